@@ -0,0 +1,91 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ai
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/zeebo/blake3"
+)
+
+// HeartbeatMinInterval is the number of blocks that must pass after a
+// credited heartbeat before the session's next heartbeat is credited
+const HeartbeatMinInterval uint64 = 10
+
+// GasHeartbeat covers the session state read and write
+const GasHeartbeat uint64 = 5000
+
+var ErrHeartbeatNonce = errors.New("heartbeat nonce must exceed the session's last nonce")
+
+// heartbeatPrefix is the storage key prefix for session heartbeat state
+var heartbeatPrefix = [4]byte{'h', 'b', 'e', 't'}
+
+// heartbeatState is a session's heartbeat record, packed into one slot
+type heartbeatState struct {
+	lastNonce   uint64 // Highest nonce accepted
+	lastCredit  uint64 // Block of the last credited heartbeat
+	creditCount uint64 // Heartbeats credited toward rewards
+}
+
+// Heartbeat records a liveness heartbeat from provider for sessionID at
+// blockNumber and reports whether it is credited toward rewards. Nonces
+// start at 1 and must strictly increase, so a duplicate or out-of-order
+// heartbeat is rejected. An accepted heartbeat is credited only if it is the
+// session's first or HeartbeatMinInterval blocks have passed since the last
+// credited one; flooding heartbeats within an interval earns nothing more.
+// Gas cost: 5,000
+func Heartbeat(stateDB StateDB, provider [20]byte, sessionID [32]byte, nonce, blockNumber uint64) (bool, error) {
+	key := makeHeartbeatKey(provider, sessionID)
+	state := unpackHeartbeatState(stateDB.GetState(precompileAddr, key))
+
+	if nonce <= state.lastNonce {
+		return false, ErrHeartbeatNonce
+	}
+	state.lastNonce = nonce
+
+	credited := state.creditCount == 0 || blockNumber >= state.lastCredit+HeartbeatMinInterval
+	if credited {
+		state.lastCredit = blockNumber
+		state.creditCount++
+	}
+
+	stateDB.SetState(precompileAddr, key, state.pack())
+	return credited, nil
+}
+
+// HeartbeatCredits returns the number of provider's heartbeats for
+// sessionID credited toward rewards
+func HeartbeatCredits(stateDB StateDB, provider [20]byte, sessionID [32]byte) uint64 {
+	return unpackHeartbeatState(stateDB.GetState(precompileAddr, makeHeartbeatKey(provider, sessionID))).creditCount
+}
+
+func (s heartbeatState) pack() [32]byte {
+	var value [32]byte
+	binary.BigEndian.PutUint64(value[8:16], s.lastNonce)
+	binary.BigEndian.PutUint64(value[16:24], s.lastCredit)
+	binary.BigEndian.PutUint64(value[24:32], s.creditCount)
+	return value
+}
+
+func unpackHeartbeatState(value [32]byte) heartbeatState {
+	return heartbeatState{
+		lastNonce:   binary.BigEndian.Uint64(value[8:16]),
+		lastCredit:  binary.BigEndian.Uint64(value[16:24]),
+		creditCount: binary.BigEndian.Uint64(value[24:32]),
+	}
+}
+
+// makeHeartbeatKey creates the storage key for a provider's session
+func makeHeartbeatKey(provider [20]byte, sessionID [32]byte) [32]byte {
+	// Key = BLAKE3(heartbeatPrefix || provider || sessionID)
+	h := blake3.New()
+	h.Write(heartbeatPrefix[:])
+	h.Write(provider[:])
+	h.Write(sessionID[:])
+
+	var key [32]byte
+	h.Digest().Read(key[:])
+	return key
+}
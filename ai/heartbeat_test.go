@@ -0,0 +1,67 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ai
+
+import "testing"
+
+func TestHeartbeatCreditsOncePerInterval(t *testing.T) {
+	stateDB := NewMockStateDB()
+	provider := [20]byte{0xaa}
+	sessionID := [32]byte{0x01}
+
+	beat := func(nonce, block uint64) bool {
+		t.Helper()
+		credited, err := Heartbeat(stateDB, provider, sessionID, nonce, block)
+		if err != nil {
+			t.Fatalf("Heartbeat(nonce %d, block %d) error: %v", nonce, block, err)
+		}
+		return credited
+	}
+
+	if !beat(1, 100) {
+		t.Error("First heartbeat should be credited")
+	}
+	// Another heartbeat in the same interval is accepted but not credited
+	if beat(2, 100+HeartbeatMinInterval-1) {
+		t.Error("Second heartbeat within the interval should not be credited")
+	}
+	if got := HeartbeatCredits(stateDB, provider, sessionID); got != 1 {
+		t.Errorf("HeartbeatCredits = %d, want 1", got)
+	}
+
+	if !beat(3, 100+HeartbeatMinInterval) {
+		t.Error("Heartbeat after the interval should be credited")
+	}
+	if got := HeartbeatCredits(stateDB, provider, sessionID); got != 2 {
+		t.Errorf("HeartbeatCredits = %d, want 2", got)
+	}
+
+	// Other sessions are tracked separately
+	if credited, err := Heartbeat(stateDB, provider, [32]byte{0x02}, 1, 101); err != nil || !credited {
+		t.Errorf("Heartbeat for another session = %v, %v; want credited", credited, err)
+	}
+}
+
+func TestHeartbeatRejectsStaleNonce(t *testing.T) {
+	stateDB := NewMockStateDB()
+	provider := [20]byte{0xaa}
+	sessionID := [32]byte{0x01}
+
+	if _, err := Heartbeat(stateDB, provider, sessionID, 0, 100); err != ErrHeartbeatNonce {
+		t.Errorf("Expected ErrHeartbeatNonce for nonce 0, got %v", err)
+	}
+	if _, err := Heartbeat(stateDB, provider, sessionID, 5, 100); err != nil {
+		t.Fatalf("Heartbeat error: %v", err)
+	}
+
+	// Duplicate and out-of-order nonces are rejected, even after the interval
+	for _, nonce := range []uint64{5, 4} {
+		if _, err := Heartbeat(stateDB, provider, sessionID, nonce, 100+HeartbeatMinInterval); err != ErrHeartbeatNonce {
+			t.Errorf("Expected ErrHeartbeatNonce for nonce %d, got %v", nonce, err)
+		}
+	}
+	if got := HeartbeatCredits(stateDB, provider, sessionID); got != 1 {
+		t.Errorf("Rejected heartbeats changed credits: %d, want 1", got)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ai
+
+import (
+	"errors"
+
+	"github.com/zeebo/blake3"
+)
+
+// Gas costs for the inference commit-reveal flow
+const (
+	GasCommitInference uint64 = 5000                // State write for the commitment
+	GasRevealInference uint64 = GasVerifyTEE + 5000 // Attestation check + state write
+)
+
+var (
+	ErrInvalidInferenceCommitment = errors.New("invalid inference commitment")
+	ErrInferenceAlreadyCommitted  = errors.New("inference already committed for session")
+	ErrInferenceNotCommitted      = errors.New("no inference commitment for session")
+	ErrInferenceRevealMismatch    = errors.New("inference reveal does not match commitment")
+)
+
+// Storage key prefixes for inference commitments and reveals
+var (
+	inferenceCommitPrefix = [4]byte{'i', 'c', 'm', 't'}
+	inferenceRevealPrefix = [4]byte{'i', 'r', 'v', 'l'}
+)
+
+// inferenceCommitDomain separates inference commitments from other BLAKE3
+// uses in this precompile
+var inferenceCommitDomain = []byte("lux/ai/inference-commit/v1")
+
+// InferenceCommitment computes the commitment a provider publishes before
+// revealing output: BLAKE3(domain || sessionID || salt || output). The salt
+// keeps low-entropy outputs from being guessed from the commitment.
+func InferenceCommitment(sessionID [32]byte, output []byte, salt [32]byte) [32]byte {
+	h := blake3.New()
+	h.Write(inferenceCommitDomain)
+	h.Write(sessionID[:])
+	h.Write(salt[:])
+	h.Write(output)
+
+	var commitment [32]byte
+	h.Digest().Read(commitment[:])
+	return commitment
+}
+
+// CommitInference records provider's commitment to the output of sessionID.
+// The provider computes the output and commits before the verifier's
+// challenge is revealed, so it cannot tailor the output to the challenge.
+// Each provider commits once per session.
+// Gas cost: 5,000
+func CommitInference(stateDB StateDB, provider [20]byte, sessionID, outputCommitment [32]byte) error {
+	if outputCommitment == ([32]byte{}) {
+		return ErrInvalidInferenceCommitment
+	}
+
+	commitKey := makeInferenceKey(inferenceCommitPrefix, provider, sessionID)
+	revealKey := makeInferenceKey(inferenceRevealPrefix, provider, sessionID)
+	if stateDB.GetState(precompileAddr, commitKey) != ([32]byte{}) ||
+		stateDB.GetState(precompileAddr, revealKey) != ([32]byte{}) {
+		return ErrInferenceAlreadyCommitted
+	}
+
+	stateDB.SetState(precompileAddr, commitKey, outputCommitment)
+	return nil
+}
+
+// RevealInference opens provider's commitment for sessionID. It fails unless
+// output and salt hash to the committed value and the TEE attestation
+// (receipt and signature, as for VerifyTEE) is valid. On success the
+// commitment is consumed and the output's BLAKE3 hash recorded, so the
+// session can be neither revealed nor committed to again.
+// Gas cost: 10,000
+func RevealInference(
+	stateDB StateDB,
+	provider [20]byte,
+	sessionID [32]byte,
+	output []byte,
+	salt [32]byte,
+	receipt, signature []byte,
+) error {
+	commitKey := makeInferenceKey(inferenceCommitPrefix, provider, sessionID)
+	commitment := stateDB.GetState(precompileAddr, commitKey)
+	if commitment == ([32]byte{}) {
+		return ErrInferenceNotCommitted
+	}
+	if InferenceCommitment(sessionID, output, salt) != commitment {
+		return ErrInferenceRevealMismatch
+	}
+
+	valid, err := VerifyTEE(receipt, signature)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrTEESignatureInvalid
+	}
+
+	stateDB.SetState(precompileAddr, commitKey, [32]byte{})
+	stateDB.SetState(precompileAddr, makeInferenceKey(inferenceRevealPrefix, provider, sessionID), blake3.Sum256(output))
+	return nil
+}
+
+// InferenceOutputHash returns the BLAKE3 hash of the output provider revealed
+// for sessionID, and false if it has not revealed one
+func InferenceOutputHash(stateDB StateDB, provider [20]byte, sessionID [32]byte) ([32]byte, bool) {
+	hash := stateDB.GetState(precompileAddr, makeInferenceKey(inferenceRevealPrefix, provider, sessionID))
+	return hash, hash != ([32]byte{})
+}
+
+// makeInferenceKey creates the storage key for a provider's session entry
+func makeInferenceKey(prefix [4]byte, provider [20]byte, sessionID [32]byte) [32]byte {
+	// Key = BLAKE3(prefix || provider || sessionID)
+	h := blake3.New()
+	h.Write(prefix[:])
+	h.Write(provider[:])
+	h.Write(sessionID[:])
+
+	var key [32]byte
+	h.Digest().Read(key[:])
+	return key
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ai
+
+import (
+	"testing"
+
+	"github.com/zeebo/blake3"
+)
+
+func TestInferenceCommitReveal(t *testing.T) {
+	stateDB := NewMockStateDB()
+
+	provider := [20]byte{0xaa}
+	sessionID := [32]byte{0x01}
+	salt := [32]byte{0x5a}
+	output := []byte("inference output")
+	receipt := make([]byte, 48)
+	signature := []byte("valid signature placeholder")
+
+	// Revealing before committing fails
+	if err := RevealInference(stateDB, provider, sessionID, output, salt, receipt, signature); err != ErrInferenceNotCommitted {
+		t.Fatalf("Expected ErrInferenceNotCommitted, got %v", err)
+	}
+
+	commitment := InferenceCommitment(sessionID, output, salt)
+	if err := CommitInference(stateDB, provider, sessionID, commitment); err != nil {
+		t.Fatalf("CommitInference error: %v", err)
+	}
+	if err := CommitInference(stateDB, provider, sessionID, commitment); err != ErrInferenceAlreadyCommitted {
+		t.Errorf("Expected ErrInferenceAlreadyCommitted, got %v", err)
+	}
+
+	// A different output, salt or provider does not open the commitment
+	if err := RevealInference(stateDB, provider, sessionID, []byte("other output"), salt, receipt, signature); err != ErrInferenceRevealMismatch {
+		t.Errorf("Expected ErrInferenceRevealMismatch for output, got %v", err)
+	}
+	if err := RevealInference(stateDB, provider, sessionID, output, [32]byte{}, receipt, signature); err != ErrInferenceRevealMismatch {
+		t.Errorf("Expected ErrInferenceRevealMismatch for salt, got %v", err)
+	}
+	if err := RevealInference(stateDB, [20]byte{0xbb}, sessionID, output, salt, receipt, signature); err != ErrInferenceNotCommitted {
+		t.Errorf("Expected ErrInferenceNotCommitted for another provider, got %v", err)
+	}
+
+	// A matching reveal with an invalid attestation fails
+	if err := RevealInference(stateDB, provider, sessionID, output, salt, receipt, nil); err != ErrTEESignatureInvalid {
+		t.Errorf("Expected ErrTEESignatureInvalid, got %v", err)
+	}
+	if _, ok := InferenceOutputHash(stateDB, provider, sessionID); ok {
+		t.Error("Failed reveals must not record an output")
+	}
+
+	if err := RevealInference(stateDB, provider, sessionID, output, salt, receipt, signature); err != nil {
+		t.Fatalf("RevealInference error: %v", err)
+	}
+	hash, ok := InferenceOutputHash(stateDB, provider, sessionID)
+	if !ok || hash != blake3.Sum256(output) {
+		t.Errorf("InferenceOutputHash = %x, %v; want hash of output", hash, ok)
+	}
+
+	// The session is closed to further reveals and commitments
+	if err := RevealInference(stateDB, provider, sessionID, output, salt, receipt, signature); err != ErrInferenceNotCommitted {
+		t.Errorf("Expected ErrInferenceNotCommitted after reveal, got %v", err)
+	}
+	if err := CommitInference(stateDB, provider, sessionID, commitment); err != ErrInferenceAlreadyCommitted {
+		t.Errorf("Expected ErrInferenceAlreadyCommitted after reveal, got %v", err)
+	}
+}
+
+func TestInferenceCommitmentBindsSession(t *testing.T) {
+	output := []byte("inference output")
+	salt := [32]byte{0x5a}
+
+	if InferenceCommitment([32]byte{0x01}, output, salt) == InferenceCommitment([32]byte{0x02}, output, salt) {
+		t.Error("Commitments for different sessions should differ")
+	}
+	if err := CommitInference(NewMockStateDB(), [20]byte{0xaa}, [32]byte{0x01}, [32]byte{}); err != ErrInvalidInferenceCommitment {
+		t.Errorf("Expected ErrInvalidInferenceCommitment, got %v", err)
+	}
+}
@@ -42,6 +42,9 @@ const (
 	SelectorIsSpent         uint32 = 0x04000000 // isSpent(bytes32)
 	SelectorMarkSpent       uint32 = 0x05000000 // markSpent(bytes32)
 	SelectorComputeWorkId   uint32 = 0x06000000 // computeWorkId(bytes32,bytes32,uint64)
+	SelectorCommitInference uint32 = 0x07000000 // commitInference(bytes32,bytes32)
+	SelectorRevealInference uint32 = 0x08000000 // revealInference(bytes32,bytes,bytes32,bytes,bytes)
+	SelectorHeartbeat       uint32 = 0x09000000 // heartbeat(bytes32,uint64)
 )
 
 type configurator struct{}
@@ -127,6 +130,12 @@ func (c *AIMiningContract) Run(
 		return c.runMarkSpent(accessibleState, data, suppliedGas, readOnly)
 	case SelectorComputeWorkId:
 		return c.runComputeWorkId(data, suppliedGas)
+	case SelectorCommitInference:
+		return c.runCommitInference(accessibleState, caller, data, suppliedGas, readOnly)
+	case SelectorRevealInference:
+		return c.runRevealInference(accessibleState, caller, data, suppliedGas, readOnly)
+	case SelectorHeartbeat:
+		return c.runHeartbeat(accessibleState, caller, data, suppliedGas, readOnly)
 	default:
 		return nil, suppliedGas, fmt.Errorf("unknown method selector: %x", selector)
 	}
@@ -322,6 +331,135 @@ func (c *AIMiningContract) runComputeWorkId(
 	return workId[:], suppliedGas - GasComputeWorkId, nil
 }
 
+func (c *AIMiningContract) runCommitInference(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	input []byte,
+	suppliedGas uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	if readOnly {
+		return nil, suppliedGas, fmt.Errorf("cannot write in read-only mode")
+	}
+
+	if suppliedGas < GasCommitInference {
+		return nil, 0, fmt.Errorf("out of gas")
+	}
+
+	if len(input) < 64 { // sessionID + commitment
+		return nil, suppliedGas - GasCommitInference, fmt.Errorf("input too short")
+	}
+
+	var sessionID, commitment [32]byte
+	copy(sessionID[:], input[:32])
+	copy(commitment[:], input[32:64])
+
+	stateDB := accessibleState.GetStateDB()
+	err := CommitInference(&stateDBAdapter{stateDB, ContractAddress}, caller, sessionID, commitment)
+	if err != nil {
+		return nil, suppliedGas - GasCommitInference, err
+	}
+
+	result := make([]byte, 32)
+	result[31] = 1
+	return result, suppliedGas - GasCommitInference, nil
+}
+
+// runRevealInference input layout:
+//
+//	[0:32]  sessionID
+//	[32:64] salt
+//	then output, TEE receipt and TEE signature, each prefixed with its
+//	length as a big-endian uint32
+func (c *AIMiningContract) runRevealInference(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	input []byte,
+	suppliedGas uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	if readOnly {
+		return nil, suppliedGas, fmt.Errorf("cannot write in read-only mode")
+	}
+
+	if suppliedGas < GasRevealInference {
+		return nil, 0, fmt.Errorf("out of gas")
+	}
+
+	if len(input) < 64 {
+		return nil, suppliedGas - GasRevealInference, fmt.Errorf("input too short")
+	}
+
+	var sessionID, salt [32]byte
+	copy(sessionID[:], input[:32])
+	copy(salt[:], input[32:64])
+
+	fields := make([][]byte, 3) // output, receipt, signature
+	offset := uint64(64)
+	for i := range fields {
+		if uint64(len(input)) < offset+4 {
+			return nil, suppliedGas - GasRevealInference, fmt.Errorf("input too short")
+		}
+		n := uint64(binary.BigEndian.Uint32(input[offset : offset+4]))
+		if uint64(len(input)) < offset+4+n {
+			return nil, suppliedGas - GasRevealInference, fmt.Errorf("invalid field length")
+		}
+		fields[i] = input[offset+4 : offset+4+n]
+		offset += 4 + n
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	err := RevealInference(&stateDBAdapter{stateDB, ContractAddress}, caller, sessionID, fields[0], salt, fields[1], fields[2])
+	if err != nil {
+		return nil, suppliedGas - GasRevealInference, err
+	}
+
+	result := make([]byte, 32)
+	result[31] = 1
+	return result, suppliedGas - GasRevealInference, nil
+}
+
+func (c *AIMiningContract) runHeartbeat(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	input []byte,
+	suppliedGas uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	if readOnly {
+		return nil, suppliedGas, fmt.Errorf("cannot write in read-only mode")
+	}
+
+	if suppliedGas < GasHeartbeat {
+		return nil, 0, fmt.Errorf("out of gas")
+	}
+
+	if len(input) < 40 { // sessionID + nonce
+		return nil, suppliedGas - GasHeartbeat, fmt.Errorf("input too short")
+	}
+
+	var sessionID [32]byte
+	copy(sessionID[:], input[:32])
+	nonce := binary.BigEndian.Uint64(input[32:40])
+
+	blockContext := accessibleState.GetBlockContext()
+	if blockContext == nil || blockContext.Number() == nil {
+		return nil, suppliedGas - GasHeartbeat, fmt.Errorf("block number unavailable")
+	}
+
+	stateDB := accessibleState.GetStateDB()
+	credited, err := Heartbeat(&stateDBAdapter{stateDB, ContractAddress}, caller, sessionID, nonce, blockContext.Number().Uint64())
+	if err != nil {
+		return nil, suppliedGas - GasHeartbeat, err
+	}
+
+	result := make([]byte, 32)
+	if credited {
+		result[31] = 1
+	}
+	return result, suppliedGas - GasHeartbeat, nil
+}
+
 // stateDBAdapter adapts contract.StateDB to ai.StateDB
 type stateDBAdapter struct {
 	stateDB contract.StateDB
@@ -356,6 +494,12 @@ func (c *AIMiningContract) RequiredGas(input []byte) uint64 {
 		return GasMarkSpent
 	case SelectorComputeWorkId:
 		return GasComputeWorkId
+	case SelectorCommitInference:
+		return GasCommitInference
+	case SelectorRevealInference:
+		return GasRevealInference
+	case SelectorHeartbeat:
+		return GasHeartbeat
 	default:
 		return GasCalculateReward
 	}
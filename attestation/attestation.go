@@ -14,9 +14,11 @@
 package attestation
 
 import (
+	"crypto/x509"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -41,15 +43,32 @@ const (
 	GasGetDeviceStatus uint64 = 5000  // Query device status
 )
 
+// NVTrust input limits. Evidence fields are attacker-controlled, so each is
+// capped, and the encoded input is capped before decoding allocates them.
+const (
+	MaxNVTrustModelLen     = 64        // GPU model name
+	MaxNVTrustVersionLen   = 64        // driver and VBIOS version strings
+	MaxNVTrustCertChainLen = 64 * 1024 // DER certificate chain
+	MaxNVTrustSPDMLen      = 64 * 1024 // SPDM measurement report
+
+	// MaxNVTrustInputSize bounds the JSON input: both byte fields at their
+	// cap, base64 encoded, plus room for the remaining fields
+	MaxNVTrustInputSize = 4*(MaxNVTrustCertChainLen+MaxNVTrustSPDMLen)/3 + 4096
+)
+
 // Errors
 var (
 	ErrInvalidInput        = errors.New("invalid input data")
 	ErrInvalidGPUEvidence  = errors.New("invalid GPU attestation evidence")
+	ErrInvalidCertChain    = errors.New("invalid GPU certificate chain")
 	ErrInvalidTPMQuote     = errors.New("invalid TPM attestation quote")
 	ErrInvalidComputeProof = errors.New("invalid compute attestation proof")
 	ErrDeviceNotAttested   = errors.New("device not attested")
 	ErrTrustScoreTooLow    = errors.New("trust score below minimum threshold")
 	ErrAttestationExpired  = errors.New("attestation has expired")
+	ErrNonceMismatch       = errors.New("attestation nonce does not match expected nonce")
+	ErrUntrustedRoot       = errors.New("GPU certificate chain does not end in a trusted root")
+	ErrPolicyNotMet        = errors.New("attestation does not meet verification policy")
 )
 
 // Global verifier instance (singleton for efficiency)
@@ -69,6 +88,7 @@ type VerifyNVTrustInput struct {
 	SPDMReport    []byte   `json:"spdm_report"`
 	CertChain     []byte   `json:"cert_chain"`
 	Nonce         [32]byte `json:"nonce"`
+	Timestamp     uint64   `json:"timestamp"` // Unix seconds the quote was taken
 }
 
 // VerifyNVTrustOutput represents output from GPU attestation verification
@@ -85,10 +105,21 @@ type VerifyNVTrustOutput struct {
 // Input: ABI-encoded VerifyNVTrustInput
 // Output: ABI-encoded VerifyNVTrustOutput
 // Gas: 50,000
+//
+// Deprecated: VerifyNVTrust applies no nonce, age, root or policy checks.
+// Use Verify with VerifyOptions.
 func VerifyNVTrust(input []byte) ([]byte, error) {
+	return verifyNVTrust(input)
+}
+
+// verifyNVTrust is the precompile entry point, which takes no options
+func verifyNVTrust(input []byte) ([]byte, error) {
 	if len(input) < 64 {
 		return nil, ErrInvalidInput
 	}
+	if len(input) > MaxNVTrustInputSize {
+		return nil, fmt.Errorf("%w: %d-byte input exceeds %d", ErrInvalidInput, len(input), MaxNVTrustInputSize)
+	}
 
 	// Decode input
 	var vi VerifyNVTrustInput
@@ -96,43 +127,201 @@ func VerifyNVTrust(input []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	result, err := Verify(&vi, VerifyOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return encodeOutput(result)
+}
+
+// VerifyPolicy is the minimum a verified GPU must meet
+type VerifyPolicy struct {
+	MinTrustScore     uint8
+	RequireHardwareCC bool
+	RequireRIM        bool
+}
+
+// VerifyOptions are the checks Verify applies on top of evidence
+// verification. Zero-valued fields are not checked.
+type VerifyOptions struct {
+	// ExpectedNonce is the nonce the relying party issued for this quote
+	ExpectedNonce *[32]byte
+	// MaxAge bounds how long before now the quote's Timestamp may be
+	MaxAge time.Duration
+	// TrustedRoots, if set, must contain the last certificate of the
+	// quote's CertChain, and each certificate must be signed by the next
+	TrustedRoots []*x509.Certificate
+	// Policy is checked against the verification result
+	Policy *VerifyPolicy
+}
+
+// Verify checks a GPU attestation quote against opts and verifies its
+// evidence. Evidence that fails verification yields an unverified result
+// rather than an error; a failed option check is an error. The nonce, age
+// and root checks run before the evidence is verified.
+func Verify(q *VerifyNVTrustInput, opts VerifyOptions) (*VerifyNVTrustOutput, error) {
+	if err := q.validateSizes(); err != nil {
+		return nil, err
+	}
+	if opts.ExpectedNonce != nil && q.Nonce != *opts.ExpectedNonce {
+		return nil, ErrNonceMismatch
+	}
+	if opts.MaxAge > 0 {
+		if q.Timestamp == 0 {
+			return nil, fmt.Errorf("%w: quote has no timestamp", ErrAttestationExpired)
+		}
+		if age := time.Since(time.Unix(int64(q.Timestamp), 0)); age > opts.MaxAge {
+			return nil, fmt.Errorf("%w: %s old, max %s", ErrAttestationExpired, age.Truncate(time.Second), opts.MaxAge)
+		}
+	}
+	if len(opts.TrustedRoots) > 0 {
+		if err := verifyCertChain(q.CertChain, opts.TrustedRoots); err != nil {
+			return nil, err
+		}
+	}
+
 	// Build GPU attestation from input
 	gpuAtt := &attestation.GPUAttestation{
-		DeviceID:      string(vi.DeviceID[:]),
-		Model:         vi.Model,
-		CCEnabled:     vi.CCEnabled,
-		TEEIOEnabled:  vi.TEEIOEnabled,
-		DriverVersion: vi.DriverVersion,
-		VBIOSVersion:  vi.VBIOSVersion,
+		DeviceID:      string(q.DeviceID[:]),
+		Model:         q.Model,
+		CCEnabled:     q.CCEnabled,
+		TEEIOEnabled:  q.TEEIOEnabled,
+		DriverVersion: q.DriverVersion,
+		VBIOSVersion:  q.VBIOSVersion,
 		Timestamp:     time.Now(),
 		Mode:          attestation.ModeLocal,
 		LocalEvidence: &attestation.LocalGPUEvidence{
-			SPDMReport:  vi.SPDMReport,
-			CertChain:   vi.CertChain,
+			SPDMReport:  q.SPDMReport,
+			CertChain:   q.CertChain,
 			RIMVerified: false, // Will be set by verifier
-			Nonce:       vi.Nonce,
+			Nonce:       q.Nonce,
 		},
 	}
 
 	// Verify using real attestation implementation
-	status, err := globalVerifier.VerifyGPUAttestation(gpuAtt)
+	result := &VerifyNVTrustOutput{Mode: uint8(attestation.ModeLocal)}
+	if status, err := globalVerifier.VerifyGPUAttestation(gpuAtt); err == nil {
+		result = &VerifyNVTrustOutput{
+			Verified:    status.Attested,
+			TrustScore:  status.TrustScore,
+			HardwareCC:  status.HardwareCC,
+			RIMVerified: gpuAtt.LocalEvidence.RIMVerified,
+			Mode:        uint8(status.Mode),
+		}
+	}
+
+	if opts.Policy != nil {
+		if err := opts.Policy.check(result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// check returns an error if result falls short of the policy
+func (p *VerifyPolicy) check(result *VerifyNVTrustOutput) error {
+	switch {
+	case !result.Verified:
+		return ErrDeviceNotAttested
+	case result.TrustScore < p.MinTrustScore:
+		return fmt.Errorf("%w: %d < %d", ErrTrustScoreTooLow, result.TrustScore, p.MinTrustScore)
+	case p.RequireHardwareCC && !result.HardwareCC:
+		return fmt.Errorf("%w: hardware confidential computing required", ErrPolicyNotMet)
+	case p.RequireRIM && !result.RIMVerified:
+		return fmt.Errorf("%w: RIM verification required", ErrPolicyNotMet)
+	}
+	return nil
+}
+
+// verifyCertChain checks that the DER chain ends in one of roots and that
+// each certificate is signed by the next
+func verifyCertChain(chain []byte, roots []*x509.Certificate) error {
+	certs, err := x509.ParseCertificates(chain)
 	if err != nil {
-		// Return failure result instead of error for verification failures
-		return encodeOutput(&VerifyNVTrustOutput{
-			Verified:   false,
-			TrustScore: 0,
-			HardwareCC: false,
-			Mode:       uint8(attestation.ModeLocal),
-		})
+		return fmt.Errorf("%w: %v", ErrInvalidCertChain, err)
+	}
+	if len(certs) == 0 {
+		return fmt.Errorf("%w: empty chain", ErrInvalidCertChain)
 	}
+	for i := 0; i+1 < len(certs); i++ {
+		if err := certs[i].CheckSignatureFrom(certs[i+1]); err != nil {
+			return fmt.Errorf("%w: certificate %d: %v", ErrInvalidCertChain, i, err)
+		}
+	}
+	root := certs[len(certs)-1]
+	for _, trusted := range roots {
+		if root.Equal(trusted) {
+			return nil
+		}
+	}
+	return ErrUntrustedRoot
+}
 
-	return encodeOutput(&VerifyNVTrustOutput{
-		Verified:    status.Attested,
-		TrustScore:  status.TrustScore,
-		HardwareCC:  status.HardwareCC,
-		RIMVerified: gpuAtt.LocalEvidence.RIMVerified,
-		Mode:        uint8(status.Mode),
-	})
+// validateSizes checks each variable-length field against its cap
+func (vi *VerifyNVTrustInput) validateSizes() error {
+	switch {
+	case len(vi.CertChain) > MaxNVTrustCertChainLen:
+		return fmt.Errorf("%w: %d bytes exceeds %d", ErrInvalidCertChain, len(vi.CertChain), MaxNVTrustCertChainLen)
+	case len(vi.SPDMReport) > MaxNVTrustSPDMLen:
+		return fmt.Errorf("%w: %d-byte SPDM report exceeds %d", ErrInvalidGPUEvidence, len(vi.SPDMReport), MaxNVTrustSPDMLen)
+	case len(vi.Model) > MaxNVTrustModelLen:
+		return fmt.Errorf("%w: %d-byte model exceeds %d", ErrInvalidGPUEvidence, len(vi.Model), MaxNVTrustModelLen)
+	case len(vi.DriverVersion) > MaxNVTrustVersionLen:
+		return fmt.Errorf("%w: %d-byte driver version exceeds %d", ErrInvalidGPUEvidence, len(vi.DriverVersion), MaxNVTrustVersionLen)
+	case len(vi.VBIOSVersion) > MaxNVTrustVersionLen:
+		return fmt.Errorf("%w: %d-byte VBIOS version exceeds %d", ErrInvalidGPUEvidence, len(vi.VBIOSVersion), MaxNVTrustVersionLen)
+	}
+	return nil
+}
+
+// Packed NVTrust result layout, from the low-order end of the word:
+//
+//	byte 31: flags (bit 0 = Verified, bit 1 = HardwareCC, bit 2 = RIMVerified)
+//	byte 30: TrustScore
+//	byte 29: Mode
+//
+// Bytes 0-28 are zero. The layout is stored on-chain and must not change.
+const (
+	resultFlagsByte = 31
+	resultScoreByte = 30
+	resultModeByte  = 29
+
+	resultFlagVerified    = 1 << 0
+	resultFlagHardwareCC  = 1 << 1
+	resultFlagRIMVerified = 1 << 2
+)
+
+// SerializeResult packs a GPU attestation result into a single storage word
+// so precompiles can record attestation state in one slot
+func SerializeResult(result *VerifyNVTrustOutput) [32]byte {
+	var word [32]byte
+	if result == nil {
+		return word
+	}
+	if result.Verified {
+		word[resultFlagsByte] |= resultFlagVerified
+	}
+	if result.HardwareCC {
+		word[resultFlagsByte] |= resultFlagHardwareCC
+	}
+	if result.RIMVerified {
+		word[resultFlagsByte] |= resultFlagRIMVerified
+	}
+	word[resultScoreByte] = result.TrustScore
+	word[resultModeByte] = result.Mode
+	return word
+}
+
+// DeserializeResult unpacks a word written by SerializeResult
+func DeserializeResult(word [32]byte) *VerifyNVTrustOutput {
+	flags := word[resultFlagsByte]
+	return &VerifyNVTrustOutput{
+		Verified:    flags&resultFlagVerified != 0,
+		TrustScore:  word[resultScoreByte],
+		HardwareCC:  flags&resultFlagHardwareCC != 0,
+		RIMVerified: flags&resultFlagRIMVerified != 0,
+		Mode:        word[resultModeByte],
+	}
 }
 
 // VerifyTPMInput represents input for TPM attestation verification
@@ -528,7 +717,7 @@ func Run(input []byte) ([]byte, error) {
 
 	switch selector {
 	case [4]byte{0x01, 0x00, 0x00, 0x00}:
-		return VerifyNVTrust(data)
+		return verifyNVTrust(data)
 	case [4]byte{0x02, 0x00, 0x00, 0x00}:
 		return VerifyTPM(data)
 	case [4]byte{0x03, 0x00, 0x00, 0x00}:
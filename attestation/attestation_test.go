@@ -4,7 +4,17 @@
 package attestation
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"errors"
+	"math/big"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -52,6 +62,48 @@ func TestVerifyNVTrust_ValidH100(t *testing.T) {
 	}
 }
 
+func TestSerializeResult_RoundTrip(t *testing.T) {
+	results := []VerifyNVTrustOutput{
+		{},
+		{Verified: true, TrustScore: 95, HardwareCC: true, RIMVerified: true, Mode: 0},
+		{Verified: true, TrustScore: 60, HardwareCC: false, RIMVerified: false, Mode: 1},
+		{Verified: false, TrustScore: 255, HardwareCC: true, RIMVerified: false, Mode: 255},
+	}
+	for _, want := range results {
+		got := DeserializeResult(SerializeResult(&want))
+		if *got != want {
+			t.Errorf("round trip: got %+v, want %+v", *got, want)
+		}
+	}
+
+	if SerializeResult(nil) != ([32]byte{}) {
+		t.Error("expected nil result to serialize to zero word")
+	}
+}
+
+func TestSerializeResult_Layout(t *testing.T) {
+	// The packed layout is stored on-chain; changing it breaks existing state
+	word := SerializeResult(&VerifyNVTrustOutput{
+		Verified:    true,
+		TrustScore:  0x5A,
+		HardwareCC:  false,
+		RIMVerified: true,
+		Mode:        1,
+	})
+	var want [32]byte
+	want[29] = 0x01 // Mode
+	want[30] = 0x5A // TrustScore
+	want[31] = 0x05 // Verified | RIMVerified
+	if word != want {
+		t.Errorf("layout changed: got %x, want %x", word, want)
+	}
+
+	word = SerializeResult(&VerifyNVTrustOutput{HardwareCC: true})
+	if word[31] != 0x02 {
+		t.Errorf("HardwareCC flag: got %#x, want 0x02", word[31])
+	}
+}
+
 func TestVerifyNVTrust_NonCCGPU(t *testing.T) {
 	// Create attestation for non-CC GPU (RTX 5090)
 	input := VerifyNVTrustInput{
@@ -87,6 +139,179 @@ func TestVerifyNVTrust_NonCCGPU(t *testing.T) {
 	}
 }
 
+func TestVerifyNVTrust_SizeLimits(t *testing.T) {
+	valid := func() VerifyNVTrustInput {
+		return VerifyNVTrustInput{
+			DeviceID:      [32]byte{0x01},
+			Model:         "H100",
+			CCEnabled:     true,
+			DriverVersion: "535.104.05",
+			VBIOSVersion:  "96.00.89.00.01",
+			SPDMReport:    make([]byte, 512),
+			CertChain:     make([]byte, 1024),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*VerifyNVTrustInput)
+		err    error
+	}{
+		{"cert chain at cap", func(vi *VerifyNVTrustInput) { vi.CertChain = make([]byte, MaxNVTrustCertChainLen) }, nil},
+		{"cert chain over cap", func(vi *VerifyNVTrustInput) { vi.CertChain = make([]byte, MaxNVTrustCertChainLen+1) }, ErrInvalidCertChain},
+		{"SPDM report over cap", func(vi *VerifyNVTrustInput) { vi.SPDMReport = make([]byte, MaxNVTrustSPDMLen+1) }, ErrInvalidGPUEvidence},
+		{"model over cap", func(vi *VerifyNVTrustInput) { vi.Model = strings.Repeat("H", MaxNVTrustModelLen+1) }, ErrInvalidGPUEvidence},
+		{"driver version over cap", func(vi *VerifyNVTrustInput) { vi.DriverVersion = strings.Repeat("5", MaxNVTrustVersionLen+1) }, ErrInvalidGPUEvidence},
+		{"VBIOS version over cap", func(vi *VerifyNVTrustInput) { vi.VBIOSVersion = strings.Repeat("9", MaxNVTrustVersionLen+1) }, ErrInvalidGPUEvidence},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid()
+			tt.modify(&input)
+			data, err := json.Marshal(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(data) > MaxNVTrustInputSize {
+				t.Fatalf("%d-byte input exceeds MaxNVTrustInputSize", len(data))
+			}
+
+			_, err = VerifyNVTrust(data)
+			if tt.err == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestVerifyNVTrust_HugeCertChainRejectedBeforeAllocation(t *testing.T) {
+	// A cert chain claiming 16 MiB is rejected on input size alone, before
+	// decoding allocates it
+	const huge = 16 << 20
+	var buf bytes.Buffer
+	buf.WriteString(`{"model":"H100","cert_chain":"`)
+	buf.Write(bytes.Repeat([]byte("A"), huge))
+	buf.WriteString(`"}`)
+	data := buf.Bytes()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	_, err := VerifyNVTrust(data)
+	runtime.ReadMemStats(&after)
+
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64*1024 {
+		t.Fatalf("rejecting a %d-byte input allocated %d bytes", len(data), allocated)
+	}
+}
+
+// testCertChain returns a DER chain of a leaf signed by a fresh root, and
+// the root
+func testCertChain(t *testing.T) ([]byte, *x509.Certificate) {
+	t.Helper()
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := x509.ParseCertificate(rootDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test GPU"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, root, &leafKey.PublicKey, rootKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(leafDER, rootDER...), root
+}
+
+func TestVerify_Options(t *testing.T) {
+	chain, root := testCertChain(t)
+	_, otherRoot := testCertChain(t)
+	nonce := [32]byte{0xAA, 0xBB, 0xCC}
+	otherNonce := [32]byte{0x01}
+	withChain := func(q *VerifyNVTrustInput) { q.CertChain = chain }
+
+	quote := func() *VerifyNVTrustInput {
+		return &VerifyNVTrustInput{
+			DeviceID:      [32]byte{0x07},
+			Model:         "H100",
+			CCEnabled:     true,
+			TEEIOEnabled:  true,
+			DriverVersion: "535.104.05",
+			VBIOSVersion:  "96.00.89.00.01",
+			SPDMReport:    make([]byte, 512),
+			CertChain:     make([]byte, 1024),
+			Nonce:         nonce,
+			Timestamp:     uint64(time.Now().Unix()),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*VerifyNVTrustInput)
+		opts   VerifyOptions
+		err    error
+	}{
+		{"no options", nil, VerifyOptions{}, nil},
+		{"nonce matches", nil, VerifyOptions{ExpectedNonce: &nonce}, nil},
+		{"nonce mismatch", nil, VerifyOptions{ExpectedNonce: &otherNonce}, ErrNonceMismatch},
+		{"fresh", nil, VerifyOptions{MaxAge: time.Hour}, nil},
+		{"too old", func(q *VerifyNVTrustInput) { q.Timestamp = uint64(time.Now().Add(-2 * time.Hour).Unix()) }, VerifyOptions{MaxAge: time.Hour}, ErrAttestationExpired},
+		{"no timestamp", func(q *VerifyNVTrustInput) { q.Timestamp = 0 }, VerifyOptions{MaxAge: time.Hour}, ErrAttestationExpired},
+		{"trusted root", withChain, VerifyOptions{TrustedRoots: []*x509.Certificate{otherRoot, root}}, nil},
+		{"untrusted root", withChain, VerifyOptions{TrustedRoots: []*x509.Certificate{otherRoot}}, ErrUntrustedRoot},
+		{"unparseable chain", nil, VerifyOptions{TrustedRoots: []*x509.Certificate{root}}, ErrInvalidCertChain},
+		{"policy met", nil, VerifyOptions{Policy: &VerifyPolicy{MinTrustScore: 70}}, nil},
+		{"policy below threshold", nil, VerifyOptions{Policy: &VerifyPolicy{MinTrustScore: 101}}, ErrTrustScoreTooLow},
+		{"policy on unverified device", func(q *VerifyNVTrustInput) { q.Model = "RTX 5090"; q.CCEnabled = false }, VerifyOptions{Policy: &VerifyPolicy{}}, ErrDeviceNotAttested},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := quote()
+			if tt.modify != nil {
+				tt.modify(q)
+			}
+			_, err := Verify(q, tt.opts)
+			if tt.err == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
 func TestVerifyTPM_SGX(t *testing.T) {
 	// Create valid SGX quote (minimum size 432 bytes)
 	quote := make([]byte, 512)
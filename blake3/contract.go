@@ -51,6 +51,7 @@ const (
 	DigestLength32  = 32          // Standard hash length
 	DigestLength64  = 64          // Extended hash length
 	MaxMerkleLeaves = 1024        // Maximum Merkle tree leaves
+	BlockSize       = 64          // Blake3 compression block size
 )
 
 // Operation selectors (first byte of input)
@@ -68,7 +69,7 @@ const (
 	GasBase256       = 100 // Base cost for 32-byte hash
 	GasBase512       = 150 // Base cost for 64-byte hash
 	GasBaseXOF       = 200 // Base cost for XOF
-	GasPerInputWord  = 3   // Per 32-byte input word
+	GasPerInputBlock = 6   // Per 64-byte input block compressed
 	GasPerOutputWord = 5   // Per 32-byte output word (XOF)
 	GasDomainSetup   = 50  // Domain separator setup
 	GasMerkleBase    = 500 // Merkle tree base cost
@@ -89,16 +90,18 @@ func (p *blake3Precompile) RequiredGas(input []byte) uint64 {
 		return 0
 	}
 
+	// Blake3 compresses its input in 64-byte blocks, so input cost scales
+	// with the number of blocks
 	op := input[0]
 	dataLen := uint64(len(input) - 1)
-	inputWords := (dataLen + 31) / 32
+	inputBlocks := (dataLen + BlockSize - 1) / BlockSize
 
 	switch op {
 	case OpHash256:
-		return GasBase256 + inputWords*GasPerInputWord
+		return GasBase256 + inputBlocks*GasPerInputBlock
 
 	case OpHash512:
-		return GasBase512 + inputWords*GasPerInputWord
+		return GasBase512 + inputBlocks*GasPerInputBlock
 
 	case OpHashXOF:
 		if len(input) < 5 {
@@ -106,10 +109,10 @@ func (p *blake3Precompile) RequiredGas(input []byte) uint64 {
 		}
 		outputLen := binary.BigEndian.Uint32(input[1:5])
 		outputWords := (uint64(outputLen) + 31) / 32
-		return GasBaseXOF + inputWords*GasPerInputWord + outputWords*GasPerOutputWord
+		return GasBaseXOF + inputBlocks*GasPerInputBlock + outputWords*GasPerOutputWord
 
 	case OpHashWithDomain:
-		return GasDomainSetup + GasBase256 + inputWords*GasPerInputWord
+		return GasDomainSetup + GasBase256 + inputBlocks*GasPerInputBlock
 
 	case OpMerkleRoot:
 		if len(input) < 5 {
@@ -119,7 +122,7 @@ func (p *blake3Precompile) RequiredGas(input []byte) uint64 {
 		return GasMerkleBase + uint64(numLeaves)*GasMerklePerLeaf
 
 	case OpDeriveKey:
-		return GasDeriveKey + inputWords*GasPerInputWord
+		return GasDeriveKey + inputBlocks*GasPerInputBlock
 
 	default:
 		return 0
@@ -157,32 +160,41 @@ func (p *blake3Precompile) Run(
 		return p.hash512(data), remainingGas, nil
 
 	case OpHashXOF:
-		return p.hashXOF(data)
+		ret, _, err = p.hashXOF(data)
+		return ret, remainingGas, err
 
 	case OpHashWithDomain:
-		return p.hashWithDomain(data)
+		ret, _, err = p.hashWithDomain(data)
+		return ret, remainingGas, err
 
 	case OpMerkleRoot:
-		return p.merkleRoot(data)
+		ret, _, err = p.merkleRoot(data)
+		return ret, remainingGas, err
 
 	case OpDeriveKey:
-		return p.deriveKey(data)
+		ret, _, err = p.deriveKey(data)
+		return ret, remainingGas, err
 
 	default:
 		return nil, remainingGas, ErrInvalidOperation
 	}
 }
 
+// sum hashes data into an output of outLen bytes with the Blake3 library
+func sum(data []byte, outLen int) []byte {
+	h := blake3.New()
+	h.Write(data)
+	result := make([]byte, outLen)
+	h.Reader().Read(result)
+	return result
+}
+
 // hash256 computes a 32-byte Blake3 hash
 func (p *blake3Precompile) hash256(data []byte) []byte {
 	if len(data) > MaxInputLength {
 		data = data[:MaxInputLength]
 	}
-	h := blake3.New()
-	h.Write(data)
-	result := make([]byte, DigestLength32)
-	h.Reader().Read(result)
-	return result
+	return sum(data, DigestLength32)
 }
 
 // hash512 computes a 64-byte Blake3 hash
@@ -190,8 +202,7 @@ func (p *blake3Precompile) hash512(data []byte) []byte {
 	if len(data) > MaxInputLength {
 		data = data[:MaxInputLength]
 	}
-	digest := blake3.HashBytes(data)
-	return digest[:]
+	return sum(data, DigestLength64)
 }
 
 // hashXOF computes an arbitrary-length hash using XOF mode
@@ -211,11 +222,7 @@ func (p *blake3Precompile) hashXOF(data []byte) ([]byte, uint64, error) {
 		inputData = inputData[:MaxInputLength]
 	}
 
-	h := blake3.New()
-	h.Write(inputData)
-	result := make([]byte, outputLen)
-	h.Reader().Read(result)
-	return result, 0, nil
+	return sum(inputData, int(outputLen)), 0, nil
 }
 
 // hashWithDomain computes a domain-separated hash
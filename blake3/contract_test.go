@@ -10,6 +10,7 @@ import (
 
 	"github.com/luxfi/geth/common"
 	"github.com/stretchr/testify/require"
+	zeebo "github.com/zeebo/blake3"
 )
 
 // mockAccessibleState implements the minimal interface for testing
@@ -272,8 +273,9 @@ func TestRequiredGas(t *testing.T) {
 		expected uint64
 	}{
 		{"hash256_empty", []byte{OpHash256}, GasBase256},
-		{"hash256_32b", append([]byte{OpHash256}, make([]byte, 32)...), GasBase256 + GasPerInputWord},
-		{"hash256_64b", append([]byte{OpHash256}, make([]byte, 64)...), GasBase256 + 2*GasPerInputWord},
+		{"hash256_32b", append([]byte{OpHash256}, make([]byte, 32)...), GasBase256 + GasPerInputBlock},
+		{"hash256_64b", append([]byte{OpHash256}, make([]byte, 64)...), GasBase256 + GasPerInputBlock},
+		{"hash256_65b", append([]byte{OpHash256}, make([]byte, 65)...), GasBase256 + 2*GasPerInputBlock},
 		{"hash512_empty", []byte{OpHash512}, GasBase512},
 		{"merkle_4leaves", func() []byte {
 			data := make([]byte, 5)
@@ -292,6 +294,66 @@ func TestRequiredGas(t *testing.T) {
 	}
 }
 
+func TestRequiredGasScalesWithBlocks(t *testing.T) {
+	small := append([]byte{OpHash256}, make([]byte, BlockSize)...)
+	large := append([]byte{OpHash256}, make([]byte, 1024)...)
+
+	smallGas := Blake3Precompile.RequiredGas(small)
+	largeGas := Blake3Precompile.RequiredGas(large)
+	require.Equal(t, uint64(GasBase256+GasPerInputBlock), smallGas)
+	require.Equal(t, uint64(GasBase256+16*GasPerInputBlock), largeGas)
+	require.Equal(t, 15*uint64(GasPerInputBlock), largeGas-smallGas)
+}
+
+func TestRunMatchesReference(t *testing.T) {
+	for _, size := range []int{0, 1, 64, 1024, 4097} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		input := append([]byte{OpHash256}, data...)
+		gas := Blake3Precompile.RequiredGas(input)
+
+		ret, remainingGas, err := Blake3Precompile.Run(nil, common.Address{}, ContractAddress, input, gas+10, true)
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), remainingGas)
+		expected := zeebo.Sum256(data)
+		require.Equal(t, expected[:], ret, "size %d", size)
+	}
+
+	// Operations with a sub-format also return the unused gas
+	input := []byte{OpDeriveKey, 1, 'c'}
+	input = append(input, make([]byte, 32)...)
+	gas := Blake3Precompile.RequiredGas(input)
+	_, remainingGas, err := Blake3Precompile.Run(nil, common.Address{}, ContractAddress, input, gas+10, true)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), remainingGas)
+}
+
+func TestSumMatchesReference(t *testing.T) {
+	// The input lengths of the official Blake3 test vectors, then a
+	// multi-megabyte input with an uneven chunk count
+	sizes := []int{
+		0, 1, BlockSize, 1023, 1024, 1025, 2048, 2049, 3072, 3073, 4096, 4097,
+		5120, 5121, 6144, 6145, 7168, 7169, 8192, 8193, 16384, 31744, 102400,
+		5*1024*1024 + 123,
+	}
+	for _, size := range sizes {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i % 251)
+		}
+
+		for _, outLen := range []int{DigestLength32, DigestLength64, 200} {
+			expected := make([]byte, outLen)
+			h := zeebo.New()
+			h.Write(data)
+			h.Digest().Read(expected)
+			require.Equal(t, expected, sum(data, outLen), "size %d, output %d", size, outLen)
+		}
+	}
+}
+
 func TestRunInvalidOperation(t *testing.T) {
 	p := &blake3Precompile{}
 
@@ -352,3 +414,17 @@ func BenchmarkMerkleRoot256Leaves(b *testing.B) {
 		p.merkleRoot(data)
 	}
 }
+
+func BenchmarkHash4MB(b *testing.B) {
+	p := &blake3Precompile{}
+	data := make([]byte, 4*1024*1024)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	b.SetBytes(int64(len(data)))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.hash256(data)
+	}
+}
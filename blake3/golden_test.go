@@ -0,0 +1,101 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blake3
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// Run with -update after an intentional change to an output; the diff of
+// the golden file is then the record of what changed on-chain
+var updateGolden = flag.Bool("update", false, "rewrite testdata golden vectors")
+
+const goldenFile = "golden_vectors.json"
+
+// goldenData returns n bytes of the repeating pattern 0, 1, ..., 250
+func goldenData(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+// computeGoldenVectors runs every operation through Run on fixed inputs
+func computeGoldenVectors(t *testing.T) map[string]string {
+	t.Helper()
+	require := require.New(t)
+	vectors := make(map[string]string)
+	put := func(name string, op byte, data []byte) {
+		input := append([]byte{op}, data...)
+		ret, _, err := Blake3Precompile.Run(nil, common.Address{}, ContractAddress, input, Blake3Precompile.RequiredGas(input), true)
+		require.NoError(err, name)
+		vectors[name] = hex.EncodeToString(ret)
+	}
+
+	// Sizes straddle the block and chunk boundaries, up to a 256-chunk tree
+	for _, n := range []int{0, 3, BlockSize, 1024, 1025, 8*1024 + 7, 256*1024 + 5} {
+		put("hash256/"+strconv.Itoa(n), OpHash256, goldenData(n))
+	}
+	put("hash256/abc", OpHash256, []byte("abc"))
+	put("hash512/abc", OpHash512, []byte("abc"))
+
+	xof := binary.BigEndian.AppendUint32(nil, 100)
+	put("xof/100/abc", OpHashXOF, append(xof, "abc"...))
+
+	domain := append([]byte{3}, "lux"...)
+	put("domain/lux/abc", OpHashWithDomain, append(domain, "abc"...))
+
+	for _, n := range []int{1, 2, 5, 8} {
+		leaves := binary.BigEndian.AppendUint32(nil, uint32(n))
+		for i := 0; i < n; i++ {
+			leaves = append(leaves, sum([]byte{byte(i)}, DigestLength32)...)
+		}
+		put("merkle_root/"+strconv.Itoa(n), OpMerkleRoot, leaves)
+	}
+
+	derive := append([]byte{3}, "lux"...)
+	put("derive_key/lux", OpDeriveKey, append(derive, goldenData(32)...))
+
+	return vectors
+}
+
+// TestGoldenVectors pins the output of every operation, so an
+// implementation change that alters on-chain values fails here
+func TestGoldenVectors(t *testing.T) {
+	require := require.New(t)
+	got := computeGoldenVectors(t)
+	path := filepath.Join("testdata", goldenFile)
+
+	if *updateGolden {
+		data, err := json.MarshalIndent(got, "", "  ")
+		require.NoError(err)
+		require.NoError(os.MkdirAll("testdata", 0o755))
+		require.NoError(os.WriteFile(path, append(data, '\n'), 0o644))
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(err, "run go test -run TestGoldenVectors -update to create it")
+	var want map[string]string
+	require.NoError(json.Unmarshal(data, &want))
+
+	for name, w := range want {
+		require.Contains(got, name, "golden vector no longer computed")
+		require.Equal(w, got[name], "%s drifted from its golden value", name)
+	}
+	for name := range got {
+		require.Contains(want, name, "vector missing from %s", path)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blake3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/luxfi/precompile/registry"
+)
+
+// Known answers from the Blake3 reference implementation
+var selfTestVectors = []struct {
+	input  string
+	digest string
+}{
+	{"", "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+	{"abc", "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85"},
+}
+
+func init() {
+	if err := registry.RegisterSelfTest("BLAKE3", SelfTest); err != nil {
+		panic(err)
+	}
+}
+
+// SelfTest hashes fixed inputs and fails if the digests differ from the
+// reference implementation's
+func SelfTest() error {
+	return selfTest(sum)
+}
+
+func selfTest(hash func(data []byte, outLen int) []byte) error {
+	for _, v := range selfTestVectors {
+		want, _ := hex.DecodeString(v.digest)
+		if got := hash([]byte(v.input), DigestLength32); !bytes.Equal(got, want) {
+			return fmt.Errorf("blake3(%q) = %x, want %s", v.input, got, v.digest)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package blake3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, SelfTest())
+}
+
+func TestSelfTestDetectsBrokenHash(t *testing.T) {
+	// A hash that drops the last input byte still yields 32 bytes
+	truncating := func(data []byte, outLen int) []byte {
+		if len(data) > 0 {
+			data = data[:len(data)-1]
+		}
+		return sum(data, outLen)
+	}
+	require.ErrorContains(t, selfTest(truncating), `blake3("abc")`)
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package capability implements a precompile that reports whether another
+// precompile is enabled on the current chain at the current block, so
+// contracts can degrade gracefully when an optional precompile (e.g. FHE) is
+// not available. Address: 0x8001 (Lux Core System range)
+package capability
+
+import (
+	"errors"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/modules"
+)
+
+var (
+	// ContractAddress is the address of the capability precompile (Lux Core System range 0x8001)
+	ContractAddress = common.HexToAddress("0x8001")
+
+	// Singleton instance
+	CapabilityPrecompile = &capabilityPrecompile{}
+
+	_ contract.StatefulPrecompiledContract = &capabilityPrecompile{}
+
+	ErrInvalidInput = errors.New("invalid capability input")
+)
+
+// GasIsEnabled is the flat cost of a capability query
+const GasIsEnabled uint64 = 500
+
+type capabilityPrecompile struct{}
+
+// Address returns the address of the capability precompile
+func (p *capabilityPrecompile) Address() common.Address {
+	return ContractAddress
+}
+
+// RequiredGas returns the gas for a capability query
+func (p *capabilityPrecompile) RequiredGas(input []byte) uint64 {
+	return GasIsEnabled
+}
+
+// Run reports whether the target precompile is enabled.
+// Input: [target(20)] or an ABI-encoded address [zeros(12)] [target(20)]
+// Output: 32-byte word, 1 if enabled and 0 otherwise
+func (p *capabilityPrecompile) Run(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	addr common.Address,
+	input []byte,
+	suppliedGas uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	remainingGas, err := contract.DeductGas(suppliedGas, GasIsEnabled)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	target, err := parseTarget(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	ret := make([]byte, common.HashLength)
+	if IsEnabled(accessibleState, target) {
+		ret[common.HashLength-1] = 1
+	}
+	return ret, remainingGas, nil
+}
+
+// IsEnabled reports whether the precompile at target is active under the
+// chain config of accessibleState at its current block timestamp. Chain
+// configs that do not report their active precompiles enable nothing.
+func IsEnabled(accessibleState contract.AccessibleState, target common.Address) bool {
+	if accessibleState == nil {
+		return false
+	}
+	blockContext := accessibleState.GetBlockContext()
+	if blockContext == nil {
+		return false
+	}
+	return modules.IsActive(accessibleState.GetChainConfig(), target, blockContext.Timestamp())
+}
+
+// parseTarget reads a raw or ABI-encoded address
+func parseTarget(input []byte) (common.Address, error) {
+	switch len(input) {
+	case common.AddressLength:
+		return common.BytesToAddress(input), nil
+	case common.HashLength:
+		for _, b := range input[:common.HashLength-common.AddressLength] {
+			if b != 0 {
+				return common.Address{}, ErrInvalidInput
+			}
+		}
+		return common.BytesToAddress(input), nil
+	default:
+		return common.Address{}, ErrInvalidInput
+	}
+}
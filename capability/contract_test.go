@@ -0,0 +1,111 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package capability
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/precompileconfig"
+	"github.com/stretchr/testify/require"
+)
+
+type blockContext struct{ timestamp uint64 }
+
+func (b blockContext) Number() *big.Int                                       { return nil }
+func (b blockContext) Timestamp() uint64                                      { return b.timestamp }
+func (b blockContext) GetPredicateResults(common.Hash, common.Address) []byte { return nil }
+
+// fheAddress is where the FHE module is deployed (Lux Privacy range 0x0700)
+var fheAddress = common.HexToAddress("0x0700000000000000000000000000000000000000")
+
+// chainConfig maps active precompiles to their activation timestamps
+type chainConfig map[common.Address]uint64
+
+func (c chainConfig) IsDurango(uint64) bool { return true }
+func (c chainConfig) IsPrecompileEnabled(address common.Address, time uint64) bool {
+	activation, ok := c[address]
+	return ok && time >= activation
+}
+
+// legacyChainConfig does not report its active precompiles
+type legacyChainConfig struct{}
+
+func (legacyChainConfig) IsDurango(uint64) bool { return true }
+
+type chainState struct {
+	config    precompileconfig.ChainConfig
+	timestamp uint64
+}
+
+func (s *chainState) GetStateDB() contract.StateDB                     { return nil }
+func (s *chainState) GetBlockContext() contract.BlockContext           { return blockContext{s.timestamp} }
+func (s *chainState) GetConsensusContext() context.Context             { return context.Background() }
+func (s *chainState) GetChainConfig() precompileconfig.ChainConfig     { return s.config }
+func (s *chainState) GetPrecompileEnv() contract.PrecompileEnvironment { return nil }
+
+func query(t *testing.T, state contract.AccessibleState, target []byte) byte {
+	t.Helper()
+	ret, remaining, err := CapabilityPrecompile.Run(state, common.Address{}, ContractAddress, target, GasIsEnabled, true)
+	require.NoError(t, err)
+	require.Zero(t, remaining)
+	require.Len(t, ret, common.HashLength)
+	return ret[common.HashLength-1]
+}
+
+func TestCapabilityPrecompile_Address(t *testing.T) {
+	require.Equal(t, common.HexToAddress("0x0000000000000000000000000000000000008001"), ContractAddress)
+	require.Equal(t, ContractAddress, CapabilityPrecompile.Address())
+}
+
+func TestCapability_EnabledAndUnknown(t *testing.T) {
+	state := &chainState{config: chainConfig{fheAddress: 0}}
+
+	// Raw and ABI-encoded targets
+	require.Equal(t, byte(1), query(t, state, fheAddress.Bytes()))
+	require.Equal(t, byte(1), query(t, state, common.LeftPadBytes(fheAddress.Bytes(), 32)))
+
+	// Unknown address
+	require.Equal(t, byte(0), query(t, state, common.HexToAddress("0x1234").Bytes()))
+
+	// Not active on a chain that does not enable it
+	state.config = chainConfig{}
+	require.Equal(t, byte(0), query(t, state, fheAddress.Bytes()))
+
+	// Chain configs that do not report their precompiles enable nothing
+	state.config = legacyChainConfig{}
+	require.Equal(t, byte(0), query(t, state, fheAddress.Bytes()))
+	state.config = nil
+	require.Equal(t, byte(0), query(t, state, fheAddress.Bytes()))
+}
+
+func TestCapability_UpgradeSchedule(t *testing.T) {
+	activation := uint64(1000)
+	config := chainConfig{fheAddress: activation}
+	fhe := fheAddress.Bytes()
+
+	require.Equal(t, byte(0), query(t, &chainState{config: config, timestamp: activation - 1}, fhe))
+	require.Equal(t, byte(1), query(t, &chainState{config: config, timestamp: activation}, fhe))
+}
+
+func TestCapability_InvalidInput(t *testing.T) {
+	state := &chainState{config: chainConfig{}}
+
+	for _, input := range [][]byte{nil, make([]byte, 19), make([]byte, 33)} {
+		_, _, err := CapabilityPrecompile.Run(state, common.Address{}, ContractAddress, input, GasIsEnabled, true)
+		require.ErrorIs(t, err, ErrInvalidInput)
+	}
+
+	// Dirty upper bytes in an ABI-encoded address
+	dirty := make([]byte, 32)
+	dirty[0] = 1
+	_, _, err := CapabilityPrecompile.Run(state, common.Address{}, ContractAddress, dirty, GasIsEnabled, true)
+	require.ErrorIs(t, err, ErrInvalidInput)
+
+	_, _, err = CapabilityPrecompile.Run(state, common.Address{}, ContractAddress, make([]byte, 20), GasIsEnabled-1, true)
+	require.ErrorIs(t, err, contract.ErrOutOfGas)
+}
@@ -4,14 +4,13 @@
 package cggmp21
 
 import (
-	"crypto/ecdsa"
 	"encoding/binary"
 	"errors"
 	"fmt"
 
-	"github.com/luxfi/crypto"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/crypto/ecverify"
 )
 
 var (
@@ -25,8 +24,8 @@ var (
 
 	ErrInvalidInputLength  = errors.New("invalid input length")
 	ErrInvalidThreshold    = errors.New("invalid threshold: t must be > 0 and <= n")
-	ErrInvalidPublicKey    = errors.New("invalid public key")
-	ErrInvalidSignature    = errors.New("invalid signature")
+	ErrInvalidPublicKey    = ecverify.ErrInvalidPublicKey
+	ErrInvalidSignature    = ecverify.ErrInvalidSignature
 	ErrSignatureVerifyFail = errors.New("signature verification failed")
 )
 
@@ -113,8 +112,8 @@ func (p *cggmp21VerifyPrecompile) Run(
 	messageHash := input[73:105]
 	signatureBytes := input[105:170]
 
-	// Verify ECDSA signature
-	valid, err := verifyECDSASignature(publicKeyBytes, messageHash, signatureBytes)
+	// CGGMP21 produces standard ECDSA signatures that can be verified normally
+	valid, err := ecverify.VerifyECDSASecp256k1(publicKeyBytes, messageHash, signatureBytes)
 	if err != nil {
 		return nil, suppliedGas - gasCost, err
 	}
@@ -127,85 +126,3 @@ func (p *cggmp21VerifyPrecompile) Run(
 
 	return result, suppliedGas - gasCost, nil
 }
-
-// verifyECDSASignature verifies an ECDSA signature
-func verifyECDSASignature(publicKeyBytes, messageHash, signatureBytes []byte) (bool, error) {
-	if len(publicKeyBytes) != 65 {
-		return false, ErrInvalidPublicKey
-	}
-	if len(messageHash) != 32 {
-		return false, errors.New("invalid message hash length")
-	}
-	if len(signatureBytes) != 65 {
-		return false, ErrInvalidSignature
-	}
-
-	// Parse public key
-	publicKey, err := crypto.UnmarshalPubkey(publicKeyBytes)
-	if err != nil {
-		return false, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
-	}
-
-	// Extract v from signature (r and s are at positions 0:32 and 32:64)
-	v := signatureBytes[64]
-
-	// Normalize v (should be 27 or 28, or 0 or 1)
-	if v >= 27 {
-		v -= 27
-	}
-	_ = v // v is used in recovery below
-
-	// Verify signature
-	// CGGMP21 produces standard ECDSA signatures that can be verified normally
-	sig := make([]byte, 64)
-	copy(sig[0:32], signatureBytes[0:32])   // r
-	copy(sig[32:64], signatureBytes[32:64]) // s
-
-	valid := crypto.VerifySignature(
-		crypto.FromECDSAPub(publicKey),
-		messageHash,
-		sig,
-	)
-
-	if !valid {
-		return false, nil
-	}
-
-	// Additional validation: recover public key and compare
-	recoveredPubKey, err := recoverPublicKey(messageHash, signatureBytes)
-	if err != nil {
-		return false, nil
-	}
-
-	// Compare recovered public key with expected
-	if recoveredPubKey.X.Cmp(publicKey.X) != 0 || recoveredPubKey.Y.Cmp(publicKey.Y) != 0 {
-		return false, nil
-	}
-
-	return true, nil
-}
-
-// recoverPublicKey recovers the public key from signature
-func recoverPublicKey(messageHash, signature []byte) (*ecdsa.PublicKey, error) {
-	if len(signature) != 65 {
-		return nil, ErrInvalidSignature
-	}
-
-	v := signature[64]
-	if v >= 27 {
-		v -= 27
-	}
-
-	// Normalize signature for ecrecover
-	sig := make([]byte, 65)
-	copy(sig[0:32], signature[0:32])   // r
-	copy(sig[32:64], signature[32:64]) // s
-	sig[64] = v
-
-	pubKeyBytes, err := crypto.Ecrecover(messageHash, sig)
-	if err != nil {
-		return nil, err
-	}
-
-	return crypto.UnmarshalPubkey(pubKeyBytes)
-}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/luxfi/crypto"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/crypto/ecverify"
 	"github.com/stretchr/testify/require"
 )
 
@@ -301,3 +302,56 @@ func BenchmarkCGGMP21Verify_10of15(b *testing.B) {
 		)
 	}
 }
+
+// ecdsaCases returns signatures the shared core accepts and rejects
+func ecdsaCases(t *testing.T) map[string][3][]byte {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey := crypto.FromECDSAPub(&privateKey.PublicKey)
+	messageHash := crypto.Keccak256([]byte("test message"))
+	signature, err := crypto.Sign(messageHash, privateKey)
+	require.NoError(t, err)
+
+	corrupted := common.CopyBytes(signature)
+	corrupted[10] ^= 0xFF
+	flipped := common.CopyBytes(signature)
+	flipped[64] ^= 1
+	legacy := common.CopyBytes(signature)
+	legacy[64] += 27
+
+	return map[string][3][]byte{
+		"valid":          {publicKey, messageHash, signature},
+		"legacy v":       {publicKey, messageHash, legacy},
+		"corrupted":      {publicKey, messageHash, corrupted},
+		"flipped v":      {publicKey, messageHash, flipped},
+		"wrong message":  {publicKey, crypto.Keccak256([]byte("other")), signature},
+		"invalid pubkey": {make([]byte, 65), messageHash, signature},
+	}
+}
+
+func TestCGGMP21Verify_MatchesSharedCore(t *testing.T) {
+	for name, tc := range ecdsaCases(t) {
+		t.Run(name, func(t *testing.T) {
+			publicKey, messageHash, signature := tc[0], tc[1], tc[2]
+
+			input := make([]byte, MinInputSize)
+			binary.BigEndian.PutUint32(input[0:4], 2)
+			binary.BigEndian.PutUint32(input[4:8], 3)
+			copy(input[8:73], publicKey)
+			copy(input[73:105], messageHash)
+			copy(input[105:170], signature)
+
+			result, _, err := CGGMP21VerifyPrecompile.Run(nil, common.Address{}, ContractCGGMP21VerifyAddress, input, 1_000_000, true)
+			want, wantErr := ecverify.VerifyECDSASecp256k1(publicKey, messageHash, signature)
+			if wantErr != nil {
+				require.ErrorIs(t, err, ecverify.ErrInvalidPublicKey)
+				require.ErrorIs(t, wantErr, ecverify.ErrInvalidPublicKey)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, want, result[31] == 1)
+		})
+	}
+}
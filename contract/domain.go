@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+)
+
+// DomainSeparatorTag prefixes every domain-separated message so it cannot
+// collide with a raw message signed for some other purpose
+const DomainSeparatorTag = "LUX_PRECOMPILE_DOMAIN_V1"
+
+// ErrChainIDUnavailable is returned when a precompile needs the chain id but
+// the chain config does not expose one
+var ErrChainIDUnavailable = errors.New("chain id unavailable")
+
+// ChainIDConfig is implemented by chain configs that expose the chain id.
+// Signature precompiles need it to bind signatures to a chain.
+type ChainIDConfig interface {
+	GetChainID() *big.Int
+}
+
+// ChainID returns the chain id from accessibleState's chain config
+func ChainID(accessibleState AccessibleState) (*big.Int, error) {
+	if accessibleState == nil {
+		return nil, ErrChainIDUnavailable
+	}
+	config, ok := accessibleState.GetChainConfig().(ChainIDConfig)
+	if !ok {
+		return nil, ErrChainIDUnavailable
+	}
+	chainID := config.GetChainID()
+	if chainID == nil || chainID.Sign() < 0 || chainID.BitLen() > 256 {
+		return nil, ErrChainIDUnavailable
+	}
+	return chainID, nil
+}
+
+// DomainSeparatedMessage binds message to a chain and precompile:
+//
+//	DomainSeparatorTag || chainID (32, big-endian) || precompile (20) || message
+//
+// A signer signs this instead of the raw message, so the signature only
+// verifies at that precompile on that chain.
+func DomainSeparatedMessage(chainID *big.Int, precompile common.Address, message []byte) []byte {
+	out := make([]byte, 0, len(DomainSeparatorTag)+common.HashLength+common.AddressLength+len(message))
+	out = append(out, DomainSeparatorTag...)
+	out = append(out, chainID.FillBytes(make([]byte, common.HashLength))...)
+	out = append(out, precompile.Bytes()...)
+	return append(out, message...)
+}
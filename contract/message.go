@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/precompile/precompileconfig"
+)
+
+// MaxMessageSize is the default limit on the message a signature-verifying
+// precompile hashes. Verification work grows with the message while most of
+// the gas is a flat per-mode charge, so longer messages are rejected before
+// any processing. Callers with longer messages can use pre-hashed mode.
+const MaxMessageSize = 16 * 1024
+
+var ErrMessageTooLarge = errors.New("message exceeds maximum size")
+
+// MessageSizeLimit returns the maximum message size under the chain config of
+// accessibleState at its current block timestamp. Chain configs that do not
+// implement precompileconfig.MessageSizeConfig use MaxMessageSize.
+func MessageSizeLimit(accessibleState AccessibleState) uint64 {
+	if accessibleState == nil {
+		return MaxMessageSize
+	}
+	c, ok := accessibleState.GetChainConfig().(precompileconfig.MessageSizeConfig)
+	if !ok {
+		return MaxMessageSize
+	}
+	var timestamp uint64
+	if blockContext := accessibleState.GetBlockContext(); blockContext != nil {
+		timestamp = blockContext.Timestamp()
+	}
+	if limit := c.MaxMessageSize(timestamp); limit != 0 {
+		return limit
+	}
+	return MaxMessageSize
+}
+
+// CheckMessageSize returns ErrMessageTooLarge if a message of size bytes is
+// over limit
+func CheckMessageSize(size, limit uint64) error {
+	if size > limit {
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrMessageTooLarge, size, limit)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import (
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+)
+
+// Pre-hash algorithm ids. Each id is the last arc of the algorithm's NIST
+// OID 2.16.840.1.101.3.4.2.x.
+const (
+	PreHashSHA256   uint8 = 0x01
+	PreHashSHA384   uint8 = 0x02
+	PreHashSHA512   uint8 = 0x03
+	PreHashSHA3_256 uint8 = 0x08
+	PreHashSHA3_384 uint8 = 0x09
+	PreHashSHA3_512 uint8 = 0x0A
+	PreHashSHAKE128 uint8 = 0x0B // 32-byte output
+	PreHashSHAKE256 uint8 = 0x0C // 64-byte output
+)
+
+// preHashOIDPrefix is the DER encoding of OID 2.16.840.1.101.3.4.2 without
+// its final arc
+var preHashOIDPrefix = []byte{0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02}
+
+var (
+	ErrUnsupportedPreHash = errors.New("unsupported pre-hash algorithm")
+	ErrInvalidDigest      = errors.New("invalid pre-hash digest length")
+)
+
+// PreHashDigestLength returns the digest length of a pre-hash algorithm
+func PreHashDigestLength(alg uint8) (int, error) {
+	switch alg {
+	case PreHashSHA256, PreHashSHA3_256, PreHashSHAKE128:
+		return 32, nil
+	case PreHashSHA384, PreHashSHA3_384:
+		return 48, nil
+	case PreHashSHA512, PreHashSHA3_512, PreHashSHAKE256:
+		return 64, nil
+	default:
+		return 0, fmt.Errorf("%w: 0x%02x", ErrUnsupportedPreHash, alg)
+	}
+}
+
+// PreHashedMessage returns the message a pre-hashed signature is made over:
+//
+//	OID(alg) (11, DER) || digest
+//
+// The result is signed and verified in pure mode, so a verifier only needs
+// the digest and never the original message. This is a Lux-specific
+// encoding, not HashML-DSA or HashSLH-DSA: those sign with domain byte 0x01
+// in the FIPS 204 and 205 message prefix, while this is an ordinary pure
+// signature (domain byte 0x00) over the encoded digest. Signatures made with
+// a FIPS pre-hash signer do not verify here.
+func PreHashedMessage(alg uint8, digest []byte) ([]byte, error) {
+	size, err := PreHashDigestLength(alg)
+	if err != nil {
+		return nil, err
+	}
+	if len(digest) != size {
+		return nil, fmt.Errorf("%w: expected %d bytes for algorithm 0x%02x, got %d", ErrInvalidDigest, size, alg, len(digest))
+	}
+	out := make([]byte, 0, len(preHashOIDPrefix)+1+size)
+	out = append(out, preHashOIDPrefix...)
+	out = append(out, alg)
+	return append(out, digest...), nil
+}
+
+// PreHash hashes message with a pre-hash algorithm. Signers use it to build
+// the digest passed to PreHashedMessage.
+func PreHash(alg uint8, message []byte) ([]byte, error) {
+	switch alg {
+	case PreHashSHA256:
+		digest := sha256.Sum256(message)
+		return digest[:], nil
+	case PreHashSHA384:
+		digest := sha512.Sum384(message)
+		return digest[:], nil
+	case PreHashSHA512:
+		digest := sha512.Sum512(message)
+		return digest[:], nil
+	case PreHashSHA3_256:
+		digest := sha3.Sum256(message)
+		return digest[:], nil
+	case PreHashSHA3_384:
+		digest := sha3.Sum384(message)
+		return digest[:], nil
+	case PreHashSHA3_512:
+		digest := sha3.Sum512(message)
+		return digest[:], nil
+	case PreHashSHAKE128:
+		return sha3.SumSHAKE128(message, 32), nil
+	case PreHashSHAKE256:
+		return sha3.SumSHAKE256(message, 64), nil
+	default:
+		return nil, fmt.Errorf("%w: 0x%02x", ErrUnsupportedPreHash, alg)
+	}
+}
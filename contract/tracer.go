@@ -0,0 +1,42 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package contract
+
+import "github.com/luxfi/geth/common"
+
+// Tracer receives precompile execution events for debugging. OnEnter is
+// called before a precompile does any work and OnExit after it returns,
+// with the gas it consumed and the error it returned, if any.
+type Tracer interface {
+	OnEnter(addr common.Address, selector []byte, inputLen int)
+	OnExit(gasUsed uint64, err error)
+}
+
+// TracingState is implemented by an AccessibleState that has a tracer
+// configured
+type TracingState interface {
+	GetTracer() Tracer
+}
+
+// TracerFrom returns the tracer configured on accessibleState, or nil when
+// there is none
+func TracerFrom(accessibleState AccessibleState) Tracer {
+	if accessibleState == nil {
+		return nil
+	}
+	tracing, ok := accessibleState.(TracingState)
+	if !ok {
+		return nil
+	}
+	return tracing.GetTracer()
+}
+
+// Selector returns the 4-byte function selector of input, or all of input
+// when it is shorter
+func Selector(input []byte) []byte {
+	if len(input) < 4 {
+		return input
+	}
+	return input[:4]
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/luxfi/crypto"
 	"github.com/luxfi/geth/accounts/abi"
+	"github.com/luxfi/geth/common"
 )
 
 // ErrOutOfGas is returned when the gas is exhausted
@@ -51,6 +52,31 @@ func DeductGas(suppliedGas uint64, requiredGas uint64) (uint64, error) {
 	return suppliedGas - requiredGas, nil
 }
 
+// LengthRejectionRefundPercent is the share of its up-front charge a
+// precompile may refund when it rejects input on length alone, before doing
+// any real work. The rest is kept so malformed calls still cost enough to
+// make probing the precompile expensive.
+const LengthRejectionRefundPercent = 50
+
+// RefundLengthRejection returns remainingGas plus the refund due on
+// chargedGas for a length-only rejection. Callers charge the full gas first
+// (with DeductGas), so a call that cannot afford a real run still fails with
+// ErrOutOfGas whatever its input.
+func RefundLengthRejection(remainingGas uint64, chargedGas uint64) uint64 {
+	return remainingGas + chargedGas*LengthRejectionRefundPercent/100
+}
+
+// WrapRunError prefixes an error returned from a precompile's Run with the
+// precompile's name and address, so a failure in a call that crosses
+// several precompiles names its source. The result still matches err with
+// errors.Is. WrapRunError returns nil for a nil err.
+func WrapRunError(name string, addr common.Address, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s precompile %s: %w", name, addr.Hex(), err)
+}
+
 // ParseABI parses the given ABI string and returns the parsed ABI.
 // If the ABI is invalid, it panics.
 func ParseABI(rawABI string) abi.ABI {
@@ -0,0 +1,248 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package ecverify is the single signature verification core shared by the
+// threshold and classical signature precompiles.
+//
+// Threshold protocols (CGGMP21, Doerner, FROST, BLS threshold) all emit an
+// ordinary single-key signature, so every precompile that checks one must
+// agree bit-for-bit on what "valid" means. Verification lives here and
+// nowhere else.
+//
+// Each verifier returns (false, nil) for a well-formed signature that does
+// not verify, and a wrapped ErrInvalidPublicKey, ErrInvalidMessageHash or
+// ErrInvalidSignature for input that cannot be parsed.
+package ecverify
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/crypto/secp256k1"
+)
+
+var (
+	ErrInvalidPublicKey   = errors.New("invalid public key")
+	ErrInvalidMessageHash = errors.New("invalid message hash length")
+	ErrInvalidSignature   = errors.New("invalid signature")
+
+	// curve is secp256k1, shared by ECDSA and BIP-340
+	curve = secp256k1.S256()
+
+	// bip340Challenge is SHA256("BIP0340/challenge"), hashed twice as the
+	// tagged-hash prefix
+	bip340Challenge = sha256.Sum256([]byte("BIP0340/challenge"))
+)
+
+const (
+	ECDSAPublicKeySize = 65 // Uncompressed public key (0x04 || x || y)
+	ECDSASignatureSize = 65 // Recoverable signature (r || s || v)
+	MessageHashSize    = 32
+
+	SchnorrPublicKeySize = 32 // x-only public key
+	SchnorrSignatureSize = 64 // r || s
+
+	BLSPublicKeySize = 48 // Compressed G1 point
+	BLSSignatureSize = 96 // Compressed G2 point
+
+	BLSUncompressedPublicKeySize = 96  // Uncompressed G1 point
+	BLSUncompressedSignatureSize = 192 // Uncompressed G2 point
+
+	// blsCompressedFlag is set in the first byte of a compressed BLS12-381
+	// point encoding
+	blsCompressedFlag = 0x80
+)
+
+// VerifyECDSASecp256k1 verifies a 65-byte [r || s || v] signature over a
+// 32-byte hash. v may be 0/1 or 27/28, and must recover to publicKey: a
+// signature that only verifies under the other recovery id is rejected.
+func VerifyECDSASecp256k1(publicKey, messageHash, signature []byte) (bool, error) {
+	if len(publicKey) != ECDSAPublicKeySize {
+		return false, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidPublicKey, ECDSAPublicKeySize, len(publicKey))
+	}
+	if len(messageHash) != MessageHashSize {
+		return false, ErrInvalidMessageHash
+	}
+	if len(signature) != ECDSASignatureSize {
+		return false, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, ECDSASignatureSize, len(signature))
+	}
+
+	pk, err := crypto.UnmarshalPubkey(publicKey)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	encoded := crypto.FromECDSAPub(pk)
+
+	if !crypto.VerifySignature(encoded, messageHash, signature[:64]) {
+		return false, nil
+	}
+
+	// The recovery id must also point back at the key
+	sig := make([]byte, ECDSASignatureSize)
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	recovered, err := crypto.Ecrecover(messageHash, sig)
+	if err != nil {
+		return false, nil
+	}
+	return string(recovered) == string(encoded), nil
+}
+
+// VerifySchnorrBIP340 verifies a BIP-340 Schnorr signature under an x-only
+// secp256k1 public key
+func VerifySchnorrBIP340(publicKey, message, signature []byte) (bool, error) {
+	if len(publicKey) != SchnorrPublicKeySize {
+		return false, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidPublicKey, SchnorrPublicKeySize, len(publicKey))
+	}
+	if len(signature) != SchnorrSignatureSize {
+		return false, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, SchnorrSignatureSize, len(signature))
+	}
+
+	px, py, ok := liftX(new(big.Int).SetBytes(publicKey))
+	if !ok {
+		return false, ErrInvalidPublicKey
+	}
+
+	params := curve.Params()
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if r.Cmp(params.P) >= 0 || s.Cmp(params.N) >= 0 {
+		return false, nil
+	}
+
+	// e = int(hash_BIP0340/challenge(r || P || m)) mod n
+	h := sha256.New()
+	h.Write(bip340Challenge[:])
+	h.Write(bip340Challenge[:])
+	h.Write(signature[:32])
+	h.Write(publicKey)
+	h.Write(message)
+	e := new(big.Int).SetBytes(h.Sum(nil))
+	e.Mod(e, params.N)
+
+	// R = s*G - e*P
+	var rx, ry *big.Int
+	if s.Sign() != 0 {
+		rx, ry = curve.ScalarBaseMult(scalarBytes(s))
+	}
+	if e.Sign() != 0 {
+		negE := new(big.Int).Sub(params.N, e)
+		ex, ey := curve.ScalarMult(px, py, scalarBytes(negE))
+		if rx == nil {
+			rx, ry = ex, ey
+		} else {
+			rx, ry = curve.Add(rx, ry, ex, ey)
+		}
+	}
+	if rx == nil || (rx.Sign() == 0 && ry.Sign() == 0) {
+		return false, nil
+	}
+	return ry.Bit(0) == 0 && rx.Cmp(r) == 0, nil
+}
+
+// VerifyBLS12381 verifies a BLS12-381 signature (G2) under a G1 public key.
+// Either may be compressed or uncompressed; the format is detected by length.
+func VerifyBLS12381(publicKey, message, signature []byte) (bool, error) {
+	pk, err := decompressG1(publicKey)
+	if err != nil {
+		return false, err
+	}
+	sig, err := decompressG2(signature)
+	if err != nil {
+		return false, err
+	}
+	return bls.Verify(pk, sig, message), nil
+}
+
+// IsBLSCompressed reports whether a BLS12-381 point encoding has the
+// compression flag set. Callers use it to size a point at the start of a
+// packed input.
+func IsBLSCompressed(point []byte) bool {
+	return len(point) > 0 && point[0]&blsCompressedFlag != 0
+}
+
+// decompressG1 parses a 48-byte compressed or 96-byte uncompressed G1 public
+// key. The compression flag must agree with the length.
+func decompressG1(publicKey []byte) (*bls.PublicKey, error) {
+	switch len(publicKey) {
+	case BLSPublicKeySize:
+	case BLSUncompressedPublicKeySize:
+		if IsBLSCompressed(publicKey) {
+			return nil, fmt.Errorf("%w: compression flag set on %d-byte key", ErrInvalidPublicKey, len(publicKey))
+		}
+		var p bls12381.G1
+		if err := p.SetBytes(publicKey); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+		}
+		publicKey = p.BytesCompressed()
+	default:
+		return nil, fmt.Errorf("%w: expected %d or %d bytes, got %d", ErrInvalidPublicKey, BLSPublicKeySize, BLSUncompressedPublicKeySize, len(publicKey))
+	}
+
+	pk, err := bls.PublicKeyFromCompressedBytes(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPublicKey, err)
+	}
+	return pk, nil
+}
+
+// decompressG2 parses a 96-byte compressed or 192-byte uncompressed G2
+// signature. The compression flag must agree with the length.
+func decompressG2(signature []byte) (*bls.Signature, error) {
+	switch len(signature) {
+	case BLSSignatureSize:
+	case BLSUncompressedSignatureSize:
+		if IsBLSCompressed(signature) {
+			return nil, fmt.Errorf("%w: compression flag set on %d-byte signature", ErrInvalidSignature, len(signature))
+		}
+		var p bls12381.G2
+		if err := p.SetBytes(signature); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+		}
+		signature = p.BytesCompressed()
+	default:
+		return nil, fmt.Errorf("%w: expected %d or %d bytes, got %d", ErrInvalidSignature, BLSSignatureSize, BLSUncompressedSignatureSize, len(signature))
+	}
+
+	sig, err := bls.SignatureFromBytes(signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return sig, nil
+}
+
+// liftX returns the secp256k1 point with x-coordinate x and even y
+func liftX(x *big.Int) (*big.Int, *big.Int, bool) {
+	p := curve.Params().P
+	if x.Cmp(p) >= 0 {
+		return nil, nil, false
+	}
+
+	// y^2 = x^3 + 7; p = 3 mod 4 so y = c^((p+1)/4)
+	c := new(big.Int).Exp(x, big.NewInt(3), p)
+	c.Add(c, big.NewInt(7))
+	c.Mod(c, p)
+
+	exp := new(big.Int).Add(p, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(c, exp, p)
+	if new(big.Int).Exp(y, big.NewInt(2), p).Cmp(c) != 0 {
+		return nil, nil, false
+	}
+	if y.Bit(0) == 1 {
+		y.Sub(p, y)
+	}
+	return x, y, true
+}
+
+// scalarBytes left-pads k to 32 bytes
+func scalarBytes(k *big.Int) []byte {
+	return k.FillBytes(make([]byte, 32))
+}
@@ -0,0 +1,284 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ecverify
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// signBIP340 is a reference BIP-340 signer with a random nonce
+func signBIP340(t *testing.T, d *big.Int, message []byte) (publicKey, signature []byte) {
+	t.Helper()
+
+	n := curve.Params().N
+	px, py := curve.ScalarBaseMult(scalarBytes(d))
+	if py.Bit(0) == 1 {
+		d = new(big.Int).Sub(n, d)
+	}
+
+	k, err := rand.Int(rand.Reader, n)
+	require.NoError(t, err)
+	rx, ry := curve.ScalarBaseMult(scalarBytes(k))
+	if ry.Bit(0) == 1 {
+		k.Sub(n, k)
+	}
+
+	h := sha256.New()
+	h.Write(bip340Challenge[:])
+	h.Write(bip340Challenge[:])
+	h.Write(scalarBytes(rx))
+	h.Write(scalarBytes(px))
+	h.Write(message)
+	e := new(big.Int).SetBytes(h.Sum(nil))
+
+	s := new(big.Int).Mul(e, d)
+	s.Add(s, k)
+	s.Mod(s, n)
+	return scalarBytes(px), append(scalarBytes(rx), scalarBytes(s)...)
+}
+
+func TestVerifyECDSASecp256k1(t *testing.T) {
+	key, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey := crypto.FromECDSAPub(&key.PublicKey)
+	messageHash := crypto.Keccak256([]byte("test message"))
+
+	signature, err := crypto.Sign(messageHash, key)
+	require.NoError(t, err)
+
+	valid, err := VerifyECDSASecp256k1(publicKey, messageHash, signature)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// Ethereum-style v is accepted
+	legacy := common.CopyBytes(signature)
+	legacy[64] += 27
+	valid, err = VerifyECDSASecp256k1(publicKey, messageHash, legacy)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// A flipped recovery id no longer points at the key
+	flipped := common.CopyBytes(signature)
+	flipped[64] ^= 1
+	valid, err = VerifyECDSASecp256k1(publicKey, messageHash, flipped)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	valid, err = VerifyECDSASecp256k1(publicKey, crypto.Keccak256([]byte("other")), signature)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	_, err = VerifyECDSASecp256k1(publicKey[:64], messageHash, signature)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+	_, err = VerifyECDSASecp256k1(make([]byte, ECDSAPublicKeySize), messageHash, signature)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+	_, err = VerifyECDSASecp256k1(publicKey, messageHash[:31], signature)
+	require.ErrorIs(t, err, ErrInvalidMessageHash)
+	_, err = VerifyECDSASecp256k1(publicKey, messageHash, signature[:64])
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerifySchnorrBIP340_Vector(t *testing.T) {
+	// BIP-340 test vector 0
+	publicKey := mustHex(t, "f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9")
+	message := make([]byte, 32)
+	signature := mustHex(t, "e907831f80848d1069a5371b402410364bdf1c5f8307b0084c55f1ce2dca8215"+
+		"25f66a4a85ea8b71e482a74f382d2ce5ebeee8fdb2172f477df4900d310536c0")
+
+	valid, err := VerifySchnorrBIP340(publicKey, message, signature)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	signature[63] ^= 1
+	valid, err = VerifySchnorrBIP340(publicKey, message, signature)
+	require.NoError(t, err)
+	require.False(t, valid)
+}
+
+func TestVerifySchnorrBIP340(t *testing.T) {
+	d, err := rand.Int(rand.Reader, curve.Params().N)
+	require.NoError(t, err)
+	message := crypto.Keccak256([]byte("test message"))
+
+	publicKey, signature := signBIP340(t, d, message)
+
+	valid, err := VerifySchnorrBIP340(publicKey, message, signature)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	valid, err = VerifySchnorrBIP340(publicKey, crypto.Keccak256([]byte("other")), signature)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// s >= n is rejected rather than reduced
+	overflow := append(common.CopyBytes(signature[:32]), scalarBytes(curve.Params().N)...)
+	valid, err = VerifySchnorrBIP340(publicKey, message, overflow)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// x = 5 has no point on secp256k1
+	noPoint := make([]byte, SchnorrPublicKeySize)
+	noPoint[31] = 5
+	_, err = VerifySchnorrBIP340(noPoint, message, signature)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+	_, err = VerifySchnorrBIP340(publicKey, message, signature[:63])
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerifyBLS12381(t *testing.T) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	publicKey := bls.PublicKeyToCompressedBytes(bls.PublicFromSecretKey(sk))
+	message := []byte("test message")
+	signature := bls.SignatureToBytes(bls.Sign(sk, message))
+
+	valid, err := VerifyBLS12381(publicKey, message, signature)
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	valid, err = VerifyBLS12381(publicKey, []byte("other"), signature)
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	_, err = VerifyBLS12381(publicKey[:47], message, signature)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+	_, err = VerifyBLS12381(publicKey, message, signature[:95])
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerifyBLS12381Uncompressed(t *testing.T) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+	publicKey := bls.PublicKeyToCompressedBytes(bls.PublicFromSecretKey(sk))
+	message := []byte("test message")
+	signature := bls.SignatureToBytes(bls.Sign(sk, message))
+
+	var pk bls12381.G1
+	require.NoError(t, pk.SetBytes(publicKey))
+	uncompressedKey := pk.Bytes()
+	require.Len(t, uncompressedKey, BLSUncompressedPublicKeySize)
+
+	var sig bls12381.G2
+	require.NoError(t, sig.SetBytes(signature))
+	uncompressedSig := sig.Bytes()
+	require.Len(t, uncompressedSig, BLSUncompressedSignatureSize)
+
+	// The same signature verifies in every combination of formats
+	for _, key := range [][]byte{publicKey, uncompressedKey} {
+		for _, s := range [][]byte{signature, uncompressedSig} {
+			valid, err := VerifyBLS12381(key, message, s)
+			require.NoError(t, err)
+			require.True(t, valid)
+
+			valid, err = VerifyBLS12381(key, []byte("other"), s)
+			require.NoError(t, err)
+			require.False(t, valid)
+		}
+	}
+
+	// Compression flag set on an uncompressed encoding
+	flagged := common.CopyBytes(uncompressedKey)
+	flagged[0] |= 0x80
+	_, err = VerifyBLS12381(flagged, message, signature)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+	flagged = common.CopyBytes(uncompressedSig)
+	flagged[0] |= 0x80
+	_, err = VerifyBLS12381(publicKey, message, flagged)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Point not on the curve
+	offCurve := common.CopyBytes(uncompressedKey)
+	offCurve[len(offCurve)-1] ^= 1
+	_, err = VerifyBLS12381(offCurve, message, signature)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+	offCurve = common.CopyBytes(uncompressedSig)
+	offCurve[len(offCurve)-1] ^= 1
+	_, err = VerifyBLS12381(publicKey, message, offCurve)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Compressed encoding without the compression flag
+	unflagged := common.CopyBytes(publicKey)
+	unflagged[0] &^= 0x80
+	_, err = VerifyBLS12381(unflagged, message, signature)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	// Group keys may be registered in either form
+	require.NoError(t, validatePublicKey(SchemeBLS12381, uncompressedKey))
+}
+
+func TestGroupKeyRegistry(t *testing.T) {
+	key, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey := crypto.FromECDSAPub(&key.PublicKey)
+	parties := []common.Address{{0x1}, {0x2}, {0x3}}
+
+	r := NewGroupKeyRegistry()
+	groupKey := GroupKey{
+		Name:      "custody",
+		Scheme:    SchemeECDSASecp256k1,
+		PublicKey: publicKey,
+		Threshold: 2,
+		Parties:   parties,
+	}
+
+	id, err := r.Register(groupKey)
+	require.NoError(t, err)
+	require.Equal(t, GroupKeyID(publicKey), id)
+
+	got, ok := r.Get(id)
+	require.True(t, ok)
+	require.Equal(t, publicKey, got.PublicKey)
+	got, ok = r.Lookup("custody")
+	require.True(t, ok)
+	require.Equal(t, id, GroupKeyID(got.PublicKey))
+	_, ok = r.Lookup("missing")
+	require.False(t, ok)
+
+	_, err = r.Register(groupKey)
+	require.ErrorIs(t, err, ErrGroupKeyExists)
+
+	other, err := ecdsa.GenerateKey(crypto.S256(), rand.Reader)
+	require.NoError(t, err)
+	renamed := groupKey
+	renamed.PublicKey = crypto.FromECDSAPub(&other.PublicKey)
+	_, err = r.Register(renamed)
+	require.ErrorIs(t, err, ErrGroupKeyNameTaken)
+
+	renamed.Name = "other"
+	renamed.Threshold = 4
+	_, err = r.Register(renamed)
+	require.ErrorIs(t, err, ErrInvalidThreshold)
+
+	renamed.Threshold = 2
+	renamed.Parties = []common.Address{{0x1}, {0x1}}
+	_, err = r.Register(renamed)
+	require.ErrorIs(t, err, ErrInvalidParties)
+
+	renamed.Parties = parties
+	renamed.Scheme = SchemeBLS12381
+	_, err = r.Register(renamed)
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+
+	renamed.Scheme = 0
+	_, err = r.Register(renamed)
+	require.ErrorIs(t, err, ErrInvalidScheme)
+}
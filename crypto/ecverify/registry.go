@@ -0,0 +1,167 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ecverify
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+)
+
+var (
+	// DefaultGroupKeys is the group key registry shared by the threshold
+	// precompiles
+	DefaultGroupKeys = NewGroupKeyRegistry()
+
+	ErrGroupKeyExists    = errors.New("group key already registered")
+	ErrGroupKeyNameTaken = errors.New("group key name already registered")
+	ErrInvalidGroupName  = errors.New("group key name must be non-empty")
+	ErrInvalidScheme     = errors.New("unknown signature scheme")
+	ErrInvalidParties    = errors.New("group key parties must be distinct and non-zero")
+	ErrInvalidThreshold  = errors.New("invalid threshold: t must be > 0 and <= n")
+)
+
+// Scheme identifies the verifier a group key is checked with
+type Scheme uint8
+
+const (
+	SchemeECDSASecp256k1 Scheme = iota + 1
+	SchemeSchnorrBIP340
+	SchemeBLS12381
+)
+
+// Verify checks signature over message with the scheme's verifier
+func (s Scheme) Verify(publicKey, message, signature []byte) (bool, error) {
+	switch s {
+	case SchemeECDSASecp256k1:
+		return VerifyECDSASecp256k1(publicKey, message, signature)
+	case SchemeSchnorrBIP340:
+		return VerifySchnorrBIP340(publicKey, message, signature)
+	case SchemeBLS12381:
+		return VerifyBLS12381(publicKey, message, signature)
+	default:
+		return false, ErrInvalidScheme
+	}
+}
+
+// GroupKey is a named threshold public key and the parties holding its shares
+type GroupKey struct {
+	Name      string
+	Scheme    Scheme
+	PublicKey []byte
+	Threshold uint32
+	Parties   []common.Address
+}
+
+// GroupKeyRegistry maps key IDs and names to group keys
+type GroupKeyRegistry struct {
+	keys  map[common.Hash]*GroupKey
+	names map[string]common.Hash
+	mu    sync.RWMutex
+}
+
+// NewGroupKeyRegistry creates an empty registry
+func NewGroupKeyRegistry() *GroupKeyRegistry {
+	return &GroupKeyRegistry{
+		keys:  make(map[common.Hash]*GroupKey),
+		names: make(map[string]common.Hash),
+	}
+}
+
+// GroupKeyID returns the registry ID of a public key
+func GroupKeyID(publicKey []byte) common.Hash {
+	return common.BytesToHash(crypto.Keccak256(publicKey))
+}
+
+// Register validates key and records it under its ID and name
+func (r *GroupKeyRegistry) Register(key GroupKey) (common.Hash, error) {
+	if key.Name == "" {
+		return common.Hash{}, ErrInvalidGroupName
+	}
+	if err := validatePublicKey(key.Scheme, key.PublicKey); err != nil {
+		return common.Hash{}, err
+	}
+	if key.Threshold == 0 || int(key.Threshold) > len(key.Parties) {
+		return common.Hash{}, ErrInvalidThreshold
+	}
+	seen := make(map[common.Address]bool, len(key.Parties))
+	for _, p := range key.Parties {
+		if p == (common.Address{}) || seen[p] {
+			return common.Hash{}, ErrInvalidParties
+		}
+		seen[p] = true
+	}
+
+	id := GroupKeyID(key.PublicKey)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[id]; exists {
+		return common.Hash{}, ErrGroupKeyExists
+	}
+	if _, exists := r.names[key.Name]; exists {
+		return common.Hash{}, ErrGroupKeyNameTaken
+	}
+	r.keys[id] = &GroupKey{
+		Name:      key.Name,
+		Scheme:    key.Scheme,
+		PublicKey: common.CopyBytes(key.PublicKey),
+		Threshold: key.Threshold,
+		Parties:   append([]common.Address(nil), key.Parties...),
+	}
+	r.names[key.Name] = id
+	return id, nil
+}
+
+// Get returns the group key registered under id
+func (r *GroupKeyRegistry) Get(id common.Hash) (*GroupKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	return key, ok
+}
+
+// Lookup returns the group key registered under name
+func (r *GroupKeyRegistry) Lookup(name string) (*GroupKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.names[name]
+	if !ok {
+		return nil, false
+	}
+	return r.keys[id], true
+}
+
+// validatePublicKey checks publicKey parses under scheme
+func validatePublicKey(scheme Scheme, publicKey []byte) error {
+	switch scheme {
+	case SchemeECDSASecp256k1:
+		if len(publicKey) != ECDSAPublicKeySize {
+			return ErrInvalidPublicKey
+		}
+		if _, err := crypto.UnmarshalPubkey(publicKey); err != nil {
+			return ErrInvalidPublicKey
+		}
+	case SchemeSchnorrBIP340:
+		if len(publicKey) != SchnorrPublicKeySize {
+			return ErrInvalidPublicKey
+		}
+		if _, _, ok := liftX(new(big.Int).SetBytes(publicKey)); !ok {
+			return ErrInvalidPublicKey
+		}
+	case SchemeBLS12381:
+		if _, err := decompressG1(publicKey); err != nil {
+			return ErrInvalidPublicKey
+		}
+	default:
+		return ErrInvalidScheme
+	}
+	return nil
+}
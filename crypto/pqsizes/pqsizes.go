@@ -0,0 +1,220 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package pqsizes is the canonical table of post-quantum key, signature and
+// ciphertext sizes shared by the PQ precompiles.
+//
+// Precompiles check input lengths against this table before handing bytes
+// to a library, so a key sized for the wrong mode is reported as a size
+// mismatch rather than as an opaque parse error. Modes are named by their
+// FIPS parameter set, independent of any precompile's mode byte encoding.
+package pqsizes
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownMode  = errors.New("unknown post-quantum mode")
+	ErrSizeMismatch = errors.New("post-quantum size mismatch")
+)
+
+// Scheme is a post-quantum algorithm family
+type Scheme uint8
+
+const (
+	MLDSA Scheme = iota + 1
+	MLKEM
+	SLHDSA
+	Falcon
+)
+
+func (s Scheme) String() string {
+	switch s {
+	case MLDSA:
+		return "ML-DSA"
+	case MLKEM:
+		return "ML-KEM"
+	case SLHDSA:
+		return "SLH-DSA"
+	case Falcon:
+		return "Falcon"
+	default:
+		return fmt.Sprintf("Scheme(%d)", uint8(s))
+	}
+}
+
+// Mode is a parameter set, named as in its standard
+type Mode string
+
+// ML-DSA (FIPS 204)
+const (
+	MLDSA44 Mode = "ML-DSA-44"
+	MLDSA65 Mode = "ML-DSA-65"
+	MLDSA87 Mode = "ML-DSA-87"
+)
+
+// ML-KEM (FIPS 203)
+const (
+	MLKEM512  Mode = "ML-KEM-512"
+	MLKEM768  Mode = "ML-KEM-768"
+	MLKEM1024 Mode = "ML-KEM-1024"
+)
+
+// SLH-DSA (FIPS 205)
+const (
+	SLHDSASHA2_128s  Mode = "SLH-DSA-SHA2-128s"
+	SLHDSASHA2_128f  Mode = "SLH-DSA-SHA2-128f"
+	SLHDSASHA2_192s  Mode = "SLH-DSA-SHA2-192s"
+	SLHDSASHA2_192f  Mode = "SLH-DSA-SHA2-192f"
+	SLHDSASHA2_256s  Mode = "SLH-DSA-SHA2-256s"
+	SLHDSASHA2_256f  Mode = "SLH-DSA-SHA2-256f"
+	SLHDSASHAKE_128s Mode = "SLH-DSA-SHAKE-128s"
+	SLHDSASHAKE_128f Mode = "SLH-DSA-SHAKE-128f"
+	SLHDSASHAKE_192s Mode = "SLH-DSA-SHAKE-192s"
+	SLHDSASHAKE_192f Mode = "SLH-DSA-SHAKE-192f"
+	SLHDSASHAKE_256s Mode = "SLH-DSA-SHAKE-256s"
+	SLHDSASHAKE_256f Mode = "SLH-DSA-SHAKE-256f"
+)
+
+// Falcon (FN-DSA), padded signature format
+const (
+	Falcon512  Mode = "Falcon-512"
+	Falcon1024 Mode = "Falcon-1024"
+)
+
+// Sizes holds the encoded sizes in bytes of a mode's objects. A zero size
+// means the object does not exist for the scheme (signature schemes have no
+// ciphertext, KEMs have no signature).
+type Sizes struct {
+	PublicKey  int
+	PrivateKey int
+	Signature  int
+	Ciphertext int
+}
+
+type entry struct {
+	scheme Scheme
+	sizes  Sizes
+}
+
+var table = map[Mode]entry{
+	MLDSA44: {MLDSA, Sizes{PublicKey: 1312, PrivateKey: 2560, Signature: 2420}},
+	MLDSA65: {MLDSA, Sizes{PublicKey: 1952, PrivateKey: 4032, Signature: 3309}},
+	MLDSA87: {MLDSA, Sizes{PublicKey: 2592, PrivateKey: 4896, Signature: 4627}},
+
+	MLKEM512:  {MLKEM, Sizes{PublicKey: 800, PrivateKey: 1632, Ciphertext: 768}},
+	MLKEM768:  {MLKEM, Sizes{PublicKey: 1184, PrivateKey: 2400, Ciphertext: 1088}},
+	MLKEM1024: {MLKEM, Sizes{PublicKey: 1568, PrivateKey: 3168, Ciphertext: 1568}},
+
+	SLHDSASHA2_128s:  {SLHDSA, Sizes{PublicKey: 32, PrivateKey: 64, Signature: 7856}},
+	SLHDSASHA2_128f:  {SLHDSA, Sizes{PublicKey: 32, PrivateKey: 64, Signature: 17088}},
+	SLHDSASHA2_192s:  {SLHDSA, Sizes{PublicKey: 48, PrivateKey: 96, Signature: 16224}},
+	SLHDSASHA2_192f:  {SLHDSA, Sizes{PublicKey: 48, PrivateKey: 96, Signature: 35664}},
+	SLHDSASHA2_256s:  {SLHDSA, Sizes{PublicKey: 64, PrivateKey: 128, Signature: 29792}},
+	SLHDSASHA2_256f:  {SLHDSA, Sizes{PublicKey: 64, PrivateKey: 128, Signature: 49856}},
+	SLHDSASHAKE_128s: {SLHDSA, Sizes{PublicKey: 32, PrivateKey: 64, Signature: 7856}},
+	SLHDSASHAKE_128f: {SLHDSA, Sizes{PublicKey: 32, PrivateKey: 64, Signature: 17088}},
+	SLHDSASHAKE_192s: {SLHDSA, Sizes{PublicKey: 48, PrivateKey: 96, Signature: 16224}},
+	SLHDSASHAKE_192f: {SLHDSA, Sizes{PublicKey: 48, PrivateKey: 96, Signature: 35664}},
+	SLHDSASHAKE_256s: {SLHDSA, Sizes{PublicKey: 64, PrivateKey: 128, Signature: 29792}},
+	SLHDSASHAKE_256f: {SLHDSA, Sizes{PublicKey: 64, PrivateKey: 128, Signature: 49856}},
+
+	Falcon512:  {Falcon, Sizes{PublicKey: 897, PrivateKey: 1281, Signature: 666}},
+	Falcon1024: {Falcon, Sizes{PublicKey: 1793, PrivateKey: 2305, Signature: 1280}},
+}
+
+// Lookup returns the sizes of a scheme's mode
+func Lookup(scheme Scheme, mode Mode) (Sizes, error) {
+	e, ok := table[mode]
+	if !ok || e.scheme != scheme {
+		return Sizes{}, fmt.Errorf("%w: %s mode %q", ErrUnknownMode, scheme, mode)
+	}
+	return e.sizes, nil
+}
+
+// Modes returns every mode of a scheme, in no particular order
+func Modes(scheme Scheme) []Mode {
+	var modes []Mode
+	for mode, e := range table {
+		if e.scheme == scheme {
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+// Field identifies which object a Length measures
+type Field uint8
+
+const (
+	FieldPublicKey Field = iota + 1
+	FieldPrivateKey
+	FieldSignature
+	FieldCiphertext
+)
+
+func (f Field) String() string {
+	switch f {
+	case FieldPublicKey:
+		return "public key"
+	case FieldPrivateKey:
+		return "private key"
+	case FieldSignature:
+		return "signature"
+	case FieldCiphertext:
+		return "ciphertext"
+	default:
+		return fmt.Sprintf("Field(%d)", uint8(f))
+	}
+}
+
+// Length is the observed length of one object in an input
+type Length struct {
+	Field Field
+	Got   int
+}
+
+func PublicKey(n int) Length  { return Length{FieldPublicKey, n} }
+func PrivateKey(n int) Length { return Length{FieldPrivateKey, n} }
+func Signature(n int) Length  { return Length{FieldSignature, n} }
+func Ciphertext(n int) Length { return Length{FieldCiphertext, n} }
+
+// Expected returns the size a field must have for the mode
+func (s Sizes) Expected(f Field) int {
+	switch f {
+	case FieldPublicKey:
+		return s.PublicKey
+	case FieldPrivateKey:
+		return s.PrivateKey
+	case FieldSignature:
+		return s.Signature
+	case FieldCiphertext:
+		return s.Ciphertext
+	default:
+		return 0
+	}
+}
+
+// ValidateSizes checks each length against the mode's table entry. It
+// returns a wrapped ErrUnknownMode for a mode outside the scheme and a
+// wrapped ErrSizeMismatch, naming the mode, object and both sizes, for the
+// first length that is wrong. Asking for an object the scheme does not have
+// is a mismatch.
+func ValidateSizes(scheme Scheme, mode Mode, lengths ...Length) error {
+	sizes, err := Lookup(scheme, mode)
+	if err != nil {
+		return err
+	}
+	for _, l := range lengths {
+		expected := sizes.Expected(l.Field)
+		if expected == 0 {
+			return fmt.Errorf("%w: %s has no %s", ErrSizeMismatch, mode, l.Field)
+		}
+		if l.Got != expected {
+			return fmt.Errorf("%w: expected %s %s size %d, got %d", ErrSizeMismatch, mode, l.Field, expected, l.Got)
+		}
+	}
+	return nil
+}
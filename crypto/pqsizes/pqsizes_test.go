@@ -0,0 +1,128 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pqsizes
+
+import (
+	"testing"
+
+	"github.com/luxfi/crypto/mldsa"
+	"github.com/luxfi/crypto/mlkem"
+	"github.com/luxfi/crypto/slhdsa"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizesTable(t *testing.T) {
+	tests := []struct {
+		scheme Scheme
+		mode   Mode
+		sizes  Sizes
+	}{
+		{MLDSA, MLDSA44, Sizes{PublicKey: 1312, PrivateKey: 2560, Signature: 2420}},
+		{MLDSA, MLDSA65, Sizes{PublicKey: 1952, PrivateKey: 4032, Signature: 3309}},
+		{MLDSA, MLDSA87, Sizes{PublicKey: 2592, PrivateKey: 4896, Signature: 4627}},
+		{MLKEM, MLKEM512, Sizes{PublicKey: 800, PrivateKey: 1632, Ciphertext: 768}},
+		{MLKEM, MLKEM768, Sizes{PublicKey: 1184, PrivateKey: 2400, Ciphertext: 1088}},
+		{MLKEM, MLKEM1024, Sizes{PublicKey: 1568, PrivateKey: 3168, Ciphertext: 1568}},
+		{SLHDSA, SLHDSASHA2_128s, Sizes{PublicKey: 32, PrivateKey: 64, Signature: 7856}},
+		{SLHDSA, SLHDSASHA2_128f, Sizes{PublicKey: 32, PrivateKey: 64, Signature: 17088}},
+		{SLHDSA, SLHDSASHA2_192s, Sizes{PublicKey: 48, PrivateKey: 96, Signature: 16224}},
+		{SLHDSA, SLHDSASHA2_192f, Sizes{PublicKey: 48, PrivateKey: 96, Signature: 35664}},
+		{SLHDSA, SLHDSASHA2_256s, Sizes{PublicKey: 64, PrivateKey: 128, Signature: 29792}},
+		{SLHDSA, SLHDSASHA2_256f, Sizes{PublicKey: 64, PrivateKey: 128, Signature: 49856}},
+		{SLHDSA, SLHDSASHAKE_128s, Sizes{PublicKey: 32, PrivateKey: 64, Signature: 7856}},
+		{SLHDSA, SLHDSASHAKE_128f, Sizes{PublicKey: 32, PrivateKey: 64, Signature: 17088}},
+		{SLHDSA, SLHDSASHAKE_192s, Sizes{PublicKey: 48, PrivateKey: 96, Signature: 16224}},
+		{SLHDSA, SLHDSASHAKE_192f, Sizes{PublicKey: 48, PrivateKey: 96, Signature: 35664}},
+		{SLHDSA, SLHDSASHAKE_256s, Sizes{PublicKey: 64, PrivateKey: 128, Signature: 29792}},
+		{SLHDSA, SLHDSASHAKE_256f, Sizes{PublicKey: 64, PrivateKey: 128, Signature: 49856}},
+		{Falcon, Falcon512, Sizes{PublicKey: 897, PrivateKey: 1281, Signature: 666}},
+		{Falcon, Falcon1024, Sizes{PublicKey: 1793, PrivateKey: 2305, Signature: 1280}},
+	}
+
+	// Every table entry is covered
+	require.Len(t, tests, len(table))
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			require := require.New(t)
+
+			sizes, err := Lookup(tt.scheme, tt.mode)
+			require.NoError(err)
+			require.Equal(tt.sizes, sizes)
+			require.Contains(Modes(tt.scheme), tt.mode)
+
+			var lengths []Length
+			for _, f := range []Field{FieldPublicKey, FieldPrivateKey, FieldSignature, FieldCiphertext} {
+				expected := sizes.Expected(f)
+				if expected == 0 {
+					// The scheme has no such object
+					require.ErrorIs(ValidateSizes(tt.scheme, tt.mode, Length{f, 0}), ErrSizeMismatch)
+					continue
+				}
+				lengths = append(lengths, Length{f, expected})
+
+				for _, wrong := range []int{0, expected - 1, expected + 1} {
+					err := ValidateSizes(tt.scheme, tt.mode, Length{f, wrong})
+					require.ErrorIs(err, ErrSizeMismatch)
+					require.ErrorContains(err, string(tt.mode))
+					require.ErrorContains(err, f.String())
+				}
+			}
+			require.NoError(ValidateSizes(tt.scheme, tt.mode, lengths...))
+		})
+	}
+}
+
+func TestSizesMatchLibraries(t *testing.T) {
+	require := require.New(t)
+
+	mldsaModes := map[Mode]mldsa.Mode{MLDSA44: mldsa.MLDSA44, MLDSA65: mldsa.MLDSA65, MLDSA87: mldsa.MLDSA87}
+	mldsaPrivate := map[Mode]int{
+		MLDSA44: mldsa.MLDSA44PrivateKeySize,
+		MLDSA65: mldsa.MLDSA65PrivateKeySize,
+		MLDSA87: mldsa.MLDSA87PrivateKeySize,
+	}
+	for mode, m := range mldsaModes {
+		require.NoError(ValidateSizes(MLDSA, mode,
+			PublicKey(mldsa.GetPublicKeySize(m)),
+			PrivateKey(mldsaPrivate[mode]),
+			Signature(mldsa.GetSignatureSize(m)),
+		), mode)
+	}
+
+	mlkemSizes := map[Mode][3]int{
+		MLKEM512:  {mlkem.MLKEM512PublicKeySize, mlkem.MLKEM512PrivateKeySize, mlkem.MLKEM512CiphertextSize},
+		MLKEM768:  {mlkem.MLKEM768PublicKeySize, mlkem.MLKEM768PrivateKeySize, mlkem.MLKEM768CiphertextSize},
+		MLKEM1024: {mlkem.MLKEM1024PublicKeySize, mlkem.MLKEM1024PrivateKeySize, mlkem.MLKEM1024CiphertextSize},
+	}
+	for mode, s := range mlkemSizes {
+		require.NoError(ValidateSizes(MLKEM, mode, PublicKey(s[0]), PrivateKey(s[1]), Ciphertext(s[2])), mode)
+	}
+
+	slhdsaModes := map[Mode]slhdsa.Mode{
+		SLHDSASHA2_128s: slhdsa.SHA2_128s, SLHDSASHA2_128f: slhdsa.SHA2_128f,
+		SLHDSASHA2_192s: slhdsa.SHA2_192s, SLHDSASHA2_192f: slhdsa.SHA2_192f,
+		SLHDSASHA2_256s: slhdsa.SHA2_256s, SLHDSASHA2_256f: slhdsa.SHA2_256f,
+		SLHDSASHAKE_128s: slhdsa.SHAKE_128s, SLHDSASHAKE_128f: slhdsa.SHAKE_128f,
+		SLHDSASHAKE_192s: slhdsa.SHAKE_192s, SLHDSASHAKE_192f: slhdsa.SHAKE_192f,
+		SLHDSASHAKE_256s: slhdsa.SHAKE_256s, SLHDSASHAKE_256f: slhdsa.SHAKE_256f,
+	}
+	require.Len(Modes(SLHDSA), len(slhdsaModes))
+	for mode, m := range slhdsaModes {
+		require.NoError(ValidateSizes(SLHDSA, mode,
+			PublicKey(slhdsa.GetPublicKeySize(m)),
+			Signature(slhdsa.GetSignatureSize(m)),
+		), mode)
+	}
+}
+
+func TestValidateSizesUnknownMode(t *testing.T) {
+	require := require.New(t)
+
+	require.ErrorIs(ValidateSizes(MLDSA, "ML-DSA-99"), ErrUnknownMode)
+	// A mode is only valid under its own scheme
+	require.ErrorIs(ValidateSizes(SLHDSA, MLDSA65, PublicKey(1952)), ErrUnknownMode)
+	_, err := Lookup(Falcon, MLKEM768)
+	require.ErrorIs(err, ErrUnknownMode)
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+)
+
+// BridgeMethod identifies how a leg of a transfer crosses chains
+type BridgeMethod uint8
+
+const (
+	BridgeMethodWarp     BridgeMethod = iota // Lock on source, mint on Warp finality
+	BridgeMethodTeleport                     // LP-fronted instant teleport
+)
+
+// BridgeLeg is one part of a routed transfer
+type BridgeLeg struct {
+	Method    BridgeMethod
+	Recipient common.Address
+	Amount    *big.Int // Amount sent through this path
+	Fee       *big.Int // Fee charged by this path
+}
+
+// BridgeRouter selects bridge paths for cross-chain transfers
+//
+// Transfers up to InstantThreshold are sent by teleport when LP liquidity
+// allows. Larger transfers go through Warp lock/mint up to the token's
+// per-transaction limit, and any remainder is split onto teleport
+// liquidity.
+type BridgeRouter struct {
+	Bridge           *TeleportBridge
+	SourceChain      uint32   // Chain transfers originate on
+	InstantThreshold *big.Int // Largest amount preferring teleport
+}
+
+// NewBridgeRouter creates a router over bridge for transfers from sourceChain
+func NewBridgeRouter(bridge *TeleportBridge, sourceChain uint32, instantThreshold *big.Int) *BridgeRouter {
+	return &BridgeRouter{
+		Bridge:           bridge,
+		SourceChain:      sourceChain,
+		InstantThreshold: new(big.Int).Set(instantThreshold),
+	}
+}
+
+// Route plans a transfer of amount to destAddr on destChain. maxSlippage
+// bounds total fees in basis points of amount.
+func (br *BridgeRouter) Route(
+	token common.Address,
+	amount *big.Int,
+	destChain uint32,
+	destAddr common.Address,
+	maxSlippage uint32,
+) ([]BridgeLeg, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	tb := br.Bridge
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	if !tb.isChainSupported(destChain) {
+		return nil, ErrInvalidChainID
+	}
+
+	warpCap, warpMin := br.warpLimits(token)
+	instantCap := br.instantCapacity(token, destChain)
+
+	var legs []BridgeLeg
+	if amount.Cmp(br.InstantThreshold) <= 0 && instantCap.Cmp(amount) >= 0 {
+		legs = append(legs, br.teleportLeg(destAddr, amount))
+	} else {
+		remaining := new(big.Int).Set(amount)
+
+		if warpAmount := minBig(remaining, warpCap); warpAmount.Sign() > 0 && warpAmount.Cmp(warpMin) >= 0 {
+			legs = append(legs, BridgeLeg{
+				Method:    BridgeMethodWarp,
+				Recipient: destAddr,
+				Amount:    warpAmount,
+				Fee:       tb.calculateFee(warpAmount),
+			})
+			remaining.Sub(remaining, warpAmount)
+		}
+
+		if remaining.Sign() > 0 {
+			if instantCap.Cmp(remaining) < 0 {
+				return nil, ErrNoRouteFound
+			}
+			legs = append(legs, br.teleportLeg(destAddr, remaining))
+		}
+	}
+
+	if len(legs) == 0 {
+		return nil, ErrNoRouteFound
+	}
+
+	// fees * 10000 <= amount * maxSlippage
+	fees := big.NewInt(0)
+	for _, leg := range legs {
+		fees.Add(fees, leg.Fee)
+	}
+	fees.Mul(fees, big.NewInt(10000))
+	if fees.Cmp(new(big.Int).Mul(amount, big.NewInt(int64(maxSlippage)))) > 0 {
+		return nil, ErrSlippageExceeded
+	}
+
+	return legs, nil
+}
+
+// warpLimits returns the most and least one Warp lock/mint can carry
+// (zero capacity if the token is not bridgeable)
+func (br *BridgeRouter) warpLimits(token common.Address) (capacity, minimum *big.Int) {
+	config := br.Bridge.getTokenConfig(br.SourceChain, token)
+	if config == nil || config.IsPaused {
+		return big.NewInt(0), big.NewInt(0)
+	}
+	return config.SingleTxLimit, config.MinAmount
+}
+
+// instantCapacity is the LP liquidity available on the destination chain
+func (br *BridgeRouter) instantCapacity(token common.Address, destChain uint32) *big.Int {
+	liquidity := br.Bridge.Liquidity[destChain][token]
+	if liquidity == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(liquidity)
+}
+
+func (br *BridgeRouter) teleportLeg(recipient common.Address, amount *big.Int) BridgeLeg {
+	fee := new(big.Int).Mul(amount, big.NewInt(int64(br.Bridge.InstantFeeRate)))
+	fee.Div(fee, big.NewInt(10000))
+	return BridgeLeg{
+		Method:    BridgeMethodTeleport,
+		Recipient: recipient,
+		Amount:    new(big.Int).Set(amount),
+		Fee:       fee,
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+)
+
+// newTestBridgeRouter routes ChainETH -> ChainLux with a 10,000 Warp
+// per-transaction limit, 500 of LP liquidity and a 100 instant threshold
+func newTestBridgeRouter(t *testing.T) *BridgeRouter {
+	t.Helper()
+
+	now := int64(1000)
+	tb := newTestTeleportBridge(t, &now)
+	tb.MinFee = big.NewInt(0)
+	return NewBridgeRouter(tb, ChainETH, big.NewInt(100))
+}
+
+func TestBridgeRouter_SmallTransferUsesTeleport(t *testing.T) {
+	br := newTestBridgeRouter(t)
+
+	legs, err := br.Route(testTeleportToken, big.NewInt(100), ChainLux, testUser1, 100)
+	if err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+	if len(legs) != 1 || legs[0].Method != BridgeMethodTeleport {
+		t.Fatalf("expected a single teleport leg, got %+v", legs)
+	}
+	if legs[0].Amount.Int64() != 100 || legs[0].Recipient != testUser1 {
+		t.Fatalf("unexpected leg: %+v", legs[0])
+	}
+
+	// Above the threshold a transfer within the Warp limit goes by Warp
+	legs, err = br.Route(testTeleportToken, big.NewInt(5000), ChainLux, testUser1, 100)
+	if err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+	if len(legs) != 1 || legs[0].Method != BridgeMethodWarp {
+		t.Fatalf("expected a single warp leg, got %+v", legs)
+	}
+}
+
+func TestBridgeRouter_LargeTransferIsSplit(t *testing.T) {
+	br := newTestBridgeRouter(t)
+
+	legs, err := br.Route(testTeleportToken, big.NewInt(10_400), ChainLux, testUser1, 100)
+	if err != nil {
+		t.Fatalf("Route failed: %v", err)
+	}
+	if len(legs) != 2 {
+		t.Fatalf("expected 2 legs, got %d", len(legs))
+	}
+	if legs[0].Method != BridgeMethodWarp || legs[0].Amount.Int64() != 10_000 {
+		t.Fatalf("expected warp leg of 10000, got %+v", legs[0])
+	}
+	if legs[1].Method != BridgeMethodTeleport || legs[1].Amount.Int64() != 400 {
+		t.Fatalf("expected teleport leg of 400, got %+v", legs[1])
+	}
+
+	// Tight slippage rejects the 0.3% Warp fee
+	if _, err := br.Route(testTeleportToken, big.NewInt(10_400), ChainLux, testUser1, 10); err != ErrSlippageExceeded {
+		t.Fatalf("expected ErrSlippageExceeded, got %v", err)
+	}
+}
+
+func TestBridgeRouter_NoRoute(t *testing.T) {
+	br := newTestBridgeRouter(t)
+
+	// Exceeds Warp limit plus LP liquidity
+	if _, err := br.Route(testTeleportToken, big.NewInt(10_501), ChainLux, testUser1, 100); err != ErrNoRouteFound {
+		t.Fatalf("expected ErrNoRouteFound, got %v", err)
+	}
+
+	// Unsupported token with no LP liquidity
+	if _, err := br.Route(testBookBase.Address, big.NewInt(50), ChainLux, testUser1, 100); err != ErrNoRouteFound {
+		t.Fatalf("expected ErrNoRouteFound for unknown token, got %v", err)
+	}
+
+	if _, err := br.Route(testTeleportToken, big.NewInt(50), 999, testUser1, 100); err != ErrInvalidChainID {
+		t.Fatalf("expected ErrInvalidChainID, got %v", err)
+	}
+}
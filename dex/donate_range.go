@@ -0,0 +1,214 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Band donations are accounted with tick-indexed accumulators kept in state,
+// so neither donating nor collecting walks the pool's positions or past
+// donations.
+//
+// A position [l, u) overlaps a band [a, b) iff a < u and b > l. Positions
+// with u <= a all have l < b, so the liquidity overlapping a band is
+//
+//	L(lower < b) - L(upper <= a)
+//
+// and, symmetrically, the band growth a position has earned is
+//
+//	G(band lower < u) - G(band upper <= l)
+//
+// Each term is a prefix sum over ticks, held in a Fenwick tree keyed by
+// tick: liquidity by position lower and upper tick, and donated growth per
+// unit of liquidity by band lower and upper tick. Updates and prefix sums
+// touch at most log2(MaxTick-MinTick+1) = 21 slots.
+
+// rangeDonationPrefix prefixes the storage keys of the band accumulators
+var rangeDonationPrefix = []byte("rdon")
+
+// Fenwick trees kept per pool
+const (
+	rangeTreeLiqLower = "liqLower" // position liquidity by lower tick
+	rangeTreeLiqUpper = "liqUpper" // position liquidity by upper tick
+	rangeTreeG0Lower  = "g0Lower"  // band growth of currency0 by lower tick
+	rangeTreeG0Upper  = "g0Upper"  // band growth of currency0 by upper tick
+	rangeTreeG1Lower  = "g1Lower"  // band growth of currency1 by lower tick
+	rangeTreeG1Upper  = "g1Upper"  // band growth of currency1 by upper tick
+)
+
+// rangeTreeSize is the number of ticks a tree indexes
+const rangeTreeSize = int(MaxTick-MinTick) + 1
+
+// DonateToRange donates to the liquidity providers whose positions overlap
+// [tickLower, tickUpper), in proportion to their liquidity. Unlike Donate,
+// the current price does not matter: the donation accrues to the band's
+// own fee growth, so an out-of-range band is rewarded just as an in-range
+// one is. Positions collect their share into TokensOwed the next time
+// their liquidity is modified.
+func (pm *PoolManager) DonateToRange(
+	stateDB StateDB,
+	key PoolKey,
+	tickLower, tickUpper int24,
+	amount0, amount1 *big.Int,
+	hookData []byte,
+) (BalanceDelta, error) {
+	locker := pm.getCurrentLocker()
+	if locker == (common.Address{}) {
+		return ZeroBalanceDelta(), ErrUnauthorized
+	}
+
+	if tickLower >= tickUpper {
+		return ZeroBalanceDelta(), ErrInvalidTickRange
+	}
+	if tickLower < MinTick || tickUpper > MaxTick {
+		return ZeroBalanceDelta(), ErrTickOutOfRange
+	}
+	if amount0 == nil {
+		amount0 = big.NewInt(0)
+	}
+	if amount1 == nil {
+		amount1 = big.NewInt(0)
+	}
+	if amount0.Sign() < 0 || amount1.Sign() < 0 {
+		return ZeroBalanceDelta(), ErrInvalidAmount
+	}
+
+	poolId := key.ID()
+	pool := pm.getPool(stateDB, poolId)
+	if !pool.IsInitialized() {
+		return ZeroBalanceDelta(), ErrPoolNotInitialized
+	}
+
+	if key.Hooks != (common.Address{}) {
+		if err := pm.callHook(stateDB, key.Hooks, HookBeforeDonate, key, amount0, amount1, hookData); err != nil {
+			return ZeroBalanceDelta(), err
+		}
+	}
+
+	liquidity := pm.bandLiquidity(stateDB, poolId, tickLower, tickUpper)
+	if liquidity.Sign() == 0 {
+		return ZeroBalanceDelta(), ErrNoLiquidity
+	}
+
+	// growth = amount * 2^128 / overlapping liquidity, recorded at both
+	// band edges
+	for _, leg := range []struct {
+		amount       *big.Int
+		lower, upper string
+	}{
+		{amount0, rangeTreeG0Lower, rangeTreeG0Upper},
+		{amount1, rangeTreeG1Lower, rangeTreeG1Upper},
+	} {
+		if leg.amount.Sign() == 0 {
+			continue
+		}
+		growth := new(big.Int).Mul(leg.amount, Q128)
+		growth.Div(growth, liquidity)
+		pm.rangeTreeAdd(stateDB, poolId, leg.lower, tickLower, growth)
+		pm.rangeTreeAdd(stateDB, poolId, leg.upper, tickUpper, growth)
+	}
+
+	delta := NewBalanceDelta(amount0, amount1)
+	pm.updateDelta(locker, key.Currency0, amount0)
+	pm.updateDelta(locker, key.Currency1, amount1)
+
+	if key.Hooks != (common.Address{}) {
+		if err := pm.callHook(stateDB, key.Hooks, HookAfterDonate, key, amount0, amount1, delta, hookData); err != nil {
+			return ZeroBalanceDelta(), err
+		}
+	}
+
+	return delta, nil
+}
+
+// bandLiquidity returns the liquidity of the pool's positions overlapping
+// [tickLower, tickUpper)
+func (pm *PoolManager) bandLiquidity(stateDB StateDB, poolId [32]byte, tickLower, tickUpper int24) *big.Int {
+	liquidity := pm.rangeTreePrefix(stateDB, poolId, rangeTreeLiqLower, tickUpper-1)
+	return liquidity.Sub(liquidity, pm.rangeTreePrefix(stateDB, poolId, rangeTreeLiqUpper, tickLower))
+}
+
+// rangeGrowthInside returns the band growth per unit of liquidity donated
+// to bands overlapping [tickLower, tickUpper) since the pool was created
+func (pm *PoolManager) rangeGrowthInside(stateDB StateDB, poolId [32]byte, tickLower, tickUpper int24) (*big.Int, *big.Int) {
+	growth0 := pm.rangeTreePrefix(stateDB, poolId, rangeTreeG0Lower, tickUpper-1)
+	growth0.Sub(growth0, pm.rangeTreePrefix(stateDB, poolId, rangeTreeG0Upper, tickLower))
+	growth1 := pm.rangeTreePrefix(stateDB, poolId, rangeTreeG1Lower, tickUpper-1)
+	growth1.Sub(growth1, pm.rangeTreePrefix(stateDB, poolId, rangeTreeG1Upper, tickLower))
+	return growth0, growth1
+}
+
+// accrueRangeDonations credits a position on [tickLower, tickUpper) with the
+// band donations made since it was last credited, at its current liquidity.
+// It must run before any change to the position's liquidity.
+func (pm *PoolManager) accrueRangeDonations(stateDB StateDB, poolId [32]byte, pos *Position, tickLower, tickUpper int24) {
+	growth0, growth1 := pm.rangeGrowthInside(stateDB, poolId, tickLower, tickUpper)
+
+	if pos.Liquidity.Sign() > 0 {
+		owed0 := new(big.Int).Sub(growth0, pos.RangeGrowthInside0LastX128)
+		owed0.Mul(owed0, pos.Liquidity).Div(owed0, Q128)
+		pos.TokensOwed0 = new(big.Int).Add(pos.TokensOwed0, owed0)
+
+		owed1 := new(big.Int).Sub(growth1, pos.RangeGrowthInside1LastX128)
+		owed1.Mul(owed1, pos.Liquidity).Div(owed1, Q128)
+		pos.TokensOwed1 = new(big.Int).Add(pos.TokensOwed1, owed1)
+	}
+	pos.RangeGrowthInside0LastX128 = growth0
+	pos.RangeGrowthInside1LastX128 = growth1
+}
+
+// updateRangeLiquidity records a change in the liquidity of a position on
+// [tickLower, tickUpper)
+func (pm *PoolManager) updateRangeLiquidity(stateDB StateDB, poolId [32]byte, tickLower, tickUpper int24, liquidityDelta *big.Int) {
+	if liquidityDelta.Sign() == 0 {
+		return
+	}
+	pm.rangeTreeAdd(stateDB, poolId, rangeTreeLiqLower, tickLower, liquidityDelta)
+	pm.rangeTreeAdd(stateDB, poolId, rangeTreeLiqUpper, tickUpper, liquidityDelta)
+}
+
+// rangeTreeAdd adds delta at tick in one of the pool's Fenwick trees. Every
+// node stays non-negative because it sums non-negative entries.
+func (pm *PoolManager) rangeTreeAdd(stateDB StateDB, poolId [32]byte, tree string, tick int24, delta *big.Int) {
+	for i := rangeTreeIndex(tick); i <= rangeTreeSize; i += i & -i {
+		slot := rangeTreeSlot(poolId, tree, i)
+		node := new(big.Int).SetBytes(stateDB.GetState(poolManagerAddr, slot).Bytes())
+		node.Add(node, delta)
+
+		var nodeHash common.Hash
+		node.FillBytes(nodeHash[:])
+		stateDB.SetState(poolManagerAddr, slot, nodeHash)
+	}
+}
+
+// rangeTreePrefix returns the sum of the entries at ticks <= tick in one of
+// the pool's Fenwick trees
+func (pm *PoolManager) rangeTreePrefix(stateDB StateDB, poolId [32]byte, tree string, tick int24) *big.Int {
+	sum := big.NewInt(0)
+	if tick < MinTick {
+		return sum
+	}
+	for i := rangeTreeIndex(min(tick, MaxTick)); i > 0; i -= i & -i {
+		sum.Add(sum, new(big.Int).SetBytes(stateDB.GetState(poolManagerAddr, rangeTreeSlot(poolId, tree, i)).Bytes()))
+	}
+	return sum
+}
+
+// rangeTreeIndex maps a tick to its 1-based Fenwick index
+func rangeTreeIndex(tick int24) int {
+	return int(tick-MinTick) + 1
+}
+
+// rangeTreeSlot derives the storage slot of one Fenwick node
+func rangeTreeSlot(poolId [32]byte, tree string, index int) common.Hash {
+	id := make([]byte, 0, len(poolId)+len(tree)+4)
+	id = append(id, poolId[:]...)
+	id = append(id, tree...)
+	id = binary.BigEndian.AppendUint32(id, uint32(index))
+	return makeStorageKey(rangeDonationPrefix, id)
+}
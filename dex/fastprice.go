@@ -0,0 +1,149 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sync"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+)
+
+// DefaultFastPriceMaxStaleness is how long a keeper price stays valid (blocks)
+const DefaultFastPriceMaxStaleness uint64 = 150
+
+// fastPriceDomain prefixes the digest keepers sign
+var fastPriceDomain = []byte("LX_FAST_PRICE_V1")
+
+// FastPriceFeed is a low-latency price lane for perps (GMX-style fast price)
+// Address: LP-9011 LXOracle
+//
+// Whitelisted keepers push signed prices every block, stamped with the block
+// they were observed in. A price more than MaxStaleness blocks old is
+// ignored and reads fall back to the oracle hub.
+type FastPriceFeed struct {
+	Keepers      map[common.Address]bool // Whitelisted keeper signers
+	MaxStaleness uint64                  // Blocks before a fast price expires
+
+	hub    *OracleHub
+	prices map[common.Address]*priceObservation
+
+	mu sync.RWMutex
+}
+
+// NewFastPriceFeed creates a fast price feed falling back to hub
+func NewFastPriceFeed(hub *OracleHub, maxStaleness uint64) *FastPriceFeed {
+	if maxStaleness == 0 {
+		maxStaleness = DefaultFastPriceMaxStaleness
+	}
+	return &FastPriceFeed{
+		Keepers:      make(map[common.Address]bool),
+		MaxStaleness: maxStaleness,
+		hub:          hub,
+		prices:       make(map[common.Address]*priceObservation),
+	}
+}
+
+// AddKeeper whitelists a keeper signer
+func (fp *FastPriceFeed) AddKeeper(keeper common.Address) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	fp.Keepers[keeper] = true
+}
+
+// RemoveKeeper revokes a keeper signer
+func (fp *FastPriceFeed) RemoveKeeper(keeper common.Address) {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	delete(fp.Keepers, keeper)
+}
+
+// FastPriceDigest returns the hash a keeper signs for a price update
+// keccak256(domain || asset || price (32 bytes) || block (8 bytes))
+func FastPriceDigest(asset common.Address, price *big.Int, block uint64) []byte {
+	var bn [8]byte
+	binary.BigEndian.PutUint64(bn[:], block)
+	return crypto.Keccak256(fastPriceDomain, asset[:], common.LeftPadBytes(price.Bytes(), 32), bn[:])
+}
+
+// SubmitFastPrice records a keeper-signed price observed at block. The
+// 65-byte signature [R || S || V] must recover to a whitelisted keeper, and
+// the block may not be after the current one.
+func (fp *FastPriceFeed) SubmitFastPrice(
+	stateDB StateDB,
+	asset common.Address,
+	price *big.Int,
+	block uint64,
+	signature []byte,
+) error {
+	if price == nil || price.Sign() <= 0 || price.BitLen() > 256 {
+		return ErrInvalidAmount
+	}
+	if len(signature) != 65 {
+		return ErrInvalidSignature
+	}
+
+	sig := make([]byte, 65)
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(FastPriceDigest(asset, price, block), sig)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	signer := common.Address(crypto.PubkeyToAddress(*pub))
+
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+
+	if !fp.Keepers[signer] {
+		return ErrNotKeeper
+	}
+	if block > stateDB.GetBlockNumber() {
+		return ErrFutureReport
+	}
+	if prev := fp.prices[asset]; prev != nil && block < prev.Block {
+		return ErrStaleReport
+	}
+
+	fp.prices[asset] = &priceObservation{
+		Block: block,
+		Price: new(big.Int).Set(price),
+	}
+	return nil
+}
+
+// IsFresh reports whether the asset has a keeper price within MaxStaleness
+// blocks of the current block
+func (fp *FastPriceFeed) IsFresh(stateDB StateDB, asset common.Address) bool {
+	fp.mu.RLock()
+	defer fp.mu.RUnlock()
+
+	return fp.isFresh(stateDB, asset)
+}
+
+// GetFastPrice returns the latest keeper price, or the oracle hub's
+// aggregate price if the keeper price is missing or stale
+func (fp *FastPriceFeed) GetFastPrice(stateDB StateDB, asset common.Address) (*big.Int, error) {
+	fp.mu.RLock()
+	defer fp.mu.RUnlock()
+
+	if fp.isFresh(stateDB, asset) {
+		return new(big.Int).Set(fp.prices[asset].Price), nil
+	}
+	if fp.hub == nil {
+		return nil, ErrPriceUnavailable
+	}
+	return fp.hub.GetPrice(asset)
+}
+
+func (fp *FastPriceFeed) isFresh(stateDB StateDB, asset common.Address) bool {
+	obs := fp.prices[asset]
+	return obs != nil && stateDB.GetBlockNumber() <= obs.Block+fp.MaxStaleness
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+)
+
+// newTestFastPriceFeed returns a feed with one keeper and a hub price of
+// 100 submitted at the current block of stateDB
+func newTestFastPriceFeed(t *testing.T, stateDB *MockStateDB) (*FastPriceFeed, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	hub := NewOracleHub()
+	hub.AddSource(testOracleSource1)
+	if err := hub.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), stateDB.GetBlockNumber()); err != nil {
+		t.Fatalf("SubmitPrice failed: %v", err)
+	}
+
+	keeperKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	fp := NewFastPriceFeed(hub, 60)
+	fp.AddKeeper(common.Address(crypto.PubkeyToAddress(keeperKey.PublicKey)))
+	return fp, keeperKey
+}
+
+// signFastPrice signs a price update with key
+func signFastPrice(t *testing.T, key *ecdsa.PrivateKey, asset common.Address, price *big.Int, block uint64) []byte {
+	t.Helper()
+
+	sig, err := crypto.Sign(FastPriceDigest(asset, price, block), key)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	return sig
+}
+
+func TestFastPrice_KeeperUpdate(t *testing.T) {
+	block := uint64(1000)
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(block)
+	fp, keeperKey := newTestFastPriceFeed(t, stateDB)
+
+	price := big.NewInt(105)
+	sig := signFastPrice(t, keeperKey, testOracleAsset, price, block)
+	if err := fp.SubmitFastPrice(stateDB, testOracleAsset, price, block, sig); err != nil {
+		t.Fatalf("SubmitFastPrice failed: %v", err)
+	}
+
+	got, err := fp.GetFastPrice(stateDB, testOracleAsset)
+	if err != nil {
+		t.Fatalf("GetFastPrice failed: %v", err)
+	}
+	if got.Cmp(price) != 0 {
+		t.Fatalf("expected fast price %s, got %s", price, got)
+	}
+	if !fp.IsFresh(stateDB, testOracleAsset) {
+		t.Fatal("price should be fresh")
+	}
+
+	// Signature over a different price does not verify as the keeper
+	err = fp.SubmitFastPrice(stateDB, testOracleAsset, big.NewInt(999), block, sig)
+	if err != ErrNotKeeper && err != ErrInvalidSignature {
+		t.Fatalf("expected tampered update to be rejected, got %v", err)
+	}
+
+	// Updates from a future block are rejected
+	next := signFastPrice(t, keeperKey, testOracleAsset, price, block+1)
+	if err := fp.SubmitFastPrice(stateDB, testOracleAsset, price, block+1, next); err != ErrFutureReport {
+		t.Fatalf("expected ErrFutureReport, got %v", err)
+	}
+
+	// Older updates are rejected
+	old := signFastPrice(t, keeperKey, testOracleAsset, price, block-1)
+	if err := fp.SubmitFastPrice(stateDB, testOracleAsset, price, block-1, old); err != ErrStaleReport {
+		t.Fatalf("expected ErrStaleReport, got %v", err)
+	}
+}
+
+func TestFastPrice_NonKeeperRejected(t *testing.T) {
+	block := uint64(1000)
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(block)
+	fp, _ := newTestFastPriceFeed(t, stateDB)
+
+	otherKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	price := big.NewInt(105)
+	sig := signFastPrice(t, otherKey, testOracleAsset, price, block)
+	if err := fp.SubmitFastPrice(stateDB, testOracleAsset, price, block, sig); err != ErrNotKeeper {
+		t.Fatalf("expected ErrNotKeeper, got %v", err)
+	}
+
+	if err := fp.SubmitFastPrice(stateDB, testOracleAsset, price, block, sig[:64]); err != ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	// Hub price is served since no keeper price was accepted
+	got, _ := fp.GetFastPrice(stateDB, testOracleAsset)
+	if got.Int64() != 100 {
+		t.Fatalf("expected hub price 100, got %s", got)
+	}
+}
+
+func TestFastPrice_StalenessFallback(t *testing.T) {
+	block := uint64(1000)
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(block)
+	fp, keeperKey := newTestFastPriceFeed(t, stateDB)
+
+	price := big.NewInt(105)
+	sig := signFastPrice(t, keeperKey, testOracleAsset, price, block)
+	if err := fp.SubmitFastPrice(stateDB, testOracleAsset, price, block, sig); err != nil {
+		t.Fatalf("SubmitFastPrice failed: %v", err)
+	}
+
+	// Still valid at the edge of the window
+	stateDB.blockNumber += fp.MaxStaleness
+	got, _ := fp.GetFastPrice(stateDB, testOracleAsset)
+	if got.Int64() != 105 {
+		t.Fatalf("expected fast price 105 at staleness edge, got %s", got)
+	}
+
+	// Expired: falls back to the hub
+	stateDB.blockNumber++
+	if fp.IsFresh(stateDB, testOracleAsset) {
+		t.Fatal("price should be stale")
+	}
+	got, err := fp.GetFastPrice(stateDB, testOracleAsset)
+	if err != nil {
+		t.Fatalf("GetFastPrice failed: %v", err)
+	}
+	if got.Int64() != 100 {
+		t.Fatalf("expected hub fallback price 100, got %s", got)
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sync"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Precompile address (LP-6221 FeeGov)
+var feeGovAddr = common.HexToAddress(FeeGovAddress)
+
+// FeeGov lets a pool's liquidity providers govern its protocol fee. An LP's
+// voting power on a proposal is the value of the positions it held in the
+// pool before the proposal's creation block, read from the pool manager's
+// voting checkpoints. Liquidity added in or after that block, including
+// flash loaned liquidity, does not count.
+//
+// Positions are valued in currency1 at the pool price of the checkpoint
+// that added them, so a narrow range does not outvote a wide one holding
+// the same tokens just because it has more raw liquidity. Removing
+// liquidity releases the same share of the recorded value.
+//
+// A proposal passes when, after its voting period, the votes cast reach
+// QuorumBps of the pool's snapshot voting power and more of them support it
+// than oppose it. It can be executed once the timelock has also elapsed;
+// execution sets the pool's protocol fee, so the pool manager's protocol
+// fee controller must be FeeGovAddress.
+type FeeGov struct {
+	mu sync.Mutex
+
+	poolManager *PoolManager
+
+	QuorumBps    uint64 // Share of snapshot liquidity that must vote, in bps
+	VotingPeriod uint64 // Blocks after creation during which votes are accepted
+	Timelock     uint64 // Blocks after the voting period before execution
+
+	proposals      map[uint64]*FeeProposal
+	nextProposalID uint64
+}
+
+// NewFeeGov creates fee governance over pm's pools
+func NewFeeGov(pm *PoolManager, quorumBps, votingPeriod, timelock uint64) (*FeeGov, error) {
+	if quorumBps == 0 || quorumBps > FeePrecision || votingPeriod == 0 {
+		return nil, ErrInvalidParameter
+	}
+	return &FeeGov{
+		poolManager:    pm,
+		QuorumBps:      quorumBps,
+		VotingPeriod:   votingPeriod,
+		Timelock:       timelock,
+		proposals:      make(map[uint64]*FeeProposal),
+		nextProposalID: 1,
+	}, nil
+}
+
+// Propose opens a proposal to set key's protocol fee, in millionths of the
+// swap fee. The proposer must hold liquidity in the pool at the snapshot.
+func (g *FeeGov) Propose(
+	stateDB StateDB,
+	proposer common.Address,
+	key PoolKey,
+	protocolFee uint24,
+) (uint64, error) {
+	if protocolFee > MaxProtocolFee {
+		return 0, ErrInvalidFee
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	poolId := key.ID()
+	if !g.poolManager.getPool(stateDB, poolId).IsInitialized() {
+		return 0, ErrPoolNotInitialized
+	}
+
+	snapshot := stateDB.GetBlockNumber()
+	if g.poolManager.VotingPowerBefore(stateDB, poolId, proposer, snapshot).Sign() <= 0 {
+		return 0, ErrNoVotingPower
+	}
+
+	proposal := &FeeProposal{
+		ID:            g.nextProposalID,
+		Proposer:      proposer,
+		Pool:          key,
+		ProtocolFee:   protocolFee,
+		SnapshotBlock: snapshot,
+		VoteEnd:       snapshot + g.VotingPeriod,
+		ForVotes:      big.NewInt(0),
+		AgainstVotes:  big.NewInt(0),
+		voted:         make(map[common.Address]bool),
+	}
+	g.proposals[proposal.ID] = proposal
+	g.nextProposalID++
+	return proposal.ID, nil
+}
+
+// Vote casts voter's snapshot voting power for or against a proposal
+func (g *FeeGov) Vote(stateDB StateDB, voter common.Address, proposalID uint64, support bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	proposal, exists := g.proposals[proposalID]
+	if !exists {
+		return ErrProposalNotFound
+	}
+	if stateDB.GetBlockNumber() > proposal.VoteEnd {
+		return ErrVotingClosed
+	}
+	if proposal.voted[voter] {
+		return ErrAlreadyVoted
+	}
+
+	power := g.poolManager.VotingPowerBefore(stateDB, proposal.Pool.ID(), voter, proposal.SnapshotBlock)
+	if power.Sign() <= 0 {
+		return ErrNoVotingPower
+	}
+
+	proposal.voted[voter] = true
+	if support {
+		proposal.ForVotes.Add(proposal.ForVotes, power)
+	} else {
+		proposal.AgainstVotes.Add(proposal.AgainstVotes, power)
+	}
+	return nil
+}
+
+// Execute applies a passed proposal's fee change once its voting period and
+// timelock have elapsed
+func (g *FeeGov) Execute(stateDB StateDB, proposalID uint64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	proposal, exists := g.proposals[proposalID]
+	if !exists {
+		return ErrProposalNotFound
+	}
+	if proposal.Executed {
+		return ErrProposalExecuted
+	}
+	if stateDB.GetBlockNumber() <= proposal.VoteEnd+g.Timelock {
+		return ErrTimelockActive
+	}
+
+	total := g.poolManager.TotalVotingPowerBefore(stateDB, proposal.Pool.ID(), proposal.SnapshotBlock)
+	quorum := new(big.Int).Mul(total, new(big.Int).SetUint64(g.QuorumBps))
+	quorum.Div(quorum, big.NewInt(FeePrecision))
+	cast := new(big.Int).Add(proposal.ForVotes, proposal.AgainstVotes)
+	if cast.Cmp(quorum) < 0 {
+		return ErrQuorumNotReached
+	}
+	if proposal.ForVotes.Cmp(proposal.AgainstVotes) <= 0 {
+		return ErrProposalDefeated
+	}
+
+	if err := g.poolManager.SetProtocolFee(stateDB, feeGovAddr, proposal.Pool, proposal.ProtocolFee); err != nil {
+		return err
+	}
+	proposal.Executed = true
+	return nil
+}
+
+// GetProposal returns a proposal by ID
+func (g *FeeGov) GetProposal(proposalID uint64) (*FeeProposal, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	proposal, exists := g.proposals[proposalID]
+	if !exists {
+		return nil, ErrProposalNotFound
+	}
+	return proposal, nil
+}
+
+// =========================================================================
+// Voting Checkpoints
+// =========================================================================
+
+// votingCheckpointPrefix prefixes the storage keys of voting checkpoints.
+// Each series (one per pool owner, plus the pool total) is an array of
+// (block, balance) entries in block order:
+//
+//	series || "n"               -> entry count
+//	series || i || "b" / "v"    -> entry i's block and balance
+var votingCheckpointPrefix = []byte("vote")
+
+// positionValue values liquidity over [tickLower, tickUpper) at
+// sqrtPriceX96 in units of currency1: amount1 + amount0 * price
+func positionValue(sqrtPriceX96 *big.Int, tickLower, tickUpper int24, liquidity *big.Int) *big.Int {
+	sqrtLower, err := GetSqrtRatioAtTick(tickLower)
+	if err != nil {
+		return big.NewInt(0)
+	}
+	sqrtUpper, err := GetSqrtRatioAtTick(tickUpper)
+	if err != nil {
+		return big.NewInt(0)
+	}
+	amount0, amount1, err := GetAmountsForLiquidity(sqrtPriceX96, sqrtLower, sqrtUpper, liquidity)
+	if err != nil {
+		return big.NewInt(0)
+	}
+	value := new(big.Int).Mul(amount0, sqrtPriceX96)
+	value.Mul(value, sqrtPriceX96)
+	value.Rsh(value, 192)
+	return value.Add(value, amount1)
+}
+
+// votingValueDelta returns the change in a position's voting value from
+// applying params at sqrtPriceX96 and records the position's new value.
+// It must run before the position's liquidity changes.
+func (pm *PoolManager) votingValueDelta(
+	stateDB StateDB,
+	sqrtPriceX96 *big.Int,
+	positionKey [32]byte,
+	pos *Position,
+	params ModifyLiquidityParams,
+) *big.Int {
+	value := pm.getPositionVotingValue(stateDB, positionKey)
+
+	var delta *big.Int
+	switch {
+	case params.LiquidityDelta.Sign() >= 0:
+		delta = positionValue(sqrtPriceX96, params.TickLower, params.TickUpper, params.LiquidityDelta)
+	case pos.Liquidity.Sign() <= 0:
+		delta = big.NewInt(0)
+	case new(big.Int).Add(pos.Liquidity, params.LiquidityDelta).Sign() <= 0:
+		delta = new(big.Int).Neg(value)
+	default:
+		delta = new(big.Int).Mul(value, params.LiquidityDelta)
+		delta.Quo(delta, pos.Liquidity)
+	}
+
+	pm.setPositionVotingValue(stateDB, positionKey, new(big.Int).Add(value, delta))
+	return delta
+}
+
+// getPositionVotingValue returns the voting value recorded for a position
+func (pm *PoolManager) getPositionVotingValue(stateDB StateDB, positionKey [32]byte) *big.Int {
+	key := makeStorageKey(positionPrefix, append(positionKey[:], []byte("vote")...))
+	return new(big.Int).SetBytes(stateDB.GetState(poolManagerAddr, key).Bytes())
+}
+
+// setPositionVotingValue records the voting value of a position
+func (pm *PoolManager) setPositionVotingValue(stateDB StateDB, positionKey [32]byte, value *big.Int) {
+	key := makeStorageKey(positionPrefix, append(positionKey[:], []byte("vote")...))
+	var valueHash common.Hash
+	value.FillBytes(valueHash[:])
+	stateDB.SetState(poolManagerAddr, key, valueHash)
+}
+
+// checkpointVotingPower adds delta to owner's and the pool's checkpointed
+// voting power as of the current block
+func (pm *PoolManager) checkpointVotingPower(stateDB StateDB, poolId [32]byte, owner common.Address, delta *big.Int) {
+	if delta.Sign() == 0 {
+		return
+	}
+	block := stateDB.GetBlockNumber()
+	writeCheckpoint(stateDB, ownerCheckpointSeries(poolId, owner), block, delta)
+	writeCheckpoint(stateDB, totalCheckpointSeries(poolId), block, delta)
+}
+
+// ownerCheckpointSeries identifies owner's checkpoints in a pool
+func ownerCheckpointSeries(poolId [32]byte, owner common.Address) []byte {
+	return append(append(poolId[:], 'o'), owner.Bytes()...)
+}
+
+// totalCheckpointSeries identifies a pool's total checkpoints
+func totalCheckpointSeries(poolId [32]byte) []byte {
+	return append(poolId[:], 't')
+}
+
+// checkpointKey derives the storage key of one field of a checkpoint series
+func checkpointKey(series []byte, index uint64, field byte) common.Hash {
+	id := append([]byte(nil), series...)
+	id = binary.BigEndian.AppendUint64(id, index)
+	return makeStorageKey(votingCheckpointPrefix, append(id, field))
+}
+
+// checkpointCount returns the number of checkpoints in a series
+func checkpointCount(stateDB StateDB, series []byte) uint64 {
+	key := makeStorageKey(votingCheckpointPrefix, append(append([]byte(nil), series...), 'n'))
+	slot := stateDB.GetState(poolManagerAddr, key)
+	return binary.BigEndian.Uint64(slot[24:])
+}
+
+// checkpointAt returns the block and balance of checkpoint index
+func checkpointAt(stateDB StateDB, series []byte, index uint64) (uint64, *big.Int) {
+	block := stateDB.GetState(poolManagerAddr, checkpointKey(series, index, 'b'))
+	value := stateDB.GetState(poolManagerAddr, checkpointKey(series, index, 'v'))
+	return binary.BigEndian.Uint64(block[24:]), new(big.Int).SetBytes(value[:])
+}
+
+// writeCheckpoint appends the balance after delta at block, or updates the
+// last checkpoint if it is already at block
+func writeCheckpoint(stateDB StateDB, series []byte, block uint64, delta *big.Int) {
+	n := checkpointCount(stateDB, series)
+	value := new(big.Int).Set(delta)
+	index := n
+	if n > 0 {
+		lastBlock, lastValue := checkpointAt(stateDB, series, n-1)
+		value.Add(value, lastValue)
+		if lastBlock == block {
+			index = n - 1
+		}
+	}
+	if value.Sign() < 0 {
+		value.SetInt64(0)
+	}
+
+	var valueHash common.Hash
+	value.FillBytes(valueHash[:])
+	stateDB.SetState(poolManagerAddr, checkpointKey(series, index, 'b'), uint64Hash(block))
+	stateDB.SetState(poolManagerAddr, checkpointKey(series, index, 'v'), valueHash)
+	if index == n {
+		key := makeStorageKey(votingCheckpointPrefix, append(append([]byte(nil), series...), 'n'))
+		stateDB.SetState(poolManagerAddr, key, uint64Hash(n+1))
+	}
+}
+
+// checkpointBefore returns the balance held at the end of the block before
+// block, by binary search over the series
+func checkpointBefore(stateDB StateDB, series []byte, block uint64) *big.Int {
+	lo, hi := uint64(0), checkpointCount(stateDB, series)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if midBlock, _ := checkpointAt(stateDB, series, mid); midBlock >= block {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	if lo == 0 {
+		return big.NewInt(0)
+	}
+	_, value := checkpointAt(stateDB, series, lo-1)
+	return value
+}
+
+// VotingPowerBefore returns the value of the positions owner held in a
+// pool before block, i.e. at the end of block-1
+func (pm *PoolManager) VotingPowerBefore(stateDB StateDB, poolId [32]byte, owner common.Address, block uint64) *big.Int {
+	return checkpointBefore(stateDB, ownerCheckpointSeries(poolId, owner), block)
+}
+
+// TotalVotingPowerBefore returns the value of all positions in a pool
+// before block
+func (pm *PoolManager) TotalVotingPowerBefore(stateDB StateDB, poolId [32]byte, block uint64) *big.Int {
+	return checkpointBefore(stateDB, totalCheckpointSeries(poolId), block)
+}
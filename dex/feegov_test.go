@@ -0,0 +1,283 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+)
+
+var (
+	testGovAlice = common.HexToAddress("0xa11ce00000000000000000000000000000000000")
+	testGovBob   = common.HexToAddress("0xb0b0000000000000000000000000000000000000")
+	testGovCarol = common.HexToAddress("0xca10100000000000000000000000000000000000")
+	testGovDave  = common.HexToAddress("0xda5e000000000000000000000000000000000000")
+)
+
+// govLiquidityUnit scales test liquidity so position values do not round
+// away
+const govLiquidityUnit = 1e12
+
+// addGovLiquidity changes owner's liquidity in key's pool over [-600, 600)
+// as the locker, in govLiquidityUnit
+func addGovLiquidity(t *testing.T, pm *PoolManager, stateDB *MockStateDB, key PoolKey, owner common.Address, delta int64) {
+	t.Helper()
+	addGovRangeLiquidity(t, pm, stateDB, key, owner, -600, 600, delta)
+}
+
+func addGovRangeLiquidity(t *testing.T, pm *PoolManager, stateDB *MockStateDB, key PoolKey, owner common.Address, tickLower, tickUpper int24, delta int64) {
+	t.Helper()
+	pm.lockers = append(pm.lockers, owner)
+	pm.currentDeltas[owner] = make(map[Currency]*big.Int)
+	defer pm.cleanupLocker(owner)
+
+	params := ModifyLiquidityParams{
+		TickLower:      tickLower,
+		TickUpper:      tickUpper,
+		LiquidityDelta: new(big.Int).Mul(big.NewInt(delta), big.NewInt(govLiquidityUnit)),
+	}
+	if _, _, err := pm.ModifyLiquidity(stateDB, key, params, nil); err != nil {
+		t.Fatalf("ModifyLiquidity failed: %v", err)
+	}
+}
+
+// govValue is the currency1 value of liquidity units over [tickLower,
+// tickUpper) at price 1: amount0 + amount1
+func govValue(t *testing.T, tickLower, tickUpper int24, units int64) *big.Int {
+	t.Helper()
+	sqrtLower, _ := GetSqrtRatioAtTick(tickLower)
+	sqrtUpper, _ := GetSqrtRatioAtTick(tickUpper)
+	liquidity := new(big.Int).Mul(big.NewInt(units), big.NewInt(govLiquidityUnit))
+	amount0, amount1, err := GetAmountsForLiquidity(Q96, sqrtLower, sqrtUpper, liquidity)
+	if err != nil {
+		t.Fatalf("GetAmountsForLiquidity failed: %v", err)
+	}
+	return amount0.Add(amount0, amount1)
+}
+
+// newTestFeeGov sets up a pool at price 1 where, from block 10, alice holds
+// 600, bob 300 and carol 100 units of liquidity over the same range, with a 40% quorum, 10 block voting period
+// and 5 block timelock, and opens a proposal by alice at block 20
+func newTestFeeGov(t *testing.T) (*FeeGov, *PoolManager, *MockStateDB, PoolKey, uint64) {
+	t.Helper()
+	pm := newTestPoolManager()
+	pm.protocolFeeController = common.HexToAddress(FeeGovAddress)
+	stateDB := NewMockStateDB()
+	key := newTestPoolKey()
+	if _, err := pm.Initialize(stateDB, key, new(big.Int).Set(Q96), nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	stateDB.SetBlockNumber(10)
+	addGovLiquidity(t, pm, stateDB, key, testGovAlice, 600)
+	addGovLiquidity(t, pm, stateDB, key, testGovBob, 300)
+	addGovLiquidity(t, pm, stateDB, key, testGovCarol, 100)
+
+	gov, err := NewFeeGov(pm, 4000, 10, 5)
+	if err != nil {
+		t.Fatalf("NewFeeGov failed: %v", err)
+	}
+
+	stateDB.SetBlockNumber(20)
+	proposalID, err := gov.Propose(stateDB, testGovAlice, key, 100000)
+	if err != nil {
+		t.Fatalf("Propose failed: %v", err)
+	}
+	return gov, pm, stateDB, key, proposalID
+}
+
+func TestFeeGov_PassesWithQuorum(t *testing.T) {
+	gov, pm, stateDB, key, proposalID := newTestFeeGov(t)
+
+	if err := gov.Vote(stateDB, testGovAlice, proposalID, true); err != nil {
+		t.Fatalf("Vote failed: %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovAlice, proposalID, true); err != ErrAlreadyVoted {
+		t.Fatalf("expected ErrAlreadyVoted, got %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovCarol, proposalID, false); err != nil {
+		t.Fatalf("Vote failed: %v", err)
+	}
+
+	// Not executable until the voting period and timelock are over
+	stateDB.SetBlockNumber(35)
+	if err := gov.Execute(stateDB, proposalID); err != ErrTimelockActive {
+		t.Fatalf("expected ErrTimelockActive, got %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovBob, proposalID, false); err != ErrVotingClosed {
+		t.Fatalf("expected ErrVotingClosed, got %v", err)
+	}
+
+	stateDB.SetBlockNumber(36)
+	if err := gov.Execute(stateDB, proposalID); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if fee := pm.getPool(stateDB, key.ID()).ProtocolFee; fee != 100000 {
+		t.Fatalf("expected protocol fee 100000, got %d", fee)
+	}
+	if err := gov.Execute(stateDB, proposalID); err != ErrProposalExecuted {
+		t.Fatalf("expected ErrProposalExecuted, got %v", err)
+	}
+
+	proposal, _ := gov.GetProposal(proposalID)
+	if proposal.ForVotes.Cmp(govValue(t, -600, 600, 600)) != 0 || proposal.AgainstVotes.Cmp(govValue(t, -600, 600, 100)) != 0 {
+		t.Fatalf("expected alice's and carol's position values, got %s and %s", proposal.ForVotes, proposal.AgainstVotes)
+	}
+}
+
+func TestFeeGov_FailsWithoutQuorum(t *testing.T) {
+	gov, pm, stateDB, key, proposalID := newTestFeeGov(t)
+
+	// 30% of the snapshot value is below the 40% quorum
+	if err := gov.Vote(stateDB, testGovBob, proposalID, true); err != nil {
+		t.Fatalf("Vote failed: %v", err)
+	}
+	stateDB.SetBlockNumber(36)
+	if err := gov.Execute(stateDB, proposalID); err != ErrQuorumNotReached {
+		t.Fatalf("expected ErrQuorumNotReached, got %v", err)
+	}
+	if fee := pm.getPool(stateDB, key.ID()).ProtocolFee; fee != 0 {
+		t.Fatalf("failed proposal should not change the fee, got %d", fee)
+	}
+
+	// Quorum reached but outvoted
+	stateDB.SetBlockNumber(40)
+	defeated, err := gov.Propose(stateDB, testGovBob, key, 50000)
+	if err != nil {
+		t.Fatalf("Propose failed: %v", err)
+	}
+	gov.Vote(stateDB, testGovBob, defeated, true)
+	gov.Vote(stateDB, testGovAlice, defeated, false)
+	stateDB.SetBlockNumber(56)
+	if err := gov.Execute(stateDB, defeated); err != ErrProposalDefeated {
+		t.Fatalf("expected ErrProposalDefeated, got %v", err)
+	}
+}
+
+func TestFeeGov_SnapshotIgnoresLaterLiquidity(t *testing.T) {
+	gov, pm, stateDB, key, proposalID := newTestFeeGov(t)
+
+	// Liquidity added in the proposal's block, as a flash loan would, and
+	// after it does not count; neither does liquidity removed after it
+	addGovLiquidity(t, pm, stateDB, key, testGovCarol, 10_000)
+	addGovLiquidity(t, pm, stateDB, key, testGovDave, 10_000)
+	stateDB.SetBlockNumber(21)
+	addGovLiquidity(t, pm, stateDB, key, testGovCarol, 10_000)
+	addGovLiquidity(t, pm, stateDB, key, testGovAlice, -600)
+
+	if err := gov.Vote(stateDB, testGovDave, proposalID, false); err != ErrNoVotingPower {
+		t.Fatalf("expected ErrNoVotingPower for post-snapshot LP, got %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovCarol, proposalID, false); err != nil {
+		t.Fatalf("Vote failed: %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovAlice, proposalID, true); err != nil {
+		t.Fatalf("Vote failed: %v", err)
+	}
+
+	proposal, _ := gov.GetProposal(proposalID)
+	if proposal.ForVotes.Cmp(govValue(t, -600, 600, 600)) != 0 || proposal.AgainstVotes.Cmp(govValue(t, -600, 600, 100)) != 0 {
+		t.Fatalf("expected snapshot votes, got %s for and %s against", proposal.ForVotes, proposal.AgainstVotes)
+	}
+
+	// Quorum is measured against the snapshot total, not the inflated pool
+	stateDB.SetBlockNumber(36)
+	if err := gov.Execute(stateDB, proposalID); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	total := govValue(t, -600, 600, 600)
+	total.Add(total, govValue(t, -600, 600, 300))
+	total.Add(total, govValue(t, -600, 600, 100))
+	if got := pm.TotalVotingPowerBefore(stateDB, key.ID(), 20); got.Cmp(total) != 0 {
+		t.Fatalf("expected snapshot total %s, got %s", total, got)
+	}
+
+	// Checkpoints live in state: a fresh pool manager reads the same power
+	if got := newTestPoolManager().VotingPowerBefore(stateDB, key.ID(), testGovAlice, 20); got.Cmp(govValue(t, -600, 600, 600)) != 0 {
+		t.Fatalf("expected alice's snapshot power after reload, got %s", got)
+	}
+	if got := newTestPoolManager().VotingPowerBefore(stateDB, key.ID(), testGovAlice, 22); got.Sign() != 0 {
+		t.Fatalf("expected no power after alice withdrew, got %s", got)
+	}
+}
+
+func TestFeeGov_PowerIsPositionValue(t *testing.T) {
+	gov, pm, stateDB, key, _ := newTestFeeGov(t)
+
+	// The same liquidity over a narrower range holds fewer tokens, so it
+	// carries less voting power
+	stateDB.SetBlockNumber(21)
+	addGovRangeLiquidity(t, pm, stateDB, key, testGovDave, -60, 60, 600)
+	stateDB.SetBlockNumber(22)
+
+	dave := pm.VotingPowerBefore(stateDB, key.ID(), testGovDave, 22)
+	alice := pm.VotingPowerBefore(stateDB, key.ID(), testGovAlice, 22)
+	if dave.Cmp(govValue(t, -60, 60, 600)) != 0 {
+		t.Fatalf("expected dave's power to be his position value, got %s", dave)
+	}
+	if dave.Cmp(alice) >= 0 {
+		t.Fatalf("narrow position should weigh less than an equal-liquidity wide one: %s >= %s", dave, alice)
+	}
+
+	// Removing half the liquidity releases half the recorded value
+	addGovRangeLiquidity(t, pm, stateDB, key, testGovDave, -60, 60, -300)
+	stateDB.SetBlockNumber(23)
+	half := new(big.Int).Sub(dave, new(big.Int).Div(dave, big.NewInt(2)))
+	if got := pm.VotingPowerBefore(stateDB, key.ID(), testGovDave, 23); got.Cmp(half) != 0 {
+		t.Fatalf("expected %s after removing half, got %s", half, got)
+	}
+
+	proposalID, err := gov.Propose(stateDB, testGovDave, key, 1000)
+	if err != nil {
+		t.Fatalf("Propose failed: %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovDave, proposalID, true); err != nil {
+		t.Fatalf("Vote failed: %v", err)
+	}
+	if proposal, _ := gov.GetProposal(proposalID); proposal.ForVotes.Cmp(half) != 0 {
+		t.Fatalf("expected %s for, got %s", half, proposal.ForVotes)
+	}
+}
+
+func TestFeeGov_TransferMovesVotingPower(t *testing.T) {
+	gov, pm, stateDB, key, _ := newTestFeeGov(t)
+
+	pm.lockers = append(pm.lockers, testGovBob)
+	positionKey := PositionKey(testGovBob, -600, 600, [32]byte{})
+	if err := pm.TransferPosition(stateDB, positionKey, testGovDave); err != nil {
+		t.Fatalf("TransferPosition failed: %v", err)
+	}
+	pm.cleanupLocker(testGovBob)
+
+	stateDB.SetBlockNumber(22)
+	proposalID, err := gov.Propose(stateDB, testGovDave, key, 1000)
+	if err != nil {
+		t.Fatalf("Propose by new owner failed: %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovBob, proposalID, true); err != ErrNoVotingPower {
+		t.Fatalf("expected ErrNoVotingPower for previous owner, got %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovDave, proposalID, true); err != nil {
+		t.Fatalf("Vote failed: %v", err)
+	}
+}
+
+func TestFeeGov_Invalid(t *testing.T) {
+	gov, _, stateDB, key, _ := newTestFeeGov(t)
+
+	if _, err := NewFeeGov(NewPoolManager(), 0, 10, 5); err != ErrInvalidParameter {
+		t.Fatalf("expected ErrInvalidParameter for zero quorum, got %v", err)
+	}
+	if _, err := gov.Propose(stateDB, testGovDave, key, 1000); err != ErrNoVotingPower {
+		t.Fatalf("expected ErrNoVotingPower, got %v", err)
+	}
+	if _, err := gov.Propose(stateDB, testGovAlice, key, MaxProtocolFee+1); err != ErrInvalidFee {
+		t.Fatalf("expected ErrInvalidFee, got %v", err)
+	}
+	if err := gov.Vote(stateDB, testGovAlice, 99, true); err != ErrProposalNotFound {
+		t.Fatalf("expected ErrProposalNotFound, got %v", err)
+	}
+}
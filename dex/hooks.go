@@ -177,6 +177,22 @@ func (hr *HookRegistry) RegisterHook(addr common.Address, flags HookFlags) error
 	return nil
 }
 
+// hookFlagsMask covers every defined hook flag
+const hookFlagsMask = HookAfterFlash<<1 - 1
+
+// RegisterFromAddress validates the permission bits encoded in a hook
+// address and records them. An address claiming no callbacks or setting
+// undefined bits is rejected.
+func (hr *HookRegistry) RegisterFromAddress(addr common.Address) (HookFlags, error) {
+	flags := HookFlags(binary.BigEndian.Uint16(addr[0:2]))
+	if flags == 0 || flags&^hookFlagsMask != 0 {
+		return 0, ErrHookInvalidAddress
+	}
+
+	hr.registeredHooks[addr] = flags
+	return flags, nil
+}
+
 // GetHookFlags returns the flags for a registered hook
 func (hr *HookRegistry) GetHookFlags(addr common.Address) (HookFlags, bool) {
 	flags, ok := hr.registeredHooks[addr]
@@ -193,6 +209,9 @@ func (hr *HookRegistry) IsHookEnabled(addr common.Address, flag HookFlags) bool
 	return flags&flag != 0
 }
 
+// HookInvoker dispatches a hook callback to the hook contract
+type HookInvoker func(stateDB StateDB, hookAddr common.Address, flag HookFlags, args ...interface{}) error
+
 // =========================================================================
 // Hook Data Structures for Common Hook Patterns
 // =========================================================================
@@ -217,6 +217,116 @@ func TestHookRegistryRegisterInvalidAddress(t *testing.T) {
 	}
 }
 
+func TestHookRegistryRegisterFromAddress(t *testing.T) {
+	registry := NewHookRegistry()
+
+	var addr common.Address
+	binary.BigEndian.PutUint16(addr[0:2], uint16(HookBeforeSwap|HookAfterSwap))
+	flags, err := registry.RegisterFromAddress(addr)
+	if err != nil {
+		t.Fatalf("RegisterFromAddress failed: %v", err)
+	}
+	if flags != HookBeforeSwap|HookAfterSwap {
+		t.Errorf("Flags mismatch: got %d", flags)
+	}
+	if recorded, ok := registry.GetHookFlags(addr); !ok || recorded != flags {
+		t.Errorf("Flags not recorded: got %d, %v", recorded, ok)
+	}
+
+	// Undefined high bits are rejected
+	var bad common.Address
+	binary.BigEndian.PutUint16(bad[0:2], 0x8000|uint16(HookBeforeSwap))
+	if _, err := registry.RegisterFromAddress(bad); err != ErrHookInvalidAddress {
+		t.Errorf("Expected ErrHookInvalidAddress for undefined bits, got: %v", err)
+	}
+
+	// An address declaring no callbacks is rejected
+	none := common.HexToAddress("0x0000000000000000000000000000000000001234")
+	if _, err := registry.RegisterFromAddress(none); err != ErrHookInvalidAddress {
+		t.Errorf("Expected ErrHookInvalidAddress for no flags, got: %v", err)
+	}
+}
+
+func TestPoolManagerHookDispatch(t *testing.T) {
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	params := SwapParams{
+		ZeroForOne:        true,
+		AmountSpecified:   big.NewInt(1000),
+		SqrtPriceLimitX96: MinSqrtRatio,
+	}
+
+	tests := []struct {
+		name       string
+		flags      HookFlags
+		wantBefore bool
+	}{
+		{"claims beforeSwap", HookBeforeSwap, true},
+		{"does not claim beforeSwap", HookAfterSwap, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := newTestPoolManager()
+			stateDB := NewMockStateDB()
+
+			var hookAddr common.Address
+			binary.BigEndian.PutUint16(hookAddr[0:2], uint16(tt.flags))
+			hookAddr[19] = 0x01
+
+			key := newTestPoolKey()
+			key.Hooks = hookAddr
+
+			var called []HookFlags
+			pm.SetHookInvoker(func(stateDB StateDB, addr common.Address, flag HookFlags, args ...interface{}) error {
+				called = append(called, flag)
+				return nil
+			})
+
+			if _, err := pm.Initialize(stateDB, key, sqrtPriceX96, nil); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+			if flags, ok := pm.hooks.GetHookFlags(hookAddr); !ok || flags != tt.flags {
+				t.Fatalf("hook flags not recorded at initialization: got %d, %v", flags, ok)
+			}
+
+			pm.pools[key.ID()].Liquidity = big.NewInt(1000000000)
+			pm.lockers = append(pm.lockers, caller)
+			pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+
+			if _, err := pm.Swap(stateDB, key, params, nil); err != nil {
+				t.Fatalf("Swap failed: %v", err)
+			}
+
+			invoked := false
+			for _, flag := range called {
+				if flag&^tt.flags != 0 {
+					t.Errorf("undeclared callback %d invoked", flag)
+				}
+				if flag == HookBeforeSwap {
+					invoked = true
+				}
+			}
+			if invoked != tt.wantBefore {
+				t.Errorf("beforeSwap invoked = %v, want %v", invoked, tt.wantBefore)
+			}
+		})
+	}
+}
+
+func TestPoolManagerInitializeInvalidHookAddress(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+
+	key := newTestPoolKey()
+	binary.BigEndian.PutUint16(key.Hooks[0:2], 0xf000)
+
+	_, err := pm.Initialize(stateDB, key, new(big.Int).Lsh(big.NewInt(1), 96), nil)
+	if err != ErrHookInvalidAddress {
+		t.Errorf("Expected ErrHookInvalidAddress, got: %v", err)
+	}
+}
+
 func TestHookRegistryIsEnabled(t *testing.T) {
 	registry := NewHookRegistry()
 
@@ -4,6 +4,7 @@
 package dex
 
 import (
+	"encoding/binary"
 	"math/big"
 	"sync"
 
@@ -21,8 +22,12 @@ var (
 	liquidTokenPrefix      = []byte("liquid/syn")  // Synthetic tokens
 	liquidAccountPrefix    = []byte("liquid/acc")  // User accounts
 	liquidGlobalPrefix     = []byte("liquid/glob") // Global state
+	liquidOwnerIndexPrefix = []byte("liquid/ownr") // Account owners per yield token
 )
 
+// liquidLockKey is the storage slot of the Liquid reentrancy lock
+var liquidLockKey = makeStorageKey(liquidGlobalPrefix, []byte("lock"))
+
 // Liquid implements the self-repaying loan vault precompile
 // Based on Alchemix architecture with 90% LTV (vs 50% in original)
 //
@@ -30,8 +35,19 @@ var (
 // - Deposit yield-bearing tokens (LP tokens) as collateral
 // - Mint liquid tokens (LUSD, LETH) up to 90% of collateral value
 // - Yield automatically harvested and applied to debt repayment
-// - NO LIQUIDATIONS - positions are always solvent (debt <= collateral)
+// - Optional oracle pricing for collateral that does not track its underlying 1:1
 // - Manual repayment also supported
+//
+// Collateral is valued 1:1 unless its yield token has a CollateralOracle.
+// At 1:1, debt is capped at MaxLTV on mint and withdraw while yield only
+// ever repays debt, so no account can cross LiquidationThreshold. An
+// oracle-priced account can: a price drop raises its LTV without any new
+// borrowing, and Liquidate then lets anyone repay part of its debt for
+// collateral at a discount. How much of the debt one call may repay and the
+// discount are the yield token's CloseFactor and LiquidationBonus.
+//
+// Mutating operations hold a storage reentrancy lock and move tokens only
+// after all state has been saved (checks-effects-interactions).
 type Liquid struct {
 	mu sync.RWMutex
 
@@ -87,11 +103,13 @@ func (a *Liquid) AddYieldToken(
 	}
 
 	yt := &YieldToken{
-		Address:         token,
-		UnderlyingAsset: underlying,
-		YieldPerBlock:   new(big.Int).Set(yieldPerBlock),
-		IsActive:        true,
-		TotalDeposited:  big.NewInt(0),
+		Address:          token,
+		UnderlyingAsset:  underlying,
+		YieldPerBlock:    new(big.Int).Set(yieldPerBlock),
+		IsActive:         true,
+		TotalDeposited:   big.NewInt(0),
+		CloseFactor:      DefaultLiquidCloseFactor,
+		LiquidationBonus: DefaultLiquidLiquidationBonus,
 	}
 
 	a.yieldTokens[token] = yt
@@ -99,6 +117,106 @@ func (a *Liquid) AddYieldToken(
 	return nil
 }
 
+// DeactivateYieldToken retires a yield token whose strategy is deprecated.
+// New deposits and mints are rejected; withdrawals, burns and harvests keep
+// working so existing depositors can exit or be migrated.
+func (a *Liquid) DeactivateYieldToken(
+	stateDB StateDB,
+	token common.Address,
+) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	yt, exists := a.yieldTokens[token]
+	if !exists || !yt.IsActive {
+		return ErrInvalidYieldToken
+	}
+
+	yt.IsActive = false
+	a.saveYieldToken(stateDB, yt)
+	return nil
+}
+
+// MigrateYieldToken moves every account's collateral and debt from a
+// deactivated yield token to an active replacement strategy. Accrued yield
+// is harvested on the old token first; positions the owner already holds in
+// the replacement are merged. Collateral is moved token for token; if
+// either token has a CollateralOracle, migrated positions are revalued at
+// the replacement's price.
+func (a *Liquid) MigrateYieldToken(
+	stateDB StateDB,
+	oldToken common.Address,
+	newToken common.Address,
+) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	oldYT, exists := a.yieldTokens[oldToken]
+	if !exists || oldToken == newToken {
+		return ErrInvalidYieldToken
+	}
+	if oldYT.IsActive {
+		return ErrYieldTokenStillActive
+	}
+	newYT, exists := a.yieldTokens[newToken]
+	if !exists || !newYT.IsActive {
+		return ErrInvalidYieldToken
+	}
+
+	// Walk the owner index in state rather than the account cache, so
+	// accounts this instance has not loaded are migrated too
+	migrated := big.NewInt(0)
+	count := a.ownerIndexCount(stateDB, oldToken)
+	for i := uint64(0); i < count; i++ {
+		owner := a.ownerIndexEntry(stateDB, oldToken, i)
+		key := accountKey(owner, oldToken)
+		oldAcc := a.getAccount(stateDB, key)
+		if oldAcc == nil || oldAcc.Collateral.Sign() <= 0 {
+			continue
+		}
+		// Accounts reloaded from state carry only their balances
+		oldAcc.Owner = owner
+		oldAcc.YieldToken = oldToken
+		a.harvestYieldInternal(stateDB, oldAcc, oldYT)
+
+		newKey := accountKey(owner, newToken)
+		newAcc := a.getAccount(stateDB, newKey)
+		if newAcc == nil {
+			newAcc = &LiquidAccount{
+				Owner:        owner,
+				YieldToken:   newToken,
+				Collateral:   big.NewInt(0),
+				Debt:         big.NewInt(0),
+				AccruedYield: big.NewInt(0),
+			}
+		}
+		newAcc.Owner = owner
+		newAcc.YieldToken = newToken
+		a.harvestYieldInternal(stateDB, newAcc, newYT)
+
+		newAcc.Collateral = new(big.Int).Add(newAcc.Collateral, oldAcc.Collateral)
+		newAcc.Debt = new(big.Int).Add(newAcc.Debt, oldAcc.Debt)
+		newAcc.AccruedYield = new(big.Int).Add(newAcc.AccruedYield, oldAcc.AccruedYield)
+		migrated.Add(migrated, oldAcc.Collateral)
+
+		oldAcc.Collateral = big.NewInt(0)
+		oldAcc.Debt = big.NewInt(0)
+		oldAcc.AccruedYield = big.NewInt(0)
+
+		a.saveAccount(stateDB, key, oldAcc)
+		a.saveAccount(stateDB, newKey, newAcc)
+		a.indexOwner(stateDB, newToken, owner)
+	}
+
+	// In production, would redeem the old strategy and deposit into the new one
+	oldYT.TotalDeposited = new(big.Int).Sub(oldYT.TotalDeposited, migrated)
+	newYT.TotalDeposited = new(big.Int).Add(newYT.TotalDeposited, migrated)
+	a.saveYieldToken(stateDB, oldYT)
+	a.saveYieldToken(stateDB, newYT)
+
+	return nil
+}
+
 // AddLiquidToken registers a new liquid token
 func (a *Liquid) AddLiquidToken(
 	stateDB StateDB,
@@ -112,6 +230,10 @@ func (a *Liquid) AddLiquidToken(
 	if _, exists := a.liquidTokens[synthetic]; exists {
 		return ErrLiquidTokenNotRegistered
 	}
+	// The ceiling bounds TotalMinted, so it must fit the uint256 supply
+	if debtCeiling == nil || debtCeiling.Sign() < 0 || debtCeiling.BitLen() > 256 {
+		return ErrInvalidAmount
+	}
 
 	st := &LiquidToken{
 		Address:         synthetic,
@@ -139,6 +261,12 @@ func (a *Liquid) Deposit(
 	yieldToken common.Address,
 	amount *big.Int,
 ) error {
+	release, err := nonReentrant(stateDB, liquidAddr, liquidLockKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -169,9 +297,6 @@ func (a *Liquid) Deposit(
 	// Harvest any accrued yield first
 	a.harvestYieldInternal(stateDB, account, yt)
 
-	// Transfer yield tokens from user to Liquid
-	a.transferFrom(stateDB, yieldToken, owner, liquidAddr, amount)
-
 	// Update account
 	account.Collateral = new(big.Int).Add(account.Collateral, amount)
 
@@ -180,8 +305,12 @@ func (a *Liquid) Deposit(
 
 	// Save state
 	a.saveAccount(stateDB, key, account)
+	a.indexOwner(stateDB, yieldToken, owner)
 	a.saveYieldToken(stateDB, yt)
 
+	// Transfer yield tokens from user to Liquid
+	a.transferFrom(stateDB, yieldToken, owner, liquidAddr, amount)
+
 	return nil
 }
 
@@ -193,6 +322,12 @@ func (a *Liquid) Withdraw(
 	yieldToken common.Address,
 	amount *big.Int,
 ) error {
+	release, err := nonReentrant(stateDB, liquidAddr, liquidLockKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -218,10 +353,13 @@ func (a *Liquid) Withdraw(
 	// Calculate new collateral after withdrawal
 	newCollateral := new(big.Int).Sub(account.Collateral, amount)
 
-	// Ensure position remains healthy (debt <= 90% of collateral)
-	// Since this is Alchemix-style, debt can never exceed collateral
+	// Ensure position remains healthy (debt <= 90% of collateral value)
 	if account.Debt.Sign() > 0 {
-		maxDebt := a.calculateMaxDebt(newCollateral)
+		newCollateralValue, err := a.getCollateralValue(stateDB, newCollateral, yt)
+		if err != nil {
+			return err
+		}
+		maxDebt := a.calculateMaxDebt(newCollateralValue)
 		if account.Debt.Cmp(maxDebt) > 0 {
 			return ErrMaxLTVExceeded
 		}
@@ -233,13 +371,13 @@ func (a *Liquid) Withdraw(
 	// Update global state
 	yt.TotalDeposited = new(big.Int).Sub(yt.TotalDeposited, amount)
 
-	// Transfer yield tokens back to user
-	a.transfer(stateDB, yieldToken, liquidAddr, owner, amount)
-
 	// Save state
 	a.saveAccount(stateDB, key, account)
 	a.saveYieldToken(stateDB, yt)
 
+	// Transfer yield tokens back to user
+	a.transfer(stateDB, yieldToken, liquidAddr, owner, amount)
+
 	return nil
 }
 
@@ -252,6 +390,16 @@ func (a *Liquid) Mint(
 	syntheticToken common.Address,
 	amount *big.Int,
 ) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+
+	release, err := nonReentrant(stateDB, liquidAddr, liquidLockKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -266,10 +414,11 @@ func (a *Liquid) Mint(
 		return ErrLiquidTokenNotRegistered
 	}
 
-	// Check debt ceiling
+	// Check the synthetic's aggregate debt ceiling before any per-account
+	// check: TotalMinted + amount <= DebtCeiling across all minters
 	newTotalMinted := new(big.Int).Add(st.TotalMinted, amount)
 	if newTotalMinted.Cmp(st.DebtCeiling) > 0 {
-		return ErrDebtCeiling
+		return ErrDebtCeilingExceeded
 	}
 
 	// Get account
@@ -283,7 +432,10 @@ func (a *Liquid) Mint(
 	a.harvestYieldInternal(stateDB, account, yt)
 
 	// Calculate max mintable (90% of collateral value minus existing debt)
-	collateralValue := a.getCollateralValue(stateDB, account.Collateral, yt)
+	collateralValue, err := a.getCollateralValue(stateDB, account.Collateral, yt)
+	if err != nil {
+		return err
+	}
 	maxDebt := a.calculateMaxDebt(collateralValue)
 	availableToMint := new(big.Int).Sub(maxDebt, account.Debt)
 
@@ -301,13 +453,13 @@ func (a *Liquid) Mint(
 	// Update synthetic total minted
 	st.TotalMinted = newTotalMinted
 
-	// Mint liquid tokens to user
-	a.mintSynthetic(stateDB, syntheticToken, owner, netMintAmount)
-
 	// Save state
 	a.saveAccount(stateDB, key, account)
 	a.saveLiquidToken(stateDB, st)
 
+	// Mint liquid tokens to user
+	a.mintSynthetic(stateDB, syntheticToken, owner, netMintAmount)
+
 	return nil
 }
 
@@ -320,6 +472,12 @@ func (a *Liquid) Burn(
 	syntheticToken common.Address,
 	amount *big.Int,
 ) error {
+	release, err := nonReentrant(stateDB, liquidAddr, liquidLockKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -349,22 +507,25 @@ func (a *Liquid) Burn(
 	feeAmount := a.calculateFee(burnAmount, st.BurnFee)
 	debtReduction := new(big.Int).Sub(burnAmount, feeAmount)
 
-	// Burn liquid tokens from user
-	a.burnSynthetic(stateDB, syntheticToken, owner, burnAmount)
-
 	// Reduce debt
 	account.Debt = new(big.Int).Sub(account.Debt, debtReduction)
 	if account.Debt.Sign() < 0 {
 		account.Debt = big.NewInt(0)
 	}
 
-	// Update synthetic total
+	// Update synthetic total, freeing capacity under the debt ceiling
 	st.TotalMinted = new(big.Int).Sub(st.TotalMinted, burnAmount)
+	if st.TotalMinted.Sign() < 0 {
+		st.TotalMinted = big.NewInt(0)
+	}
 
 	// Save state
 	a.saveAccount(stateDB, key, account)
 	a.saveLiquidToken(stateDB, st)
 
+	// Burn liquid tokens from user
+	a.burnSynthetic(stateDB, syntheticToken, owner, burnAmount)
+
 	return nil
 }
 
@@ -375,6 +536,12 @@ func (a *Liquid) Harvest(
 	owner common.Address,
 	yieldToken common.Address,
 ) (*big.Int, error) {
+	release, err := nonReentrant(stateDB, liquidAddr, liquidLockKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
@@ -414,16 +581,10 @@ func (a *Liquid) harvestYieldInternal(
 		return big.NewInt(0)
 	}
 
-	// Calculate blocks elapsed
-	blocksElapsed := currentBlock - account.LastHarvestBlock
-	if blocksElapsed == 0 {
+	if currentBlock <= account.LastHarvestBlock {
 		return big.NewInt(0)
 	}
-
-	// Calculate yield: collateral * yieldPerBlock * blocksElapsed / 1e18
-	yieldAmount := new(big.Int).Mul(account.Collateral, yt.YieldPerBlock)
-	yieldAmount.Mul(yieldAmount, big.NewInt(int64(blocksElapsed)))
-	yieldAmount.Div(yieldAmount, big.NewInt(1e18))
+	yieldAmount := a.pendingYield(account, yt, currentBlock)
 
 	// Add to accrued yield
 	account.AccruedYield = new(big.Int).Add(account.AccruedYield, yieldAmount)
@@ -446,6 +607,202 @@ func (a *Liquid) harvestYieldInternal(
 	return yieldAmount
 }
 
+// pendingYield returns the yield earned since the last harvest:
+// collateral * yieldPerBlock * (currentBlock - lastHarvestBlock) / 1e18
+func (a *Liquid) pendingYield(account *LiquidAccount, yt *YieldToken, currentBlock uint64) *big.Int {
+	if account.LastHarvestBlock == 0 || currentBlock <= account.LastHarvestBlock {
+		return big.NewInt(0)
+	}
+	blocksElapsed := new(big.Int).SetUint64(currentBlock - account.LastHarvestBlock)
+
+	yieldAmount := new(big.Int).Mul(account.Collateral, yt.YieldPerBlock)
+	yieldAmount.Mul(yieldAmount, blocksElapsed)
+	yieldAmount.Div(yieldAmount, big.NewInt(1e18))
+	return yieldAmount
+}
+
+// =========================================================================
+// Oracle Pricing & Liquidation
+// =========================================================================
+
+// CollateralOracle prices a yield token in its synthetic's underlying unit,
+// scaled by 1e18 (1e18 = 1:1). *OracleHub satisfies it.
+type CollateralOracle interface {
+	GetPrice(asset common.Address) (*big.Int, error)
+}
+
+// SetCollateralOracle sets the oracle that values a yield token's
+// collateral before LTV checks, for yield tokens that do not track their
+// underlying 1:1. A nil oracle restores 1:1 valuation.
+func (a *Liquid) SetCollateralOracle(
+	stateDB StateDB,
+	token common.Address,
+	oracle CollateralOracle,
+) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	yt, exists := a.yieldTokens[token]
+	if !exists {
+		return ErrInvalidYieldToken
+	}
+
+	yt.Oracle = oracle
+	a.saveYieldToken(stateDB, yt)
+	return nil
+}
+
+// SetCloseFactor sets the share of an account's debt, in basis points,
+// that one liquidation of token's collateral may repay. A close factor
+// below 100% keeps a barely unhealthy account from being liquidated in full.
+func (a *Liquid) SetCloseFactor(
+	stateDB StateDB,
+	token common.Address,
+	closeFactor uint24,
+) error {
+	if closeFactor == 0 || closeFactor > LTVPrecision {
+		return ErrInvalidParameter
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	yt, exists := a.yieldTokens[token]
+	if !exists {
+		return ErrInvalidYieldToken
+	}
+
+	yt.CloseFactor = closeFactor
+	a.saveYieldToken(stateDB, yt)
+	return nil
+}
+
+// SetLiquidationBonus sets the collateral value, in basis points of the
+// repaid debt, a liquidator of token's collateral receives on top of the
+// debt it repays. It is capped at MaxLiquidLiquidationBonus.
+func (a *Liquid) SetLiquidationBonus(
+	stateDB StateDB,
+	token common.Address,
+	bonus uint24,
+) error {
+	if bonus > MaxLiquidLiquidationBonus {
+		return ErrInvalidParameter
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	yt, exists := a.yieldTokens[token]
+	if !exists {
+		return ErrInvalidYieldToken
+	}
+
+	yt.LiquidationBonus = bonus
+	a.saveYieldToken(stateDB, yt)
+	return nil
+}
+
+// Liquidate repays up to repayAmount of an unhealthy account's debt with
+// the liquidator's synthetic tokens, capped at the yield token's
+// CloseFactor of the debt, and pays the liquidator collateral worth the
+// repaid debt plus the yield token's LiquidationBonus. An account is unhealthy when its debt exceeds
+// LiquidationThreshold of its collateral value, which only oracle-priced
+// collateral can reach. Returns the collateral seized.
+func (a *Liquid) Liquidate(
+	stateDB StateDB,
+	liquidator common.Address,
+	owner common.Address,
+	yieldToken common.Address,
+	syntheticToken common.Address,
+	repayAmount *big.Int,
+) (*big.Int, error) {
+	if repayAmount == nil || repayAmount.Sign() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	release, err := nonReentrant(stateDB, liquidAddr, liquidLockKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	yt, exists := a.yieldTokens[yieldToken]
+	if !exists {
+		return nil, ErrInvalidYieldToken
+	}
+	st, exists := a.liquidTokens[syntheticToken]
+	if !exists {
+		return nil, ErrLiquidTokenNotRegistered
+	}
+
+	key := accountKey(owner, yieldToken)
+	account := a.getAccount(stateDB, key)
+	if account == nil || account.Debt.Sign() == 0 {
+		return nil, ErrNoDebtToRepay
+	}
+
+	// Harvest yield first; it may restore the account's health
+	a.harvestYieldInternal(stateDB, account, yt)
+
+	collateralValue, err := a.getCollateralValue(stateDB, account.Collateral, yt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unhealthy: debt * LTVPrecision > collateralValue * LiquidationThreshold
+	lhs := new(big.Int).Mul(account.Debt, big.NewInt(LTVPrecision))
+	rhs := new(big.Int).Mul(collateralValue, big.NewInt(LiquidationThreshold))
+	if lhs.Cmp(rhs) <= 0 {
+		return nil, ErrPositionHealthy
+	}
+
+	// Cap the repayment at the close factor
+	repay := new(big.Int).Mul(account.Debt, big.NewInt(int64(yt.CloseFactor)))
+	repay.Div(repay, big.NewInt(LTVPrecision))
+	if repayAmount.Cmp(repay) < 0 {
+		repay = new(big.Int).Set(repayAmount)
+	}
+	if repay.Sign() == 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	// Seize collateral worth repay * (1 + bonus) at the oracle price,
+	// capped at the account's collateral
+	seized := new(big.Int).Set(account.Collateral)
+	if collateralValue.Sign() > 0 {
+		seizeValue := new(big.Int).Mul(repay, big.NewInt(LTVPrecision+int64(yt.LiquidationBonus)))
+		seizeValue.Div(seizeValue, big.NewInt(LTVPrecision))
+		amount := new(big.Int).Mul(seizeValue, account.Collateral)
+		amount.Div(amount, collateralValue)
+		if amount.Cmp(seized) < 0 {
+			seized = amount
+		}
+	}
+
+	// Update account and global state
+	account.Debt = new(big.Int).Sub(account.Debt, repay)
+	account.Collateral = new(big.Int).Sub(account.Collateral, seized)
+	yt.TotalDeposited = new(big.Int).Sub(yt.TotalDeposited, seized)
+	st.TotalMinted = new(big.Int).Sub(st.TotalMinted, repay)
+	if st.TotalMinted.Sign() < 0 {
+		st.TotalMinted = big.NewInt(0)
+	}
+
+	// Save state
+	a.saveAccount(stateDB, key, account)
+	a.saveYieldToken(stateDB, yt)
+	a.saveLiquidToken(stateDB, st)
+
+	// Burn the liquidator's liquid tokens and pay out the collateral
+	a.burnSynthetic(stateDB, syntheticToken, liquidator, repay)
+	a.transfer(stateDB, yieldToken, liquidAddr, liquidator, seized)
+
+	return seized, nil
+}
+
 // =========================================================================
 // View Functions
 // =========================================================================
@@ -483,7 +840,10 @@ func (a *Liquid) GetMaxMintable(
 		return big.NewInt(0)
 	}
 
-	collateralValue := a.getCollateralValue(stateDB, account.Collateral, yt)
+	collateralValue, err := a.getCollateralValue(stateDB, account.Collateral, yt)
+	if err != nil {
+		return big.NewInt(0)
+	}
 	maxDebt := a.calculateMaxDebt(collateralValue)
 
 	// Account for accrued yield that will reduce debt
@@ -520,8 +880,8 @@ func (a *Liquid) GetLTV(
 		return big.NewInt(0)
 	}
 
-	collateralValue := a.getCollateralValue(stateDB, account.Collateral, yt)
-	if collateralValue.Sign() == 0 {
+	collateralValue, err := a.getCollateralValue(stateDB, account.Collateral, yt)
+	if err != nil || collateralValue.Sign() == 0 {
 		return big.NewInt(0)
 	}
 
@@ -532,6 +892,46 @@ func (a *Liquid) GetLTV(
 	return ltv
 }
 
+// maxHealthFactor is reported for accounts without debt (type(uint256).max)
+var maxHealthFactor = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// GetHealthFactor returns the ratio of max-allowed debt to current debt,
+// scaled by HealthFactorPrecision. A value below HealthFactorPrecision
+// means the position is past the liquidation threshold. Accounts without
+// debt report the maximum uint256 value.
+func (a *Liquid) GetHealthFactor(
+	stateDB StateDB,
+	owner common.Address,
+	yieldToken common.Address,
+) *big.Int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	key := accountKey(owner, yieldToken)
+	account := a.getAccount(stateDB, key)
+	if account == nil || account.Debt.Sign() == 0 {
+		return new(big.Int).Set(maxHealthFactor)
+	}
+
+	yt, exists := a.yieldTokens[yieldToken]
+	if !exists {
+		return big.NewInt(0)
+	}
+
+	collateralValue, err := a.getCollateralValue(stateDB, account.Collateral, yt)
+	if err != nil {
+		return big.NewInt(0)
+	}
+
+	// HF = (collateralValue * LiquidationThreshold * precision) / (debt * LTVPrecision)
+	hf := new(big.Int).Mul(collateralValue, big.NewInt(LiquidationThreshold))
+	hf.Mul(hf, big.NewInt(HealthFactorPrecision))
+	denom := new(big.Int).Mul(account.Debt, big.NewInt(LTVPrecision))
+	hf.Div(hf, denom)
+
+	return hf
+}
+
 // GetTimeToRepayment estimates blocks until debt is fully repaid
 func (a *Liquid) GetTimeToRepayment(
 	stateDB StateDB,
@@ -570,6 +970,30 @@ func (a *Liquid) GetTimeToRepayment(
 	return blocks.Uint64()
 }
 
+// GetAccruedYield returns the yield an account's collateral has earned
+// toward debt repayment since its last harvest, without harvesting it
+func (a *Liquid) GetAccruedYield(
+	stateDB StateDB,
+	owner common.Address,
+	yieldToken common.Address,
+) *big.Int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	yt, exists := a.yieldTokens[yieldToken]
+	if !exists {
+		return big.NewInt(0)
+	}
+
+	key := accountKey(owner, yieldToken)
+	account := a.getAccount(stateDB, key)
+	if account == nil {
+		return big.NewInt(0)
+	}
+
+	return a.pendingYield(account, yt, a.getCurrentBlock(stateDB))
+}
+
 // =========================================================================
 // Helper Functions
 // =========================================================================
@@ -590,12 +1014,21 @@ func (a *Liquid) calculateFee(amount *big.Int, feeBps uint24) *big.Int {
 	return fee
 }
 
-// getCollateralValue returns the value of collateral in underlying terms
-func (a *Liquid) getCollateralValue(stateDB StateDB, amount *big.Int, yt *YieldToken) *big.Int {
-	// For LP tokens, would query the pool for underlying value
-	// Simplified: assume 1:1 for now
-	// In production, this would call poolManager to get real value
-	return new(big.Int).Set(amount)
+// getCollateralValue returns the value of collateral in underlying terms:
+// amount * price / 1e18 with the yield token's oracle, else amount (1:1)
+func (a *Liquid) getCollateralValue(stateDB StateDB, amount *big.Int, yt *YieldToken) (*big.Int, error) {
+	if yt.Oracle == nil {
+		return new(big.Int).Set(amount), nil
+	}
+	price, err := yt.Oracle.GetPrice(yt.Address)
+	if err != nil {
+		return nil, err
+	}
+	if price == nil || price.Sign() <= 0 {
+		return nil, ErrPriceUnavailable
+	}
+	value := new(big.Int).Mul(amount, price)
+	return value.Div(value, RAY), nil
 }
 
 // getCurrentBlock returns the current block number
@@ -649,6 +1082,40 @@ func (a *Liquid) saveAccount(stateDB StateDB, key [32]byte, acc *LiquidAccount)
 	stateDB.SetState(liquidAddr, storageKey, data)
 }
 
+// The owner index lists, per yield token, every owner that has deposited
+// it. It lives in state, so it survives reloads and is rolled back with the
+// transaction that changed it:
+//
+//	yieldToken || "n"          -> entry count
+//	yieldToken || "e" || i     -> owner i
+//	yieldToken || owner        -> i + 1, or 0 if absent
+
+// indexOwner adds owner to yieldToken's index if it is not there yet
+func (a *Liquid) indexOwner(stateDB StateDB, yieldToken, owner common.Address) {
+	slotKey := makeStorageKey(liquidOwnerIndexPrefix, append(yieldToken.Bytes(), owner.Bytes()...))
+	if stateDB.GetState(liquidAddr, slotKey) != (common.Hash{}) {
+		return
+	}
+	count := a.ownerIndexCount(stateDB, yieldToken)
+	stateDB.SetState(liquidAddr, liquidOwnerIndexEntryKey(yieldToken, count), common.BytesToHash(owner.Bytes()))
+	stateDB.SetState(liquidAddr, slotKey, uint64Hash(count+1))
+	stateDB.SetState(liquidAddr, makeStorageKey(liquidOwnerIndexPrefix, append(yieldToken.Bytes(), 'n')), uint64Hash(count+1))
+}
+
+func (a *Liquid) ownerIndexCount(stateDB StateDB, yieldToken common.Address) uint64 {
+	slot := stateDB.GetState(liquidAddr, makeStorageKey(liquidOwnerIndexPrefix, append(yieldToken.Bytes(), 'n')))
+	return binary.BigEndian.Uint64(slot[24:])
+}
+
+func (a *Liquid) ownerIndexEntry(stateDB StateDB, yieldToken common.Address, index uint64) common.Address {
+	return common.BytesToAddress(stateDB.GetState(liquidAddr, liquidOwnerIndexEntryKey(yieldToken, index)).Bytes())
+}
+
+func liquidOwnerIndexEntryKey(yieldToken common.Address, index uint64) common.Hash {
+	id := append(yieldToken.Bytes(), 'e')
+	return makeStorageKey(liquidOwnerIndexPrefix, binary.BigEndian.AppendUint64(id, index))
+}
+
 func (a *Liquid) saveYieldToken(stateDB StateDB, yt *YieldToken) {
 	a.yieldTokens[yt.Address] = yt
 	// In production, would serialize full struct to state
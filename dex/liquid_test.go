@@ -190,6 +190,467 @@ func TestLiquid_Mint_MaxLTV(t *testing.T) {
 	}
 }
 
+func TestLiquid_Mint_DebtCeiling(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	// Ceiling well below what the collateral allows
+	yieldPerBlock := bigInt("1000000000000000")
+	debtCeiling := bigInt("50000000000000000000") // 50 tokens
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, yieldPerBlock)
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, debtCeiling)
+
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	depositAmount := bigInt("100000000000000000000") // 100 tokens
+	if err := alchemist.Deposit(stateDB, testUser1, testYieldToken, depositAmount); err != nil {
+		t.Fatalf("Deposit failed: %v", err)
+	}
+
+	// Minting exactly up to the ceiling succeeds
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, debtCeiling); err != nil {
+		t.Fatalf("Mint up to the ceiling failed: %v", err)
+	}
+	if total := alchemist.liquidTokens[testLiquidToken].TotalMinted; total.Cmp(debtCeiling) != 0 {
+		t.Fatalf("TotalMinted mismatch: got %s, want %s", total, debtCeiling)
+	}
+
+	// One wei over is rejected and leaves state untouched
+	err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, big.NewInt(1))
+	if err != ErrDebtCeilingExceeded {
+		t.Fatalf("expected ErrDebtCeilingExceeded, got %v", err)
+	}
+	if total := alchemist.liquidTokens[testLiquidToken].TotalMinted; total.Cmp(debtCeiling) != 0 {
+		t.Fatalf("TotalMinted changed after rejected mint: %s", total)
+	}
+	if debt := alchemist.GetAccount(stateDB, testUser1, testYieldToken).Debt; debt.Cmp(debtCeiling) != 0 {
+		t.Fatalf("debt changed after rejected mint: got %s, want %s", debt, debtCeiling)
+	}
+}
+
+func TestLiquid_Mint_DebtCeilingAcrossUsers(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	yieldPerBlock := bigInt("1000000000000000")
+	debtCeiling := bigInt("60000000000000000000") // 60 tokens
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, yieldPerBlock)
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, debtCeiling)
+
+	testUser3 := common.HexToAddress("0x6666666666666666666666666666666666666666")
+	depositAmount := bigInt("100000000000000000000") // 100 tokens, 90 mintable each
+	for _, user := range []common.Address{testUser1, testUser2, testUser3} {
+		setBalance(stateDB, user, depositAmount)
+		if err := alchemist.Deposit(stateDB, user, testYieldToken, depositAmount); err != nil {
+			t.Fatalf("Deposit failed: %v", err)
+		}
+	}
+
+	// Two users fill the ceiling, each well within their own LTV
+	half := bigInt("30000000000000000000")
+	for _, user := range []common.Address{testUser1, testUser2} {
+		if err := alchemist.Mint(stateDB, user, testYieldToken, testLiquidToken, half); err != nil {
+			t.Fatalf("Mint failed: %v", err)
+		}
+	}
+
+	// The third user has 90 tokens of LTV headroom but the ceiling is full
+	if maxMintable := alchemist.GetMaxMintable(stateDB, testUser3, testYieldToken); maxMintable.Cmp(half) <= 0 {
+		t.Fatalf("expected LTV headroom above %s, got %s", half, maxMintable)
+	}
+	err := alchemist.Mint(stateDB, testUser3, testYieldToken, testLiquidToken, big.NewInt(1))
+	if err != ErrDebtCeilingExceeded {
+		t.Fatalf("expected ErrDebtCeilingExceeded, got %v", err)
+	}
+	if debt := alchemist.GetAccount(stateDB, testUser3, testYieldToken).Debt; debt.Sign() != 0 {
+		t.Fatalf("rejected mint recorded debt: %s", debt)
+	}
+
+	// Burning frees capacity for other users
+	burnAmount := bigInt("10000000000000000000")
+	if err := alchemist.Burn(stateDB, testUser1, testYieldToken, testLiquidToken, burnAmount); err != nil {
+		t.Fatalf("Burn failed: %v", err)
+	}
+	if err := alchemist.Mint(stateDB, testUser3, testYieldToken, testLiquidToken, burnAmount); err != nil {
+		t.Fatalf("Mint after burn failed: %v", err)
+	}
+	if total := alchemist.liquidTokens[testLiquidToken].TotalMinted; total.Cmp(debtCeiling) != 0 {
+		t.Fatalf("TotalMinted mismatch: got %s, want %s", total, debtCeiling)
+	}
+}
+
+func TestLiquid_Mint_InvalidAmounts(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+
+	// Ceilings must fit in a uint256 supply
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 256)
+	for _, ceiling := range []*big.Int{nil, big.NewInt(-1), tooLarge} {
+		if err := alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, ceiling); err != ErrInvalidAmount {
+			t.Fatalf("ceiling %v: expected ErrInvalidAmount, got %v", ceiling, err)
+		}
+	}
+	maxUint256 := new(big.Int).Sub(tooLarge, big.NewInt(1))
+	if err := alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, maxUint256); err != nil {
+		t.Fatalf("AddLiquidToken failed: %v", err)
+	}
+
+	for _, amount := range []*big.Int{nil, big.NewInt(0), big.NewInt(-1)} {
+		if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, amount); err != ErrInvalidAmount {
+			t.Fatalf("amount %v: expected ErrInvalidAmount, got %v", amount, err)
+		}
+	}
+}
+
+func TestLiquid_GetHealthFactor(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	yieldPerBlock := bigInt("1000000000000000")
+	debtCeiling := bigInt("1000000000000000000000000")
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, yieldPerBlock)
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, debtCeiling)
+
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	depositAmount := bigInt("100000000000000000000") // 100 tokens
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, depositAmount)
+
+	one := big.NewInt(HealthFactorPrecision)
+
+	// No debt - maximum health factor
+	hf := alchemist.GetHealthFactor(stateDB, testUser1, testYieldToken)
+	if hf.Cmp(maxHealthFactor) != 0 {
+		t.Fatalf("health factor without debt: got %s, want max", hf)
+	}
+
+	// Healthy: 45 debt against 90 allowed -> 2.0
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, bigInt("45000000000000000000")); err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+	hf = alchemist.GetHealthFactor(stateDB, testUser1, testYieldToken)
+	want := new(big.Int).Mul(one, big.NewInt(2))
+	if hf.Cmp(want) != 0 {
+		t.Fatalf("healthy health factor: got %s, want %s", hf, want)
+	}
+
+	// At threshold: minted to MaxLTV -> exactly 1.0
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, bigInt("45000000000000000000")); err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+	hf = alchemist.GetHealthFactor(stateDB, testUser1, testYieldToken)
+	if hf.Cmp(one) != 0 {
+		t.Fatalf("at-threshold health factor: got %s, want %s", hf, one)
+	}
+
+	// Underwater: debt grows past the threshold (120 against 90 allowed -> 0.75)
+	account := alchemist.GetAccount(stateDB, testUser1, testYieldToken)
+	account.Debt = bigInt("120000000000000000000")
+	hf = alchemist.GetHealthFactor(stateDB, testUser1, testYieldToken)
+	want = new(big.Int).Div(new(big.Int).Mul(one, big.NewInt(3)), big.NewInt(4))
+	if hf.Cmp(want) != 0 {
+		t.Fatalf("underwater health factor: got %s, want %s", hf, want)
+	}
+	if hf.Cmp(one) >= 0 {
+		t.Fatal("underwater position should have health factor below 1")
+	}
+}
+
+// testCollateralOracle serves a settable price per yield token
+type testCollateralOracle map[common.Address]*big.Int
+
+func (o testCollateralOracle) GetPrice(asset common.Address) (*big.Int, error) {
+	price, ok := o[asset]
+	if !ok {
+		return nil, ErrPriceUnavailable
+	}
+	return new(big.Int).Set(price), nil
+}
+
+// priceBps returns a collateral price of bps/10000 underlying per token
+func priceBps(bps int64) *big.Int {
+	price := new(big.Int).Mul(RAY, big.NewInt(bps))
+	return price.Div(price, big.NewInt(LTVPrecision))
+}
+
+func TestLiquid_CollateralOracle_LTV(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, bigInt("1000000000000000000000000"))
+
+	oracle := testCollateralOracle{testYieldToken: priceBps(10000)}
+	if err := alchemist.SetCollateralOracle(stateDB, testYieldToken, oracle); err != nil {
+		t.Fatalf("SetCollateralOracle failed: %v", err)
+	}
+	if err := alchemist.SetCollateralOracle(stateDB, testUser2, oracle); err != ErrInvalidYieldToken {
+		t.Fatalf("expected ErrInvalidYieldToken, got %v", err)
+	}
+
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, bigInt("100000000000000000000")) // 100 tokens
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, bigInt("45000000000000000000")); err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+
+	tests := []struct {
+		priceBps int64
+		ltv      int64
+	}{
+		{priceBps: 10000, ltv: 4500},
+		{priceBps: 20000, ltv: 2250},
+		{priceBps: 5000, ltv: 9000},
+		{priceBps: 4000, ltv: 11250},
+	}
+	for _, tt := range tests {
+		oracle[testYieldToken] = priceBps(tt.priceBps)
+		if ltv := alchemist.GetLTV(stateDB, testUser1, testYieldToken); ltv.Int64() != tt.ltv {
+			t.Errorf("price %d bps: LTV = %s, want %d", tt.priceBps, ltv, tt.ltv)
+		}
+	}
+
+	// At half price the 45 debt already uses the full 90% of 50 value
+	oracle[testYieldToken] = priceBps(5000)
+	if max := alchemist.GetMaxMintable(stateDB, testUser1, testYieldToken); max.Sign() != 0 {
+		t.Errorf("GetMaxMintable at half price = %s, want 0", max)
+	}
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, big.NewInt(1)); err != ErrMaxLTVExceeded {
+		t.Errorf("expected ErrMaxLTVExceeded at half price, got %v", err)
+	}
+
+	// At double price twice the collateral value is available
+	oracle[testYieldToken] = priceBps(20000)
+	if max := alchemist.GetMaxMintable(stateDB, testUser1, testYieldToken); max.Cmp(bigInt("135000000000000000000")) != 0 {
+		t.Errorf("GetMaxMintable at double price = %s, want 135e18", max)
+	}
+
+	// Without a price the vault refuses to lend
+	delete(oracle, testYieldToken)
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, big.NewInt(1)); err != ErrPriceUnavailable {
+		t.Errorf("expected ErrPriceUnavailable, got %v", err)
+	}
+}
+
+func TestLiquid_Liquidate_PriceDrop(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, bigInt("1000000000000000000000000"))
+	oracle := testCollateralOracle{testYieldToken: priceBps(10000)}
+	alchemist.SetCollateralOracle(stateDB, testYieldToken, oracle)
+	alchemist.SetCloseFactor(stateDB, testYieldToken, 5000)
+	alchemist.SetLiquidationBonus(stateDB, testYieldToken, 500)
+
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, bigInt("100000000000000000000")) // 100 tokens
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, bigInt("90000000000000000000")); err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+
+	liquidator := testUser2
+	setBalance(stateDB, liquidator, bigInt("100000000000000000000"))
+
+	// Minted to the threshold is not yet liquidatable
+	if _, err := alchemist.Liquidate(stateDB, liquidator, testUser1, testYieldToken, testLiquidToken, bigInt("10000000000000000000")); err != ErrPositionHealthy {
+		t.Fatalf("expected ErrPositionHealthy, got %v", err)
+	}
+
+	// A 20% price drop, with no new borrowing, puts 90 debt against 72 allowed
+	oracle[testYieldToken] = priceBps(8000)
+	if hf := alchemist.GetHealthFactor(stateDB, testUser1, testYieldToken); hf.Cmp(big.NewInt(HealthFactorPrecision)) >= 0 {
+		t.Fatalf("health factor after price drop = %s, want below 1", hf)
+	}
+
+	// Repayment is capped at half the debt; the liquidator receives 45 * 1.05
+	// of value, 47.25 / 0.8 = 59.0625 tokens
+	seized, err := alchemist.Liquidate(stateDB, liquidator, testUser1, testYieldToken, testLiquidToken, bigInt("90000000000000000000"))
+	if err != nil {
+		t.Fatalf("Liquidate failed: %v", err)
+	}
+	if want := bigInt("59062500000000000000"); seized.Cmp(want) != 0 {
+		t.Errorf("seized = %s, want %s", seized, want)
+	}
+
+	account := alchemist.GetAccount(stateDB, testUser1, testYieldToken)
+	if want := bigInt("45000000000000000000"); account.Debt.Cmp(want) != 0 {
+		t.Errorf("debt after liquidation = %s, want %s", account.Debt, want)
+	}
+	if want := bigInt("40937500000000000000"); account.Collateral.Cmp(want) != 0 {
+		t.Errorf("collateral after liquidation = %s, want %s", account.Collateral, want)
+	}
+	if want := bigInt("45000000000000000000"); alchemist.liquidTokens[testLiquidToken].TotalMinted.Cmp(want) != 0 {
+		t.Errorf("total minted after liquidation = %s, want %s", alchemist.liquidTokens[testLiquidToken].TotalMinted, want)
+	}
+
+	// 100 - 45 burned + 59.0625 seized
+	if want := bigInt("114062500000000000000"); stateDB.GetBalance(liquidator).ToBig().Cmp(want) != 0 {
+		t.Errorf("liquidator balance = %s, want %s", stateDB.GetBalance(liquidator), want)
+	}
+
+	// Once the price recovers to 1.5 the 45 debt is within 0.9 * 61.4 value
+	oracle[testYieldToken] = priceBps(15000)
+	if _, err := alchemist.Liquidate(stateDB, liquidator, testUser1, testYieldToken, testLiquidToken, big.NewInt(1)); err != ErrPositionHealthy {
+		t.Errorf("expected ErrPositionHealthy after price recovery, got %v", err)
+	}
+
+	// A second yield token with its own bonus pays it out independently: a
+	// 40 price drop on 10 debt seizes 5 * 1.1 / 0.6 = 9.1666... tokens
+	other := common.HexToAddress("0x6666666666666666666666666666666666666666")
+	alchemist.AddYieldToken(stateDB, other, testUnderlying, big.NewInt(0))
+	alchemist.SetCollateralOracle(stateDB, other, oracle)
+	alchemist.SetLiquidationBonus(stateDB, other, 1000)
+	oracle[other] = priceBps(10000)
+	alchemist.Deposit(stateDB, testUser1, other, bigInt("12000000000000000000"))
+	if err := alchemist.Mint(stateDB, testUser1, other, testLiquidToken, bigInt("10000000000000000000")); err != nil {
+		t.Fatalf("Mint on second token failed: %v", err)
+	}
+	oracle[other] = priceBps(6000)
+	seized, err = alchemist.Liquidate(stateDB, liquidator, testUser1, other, testLiquidToken, bigInt("10000000000000000000"))
+	if err != nil {
+		t.Fatalf("Liquidate on second token failed: %v", err)
+	}
+	if want := bigInt("9166666666666666666"); seized.Cmp(want) != 0 {
+		t.Errorf("seized with 10%% bonus = %s, want %s", seized, want)
+	}
+	if yt := alchemist.yieldTokens[testYieldToken]; yt.LiquidationBonus != 500 {
+		t.Errorf("first token bonus changed to %d", yt.LiquidationBonus)
+	}
+}
+
+func TestLiquid_SetLiquidationParams(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	yt := alchemist.yieldTokens[testYieldToken]
+	if yt.CloseFactor != DefaultLiquidCloseFactor || yt.LiquidationBonus != DefaultLiquidLiquidationBonus {
+		t.Errorf("defaults = %d/%d, want %d/%d", yt.CloseFactor, yt.LiquidationBonus, DefaultLiquidCloseFactor, DefaultLiquidLiquidationBonus)
+	}
+
+	if err := alchemist.SetCloseFactor(stateDB, testYieldToken, 0); err != ErrInvalidParameter {
+		t.Errorf("expected ErrInvalidParameter for zero close factor, got %v", err)
+	}
+	if err := alchemist.SetCloseFactor(stateDB, testYieldToken, LTVPrecision+1); err != ErrInvalidParameter {
+		t.Errorf("expected ErrInvalidParameter for close factor above 100%%, got %v", err)
+	}
+	if err := alchemist.SetLiquidationBonus(stateDB, testYieldToken, MaxLiquidLiquidationBonus+1); err != ErrInvalidParameter {
+		t.Errorf("expected ErrInvalidParameter for bonus above max, got %v", err)
+	}
+	if err := alchemist.SetCloseFactor(stateDB, testUser2, 2500); err != ErrInvalidYieldToken {
+		t.Errorf("expected ErrInvalidYieldToken, got %v", err)
+	}
+	if err := alchemist.SetLiquidationBonus(stateDB, testUser2, 800); err != ErrInvalidYieldToken {
+		t.Errorf("expected ErrInvalidYieldToken, got %v", err)
+	}
+
+	if err := alchemist.SetCloseFactor(stateDB, testYieldToken, 2500); err != nil {
+		t.Fatalf("SetCloseFactor failed: %v", err)
+	}
+	if err := alchemist.SetLiquidationBonus(stateDB, testYieldToken, 800); err != nil {
+		t.Fatalf("SetLiquidationBonus failed: %v", err)
+	}
+	if yt.CloseFactor != 2500 || yt.LiquidationBonus != 800 {
+		t.Errorf("params = %d/%d, want 2500/800", yt.CloseFactor, yt.LiquidationBonus)
+	}
+}
+
+func TestLiquid_Liquidate_CloseFactorRepeated(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, bigInt("1000000000000000000000000"))
+	oracle := testCollateralOracle{testYieldToken: priceBps(10000)}
+	alchemist.SetCollateralOracle(stateDB, testYieldToken, oracle)
+	alchemist.SetCloseFactor(stateDB, testYieldToken, 2500)
+
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, bigInt("100000000000000000000")) // 100 tokens
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, bigInt("90000000000000000000")); err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+
+	liquidator := testUser2
+	setBalance(stateDB, liquidator, bigInt("100000000000000000000"))
+
+	// At 0.96 the 90 debt is above 0.9 * 96 value
+	oracle[testYieldToken] = priceBps(9600)
+
+	// Even asking for the whole debt, one call repays a quarter of it, 22.5,
+	// and seizes 22.5 * 1.05 / 0.96 = 24.609375 tokens
+	seized, err := alchemist.Liquidate(stateDB, liquidator, testUser1, testYieldToken, testLiquidToken, bigInt("90000000000000000000"))
+	if err != nil {
+		t.Fatalf("Liquidate failed: %v", err)
+	}
+	if want := bigInt("24609375000000000000"); seized.Cmp(want) != 0 {
+		t.Errorf("seized = %s, want %s", seized, want)
+	}
+	account := alchemist.GetAccount(stateDB, testUser1, testYieldToken)
+	if want := bigInt("67500000000000000000"); account.Debt.Cmp(want) != 0 {
+		t.Errorf("debt after liquidation = %s, want %s", account.Debt, want)
+	}
+
+	// Further calls each repay at most a quarter of the remaining debt until
+	// the account is healthy again
+	calls := 1
+	for {
+		debt := new(big.Int).Set(account.Debt)
+		_, err := alchemist.Liquidate(stateDB, liquidator, testUser1, testYieldToken, testLiquidToken, debt)
+		if err == ErrPositionHealthy {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Liquidate %d failed: %v", calls+1, err)
+		}
+		calls++
+
+		account = alchemist.GetAccount(stateDB, testUser1, testYieldToken)
+		repaid := new(big.Int).Sub(debt, account.Debt)
+		if limit := new(big.Int).Div(new(big.Int).Mul(debt, big.NewInt(2500)), big.NewInt(LTVPrecision)); repaid.Cmp(limit) > 0 {
+			t.Fatalf("call %d repaid %s, above close factor limit %s", calls, repaid, limit)
+		}
+		if calls > 20 {
+			t.Fatal("account never became healthy")
+		}
+	}
+	if calls != 5 {
+		t.Errorf("liquidations needed = %d, want 5", calls)
+	}
+	if account.Debt.Sign() == 0 {
+		t.Error("close factor should leave the healthy remainder of the debt in place")
+	}
+}
+
+func TestLiquid_Liquidate_OneToOneNeverLiquidatable(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, bigInt("1000000000000000000000000"))
+
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, bigInt("100000000000000000000"))
+	alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, bigInt("90000000000000000000"))
+
+	if _, err := alchemist.Liquidate(stateDB, testUser2, testUser1, testYieldToken, testLiquidToken, bigInt("1000000000000000000")); err != ErrPositionHealthy {
+		t.Errorf("expected ErrPositionHealthy, got %v", err)
+	}
+	if _, err := alchemist.Liquidate(stateDB, testUser2, testUser2, testYieldToken, testLiquidToken, bigInt("1000000000000000000")); err != ErrNoDebtToRepay {
+		t.Errorf("expected ErrNoDebtToRepay, got %v", err)
+	}
+}
+
 func TestLiquid_Burn(t *testing.T) {
 	pm := NewPoolManager()
 	alchemist := NewLiquid(pm)
@@ -276,6 +737,158 @@ func TestLiquid_Withdraw_WithDebt(t *testing.T) {
 	}
 }
 
+func TestLiquid_DeactivateYieldToken(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	debtCeiling := bigInt("1000000000000000000000000")
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, debtCeiling)
+
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	depositAmount := bigInt("100000000000000000000") // 100 tokens
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, depositAmount)
+	mintAmount := bigInt("20000000000000000000") // 20 tokens
+	alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, mintAmount)
+
+	if err := alchemist.DeactivateYieldToken(stateDB, testYieldToken); err != nil {
+		t.Fatalf("DeactivateYieldToken failed: %v", err)
+	}
+	if err := alchemist.DeactivateYieldToken(stateDB, testYieldToken); err != ErrInvalidYieldToken {
+		t.Fatalf("expected ErrInvalidYieldToken deactivating twice, got %v", err)
+	}
+
+	// New deposits and mints are blocked
+	if err := alchemist.Deposit(stateDB, testUser1, testYieldToken, big.NewInt(1)); err != ErrInvalidYieldToken {
+		t.Fatalf("expected ErrInvalidYieldToken on deposit, got %v", err)
+	}
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, big.NewInt(1)); err != ErrInvalidYieldToken {
+		t.Fatalf("expected ErrInvalidYieldToken on mint, got %v", err)
+	}
+
+	// Repaying and withdrawing still work
+	if err := alchemist.Burn(stateDB, testUser1, testYieldToken, testLiquidToken, mintAmount); err != nil {
+		t.Fatalf("Burn after deactivation failed: %v", err)
+	}
+	withdrawAmount := bigInt("50000000000000000000") // 50 tokens
+	if err := alchemist.Withdraw(stateDB, testUser1, testYieldToken, withdrawAmount); err != nil {
+		t.Fatalf("Withdraw after deactivation failed: %v", err)
+	}
+	account := alchemist.GetAccount(stateDB, testUser1, testYieldToken)
+	expectedCollateral := new(big.Int).Sub(depositAmount, withdrawAmount)
+	if account.Collateral.Cmp(expectedCollateral) != 0 {
+		t.Fatalf("collateral mismatch: got %s, want %s", account.Collateral, expectedCollateral)
+	}
+}
+
+func TestLiquid_MigrateYieldToken(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	newYieldToken := common.HexToAddress("0x7777777777777777777777777777777777777777")
+	debtCeiling := bigInt("1000000000000000000000000")
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddYieldToken(stateDB, newYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, debtCeiling)
+
+	deposit1 := bigInt("100000000000000000000") // 100 tokens
+	deposit2 := bigInt("40000000000000000000")  // 40 tokens
+	existing := bigInt("10000000000000000000")  // 10 tokens already in the new strategy
+	debt1 := bigInt("30000000000000000000")     // 30 tokens
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	setBalance(stateDB, testUser2, bigInt("1000000000000000000000"))
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, deposit1)
+	alchemist.Deposit(stateDB, testUser2, testYieldToken, deposit2)
+	alchemist.Deposit(stateDB, testUser2, newYieldToken, existing)
+	alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, debt1)
+
+	// The old token must be retired first
+	if err := alchemist.MigrateYieldToken(stateDB, testYieldToken, newYieldToken); err != ErrYieldTokenStillActive {
+		t.Fatalf("expected ErrYieldTokenStillActive, got %v", err)
+	}
+	alchemist.DeactivateYieldToken(stateDB, testYieldToken)
+
+	if err := alchemist.MigrateYieldToken(stateDB, testYieldToken, newYieldToken); err != nil {
+		t.Fatalf("MigrateYieldToken failed: %v", err)
+	}
+
+	// Each account's collateral and debt moved to the new strategy
+	for _, tc := range []struct {
+		owner      common.Address
+		collateral *big.Int
+		debt       *big.Int
+	}{
+		{testUser1, deposit1, debt1},
+		{testUser2, new(big.Int).Add(deposit2, existing), big.NewInt(0)},
+	} {
+		migrated := alchemist.GetAccount(stateDB, tc.owner, newYieldToken)
+		if migrated.Collateral.Cmp(tc.collateral) != 0 {
+			t.Fatalf("collateral mismatch for %s: got %s, want %s", tc.owner, migrated.Collateral, tc.collateral)
+		}
+		if migrated.Debt.Cmp(tc.debt) != 0 {
+			t.Fatalf("debt mismatch for %s: got %s, want %s", tc.owner, migrated.Debt, tc.debt)
+		}
+		if old := alchemist.GetAccount(stateDB, tc.owner, testYieldToken); old.Collateral.Sign() != 0 || old.Debt.Sign() != 0 {
+			t.Fatalf("old position not cleared for %s", tc.owner)
+		}
+	}
+
+	if total := alchemist.yieldTokens[testYieldToken].TotalDeposited; total.Sign() != 0 {
+		t.Fatalf("old TotalDeposited should be zero, got %s", total)
+	}
+	wantTotal := new(big.Int).Add(deposit1, deposit2)
+	wantTotal.Add(wantTotal, existing)
+	if total := alchemist.yieldTokens[newYieldToken].TotalDeposited; total.Cmp(wantTotal) != 0 {
+		t.Fatalf("new TotalDeposited mismatch: got %s, want %s", total, wantTotal)
+	}
+}
+
+// Accounts are found through the owner index in state, so an instance that
+// never loaded them still migrates every position
+func TestLiquid_MigrateYieldTokenAfterReload(t *testing.T) {
+	stateDB := NewMockStateDB()
+	newYieldToken := common.HexToAddress("0x7777777777777777777777777777777777777777")
+	deposit := bigInt("100000000000000000000") // 100 tokens
+	debt := bigInt("30000000000000000000")     // 30 tokens
+
+	original := NewLiquid(NewPoolManager())
+	original.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	original.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, bigInt("1000000000000000000000000"))
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	original.Deposit(stateDB, testUser1, testYieldToken, deposit)
+	original.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, debt)
+
+	// A fresh instance over the same state has an empty account cache
+	reloaded := NewLiquid(NewPoolManager())
+	reloaded.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	reloaded.AddYieldToken(stateDB, newYieldToken, testUnderlying, big.NewInt(0))
+	reloaded.DeactivateYieldToken(stateDB, testYieldToken)
+	if err := reloaded.MigrateYieldToken(stateDB, testYieldToken, newYieldToken); err != nil {
+		t.Fatalf("MigrateYieldToken failed: %v", err)
+	}
+
+	// Read back through a third instance so only persisted state is checked
+	check := NewLiquid(NewPoolManager())
+	migrated := check.GetAccount(stateDB, testUser1, newYieldToken)
+	if migrated == nil {
+		t.Fatal("migrated account not found")
+	}
+	if migrated.Collateral.Cmp(deposit) != 0 {
+		t.Fatalf("collateral mismatch: got %s, want %s", migrated.Collateral, deposit)
+	}
+	if migrated.Debt.Cmp(debt) != 0 {
+		t.Fatalf("debt mismatch: got %s, want %s", migrated.Debt, debt)
+	}
+	if old := check.GetAccount(stateDB, testUser1, testYieldToken); old.Collateral.Sign() != 0 || old.Debt.Sign() != 0 {
+		t.Fatal("old position not cleared")
+	}
+	if zero := check.GetAccount(stateDB, common.Address{}, newYieldToken); zero != nil {
+		t.Fatal("collateral merged into the zero-address account")
+	}
+}
+
 func TestLiquid_GetMaxMintable(t *testing.T) {
 	pm := NewPoolManager()
 	alchemist := NewLiquid(pm)
@@ -344,6 +957,68 @@ func TestLiquid_GetTimeToRepayment(t *testing.T) {
 	}
 }
 
+// Helper to advance the block number seen by Liquid
+func setLiquidBlock(stateDB *MockStateDB, block uint64) {
+	blockKey := makeStorageKey(liquidGlobalPrefix, []byte("block"))
+	stateDB.SetState(liquidAddr, blockKey, common.BigToHash(new(big.Int).SetUint64(block)))
+}
+
+func TestLiquid_GetAccruedYield(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	stateDB := NewMockStateDB()
+
+	yieldPerBlock := bigInt("1000000000000000") // 0.001 per block per unit
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, yieldPerBlock)
+
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	depositAmount := bigInt("100000000000000000000") // 100 tokens
+	setLiquidBlock(stateDB, 10)
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, depositAmount)
+
+	if accrued := alchemist.GetAccruedYield(stateDB, testUser1, testYieldToken); accrued.Sign() != 0 {
+		t.Fatalf("expected no accrued yield at deposit block, got %s", accrued)
+	}
+
+	// 100e18 * 1e15 / 1e18 = 1e17 per block, growing linearly
+	perBlock := bigInt("100000000000000000")
+	for _, elapsed := range []uint64{1, 5, 20} {
+		setLiquidBlock(stateDB, 10+elapsed)
+		want := new(big.Int).Mul(perBlock, new(big.Int).SetUint64(elapsed))
+		if accrued := alchemist.GetAccruedYield(stateDB, testUser1, testYieldToken); accrued.Cmp(want) != 0 {
+			t.Fatalf("accrued yield after %d blocks: got %s, want %s", elapsed, accrued, want)
+		}
+	}
+
+	// The query does not harvest
+	account := alchemist.GetAccount(stateDB, testUser1, testYieldToken)
+	if account.LastHarvestBlock != 10 || account.AccruedYield.Sign() != 0 {
+		t.Fatal("GetAccruedYield mutated the account")
+	}
+
+	// Harvesting collects the pending yield and resets the query
+	harvested, err := alchemist.Harvest(stateDB, testUser1, testYieldToken)
+	if err != nil {
+		t.Fatalf("Harvest failed: %v", err)
+	}
+	if want := new(big.Int).Mul(perBlock, big.NewInt(20)); harvested.Cmp(want) != 0 {
+		t.Fatalf("harvested mismatch: got %s, want %s", harvested, want)
+	}
+	if accrued := alchemist.GetAccruedYield(stateDB, testUser1, testYieldToken); accrued.Sign() != 0 {
+		t.Fatalf("expected accrued yield to reset after harvest, got %s", accrued)
+	}
+
+	setLiquidBlock(stateDB, 33)
+	if accrued := alchemist.GetAccruedYield(stateDB, testUser1, testYieldToken); accrued.Cmp(new(big.Int).Mul(perBlock, big.NewInt(3))) != 0 {
+		t.Fatalf("accrued yield after harvest mismatch: got %s", accrued)
+	}
+
+	// Unknown accounts earn nothing
+	if accrued := alchemist.GetAccruedYield(stateDB, testUser2, testYieldToken); accrued.Sign() != 0 {
+		t.Fatalf("expected zero for unknown account, got %s", accrued)
+	}
+}
+
 // =========================================================================
 // Transmuter Tests
 // =========================================================================
@@ -462,6 +1137,99 @@ func TestTransmuter_Deposit_And_Claim(t *testing.T) {
 	}
 }
 
+// reentrantToken simulates a token whose transfer hook calls back into the
+// precompile when the recipient is credited
+type reentrantToken struct {
+	*MockStateDB
+	recipient common.Address
+	onCredit  func()
+}
+
+func (r *reentrantToken) AddBalance(addr common.Address, amount *uint256.Int) {
+	r.MockStateDB.AddBalance(addr, amount)
+	if addr == r.recipient && r.onCredit != nil {
+		hook := r.onCredit
+		r.onCredit = nil // reenter once
+		hook()
+	}
+}
+
+func TestTransmuter_ClaimReentrancy(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	transmuter := NewTransmuter(alchemist)
+	mock := NewMockStateDB()
+	stateDB := &reentrantToken{MockStateDB: mock, recipient: testUser1}
+
+	transmuter.InitializeTransmuter(stateDB, testLiquidToken, testUnderlying)
+	setBalance(mock, testUser1, bigInt("1000000000000000000000"))
+	setBalance(mock, transmuterAddr, bigInt("1000000000000000000000"))
+
+	stakeAmount := bigInt("100000000000000000000")
+	transmuter.Stake(stateDB, testUser1, testLiquidToken, stakeAmount)
+	depositAmount := bigInt("50000000000000000000")
+	transmuter.Deposit(stateDB, testLiquidToken, depositAmount)
+
+	// The underlying transfer hook tries to claim again mid-Claim
+	var reentrantErr error
+	var reentrantClaimed *big.Int
+	stateDB.onCredit = func() {
+		reentrantClaimed, reentrantErr = transmuter.Claim(stateDB, testUser1, testLiquidToken)
+	}
+
+	claimed, err := transmuter.Claim(stateDB, testUser1, testLiquidToken)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if reentrantErr != ErrReentrant || reentrantClaimed != nil {
+		t.Fatalf("expected reentrant Claim to fail with ErrReentrant, got %v (%v)", reentrantErr, reentrantClaimed)
+	}
+	if claimed.Cmp(depositAmount) != 0 {
+		t.Fatalf("claimed amount mismatch: got %s, want %s", claimed, depositAmount)
+	}
+	wantBalance := new(big.Int).Sub(bigInt("1000000000000000000000"), stakeAmount)
+	wantBalance.Add(wantBalance, depositAmount)
+	if balance := mock.GetBalance(testUser1).ToBig(); balance.Cmp(wantBalance) != 0 {
+		t.Fatalf("underlying paid out more than once: got %s, want %s", balance, wantBalance)
+	}
+
+	// The lock is released once the outer call returns
+	claimed, err = transmuter.Claim(stateDB, testUser1, testLiquidToken)
+	if err != nil || claimed.Sign() != 0 {
+		t.Fatalf("expected empty claim after lock release, got %v (%v)", claimed, err)
+	}
+}
+
+func TestLiquid_MintReentrancy(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	mock := NewMockStateDB()
+	stateDB := &reentrantToken{MockStateDB: mock, recipient: testUser1}
+
+	debtCeiling := bigInt("1000000000000000000000000")
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, debtCeiling)
+	setBalance(mock, testUser1, bigInt("1000000000000000000000"))
+	alchemist.Deposit(stateDB, testUser1, testYieldToken, bigInt("100000000000000000000"))
+
+	// The synthetic mint hook tries to mint again mid-Mint
+	var reentrantErr error
+	stateDB.onCredit = func() {
+		reentrantErr = alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, big.NewInt(1))
+	}
+
+	mintAmount := bigInt("10000000000000000000")
+	if err := alchemist.Mint(stateDB, testUser1, testYieldToken, testLiquidToken, mintAmount); err != nil {
+		t.Fatalf("Mint failed: %v", err)
+	}
+	if reentrantErr != ErrReentrant {
+		t.Fatalf("expected ErrReentrant, got %v", reentrantErr)
+	}
+	if debt := alchemist.GetAccount(stateDB, testUser1, testYieldToken).Debt; debt.Cmp(mintAmount) != 0 {
+		t.Fatalf("debt mismatch: got %s, want %s", debt, mintAmount)
+	}
+}
+
 func TestTransmuter_Unstake(t *testing.T) {
 	pm := NewPoolManager()
 	alchemist := NewLiquid(pm)
@@ -531,6 +1299,216 @@ func TestTransmuter_MultipleStakers(t *testing.T) {
 	}
 }
 
+func TestTransmuter_DustAccounting(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	transmuter := NewTransmuter(alchemist)
+	stateDB := NewMockStateDB()
+
+	// Setup
+	transmuter.InitializeTransmuter(stateDB, testLiquidToken, testUnderlying)
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	setBalance(stateDB, testUser2, bigInt("1000000000000000000000"))
+	setBalance(stateDB, transmuterAddr, bigInt("1000000000000000000000"))
+
+	// Stakes that don't divide evenly into anything
+	transmuter.Stake(stateDB, testUser1, testLiquidToken, bigInt("100000000000000000007"))
+	transmuter.Stake(stateDB, testUser2, testLiquidToken, bigInt("300000000000000000011"))
+
+	// Many small, odd-sized deposits so every division rounds
+	totalDeposited := big.NewInt(0)
+	totalClaimed := big.NewInt(0)
+	for i := 0; i < 200; i++ {
+		amount := big.NewInt(int64(1000003 + 17*i))
+		if err := transmuter.Deposit(stateDB, testLiquidToken, amount); err != nil {
+			t.Fatalf("Deposit failed: %v", err)
+		}
+		totalDeposited.Add(totalDeposited, amount)
+
+		// User1 claims periodically, user2 only at the end
+		if i%25 == 0 {
+			claimed, err := transmuter.Claim(stateDB, testUser1, testLiquidToken)
+			if err != nil {
+				t.Fatalf("Claim failed: %v", err)
+			}
+			totalClaimed.Add(totalClaimed, claimed)
+		}
+	}
+
+	for _, user := range []common.Address{testUser1, testUser2} {
+		claimed, err := transmuter.Claim(stateDB, user, testLiquidToken)
+		if err != nil {
+			t.Fatalf("Claim failed: %v", err)
+		}
+		totalClaimed.Add(totalClaimed, claimed)
+	}
+
+	// claims * Q96 + dust (pool and per-stake) == deposits * Q96, exactly
+	state := transmuter.GetLiquidFXState(testLiquidToken)
+	dust := new(big.Int).Set(state.DustBuffer)
+	for _, user := range []common.Address{testUser1, testUser2} {
+		dust.Add(dust, transmuter.GetStake(stateDB, user, testLiquidToken).AccrualDust)
+	}
+
+	lhs := new(big.Int).Mul(totalClaimed, Q96)
+	lhs.Add(lhs, dust)
+	rhs := new(big.Int).Mul(totalDeposited, Q96)
+	if lhs.Cmp(rhs) != 0 {
+		t.Fatalf("claims + dust != deposits: got %s, want %s", lhs, rhs)
+	}
+
+	// Outstanding dust is less than one unit per participant
+	dustUnits := new(big.Int).Div(dust, Q96)
+	if dustUnits.Cmp(big.NewInt(3)) >= 0 {
+		t.Fatalf("dust too large: %s units", dustUnits)
+	}
+}
+
+func TestTransmuter_DepositWithoutStakersIsCarried(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	transmuter := NewTransmuter(alchemist)
+	stateDB := NewMockStateDB()
+
+	transmuter.InitializeTransmuter(stateDB, testLiquidToken, testUnderlying)
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	setBalance(stateDB, transmuterAddr, bigInt("1000000000000000000000"))
+
+	// Deposit before anyone stakes is held as dust
+	early := bigInt("10000000000000000000")
+	transmuter.Deposit(stateDB, testLiquidToken, early)
+
+	stakeAmount := bigInt("100000000000000000000")
+	transmuter.Stake(stateDB, testUser1, testLiquidToken, stakeAmount)
+
+	// The next deposit distributes both
+	late := bigInt("20000000000000000000")
+	transmuter.Deposit(stateDB, testLiquidToken, late)
+
+	claimed, err := transmuter.Claim(stateDB, testUser1, testLiquidToken)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	// Everything except sub-unit dust reaches the staker
+	state := transmuter.GetLiquidFXState(testLiquidToken)
+	stake := transmuter.GetStake(stateDB, testUser1, testLiquidToken)
+	accounted := new(big.Int).Mul(claimed, Q96)
+	accounted.Add(accounted, state.DustBuffer)
+	accounted.Add(accounted, stake.AccrualDust)
+
+	expected := new(big.Int).Add(early, late)
+	if accounted.Cmp(new(big.Int).Mul(expected, Q96)) != 0 {
+		t.Fatalf("early deposit lost: claimed %s of %s", claimed, expected)
+	}
+	if new(big.Int).Sub(expected, claimed).Cmp(big.NewInt(1)) > 0 {
+		t.Fatalf("claimed too little: got %s, want ~%s", claimed, expected)
+	}
+}
+
+func TestTransmuter_TotalStakedTracksBurn(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	transmuter := NewTransmuter(alchemist)
+	stateDB := NewMockStateDB()
+
+	transmuter.InitializeTransmuter(stateDB, testLiquidToken, testUnderlying)
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	setBalance(stateDB, testUser2, bigInt("1000000000000000000000"))
+	setBalance(stateDB, transmuterAddr, bigInt("1000000000000000000000"))
+
+	transmuter.Stake(stateDB, testUser1, testLiquidToken, bigInt("100000000000000000000"))
+	transmuter.Stake(stateDB, testUser2, testLiquidToken, bigInt("300000000000000000000"))
+	transmuter.Deposit(stateDB, testLiquidToken, bigInt("40000000000000000000"))
+
+	for _, user := range []common.Address{testUser1, testUser2} {
+		if _, err := transmuter.Claim(stateDB, user, testLiquidToken); err != nil {
+			t.Fatalf("Claim failed: %v", err)
+		}
+	}
+
+	// Claiming burned liquid; the total must shrink with it so the next
+	// deposit is shared only among the liquid still staked
+	staked := new(big.Int).Add(
+		transmuter.GetStake(stateDB, testUser1, testLiquidToken).StakedAmount,
+		transmuter.GetStake(stateDB, testUser2, testLiquidToken).StakedAmount,
+	)
+	state := transmuter.GetLiquidFXState(testLiquidToken)
+	if state.TotalStaked.Cmp(staked) != 0 {
+		t.Fatalf("TotalStaked: expected %s, got %s", staked, state.TotalStaked)
+	}
+	if staked.Cmp(bigInt("400000000000000000000")) >= 0 {
+		t.Errorf("Claims should have burned liquid, %s still staked", staked)
+	}
+}
+
+func TestTransmuter_DustPersisted(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	transmuter := NewTransmuter(alchemist)
+	stateDB := NewMockStateDB()
+
+	transmuter.InitializeTransmuter(stateDB, testLiquidToken, testUnderlying)
+	setBalance(stateDB, testUser1, bigInt("1000000000000000000000"))
+	setBalance(stateDB, transmuterAddr, bigInt("1000000000000000000000"))
+
+	transmuter.Stake(stateDB, testUser1, testLiquidToken, bigInt("300000000000000000011"))
+	transmuter.Deposit(stateDB, testLiquidToken, big.NewInt(1000003))
+	if _, err := transmuter.Claim(stateDB, testUser1, testLiquidToken); err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	state := transmuter.GetLiquidFXState(testLiquidToken)
+	stake := transmuter.GetStake(stateDB, testUser1, testLiquidToken)
+	if state.DustBuffer.Sign() == 0 || stake.AccrualDust.Sign() == 0 {
+		t.Fatalf("amounts should leave dust: pool %s, stake %s", state.DustBuffer, stake.AccrualDust)
+	}
+
+	// Both remainders are in state, not only in memory
+	stored := stateDB.GetState(transmuterAddr, transmuterDustKey(transmuterStatePrefix, testLiquidToken.Bytes()))
+	if got := new(big.Int).SetBytes(stored[:]); got.Cmp(state.DustBuffer) != 0 {
+		t.Errorf("Stored pool dust: expected %s, got %s", state.DustBuffer, got)
+	}
+	reloaded := NewTransmuter(alchemist).GetStake(stateDB, testUser1, testLiquidToken)
+	if reloaded.AccrualDust.Cmp(stake.AccrualDust) != 0 {
+		t.Errorf("Reloaded stake dust: expected %s, got %s", stake.AccrualDust, reloaded.AccrualDust)
+	}
+}
+
+func TestTransmuter_DepositBoundedWithoutStakers(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	transmuter := NewTransmuter(alchemist)
+	stateDB := NewMockStateDB()
+
+	transmuter.InitializeTransmuter(stateDB, testLiquidToken, testUnderlying)
+
+	// Scaled by Q96, this would not fit a storage slot
+	huge := new(big.Int).Lsh(big.NewInt(1), 200)
+	if err := transmuter.Deposit(stateDB, testLiquidToken, huge); err != ErrInvalidAmount {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+
+	// With nobody staked the whole deposit is held as Q96-scaled dust; the
+	// largest buffer allowed still fits its slot
+	limit := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	if err := transmuter.Deposit(stateDB, testLiquidToken, limit); err != nil {
+		t.Fatalf("Deposit failed: %v", err)
+	}
+	state := transmuter.GetLiquidFXState(testLiquidToken)
+	stored := stateDB.GetState(transmuterAddr, transmuterDustKey(transmuterStatePrefix, testLiquidToken.Bytes()))
+	if got := new(big.Int).SetBytes(stored[:]); got.Cmp(state.DustBuffer) != 0 {
+		t.Errorf("Stored pool dust: expected %s, got %s", state.DustBuffer, got)
+	}
+
+	if err := transmuter.Deposit(stateDB, testLiquidToken, big.NewInt(1)); err != ErrInvalidAmount {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+	if state.ExchangeBuffer.Cmp(limit) != 0 {
+		t.Errorf("rejected deposits changed the buffer to %s", state.ExchangeBuffer)
+	}
+}
+
 // =========================================================================
 // Integration Tests
 // =========================================================================
@@ -583,6 +1561,91 @@ func TestLiquid_FullFlow(t *testing.T) {
 	t.Logf("  Staked in transmuter: %s", stake.StakedAmount)
 }
 
+func TestTransmuter_Zap(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	transmuter := NewTransmuter(alchemist)
+	stateDB := NewMockStateDB()
+
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, bigInt("1000000000000000000000000"))
+	transmuter.InitializeTransmuter(stateDB, testLiquidToken, testUnderlying)
+	balance := bigInt("1000000000000000000000")
+	setBalance(stateDB, testUser1, balance)
+
+	depositAmount := bigInt("100000000000000000000")
+	mintAmount := bigInt("80000000000000000000")
+	if err := transmuter.Zap(stateDB, testUser1, testYieldToken, testLiquidToken, depositAmount, mintAmount); err != nil {
+		t.Fatalf("Zap failed: %v", err)
+	}
+
+	account := alchemist.GetAccount(stateDB, testUser1, testYieldToken)
+	if account.Collateral.Cmp(depositAmount) != 0 {
+		t.Fatalf("collateral mismatch: got %s, want %s", account.Collateral, depositAmount)
+	}
+	if account.Debt.Cmp(mintAmount) != 0 {
+		t.Fatalf("debt mismatch: got %s, want %s", account.Debt, mintAmount)
+	}
+
+	// The stake is what was minted net of the mint fee
+	netMint := new(big.Int).Sub(mintAmount, alchemist.calculateFee(mintAmount, alchemist.liquidTokens[testLiquidToken].MintFee))
+	stake := transmuter.GetStake(stateDB, testUser1, testLiquidToken)
+	if stake == nil || stake.StakedAmount.Cmp(netMint) != 0 {
+		t.Fatalf("stake should hold the net minted amount %s, got %v", netMint, stake)
+	}
+	if total := transmuter.GetLiquidFXState(testLiquidToken).TotalStaked; total.Cmp(netMint) != 0 {
+		t.Fatalf("total staked mismatch: got %s, want %s", total, netMint)
+	}
+	if total := alchemist.liquidTokens[testLiquidToken].TotalMinted; total.Cmp(mintAmount) != 0 {
+		t.Fatalf("total minted mismatch: got %s, want %s", total, mintAmount)
+	}
+	wantBalance := new(big.Int).Sub(balance, depositAmount)
+	if got := stateDB.GetBalance(testUser1).ToBig(); got.Cmp(wantBalance) != 0 {
+		t.Fatalf("owner balance should only lose the deposit: got %s, want %s", got, wantBalance)
+	}
+}
+
+func TestTransmuter_ZapRevertsOnFailure(t *testing.T) {
+	pm := NewPoolManager()
+	alchemist := NewLiquid(pm)
+	transmuter := NewTransmuter(alchemist)
+	stateDB := NewMockStateDB()
+
+	alchemist.AddYieldToken(stateDB, testYieldToken, testUnderlying, big.NewInt(0))
+	alchemist.AddLiquidToken(stateDB, testLiquidToken, testUnderlying, bigInt("1000000000000000000000000"))
+	transmuter.InitializeTransmuter(stateDB, testLiquidToken, testUnderlying)
+	balance := bigInt("1000000000000000000000")
+	setBalance(stateDB, testUser1, balance)
+
+	// Minting 91 against 100 exceeds the 90% LTV
+	depositAmount := bigInt("100000000000000000000")
+	err := transmuter.Zap(stateDB, testUser1, testYieldToken, testLiquidToken, depositAmount, bigInt("91000000000000000000"))
+	if err != ErrMaxLTVExceeded {
+		t.Fatalf("expected ErrMaxLTVExceeded, got %v", err)
+	}
+
+	if account := alchemist.GetAccount(stateDB, testUser1, testYieldToken); account != nil {
+		t.Fatalf("failed zap should not create an account, got collateral %s", account.Collateral)
+	}
+	if total := alchemist.yieldTokens[testYieldToken].TotalDeposited; total.Sign() != 0 {
+		t.Fatalf("failed zap should not count the deposit, got %s", total)
+	}
+	if stake := transmuter.GetStake(stateDB, testUser1, testLiquidToken); stake != nil {
+		t.Fatalf("failed zap should not stake, got %s", stake.StakedAmount)
+	}
+	if got := stateDB.GetBalance(testUser1).ToBig(); got.Cmp(balance) != 0 {
+		t.Fatalf("failed zap should not move tokens: got %s, want %s", got, balance)
+	}
+
+	// Both locks are released, so the plain flow still works
+	if err := alchemist.Deposit(stateDB, testUser1, testYieldToken, depositAmount); err != nil {
+		t.Fatalf("Deposit after failed zap failed: %v", err)
+	}
+	if err := transmuter.Zap(stateDB, testUser1, testYieldToken, testLiquidToken, big.NewInt(0), big.NewInt(1)); err != ErrInvalidAmount {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+}
+
 // =========================================================================
 // Benchmark Tests
 // =========================================================================
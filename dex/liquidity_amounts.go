@@ -0,0 +1,114 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+)
+
+// Uniswap v3 LiquidityAmounts: converts between liquidity and token amounts
+// for a position over [sqrtLower, sqrtUpper] at the current sqrt price. All
+// results round down, as in the Solidity library, so a position manager
+// never asks a user for more than they supplied.
+
+// maxLiquidity is the largest liquidity a position can hold (uint128)
+var maxLiquidity = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// GetLiquidityForAmounts returns the most liquidity that amount0 and amount1
+// can provide over the range at sqrtPriceX96
+func GetLiquidityForAmounts(sqrtPriceX96, sqrtLower, sqrtUpper, amount0, amount1 *big.Int) (*big.Int, error) {
+	sqrtLower, sqrtUpper, err := sortSqrtRange(sqrtPriceX96, sqrtLower, sqrtUpper)
+	if err != nil {
+		return nil, err
+	}
+	if amount0 == nil || amount1 == nil || amount0.Sign() < 0 || amount1.Sign() < 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	var liquidity *big.Int
+	switch {
+	case sqrtPriceX96.Cmp(sqrtLower) <= 0:
+		liquidity = liquidityForAmount0(sqrtLower, sqrtUpper, amount0)
+	case sqrtPriceX96.Cmp(sqrtUpper) < 0:
+		liquidity0 := liquidityForAmount0(sqrtPriceX96, sqrtUpper, amount0)
+		liquidity1 := liquidityForAmount1(sqrtLower, sqrtPriceX96, amount1)
+		liquidity = liquidity0
+		if liquidity1.Cmp(liquidity0) < 0 {
+			liquidity = liquidity1
+		}
+	default:
+		liquidity = liquidityForAmount1(sqrtLower, sqrtUpper, amount1)
+	}
+
+	if liquidity.Cmp(maxLiquidity) > 0 {
+		return nil, ErrLiquidityOverflow
+	}
+	return liquidity, nil
+}
+
+// GetAmountsForLiquidity returns the token amounts liquidity is worth over
+// the range at sqrtPriceX96
+func GetAmountsForLiquidity(sqrtPriceX96, sqrtLower, sqrtUpper, liquidity *big.Int) (*big.Int, *big.Int, error) {
+	sqrtLower, sqrtUpper, err := sortSqrtRange(sqrtPriceX96, sqrtLower, sqrtUpper)
+	if err != nil {
+		return nil, nil, err
+	}
+	if liquidity == nil || liquidity.Sign() < 0 || liquidity.Cmp(maxLiquidity) > 0 {
+		return nil, nil, ErrInvalidAmount
+	}
+
+	switch {
+	case sqrtPriceX96.Cmp(sqrtLower) <= 0:
+		return amount0ForLiquidity(sqrtLower, sqrtUpper, liquidity), big.NewInt(0), nil
+	case sqrtPriceX96.Cmp(sqrtUpper) < 0:
+		return amount0ForLiquidity(sqrtPriceX96, sqrtUpper, liquidity),
+			amount1ForLiquidity(sqrtLower, sqrtPriceX96, liquidity), nil
+	default:
+		return big.NewInt(0), amount1ForLiquidity(sqrtLower, sqrtUpper, liquidity), nil
+	}
+}
+
+// sortSqrtRange validates the sqrt prices and orders the range bounds
+func sortSqrtRange(sqrtPriceX96, sqrtLower, sqrtUpper *big.Int) (*big.Int, *big.Int, error) {
+	for _, p := range []*big.Int{sqrtPriceX96, sqrtLower, sqrtUpper} {
+		if p == nil || p.Sign() <= 0 {
+			return nil, nil, ErrInvalidSqrtPrice
+		}
+	}
+	if sqrtLower.Cmp(sqrtUpper) > 0 {
+		sqrtLower, sqrtUpper = sqrtUpper, sqrtLower
+	}
+	if sqrtLower.Cmp(sqrtUpper) == 0 {
+		return nil, nil, ErrInvalidTickRange
+	}
+	return sqrtLower, sqrtUpper, nil
+}
+
+// liquidityForAmount0 = amount0 * (sqrtA * sqrtB / Q96) / (sqrtB - sqrtA)
+func liquidityForAmount0(sqrtA, sqrtB, amount0 *big.Int) *big.Int {
+	intermediate := new(big.Int).Mul(sqrtA, sqrtB)
+	intermediate.Div(intermediate, Q96)
+	liquidity := new(big.Int).Mul(amount0, intermediate)
+	return liquidity.Div(liquidity, new(big.Int).Sub(sqrtB, sqrtA))
+}
+
+// liquidityForAmount1 = amount1 * Q96 / (sqrtB - sqrtA)
+func liquidityForAmount1(sqrtA, sqrtB, amount1 *big.Int) *big.Int {
+	liquidity := new(big.Int).Mul(amount1, Q96)
+	return liquidity.Div(liquidity, new(big.Int).Sub(sqrtB, sqrtA))
+}
+
+// amount0ForLiquidity = (liquidity << 96) * (sqrtB - sqrtA) / sqrtB / sqrtA
+func amount0ForLiquidity(sqrtA, sqrtB, liquidity *big.Int) *big.Int {
+	amount0 := new(big.Int).Lsh(liquidity, 96)
+	amount0.Mul(amount0, new(big.Int).Sub(sqrtB, sqrtA))
+	amount0.Div(amount0, sqrtB)
+	return amount0.Div(amount0, sqrtA)
+}
+
+// amount1ForLiquidity = liquidity * (sqrtB - sqrtA) / Q96
+func amount1ForLiquidity(sqrtA, sqrtB, liquidity *big.Int) *big.Int {
+	amount1 := new(big.Int).Mul(liquidity, new(big.Int).Sub(sqrtB, sqrtA))
+	return amount1.Div(amount1, Q96)
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+)
+
+// encodePriceSqrt mirrors the Uniswap v3 test helper: sqrt(reserve1 / reserve0) * 2^96
+func encodePriceSqrt(t *testing.T, reserve1, reserve0 int64) *big.Int {
+	t.Helper()
+	sqrtPriceX96, err := sqrtPriceX96FromPrice(big.NewRat(reserve1, reserve0))
+	if err != nil {
+		t.Fatalf("sqrtPriceX96FromPrice failed: %v", err)
+	}
+	return sqrtPriceX96
+}
+
+// Reference values from the Uniswap v3 periphery LiquidityAmounts tests,
+// over the range [sqrt(100/110), sqrt(110/100)]
+func TestLiquidityAmountsReference(t *testing.T) {
+	sqrtLower := encodePriceSqrt(t, 100, 110)
+	sqrtUpper := encodePriceSqrt(t, 110, 100)
+
+	tests := []struct {
+		name             string
+		sqrtPriceX96     *big.Int
+		liquidity        int64 // from amount0 = 100, amount1 = 200
+		amount0, amount1 int64 // back out of liquidity
+	}{
+		{name: "in range", sqrtPriceX96: encodePriceSqrt(t, 1, 1), liquidity: 2148, amount0: 99, amount1: 99},
+		{name: "below range", sqrtPriceX96: encodePriceSqrt(t, 99, 110), liquidity: 1048, amount0: 99, amount1: 0},
+		{name: "above range", sqrtPriceX96: encodePriceSqrt(t, 111, 100), liquidity: 2097, amount0: 0, amount1: 199},
+		{name: "at lower bound", sqrtPriceX96: sqrtLower, liquidity: 1048, amount0: 99, amount1: 0},
+		{name: "at upper bound", sqrtPriceX96: sqrtUpper, liquidity: 2097, amount0: 0, amount1: 199},
+	}
+
+	for _, tt := range tests {
+		liquidity, err := GetLiquidityForAmounts(tt.sqrtPriceX96, sqrtLower, sqrtUpper, big.NewInt(100), big.NewInt(200))
+		if err != nil {
+			t.Fatalf("%s: GetLiquidityForAmounts failed: %v", tt.name, err)
+		}
+		if liquidity.Int64() != tt.liquidity {
+			t.Errorf("%s: liquidity = %s, want %d", tt.name, liquidity, tt.liquidity)
+		}
+
+		amount0, amount1, err := GetAmountsForLiquidity(tt.sqrtPriceX96, sqrtLower, sqrtUpper, big.NewInt(tt.liquidity))
+		if err != nil {
+			t.Fatalf("%s: GetAmountsForLiquidity failed: %v", tt.name, err)
+		}
+		if amount0.Int64() != tt.amount0 || amount1.Int64() != tt.amount1 {
+			t.Errorf("%s: amounts = (%s, %s), want (%d, %d)", tt.name, amount0, amount1, tt.amount0, tt.amount1)
+		}
+
+		// Bounds may be given in either order
+		swapped, err := GetLiquidityForAmounts(tt.sqrtPriceX96, sqrtUpper, sqrtLower, big.NewInt(100), big.NewInt(200))
+		if err != nil || swapped.Cmp(liquidity) != 0 {
+			t.Errorf("%s: swapped bounds gave %v, %v", tt.name, swapped, err)
+		}
+	}
+}
+
+func TestLiquidityAmountsInvalid(t *testing.T) {
+	sqrtPriceX96 := new(big.Int).Set(Q96)
+	sqrtLower := mustSqrtRatio(t, -60)
+	sqrtUpper := mustSqrtRatio(t, 60)
+
+	if _, err := GetLiquidityForAmounts(sqrtPriceX96, sqrtLower, sqrtLower, big.NewInt(1), big.NewInt(1)); err != ErrInvalidTickRange {
+		t.Errorf("Expected ErrInvalidTickRange for empty range, got: %v", err)
+	}
+	if _, err := GetLiquidityForAmounts(big.NewInt(0), sqrtLower, sqrtUpper, big.NewInt(1), big.NewInt(1)); err != ErrInvalidSqrtPrice {
+		t.Errorf("Expected ErrInvalidSqrtPrice, got: %v", err)
+	}
+	if _, err := GetLiquidityForAmounts(sqrtPriceX96, sqrtLower, sqrtUpper, big.NewInt(-1), big.NewInt(1)); err != ErrInvalidAmount {
+		t.Errorf("Expected ErrInvalidAmount, got: %v", err)
+	}
+	if _, _, err := GetAmountsForLiquidity(sqrtPriceX96, sqrtLower, sqrtUpper, new(big.Int).Lsh(big.NewInt(1), 128)); err != ErrInvalidAmount {
+		t.Errorf("Expected ErrInvalidAmount for liquidity above uint128, got: %v", err)
+	}
+
+	// Full-range liquidity from huge amounts does not fit in uint128
+	huge := new(big.Int).Lsh(big.NewInt(1), 200)
+	if _, err := GetLiquidityForAmounts(sqrtPriceX96, MinSqrtRatio, MaxSqrtRatio, huge, huge); err != ErrLiquidityOverflow {
+		t.Errorf("Expected ErrLiquidityOverflow, got: %v", err)
+	}
+}
@@ -138,6 +138,11 @@ type DEXContract struct {
 	poolManager *PoolManager
 }
 
+// Name returns the precompile name
+func (c *DEXContract) Name() string {
+	return "DEX"
+}
+
 // Run executes the precompile
 func (c *DEXContract) Run(
 	accessibleState contract.AccessibleState,
@@ -147,6 +152,12 @@ func (c *DEXContract) Run(
 	suppliedGas uint64,
 	readOnly bool,
 ) (ret []byte, remainingGas uint64, err error) {
+	if tracer := contract.TracerFrom(accessibleState); tracer != nil {
+		tracer.OnEnter(addr, contract.Selector(input), len(input))
+		defer func() { tracer.OnExit(suppliedGas-remainingGas, err) }()
+	}
+	defer func() { err = contract.WrapRunError(c.Name(), addr, err) }()
+
 	if len(input) < 4 {
 		return nil, suppliedGas, fmt.Errorf("input too short")
 	}
@@ -0,0 +1,150 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/precompileconfig"
+)
+
+// traceEvent is one OnEnter/OnExit pair recorded by recordingTracer
+type traceEvent struct {
+	addr     common.Address
+	selector []byte
+	inputLen int
+	gasUsed  uint64
+	err      error
+}
+
+type recordingTracer struct {
+	events []traceEvent
+}
+
+func (r *recordingTracer) OnEnter(addr common.Address, selector []byte, inputLen int) {
+	r.events = append(r.events, traceEvent{addr: addr, selector: common.CopyBytes(selector), inputLen: inputLen})
+}
+
+func (r *recordingTracer) OnExit(gasUsed uint64, err error) {
+	last := &r.events[len(r.events)-1]
+	last.gasUsed, last.err = gasUsed, err
+}
+
+// contractStateDB backs the storage calls the pool manager makes with a
+// MockStateDB. Other contract.StateDB methods are not used by these tests.
+type contractStateDB struct {
+	contract.StateDB
+	mock *MockStateDB
+}
+
+func (s *contractStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	return s.mock.GetState(addr, key)
+}
+
+func (s *contractStateDB) SetState(addr common.Address, key, value common.Hash) common.Hash {
+	prev := s.mock.GetState(addr, key)
+	s.mock.SetState(addr, key, value)
+	return prev
+}
+
+type tracingState struct {
+	stateDB contract.StateDB
+	tracer  contract.Tracer
+}
+
+func (s *tracingState) GetStateDB() contract.StateDB                     { return s.stateDB }
+func (s *tracingState) GetBlockContext() contract.BlockContext           { return nil }
+func (s *tracingState) GetConsensusContext() context.Context             { return context.Background() }
+func (s *tracingState) GetChainConfig() precompileconfig.ChainConfig     { return nil }
+func (s *tracingState) GetPrecompileEnv() contract.PrecompileEnvironment { return nil }
+func (s *tracingState) GetTracer() contract.Tracer                       { return s.tracer }
+
+func encodeSwapCall(key PoolKey, params SwapParams) []byte {
+	input := make([]byte, 4+193)
+	binary.BigEndian.PutUint32(input[:4], SelectorSwap)
+	data := input[4:]
+	copy(data[12:32], key.Currency0.Address.Bytes())
+	copy(data[44:64], key.Currency1.Address.Bytes())
+	var fee [4]byte
+	binary.BigEndian.PutUint32(fee[:], key.Fee)
+	copy(data[64:67], fee[1:])
+	var spacing [4]byte
+	binary.BigEndian.PutUint32(spacing[:], uint32(key.TickSpacing))
+	copy(data[67:70], spacing[1:])
+	copy(data[76:96], key.Hooks.Bytes())
+	if params.ZeroForOne {
+		data[128] = 1
+	}
+	params.AmountSpecified.FillBytes(data[129:161])
+	params.SqrtPriceLimitX96.FillBytes(data[161:193])
+	return input
+}
+
+func TestDEXContractTracesSwap(t *testing.T) {
+	pm := newTestPoolManager()
+	c := &DEXContract{poolManager: pm}
+	mock := NewMockStateDB()
+	tracer := &recordingTracer{}
+	state := &tracingState{stateDB: &contractStateDB{mock: mock}, tracer: tracer}
+
+	key := newTestPoolKey()
+	if _, err := pm.Initialize(mock, key, new(big.Int).Lsh(big.NewInt(1), 96), nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	pm.pools[key.ID()].Liquidity = big.NewInt(1000000000)
+
+	input := encodeSwapCall(key, SwapParams{
+		ZeroForOne:        true,
+		AmountSpecified:   big.NewInt(1000),
+		SqrtPriceLimitX96: MinSqrtRatio,
+	})
+
+	// Outside a lock the swap fails
+	if _, _, err := c.Run(state, common.Address{}, poolManagerAddr, input, 1_000_000, false); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Expected ErrUnauthorized, got: %v", err)
+	}
+
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	pm.lockers = append(pm.lockers, caller)
+	pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+	if _, _, err := c.Run(state, caller, poolManagerAddr, input, 1_000_000, false); err != nil {
+		t.Fatalf("Swap failed: %v", err)
+	}
+
+	if len(tracer.events) != 2 {
+		t.Fatalf("Expected 2 trace events, got %d", len(tracer.events))
+	}
+	for i, ev := range tracer.events {
+		if ev.addr != poolManagerAddr {
+			t.Errorf("event %d: addr %s, want %s", i, ev.addr, poolManagerAddr)
+		}
+		if binary.BigEndian.Uint32(ev.selector) != SelectorSwap {
+			t.Errorf("event %d: selector %x, want swap", i, ev.selector)
+		}
+		if ev.inputLen != len(input) {
+			t.Errorf("event %d: inputLen %d, want %d", i, ev.inputLen, len(input))
+		}
+		if ev.gasUsed != GasSwap {
+			t.Errorf("event %d: gasUsed %d, want %d", i, ev.gasUsed, GasSwap)
+		}
+	}
+	if !errors.Is(tracer.events[0].err, ErrUnauthorized) {
+		t.Errorf("Expected first event to record ErrUnauthorized, got: %v", tracer.events[0].err)
+	}
+	if tracer.events[1].err != nil {
+		t.Errorf("Expected second event to succeed, got: %v", tracer.events[1].err)
+	}
+
+	// No tracer configured is not an error
+	state.tracer = nil
+	if _, _, err := c.Run(state, caller, poolManagerAddr, input, 1_000_000, false); err != nil {
+		t.Fatalf("Swap without tracer failed: %v", err)
+	}
+}
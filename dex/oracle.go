@@ -0,0 +1,234 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/luxfi/geth/common"
+)
+
+// OracleHistorySize is the number of aggregate prices kept per asset
+const OracleHistorySize = 256
+
+// OracleHub aggregates price reports from registered sources
+// Address: LP-9011 LXOracle
+//
+// Each report updates the asset's aggregate (median of the latest report
+// from every source) and appends it to a ring buffer of observations. The
+// TWAP served by GetTWAP is computed from these oracle reports; it is
+// separate from the tick-based TWAP a pool maintains through its hooks.
+// Reports are stamped with the block they were observed in and windows are
+// measured in blocks, so every node agrees on the result.
+type OracleHub struct {
+	Sources map[common.Address]bool // Authorized reporters
+
+	// Latest report per asset per source
+	reports map[common.Address]map[common.Address]*priceObservation
+
+	// Aggregate price history per asset
+	history map[common.Address]*priceRing
+
+	mu sync.RWMutex
+}
+
+// priceObservation is a price with the block it was observed in
+type priceObservation struct {
+	Block uint64
+	Price *big.Int
+}
+
+// priceRing is a fixed-size ring buffer of observations, oldest first
+type priceRing struct {
+	obs   []priceObservation
+	next  int
+	count int
+}
+
+// NewOracleHub creates a new oracle hub
+func NewOracleHub() *OracleHub {
+	return &OracleHub{
+		Sources: make(map[common.Address]bool),
+		reports: make(map[common.Address]map[common.Address]*priceObservation),
+		history: make(map[common.Address]*priceRing),
+	}
+}
+
+// AddSource authorizes a price reporter
+func (oh *OracleHub) AddSource(source common.Address) {
+	oh.mu.Lock()
+	defer oh.mu.Unlock()
+
+	oh.Sources[source] = true
+}
+
+// RemoveSource revokes a price reporter and drops its reports
+func (oh *OracleHub) RemoveSource(source common.Address) {
+	oh.mu.Lock()
+	defer oh.mu.Unlock()
+
+	delete(oh.Sources, source)
+	for _, reports := range oh.reports {
+		delete(reports, source)
+	}
+}
+
+// SubmitPrice records a report from source, observed at block, and appends
+// the new aggregate to the asset's history. Reports from blocks after the
+// current one are rejected.
+func (oh *OracleHub) SubmitPrice(
+	stateDB StateDB,
+	source common.Address,
+	asset common.Address,
+	price *big.Int,
+	block uint64,
+) error {
+	oh.mu.Lock()
+	defer oh.mu.Unlock()
+
+	if !oh.Sources[source] {
+		return ErrUnknownOracleSource
+	}
+	if price == nil || price.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+	if block > stateDB.GetBlockNumber() {
+		return ErrFutureReport
+	}
+
+	reports := oh.reports[asset]
+	if reports == nil {
+		reports = make(map[common.Address]*priceObservation)
+		oh.reports[asset] = reports
+	}
+	if prev := reports[source]; prev != nil && block < prev.Block {
+		return ErrStaleReport
+	}
+	reports[source] = &priceObservation{
+		Block: block,
+		Price: new(big.Int).Set(price),
+	}
+
+	ring := oh.history[asset]
+	if ring == nil {
+		ring = &priceRing{obs: make([]priceObservation, OracleHistorySize)}
+		oh.history[asset] = ring
+	}
+	if latest, ok := ring.latest(); ok && block < latest.Block {
+		block = latest.Block
+	}
+	ring.push(priceObservation{
+		Block: block,
+		Price: medianPrice(reports),
+	})
+
+	return nil
+}
+
+// GetPrice returns the current aggregate (spot) price for an asset
+func (oh *OracleHub) GetPrice(asset common.Address) (*big.Int, error) {
+	oh.mu.RLock()
+	defer oh.mu.RUnlock()
+
+	ring := oh.history[asset]
+	if ring == nil {
+		return nil, ErrPriceUnavailable
+	}
+	latest, ok := ring.latest()
+	if !ok {
+		return nil, ErrPriceUnavailable
+	}
+	return new(big.Int).Set(latest.Price), nil
+}
+
+// GetTWAP returns the time-weighted average of the aggregate price over the
+// last window blocks. Each observation holds until the next one (the latest
+// until the current block). If history does not reach back the full window,
+// the average covers the available span. A zero window returns the spot
+// price.
+func (oh *OracleHub) GetTWAP(stateDB StateDB, asset common.Address, window uint32) (*big.Int, error) {
+	oh.mu.RLock()
+	defer oh.mu.RUnlock()
+
+	ring := oh.history[asset]
+	if ring == nil || ring.count == 0 {
+		return nil, ErrPriceUnavailable
+	}
+
+	latest, _ := ring.latest()
+	end := stateDB.GetBlockNumber()
+	if end < latest.Block {
+		end = latest.Block
+	}
+	start := uint64(0)
+	if end > uint64(window) {
+		start = end - uint64(window)
+	}
+
+	weighted := big.NewInt(0)
+	covered := uint64(0)
+	segEnd := end
+	for i := ring.count - 1; i >= 0 && segEnd > start; i-- {
+		obs := ring.at(i)
+		segStart := obs.Block
+		if segStart < start {
+			segStart = start
+		}
+		if segEnd > segStart {
+			duration := segEnd - segStart
+			weighted.Add(weighted, new(big.Int).Mul(obs.Price, new(big.Int).SetUint64(duration)))
+			covered += duration
+		}
+		segEnd = segStart
+	}
+
+	if covered == 0 {
+		return new(big.Int).Set(latest.Price), nil
+	}
+	return weighted.Div(weighted, new(big.Int).SetUint64(covered)), nil
+}
+
+// push appends an observation, overwriting the oldest when full
+func (r *priceRing) push(obs priceObservation) {
+	r.obs[r.next] = obs
+	r.next = (r.next + 1) % len(r.obs)
+	if r.count < len(r.obs) {
+		r.count++
+	}
+}
+
+// at returns the i-th observation, 0 being the oldest retained
+func (r *priceRing) at(i int) priceObservation {
+	start := (r.next - r.count + len(r.obs)) % len(r.obs)
+	return r.obs[(start+i)%len(r.obs)]
+}
+
+// latest returns the most recent observation
+func (r *priceRing) latest() (priceObservation, bool) {
+	if r.count == 0 {
+		return priceObservation{}, false
+	}
+	return r.at(r.count - 1), true
+}
+
+// medianPrice returns the median of the reported prices; for an even
+// number of reports it is the mean of the two middle values
+func medianPrice(reports map[common.Address]*priceObservation) *big.Int {
+	prices := make([]*big.Int, 0, len(reports))
+	for _, r := range reports {
+		prices = append(prices, r.Price)
+	}
+	sort.Slice(prices, func(i, j int) bool {
+		return prices[i].Cmp(prices[j]) < 0
+	})
+
+	mid := len(prices) / 2
+	if len(prices)%2 == 1 {
+		return new(big.Int).Set(prices[mid])
+	}
+	median := new(big.Int).Add(prices[mid-1], prices[mid])
+	return median.Rsh(median, 1)
+}
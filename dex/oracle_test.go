@@ -0,0 +1,169 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Test addresses for the oracle hub
+var (
+	testOracleAsset   = common.HexToAddress("0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+	testOracleSource1 = common.HexToAddress("0xcccccccccccccccccccccccccccccccccccccccc")
+	testOracleSource2 = common.HexToAddress("0xdddddddddddddddddddddddddddddddddddddddd")
+	testOracleSource3 = common.HexToAddress("0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+)
+
+// newTestOracleHub returns a hub with one source and a state at block
+func newTestOracleHub(block uint64) (*OracleHub, *MockStateDB) {
+	oh := NewOracleHub()
+	oh.AddSource(testOracleSource1)
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(block)
+	return oh, stateDB
+}
+
+func TestOracleHub_SubmitPrice(t *testing.T) {
+	block := uint64(1000)
+	oh, stateDB := newTestOracleHub(block)
+
+	if _, err := oh.GetPrice(testOracleAsset); err != ErrPriceUnavailable {
+		t.Fatalf("expected ErrPriceUnavailable, got %v", err)
+	}
+
+	err := oh.SubmitPrice(stateDB, testOracleSource2, testOracleAsset, big.NewInt(100), block)
+	if err != ErrUnknownOracleSource {
+		t.Fatalf("expected ErrUnknownOracleSource, got %v", err)
+	}
+
+	if err := oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), block); err != nil {
+		t.Fatalf("SubmitPrice failed: %v", err)
+	}
+	err = oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), block-1)
+	if err != ErrStaleReport {
+		t.Fatalf("expected ErrStaleReport, got %v", err)
+	}
+
+	// A report from a future block would pin the history; it is rejected
+	err = oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), block+1)
+	if err != ErrFutureReport {
+		t.Fatalf("expected ErrFutureReport, got %v", err)
+	}
+	err = oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), ^uint64(0))
+	if err != ErrFutureReport {
+		t.Fatalf("expected ErrFutureReport, got %v", err)
+	}
+
+	price, err := oh.GetPrice(testOracleAsset)
+	if err != nil {
+		t.Fatalf("GetPrice failed: %v", err)
+	}
+	if price.Int64() != 100 {
+		t.Fatalf("expected price 100, got %s", price)
+	}
+}
+
+func TestOracleHub_MedianAggregation(t *testing.T) {
+	block := uint64(1000)
+	oh, stateDB := newTestOracleHub(block)
+	oh.AddSource(testOracleSource2)
+	oh.AddSource(testOracleSource3)
+
+	oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), block)
+	oh.SubmitPrice(stateDB, testOracleSource2, testOracleAsset, big.NewInt(5000), block)
+	oh.SubmitPrice(stateDB, testOracleSource3, testOracleAsset, big.NewInt(102), block)
+
+	// An outlier source does not move the median
+	price, _ := oh.GetPrice(testOracleAsset)
+	if price.Int64() != 102 {
+		t.Fatalf("expected median 102, got %s", price)
+	}
+}
+
+func TestOracleHub_TWAPDampensSpike(t *testing.T) {
+	block := uint64(1000)
+	oh, stateDB := newTestOracleHub(block)
+
+	// Stable at 100 for 600 blocks
+	for b := block; b < 1600; b += 60 {
+		stateDB.SetBlockNumber(b)
+		oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), b)
+	}
+
+	// Short spike to 200
+	block = 1600
+	stateDB.SetBlockNumber(block)
+	oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(200), block)
+	block = 1612
+	stateDB.SetBlockNumber(block)
+
+	spot, _ := oh.GetPrice(testOracleAsset)
+	twap, err := oh.GetTWAP(stateDB, testOracleAsset, 600)
+	if err != nil {
+		t.Fatalf("GetTWAP failed: %v", err)
+	}
+	if spot.Int64() != 200 {
+		t.Fatalf("expected spot 200, got %s", spot)
+	}
+
+	// 588 blocks at 100 + 12 blocks at 200 over 600 blocks = 102
+	if twap.Int64() != 102 {
+		t.Fatalf("expected TWAP 102, got %s", twap)
+	}
+
+	// Price reverts; the spike's weight stays bounded by its duration
+	oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), block)
+	stateDB.SetBlockNumber(1900)
+	twap, _ = oh.GetTWAP(stateDB, testOracleAsset, 600)
+	if twap.Int64() != 102 {
+		t.Fatalf("expected TWAP 102 after revert, got %s", twap)
+	}
+
+	// Zero window is spot
+	twap, _ = oh.GetTWAP(stateDB, testOracleAsset, 0)
+	if twap.Int64() != 100 {
+		t.Fatalf("expected zero-window TWAP 100, got %s", twap)
+	}
+}
+
+func TestOracleHub_TWAPPartialHistory(t *testing.T) {
+	block := uint64(1000)
+	oh, stateDB := newTestOracleHub(block)
+
+	if _, err := oh.GetTWAP(stateDB, testOracleAsset, 600); err != ErrPriceUnavailable {
+		t.Fatalf("expected ErrPriceUnavailable, got %v", err)
+	}
+
+	oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(100), block)
+	stateDB.SetBlockNumber(1050)
+	oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(300), 1050)
+	stateDB.SetBlockNumber(1100)
+
+	// History covers only 100 blocks of the window: (50*100 + 50*300) / 100
+	twap, _ := oh.GetTWAP(stateDB, testOracleAsset, 3600)
+	if twap.Int64() != 200 {
+		t.Fatalf("expected TWAP 200, got %s", twap)
+	}
+}
+
+func TestOracleHub_HistoryWraps(t *testing.T) {
+	oh, stateDB := newTestOracleHub(0)
+
+	for i := 0; i < OracleHistorySize+10; i++ {
+		block := uint64(i * 10)
+		stateDB.SetBlockNumber(block)
+		oh.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, big.NewInt(int64(i+1)), block)
+	}
+
+	ring := oh.history[testOracleAsset]
+	if ring.count != OracleHistorySize {
+		t.Fatalf("expected %d observations, got %d", OracleHistorySize, ring.count)
+	}
+	if oldest := ring.at(0); oldest.Price.Int64() != 11 {
+		t.Fatalf("expected oldest retained price 11, got %s", oldest.Price)
+	}
+}
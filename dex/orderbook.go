@@ -0,0 +1,580 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/luxfi/geth/common"
+)
+
+// OrderBook is the central limit order book matching engine
+// Address: LP-9020 LXBook
+type OrderBook struct {
+	Markets map[[32]byte]*CLOBMarket // Market ID -> Market
+	Orders  map[uint64]*Order        // Order ID -> resting order
+
+	// Resting orders per market, best price first then time priority
+	bids map[[32]byte][]*Order
+	asks map[[32]byte][]*Order
+
+	// Fees and rebates accrued per market and account
+	accruals map[[32]byte]map[common.Address]*FeeAccrual
+
+	nextOrderID uint64
+	sequence    uint64
+
+	mu sync.RWMutex
+}
+
+// NewOrderBook creates a new order book engine
+func NewOrderBook() *OrderBook {
+	return &OrderBook{
+		Markets:     make(map[[32]byte]*CLOBMarket),
+		Orders:      make(map[uint64]*Order),
+		bids:        make(map[[32]byte][]*Order),
+		asks:        make(map[[32]byte][]*Order),
+		accruals:    make(map[[32]byte]map[common.Address]*FeeAccrual),
+		nextOrderID: 1,
+	}
+}
+
+// CreateMarket registers a market with its tick size, lot size and minimum
+// order size. tickSize and lotSize must be positive; minOrderSize must be a
+// positive multiple of lotSize.
+func (ob *OrderBook) CreateMarket(
+	base, quote Currency,
+	tickSize, lotSize, minOrderSize *big.Int,
+) ([32]byte, error) {
+	if tickSize == nil || tickSize.Sign() <= 0 ||
+		lotSize == nil || lotSize.Sign() <= 0 ||
+		minOrderSize == nil || minOrderSize.Sign() <= 0 ||
+		new(big.Int).Mod(minOrderSize, lotSize).Sign() != 0 {
+		return [32]byte{}, ErrInvalidParameter
+	}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	marketID := generateMarketID(base, quote)
+	if _, exists := ob.Markets[marketID]; exists {
+		return [32]byte{}, ErrMarketExists
+	}
+
+	ob.Markets[marketID] = &CLOBMarket{
+		ID:           marketID,
+		BaseAsset:    base,
+		QuoteAsset:   quote,
+		TickSize:     new(big.Int).Set(tickSize),
+		LotSize:      new(big.Int).Set(lotSize),
+		MinOrderSize: new(big.Int).Set(minOrderSize),
+	}
+
+	return marketID, nil
+}
+
+// GetMarket returns a market by ID
+func (ob *OrderBook) GetMarket(marketID [32]byte) (*CLOBMarket, error) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	market, exists := ob.Markets[marketID]
+	if !exists {
+		return nil, ErrMarketNotFound
+	}
+	return market, nil
+}
+
+// SetSelfTradePrevention sets the market's self-trade prevention mode
+func (ob *OrderBook) SetSelfTradePrevention(marketID [32]byte, mode SelfTradePrevention) error {
+	if mode > STPReject {
+		return ErrInvalidParameter
+	}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	market, exists := ob.Markets[marketID]
+	if !exists {
+		return ErrMarketNotFound
+	}
+	market.SelfTradePrevention = mode
+	return nil
+}
+
+// SetFees sets the market's maker rebate and taker fee in basis points of
+// fill notional (price * size), applied to fills from then on. The rebate
+// may not exceed the fee, so the book never pays out more than it collects;
+// the difference is the protocol's spread.
+func (ob *OrderBook) SetFees(marketID [32]byte, makerRebateBps, takerFeeBps uint64) error {
+	if takerFeeBps > FeePrecision || makerRebateBps > takerFeeBps {
+		return ErrInvalidFee
+	}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	market, exists := ob.Markets[marketID]
+	if !exists {
+		return ErrMarketNotFound
+	}
+	market.MakerRebateBps = makerRebateBps
+	market.TakerFeeBps = takerFeeBps
+	return nil
+}
+
+// GetFeeAccrual returns the fees account has paid and the rebates it has
+// earned on a market
+func (ob *OrderBook) GetFeeAccrual(marketID [32]byte, account common.Address) (*FeeAccrual, error) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	if _, exists := ob.Markets[marketID]; !exists {
+		return nil, ErrMarketNotFound
+	}
+	accrual := &FeeAccrual{FeesPaid: big.NewInt(0), RebatesEarned: big.NewInt(0)}
+	if a, ok := ob.accruals[marketID][account]; ok {
+		accrual.FeesPaid.Set(a.FeesPaid)
+		accrual.RebatesEarned.Set(a.RebatesEarned)
+	}
+	return accrual, nil
+}
+
+// PlaceOrder submits a limit order. It is matched against the opposite side
+// at the makers' prices and any remainder rests on the book. A non-zero
+// expiryBlock makes the order good-til-time: it rests through that block and
+// is skipped and removed afterwards.
+func (ob *OrderBook) PlaceOrder(
+	stateDB StateDB,
+	owner common.Address,
+	marketID [32]byte,
+	isBuy bool,
+	price, size *big.Int,
+	expiryBlock uint64,
+) (*Order, []Fill, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	market, exists := ob.Markets[marketID]
+	if !exists {
+		return nil, nil, ErrMarketNotFound
+	}
+
+	if err := market.validateOrder(price, size); err != nil {
+		return nil, nil, err
+	}
+
+	block := stateDB.GetBlockNumber()
+	if expiryBlock != 0 && expiryBlock < block {
+		return nil, nil, ErrOrderExpired
+	}
+
+	ob.sequence++
+	order := &Order{
+		ID:       ob.nextOrderID,
+		Market:   marketID,
+		Owner:    owner,
+		IsBuy:    isBuy,
+		Price:    new(big.Int).Set(price),
+		Size:     new(big.Int).Set(size),
+		Filled:   big.NewInt(0),
+		Sequence: ob.sequence,
+		Expiry:   expiryBlock,
+	}
+	ob.nextOrderID++
+
+	fills, err := ob.match(market, order, block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if order.Remaining().Sign() > 0 && !order.Canceled {
+		ob.insert(order)
+	}
+
+	return order, fills, nil
+}
+
+// MarketOrder submits an immediate-or-cancel taker order for size. It sweeps
+// the opposite side from the best price up to a worst acceptable price of
+// maxSlippageBps away from the best bid/ask, rounded to the tick inside the
+// cap. Whatever cannot fill within the cap is canceled, never rested; an
+// empty book fills nothing.
+func (ob *OrderBook) MarketOrder(
+	stateDB StateDB,
+	owner common.Address,
+	marketID [32]byte,
+	isBuy bool,
+	size *big.Int,
+	maxSlippageBps uint64,
+) (*Order, []Fill, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	market, exists := ob.Markets[marketID]
+	if !exists {
+		return nil, nil, ErrMarketNotFound
+	}
+	if err := market.validateSize(size); err != nil {
+		return nil, nil, err
+	}
+	if maxSlippageBps > SlippagePrecision {
+		return nil, nil, ErrInvalidParameter
+	}
+
+	ob.sequence++
+	order := &Order{
+		ID:       ob.nextOrderID,
+		Market:   marketID,
+		Owner:    owner,
+		IsBuy:    isBuy,
+		Price:    big.NewInt(0),
+		Size:     new(big.Int).Set(size),
+		Filled:   big.NewInt(0),
+		Sequence: ob.sequence,
+	}
+	ob.nextOrderID++
+
+	book := ob.asks
+	if !isBuy {
+		book = ob.bids
+	}
+	block := stateDB.GetBlockNumber()
+	ob.pruneExpired(book, marketID, block)
+	resting := book[marketID]
+	if len(resting) == 0 {
+		return order, nil, nil
+	}
+
+	order.Price = market.slippageLimit(resting[0].Price, isBuy, maxSlippageBps)
+	fills, err := ob.match(market, order, block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, fills, nil
+}
+
+// PruneExpired removes every order of the market whose expiry block has
+// passed and returns how many were removed
+func (ob *OrderBook) PruneExpired(stateDB StateDB, marketID [32]byte) (int, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	if _, exists := ob.Markets[marketID]; !exists {
+		return 0, ErrMarketNotFound
+	}
+
+	block := stateDB.GetBlockNumber()
+	return ob.pruneExpired(ob.bids, marketID, block) + ob.pruneExpired(ob.asks, marketID, block), nil
+}
+
+// pruneExpired removes the expired orders on one side of a market
+func (ob *OrderBook) pruneExpired(book map[[32]byte][]*Order, marketID [32]byte, block uint64) int {
+	orders := book[marketID]
+	live := orders[:0]
+	for _, order := range orders {
+		if order.Expired(block) {
+			delete(ob.Orders, order.ID)
+			continue
+		}
+		live = append(live, order)
+	}
+	pruned := len(orders) - len(live)
+	clear(orders[len(live):])
+	book[marketID] = live
+	return pruned
+}
+
+// CancelOrder removes a resting order owned by owner
+func (ob *OrderBook) CancelOrder(owner common.Address, orderID uint64) error {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	order, exists := ob.Orders[orderID]
+	if !exists {
+		return ErrOrderNotFound
+	}
+	if order.Owner != owner {
+		return ErrUnauthorized
+	}
+
+	ob.remove(order)
+	return nil
+}
+
+// GetOrder returns a resting order by ID
+func (ob *OrderBook) GetOrder(orderID uint64) (*Order, error) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	order, exists := ob.Orders[orderID]
+	if !exists {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+// GetDepth returns up to levels bid and ask price levels of a market, best
+// price first, aggregating the remaining size of resting orders per price.
+// A non-positive levels returns the whole book. Expired orders that have not
+// been pruned yet are included.
+func (ob *OrderBook) GetDepth(marketID [32]byte, levels int) ([]PriceLevel, []PriceLevel, error) {
+	ob.mu.RLock()
+	defer ob.mu.RUnlock()
+
+	if _, exists := ob.Markets[marketID]; !exists {
+		return nil, nil, ErrMarketNotFound
+	}
+	return aggregateLevels(ob.bids[marketID], levels), aggregateLevels(ob.asks[marketID], levels), nil
+}
+
+// aggregateLevels folds a sorted side of the book into price levels
+func aggregateLevels(orders []*Order, levels int) []PriceLevel {
+	var depth []PriceLevel
+	for _, order := range orders {
+		n := len(depth)
+		if n > 0 && depth[n-1].Price.Cmp(order.Price) == 0 {
+			depth[n-1].Size.Add(depth[n-1].Size, order.Remaining())
+			depth[n-1].Orders++
+			continue
+		}
+		if levels > 0 && n == levels {
+			break
+		}
+		depth = append(depth, PriceLevel{
+			Price:  new(big.Int).Set(order.Price),
+			Size:   order.Remaining(),
+			Orders: 1,
+		})
+	}
+	return depth
+}
+
+// validateOrder checks price and size against the market parameters
+func (m *CLOBMarket) validateOrder(price, size *big.Int) error {
+	if price == nil || price.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+	if new(big.Int).Mod(price, m.TickSize).Sign() != 0 {
+		return ErrPriceNotAligned
+	}
+	return m.validateSize(size)
+}
+
+// validateSize checks size against the market's minimum and lot size
+func (m *CLOBMarket) validateSize(size *big.Int) error {
+	if size == nil || size.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+	if size.Cmp(m.MinOrderSize) < 0 {
+		return ErrOrderTooSmall
+	}
+	if new(big.Int).Mod(size, m.LotSize).Sign() != 0 {
+		return ErrInvalidOrderSize
+	}
+	return nil
+}
+
+// slippageLimit returns the worst price a market order accepts: best moved
+// against the taker by slippageBps, rounded to the tick inside the cap
+func (m *CLOBMarket) slippageLimit(best *big.Int, isBuy bool, slippageBps uint64) *big.Int {
+	bps := new(big.Int).SetUint64(SlippagePrecision + slippageBps)
+	if !isBuy {
+		bps.SetUint64(SlippagePrecision - slippageBps)
+	}
+	limit := new(big.Int).Mul(best, bps)
+	limit.Div(limit, big.NewInt(SlippagePrecision))
+
+	// Round buys down and sells up to the tick
+	rem := new(big.Int).Mod(limit, m.TickSize)
+	if rem.Sign() != 0 {
+		limit.Sub(limit, rem)
+		if !isBuy {
+			limit.Add(limit, m.TickSize)
+		}
+	}
+	return limit
+}
+
+// match fills the taker against crossing resting orders on the opposite side,
+// applying the market's self-trade prevention mode. STPReject fails before
+// any fill; STPCancelIncoming marks the taker canceled. Makers expired at
+// block are removed without matching. Each fill charges the taker fee and
+// credits the maker rebate.
+func (ob *OrderBook) match(market *CLOBMarket, taker *Order, block uint64) ([]Fill, error) {
+	var fills []Fill
+
+	book := ob.asks
+	if !taker.IsBuy {
+		book = ob.bids
+	}
+
+	resting := book[taker.Market]
+	if market.SelfTradePrevention == STPReject && wouldSelfTrade(taker, resting, block) {
+		return nil, ErrSelfTrade
+	}
+
+	consumed := 0
+	for _, maker := range resting {
+		if taker.Remaining().Sign() == 0 {
+			break
+		}
+		if maker.Expired(block) {
+			delete(ob.Orders, maker.ID)
+			consumed++
+			continue
+		}
+		if !crosses(taker, maker) {
+			break
+		}
+
+		if maker.Owner == taker.Owner {
+			if market.SelfTradePrevention == STPCancelResting {
+				delete(ob.Orders, maker.ID)
+				consumed++
+				continue
+			}
+			if market.SelfTradePrevention == STPCancelIncoming {
+				taker.Canceled = true
+				break
+			}
+		}
+
+		fillSize := minBig(taker.Remaining(), maker.Remaining())
+		taker.Filled.Add(taker.Filled, fillSize)
+		maker.Filled.Add(maker.Filled, fillSize)
+
+		notional := new(big.Int).Mul(maker.Price, fillSize)
+		rebate := feeBps(notional, market.MakerRebateBps)
+		fee := feeBps(notional, market.TakerFeeBps)
+		makerAccrual := ob.accrual(market.ID, maker.Owner)
+		makerAccrual.RebatesEarned.Add(makerAccrual.RebatesEarned, rebate)
+		takerAccrual := ob.accrual(market.ID, taker.Owner)
+		takerAccrual.FeesPaid.Add(takerAccrual.FeesPaid, fee)
+
+		fills = append(fills, Fill{
+			MakerOrderID: maker.ID,
+			TakerOrderID: taker.ID,
+			Price:        new(big.Int).Set(maker.Price),
+			Size:         fillSize,
+			MakerRebate:  rebate,
+			TakerFee:     fee,
+		})
+
+		if maker.Remaining().Sign() > 0 {
+			break
+		}
+		delete(ob.Orders, maker.ID)
+		consumed++
+	}
+	book[taker.Market] = resting[consumed:]
+
+	return fills, nil
+}
+
+// accrual returns the fee accrual of account on a market, creating it
+func (ob *OrderBook) accrual(marketID [32]byte, account common.Address) *FeeAccrual {
+	accounts, ok := ob.accruals[marketID]
+	if !ok {
+		accounts = make(map[common.Address]*FeeAccrual)
+		ob.accruals[marketID] = accounts
+	}
+	a, ok := accounts[account]
+	if !ok {
+		a = &FeeAccrual{FeesPaid: big.NewInt(0), RebatesEarned: big.NewInt(0)}
+		accounts[account] = a
+	}
+	return a
+}
+
+// feeBps returns bps basis points of notional, rounded down
+func feeBps(notional *big.Int, bps uint64) *big.Int {
+	fee := new(big.Int).Mul(notional, new(big.Int).SetUint64(bps))
+	return fee.Div(fee, big.NewInt(FeePrecision))
+}
+
+// wouldSelfTrade reports whether taker would reach a resting order of its
+// own owner before being fully filled
+func wouldSelfTrade(taker *Order, resting []*Order, block uint64) bool {
+	remaining := taker.Remaining()
+	for _, maker := range resting {
+		if maker.Expired(block) {
+			continue
+		}
+		if remaining.Sign() <= 0 || !crosses(taker, maker) {
+			return false
+		}
+		if maker.Owner == taker.Owner {
+			return true
+		}
+		remaining.Sub(remaining, maker.Remaining())
+	}
+	return false
+}
+
+// insert rests an order on its side of the book
+func (ob *OrderBook) insert(order *Order) {
+	book := ob.bids
+	if !order.IsBuy {
+		book = ob.asks
+	}
+
+	orders := book[order.Market]
+	i := sort.Search(len(orders), func(i int) bool {
+		return hasPriority(order, orders[i])
+	})
+	orders = append(orders, nil)
+	copy(orders[i+1:], orders[i:])
+	orders[i] = order
+	book[order.Market] = orders
+
+	ob.Orders[order.ID] = order
+}
+
+// remove takes a resting order off the book
+func (ob *OrderBook) remove(order *Order) {
+	book := ob.bids
+	if !order.IsBuy {
+		book = ob.asks
+	}
+
+	orders := book[order.Market]
+	for i, o := range orders {
+		if o.ID == order.ID {
+			book[order.Market] = append(orders[:i], orders[i+1:]...)
+			break
+		}
+	}
+	delete(ob.Orders, order.ID)
+}
+
+// crosses reports whether taker's limit price reaches maker's price
+func crosses(taker, maker *Order) bool {
+	if taker.IsBuy {
+		return taker.Price.Cmp(maker.Price) >= 0
+	}
+	return taker.Price.Cmp(maker.Price) <= 0
+}
+
+// hasPriority reports whether a should sit ahead of b on the same side
+func hasPriority(a, b *Order) bool {
+	cmp := a.Price.Cmp(b.Price)
+	if cmp == 0 {
+		return a.Sequence < b.Sequence
+	}
+	if a.IsBuy {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// minBig returns a copy of the smaller of a and b
+func minBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
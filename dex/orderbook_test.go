@@ -0,0 +1,521 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Test addresses for the order book
+var (
+	testBookBase   = Currency{Address: common.HexToAddress("0x7777777777777777777777777777777777777777")}
+	testBookQuote  = Currency{Address: common.HexToAddress("0x8888888888888888888888888888888888888888")}
+	testBookTrader = common.HexToAddress("0x9999999999999999999999999999999999999999")
+	testBookMaker  = common.HexToAddress("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+)
+
+// newTestMarket creates a book with tick 10, lot 5 and minimum size 20
+func newTestMarket(t *testing.T) (*OrderBook, [32]byte, *MockStateDB) {
+	t.Helper()
+
+	ob := NewOrderBook()
+	marketID, err := ob.CreateMarket(testBookBase, testBookQuote, big.NewInt(10), big.NewInt(5), big.NewInt(20))
+	if err != nil {
+		t.Fatalf("CreateMarket failed: %v", err)
+	}
+	return ob, marketID, NewMockStateDB()
+}
+
+func TestOrderBook_CreateMarket(t *testing.T) {
+	ob, marketID, _ := newTestMarket(t)
+
+	market, err := ob.GetMarket(marketID)
+	if err != nil {
+		t.Fatalf("GetMarket failed: %v", err)
+	}
+	if market.TickSize.Int64() != 10 || market.LotSize.Int64() != 5 || market.MinOrderSize.Int64() != 20 {
+		t.Fatalf("unexpected market params: tick %s lot %s min %s", market.TickSize, market.LotSize, market.MinOrderSize)
+	}
+
+	_, err = ob.CreateMarket(testBookBase, testBookQuote, big.NewInt(10), big.NewInt(5), big.NewInt(20))
+	if err != ErrMarketExists {
+		t.Fatalf("expected ErrMarketExists, got %v", err)
+	}
+
+	// Minimum size must be a multiple of the lot size
+	_, err = ob.CreateMarket(testBookQuote, testBookBase, big.NewInt(10), big.NewInt(5), big.NewInt(7))
+	if err != ErrInvalidParameter {
+		t.Fatalf("expected ErrInvalidParameter, got %v", err)
+	}
+
+	_, err = ob.CreateMarket(testBookQuote, testBookBase, big.NewInt(0), big.NewInt(5), big.NewInt(5))
+	if err != ErrInvalidParameter {
+		t.Fatalf("expected ErrInvalidParameter for zero tick, got %v", err)
+	}
+}
+
+func TestOrderBook_PlaceOrder_TickAlignment(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+
+	_, _, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1005), big.NewInt(20), 0)
+	if err != ErrPriceNotAligned {
+		t.Fatalf("expected ErrPriceNotAligned, got %v", err)
+	}
+
+	order, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1000), big.NewInt(20), 0)
+	if err != nil {
+		t.Fatalf("aligned order rejected: %v", err)
+	}
+	if len(fills) != 0 {
+		t.Fatalf("expected no fills on empty book, got %d", len(fills))
+	}
+	if _, err := ob.GetOrder(order.ID); err != nil {
+		t.Fatalf("order should rest on the book: %v", err)
+	}
+}
+
+func TestOrderBook_PlaceOrder_SizeValidation(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+
+	_, _, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, false, big.NewInt(1000), big.NewInt(15), 0)
+	if err != ErrOrderTooSmall {
+		t.Fatalf("expected ErrOrderTooSmall, got %v", err)
+	}
+
+	_, _, err = ob.PlaceOrder(stateDB, testBookTrader, marketID, false, big.NewInt(1000), big.NewInt(22), 0)
+	if err != ErrInvalidOrderSize {
+		t.Fatalf("expected ErrInvalidOrderSize, got %v", err)
+	}
+
+	_, _, err = ob.PlaceOrder(stateDB, testBookTrader, marketID, false, big.NewInt(1000), big.NewInt(25), 0)
+	if err != nil {
+		t.Fatalf("lot-aligned order rejected: %v", err)
+	}
+}
+
+func TestOrderBook_PlaceOrder_MarketNotFound(t *testing.T) {
+	ob := NewOrderBook()
+	stateDB := NewMockStateDB()
+
+	_, _, err := ob.PlaceOrder(stateDB, testBookTrader, [32]byte{1}, true, big.NewInt(1000), big.NewInt(20), 0)
+	if err != ErrMarketNotFound {
+		t.Fatalf("expected ErrMarketNotFound, got %v", err)
+	}
+
+	if _, err := ob.GetMarket([32]byte{1}); err != ErrMarketNotFound {
+		t.Fatalf("expected ErrMarketNotFound, got %v", err)
+	}
+}
+
+func TestOrderBook_Matching(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+
+	// Two asks at different prices
+	ask1, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1010), big.NewInt(20), 0)
+	ask2, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1000), big.NewInt(20), 0)
+
+	// Buy crosses both, best price first, remainder rests
+	bid, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1010), big.NewInt(50), 0)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if len(fills) != 2 {
+		t.Fatalf("expected 2 fills, got %d", len(fills))
+	}
+	if fills[0].MakerOrderID != ask2.ID || fills[0].Price.Int64() != 1000 {
+		t.Fatalf("first fill should hit best ask at 1000, got order %d at %s", fills[0].MakerOrderID, fills[0].Price)
+	}
+	if fills[1].MakerOrderID != ask1.ID || fills[1].Price.Int64() != 1010 {
+		t.Fatalf("second fill should hit ask at 1010, got order %d at %s", fills[1].MakerOrderID, fills[1].Price)
+	}
+	if bid.Remaining().Int64() != 10 {
+		t.Fatalf("expected 10 remaining, got %s", bid.Remaining())
+	}
+
+	if _, err := ob.GetOrder(ask1.ID); err != ErrOrderNotFound {
+		t.Fatalf("filled ask should be removed, got %v", err)
+	}
+	if _, err := ob.GetOrder(bid.ID); err != nil {
+		t.Fatalf("partially filled bid should rest: %v", err)
+	}
+
+	// Cancel by non-owner fails, owner succeeds
+	if err := ob.CancelOrder(testBookMaker, bid.ID); err != ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+	if err := ob.CancelOrder(testBookTrader, bid.ID); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	if _, err := ob.GetOrder(bid.ID); err != ErrOrderNotFound {
+		t.Fatalf("cancelled order should be gone, got %v", err)
+	}
+}
+
+func TestOrderBook_MarketOrder_SweepsLevels(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+
+	ask1, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1000), big.NewInt(20), 0)
+	ask2, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1010), big.NewInt(30), 0)
+
+	// 1% cap from 1000 allows up to 1010
+	order, fills, err := ob.MarketOrder(stateDB, testBookTrader, marketID, true, big.NewInt(40), 100)
+	if err != nil {
+		t.Fatalf("MarketOrder failed: %v", err)
+	}
+	if len(fills) != 2 {
+		t.Fatalf("expected 2 fills, got %d", len(fills))
+	}
+	if fills[0].MakerOrderID != ask1.ID || fills[0].Price.Int64() != 1000 || fills[0].Size.Int64() != 20 {
+		t.Fatalf("unexpected first fill: order %d %s@%s", fills[0].MakerOrderID, fills[0].Size, fills[0].Price)
+	}
+	if fills[1].MakerOrderID != ask2.ID || fills[1].Price.Int64() != 1010 || fills[1].Size.Int64() != 20 {
+		t.Fatalf("unexpected second fill: order %d %s@%s", fills[1].MakerOrderID, fills[1].Size, fills[1].Price)
+	}
+	if order.Remaining().Sign() != 0 {
+		t.Fatalf("expected full fill, %s remaining", order.Remaining())
+	}
+
+	// The partially filled maker keeps resting
+	maker, err := ob.GetOrder(ask2.ID)
+	if err != nil || maker.Remaining().Int64() != 10 {
+		t.Fatalf("expected ask2 resting with 10, got %v", err)
+	}
+}
+
+func TestOrderBook_MarketOrder_SlippageCap(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+
+	ob.PlaceOrder(stateDB, testBookMaker, marketID, true, big.NewInt(1000), big.NewInt(20), 0)
+	far, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, true, big.NewInt(980), big.NewInt(20), 0)
+
+	// 1.5% below 1000 is 985: the 980 bid is beyond the cap
+	order, fills, err := ob.MarketOrder(stateDB, testBookTrader, marketID, false, big.NewInt(40), 150)
+	if err != nil {
+		t.Fatalf("MarketOrder failed: %v", err)
+	}
+	if len(fills) != 1 || fills[0].Price.Int64() != 1000 {
+		t.Fatalf("expected a single fill at 1000, got %d fills", len(fills))
+	}
+	if order.Filled.Int64() != 20 || order.Remaining().Int64() != 20 {
+		t.Fatalf("expected 20 filled and 20 canceled, got filled %s", order.Filled)
+	}
+	if order.Price.Int64() != 990 {
+		t.Fatalf("expected worst price rounded inside the cap to 990, got %s", order.Price)
+	}
+
+	// The remainder is canceled, not rested
+	if _, err := ob.GetOrder(order.ID); err != ErrOrderNotFound {
+		t.Fatalf("market order remainder should not rest, got %v", err)
+	}
+	if _, err := ob.GetOrder(far.ID); err != nil {
+		t.Fatalf("bid beyond the cap should be untouched: %v", err)
+	}
+}
+
+func TestOrderBook_MarketOrder_EmptyBook(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+
+	order, fills, err := ob.MarketOrder(stateDB, testBookTrader, marketID, true, big.NewInt(20), 500)
+	if err != nil {
+		t.Fatalf("MarketOrder failed: %v", err)
+	}
+	if len(fills) != 0 || order.Filled.Sign() != 0 {
+		t.Fatalf("expected zero fill on empty book, got %d fills", len(fills))
+	}
+	if _, err := ob.GetOrder(order.ID); err != ErrOrderNotFound {
+		t.Fatalf("market order should not rest, got %v", err)
+	}
+
+	// Size and slippage cap are validated
+	if _, _, err := ob.MarketOrder(stateDB, testBookTrader, marketID, true, big.NewInt(22), 100); err != ErrInvalidOrderSize {
+		t.Fatalf("expected ErrInvalidOrderSize, got %v", err)
+	}
+	if _, _, err := ob.MarketOrder(stateDB, testBookTrader, marketID, true, big.NewInt(20), SlippagePrecision+1); err != ErrInvalidParameter {
+		t.Fatalf("expected ErrInvalidParameter, got %v", err)
+	}
+}
+
+// newSelfTradeBook rests the trader's ask at 1000 behind a maker ask at the
+// same price, so an incoming trader bid meets the maker first
+func newSelfTradeBook(t *testing.T, mode SelfTradePrevention) (*OrderBook, [32]byte, *Order, *Order) {
+	t.Helper()
+
+	ob, marketID, stateDB := newTestMarket(t)
+	if err := ob.SetSelfTradePrevention(marketID, mode); err != nil {
+		t.Fatalf("SetSelfTradePrevention failed: %v", err)
+	}
+	makerAsk, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1000), big.NewInt(20), 0)
+	ownAsk, _, _ := ob.PlaceOrder(stateDB, testBookTrader, marketID, false, big.NewInt(1000), big.NewInt(20), 0)
+	return ob, marketID, makerAsk, ownAsk
+}
+
+func TestOrderBook_SelfTrade_None(t *testing.T) {
+	ob, marketID, _, ownAsk := newSelfTradeBook(t, STPNone)
+	stateDB := NewMockStateDB()
+
+	_, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1000), big.NewInt(40), 0)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if len(fills) != 2 || fills[1].MakerOrderID != ownAsk.ID {
+		t.Fatalf("expected the own ask to be matched without STP, got %d fills", len(fills))
+	}
+}
+
+func TestOrderBook_SelfTrade_CancelResting(t *testing.T) {
+	ob, marketID, makerAsk, ownAsk := newSelfTradeBook(t, STPCancelResting)
+	stateDB := NewMockStateDB()
+	otherAsk, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1010), big.NewInt(20), 0)
+
+	bid, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1010), big.NewInt(40), 0)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	// The own ask is skipped and canceled; matching continues behind it
+	if len(fills) != 2 || fills[0].MakerOrderID != makerAsk.ID || fills[1].MakerOrderID != otherAsk.ID {
+		t.Fatalf("expected fills against the other maker's asks, got %d fills", len(fills))
+	}
+	if _, err := ob.GetOrder(ownAsk.ID); err != ErrOrderNotFound {
+		t.Fatalf("own resting ask should be canceled, got %v", err)
+	}
+	if bid.Remaining().Sign() != 0 || bid.Canceled {
+		t.Fatalf("incoming order should fill fully, remaining %s", bid.Remaining())
+	}
+}
+
+func TestOrderBook_SelfTrade_CancelIncoming(t *testing.T) {
+	ob, marketID, makerAsk, ownAsk := newSelfTradeBook(t, STPCancelIncoming)
+	stateDB := NewMockStateDB()
+
+	bid, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1000), big.NewInt(40), 0)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	// Fills before the own order stand; the rest of the incoming order is canceled
+	if len(fills) != 1 || fills[0].MakerOrderID != makerAsk.ID {
+		t.Fatalf("expected one fill against the other maker, got %d fills", len(fills))
+	}
+	if !bid.Canceled || bid.Remaining().Int64() != 20 {
+		t.Fatalf("expected incoming remainder of 20 canceled, got canceled=%v remaining %s", bid.Canceled, bid.Remaining())
+	}
+	if _, err := ob.GetOrder(bid.ID); err != ErrOrderNotFound {
+		t.Fatalf("canceled incoming order should not rest, got %v", err)
+	}
+	if resting, err := ob.GetOrder(ownAsk.ID); err != nil || resting.Filled.Sign() != 0 {
+		t.Fatalf("own resting ask should be untouched: %v", err)
+	}
+}
+
+func TestOrderBook_SelfTrade_Reject(t *testing.T) {
+	ob, marketID, makerAsk, ownAsk := newSelfTradeBook(t, STPReject)
+	stateDB := NewMockStateDB()
+
+	_, _, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1000), big.NewInt(40), 0)
+	if err != ErrSelfTrade {
+		t.Fatalf("expected ErrSelfTrade, got %v", err)
+	}
+	// Nothing matched
+	for _, id := range []uint64{makerAsk.ID, ownAsk.ID} {
+		if resting, err := ob.GetOrder(id); err != nil || resting.Filled.Sign() != 0 {
+			t.Fatalf("order %d should be untouched: %v", id, err)
+		}
+	}
+	if _, _, err := ob.MarketOrder(stateDB, testBookTrader, marketID, true, big.NewInt(40), 100); err != ErrSelfTrade {
+		t.Fatalf("expected ErrSelfTrade for market order, got %v", err)
+	}
+
+	// An order filled before reaching the own ask is accepted
+	_, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1000), big.NewInt(20), 0)
+	if err != nil || len(fills) != 1 || fills[0].MakerOrderID != makerAsk.ID {
+		t.Fatalf("expected a fill against the other maker, got %v", err)
+	}
+
+	if err := ob.SetSelfTradePrevention(marketID, STPReject+1); err != ErrInvalidParameter {
+		t.Fatalf("expected ErrInvalidParameter, got %v", err)
+	}
+}
+
+func TestOrderBook_GoodTilTime(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+	stateDB.blockNumber = 100
+
+	// Expiry in the past is rejected
+	if _, _, err := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1000), big.NewInt(20), 99); err != ErrOrderExpired {
+		t.Fatalf("expected ErrOrderExpired, got %v", err)
+	}
+
+	expiring, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1000), big.NewInt(20), 110)
+	lasting, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1010), big.NewInt(20), 200)
+	gtc, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, true, big.NewInt(900), big.NewInt(20), 0)
+
+	// Still live on its expiry block
+	stateDB.blockNumber = 110
+	_, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1000), big.NewInt(20), 0)
+	if err != nil || len(fills) != 1 || fills[0].MakerOrderID != expiring.ID {
+		t.Fatalf("unexpired order should match, got %d fills (%v)", len(fills), err)
+	}
+
+	// Past expiry an order is skipped and removed
+	expired, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1000), big.NewInt(20), 120)
+	stateDB.blockNumber = 121
+	bid, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1010), big.NewInt(20), 0)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if len(fills) != 1 || fills[0].MakerOrderID != lasting.ID {
+		t.Fatalf("expected the expired ask to be skipped, got %d fills", len(fills))
+	}
+	if bid.Remaining().Sign() != 0 {
+		t.Fatalf("expected full fill, %s remaining", bid.Remaining())
+	}
+	if _, err := ob.GetOrder(expired.ID); err != ErrOrderNotFound {
+		t.Fatalf("expired order should be removed, got %v", err)
+	}
+
+	// PruneExpired sweeps orders that no taker reached
+	stale, _, _ := ob.PlaceOrder(stateDB, testBookMaker, marketID, true, big.NewInt(950), big.NewInt(20), 130)
+	stateDB.blockNumber = 131
+	pruned, err := ob.PruneExpired(stateDB, marketID)
+	if err != nil {
+		t.Fatalf("PruneExpired failed: %v", err)
+	}
+	if pruned != 1 {
+		t.Fatalf("expected 1 pruned order, got %d", pruned)
+	}
+	if _, err := ob.GetOrder(stale.ID); err != ErrOrderNotFound {
+		t.Fatalf("stale order should be pruned, got %v", err)
+	}
+	if _, err := ob.GetOrder(gtc.ID); err != nil {
+		t.Fatalf("good-til-canceled order should remain: %v", err)
+	}
+	if _, err := ob.PruneExpired(stateDB, [32]byte{1}); err != ErrMarketNotFound {
+		t.Fatalf("expected ErrMarketNotFound, got %v", err)
+	}
+}
+
+func TestOrderBook_GetDepth(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+
+	for _, o := range []struct {
+		isBuy       bool
+		price, size int64
+	}{
+		{true, 990, 20},
+		{true, 1000, 20},
+		{true, 990, 25},
+		{true, 980, 30},
+		{false, 1020, 20},
+		{false, 1010, 40},
+		{false, 1020, 35},
+	} {
+		if _, _, err := ob.PlaceOrder(stateDB, testBookMaker, marketID, o.isBuy, big.NewInt(o.price), big.NewInt(o.size), 0); err != nil {
+			t.Fatalf("PlaceOrder failed: %v", err)
+		}
+	}
+	// A partial fill leaves 20 of the 1010 ask
+	if _, _, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1010), big.NewInt(20), 0); err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	check := func(side string, got []PriceLevel, want [][3]int64) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: expected %d levels, got %d", side, len(want), len(got))
+		}
+		for i, w := range want {
+			if got[i].Price.Int64() != w[0] || got[i].Size.Int64() != w[1] || int64(got[i].Orders) != w[2] {
+				t.Fatalf("%s level %d: got %s x %s (%d orders), want %d x %d (%d orders)",
+					side, i, got[i].Price, got[i].Size, got[i].Orders, w[0], w[1], w[2])
+			}
+		}
+	}
+
+	bids, asks, err := ob.GetDepth(marketID, 0)
+	if err != nil {
+		t.Fatalf("GetDepth failed: %v", err)
+	}
+	check("bids", bids, [][3]int64{{1000, 20, 1}, {990, 45, 2}, {980, 30, 1}})
+	check("asks", asks, [][3]int64{{1010, 20, 1}, {1020, 55, 2}})
+
+	bids, asks, _ = ob.GetDepth(marketID, 2)
+	check("bids", bids, [][3]int64{{1000, 20, 1}, {990, 45, 2}})
+	check("asks", asks, [][3]int64{{1010, 20, 1}, {1020, 55, 2}})
+
+	// The returned levels do not alias book state
+	bids[0].Size.SetInt64(0)
+	if bids, _, _ = ob.GetDepth(marketID, 1); bids[0].Size.Int64() != 20 {
+		t.Fatalf("depth mutation leaked into the book")
+	}
+
+	if _, _, err := ob.GetDepth([32]byte{1}, 1); err != ErrMarketNotFound {
+		t.Fatalf("expected ErrMarketNotFound, got %v", err)
+	}
+}
+
+func TestOrderBook_Fees(t *testing.T) {
+	ob, marketID, stateDB := newTestMarket(t)
+
+	if err := ob.SetFees(marketID, 30, 20); err != ErrInvalidFee {
+		t.Fatalf("rebate above fee should fail with ErrInvalidFee, got %v", err)
+	}
+	if err := ob.SetFees(marketID, 0, FeePrecision+1); err != ErrInvalidFee {
+		t.Fatalf("fee above 100%% should fail with ErrInvalidFee, got %v", err)
+	}
+	if err := ob.SetFees([32]byte{1}, 10, 20); err != ErrMarketNotFound {
+		t.Fatalf("expected ErrMarketNotFound, got %v", err)
+	}
+
+	// Maker rebate 2 bps, taker fee 5 bps
+	if err := ob.SetFees(marketID, 2, 5); err != nil {
+		t.Fatalf("SetFees failed: %v", err)
+	}
+
+	// Notional 1000 * 100 = 100000: rebate 20, fee 50
+	ob.PlaceOrder(stateDB, testBookMaker, marketID, false, big.NewInt(1000), big.NewInt(100), 0)
+	_, fills, err := ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(1000), big.NewInt(100), 0)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if len(fills) != 1 {
+		t.Fatalf("expected 1 fill, got %d", len(fills))
+	}
+	if fills[0].MakerRebate.Int64() != 20 || fills[0].TakerFee.Int64() != 50 {
+		t.Fatalf("expected rebate 20 and fee 50, got %s and %s", fills[0].MakerRebate, fills[0].TakerFee)
+	}
+
+	maker, err := ob.GetFeeAccrual(marketID, testBookMaker)
+	if err != nil {
+		t.Fatalf("GetFeeAccrual failed: %v", err)
+	}
+	if maker.RebatesEarned.Int64() != 20 || maker.FeesPaid.Sign() != 0 {
+		t.Fatalf("maker should earn 20 in rebates and pay nothing, got %s and %s", maker.RebatesEarned, maker.FeesPaid)
+	}
+	taker, _ := ob.GetFeeAccrual(marketID, testBookTrader)
+	if taker.FeesPaid.Int64() != 50 || taker.RebatesEarned.Sign() != 0 {
+		t.Fatalf("taker should pay 50 in fees and earn nothing, got %s and %s", taker.FeesPaid, taker.RebatesEarned)
+	}
+
+	// The protocol keeps the 3 bps spread
+	net := new(big.Int).Sub(taker.FeesPaid, maker.RebatesEarned)
+	if net.Int64() != 100000*3/FeePrecision {
+		t.Fatalf("net fees should equal the 3 bps spread of 30, got %s", net)
+	}
+
+	// Roles follow the order, not the account: the maker now takes
+	ob.PlaceOrder(stateDB, testBookTrader, marketID, true, big.NewInt(500), big.NewInt(20), 0)
+	if _, _, err := ob.MarketOrder(stateDB, testBookMaker, marketID, false, big.NewInt(20), 100); err != nil {
+		t.Fatalf("MarketOrder failed: %v", err)
+	}
+	maker, _ = ob.GetFeeAccrual(marketID, testBookMaker)
+	if maker.FeesPaid.Int64() != 5 {
+		t.Fatalf("maker taking 10000 notional should pay 5, got %s", maker.FeesPaid)
+	}
+	taker, _ = ob.GetFeeAccrual(marketID, testBookTrader)
+	if taker.RebatesEarned.Int64() != 2 {
+		t.Fatalf("resting bid should earn 2, got %s", taker.RebatesEarned)
+	}
+}
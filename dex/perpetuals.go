@@ -4,8 +4,10 @@
 package dex
 
 import (
+	"bytes"
 	"errors"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -74,6 +76,7 @@ func (pe *PerpetualEngine) CreateMarket(
 		MaxLeverage:       maxLeverage,
 		MaintenanceMargin: maintenanceMargin,
 		InsuranceFund:     big.NewInt(0),
+		BadDebt:           big.NewInt(0),
 	}
 
 	pe.Markets[marketID] = market
@@ -82,6 +85,7 @@ func (pe *PerpetualEngine) CreateMarket(
 		LastUpdateTime:    time.Now().Unix(),
 		PremiumEMA:        big.NewInt(0),
 		TWAPWindow:        8 * 3600, // 8 hours
+		Accumulator:       NewFundingAccumulator(),
 	}
 
 	return marketID, nil
@@ -133,7 +137,7 @@ func (pe *PerpetualEngine) OpenPosition(
 			Size:             new(big.Int).Set(size),
 			EntryPrice:       new(big.Int).Set(market.MarkPrice),
 			Margin:           new(big.Int).Set(margin),
-			LastFundingIndex: new(big.Int).Set(fundingState.CumulativeFunding),
+			LastFundingIndex: fundingState.Accumulator.Index(size.Sign() > 0),
 			IsIsolated:       isIsolated,
 		}
 		userPositions[marketID] = position
@@ -155,6 +159,10 @@ func (pe *PerpetualEngine) OpenPosition(
 
 		// Calculate new average entry price
 		position.EntryPrice.Div(totalNotional, newSize)
+		if newSize.Sign() != position.Size.Sign() {
+			// Flipped side: accrue from the new side's index
+			position.LastFundingIndex = fundingState.Accumulator.Index(newSize.Sign() > 0)
+		}
 		position.Size = newSize
 		position.Margin.Add(position.Margin, margin)
 	}
@@ -309,7 +317,11 @@ func (pe *PerpetualEngine) RemoveMargin(
 	return nil
 }
 
-// LiquidatePosition liquidates an underwater position
+// LiquidatePosition liquidates an underwater position. A penalty is taken
+// from the remaining margin and split between the liquidator and the
+// market's insurance fund. If the loss exceeds the margin, the shortfall is
+// drawn from the insurance fund and, once that is empty, auto-deleveraged
+// against the most profitable opposing positions.
 func (pe *PerpetualEngine) LiquidatePosition(
 	liquidator common.Address,
 	owner common.Address,
@@ -333,12 +345,18 @@ func (pe *PerpetualEngine) LiquidatePosition(
 		return nil, ErrPositionNotFound
 	}
 
+	// Prices that fail the price feed's deviation guard cannot liquidate
+	if market.PricesUnreliable {
+		return nil, ErrPricesUnreliable
+	}
+
 	// Check if position is liquidatable
 	if pe.isPositionSafe(position, market, position.Margin) {
 		return nil, ErrPositionNotLiquidatable
 	}
 
-	// Calculate position value and PnL
+	// Equity after the position's loss and pending funding
+	fundingPnL := pe.settleFundingForPosition(position, pe.FundingStates[marketID])
 	positionSize := new(big.Int).Abs(position.Size)
 	priceDiff := new(big.Int).Sub(market.MarkPrice, position.EntryPrice)
 	pnl := new(big.Int).Mul(positionSize, priceDiff)
@@ -346,23 +364,37 @@ func (pe *PerpetualEngine) LiquidatePosition(
 	if position.Size.Sign() < 0 {
 		pnl.Neg(pnl)
 	}
-
-	// Remaining margin after loss
 	remainingMargin := new(big.Int).Add(position.Margin, pnl)
+	remainingMargin.Add(remainingMargin, fundingPnL)
+
+	reward := big.NewInt(0)
+	if remainingMargin.Sign() >= 0 {
+		// Penalty is a share of notional, capped at what is left; the
+		// insurance fund takes its cut and the liquidator the rest
+		notional := new(big.Int).Mul(positionSize, market.MarkPrice)
+		notional.Div(notional, Q96)
+		penalty := new(big.Int).Mul(notional, big.NewInt(PerpLiquidationPenalty))
+		penalty.Div(penalty, big.NewInt(PerpFeePrecision))
+		if penalty.Cmp(remainingMargin) > 0 {
+			penalty.Set(remainingMargin)
+		}
 
-	// Liquidation reward (5% of position notional, capped at remaining margin)
-	notional := new(big.Int).Mul(positionSize, market.MarkPrice)
-	notional.Div(notional, Q96)
-	reward := new(big.Int).Div(notional, big.NewInt(20)) // 5%
+		insuranceCut := new(big.Int).Mul(penalty, big.NewInt(PerpInsuranceShare))
+		insuranceCut.Div(insuranceCut, big.NewInt(PerpFeePrecision))
+		market.InsuranceFund.Add(market.InsuranceFund, insuranceCut)
+		reward.Sub(penalty, insuranceCut)
+	} else {
+		// Bad debt: draw on the insurance fund, then auto-deleverage
+		badDebt := new(big.Int).Neg(remainingMargin)
+		covered := new(big.Int).Set(badDebt)
+		if covered.Cmp(market.InsuranceFund) > 0 {
+			covered.Set(market.InsuranceFund)
+		}
+		market.InsuranceFund.Sub(market.InsuranceFund, covered)
 
-	if reward.Cmp(remainingMargin) > 0 {
-		// Socialized loss - use insurance fund
-		deficit := new(big.Int).Sub(reward, remainingMargin)
-		if market.InsuranceFund.Cmp(deficit) >= 0 {
-			market.InsuranceFund.Sub(market.InsuranceFund, deficit)
-		} else {
-			// ADL would be triggered here in production
-			reward = remainingMargin
+		if shortfall := badDebt.Sub(badDebt, covered); shortfall.Sign() > 0 {
+			uncovered := pe.autoDeleverage(marketID, market, position.Size.Sign() < 0, shortfall)
+			market.BadDebt.Add(market.BadDebt, uncovered)
 		}
 	}
 
@@ -379,6 +411,52 @@ func (pe *PerpetualEngine) LiquidatePosition(
 	return reward, nil
 }
 
+// autoDeleverage covers a shortfall from the unrealized profit of positions
+// on the given side, most profitable first, by deducting it from their
+// margin. No position loses more than its profit. Returns the part of the
+// shortfall that could not be covered.
+func (pe *PerpetualEngine) autoDeleverage(marketID [32]byte, market *PerpMarket, isLong bool, shortfall *big.Int) *big.Int {
+	type candidate struct {
+		position *PerpPosition
+		profit   *big.Int
+	}
+
+	var candidates []candidate
+	for _, userPositions := range pe.Positions {
+		position := userPositions[marketID]
+		if position == nil || (position.Size.Sign() > 0) != isLong || position.Size.Sign() == 0 {
+			continue
+		}
+		profit := new(big.Int).Sub(market.MarkPrice, position.EntryPrice)
+		profit.Mul(profit, position.Size)
+		profit.Div(profit, Q96)
+		if profit.Sign() > 0 {
+			candidates = append(candidates, candidate{position, profit})
+		}
+	}
+	// Map iteration is random; rank by profit, then owner, for determinism
+	sort.Slice(candidates, func(i, j int) bool {
+		if c := candidates[i].profit.Cmp(candidates[j].profit); c != 0 {
+			return c > 0
+		}
+		return bytes.Compare(candidates[i].position.Owner[:], candidates[j].position.Owner[:]) < 0
+	})
+
+	remaining := new(big.Int).Set(shortfall)
+	for _, c := range candidates {
+		if remaining.Sign() == 0 {
+			break
+		}
+		haircut := c.profit
+		if haircut.Cmp(remaining) > 0 {
+			haircut = remaining
+		}
+		c.position.Margin.Sub(c.position.Margin, haircut)
+		remaining.Sub(remaining, haircut)
+	}
+	return remaining
+}
+
 // UpdateFunding calculates and applies funding rate
 func (pe *PerpetualEngine) UpdateFunding(marketID [32]byte) error {
 	pe.mu.Lock()
@@ -434,6 +512,7 @@ func (pe *PerpetualEngine) UpdateFunding(marketID [32]byte) error {
 	fundingPayment := new(big.Int).Mul(fundingRate, market.MarkPrice)
 	fundingPayment.Div(fundingPayment, big.NewInt(1e6))
 	fundingState.CumulativeFunding.Add(fundingState.CumulativeFunding, fundingPayment)
+	fundingState.Accumulator.Accrue(fundingPayment, market.OpenInterestLong, market.OpenInterestShort)
 	fundingState.LastUpdateTime = now
 
 	return nil
@@ -452,6 +531,66 @@ func (pe *PerpetualEngine) GetFundingRate(marketID [32]byte) (*big.Int, error) {
 	return new(big.Int).Set(market.FundingRate), nil
 }
 
+// GetPendingFunding returns the funding a position has accrued since its
+// last settlement without settling it: negative when owed, positive when
+// earned
+func (pe *PerpetualEngine) GetPendingFunding(owner common.Address, marketID [32]byte) (*big.Int, error) {
+	pe.mu.RLock()
+	defer pe.mu.RUnlock()
+
+	position := pe.Positions[owner][marketID]
+	if position == nil {
+		return nil, ErrPositionNotFound
+	}
+
+	return pe.FundingStates[marketID].Accumulator.Pending(position.Size, position.LastFundingIndex), nil
+}
+
+// GetInsuranceFund returns a market's insurance fund balance
+func (pe *PerpetualEngine) GetInsuranceFund(marketID [32]byte) (*big.Int, error) {
+	pe.mu.RLock()
+	defer pe.mu.RUnlock()
+
+	market, exists := pe.Markets[marketID]
+	if !exists {
+		return nil, ErrPoolNotFound
+	}
+	return new(big.Int).Set(market.InsuranceFund), nil
+}
+
+// DepositInsuranceFund adds to a market's insurance fund
+func (pe *PerpetualEngine) DepositInsuranceFund(marketID [32]byte, amount *big.Int) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	market, exists := pe.Markets[marketID]
+	if !exists {
+		return ErrPoolNotFound
+	}
+	market.InsuranceFund.Add(market.InsuranceFund, amount)
+	return nil
+}
+
+// GetMarket returns a copy of a market
+func (pe *PerpetualEngine) GetMarket(marketID [32]byte) (*PerpMarket, error) {
+	pe.mu.RLock()
+	defer pe.mu.RUnlock()
+
+	market, exists := pe.Markets[marketID]
+	if !exists {
+		return nil, ErrPoolNotFound
+	}
+
+	m := *market
+	m.MarkPrice = new(big.Int).Set(market.MarkPrice)
+	m.IndexPrice = new(big.Int).Set(market.IndexPrice)
+	return &m, nil
+}
+
 // GetPosition returns a user's position
 func (pe *PerpetualEngine) GetPosition(owner common.Address, marketID [32]byte) (*PerpPosition, error) {
 	pe.mu.RLock()
@@ -521,6 +660,20 @@ func (pe *PerpetualEngine) UpdateMarkPrice(marketID [32]byte, newPrice *big.Int)
 	return nil
 }
 
+// SetPricesUnreliable flags whether a market's prices may be used for liquidation
+func (pe *PerpetualEngine) SetPricesUnreliable(marketID [32]byte, unreliable bool) error {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	market, exists := pe.Markets[marketID]
+	if !exists {
+		return ErrPoolNotFound
+	}
+
+	market.PricesUnreliable = unreliable
+	return nil
+}
+
 // UpdateIndexPrice updates the oracle index price for a market
 func (pe *PerpetualEngine) UpdateIndexPrice(marketID [32]byte, newPrice *big.Int) error {
 	pe.mu.Lock()
@@ -538,16 +691,9 @@ func (pe *PerpetualEngine) UpdateIndexPrice(marketID [32]byte, newPrice *big.Int
 // Helper functions
 
 func (pe *PerpetualEngine) settleFundingForPosition(position *PerpPosition, state *FundingState) *big.Int {
-	// Funding payment = size * (currentFundingIndex - lastFundingIndex) / Q96
-	fundingDiff := new(big.Int).Sub(state.CumulativeFunding, position.LastFundingIndex)
-	payment := new(big.Int).Mul(position.Size, fundingDiff)
-	payment.Div(payment, Q96)
-
-	// Longs pay shorts when funding > 0
-	// So longs get negative funding, shorts get positive
-	payment.Neg(payment)
-
-	position.LastFundingIndex = new(big.Int).Set(state.CumulativeFunding)
+	// Funding PnL = |size| * (lastIndex - sideIndex) / Q96
+	payment := state.Accumulator.Pending(position.Size, position.LastFundingIndex)
+	position.LastFundingIndex = state.Accumulator.Index(position.Size.Sign() > 0)
 	return payment
 }
 
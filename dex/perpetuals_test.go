@@ -0,0 +1,238 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+)
+
+var testPerpUser3 = common.HexToAddress("0x6666666666666666666666666666666666666666")
+
+// newTestPerpMarket creates a market at price 200 so the clamped 0.75%
+// funding rate is exactly 1.5 per unit of size
+func newTestPerpMarket(t *testing.T) (*PerpetualEngine, [32]byte) {
+	t.Helper()
+
+	engine := NewPerpetualEngine()
+	marketID, err := engine.CreateMarket(testBookBase, testBookQuote, q96Price(200), 100, big.NewInt(5e16))
+	if err != nil {
+		t.Fatalf("CreateMarket failed: %v", err)
+	}
+	return engine, marketID
+}
+
+func openPerp(t *testing.T, engine *PerpetualEngine, marketID [32]byte, owner common.Address, size int64) {
+	t.Helper()
+	if _, err := engine.OpenPosition(owner, marketID, big.NewInt(size), big.NewInt(1000), false); err != nil {
+		t.Fatalf("OpenPosition failed: %v", err)
+	}
+}
+
+// accrueFunding runs one funding period with mark above index when
+// longsPay, below it otherwise, so the rate clamps to ±0.75%
+func accrueFunding(t *testing.T, engine *PerpetualEngine, marketID [32]byte, longsPay bool) {
+	t.Helper()
+	index := q96Price(199)
+	if !longsPay {
+		index = q96Price(201)
+	}
+	engine.UpdateIndexPrice(marketID, index)
+	engine.FundingStates[marketID].LastUpdateTime = 0
+	if err := engine.UpdateFunding(marketID); err != nil {
+		t.Fatalf("UpdateFunding failed: %v", err)
+	}
+}
+
+func pendingFunding(t *testing.T, engine *PerpetualEngine, marketID [32]byte, owner common.Address) int64 {
+	t.Helper()
+	pending, err := engine.GetPendingFunding(owner, marketID)
+	if err != nil {
+		t.Fatalf("GetPendingFunding failed: %v", err)
+	}
+	return pending.Int64()
+}
+
+func TestPerpFunding_BalancedOpenInterest(t *testing.T) {
+	engine, marketID := newTestPerpMarket(t)
+
+	openPerp(t, engine, marketID, testUser1, 10)
+	openPerp(t, engine, marketID, testUser2, -10)
+	accrueFunding(t, engine, marketID, true)
+
+	long := pendingFunding(t, engine, marketID, testUser1)
+	short := pendingFunding(t, engine, marketID, testUser2)
+	if long != -15 || short != 15 {
+		t.Fatalf("expected long -15 and short +15, got %d and %d", long, short)
+	}
+	if long+short != 0 {
+		t.Fatalf("funding not conserved: net %d", long+short)
+	}
+
+	// Querying does not settle
+	if again := pendingFunding(t, engine, marketID, testUser1); again != long {
+		t.Fatalf("pending funding changed between queries: %d != %d", again, long)
+	}
+}
+
+func TestPerpFunding_ImbalanceChargesHeavierSide(t *testing.T) {
+	engine, marketID := newTestPerpMarket(t)
+
+	// 30 long against 10 short; longs are heavier and pay
+	openPerp(t, engine, marketID, testUser1, 20)
+	openPerp(t, engine, marketID, testPerpUser3, 10)
+	openPerp(t, engine, marketID, testUser2, -10)
+	accrueFunding(t, engine, marketID, true)
+
+	long1 := pendingFunding(t, engine, marketID, testUser1)
+	long2 := pendingFunding(t, engine, marketID, testPerpUser3)
+	short := pendingFunding(t, engine, marketID, testUser2)
+	if long1 != -30 || long2 != -15 {
+		t.Fatalf("expected longs to pay 30 and 15, got %d and %d", long1, long2)
+	}
+	// The lighter side receives everything the heavier side paid
+	if short != 45 {
+		t.Fatalf("expected short to receive 45, got %d", short)
+	}
+	if net := long1 + long2 + short; net != 0 {
+		t.Fatalf("funding not conserved: net %d", net)
+	}
+
+	// Settling on close realizes exactly the pending amount
+	pnl, err := engine.ClosePosition(testUser2, marketID, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("ClosePosition failed: %v", err)
+	}
+	if pnl.Int64() != short {
+		t.Fatalf("expected close PnL %d (no price move), got %s", short, pnl)
+	}
+}
+
+func TestPerpFunding_RoundingFavorsMarket(t *testing.T) {
+	engine, marketID := newTestPerpMarket(t)
+
+	// Shorts pay 1.5 per unit across 7 units, shared by 3 long units
+	openPerp(t, engine, marketID, testUser1, 1)
+	openPerp(t, engine, marketID, testPerpUser3, 2)
+	openPerp(t, engine, marketID, testUser2, -7)
+	accrueFunding(t, engine, marketID, false)
+
+	long1 := pendingFunding(t, engine, marketID, testUser1)
+	long2 := pendingFunding(t, engine, marketID, testPerpUser3)
+	short := pendingFunding(t, engine, marketID, testUser2)
+	if short != -11 { // 10.5 owed rounds up
+		t.Fatalf("expected short to owe 11, got %d", short)
+	}
+	if long1 != 3 || long2 != 7 { // 3.5 and 7 earned round down
+		t.Fatalf("expected longs to earn 3 and 7, got %d and %d", long1, long2)
+	}
+	if net := long1 + long2 + short; net > 0 {
+		t.Fatalf("positions earned more than was paid: net %d", net)
+	}
+}
+
+func TestPerpFunding_OneSidedMarketAccruesNothing(t *testing.T) {
+	engine, marketID := newTestPerpMarket(t)
+
+	openPerp(t, engine, marketID, testUser1, 10)
+	accrueFunding(t, engine, marketID, true)
+
+	if pending := pendingFunding(t, engine, marketID, testUser1); pending != 0 {
+		t.Fatalf("expected no funding without counterparties, got %d", pending)
+	}
+	if _, err := engine.GetPendingFunding(testUser2, marketID); err != ErrPositionNotFound {
+		t.Fatalf("expected ErrPositionNotFound, got %v", err)
+	}
+}
+
+func TestPerpInsurance_PenaltyFundsPool(t *testing.T) {
+	engine := NewPerpetualEngine()
+	marketID, err := engine.CreateMarket(testBookBase, testBookQuote, q96Price(200), 100, big.NewInt(1e17))
+	if err != nil {
+		t.Fatalf("CreateMarket failed: %v", err)
+	}
+	openPerp(t, engine, marketID, testUser1, 10) // margin 1000
+
+	// Mark falls to 120: equity 200 still covers maintenance 120
+	engine.UpdateMarkPrice(marketID, q96Price(120))
+	if _, err := engine.LiquidatePosition(testUser2, testUser1, marketID); err != ErrPositionNotLiquidatable {
+		t.Fatalf("expected ErrPositionNotLiquidatable, got %v", err)
+	}
+
+	// Mark falls to 110: loss 900, equity 100 below maintenance 110
+	engine.UpdateMarkPrice(marketID, q96Price(110))
+	reward, err := engine.LiquidatePosition(testUser2, testUser1, marketID)
+	if err != nil {
+		t.Fatalf("LiquidatePosition failed: %v", err)
+	}
+
+	// Penalty is 5% of notional 1100 = 55: 27 to the fund, 28 to the liquidator
+	fund, err := engine.GetInsuranceFund(marketID)
+	if err != nil {
+		t.Fatalf("GetInsuranceFund failed: %v", err)
+	}
+	if fund.Int64() != 27 || reward.Int64() != 28 {
+		t.Fatalf("expected fund 27 and reward 28, got %s and %s", fund, reward)
+	}
+	if _, err := engine.GetPosition(testUser1, marketID); err != ErrPositionNotFound {
+		t.Fatalf("expected position removed, got %v", err)
+	}
+}
+
+func TestPerpInsurance_BadDebtDrawsFundThenADL(t *testing.T) {
+	engine, marketID := newTestPerpMarket(t)
+	if err := engine.DepositInsuranceFund(marketID, big.NewInt(100)); err != nil {
+		t.Fatalf("DepositInsuranceFund failed: %v", err)
+	}
+
+	// testUser2 shorts 10 at 200 and is the most profitable counterparty
+	openPerp(t, engine, marketID, testUser1, 10)
+	openPerp(t, engine, marketID, testUser2, -10)
+
+	// Mark falls to 90: the long loses 1100 against 1000 margin
+	engine.UpdateMarkPrice(marketID, q96Price(90))
+	reward, err := engine.LiquidatePosition(testUser2, testUser1, marketID)
+	if err != nil {
+		t.Fatalf("LiquidatePosition failed: %v", err)
+	}
+	if reward.Sign() != 0 {
+		t.Fatalf("expected no reward on bad debt, got %s", reward)
+	}
+	fund, _ := engine.GetInsuranceFund(marketID)
+	if fund.Sign() != 0 {
+		t.Fatalf("expected fund drained by the 100 of bad debt, got %s", fund)
+	}
+	short, _ := engine.GetPosition(testUser2, marketID)
+	if short.Margin.Int64() != 1000 {
+		t.Fatalf("fund covered the debt, counterparty margin should be untouched, got %s", short.Margin)
+	}
+
+	// A second bankrupt long with the fund empty falls to ADL
+	if _, err := engine.OpenPosition(testUser1, marketID, big.NewInt(10), big.NewInt(100), false); err != nil {
+		t.Fatalf("OpenPosition failed: %v", err)
+	}
+	openPerp(t, engine, marketID, testPerpUser3, -5)
+
+	// Mark falls to 70: the long loses 200 against 100 margin
+	engine.UpdateMarkPrice(marketID, q96Price(70))
+	if _, err := engine.LiquidatePosition(testUser2, testUser1, marketID); err != nil {
+		t.Fatalf("LiquidatePosition failed: %v", err)
+	}
+
+	// testUser2 (profit 1300) absorbs the 100 shortfall before testUser3 (profit 100)
+	short, _ = engine.GetPosition(testUser2, marketID)
+	if short.Margin.Int64() != 900 {
+		t.Fatalf("expected most profitable short to be deleveraged to 900, got %s", short.Margin)
+	}
+	other, _ := engine.GetPosition(testPerpUser3, marketID)
+	if other.Margin.Int64() != 1000 {
+		t.Fatalf("expected less profitable short untouched, got %s", other.Margin)
+	}
+	market, _ := engine.GetMarket(marketID)
+	if market.BadDebt.Sign() != 0 {
+		t.Fatalf("expected ADL to cover the shortfall, got bad debt %s", market.BadDebt)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/holiman/uint256"
@@ -41,6 +42,7 @@ var (
 	settledPrefix       = []byte("setl")
 	protocolFeePrefix   = []byte("pfee")
 	hookRegistryPrefix  = []byte("hook")
+	ownerIndexPrefix    = []byte("ownr")
 )
 
 // PoolManager implements the singleton DEX pool manager precompile
@@ -73,6 +75,12 @@ type PoolManager struct {
 
 	// protocolFeeController can set protocol fees
 	protocolFeeController common.Address
+
+	// hooks records the callbacks each hook implements (LP-9013 LXHooks)
+	hooks *HookRegistry
+
+	// hookInvoker performs hook calls; nil means hooks are not executed
+	hookInvoker HookInvoker
 }
 
 // NewPoolManager creates a new pool manager instance
@@ -82,9 +90,15 @@ func NewPoolManager() *PoolManager {
 		positions:     make(map[[32]byte]*Position),
 		currentDeltas: make(map[common.Address]map[Currency]*big.Int),
 		lockers:       make([]common.Address, 0),
+		hooks:         NewHookRegistry(),
 	}
 }
 
+// SetHookInvoker sets the function used to call hook contracts
+func (pm *PoolManager) SetHookInvoker(invoker HookInvoker) {
+	pm.hookInvoker = invoker
+}
+
 // makeStorageKey creates a storage key from prefix and identifier
 func makeStorageKey(prefix []byte, id []byte) common.Hash {
 	h := blake3.New()
@@ -95,6 +109,21 @@ func makeStorageKey(prefix []byte, id []byte) common.Hash {
 	return key
 }
 
+// nonReentrant takes the reentrancy lock held in addr's storage at lockKey
+// and returns the function that releases it. The lock lives in state rather
+// than in a Go mutex so that a token transfer hook reentering the precompile
+// is rejected with ErrReentrant instead of deadlocking. Callers still follow
+// checks-effects-interactions: all state is saved before any token moves.
+func nonReentrant(stateDB StateDB, addr common.Address, lockKey common.Hash) (func(), error) {
+	if stateDB.GetState(addr, lockKey) != (common.Hash{}) {
+		return nil, ErrReentrant
+	}
+	stateDB.SetState(addr, lockKey, common.BytesToHash([]byte{1}))
+	return func() {
+		stateDB.SetState(addr, lockKey, common.Hash{})
+	}, nil
+}
+
 // =========================================================================
 // Pool Initialization
 // =========================================================================
@@ -116,6 +145,9 @@ func (pm *PoolManager) Initialize(
 	if key.Fee > FeeMax {
 		return 0, ErrInvalidFee
 	}
+	if !isValidFeeSpacing(key.Fee, key.TickSpacing) {
+		return 0, ErrInvalidTickSpacing
+	}
 
 	// Validate sqrt price
 	if sqrtPriceX96.Cmp(MinSqrtRatio) < 0 || sqrtPriceX96.Cmp(MaxSqrtRatio) > 0 {
@@ -133,8 +165,12 @@ func (pm *PoolManager) Initialize(
 	// Calculate initial tick from sqrt price
 	tick := pm.sqrtPriceX96ToTick(sqrtPriceX96)
 
-	// Call beforeInitialize hook if present
+	// Validate and record the hook's permission bits, then call
+	// beforeInitialize if present
 	if key.Hooks != (common.Address{}) {
+		if _, err := pm.hooks.RegisterFromAddress(key.Hooks); err != nil {
+			return 0, err
+		}
 		if err := pm.callHook(stateDB, key.Hooks, HookBeforeInitialize, key, sqrtPriceX96, hookData); err != nil {
 			return 0, err
 		}
@@ -160,6 +196,36 @@ func (pm *PoolManager) Initialize(
 	return tick, nil
 }
 
+// InitializeFromTick creates and initializes a new pool at the sqrt price
+// of tick
+func (pm *PoolManager) InitializeFromTick(
+	stateDB StateDB,
+	key PoolKey,
+	tick int24,
+	hookData []byte,
+) (int24, error) {
+	sqrtPriceX96, err := GetSqrtRatioAtTick(tick)
+	if err != nil {
+		return 0, err
+	}
+	return pm.Initialize(stateDB, key, sqrtPriceX96, hookData)
+}
+
+// InitializeFromPrice creates and initializes a new pool at price, quoted
+// as currency1 per currency0
+func (pm *PoolManager) InitializeFromPrice(
+	stateDB StateDB,
+	key PoolKey,
+	price *big.Rat,
+	hookData []byte,
+) (int24, error) {
+	sqrtPriceX96, err := sqrtPriceX96FromPrice(price)
+	if err != nil {
+		return 0, err
+	}
+	return pm.Initialize(stateDB, key, sqrtPriceX96, hookData)
+}
+
 // =========================================================================
 // Flash Accounting - Lock/Unlock Pattern
 // =========================================================================
@@ -404,6 +470,85 @@ func (pm *PoolManager) ModifyLiquidity(
 		return ZeroBalanceDelta(), ZeroBalanceDelta(), ErrUnauthorized
 	}
 
+	positionKey := PositionKey(locker, params.TickLower, params.TickUpper, params.Salt)
+	return pm.modifyLiquidity(stateDB, key, locker, positionKey, params, hookData)
+}
+
+// ModifyPosition adds or removes liquidity from an existing position by its
+// key, so the owner of a transferred position can still manage it
+func (pm *PoolManager) ModifyPosition(
+	stateDB StateDB,
+	key PoolKey,
+	positionKey [32]byte,
+	liquidityDelta *big.Int,
+	hookData []byte,
+) (BalanceDelta, BalanceDelta, error) {
+	locker := pm.getCurrentLocker()
+	if locker == (common.Address{}) {
+		return ZeroBalanceDelta(), ZeroBalanceDelta(), ErrUnauthorized
+	}
+
+	position := pm.getPosition(stateDB, positionKey)
+	if position.Owner == (common.Address{}) {
+		return ZeroBalanceDelta(), ZeroBalanceDelta(), ErrPositionNotFound
+	}
+
+	params := ModifyLiquidityParams{
+		TickLower:      position.TickLower,
+		TickUpper:      position.TickUpper,
+		LiquidityDelta: liquidityDelta,
+		Salt:           position.Salt,
+	}
+	return pm.modifyLiquidity(stateDB, key, locker, positionKey, params, hookData)
+}
+
+// TransferPosition reassigns the position at positionKey from the current
+// locker to newOwner. The key, liquidity and fee accounting are unchanged;
+// newOwner manages the position through ModifyPosition.
+func (pm *PoolManager) TransferPosition(
+	stateDB StateDB,
+	positionKey [32]byte,
+	newOwner common.Address,
+) error {
+	locker := pm.getCurrentLocker()
+	if locker == (common.Address{}) {
+		return ErrUnauthorized
+	}
+	if newOwner == (common.Address{}) {
+		return ErrInvalidParameter
+	}
+
+	position := pm.getPosition(stateDB, positionKey)
+	if position.Owner == (common.Address{}) {
+		return ErrPositionNotFound
+	}
+	if position.Owner != locker {
+		return ErrUnauthorized
+	}
+
+	// Voting power moves with the position; the pool total is unchanged
+	poolId := pm.getPositionPool(stateDB, positionKey)
+	value := pm.getPositionVotingValue(stateDB, positionKey)
+	pm.checkpointVotingPower(stateDB, poolId, locker, new(big.Int).Neg(value))
+	pm.checkpointVotingPower(stateDB, poolId, newOwner, value)
+
+	position.Owner = newOwner
+	pm.setPosition(stateDB, positionKey, position)
+	pm.unindexPosition(stateDB, locker, poolId, positionKey)
+	pm.indexPosition(stateDB, newOwner, poolId, positionKey)
+	return nil
+}
+
+// modifyLiquidity applies a liquidity change to the position at positionKey
+// on behalf of locker
+func (pm *PoolManager) modifyLiquidity(
+	stateDB StateDB,
+	key PoolKey,
+	locker common.Address,
+	positionKey [32]byte,
+	params ModifyLiquidityParams,
+	hookData []byte,
+) (BalanceDelta, BalanceDelta, error) {
 	// Validate tick range
 	if params.TickLower >= params.TickUpper {
 		return ZeroBalanceDelta(), ZeroBalanceDelta(), ErrInvalidTickRange
@@ -418,6 +563,17 @@ func (pm *PoolManager) ModifyLiquidity(
 	if !pool.IsInitialized() {
 		return ZeroBalanceDelta(), ZeroBalanceDelta(), ErrPoolNotInitialized
 	}
+	// Initialize validated the spacing, so it is at least MinTickSpacing
+	if params.TickLower%key.TickSpacing != 0 || params.TickUpper%key.TickSpacing != 0 {
+		return ZeroBalanceDelta(), ZeroBalanceDelta(), ErrTickMisaligned
+	}
+
+	// Only the current owner may change a position; after TransferPosition
+	// that excludes the address the key was derived from
+	position := pm.getPosition(stateDB, positionKey)
+	if position.Owner != (common.Address{}) && position.Owner != locker {
+		return ZeroBalanceDelta(), ZeroBalanceDelta(), ErrUnauthorized
+	}
 
 	// Call beforeAddLiquidity or beforeRemoveLiquidity hook
 	isAdd := params.LiquidityDelta.Sign() > 0
@@ -441,14 +597,21 @@ func (pm *PoolManager) ModifyLiquidity(
 		pool.Liquidity = new(big.Int).Add(pool.Liquidity, params.LiquidityDelta)
 	}
 
+	// Credit band donations earned at the old liquidity before changing it
+	pm.accrueRangeDonations(stateDB, poolId, position, params.TickLower, params.TickUpper)
+	pm.updateRangeLiquidity(stateDB, poolId, params.TickLower, params.TickUpper, params.LiquidityDelta)
+	votingDelta := pm.votingValueDelta(stateDB, pool.SqrtPriceX96, positionKey, position, params)
+
 	// Update position
-	positionKey := PositionKey(locker, params.TickLower, params.TickUpper, params.Salt)
-	position := pm.getPosition(stateDB, positionKey)
 	position.Liquidity = new(big.Int).Add(position.Liquidity, params.LiquidityDelta)
 	position.Owner = locker
 	position.TickLower = params.TickLower
 	position.TickUpper = params.TickUpper
+	position.Salt = params.Salt
 	pm.setPosition(stateDB, positionKey, position)
+	pm.setPositionPool(stateDB, positionKey, poolId)
+	pm.indexPosition(stateDB, locker, poolId, positionKey)
+	pm.checkpointVotingPower(stateDB, poolId, locker, votingDelta)
 
 	// Save pool state
 	pm.setPool(stateDB, poolId, pool)
@@ -534,6 +697,32 @@ func (pm *PoolManager) Donate(
 	return delta, nil
 }
 
+// SetProtocolFee sets the share of a pool's swap fees, in millionths, that
+// accrues to the protocol. Only the protocol fee controller may call it.
+func (pm *PoolManager) SetProtocolFee(
+	stateDB StateDB,
+	caller common.Address,
+	key PoolKey,
+	protocolFee uint24,
+) error {
+	if pm.protocolFeeController == (common.Address{}) || caller != pm.protocolFeeController {
+		return ErrUnauthorized
+	}
+	if protocolFee > MaxProtocolFee {
+		return ErrInvalidFee
+	}
+
+	poolId := key.ID()
+	pool := pm.getPool(stateDB, poolId)
+	if !pool.IsInitialized() {
+		return ErrPoolNotInitialized
+	}
+
+	pool.ProtocolFee = protocolFee
+	pm.setPool(stateDB, poolId, pool)
+	return nil
+}
+
 // =========================================================================
 // Flash Loans
 // =========================================================================
@@ -630,6 +819,15 @@ func (pm *PoolManager) getPool(stateDB StateDB, poolId [32]byte) *Pool {
 		pool.Liquidity = new(big.Int).SetBytes(liqHash[:])
 	}
 
+	// Read fee accounting
+	pool.FeeGrowth0X128 = pm.getPoolWord(stateDB, poolStatePrefix, poolId, "feeGrowth0")
+	pool.FeeGrowth1X128 = pm.getPoolWord(stateDB, poolStatePrefix, poolId, "feeGrowth1")
+	pool.LPFees0 = pm.getPoolWord(stateDB, poolStatePrefix, poolId, "lpFees0")
+	pool.LPFees1 = pm.getPoolWord(stateDB, poolStatePrefix, poolId, "lpFees1")
+	pool.ProtocolFees0 = pm.getPoolWord(stateDB, protocolFeePrefix, poolId, "fees0")
+	pool.ProtocolFees1 = pm.getPoolWord(stateDB, protocolFeePrefix, poolId, "fees1")
+	pool.ProtocolFee = uint24(pm.getPoolWord(stateDB, protocolFeePrefix, poolId, "share").Uint64())
+
 	pm.pools[poolId] = pool
 	return pool
 }
@@ -655,6 +853,29 @@ func (pm *PoolManager) setPool(stateDB StateDB, poolId [32]byte, pool *Pool) {
 	var liqHash common.Hash
 	pool.Liquidity.FillBytes(liqHash[:])
 	stateDB.SetState(poolManagerAddr, liqKey, liqHash)
+
+	// Write fee accounting
+	pm.setPoolWord(stateDB, poolStatePrefix, poolId, "feeGrowth0", pool.FeeGrowth0X128)
+	pm.setPoolWord(stateDB, poolStatePrefix, poolId, "feeGrowth1", pool.FeeGrowth1X128)
+	pm.setPoolWord(stateDB, poolStatePrefix, poolId, "lpFees0", pool.LPFees0)
+	pm.setPoolWord(stateDB, poolStatePrefix, poolId, "lpFees1", pool.LPFees1)
+	pm.setPoolWord(stateDB, protocolFeePrefix, poolId, "fees0", pool.ProtocolFees0)
+	pm.setPoolWord(stateDB, protocolFeePrefix, poolId, "fees1", pool.ProtocolFees1)
+	pm.setPoolWord(stateDB, protocolFeePrefix, poolId, "share", big.NewInt(int64(pool.ProtocolFee)))
+}
+
+// getPoolWord reads an unsigned 256-bit pool field
+func (pm *PoolManager) getPoolWord(stateDB StateDB, prefix []byte, poolId [32]byte, field string) *big.Int {
+	key := makeStorageKey(prefix, append(poolId[:], []byte(field)...))
+	return new(big.Int).SetBytes(stateDB.GetState(poolManagerAddr, key).Bytes())
+}
+
+// setPoolWord writes an unsigned 256-bit pool field
+func (pm *PoolManager) setPoolWord(stateDB StateDB, prefix []byte, poolId [32]byte, field string, value *big.Int) {
+	key := makeStorageKey(prefix, append(poolId[:], []byte(field)...))
+	var hash common.Hash
+	value.FillBytes(hash[:])
+	stateDB.SetState(poolManagerAddr, key, hash)
 }
 
 // getPosition retrieves position state from storage
@@ -669,6 +890,9 @@ func (pm *PoolManager) getPosition(stateDB StateDB, positionKey [32]byte) *Posit
 		TokensOwed1:              big.NewInt(0),
 		FeeGrowthInside0LastX128: big.NewInt(0),
 		FeeGrowthInside1LastX128: big.NewInt(0),
+
+		RangeGrowthInside0LastX128: big.NewInt(0),
+		RangeGrowthInside1LastX128: big.NewInt(0),
 	}
 
 	// Load from state
@@ -678,6 +902,29 @@ func (pm *PoolManager) getPosition(stateDB StateDB, positionKey [32]byte) *Posit
 		pos.Liquidity = new(big.Int).SetBytes(liqHash[:])
 	}
 
+	ownerKey := makeStorageKey(positionPrefix, append(positionKey[:], []byte("owner")...))
+	pos.Owner = common.BytesToAddress(stateDB.GetState(poolManagerAddr, ownerKey).Bytes())
+
+	ticksKey := makeStorageKey(positionPrefix, append(positionKey[:], []byte("ticks")...))
+	ticksHash := stateDB.GetState(poolManagerAddr, ticksKey)
+	pos.TickLower = int24(binary.BigEndian.Uint32(ticksHash[24:28]))
+	pos.TickUpper = int24(binary.BigEndian.Uint32(ticksHash[28:32]))
+
+	saltKey := makeStorageKey(positionPrefix, append(positionKey[:], []byte("salt")...))
+	pos.Salt = stateDB.GetState(poolManagerAddr, saltKey)
+
+	for field, value := range map[string]**big.Int{
+		"fg0":   &pos.FeeGrowthInside0LastX128,
+		"fg1":   &pos.FeeGrowthInside1LastX128,
+		"rg0":   &pos.RangeGrowthInside0LastX128,
+		"rg1":   &pos.RangeGrowthInside1LastX128,
+		"owed0": &pos.TokensOwed0,
+		"owed1": &pos.TokensOwed1,
+	} {
+		fieldKey := makeStorageKey(positionPrefix, append(positionKey[:], []byte(field)...))
+		*value = new(big.Int).SetBytes(stateDB.GetState(poolManagerAddr, fieldKey).Bytes())
+	}
+
 	pm.positions[positionKey] = pos
 	return pos
 }
@@ -691,6 +938,32 @@ func (pm *PoolManager) setPosition(stateDB StateDB, positionKey [32]byte, pos *P
 	var liqHash common.Hash
 	pos.Liquidity.FillBytes(liqHash[:])
 	stateDB.SetState(poolManagerAddr, liqKey, liqHash)
+
+	ownerKey := makeStorageKey(positionPrefix, append(positionKey[:], []byte("owner")...))
+	stateDB.SetState(poolManagerAddr, ownerKey, common.BytesToHash(pos.Owner.Bytes()))
+
+	ticksKey := makeStorageKey(positionPrefix, append(positionKey[:], []byte("ticks")...))
+	var ticksHash common.Hash
+	binary.BigEndian.PutUint32(ticksHash[24:28], uint32(pos.TickLower))
+	binary.BigEndian.PutUint32(ticksHash[28:32], uint32(pos.TickUpper))
+	stateDB.SetState(poolManagerAddr, ticksKey, ticksHash)
+
+	saltKey := makeStorageKey(positionPrefix, append(positionKey[:], []byte("salt")...))
+	stateDB.SetState(poolManagerAddr, saltKey, pos.Salt)
+
+	for field, value := range map[string]*big.Int{
+		"fg0":   pos.FeeGrowthInside0LastX128,
+		"fg1":   pos.FeeGrowthInside1LastX128,
+		"rg0":   pos.RangeGrowthInside0LastX128,
+		"rg1":   pos.RangeGrowthInside1LastX128,
+		"owed0": pos.TokensOwed0,
+		"owed1": pos.TokensOwed1,
+	} {
+		fieldKey := makeStorageKey(positionPrefix, append(positionKey[:], []byte(field)...))
+		var valueHash common.Hash
+		value.FillBytes(valueHash[:])
+		stateDB.SetState(poolManagerAddr, fieldKey, valueHash)
+	}
 }
 
 // =========================================================================
@@ -703,113 +976,17 @@ func (pm *PoolManager) areCurrenciesSorted(c0, c1 Currency) bool {
 	return bytes.Compare(c0.Address.Bytes(), c1.Address.Bytes()) < 0
 }
 
-// sqrtPriceX96ToTick converts sqrt price to tick using binary search
-// tick = floor(log_1.0001(price))
-// price = sqrtPriceX96^2 / 2^192
+// sqrtPriceX96ToTick converts sqrt price to tick, clamping to MaxTick at
+// MaxSqrtRatio, which Initialize accepts but GetTickAtSqrtRatio does not
 func (pm *PoolManager) sqrtPriceX96ToTick(sqrtPriceX96 *big.Int) int24 {
-	if sqrtPriceX96 == nil || sqrtPriceX96.Sign() <= 0 {
-		return 0
-	}
-
-	// Clamp to valid range
-	if sqrtPriceX96.Cmp(MinSqrtRatio) <= 0 {
-		return MinTick
-	}
 	if sqrtPriceX96.Cmp(MaxSqrtRatio) >= 0 {
 		return MaxTick
 	}
-
-	// Binary search for tick
-	// tickToSqrtPrice(tick) <= sqrtPriceX96 < tickToSqrtPrice(tick+1)
-	low := int24(MinTick)
-	high := int24(MaxTick)
-
-	for low < high {
-		mid := low + (high-low+1)/2
-		sqrtPriceMid := pm.tickToSqrtPriceX96(mid)
-
-		if sqrtPriceMid.Cmp(sqrtPriceX96) <= 0 {
-			low = mid
-		} else {
-			high = mid - 1
-		}
-	}
-
-	return low
-}
-
-// tickToSqrtPriceX96 converts tick to sqrt price (Q64.96 format)
-// sqrtPrice = sqrt(1.0001^tick) * 2^96
-func (pm *PoolManager) tickToSqrtPriceX96(tick int24) *big.Int {
-	// For tick 0: sqrtPrice = 2^96
-	if tick == 0 {
-		return new(big.Int).Set(Q96)
-	}
-
-	// Use lookup table approach for efficiency
-	// sqrt(1.0001) = 1.00004999875 (approximately)
-	// We compute sqrt(1.0001^|tick|) and adjust if negative
-
-	absTick := tick
-	if tick < 0 {
-		absTick = -tick
-	}
-
-	// Start with 1.0 in Q128 format for precision
-	ratio := new(big.Int).Lsh(big.NewInt(1), 128)
-
-	// Magic numbers from Uniswap v3 TickMath
-	// These are sqrt(1.0001^(2^i)) in Q128 format
-	sqrtMagics := []struct {
-		bit   int
-		magic *big.Int
-	}{
-		{0, new(big.Int).SetBytes([]byte{0xff, 0xf9, 0x71, 0x63, 0xe1, 0x37, 0x66, 0x35})}, // 2^0
-		{1, new(big.Int).SetBytes([]byte{0xff, 0xf2, 0xe5, 0x0f, 0x62, 0x6c, 0x4c, 0x95})}, // 2^1
-		{2, new(big.Int).SetBytes([]byte{0xff, 0xe5, 0xca, 0xca, 0x7e, 0x10, 0xe4, 0x46})}, // 2^2
-		{3, new(big.Int).SetBytes([]byte{0xff, 0xcb, 0x9a, 0x97, 0x93, 0x42, 0xa9, 0x50})}, // 2^3
-		{4, new(big.Int).SetBytes([]byte{0xff, 0x97, 0x38, 0x3c, 0x7e, 0x70, 0x01, 0x2a})}, // 2^4
-		{5, new(big.Int).SetBytes([]byte{0xff, 0x2e, 0xa1, 0x34, 0x34, 0xc3, 0x39, 0x69})}, // 2^5
-		{6, new(big.Int).SetBytes([]byte{0xfe, 0x5d, 0xee, 0x04, 0x6a, 0x99, 0xa1, 0x2d})}, // 2^6
-		{7, new(big.Int).SetBytes([]byte{0xfc, 0xbe, 0x86, 0xc7, 0x90, 0x67, 0x90, 0x01})}, // 2^7
-		{8, new(big.Int).SetBytes([]byte{0xf9, 0x87, 0xa7, 0x25, 0x30, 0x42, 0x46, 0x85})}, // 2^8
-	}
-
-	// Multiply by relevant factors
-	for _, sm := range sqrtMagics {
-		if int(absTick)&(1<<sm.bit) != 0 {
-			ratio.Mul(ratio, sm.magic)
-			ratio.Rsh(ratio, 64)
-		}
-	}
-
-	// Handle remaining bits for larger ticks (simplified)
-	remaining := int(absTick) >> 9
-	for i := 0; i < remaining; i++ {
-		// Approximate multiplication by sqrt(1.0001^512)
-		ratio.Mul(ratio, big.NewInt(10001))
-		ratio.Div(ratio, big.NewInt(10000))
-	}
-
-	// If negative tick, invert the ratio
-	if tick < 0 {
-		// ratio = 2^256 / ratio (approximately)
-		maxU256 := new(big.Int).Lsh(big.NewInt(1), 256)
-		ratio = new(big.Int).Div(maxU256, ratio)
-	}
-
-	// Convert from Q128 to Q96
-	result := new(big.Int).Rsh(ratio, 32)
-
-	// Ensure within bounds
-	if result.Cmp(MinSqrtRatio) < 0 {
-		return new(big.Int).Set(MinSqrtRatio)
-	}
-	if result.Cmp(MaxSqrtRatio) > 0 {
-		return new(big.Int).Set(MaxSqrtRatio)
+	tick, err := GetTickAtSqrtRatio(sqrtPriceX96)
+	if err != nil {
+		return MinTick
 	}
-
-	return result
+	return tick
 }
 
 // executeSwap performs the swap math
@@ -823,33 +1000,28 @@ func (pm *PoolManager) executeSwap(pool *Pool, key PoolKey, params SwapParams) (
 
 	exactInput := params.AmountSpecified.Sign() > 0
 
-	var amount0, amount1 *big.Int
-
-	if params.ZeroForOne {
-		// Swapping token0 for token1
-		if exactInput {
-			amount0 = params.AmountSpecified
-			// Calculate output based on price and liquidity
-			amount1 = pm.calculateSwapOutput(pool, amount0, true)
-		} else {
-			amount1 = new(big.Int).Neg(params.AmountSpecified)
-			amount0 = pm.calculateSwapInput(pool, amount1, true)
-		}
+	// The swapper pays the fee in the input currency: an exact-input swap
+	// prices only what is left of the input after the fee, an exact-output
+	// swap adds the fee on top of the input the output requires
+	var amountIn, amountOut, feeAmount *big.Int
+	if exactInput {
+		amountIn = params.AmountSpecified
+		feeAmount = pm.calculateSwapFee(amountIn, key.Fee)
+		amountOut = pm.calculateSwapOutput(pool, new(big.Int).Sub(amountIn, feeAmount), params.ZeroForOne)
 	} else {
-		// Swapping token1 for token0
-		if exactInput {
-			amount1 = params.AmountSpecified
-			amount0 = pm.calculateSwapOutput(pool, amount1, false)
-		} else {
-			amount0 = new(big.Int).Neg(params.AmountSpecified)
-			amount1 = pm.calculateSwapInput(pool, amount0, false)
-		}
+		amountOut = new(big.Int).Neg(params.AmountSpecified)
+		required := pm.calculateSwapInput(pool, amountOut, params.ZeroForOne)
+		feeAmount = pm.calculateSwapFeeOnTop(required, key.Fee)
+		amountIn = new(big.Int).Add(required, feeAmount)
 	}
 
-	// Apply fee
-	fee := pm.calculateSwapFee(amount0, amount1, key.Fee)
-	_ = fee // Fee would be distributed to LPs
+	// Split the fee between LPs and the protocol
+	pm.accrueSwapFee(pool, params.ZeroForOne, feeAmount)
 
+	amount0, amount1 := amountIn, amountOut
+	if !params.ZeroForOne {
+		amount0, amount1 = amountOut, amountIn
+	}
 	return NewBalanceDelta(amount0, new(big.Int).Neg(amount1)), pool.Tick, nil
 }
 
@@ -881,18 +1053,61 @@ func (pm *PoolManager) calculateSwapInput(pool *Pool, amountOut *big.Int, zeroFo
 	return new(big.Int).Div(numerator, denominator)
 }
 
-// calculateSwapFee calculates the fee for a swap
-func (pm *PoolManager) calculateSwapFee(amount0, amount1 *big.Int, fee uint24) *big.Int {
-	// Fee = max(|amount0|, |amount1|) * fee / 1_000_000
-	amount := amount0
-	if amount1.CmpAbs(amount0) > 0 {
-		amount = amount1
-	}
-	absAmount := new(big.Int).Abs(amount)
-	feeAmount := new(big.Int).Mul(absAmount, big.NewInt(int64(fee)))
+// calculateSwapFee calculates the fee charged on a swap's input amount
+func (pm *PoolManager) calculateSwapFee(amountIn *big.Int, fee uint24) *big.Int {
+	feeAmount := new(big.Int).Abs(amountIn)
+	feeAmount.Mul(feeAmount, big.NewInt(int64(fee)))
 	return feeAmount.Div(feeAmount, big.NewInt(1_000_000))
 }
 
+// calculateSwapFeeOnTop calculates the fee to add to a net input so that the
+// fee is the pool's fee rate of the gross input, rounding up in favour of
+// the pool
+func (pm *PoolManager) calculateSwapFeeOnTop(amountIn *big.Int, fee uint24) *big.Int {
+	feeAmount := new(big.Int).Abs(amountIn)
+	feeAmount.Mul(feeAmount, big.NewInt(int64(fee)))
+	denominator := big.NewInt(int64(1_000_000 - uint64(fee)))
+	feeAmount.Add(feeAmount, new(big.Int).Sub(denominator, big.NewInt(1)))
+	return feeAmount.Div(feeAmount, denominator)
+}
+
+// accrueSwapFee credits the protocol its share of a swap fee and the rest to
+// LPs through fee growth. Without in-range liquidity there are no LPs to
+// credit, so the whole fee goes to the protocol.
+func (pm *PoolManager) accrueSwapFee(pool *Pool, zeroForOne bool, fee *big.Int) {
+	if fee.Sign() == 0 {
+		return
+	}
+
+	protocolFee := new(big.Int).Mul(fee, big.NewInt(int64(pool.ProtocolFee)))
+	protocolFee.Div(protocolFee, big.NewInt(1_000_000))
+	lpFee := new(big.Int).Sub(fee, protocolFee)
+	if pool.Liquidity.Sign() <= 0 {
+		protocolFee, lpFee = fee, big.NewInt(0)
+	}
+
+	// feeGrowth += lpFee * 2^128 / liquidity
+	var growth *big.Int
+	if lpFee.Sign() > 0 {
+		growth = new(big.Int).Mul(lpFee, Q128)
+		growth.Div(growth, pool.Liquidity)
+	}
+
+	if zeroForOne {
+		pool.ProtocolFees0 = new(big.Int).Add(pool.ProtocolFees0, protocolFee)
+		pool.LPFees0 = new(big.Int).Add(pool.LPFees0, lpFee)
+		if growth != nil {
+			pool.FeeGrowth0X128 = new(big.Int).Add(pool.FeeGrowth0X128, growth)
+		}
+	} else {
+		pool.ProtocolFees1 = new(big.Int).Add(pool.ProtocolFees1, protocolFee)
+		pool.LPFees1 = new(big.Int).Add(pool.LPFees1, lpFee)
+		if growth != nil {
+			pool.FeeGrowth1X128 = new(big.Int).Add(pool.FeeGrowth1X128, growth)
+		}
+	}
+}
+
 // calculateLiquidityAmounts calculates token amounts for liquidity change
 func (pm *PoolManager) calculateLiquidityAmounts(
 	pool *Pool,
@@ -968,9 +1183,14 @@ func (pm *PoolManager) executeCallback(stateDB StateDB, caller common.Address, d
 
 // callHook calls a hook function (simplified)
 func (pm *PoolManager) callHook(stateDB StateDB, hookAddr common.Address, flag HookFlags, args ...interface{}) error {
-	// In real implementation, this would be an EVM call to hook contract
-	// For now, just return success
-	return nil
+	// Skip callbacks the hook did not declare
+	if !pm.hooks.IsHookEnabled(hookAddr, flag) {
+		return nil
+	}
+	if pm.hookInvoker == nil {
+		return nil
+	}
+	return pm.hookInvoker(stateDB, hookAddr, flag, args...)
 }
 
 // =========================================================================
@@ -989,6 +1209,23 @@ func (pm *PoolManager) GetPool(stateDB StateDB, key PoolKey) (*Pool, error) {
 	return pool, nil
 }
 
+// GetFeeBreakdown returns the swap fees a pool has charged since creation,
+// split per currency between LPs and the protocol. Donations are not swap
+// fees and are not included.
+func (pm *PoolManager) GetFeeBreakdown(stateDB StateDB, key PoolKey) (*FeeBreakdown, error) {
+	pool := pm.getPool(stateDB, key.ID())
+	if !pool.IsInitialized() {
+		return nil, ErrPoolNotInitialized
+	}
+
+	return &FeeBreakdown{
+		LPFees0:       new(big.Int).Set(pool.LPFees0),
+		LPFees1:       new(big.Int).Set(pool.LPFees1),
+		ProtocolFees0: new(big.Int).Set(pool.ProtocolFees0),
+		ProtocolFees1: new(big.Int).Set(pool.ProtocolFees1),
+	}, nil
+}
+
 // GetPosition returns a liquidity position
 func (pm *PoolManager) GetPosition(
 	stateDB StateDB,
@@ -1002,6 +1239,146 @@ func (pm *PoolManager) GetPosition(
 	return pos, nil
 }
 
+// GetPositionInfo returns the owner, range, liquidity and owed fees of the
+// position at positionKey
+func (pm *PoolManager) GetPositionInfo(stateDB StateDB, positionKey [32]byte) (*PositionInfo, error) {
+	pos := pm.getPosition(stateDB, positionKey)
+	if pos.Owner == (common.Address{}) {
+		return nil, ErrPositionNotFound
+	}
+
+	return &PositionInfo{
+		PositionKey: positionKey,
+		PoolID:      pm.getPositionPool(stateDB, positionKey),
+		Owner:       pos.Owner,
+		TickLower:   pos.TickLower,
+		TickUpper:   pos.TickUpper,
+		Liquidity:   new(big.Int).Set(pos.Liquidity),
+		TokensOwed0: new(big.Int).Set(pos.TokensOwed0),
+		TokensOwed1: new(big.Int).Set(pos.TokensOwed1),
+	}, nil
+}
+
+// GetPositionsByOwner returns every open position owner holds, across all
+// pools, ordered by position key. A position is open while it has
+// liquidity or owed fees.
+func (pm *PoolManager) GetPositionsByOwner(stateDB StateDB, owner common.Address) []PositionInfo {
+	type entry struct{ poolId, positionKey [32]byte }
+	count := pm.ownerIndexCount(stateDB, owner)
+	entries := make([]entry, 0, count)
+	for i := uint64(0); i < count; i++ {
+		poolId, positionKey := pm.ownerIndexEntry(stateDB, owner, i)
+		entries = append(entries, entry{poolId, positionKey})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if c := bytes.Compare(entries[i].positionKey[:], entries[j].positionKey[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(entries[i].poolId[:], entries[j].poolId[:]) < 0
+	})
+
+	var result []PositionInfo
+	for _, e := range entries {
+		info, err := pm.GetPositionInfo(stateDB, e.positionKey)
+		if err != nil || info.Owner != owner || info.PoolID != e.poolId {
+			continue
+		}
+		if info.Liquidity.Sign() == 0 && info.TokensOwed0.Sign() == 0 && info.TokensOwed1.Sign() == 0 {
+			continue
+		}
+		result = append(result, *info)
+	}
+	return result
+}
+
+// The owner index is an enumerable set per owner kept in state, so it
+// survives reloads and is rolled back with the transaction that changed it.
+// Entries are (pool ID, position key) pairs:
+//
+//	owner || "n"                        -> entry count
+//	owner || "e" || i || "pool"/"posn"  -> entry i
+//	owner || poolId || positionKey      -> i + 1, or 0 if absent
+
+// indexPosition adds the position to owner's index if it is not there yet
+func (pm *PoolManager) indexPosition(stateDB StateDB, owner common.Address, poolId, positionKey [32]byte) {
+	slotKey := ownerIndexSlotKey(owner, poolId, positionKey)
+	if stateDB.GetState(poolManagerAddr, slotKey) != (common.Hash{}) {
+		return
+	}
+	count := pm.ownerIndexCount(stateDB, owner)
+	pm.setOwnerIndexEntry(stateDB, owner, count, poolId, positionKey)
+	stateDB.SetState(poolManagerAddr, slotKey, uint64Hash(count+1))
+	pm.setOwnerIndexCount(stateDB, owner, count+1)
+}
+
+// unindexPosition removes the position from owner's index, moving the last
+// entry into its place
+func (pm *PoolManager) unindexPosition(stateDB StateDB, owner common.Address, poolId, positionKey [32]byte) {
+	slotKey := ownerIndexSlotKey(owner, poolId, positionKey)
+	slot := stateDB.GetState(poolManagerAddr, slotKey)
+	if slot == (common.Hash{}) {
+		return
+	}
+	index := binary.BigEndian.Uint64(slot[24:]) - 1
+	last := pm.ownerIndexCount(stateDB, owner) - 1
+	if index != last {
+		lastPool, lastKey := pm.ownerIndexEntry(stateDB, owner, last)
+		pm.setOwnerIndexEntry(stateDB, owner, index, lastPool, lastKey)
+		stateDB.SetState(poolManagerAddr, ownerIndexSlotKey(owner, lastPool, lastKey), uint64Hash(index+1))
+	}
+	pm.setOwnerIndexEntry(stateDB, owner, last, [32]byte{}, [32]byte{})
+	stateDB.SetState(poolManagerAddr, slotKey, common.Hash{})
+	pm.setOwnerIndexCount(stateDB, owner, last)
+}
+
+func (pm *PoolManager) ownerIndexCount(stateDB StateDB, owner common.Address) uint64 {
+	slot := stateDB.GetState(poolManagerAddr, makeStorageKey(ownerIndexPrefix, append(owner.Bytes(), 'n')))
+	return binary.BigEndian.Uint64(slot[24:])
+}
+
+func (pm *PoolManager) setOwnerIndexCount(stateDB StateDB, owner common.Address, count uint64) {
+	stateDB.SetState(poolManagerAddr, makeStorageKey(ownerIndexPrefix, append(owner.Bytes(), 'n')), uint64Hash(count))
+}
+
+func (pm *PoolManager) ownerIndexEntry(stateDB StateDB, owner common.Address, index uint64) ([32]byte, [32]byte) {
+	poolId := stateDB.GetState(poolManagerAddr, ownerIndexEntryKey(owner, index, "pool"))
+	positionKey := stateDB.GetState(poolManagerAddr, ownerIndexEntryKey(owner, index, "posn"))
+	return poolId, positionKey
+}
+
+func (pm *PoolManager) setOwnerIndexEntry(stateDB StateDB, owner common.Address, index uint64, poolId, positionKey [32]byte) {
+	stateDB.SetState(poolManagerAddr, ownerIndexEntryKey(owner, index, "pool"), poolId)
+	stateDB.SetState(poolManagerAddr, ownerIndexEntryKey(owner, index, "posn"), positionKey)
+}
+
+func ownerIndexEntryKey(owner common.Address, index uint64, field string) common.Hash {
+	id := append(owner.Bytes(), 'e')
+	id = binary.BigEndian.AppendUint64(id, index)
+	return makeStorageKey(ownerIndexPrefix, append(id, field...))
+}
+
+func ownerIndexSlotKey(owner common.Address, poolId, positionKey [32]byte) common.Hash {
+	id := append(owner.Bytes(), poolId[:]...)
+	return makeStorageKey(ownerIndexPrefix, append(id, positionKey[:]...))
+}
+
+// getPositionPool returns the ID of the pool the position was opened in
+func (pm *PoolManager) getPositionPool(stateDB StateDB, positionKey [32]byte) [32]byte {
+	return stateDB.GetState(poolManagerAddr, makeStorageKey(positionPrefix, append(positionKey[:], []byte("pool")...)))
+}
+
+// setPositionPool records the ID of the pool the position was opened in
+func (pm *PoolManager) setPositionPool(stateDB StateDB, positionKey, poolId [32]byte) {
+	stateDB.SetState(poolManagerAddr, makeStorageKey(positionPrefix, append(positionKey[:], []byte("pool")...)), poolId)
+}
+
+// uint64Hash right-aligns v in a storage word
+func uint64Hash(v uint64) common.Hash {
+	var hash common.Hash
+	binary.BigEndian.PutUint64(hash[24:], v)
+	return hash
+}
+
 // GetDelta returns the current delta for a currency
 func (pm *PoolManager) GetDelta(locker common.Address, currency Currency) *big.Int {
 	deltas, ok := pm.currentDeltas[locker]
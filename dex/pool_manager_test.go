@@ -4,6 +4,7 @@
 package dex
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
@@ -146,6 +147,101 @@ func TestPoolManagerInitializeAlreadyInitialized(t *testing.T) {
 	}
 }
 
+func TestPoolManagerInitializeFeeSpacing(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(1), 96)
+
+	tiers := SupportedFeeTiers()
+	if len(tiers) != 4 {
+		t.Fatalf("SupportedFeeTiers() returned %d tiers, want 4", len(tiers))
+	}
+	for _, tier := range tiers {
+		key := newTestPoolKey()
+		key.Fee = tier.Fee
+		key.TickSpacing = tier.TickSpacing
+		if _, err := pm.Initialize(stateDB, key, sqrtPriceX96, nil); err != nil {
+			t.Errorf("Initialize(fee %d, spacing %d) failed: %v", tier.Fee, tier.TickSpacing, err)
+		}
+	}
+
+	// The standard fee with the stable spacing is not a supported pair
+	key := newTestPoolKey()
+	key.TickSpacing = TickSpacing005
+	if _, err := pm.Initialize(stateDB, key, sqrtPriceX96, nil); err != ErrInvalidTickSpacing {
+		t.Errorf("Expected ErrInvalidTickSpacing, got: %v", err)
+	}
+
+	// Mutating the returned slice does not change the supported pairs
+	tiers[0].TickSpacing = TickSpacing030
+	if SupportedFeeTiers()[0].TickSpacing != TickSpacing001 {
+		t.Error("SupportedFeeTiers exposed its backing table")
+	}
+}
+
+func TestPoolManagerCustomTickSpacing(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(1), 96)
+
+	pm.lockers = append(pm.lockers, caller)
+	pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+
+	// A tight stable pair and a wide volatile pair, at fees outside the tiers
+	for _, custom := range []struct {
+		fee     uint24
+		spacing int24
+	}{
+		{fee: 200, spacing: 2},
+		{fee: 20000, spacing: 500},
+	} {
+		key := newTestPoolKey()
+		key.Fee = custom.fee
+		key.TickSpacing = custom.spacing
+		if _, err := pm.Initialize(stateDB, key, sqrtPriceX96, nil); err != nil {
+			t.Fatalf("Initialize(spacing %d) failed: %v", custom.spacing, err)
+		}
+
+		addLiquidity := func(tickLower, tickUpper int24) error {
+			params := ModifyLiquidityParams{
+				TickLower:      tickLower,
+				TickUpper:      tickUpper,
+				LiquidityDelta: big.NewInt(1000000),
+			}
+			_, _, err := pm.ModifyLiquidity(stateDB, key, params, nil)
+			return err
+		}
+
+		s := custom.spacing
+		if err := addLiquidity(-3*s, 5*s); err != nil {
+			t.Errorf("spacing %d: aligned position failed: %v", s, err)
+		}
+		if err := addLiquidity(MinUsableTick(s), MaxUsableTick(s)); err != nil {
+			t.Errorf("spacing %d: full range position failed: %v", s, err)
+		}
+		if MinUsableTick(s) < MinTick || MinUsableTick(s)%s != 0 || MaxUsableTick(s) > MaxTick || MaxUsableTick(s)%s != 0 {
+			t.Errorf("spacing %d: usable ticks %d..%d not aligned within bounds", s, MinUsableTick(s), MaxUsableTick(s))
+		}
+		if err := addLiquidity(-3*s, 5*s+1); err != ErrTickMisaligned {
+			t.Errorf("spacing %d: expected ErrTickMisaligned for upper tick, got: %v", s, err)
+		}
+		if err := addLiquidity(-3*s-1, 5*s); err != ErrTickMisaligned {
+			t.Errorf("spacing %d: expected ErrTickMisaligned for lower tick, got: %v", s, err)
+		}
+	}
+
+	// Custom spacings must be within bounds
+	for _, spacing := range []int24{0, -60, MaxTickSpacing + 1} {
+		key := newTestPoolKey()
+		key.Fee = 2500
+		key.TickSpacing = spacing
+		if _, err := pm.Initialize(stateDB, key, sqrtPriceX96, nil); err != ErrInvalidTickSpacing {
+			t.Errorf("spacing %d: expected ErrInvalidTickSpacing, got: %v", spacing, err)
+		}
+	}
+}
+
 func TestPoolManagerInitializeUnsortedCurrencies(t *testing.T) {
 	pm := newTestPoolManager()
 	stateDB := NewMockStateDB()
@@ -179,6 +275,87 @@ func TestPoolManagerInitializeInvalidSqrtPrice(t *testing.T) {
 	}
 }
 
+// TestPoolManagerInitializeEquivalence checks that initializing by sqrt
+// price, tick and price lands every pool on the same sqrt price and tick
+func TestPoolManagerInitializeEquivalence(t *testing.T) {
+	for _, tick := range []int24{MinTick, -276324, -60, -1, 0, 1, 60, 200000, MaxTick - 1} {
+		pm := newTestPoolManager()
+		stateDB := NewMockStateDB()
+
+		sqrtPriceX96, err := GetSqrtRatioAtTick(tick)
+		if err != nil {
+			t.Fatalf("GetSqrtRatioAtTick(%d) failed: %v", tick, err)
+		}
+		// The exact price whose square root is sqrtPriceX96 / 2^96
+		price := new(big.Rat).SetFrac(
+			new(big.Int).Mul(sqrtPriceX96, sqrtPriceX96),
+			new(big.Int).Lsh(big.NewInt(1), 192),
+		)
+
+		keys := make([]PoolKey, 3)
+		for i, tier := range SupportedFeeTiers()[1:] {
+			keys[i] = newTestPoolKey()
+			keys[i].Fee = tier.Fee
+			keys[i].TickSpacing = tier.TickSpacing
+		}
+
+		gotTicks := make([]int24, 3)
+		if gotTicks[0], err = pm.Initialize(stateDB, keys[0], sqrtPriceX96, nil); err != nil {
+			t.Fatalf("Initialize at tick %d failed: %v", tick, err)
+		}
+		if gotTicks[1], err = pm.InitializeFromTick(stateDB, keys[1], tick, nil); err != nil {
+			t.Fatalf("InitializeFromTick(%d) failed: %v", tick, err)
+		}
+		if gotTicks[2], err = pm.InitializeFromPrice(stateDB, keys[2], price, nil); err != nil {
+			t.Fatalf("InitializeFromPrice at tick %d failed: %v", tick, err)
+		}
+
+		for i, key := range keys {
+			if gotTicks[i] != tick {
+				t.Errorf("path %d: tick = %d, want %d", i, gotTicks[i], tick)
+			}
+			pool, err := pm.GetPool(stateDB, key)
+			if err != nil {
+				t.Fatalf("GetPool failed: %v", err)
+			}
+			if pool.SqrtPriceX96.Cmp(sqrtPriceX96) != 0 || pool.Tick != tick {
+				t.Errorf("path %d at tick %d: pool at (%s, %d), want (%s, %d)",
+					i, tick, pool.SqrtPriceX96, pool.Tick, sqrtPriceX96, tick)
+			}
+		}
+	}
+}
+
+func TestPoolManagerInitializeFromPrice(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	key := newTestPoolKey()
+
+	// 1 LUX = 2500 token1: sqrt(2500) = 50
+	tick, err := pm.InitializeFromPrice(stateDB, key, big.NewRat(2500, 1), nil)
+	if err != nil {
+		t.Fatalf("InitializeFromPrice failed: %v", err)
+	}
+	pool, _ := pm.GetPool(stateDB, key)
+	want := new(big.Int).Mul(big.NewInt(50), Q96)
+	if pool.SqrtPriceX96.Cmp(want) != 0 {
+		t.Errorf("SqrtPriceX96 = %s, want %s", pool.SqrtPriceX96, want)
+	}
+	// 1.0001^78244 <= 2500 < 1.0001^78245
+	if tick != 78244 {
+		t.Errorf("tick = %d, want 78244", tick)
+	}
+
+	key.Fee = Fee005
+	key.TickSpacing = TickSpacing005
+	if _, err := pm.InitializeFromPrice(stateDB, key, new(big.Rat), nil); err != ErrInvalidSqrtPrice {
+		t.Errorf("Expected ErrInvalidSqrtPrice for zero price, got: %v", err)
+	}
+	if _, err := pm.InitializeFromTick(stateDB, key, MaxTick+1, nil); err != ErrTickOutOfRange {
+		t.Errorf("Expected ErrTickOutOfRange, got: %v", err)
+	}
+}
+
 // =========================================================================
 // Flash Accounting Tests
 // =========================================================================
@@ -323,6 +500,156 @@ func TestPoolManagerSwapUninitializedPool(t *testing.T) {
 	}
 }
 
+func TestPoolManagerFeeBreakdown(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	key := newTestPoolKey()
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	controller := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	pm.protocolFeeController = controller
+
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	if _, err := pm.Initialize(stateDB, key, sqrtPriceX96, nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	pm.pools[key.ID()].Liquidity = big.NewInt(1000000000)
+
+	if err := pm.SetProtocolFee(stateDB, caller, key, 100000); err != ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized, got: %v", err)
+	}
+	if err := pm.SetProtocolFee(stateDB, controller, key, MaxProtocolFee+1); err != ErrInvalidFee {
+		t.Errorf("Expected ErrInvalidFee, got: %v", err)
+	}
+	// A sixth of every swap fee goes to the protocol
+	if err := pm.SetProtocolFee(stateDB, controller, key, 166666); err != nil {
+		t.Fatalf("SetProtocolFee failed: %v", err)
+	}
+
+	pm.lockers = append(pm.lockers, caller)
+	pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+
+	swaps := []SwapParams{
+		{ZeroForOne: true, AmountSpecified: big.NewInt(1_000_000)},
+		{ZeroForOne: false, AmountSpecified: big.NewInt(333_333)},
+		{ZeroForOne: true, AmountSpecified: big.NewInt(-250_000)}, // Exact output
+		{ZeroForOne: false, AmountSpecified: big.NewInt(7_777_777)},
+		{ZeroForOne: true, AmountSpecified: big.NewInt(12_345)},
+	}
+
+	totalFees0, totalFees1 := big.NewInt(0), big.NewInt(0)
+	for i, params := range swaps {
+		delta, err := pm.Swap(stateDB, key, params, nil)
+		if err != nil {
+			t.Fatalf("Swap %d failed: %v", i, err)
+		}
+
+		// Fees are charged on the input currency
+		amountIn, amountOut, total := delta.Amount1, delta.Amount0, totalFees1
+		if params.ZeroForOne {
+			amountIn, amountOut, total = delta.Amount0, delta.Amount1, totalFees0
+		}
+		var fee *big.Int
+		if params.AmountSpecified.Sign() > 0 {
+			fee = pm.calculateSwapFee(amountIn, key.Fee)
+		} else {
+			required := pm.calculateSwapInput(pm.pools[key.ID()], new(big.Int).Abs(amountOut), params.ZeroForOne)
+			fee = new(big.Int).Sub(new(big.Int).Abs(amountIn), required)
+		}
+		total.Add(total, fee)
+	}
+
+	breakdown, err := pm.GetFeeBreakdown(stateDB, key)
+	if err != nil {
+		t.Fatalf("GetFeeBreakdown failed: %v", err)
+	}
+
+	if got := new(big.Int).Add(breakdown.LPFees0, breakdown.ProtocolFees0); got.Cmp(totalFees0) != 0 {
+		t.Errorf("currency0 fees: LP %s + protocol %s = %s, want %s",
+			breakdown.LPFees0, breakdown.ProtocolFees0, got, totalFees0)
+	}
+	if got := new(big.Int).Add(breakdown.LPFees1, breakdown.ProtocolFees1); got.Cmp(totalFees1) != 0 {
+		t.Errorf("currency1 fees: LP %s + protocol %s = %s, want %s",
+			breakdown.LPFees1, breakdown.ProtocolFees1, got, totalFees1)
+	}
+	if breakdown.ProtocolFees0.Sign() == 0 || breakdown.ProtocolFees1.Sign() == 0 {
+		t.Error("Expected protocol fees in both currencies")
+	}
+	if breakdown.LPFees0.Cmp(breakdown.ProtocolFees0) <= 0 || breakdown.LPFees1.Cmp(breakdown.ProtocolFees1) <= 0 {
+		t.Error("Expected LPs to receive the larger share")
+	}
+
+	// The breakdown survives a reload from state
+	pm.pools = make(map[[32]byte]*Pool)
+	reloaded, err := pm.GetFeeBreakdown(stateDB, key)
+	if err != nil {
+		t.Fatalf("GetFeeBreakdown after reload failed: %v", err)
+	}
+	if reloaded.LPFees0.Cmp(breakdown.LPFees0) != 0 || reloaded.ProtocolFees1.Cmp(breakdown.ProtocolFees1) != 0 {
+		t.Error("Fee breakdown changed after reload")
+	}
+
+	// Uninitialized pool
+	other := key
+	other.Fee = Fee100
+	if _, err := pm.GetFeeBreakdown(stateDB, other); err != ErrPoolNotInitialized {
+		t.Errorf("Expected ErrPoolNotInitialized, got: %v", err)
+	}
+}
+
+func TestPoolManagerSwapperPaysFee(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	key := newTestPoolKey()
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	if _, err := pm.Initialize(stateDB, key, sqrtPriceX96, nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	pool := pm.pools[key.ID()]
+	pool.Liquidity = big.NewInt(1000000000)
+
+	pm.lockers = append(pm.lockers, caller)
+	pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+
+	// Exact input: only the input net of the fee is priced
+	amountIn := big.NewInt(1_000_000)
+	fee := pm.calculateSwapFee(amountIn, key.Fee)
+	delta, err := pm.Swap(stateDB, key, SwapParams{ZeroForOne: true, AmountSpecified: amountIn}, nil)
+	if err != nil {
+		t.Fatalf("Exact-input swap failed: %v", err)
+	}
+	if delta.Amount0.Cmp(amountIn) != 0 {
+		t.Errorf("Exact-input swap took %s, expected %s", delta.Amount0, amountIn)
+	}
+	wantOut := pm.calculateSwapOutput(pool, new(big.Int).Sub(amountIn, fee), true)
+	if got := new(big.Int).Neg(delta.Amount1); got.Cmp(wantOut) != 0 {
+		t.Errorf("Exact-input swap paid out %s, expected %s", got, wantOut)
+	}
+	if gross := pm.calculateSwapOutput(pool, amountIn, true); wantOut.Cmp(gross) >= 0 {
+		t.Errorf("Fee did not reduce output: %s >= %s", wantOut, gross)
+	}
+
+	// Exact output: the fee is added on top of the required input, and is
+	// the fee rate of the gross input
+	amountOut := big.NewInt(250_000)
+	required := pm.calculateSwapInput(pool, amountOut, true)
+	delta, err = pm.Swap(stateDB, key, SwapParams{ZeroForOne: true, AmountSpecified: new(big.Int).Neg(amountOut)}, nil)
+	if err != nil {
+		t.Fatalf("Exact-output swap failed: %v", err)
+	}
+	if got := new(big.Int).Neg(delta.Amount1); got.Cmp(amountOut) != 0 {
+		t.Errorf("Exact-output swap paid out %s, expected %s", got, amountOut)
+	}
+	fee = new(big.Int).Sub(delta.Amount0, required)
+	if fee.Sign() <= 0 {
+		t.Fatalf("Exact-output swap charged no fee: input %s, required %s", delta.Amount0, required)
+	}
+	if floor := pm.calculateSwapFee(delta.Amount0, key.Fee); fee.Cmp(floor) < 0 || new(big.Int).Sub(fee, floor).Cmp(big.NewInt(1)) > 0 {
+		t.Errorf("Exact-output fee %s is not the fee rate of gross input %s (%s)", fee, delta.Amount0, floor)
+	}
+}
+
 // =========================================================================
 // Liquidity Tests
 // =========================================================================
@@ -346,8 +673,8 @@ func TestPoolManagerModifyLiquidity(t *testing.T) {
 
 	// Add liquidity
 	params := ModifyLiquidityParams{
-		TickLower:      -1000,
-		TickUpper:      1000,
+		TickLower:      -960,
+		TickUpper:      960,
 		LiquidityDelta: big.NewInt(1000000),
 		Salt:           [32]byte{},
 	}
@@ -371,6 +698,171 @@ func TestPoolManagerModifyLiquidity(t *testing.T) {
 	}
 }
 
+func TestPoolManagerGetPositionsByOwner(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	alice := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bob := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	keyA := newTestPoolKey()
+	keyB := newTestPoolKey()
+	keyB.Currency1 = Currency{Address: common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcdefabcd")}
+	for _, key := range []PoolKey{keyA, keyB} {
+		if _, err := pm.Initialize(stateDB, key, new(big.Int).Set(Q96), nil); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+	}
+
+	pm.lockers = append(pm.lockers, alice)
+	pm.currentDeltas[alice] = make(map[Currency]*big.Int)
+
+	paramsA := ModifyLiquidityParams{TickLower: -600, TickUpper: 600, LiquidityDelta: big.NewInt(1000), Salt: [32]byte{1}}
+	paramsB := ModifyLiquidityParams{TickLower: -1200, TickUpper: 1200, LiquidityDelta: big.NewInt(2000), Salt: [32]byte{2}}
+	if _, _, err := pm.ModifyLiquidity(stateDB, keyA, paramsA, nil); err != nil {
+		t.Fatalf("ModifyLiquidity in pool A failed: %v", err)
+	}
+	if _, _, err := pm.ModifyLiquidity(stateDB, keyB, paramsB, nil); err != nil {
+		t.Fatalf("ModifyLiquidity in pool B failed: %v", err)
+	}
+	posA := PositionKey(alice, paramsA.TickLower, paramsA.TickUpper, paramsA.Salt)
+	posB := PositionKey(alice, paramsB.TickLower, paramsB.TickUpper, paramsB.Salt)
+
+	positions := pm.GetPositionsByOwner(stateDB, alice)
+	if len(positions) != 2 {
+		t.Fatalf("Expected 2 positions, got %d", len(positions))
+	}
+	byPool := map[[32]byte]PositionInfo{}
+	for _, info := range positions {
+		byPool[info.PoolID] = info
+	}
+	if info := byPool[keyA.ID()]; info.PositionKey != posA || info.Liquidity.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("Unexpected pool A position: %x with liquidity %v", info.PositionKey, info.Liquidity)
+	}
+	if info := byPool[keyB.ID()]; info.PositionKey != posB || info.Liquidity.Cmp(big.NewInt(2000)) != 0 {
+		t.Errorf("Unexpected pool B position: %x with liquidity %v", info.PositionKey, info.Liquidity)
+	}
+	if bytes.Compare(positions[0].PositionKey[:], positions[1].PositionKey[:]) >= 0 {
+		t.Error("Positions should be ordered by key")
+	}
+
+	// The index lives in state: a fresh manager over the same state sees it
+	if reloaded := newTestPoolManager().GetPositionsByOwner(stateDB, alice); len(reloaded) != 2 ||
+		reloaded[0].PositionKey != positions[0].PositionKey || reloaded[0].PoolID != positions[0].PoolID {
+		t.Errorf("Expected the same 2 positions after reload, got %d", len(reloaded))
+	}
+
+	// Transferred positions move to the new owner's index
+	if err := pm.TransferPosition(stateDB, posB, bob); err != nil {
+		t.Fatalf("TransferPosition failed: %v", err)
+	}
+	if positions := pm.GetPositionsByOwner(stateDB, alice); len(positions) != 1 || positions[0].PositionKey != posA {
+		t.Errorf("Expected only pool A position for alice, got %d positions", len(positions))
+	}
+	if positions := pm.GetPositionsByOwner(stateDB, bob); len(positions) != 1 || positions[0].PositionKey != posB {
+		t.Errorf("Expected only pool B position for bob, got %d positions", len(positions))
+	}
+	if positions := newTestPoolManager().GetPositionsByOwner(stateDB, bob); len(positions) != 1 || positions[0].PoolID != keyB.ID() {
+		t.Errorf("Expected bob's pool B position after reload, got %d positions", len(positions))
+	}
+	if pm.ownerIndexCount(stateDB, alice) != 1 {
+		t.Errorf("Transfer should remove the entry from alice's index, count %d", pm.ownerIndexCount(stateDB, alice))
+	}
+
+	// Fully withdrawn positions are closed
+	paramsA.LiquidityDelta = big.NewInt(-1000)
+	if _, _, err := pm.ModifyLiquidity(stateDB, keyA, paramsA, nil); err != nil {
+		t.Fatalf("ModifyLiquidity withdraw failed: %v", err)
+	}
+	if positions := pm.GetPositionsByOwner(stateDB, alice); len(positions) != 0 {
+		t.Errorf("Expected no open positions for alice, got %d", len(positions))
+	}
+}
+
+func TestPoolManagerTransferPosition(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	key := newTestPoolKey()
+	alice := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	bob := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	if _, err := pm.Initialize(stateDB, key, new(big.Int).Set(Q96), nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	pm.lockers = append(pm.lockers, alice)
+	pm.currentDeltas[alice] = make(map[Currency]*big.Int)
+
+	params := ModifyLiquidityParams{
+		TickLower:      -600,
+		TickUpper:      600,
+		LiquidityDelta: big.NewInt(1000000),
+		Salt:           [32]byte{7},
+	}
+	if _, _, err := pm.ModifyLiquidity(stateDB, key, params, nil); err != nil {
+		t.Fatalf("ModifyLiquidity failed: %v", err)
+	}
+	positionKey := PositionKey(alice, params.TickLower, params.TickUpper, params.Salt)
+
+	// Fees accrued to the position before the transfer
+	pos := pm.getPosition(stateDB, positionKey)
+	pos.TokensOwed0 = big.NewInt(123)
+	pos.TokensOwed1 = big.NewInt(456)
+	pm.setPosition(stateDB, positionKey, pos)
+
+	if err := pm.TransferPosition(stateDB, positionKey, common.Address{}); err != ErrInvalidParameter {
+		t.Errorf("Expected ErrInvalidParameter for zero owner, got: %v", err)
+	}
+	if err := pm.TransferPosition(stateDB, positionKey, bob); err != nil {
+		t.Fatalf("TransferPosition failed: %v", err)
+	}
+
+	// A fresh manager reads the transferred position back from state
+	reloaded := newTestPoolManager()
+	info, err := reloaded.GetPositionInfo(stateDB, positionKey)
+	if err != nil {
+		t.Fatalf("GetPositionInfo failed: %v", err)
+	}
+	if info.Owner != bob || info.TickLower != -600 || info.TickUpper != 600 {
+		t.Errorf("Unexpected position info: owner %s, range [%d, %d]", info.Owner.Hex(), info.TickLower, info.TickUpper)
+	}
+	if info.Liquidity.Cmp(big.NewInt(1000000)) != 0 {
+		t.Errorf("Expected liquidity 1000000, got: %s", info.Liquidity)
+	}
+	if info.TokensOwed0.Cmp(big.NewInt(123)) != 0 || info.TokensOwed1.Cmp(big.NewInt(456)) != 0 {
+		t.Errorf("Expected owed fees (123, 456), got: (%s, %s)", info.TokensOwed0, info.TokensOwed1)
+	}
+
+	// The old owner can no longer modify or transfer the position
+	params.LiquidityDelta = big.NewInt(-1000)
+	if _, _, err := pm.ModifyLiquidity(stateDB, key, params, nil); err != ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized for old owner, got: %v", err)
+	}
+	if _, _, err := pm.ModifyPosition(stateDB, key, positionKey, big.NewInt(-1000), nil); err != ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized for old owner, got: %v", err)
+	}
+	if err := pm.TransferPosition(stateDB, positionKey, alice); err != ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized for old owner, got: %v", err)
+	}
+
+	// The new owner manages it by key
+	pm.lockers = append(pm.lockers, bob)
+	pm.currentDeltas[bob] = make(map[Currency]*big.Int)
+	if _, _, err := pm.ModifyPosition(stateDB, key, positionKey, big.NewInt(-400000), nil); err != nil {
+		t.Fatalf("ModifyPosition by new owner failed: %v", err)
+	}
+	info, _ = pm.GetPositionInfo(stateDB, positionKey)
+	if info.Liquidity.Cmp(big.NewInt(600000)) != 0 || info.Owner != bob {
+		t.Errorf("Expected 600000 liquidity owned by bob, got %s owned by %s", info.Liquidity, info.Owner.Hex())
+	}
+	if info.TokensOwed0.Cmp(big.NewInt(123)) != 0 {
+		t.Errorf("Expected owed fees preserved, got: %s", info.TokensOwed0)
+	}
+
+	if _, err := pm.GetPositionInfo(stateDB, [32]byte{1}); err != ErrPositionNotFound {
+		t.Errorf("Expected ErrPositionNotFound, got: %v", err)
+	}
+}
+
 func TestPoolManagerModifyLiquidityInvalidTickRange(t *testing.T) {
 	pm := newTestPoolManager()
 	stateDB := NewMockStateDB()
@@ -447,6 +939,106 @@ func TestPoolManagerDonate(t *testing.T) {
 	}
 }
 
+func TestPoolManagerDonateToRange(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	key := newTestPoolKey()
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	// Price sits at tick 0, below the band donated to
+	if _, err := pm.Initialize(stateDB, key, new(big.Int).Set(Q96), nil); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	pm.lockers = append(pm.lockers, caller)
+	pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+
+	addPosition := func(tickLower, tickUpper int24, liquidity int64, salt byte) [32]byte {
+		params := ModifyLiquidityParams{
+			TickLower:      tickLower,
+			TickUpper:      tickUpper,
+			LiquidityDelta: big.NewInt(liquidity),
+			Salt:           [32]byte{salt},
+		}
+		if _, _, err := pm.ModifyLiquidity(stateDB, key, params, nil); err != nil {
+			t.Fatalf("ModifyLiquidity failed: %v", err)
+		}
+		return PositionKey(caller, tickLower, tickUpper, params.Salt)
+	}
+	inRange := addPosition(-600, 600, 5_000_000, 1)
+	below := addPosition(6000, 12000, 1_000_000, 2)
+	above := addPosition(9000, 18000, 3_000_000, 3)
+
+	poolFeeGrowth0 := new(big.Int).Set(pm.pools[key.ID()].FeeGrowth0X128)
+	owedBefore := pm.GetDelta(caller, key.Currency0)
+
+	delta, err := pm.DonateToRange(stateDB, key, 10020, 10980, big.NewInt(1000), big.NewInt(0), nil)
+	if err != nil {
+		t.Fatalf("DonateToRange failed: %v", err)
+	}
+	owedAfter := pm.GetDelta(caller, key.Currency0)
+	if delta.Amount0.Cmp(big.NewInt(1000)) != 0 || new(big.Int).Sub(owedAfter, owedBefore).Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("Expected donor to owe 1000 more currency0, got delta %s", delta.Amount0)
+	}
+
+	// In-range liquidity is not credited
+	if pm.pools[key.ID()].FeeGrowth0X128.Cmp(poolFeeGrowth0) != 0 {
+		t.Error("Band donation must not touch the pool's in-range fee growth")
+	}
+
+	// The accumulators live in state: a fresh manager sees the same band
+	fresh := newTestPoolManager()
+	if got := fresh.bandLiquidity(stateDB, key.ID(), 10020, 10980); got.Cmp(big.NewInt(4_000_000)) != 0 {
+		t.Errorf("Expected band liquidity 4000000 after reload, got %s", got)
+	}
+	if growth0, _ := fresh.rangeGrowthInside(stateDB, key.ID(), 6000, 12000); growth0.Sign() == 0 {
+		t.Error("Band growth should survive a reload")
+	}
+	if growth0, _ := fresh.rangeGrowthInside(stateDB, key.ID(), -600, 600); growth0.Sign() != 0 {
+		t.Errorf("Disjoint range should see no band growth, got %s", growth0)
+	}
+
+	// A position opened after the donation has no claim on it
+	late := addPosition(10020, 10980, 2_000_000, 4)
+
+	// Poke each position to collect
+	owed := make(map[[32]byte]*big.Int)
+	for _, positionKey := range [][32]byte{inRange, below, above, late} {
+		if _, _, err := pm.ModifyPosition(stateDB, key, positionKey, big.NewInt(0), nil); err != nil {
+			t.Fatalf("ModifyPosition failed: %v", err)
+		}
+		info, err := pm.GetPositionInfo(stateDB, positionKey)
+		if err != nil {
+			t.Fatalf("GetPositionInfo failed: %v", err)
+		}
+		owed[positionKey] = info.TokensOwed0
+	}
+
+	if owed[inRange].Sign() != 0 || owed[late].Sign() != 0 {
+		t.Errorf("Positions outside the band or opened later collected: %s, %s", owed[inRange], owed[late])
+	}
+	// 1000 split 1:3 by liquidity, rounded down
+	if owed[below].Int64() < 249 || owed[below].Int64() > 250 || owed[above].Int64() < 749 || owed[above].Int64() > 750 {
+		t.Errorf("Expected ~250/750 split, got %s/%s", owed[below], owed[above])
+	}
+
+	// Collecting twice pays nothing more
+	if _, _, err := pm.ModifyPosition(stateDB, key, below, big.NewInt(0), nil); err != nil {
+		t.Fatalf("ModifyPosition failed: %v", err)
+	}
+	if info, _ := pm.GetPositionInfo(stateDB, below); info.TokensOwed0.Cmp(owed[below]) != 0 {
+		t.Errorf("Second collection changed owed fees: %s -> %s", owed[below], info.TokensOwed0)
+	}
+
+	// A band nobody overlaps cannot be donated to
+	if _, err := pm.DonateToRange(stateDB, key, 50000, 60000, big.NewInt(1), nil, nil); err != ErrNoLiquidity {
+		t.Errorf("Expected ErrNoLiquidity, got: %v", err)
+	}
+	if _, err := pm.DonateToRange(stateDB, key, 600, -600, big.NewInt(1), nil, nil); err != ErrInvalidTickRange {
+		t.Errorf("Expected ErrInvalidTickRange, got: %v", err)
+	}
+}
+
 // =========================================================================
 // Flash Loan Tests
 // =========================================================================
@@ -662,8 +1254,8 @@ func BenchmarkPoolManagerModifyLiquidity(b *testing.B) {
 	pm.Initialize(stateDB, key, sqrtPriceX96, nil)
 
 	params := ModifyLiquidityParams{
-		TickLower:      -1000,
-		TickUpper:      1000,
+		TickLower:      -960,
+		TickUpper:      960,
 		LiquidityDelta: big.NewInt(1000000),
 		Salt:           [32]byte{},
 	}
@@ -0,0 +1,294 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/geth/common"
+)
+
+// DefaultPositionRequestTimeout is how long a request waits for a keeper
+// before its owner may cancel it (blocks)
+const DefaultPositionRequestTimeout uint64 = 90
+
+// PositionRequest is a pending perp position change awaiting execution
+type PositionRequest struct {
+	ID              uint64
+	Owner           common.Address
+	Market          [32]byte // Perp market ID
+	IsIncrease      bool     // Open/increase vs decrease/close
+	IsLong          bool     // Position direction
+	SizeDelta       *big.Int // Absolute size to add or remove
+	CollateralDelta *big.Int // Collateral escrowed (increase only)
+	AcceptablePrice *big.Int // Worst execution price (Q96)
+	CreatedAt       uint64   // Block the request was created in
+}
+
+// PositionRouter decouples perp order submission from execution
+// (request-then-execute). Users queue requests; keepers execute them
+// against a fresh fast price. Collateral for increase requests is held in
+// LXVault custody from request creation until it becomes position margin or
+// is refunded on cancellation.
+type PositionRouter struct {
+	Keepers        map[common.Address]bool     // Whitelisted executors
+	Requests       map[uint64]*PositionRequest // Pending requests
+	RequestTimeout uint64                      // Blocks before owner may cancel
+	Escrowed       *big.Int                    // Collateral held for pending requests
+
+	engine *PerpetualEngine
+	prices *FastPriceFeed
+
+	nextRequestID uint64
+
+	mu sync.Mutex
+}
+
+// NewPositionRouter creates a position router executing against engine
+// with prices from the fast price feed
+func NewPositionRouter(engine *PerpetualEngine, prices *FastPriceFeed) *PositionRouter {
+	return &PositionRouter{
+		Keepers:        make(map[common.Address]bool),
+		Requests:       make(map[uint64]*PositionRequest),
+		RequestTimeout: DefaultPositionRequestTimeout,
+		Escrowed:       big.NewInt(0),
+		engine:         engine,
+		prices:         prices,
+		nextRequestID:  1,
+	}
+}
+
+// AddKeeper whitelists a request executor
+func (pr *PositionRouter) AddKeeper(keeper common.Address) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.Keepers[keeper] = true
+}
+
+// CreateIncreasePositionRequest queues an open/increase and escrows
+// collateral from owner into LXVault custody
+func (pr *PositionRouter) CreateIncreasePositionRequest(
+	stateDB StateDB,
+	owner common.Address,
+	marketID [32]byte,
+	isLong bool,
+	sizeDelta, collateral, acceptablePrice *big.Int,
+) (uint64, error) {
+	if collateral == nil || collateral.Sign() <= 0 {
+		return 0, ErrInsufficientMargin
+	}
+	collateralU256, overflow := uint256.FromBig(collateral)
+	if overflow || stateDB.GetBalance(owner).Lt(collateralU256) {
+		return 0, ErrInsufficientBalance
+	}
+
+	id, err := pr.createRequest(stateDB, owner, marketID, true, isLong, sizeDelta, collateral, acceptablePrice)
+	if err != nil {
+		return 0, err
+	}
+	stateDB.SubBalance(owner, collateralU256)
+	stateDB.AddBalance(lxVaultAddr, collateralU256)
+	return id, nil
+}
+
+// CreateDecreasePositionRequest queues a decrease/close
+func (pr *PositionRouter) CreateDecreasePositionRequest(
+	stateDB StateDB,
+	owner common.Address,
+	marketID [32]byte,
+	isLong bool,
+	sizeDelta, acceptablePrice *big.Int,
+) (uint64, error) {
+	return pr.createRequest(stateDB, owner, marketID, false, isLong, sizeDelta, big.NewInt(0), acceptablePrice)
+}
+
+func (pr *PositionRouter) createRequest(
+	stateDB StateDB,
+	owner common.Address,
+	marketID [32]byte,
+	isIncrease, isLong bool,
+	sizeDelta, collateral, acceptablePrice *big.Int,
+) (uint64, error) {
+	if sizeDelta == nil || sizeDelta.Sign() <= 0 {
+		return 0, ErrInvalidPositionSize
+	}
+	if acceptablePrice == nil || acceptablePrice.Sign() <= 0 {
+		return 0, ErrInvalidAmount
+	}
+	if _, err := pr.engine.GetMarket(marketID); err != nil {
+		return 0, err
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	id := pr.nextRequestID
+	pr.nextRequestID++
+
+	pr.Requests[id] = &PositionRequest{
+		ID:              id,
+		Owner:           owner,
+		Market:          marketID,
+		IsIncrease:      isIncrease,
+		IsLong:          isLong,
+		SizeDelta:       new(big.Int).Set(sizeDelta),
+		CollateralDelta: new(big.Int).Set(collateral),
+		AcceptablePrice: new(big.Int).Set(acceptablePrice),
+		CreatedAt:       stateDB.GetBlockNumber(),
+	}
+	pr.Escrowed.Add(pr.Escrowed, collateral)
+
+	return id, nil
+}
+
+// ExecuteIncreasePosition opens or increases the requested position at the
+// current fast price and moves the escrowed collateral into it
+func (pr *PositionRouter) ExecuteIncreasePosition(stateDB StateDB, keeper common.Address, requestID uint64) (*PerpPosition, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	req, err := pr.executableRequest(stateDB, keeper, requestID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	restoreMark, err := pr.setExecutionPrice(stateDB, req)
+	if err != nil {
+		return nil, err
+	}
+
+	size := new(big.Int).Set(req.SizeDelta)
+	if !req.IsLong {
+		size.Neg(size)
+	}
+	position, err := pr.engine.OpenPosition(req.Owner, req.Market, size, req.CollateralDelta, false)
+	if err != nil {
+		restoreMark()
+		return nil, err
+	}
+
+	pr.Escrowed.Sub(pr.Escrowed, req.CollateralDelta)
+	delete(pr.Requests, requestID)
+	return position, nil
+}
+
+// ExecuteDecreasePosition reduces the requested position at the current
+// fast price and returns the realized PnL
+func (pr *PositionRouter) ExecuteDecreasePosition(stateDB StateDB, keeper common.Address, requestID uint64) (*big.Int, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	req, err := pr.executableRequest(stateDB, keeper, requestID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The position may have flipped or closed since the request was made;
+	// never reduce the other side
+	position, err := pr.engine.GetPosition(req.Owner, req.Market)
+	if err != nil {
+		return nil, err
+	}
+	if (position.Size.Sign() > 0) != req.IsLong {
+		return nil, ErrPositionSideMismatch
+	}
+
+	restoreMark, err := pr.setExecutionPrice(stateDB, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pnl, err := pr.engine.ClosePosition(req.Owner, req.Market, req.SizeDelta)
+	if err != nil {
+		restoreMark()
+		return nil, err
+	}
+
+	delete(pr.Requests, requestID)
+	return pnl, nil
+}
+
+// CancelRequest cancels a request whose timeout has passed, pays the
+// escrowed collateral back to the owner and returns the amount refunded
+func (pr *PositionRouter) CancelRequest(stateDB StateDB, owner common.Address, requestID uint64) (*big.Int, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	req, exists := pr.Requests[requestID]
+	if !exists {
+		return nil, ErrRequestNotFound
+	}
+	if req.Owner != owner {
+		return nil, ErrUnauthorized
+	}
+	if !pr.isExpired(stateDB, req) {
+		return nil, ErrRequestNotExpired
+	}
+
+	refund := new(big.Int).Set(req.CollateralDelta)
+	if refund.Sign() > 0 {
+		refundU256, _ := uint256.FromBig(refund)
+		stateDB.SubBalance(lxVaultAddr, refundU256)
+		stateDB.AddBalance(owner, refundU256)
+	}
+	pr.Escrowed.Sub(pr.Escrowed, refund)
+	delete(pr.Requests, requestID)
+	return refund, nil
+}
+
+// executableRequest checks the keeper and request state before execution
+func (pr *PositionRouter) executableRequest(stateDB StateDB, keeper common.Address, requestID uint64, isIncrease bool) (*PositionRequest, error) {
+	if !pr.Keepers[keeper] {
+		return nil, ErrNotKeeper
+	}
+	req, exists := pr.Requests[requestID]
+	if !exists || req.IsIncrease != isIncrease {
+		return nil, ErrRequestNotFound
+	}
+	if pr.isExpired(stateDB, req) {
+		return nil, ErrRequestExpired
+	}
+	return req, nil
+}
+
+// setExecutionPrice reads the fast price for the market's base asset,
+// enforces the request's acceptable price and marks the market to it. The
+// returned func restores the previous mark price and must be called if the
+// execution then fails, so a rejected request never moves the market.
+func (pr *PositionRouter) setExecutionPrice(stateDB StateDB, req *PositionRequest) (func(), error) {
+	market, err := pr.engine.GetMarket(req.Market)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := market.BaseAsset.Address
+	if !pr.prices.IsFresh(stateDB, asset) {
+		return nil, ErrStaleFastPrice
+	}
+	price, err := pr.prices.GetFastPrice(stateDB, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buying (long increase, short decrease) must not pay above acceptable;
+	// selling must not receive below it
+	buying := req.IsLong == req.IsIncrease
+	if buying && price.Cmp(req.AcceptablePrice) > 0 ||
+		!buying && price.Cmp(req.AcceptablePrice) < 0 {
+		return nil, ErrPriceSlippage
+	}
+
+	previous := market.MarkPrice
+	if err := pr.engine.UpdateMarkPrice(req.Market, price); err != nil {
+		return nil, err
+	}
+	return func() { pr.engine.UpdateMarkPrice(req.Market, previous) }, nil
+}
+
+func (pr *PositionRouter) isExpired(stateDB StateDB, req *PositionRequest) bool {
+	return stateDB.GetBlockNumber() >= req.CreatedAt+pr.RequestTimeout
+}
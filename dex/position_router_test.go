@@ -0,0 +1,226 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/geth/common"
+)
+
+var testRouterKeeper = common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")
+
+// q96Price returns p as a Q96 price
+func q96Price(p int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(p), Q96)
+}
+
+// newTestPositionRouter sets up a perp market on testOracleAsset at price
+// 100, a fast price feed and a router with one keeper. pushPrice submits a
+// keeper price at the current block of stateDB.
+func newTestPositionRouter(t *testing.T, stateDB *MockStateDB) (*PositionRouter, *PerpetualEngine, [32]byte, func(int64)) {
+	t.Helper()
+
+	fp, keeperKey := newTestFastPriceFeed(t, stateDB)
+	engine := NewPerpetualEngine()
+	marketID, err := engine.CreateMarket(
+		Currency{Address: testOracleAsset}, testBookQuote,
+		q96Price(100), 100, big.NewInt(5e16),
+	)
+	if err != nil {
+		t.Fatalf("CreateMarket failed: %v", err)
+	}
+
+	pr := NewPositionRouter(engine, fp)
+	pr.AddKeeper(testRouterKeeper)
+
+	pushPrice := func(p int64) {
+		price := q96Price(p)
+		block := stateDB.GetBlockNumber()
+		sig := signFastPrice(t, keeperKey, testOracleAsset, price, block)
+		if err := fp.SubmitFastPrice(stateDB, testOracleAsset, price, block, sig); err != nil {
+			t.Fatalf("SubmitFastPrice failed: %v", err)
+		}
+	}
+	return pr, engine, marketID, pushPrice
+}
+
+func TestPositionRouter_CreateRequest(t *testing.T) {
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(1000)
+	pr, _, marketID, _ := newTestPositionRouter(t, stateDB)
+	stateDB.AddBalance(testUser1, uint256.NewInt(1000))
+
+	id, err := pr.CreateIncreasePositionRequest(stateDB, testUser1, marketID, true, big.NewInt(10), big.NewInt(100), q96Price(101))
+	if err != nil {
+		t.Fatalf("CreateIncreasePositionRequest failed: %v", err)
+	}
+
+	req := pr.Requests[id]
+	if req == nil || !req.IsIncrease || !req.IsLong || req.CreatedAt != stateDB.GetBlockNumber() {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+	if pr.Escrowed.Int64() != 100 {
+		t.Fatalf("expected 100 escrowed, got %s", pr.Escrowed)
+	}
+	if stateDB.GetBalance(testUser1).Uint64() != 900 || stateDB.GetBalance(lxVaultAddr).Uint64() != 100 {
+		t.Fatalf("collateral not moved to custody: owner %s vault %s",
+			stateDB.GetBalance(testUser1), stateDB.GetBalance(lxVaultAddr))
+	}
+
+	_, err = pr.CreateIncreasePositionRequest(stateDB, testUser1, [32]byte{9}, true, big.NewInt(10), big.NewInt(100), q96Price(101))
+	if err != ErrPoolNotFound {
+		t.Fatalf("expected ErrPoolNotFound, got %v", err)
+	}
+	_, err = pr.CreateIncreasePositionRequest(stateDB, testUser1, marketID, true, big.NewInt(10), big.NewInt(0), q96Price(101))
+	if err != ErrInsufficientMargin {
+		t.Fatalf("expected ErrInsufficientMargin, got %v", err)
+	}
+	_, err = pr.CreateIncreasePositionRequest(stateDB, testUser1, marketID, true, big.NewInt(10), big.NewInt(901), q96Price(101))
+	if err != ErrInsufficientBalance {
+		t.Fatalf("expected ErrInsufficientBalance, got %v", err)
+	}
+	if pr.Escrowed.Int64() != 100 || len(pr.Requests) != 1 {
+		t.Fatal("rejected requests should not escrow anything")
+	}
+}
+
+func TestPositionRouter_KeeperExecution(t *testing.T) {
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(1000)
+	pr, engine, marketID, pushPrice := newTestPositionRouter(t, stateDB)
+	stateDB.AddBalance(testUser1, uint256.NewInt(1000))
+
+	id, _ := pr.CreateIncreasePositionRequest(stateDB, testUser1, marketID, true, big.NewInt(10), big.NewInt(100), q96Price(101))
+
+	// Execution requires a fresh fast price
+	stateDB.blockNumber += 5
+	if _, err := pr.ExecuteIncreasePosition(stateDB, testRouterKeeper, id); err != ErrStaleFastPrice {
+		t.Fatalf("expected ErrStaleFastPrice, got %v", err)
+	}
+
+	pushPrice(102)
+	if _, err := pr.ExecuteIncreasePosition(stateDB, testUser1, id); err != ErrNotKeeper {
+		t.Fatalf("expected ErrNotKeeper, got %v", err)
+	}
+	if _, err := pr.ExecuteIncreasePosition(stateDB, testRouterKeeper, id); err != ErrPriceSlippage {
+		t.Fatalf("expected ErrPriceSlippage, got %v", err)
+	}
+
+	stateDB.blockNumber++
+	pushPrice(101)
+	if _, err := pr.ExecuteIncreasePosition(stateDB, testRouterKeeper, id); err != nil {
+		t.Fatalf("ExecuteIncreasePosition failed: %v", err)
+	}
+
+	position, err := engine.GetPosition(testUser1, marketID)
+	if err != nil {
+		t.Fatalf("GetPosition failed: %v", err)
+	}
+	if position.Size.Int64() != 10 || position.Margin.Int64() != 100 {
+		t.Fatalf("unexpected position size %s margin %s", position.Size, position.Margin)
+	}
+	if position.EntryPrice.Cmp(q96Price(101)) != 0 {
+		t.Fatalf("expected entry at fast price 101, got %s", position.EntryPrice)
+	}
+	if pr.Escrowed.Sign() != 0 {
+		t.Fatalf("escrow should be released, got %s", pr.Escrowed)
+	}
+	if _, exists := pr.Requests[id]; exists {
+		t.Fatal("executed request should be removed")
+	}
+
+	// A decrease must match the side of the position it reduces
+	id, _ = pr.CreateDecreasePositionRequest(stateDB, testUser1, marketID, false, big.NewInt(5), q96Price(90))
+	if _, err := pr.ExecuteDecreasePosition(stateDB, testRouterKeeper, id); err != ErrPositionSideMismatch {
+		t.Fatalf("expected ErrPositionSideMismatch, got %v", err)
+	}
+	position, _ = engine.GetPosition(testUser1, marketID)
+	if position.Size.Int64() != 10 {
+		t.Fatalf("mismatched decrease changed size to %s", position.Size)
+	}
+
+	// Decrease half at a higher price
+	id, _ = pr.CreateDecreasePositionRequest(stateDB, testUser1, marketID, true, big.NewInt(5), q96Price(110))
+	stateDB.blockNumber++
+	pushPrice(111)
+	pnl, err := pr.ExecuteDecreasePosition(stateDB, testRouterKeeper, id)
+	if err != nil {
+		t.Fatalf("ExecuteDecreasePosition failed: %v", err)
+	}
+	if pnl.Int64() != 50 {
+		t.Fatalf("expected pnl 50, got %s", pnl)
+	}
+	position, _ = engine.GetPosition(testUser1, marketID)
+	if position.Size.Int64() != 5 {
+		t.Fatalf("expected size 5 after decrease, got %s", position.Size)
+	}
+}
+
+func TestPositionRouter_CancelAfterTimeout(t *testing.T) {
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(1000)
+	pr, _, marketID, pushPrice := newTestPositionRouter(t, stateDB)
+	stateDB.AddBalance(testUser1, uint256.NewInt(250))
+
+	id, _ := pr.CreateIncreasePositionRequest(stateDB, testUser1, marketID, false, big.NewInt(10), big.NewInt(250), q96Price(99))
+
+	if _, err := pr.CancelRequest(stateDB, testUser1, id); err != ErrRequestNotExpired {
+		t.Fatalf("expected ErrRequestNotExpired, got %v", err)
+	}
+
+	stateDB.blockNumber += pr.RequestTimeout
+	pushPrice(100)
+
+	// Keepers can no longer execute an expired request
+	if _, err := pr.ExecuteIncreasePosition(stateDB, testRouterKeeper, id); err != ErrRequestExpired {
+		t.Fatalf("expected ErrRequestExpired, got %v", err)
+	}
+	if _, err := pr.CancelRequest(stateDB, testUser2, id); err != ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+
+	refund, err := pr.CancelRequest(stateDB, testUser1, id)
+	if err != nil {
+		t.Fatalf("CancelRequest failed: %v", err)
+	}
+	if refund.Int64() != 250 {
+		t.Fatalf("expected refund 250, got %s", refund)
+	}
+	if pr.Escrowed.Sign() != 0 {
+		t.Fatalf("escrow should be empty, got %s", pr.Escrowed)
+	}
+	if stateDB.GetBalance(testUser1).Uint64() != 250 || !stateDB.GetBalance(lxVaultAddr).IsZero() {
+		t.Fatalf("refund not paid: owner %s vault %s", stateDB.GetBalance(testUser1), stateDB.GetBalance(lxVaultAddr))
+	}
+	if _, err := pr.CancelRequest(stateDB, testUser1, id); err != ErrRequestNotFound {
+		t.Fatalf("expected ErrRequestNotFound, got %v", err)
+	}
+}
+
+func TestPositionRouter_FailedExecutionKeepsMark(t *testing.T) {
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(1000)
+	pr, engine, marketID, pushPrice := newTestPositionRouter(t, stateDB)
+	stateDB.AddBalance(testUser1, uint256.NewInt(100))
+
+	// Far beyond the market's 100x leverage cap
+	id, _ := pr.CreateIncreasePositionRequest(stateDB, testUser1, marketID, true, big.NewInt(10_000), big.NewInt(100), q96Price(110))
+
+	stateDB.blockNumber++
+	pushPrice(105)
+	if _, err := pr.ExecuteIncreasePosition(stateDB, testRouterKeeper, id); err != ErrExcessiveLeverage {
+		t.Fatalf("expected ErrExcessiveLeverage, got %v", err)
+	}
+
+	market, _ := engine.GetMarket(marketID)
+	if market.MarkPrice.Cmp(q96Price(100)) != 0 {
+		t.Fatalf("failed execution moved mark to %s", market.MarkPrice)
+	}
+	if _, exists := pr.Requests[id]; !exists {
+		t.Fatal("failed request should stay pending")
+	}
+}
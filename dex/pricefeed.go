@@ -0,0 +1,100 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+)
+
+// DefaultMaxPriceDeviationBps is the default mark/index divergence bound (2.5%)
+const DefaultMaxPriceDeviationBps uint64 = 250
+
+// PriceFeed binds oracle prices to perp markets
+// Address: LP-9040 LXFeed
+//
+// The index price is the oracle hub aggregate; the mark price is the fast
+// price (which itself falls back to the hub). When the two diverge by more
+// than MaxDeviationBps the prices are flagged unreliable and the perp
+// engine refuses to liquidate against them.
+type PriceFeed struct {
+	MaxDeviationBps uint64 // Allowed |mark - index| / index in basis points
+
+	hub  *OracleHub
+	fast *FastPriceFeed
+}
+
+// NewPriceFeed creates a price feed over the oracle hub and fast price feed
+func NewPriceFeed(hub *OracleHub, fast *FastPriceFeed, maxDeviationBps uint64) *PriceFeed {
+	if maxDeviationBps == 0 {
+		maxDeviationBps = DefaultMaxPriceDeviationBps
+	}
+	return &PriceFeed{
+		MaxDeviationBps: maxDeviationBps,
+		hub:             hub,
+		fast:            fast,
+	}
+}
+
+// GetIndexPrice returns the oracle hub aggregate price for an asset
+func (pf *PriceFeed) GetIndexPrice(asset common.Address) (*big.Int, error) {
+	return pf.hub.GetPrice(asset)
+}
+
+// GetMarkPrice returns the fast price for an asset
+func (pf *PriceFeed) GetMarkPrice(stateDB StateDB, asset common.Address) (*big.Int, error) {
+	return pf.fast.GetFastPrice(stateDB, asset)
+}
+
+// IsReliable reports whether mark and index are within MaxDeviationBps
+func (pf *PriceFeed) IsReliable(stateDB StateDB, asset common.Address) (bool, error) {
+	mark, index, err := pf.prices(stateDB, asset)
+	if err != nil {
+		return false, err
+	}
+	return pf.withinBound(mark, index), nil
+}
+
+// SyncMarket pushes the current mark and index prices into a perp market
+// and flags the market unreliable if they diverge beyond the bound
+func (pf *PriceFeed) SyncMarket(stateDB StateDB, engine *PerpetualEngine, marketID [32]byte) error {
+	market, err := engine.GetMarket(marketID)
+	if err != nil {
+		return err
+	}
+
+	mark, index, err := pf.prices(stateDB, market.BaseAsset.Address)
+	if err != nil {
+		return err
+	}
+
+	if err := engine.UpdateMarkPrice(marketID, mark); err != nil {
+		return err
+	}
+	if err := engine.UpdateIndexPrice(marketID, index); err != nil {
+		return err
+	}
+	return engine.SetPricesUnreliable(marketID, !pf.withinBound(mark, index))
+}
+
+func (pf *PriceFeed) prices(stateDB StateDB, asset common.Address) (mark, index *big.Int, err error) {
+	index, err = pf.GetIndexPrice(asset)
+	if err != nil {
+		return nil, nil, err
+	}
+	mark, err = pf.GetMarkPrice(stateDB, asset)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mark, index, nil
+}
+
+// withinBound checks |mark - index| * 10000 <= index * MaxDeviationBps
+func (pf *PriceFeed) withinBound(mark, index *big.Int) bool {
+	diff := new(big.Int).Sub(mark, index)
+	diff.Abs(diff).Mul(diff, big.NewInt(10000))
+	bound := new(big.Int).Mul(index, new(big.Int).SetUint64(pf.MaxDeviationBps))
+	return diff.Cmp(bound) <= 0
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+)
+
+// newTestPriceFeed sets up a perp market on testOracleAsset with index and
+// mark both at 100 (Q96) and returns setters for each, which submit at the
+// current block of stateDB
+func newTestPriceFeed(t *testing.T, stateDB *MockStateDB) (*PriceFeed, *PerpetualEngine, [32]byte, func(int64), func(int64)) {
+	t.Helper()
+
+	pr, engine, marketID, pushMark := newTestPositionRouter(t, stateDB)
+	hub := pr.prices.hub
+
+	setIndex := func(p int64) {
+		if err := hub.SubmitPrice(stateDB, testOracleSource1, testOracleAsset, q96Price(p), stateDB.GetBlockNumber()); err != nil {
+			t.Fatalf("SubmitPrice failed: %v", err)
+		}
+	}
+	setIndex(100)
+	pushMark(100)
+
+	return NewPriceFeed(hub, pr.prices, 0), engine, marketID, setIndex, pushMark
+}
+
+func TestPriceFeed_Convergence(t *testing.T) {
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(1000)
+	pf, engine, marketID, setIndex, pushMark := newTestPriceFeed(t, stateDB)
+
+	setIndex(100)
+	pushMark(102)
+
+	mark, _ := pf.GetMarkPrice(stateDB, testOracleAsset)
+	index, _ := pf.GetIndexPrice(testOracleAsset)
+	if mark.Cmp(q96Price(102)) != 0 || index.Cmp(q96Price(100)) != 0 {
+		t.Fatalf("unexpected mark %s index %s", mark, index)
+	}
+
+	// 2% apart is within the default 2.5% bound
+	reliable, err := pf.IsReliable(stateDB, testOracleAsset)
+	if err != nil {
+		t.Fatalf("IsReliable failed: %v", err)
+	}
+	if !reliable {
+		t.Fatal("converged prices should be reliable")
+	}
+
+	if err := pf.SyncMarket(stateDB, engine, marketID); err != nil {
+		t.Fatalf("SyncMarket failed: %v", err)
+	}
+	market, _ := engine.GetMarket(marketID)
+	if market.MarkPrice.Cmp(mark) != 0 || market.IndexPrice.Cmp(index) != 0 {
+		t.Fatalf("market not synced: mark %s index %s", market.MarkPrice, market.IndexPrice)
+	}
+	if market.PricesUnreliable {
+		t.Fatal("market should not be flagged")
+	}
+}
+
+func TestPriceFeed_DivergenceBlocksLiquidation(t *testing.T) {
+	stateDB := NewMockStateDB()
+	stateDB.SetBlockNumber(1000)
+	pf, engine, marketID, setIndex, pushMark := newTestPriceFeed(t, stateDB)
+
+	if err := pf.SyncMarket(stateDB, engine, marketID); err != nil {
+		t.Fatalf("SyncMarket failed: %v", err)
+	}
+	if _, err := engine.OpenPosition(testUser1, marketID, big.NewInt(10), big.NewInt(100), false); err != nil {
+		t.Fatalf("OpenPosition failed: %v", err)
+	}
+
+	// Mark spikes down 9% while the index holds: position looks underwater
+	stateDB.blockNumber++
+	pushMark(91)
+	reliable, _ := pf.IsReliable(stateDB, testOracleAsset)
+	if reliable {
+		t.Fatal("diverged prices should be unreliable")
+	}
+	if err := pf.SyncMarket(stateDB, engine, marketID); err != nil {
+		t.Fatalf("SyncMarket failed: %v", err)
+	}
+	if _, err := engine.LiquidatePosition(testUser2, testUser1, marketID); err != ErrPricesUnreliable {
+		t.Fatalf("expected ErrPricesUnreliable, got %v", err)
+	}
+
+	// Index confirms the move: prices converge and liquidation proceeds
+	setIndex(91)
+	if err := pf.SyncMarket(stateDB, engine, marketID); err != nil {
+		t.Fatalf("SyncMarket failed: %v", err)
+	}
+	if _, err := engine.LiquidatePosition(testUser2, testUser1, marketID); err != nil {
+		t.Fatalf("LiquidatePosition failed: %v", err)
+	}
+}
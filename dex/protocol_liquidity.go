@@ -0,0 +1,196 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/geth/common"
+)
+
+// ProtocolPositionKey returns the key of the protocol-owned position seeded
+// into the pool for key. The position is full range, owned by the pool
+// manager itself and salted with the pool ID, so each pool has exactly one.
+func ProtocolPositionKey(key PoolKey) [32]byte {
+	return PositionKey(poolManagerAddr, MinUsableTick(key.TickSpacing), MaxUsableTick(key.TickSpacing), key.ID())
+}
+
+// SeedPool initializes the pool for key at sqrtPriceX96 and adds liquidity
+// across its full range as protocol-owned liquidity. Only the protocol fee
+// controller may seed, and the tokens are pulled from the controller itself:
+// no other account can be made to fund a seed. Every check that can fail runs
+// before the pool is created, so a rejected seed leaves no pool behind;
+// hook errors revert the whole call as for any other precompile failure.
+// The position can later be withdrawn to the treasury with
+// WithdrawProtocolLiquidity.
+func (pm *PoolManager) SeedPool(
+	stateDB StateDB,
+	caller common.Address,
+	key PoolKey,
+	sqrtPriceX96 *big.Int,
+	liquidity *big.Int,
+) (BalanceDelta, error) {
+	if pm.protocolFeeController == (common.Address{}) || caller != pm.protocolFeeController {
+		return ZeroBalanceDelta(), ErrUnauthorized
+	}
+	if liquidity == nil || liquidity.Sign() <= 0 {
+		return ZeroBalanceDelta(), ErrInvalidAmount
+	}
+	if sqrtPriceX96 == nil || sqrtPriceX96.Cmp(MinSqrtRatio) < 0 || sqrtPriceX96.Cmp(MaxSqrtRatio) > 0 {
+		return ZeroBalanceDelta(), ErrInvalidSqrtPrice
+	}
+	if pm.getPool(stateDB, key.ID()).IsInitialized() {
+		return ZeroBalanceDelta(), ErrPoolAlreadyInitialized
+	}
+	pm.mu.RLock()
+	locked := pm.locked
+	pm.mu.RUnlock()
+	if locked {
+		return ZeroBalanceDelta(), ErrReentrant
+	}
+
+	// Price the deposit at the starting tick and make sure the controller can
+	// cover its native leg before anything is written
+	params := ModifyLiquidityParams{
+		TickLower:      MinUsableTick(key.TickSpacing),
+		TickUpper:      MaxUsableTick(key.TickSpacing),
+		LiquidityDelta: new(big.Int).Set(liquidity),
+		Salt:           key.ID(),
+	}
+	owed, _ := pm.calculateLiquidityAmounts(&Pool{Tick: pm.sqrtPriceX96ToTick(sqrtPriceX96)}, key, params, poolManagerAddr)
+	if err := checkNativeBalance(stateDB, key, caller, owed); err != nil {
+		return ZeroBalanceDelta(), err
+	}
+
+	if _, err := pm.Initialize(stateDB, key, sqrtPriceX96, nil); err != nil {
+		return ZeroBalanceDelta(), err
+	}
+	return pm.modifyProtocolLiquidity(stateDB, key, params, caller)
+}
+
+// WithdrawProtocolLiquidity removes liquidity from the protocol-owned
+// position in the pool for key and sends the released tokens to treasury.
+// Only the protocol fee controller may withdraw.
+func (pm *PoolManager) WithdrawProtocolLiquidity(
+	stateDB StateDB,
+	caller common.Address,
+	key PoolKey,
+	liquidity *big.Int,
+	treasury common.Address,
+) (BalanceDelta, error) {
+	if pm.protocolFeeController == (common.Address{}) || caller != pm.protocolFeeController {
+		return ZeroBalanceDelta(), ErrUnauthorized
+	}
+	if liquidity == nil || liquidity.Sign() <= 0 {
+		return ZeroBalanceDelta(), ErrInvalidAmount
+	}
+	if treasury == (common.Address{}) {
+		return ZeroBalanceDelta(), ErrInvalidRecipient
+	}
+
+	positionKey := ProtocolPositionKey(key)
+	position := pm.getPosition(stateDB, positionKey)
+	if position.Owner != poolManagerAddr {
+		return ZeroBalanceDelta(), ErrPositionNotFound
+	}
+	if position.Liquidity.Cmp(liquidity) < 0 {
+		return ZeroBalanceDelta(), ErrInsufficientLiquidity
+	}
+
+	params := ModifyLiquidityParams{
+		TickLower:      position.TickLower,
+		TickUpper:      position.TickUpper,
+		LiquidityDelta: new(big.Int).Neg(liquidity),
+		Salt:           position.Salt,
+	}
+	return pm.modifyProtocolLiquidity(stateDB, key, params, treasury)
+}
+
+// modifyProtocolLiquidity applies params to the protocol-owned position
+// under its own lock context and settles the result with counterparty:
+// tokens owed by the protocol are pulled from it, tokens released are paid
+// to it
+func (pm *PoolManager) modifyProtocolLiquidity(
+	stateDB StateDB,
+	key PoolKey,
+	params ModifyLiquidityParams,
+	counterparty common.Address,
+) (BalanceDelta, error) {
+	pm.mu.Lock()
+	if pm.locked {
+		pm.mu.Unlock()
+		return ZeroBalanceDelta(), ErrReentrant
+	}
+	pm.locked = true
+	pm.mu.Unlock()
+
+	defer func() {
+		pm.mu.Lock()
+		pm.locked = false
+		pm.mu.Unlock()
+	}()
+
+	pm.lockers = append(pm.lockers, poolManagerAddr)
+	pm.currentDeltas[poolManagerAddr] = make(map[Currency]*big.Int)
+	defer pm.cleanupLocker(poolManagerAddr)
+
+	positionKey := PositionKey(poolManagerAddr, params.TickLower, params.TickUpper, params.Salt)
+	delta, _, err := pm.modifyLiquidity(stateDB, key, poolManagerAddr, positionKey, params, nil)
+	if err != nil {
+		return ZeroBalanceDelta(), err
+	}
+
+	for _, leg := range deltaLegs(key, delta) {
+		switch leg.amount.Sign() {
+		case 1:
+			pm.moveFunds(stateDB, leg.currency, counterparty, poolManagerAddr, leg.amount)
+		case -1:
+			pm.moveFunds(stateDB, leg.currency, poolManagerAddr, counterparty, new(big.Int).Neg(leg.amount))
+		}
+		pm.updateDelta(poolManagerAddr, leg.currency, new(big.Int).Neg(leg.amount))
+	}
+
+	if err := pm.verifySettlement(poolManagerAddr); err != nil {
+		return ZeroBalanceDelta(), err
+	}
+	return delta, nil
+}
+
+// moveFunds transfers amount of currency between two accounts
+func (pm *PoolManager) moveFunds(stateDB StateDB, currency Currency, from, to common.Address, amount *big.Int) {
+	if currency.IsNative() {
+		amountU256, _ := uint256.FromBig(amount)
+		stateDB.SubBalance(from, amountU256)
+		stateDB.AddBalance(to, amountU256)
+	} else {
+		pm.transferERC20(stateDB, currency, from, to, amount)
+	}
+}
+
+// checkNativeBalance returns ErrInsufficientBalance if account cannot pay
+// the native leg of owed
+func checkNativeBalance(stateDB StateDB, key PoolKey, account common.Address, owed BalanceDelta) error {
+	for _, leg := range deltaLegs(key, owed) {
+		if !leg.currency.IsNative() || leg.amount.Sign() <= 0 {
+			continue
+		}
+		amountU256, overflow := uint256.FromBig(leg.amount)
+		if overflow || stateDB.GetBalance(account).Lt(amountU256) {
+			return ErrInsufficientBalance
+		}
+	}
+	return nil
+}
+
+// deltaLeg is one currency's side of a BalanceDelta
+type deltaLeg struct {
+	currency Currency
+	amount   *big.Int
+}
+
+// deltaLegs pairs each side of delta with its currency in key
+func deltaLegs(key PoolKey, delta BalanceDelta) [2]deltaLeg {
+	return [2]deltaLeg{{key.Currency0, delta.Amount0}, {key.Currency1, delta.Amount1}}
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/geth/common"
+)
+
+func TestSeedPool(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	key := newTestPoolKey()
+	admin := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	treasury := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	pm.protocolFeeController = admin
+	stateDB.AddBalance(admin, uint256.NewInt(1_000_000))
+	stateDB.AddBalance(other, uint256.NewInt(1_000_000))
+
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	liquidity := big.NewInt(1_000_000)
+
+	delta, err := pm.SeedPool(stateDB, admin, key, sqrtPriceX96, liquidity)
+	if err != nil {
+		t.Fatalf("SeedPool failed: %v", err)
+	}
+
+	pool, err := pm.GetPool(stateDB, key)
+	if err != nil {
+		t.Fatalf("GetPool failed: %v", err)
+	}
+	if pool.Liquidity.Cmp(liquidity) != 0 {
+		t.Errorf("Pool liquidity: expected %s, got %s", liquidity, pool.Liquidity)
+	}
+
+	info, err := pm.GetPositionInfo(stateDB, ProtocolPositionKey(key))
+	if err != nil {
+		t.Fatalf("GetPositionInfo failed: %v", err)
+	}
+	if info.Owner != poolManagerAddr {
+		t.Errorf("Position owner: expected %s, got %s", poolManagerAddr.Hex(), info.Owner.Hex())
+	}
+	if info.Liquidity.Cmp(liquidity) != 0 {
+		t.Errorf("Position liquidity: expected %s, got %s", liquidity, info.Liquidity)
+	}
+	if info.TickLower != MinUsableTick(key.TickSpacing) || info.TickUpper != MaxUsableTick(key.TickSpacing) {
+		t.Errorf("Position range: expected full range, got [%d, %d)", info.TickLower, info.TickUpper)
+	}
+	if len(pm.GetPositionsByOwner(stateDB, poolManagerAddr)) != 1 {
+		t.Error("Protocol position should be indexed under the pool manager")
+	}
+
+	// The controller paid the native leg into the pool manager; nobody else
+	// was charged
+	paid := new(uint256.Int).Sub(uint256.NewInt(1_000_000), stateDB.GetBalance(admin))
+	if paid.ToBig().Cmp(delta.Amount0) != 0 || stateDB.GetBalance(poolManagerAddr).ToBig().Cmp(delta.Amount0) != 0 {
+		t.Errorf("Controller paid %s, pool manager holds %s, expected %s",
+			paid, stateDB.GetBalance(poolManagerAddr), delta.Amount0)
+	}
+	if !stateDB.GetBalance(other).Eq(uint256.NewInt(1_000_000)) {
+		t.Errorf("Other account balance changed to %s", stateDB.GetBalance(other))
+	}
+	if len(pm.lockers) != 0 || pm.GetDelta(poolManagerAddr, key.Currency0).Sign() != 0 {
+		t.Error("Seeding should leave no open lock context")
+	}
+
+	// A pool can only be seeded once
+	if _, err := pm.SeedPool(stateDB, admin, key, sqrtPriceX96, liquidity); err != ErrPoolAlreadyInitialized {
+		t.Errorf("Expected ErrPoolAlreadyInitialized, got: %v", err)
+	}
+
+	// Withdrawing sends the released tokens to the treasury
+	if _, err := pm.WithdrawProtocolLiquidity(stateDB, other, key, liquidity, treasury); err != ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized, got: %v", err)
+	}
+	if _, err := pm.WithdrawProtocolLiquidity(stateDB, admin, key, new(big.Int).Add(liquidity, big.NewInt(1)), treasury); err != ErrInsufficientLiquidity {
+		t.Errorf("Expected ErrInsufficientLiquidity, got: %v", err)
+	}
+	out, err := pm.WithdrawProtocolLiquidity(stateDB, admin, key, liquidity, treasury)
+	if err != nil {
+		t.Fatalf("WithdrawProtocolLiquidity failed: %v", err)
+	}
+	if got := stateDB.GetBalance(treasury).ToBig(); got.Cmp(new(big.Int).Neg(out.Amount0)) != 0 {
+		t.Errorf("Treasury received %s, expected %s", got, new(big.Int).Neg(out.Amount0))
+	}
+	if len(pm.GetPositionsByOwner(stateDB, poolManagerAddr)) != 0 {
+		t.Error("Withdrawn protocol position should be closed")
+	}
+}
+
+func TestSeedPoolOnlyAdmin(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	key := newTestPoolKey()
+	admin := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	other := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	stateDB.AddBalance(admin, uint256.NewInt(1_000_000))
+	stateDB.AddBalance(other, uint256.NewInt(1_000_000))
+
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	liquidity := big.NewInt(1_000_000)
+
+	// With no controller configured nobody can seed
+	if _, err := pm.SeedPool(stateDB, admin, key, sqrtPriceX96, liquidity); err != ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized, got: %v", err)
+	}
+
+	// A funded account that is not the controller cannot seed with its own
+	// tokens either
+	pm.protocolFeeController = admin
+	if _, err := pm.SeedPool(stateDB, other, key, sqrtPriceX96, liquidity); err != ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized, got: %v", err)
+	}
+
+	// A seed the controller cannot pay for leaves no pool behind
+	if _, err := pm.SeedPool(stateDB, admin, key, sqrtPriceX96, big.NewInt(10_000_000)); err != ErrInsufficientBalance {
+		t.Errorf("Expected ErrInsufficientBalance, got: %v", err)
+	}
+	if _, err := pm.GetPool(stateDB, key); err == nil {
+		t.Error("Rejected seed should not initialize the pool")
+	}
+}
@@ -5,6 +5,7 @@ package dex
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 	"time"
@@ -41,9 +42,47 @@ type TeleportBridge struct {
 	TotalBridged map[common.Address]*big.Int
 	TotalFees    *big.Int
 
+	// Instant teleports: LP balances per chain/token/provider and
+	// fronted transfers awaiting Warp finalization
+	LPBalances       map[uint32]map[common.Address]map[common.Address]*big.Int
+	FrontedTeleports map[[32]byte]*FrontedTeleport
+	InstantFeeRate   uint32 // LP fee in basis points
+	InstantTimeout   int64  // Seconds before an LP may claw back from escrow
+
+	// warpVerifier checks teleport Warp messages; nil rejects them all
+	warpVerifier WarpVerifier
+
+	// now returns the current unix time; replaced in tests
+	now func() int64
+
 	mu sync.RWMutex
 }
 
+// FrontedTeleport is a transfer an LP paid out on the destination chain
+// before its Warp message finalized
+type FrontedTeleport struct {
+	TeleportID  [32]byte
+	Provider    common.Address // LP that fronted the funds
+	SourceChain uint32         // Chain holding the escrow
+	DestChain   uint32         // Chain the LP paid out on
+	Recipient   common.Address
+	Token       common.Address
+	Amount      *big.Int // Amount owed back to the LP
+	Deadline    int64    // Unix time after which the LP may claw back
+}
+
+// TeleportPayload is the transfer a teleport's Warp message attests to
+type TeleportPayload struct {
+	TeleportID [32]byte
+	Recipient  common.Address
+	Token      common.Address
+	Amount     *big.Int
+}
+
+// WarpVerifier checks a Warp message and its signatures against the source
+// chain's validator set and returns the teleport payload it carries
+type WarpVerifier func(message []byte, signatures [][]byte) (*TeleportPayload, error)
+
 // BridgedToken represents a token that can be bridged
 type BridgedToken struct {
 	LocalAddress  common.Address // Address on this chain
@@ -99,9 +138,24 @@ func NewTeleportBridge(threshold uint32) *TeleportBridge {
 		Liquidity:          make(map[uint32]map[common.Address]*big.Int),
 		TotalBridged:       make(map[common.Address]*big.Int),
 		TotalFees:          big.NewInt(0),
+		LPBalances:         make(map[uint32]map[common.Address]map[common.Address]*big.Int),
+		FrontedTeleports:   make(map[[32]byte]*FrontedTeleport),
+		InstantFeeRate:     10,   // 0.1%
+		InstantTimeout:     3600, // 1 hour
+		now:                func() int64 { return time.Now().Unix() },
 	}
 }
 
+// SetWarpVerifier sets the function used to verify teleport Warp messages.
+// Until one is set, CompleteTeleport and ReconcileTeleport fail with
+// ErrWarpVerifierUnset.
+func (tb *TeleportBridge) SetWarpVerifier(verifier WarpVerifier) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.warpVerifier = verifier
+}
+
 // InitiateTeleport starts a cross-chain transfer
 func (tb *TeleportBridge) InitiateTeleport(
 	sender common.Address,
@@ -191,14 +245,15 @@ func (tb *TeleportBridge) CompleteTeleport(
 		return ErrInvalidTeleportState
 	}
 
-	// Verify signatures (simplified - in production use Warp verification)
 	if uint32(len(signatures)) < tb.Threshold {
 		return ErrInsufficientSignatures
 	}
-
-	// Verify warp message (in production, call Warp precompile)
-	if !tb.verifyWarpMessage(warpMessage, teleportID) {
-		return ErrInvalidWarpSignature
+	payload, err := tb.verifyWarpMessage(warpMessage, signatures, teleportID)
+	if err != nil {
+		return err
+	}
+	if !payload.matches(request.Recipient, request.Token, request.Amount) {
+		return ErrTeleportMismatch
 	}
 
 	// Mark as validated
@@ -247,7 +302,8 @@ func (tb *TeleportBridge) CancelTeleport(sender common.Address, teleportID [32]b
 		return ErrUnauthorized
 	}
 
-	if request.Status != TeleportPending {
+	// A fronted teleport has already been paid out by an LP
+	if request.Status != TeleportPending || request.Fronted {
 		return ErrCannotCancel
 	}
 
@@ -261,6 +317,159 @@ func (tb *TeleportBridge) CancelTeleport(sender common.Address, teleportID [32]b
 	return nil
 }
 
+// ProvideLiquidity deposits LP funds on chainID used to front instant teleports
+func (tb *TeleportBridge) ProvideLiquidity(
+	provider common.Address,
+	chainID uint32,
+	token common.Address,
+	amount *big.Int,
+) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.creditLP(provider, chainID, token, amount)
+	return nil
+}
+
+// GetLPBalance returns a provider's unencumbered liquidity
+func (tb *TeleportBridge) GetLPBalance(provider common.Address, chainID uint32, token common.Address) *big.Int {
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	return new(big.Int).Set(tb.lpBalance(provider, chainID, token))
+}
+
+// InstantTeleport pays a pending teleport out of an LP's liquidity on the
+// destination chain without waiting for Warp finality. The chains,
+// recipient, token and amount must match the pending request, and each
+// request can be fronted once. The recipient receives amount minus the LP
+// fee; the LP is owed the full amount, which is repaid from escrow by
+// ReconcileTeleport or ClawbackTeleport.
+func (tb *TeleportBridge) InstantTeleport(
+	provider common.Address,
+	teleportID [32]byte,
+	sourceChain, destChain uint32,
+	recipient common.Address,
+	token common.Address,
+	amount *big.Int,
+) (*big.Int, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	if tb.CompletedTeleports[teleportID] || tb.FrontedTeleports[teleportID] != nil {
+		return nil, ErrDuplicateTeleportID
+	}
+	request := tb.PendingTeleports[teleportID]
+	if request == nil {
+		return nil, ErrTeleportNotFound
+	}
+	if request.Fronted {
+		return nil, ErrDuplicateTeleportID
+	}
+	if request.Status != TeleportPending && request.Status != TeleportBurned {
+		return nil, ErrInvalidTeleportState
+	}
+	if request.SourceChain != sourceChain || request.DestChain != destChain ||
+		request.Recipient != recipient || request.Token != token || request.Amount.Cmp(amount) != 0 {
+		return nil, ErrTeleportMismatch
+	}
+
+	balance := tb.lpBalance(provider, destChain, token)
+	if balance.Cmp(amount) < 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+
+	fee := new(big.Int).Mul(amount, big.NewInt(int64(tb.InstantFeeRate)))
+	fee.Div(fee, big.NewInt(10000))
+	payout := new(big.Int).Sub(amount, fee)
+
+	// Only the payout leaves the LP; repaying the full amount later is
+	// what earns it the fee
+	balance.Sub(balance, payout)
+	tb.Liquidity[destChain][token].Sub(tb.Liquidity[destChain][token], payout)
+
+	request.Fronted = true
+	tb.FrontedTeleports[teleportID] = &FrontedTeleport{
+		TeleportID:  teleportID,
+		Provider:    provider,
+		SourceChain: sourceChain,
+		DestChain:   destChain,
+		Recipient:   recipient,
+		Token:       token,
+		Amount:      new(big.Int).Set(amount),
+		Deadline:    tb.now() + tb.InstantTimeout,
+	}
+
+	return payout, nil
+}
+
+// ReconcileTeleport settles a fronted teleport once its Warp message
+// finalizes. The message must attest to the recipient, token and amount the
+// LP fronted; the amount is then released from escrow to repay the LP.
+func (tb *TeleportBridge) ReconcileTeleport(
+	teleportID [32]byte,
+	warpMessage []byte,
+	signatures [][]byte,
+) error {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	fronted := tb.FrontedTeleports[teleportID]
+	if fronted == nil {
+		return ErrTeleportNotFound
+	}
+
+	if uint32(len(signatures)) < tb.Threshold {
+		return ErrInsufficientSignatures
+	}
+	payload, err := tb.verifyWarpMessage(warpMessage, signatures, teleportID)
+	if err != nil {
+		return err
+	}
+	if !payload.matches(fronted.Recipient, fronted.Token, fronted.Amount) {
+		return ErrTeleportMismatch
+	}
+
+	if err := tb.releaseEscrow(fronted); err != nil {
+		return err
+	}
+	tb.repayFronted(fronted)
+	return nil
+}
+
+// ClawbackTeleport repays an LP from the source escrow when a fronted
+// teleport's Warp message has not arrived by its deadline. The teleport is
+// marked complete so a late message cannot pay out twice.
+func (tb *TeleportBridge) ClawbackTeleport(provider common.Address, teleportID [32]byte) error {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	fronted := tb.FrontedTeleports[teleportID]
+	if fronted == nil {
+		return ErrTeleportNotFound
+	}
+	if fronted.Provider != provider {
+		return ErrUnauthorized
+	}
+	if tb.now() < fronted.Deadline {
+		return ErrTeleportNotFinalized
+	}
+
+	if err := tb.releaseEscrow(fronted); err != nil {
+		return err
+	}
+	tb.repayFronted(fronted)
+	return nil
+}
+
 // GetTeleportStatus returns the status of a teleport
 func (tb *TeleportBridge) GetTeleportStatus(teleportID [32]byte) (TeleportStatus, error) {
 	tb.mu.RLock()
@@ -349,6 +558,55 @@ func (tb *TeleportBridge) AddOperator(operator common.Address) {
 
 // Helper functions
 
+// lpBalance returns the provider's balance, creating a zero entry if needed
+func (tb *TeleportBridge) lpBalance(provider common.Address, chainID uint32, token common.Address) *big.Int {
+	if tb.LPBalances[chainID] == nil {
+		tb.LPBalances[chainID] = make(map[common.Address]map[common.Address]*big.Int)
+	}
+	if tb.LPBalances[chainID][token] == nil {
+		tb.LPBalances[chainID][token] = make(map[common.Address]*big.Int)
+	}
+	balance := tb.LPBalances[chainID][token][provider]
+	if balance == nil {
+		balance = big.NewInt(0)
+		tb.LPBalances[chainID][token][provider] = balance
+	}
+	return balance
+}
+
+// creditLP adds to a provider's balance and the chain/token liquidity total
+func (tb *TeleportBridge) creditLP(provider common.Address, chainID uint32, token common.Address, amount *big.Int) {
+	balance := tb.lpBalance(provider, chainID, token)
+	balance.Add(balance, amount)
+
+	if tb.Liquidity[chainID] == nil {
+		tb.Liquidity[chainID] = make(map[common.Address]*big.Int)
+	}
+	if tb.Liquidity[chainID][token] == nil {
+		tb.Liquidity[chainID][token] = big.NewInt(0)
+	}
+	tb.Liquidity[chainID][token].Add(tb.Liquidity[chainID][token], amount)
+}
+
+// releaseEscrow debits a fronted amount from the source chain's escrow
+func (tb *TeleportBridge) releaseEscrow(fronted *FrontedTeleport) error {
+	escrow := tb.getTokenConfig(fronted.SourceChain, fronted.Token)
+	if escrow == nil || escrow.TotalLocked.Cmp(fronted.Amount) < 0 {
+		return ErrInsufficientLiquidity
+	}
+	escrow.TotalLocked.Sub(escrow.TotalLocked, fronted.Amount)
+	return nil
+}
+
+// repayFronted returns a fronted amount to its LP and closes the teleport,
+// so neither the request nor the fronted record can complete again
+func (tb *TeleportBridge) repayFronted(fronted *FrontedTeleport) {
+	tb.creditLP(fronted.Provider, fronted.DestChain, fronted.Token, fronted.Amount)
+	tb.CompletedTeleports[fronted.TeleportID] = true
+	delete(tb.FrontedTeleports, fronted.TeleportID)
+	delete(tb.PendingTeleports, fronted.TeleportID)
+}
+
 func (tb *TeleportBridge) isChainSupported(chainID uint32) bool {
 	switch chainID {
 	case ChainLux, ChainHanzo, ChainZoo, ChainETH, ChainArb, ChainOP, ChainBase, ChainPoly, ChainBSC, ChainAvax:
@@ -401,10 +659,29 @@ func (tb *TeleportBridge) generateTeleportID(
 	return id
 }
 
-func (tb *TeleportBridge) verifyWarpMessage(message []byte, teleportID [32]byte) bool {
-	// In production, this would call the Warp precompile to verify
-	// For now, just check message is non-empty
-	return len(message) > 0
+// verifyWarpMessage verifies message with the configured WarpVerifier and
+// returns its payload, which must be for teleportID
+func (tb *TeleportBridge) verifyWarpMessage(message []byte, signatures [][]byte, teleportID [32]byte) (*TeleportPayload, error) {
+	if tb.warpVerifier == nil {
+		return nil, ErrWarpVerifierUnset
+	}
+	if len(message) == 0 {
+		return nil, ErrInvalidWarpSignature
+	}
+	payload, err := tb.warpVerifier(message, signatures)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWarpSignature, err)
+	}
+	if payload == nil || payload.TeleportID != teleportID {
+		return nil, ErrInvalidWarpSignature
+	}
+	return payload, nil
+}
+
+// matches reports whether p transfers amount of token to recipient
+func (p *TeleportPayload) matches(recipient, token common.Address, amount *big.Int) bool {
+	return p.Recipient == recipient && p.Token == token &&
+		p.Amount != nil && amount != nil && p.Amount.Cmp(amount) == 0
 }
 
 // NewOmnichainRouter creates a new multi-chain router
@@ -540,4 +817,7 @@ var (
 	ErrInsufficientSignatures = errors.New("insufficient signatures")
 	ErrCannotCancel           = errors.New("cannot cancel teleport in current state")
 	ErrNoRouteFound           = errors.New("no route found")
+	ErrSlippageExceeded       = errors.New("route fees exceed max slippage")
+	ErrTeleportMismatch       = errors.New("teleport does not match pending request")
+	ErrWarpVerifierUnset      = errors.New("warp verifier not configured")
 )
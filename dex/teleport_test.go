@@ -0,0 +1,302 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Test addresses for teleport
+var (
+	testTeleportToken = common.HexToAddress("0x1010101010101010101010101010101010101010")
+	testTeleportLP    = common.HexToAddress("0x2020202020202020202020202020202020202020")
+)
+
+// newTestTeleportBridge returns a bridge with a settable clock, no bridge
+// fee, the test token supported on ChainETH, an LP holding 500 tokens on
+// ChainLux, and a Warp verifier that accepts the messages in payloads
+func newTestTeleportBridge(t *testing.T, now *int64) (*TeleportBridge, map[string]*TeleportPayload) {
+	t.Helper()
+
+	tb := NewTeleportBridge(1)
+	tb.now = func() int64 { return *now }
+	tb.FeeRate = 0
+	tb.MinFee = big.NewInt(0)
+
+	if err := tb.AddSupportedToken(ChainETH, testTeleportToken, testTeleportToken, 18,
+		big.NewInt(1_000_000), big.NewInt(10_000), big.NewInt(1)); err != nil {
+		t.Fatalf("AddSupportedToken failed: %v", err)
+	}
+	if err := tb.ProvideLiquidity(testTeleportLP, ChainLux, testTeleportToken, big.NewInt(500)); err != nil {
+		t.Fatalf("ProvideLiquidity failed: %v", err)
+	}
+
+	payloads := make(map[string]*TeleportPayload)
+	tb.SetWarpVerifier(func(message []byte, signatures [][]byte) (*TeleportPayload, error) {
+		payload, ok := payloads[string(message)]
+		if !ok {
+			return nil, ErrInvalidWarpSignature
+		}
+		return payload, nil
+	})
+	return tb, payloads
+}
+
+// initiateTestTeleport escrows amount on ChainETH for testUser1 on ChainLux
+func initiateTestTeleport(t *testing.T, tb *TeleportBridge, amount int64) [32]byte {
+	t.Helper()
+	request, err := tb.InitiateTeleport(testUser2, ChainLux, testUser1, testTeleportToken, big.NewInt(amount), ChainETH)
+	if err != nil {
+		t.Fatalf("InitiateTeleport failed: %v", err)
+	}
+	return request.TeleportID
+}
+
+// frontTestTeleport initiates a teleport of amount and fronts it from the
+// test LP, returning its ID
+func frontTestTeleport(t *testing.T, tb *TeleportBridge, amount int64) [32]byte {
+	t.Helper()
+	id := initiateTestTeleport(t, tb, amount)
+	if _, err := tb.InstantTeleport(testTeleportLP, id, ChainETH, ChainLux, testUser1, testTeleportToken, big.NewInt(amount)); err != nil {
+		t.Fatalf("InstantTeleport failed: %v", err)
+	}
+	return id
+}
+
+// warpPayload registers a Warp message attesting to a teleport of amount
+// to recipient under id and returns it
+func warpPayload(payloads map[string]*TeleportPayload, id [32]byte, recipient common.Address, amount int64) []byte {
+	message := append([]byte("warp"), id[:]...)
+	message = append(message, recipient[:]...)
+	message = append(message, big.NewInt(amount).Bytes()...)
+	payloads[string(message)] = &TeleportPayload{
+		TeleportID: id,
+		Recipient:  recipient,
+		Token:      testTeleportToken,
+		Amount:     big.NewInt(amount),
+	}
+	return message
+}
+
+func escrowLocked(tb *TeleportBridge) int64 {
+	return tb.getTokenConfig(ChainETH, testTeleportToken).TotalLocked.Int64()
+}
+
+func TestTeleport_InstantPayout(t *testing.T) {
+	now := int64(1000)
+	tb, _ := newTestTeleportBridge(t, &now)
+
+	id := initiateTestTeleport(t, tb, 200)
+	payout, err := tb.InstantTeleport(testTeleportLP, id, ChainETH, ChainLux, testUser1, testTeleportToken, big.NewInt(200))
+	if err != nil {
+		t.Fatalf("InstantTeleport failed: %v", err)
+	}
+
+	// The 0.1% fee on 200 rounds down to zero
+	if payout.Int64() != 200 {
+		t.Fatalf("expected payout 200, got %s", payout)
+	}
+	if bal := tb.GetLPBalance(testTeleportLP, ChainLux, testTeleportToken); bal.Int64() != 300 {
+		t.Fatalf("expected LP balance 300, got %s", bal)
+	}
+	if tb.FrontedTeleports[id] == nil || !tb.PendingTeleports[id].Fronted {
+		t.Fatal("fronted teleport should be tracked")
+	}
+
+	// Same ID cannot be fronted twice
+	_, err = tb.InstantTeleport(testTeleportLP, id, ChainETH, ChainLux, testUser1, testTeleportToken, big.NewInt(200))
+	if err != ErrDuplicateTeleportID {
+		t.Fatalf("expected ErrDuplicateTeleportID, got %v", err)
+	}
+
+	// Nor can the sender cancel it and recover the escrow
+	if err := tb.CancelTeleport(testUser2, id); err != ErrCannotCancel {
+		t.Fatalf("expected ErrCannotCancel, got %v", err)
+	}
+
+	// LP cannot front more than it holds
+	large := initiateTestTeleport(t, tb, 301)
+	_, err = tb.InstantTeleport(testTeleportLP, large, ChainETH, ChainLux, testUser1, testTeleportToken, big.NewInt(301))
+	if err != ErrInsufficientLiquidity {
+		t.Fatalf("expected ErrInsufficientLiquidity, got %v", err)
+	}
+}
+
+func TestTeleport_InstantRequiresPendingRequest(t *testing.T) {
+	now := int64(1000)
+	tb, _ := newTestTeleportBridge(t, &now)
+
+	// A teleport that was never initiated cannot be fronted
+	_, err := tb.InstantTeleport(testTeleportLP, [32]byte{9}, ChainETH, ChainLux, testUser1, testTeleportToken, big.NewInt(100))
+	if err != ErrTeleportNotFound {
+		t.Fatalf("expected ErrTeleportNotFound, got %v", err)
+	}
+
+	// Every field must match the escrowed request
+	id := initiateTestTeleport(t, tb, 100)
+	mismatches := []struct {
+		name      string
+		source    uint32
+		recipient common.Address
+		token     common.Address
+		amount    int64
+	}{
+		{"source chain", ChainArb, testUser1, testTeleportToken, 100},
+		{"recipient", ChainETH, testTeleportLP, testTeleportToken, 100},
+		{"token", ChainETH, testUser1, testUser2, 100},
+		{"amount", ChainETH, testUser1, testTeleportToken, 99},
+	}
+	for _, m := range mismatches {
+		_, err := tb.InstantTeleport(testTeleportLP, id, m.source, ChainLux, m.recipient, m.token, big.NewInt(m.amount))
+		if err != ErrTeleportMismatch {
+			t.Fatalf("%s: expected ErrTeleportMismatch, got %v", m.name, err)
+		}
+	}
+	if bal := tb.GetLPBalance(testTeleportLP, ChainLux, testTeleportToken); bal.Int64() != 500 {
+		t.Fatalf("rejected fronts should not move LP funds, balance %s", bal)
+	}
+}
+
+func TestTeleport_InstantFee(t *testing.T) {
+	now := int64(1000)
+	tb, payloads := newTestTeleportBridge(t, &now)
+	tb.ProvideLiquidity(testTeleportLP, ChainLux, testTeleportToken, big.NewInt(9500))
+
+	id := initiateTestTeleport(t, tb, 10_000)
+	payout, err := tb.InstantTeleport(testTeleportLP, id, ChainETH, ChainLux, testUser1, testTeleportToken, big.NewInt(10_000))
+	if err != nil {
+		t.Fatalf("InstantTeleport failed: %v", err)
+	}
+	if payout.Int64() != 9990 {
+		t.Fatalf("expected payout 9990, got %s", payout)
+	}
+
+	// Reconciliation repays the full amount, leaving the LP up by the fee
+	msg := warpPayload(payloads, id, testUser1, 10_000)
+	if err := tb.ReconcileTeleport(id, msg, [][]byte{{1}}); err != nil {
+		t.Fatalf("ReconcileTeleport failed: %v", err)
+	}
+	if bal := tb.GetLPBalance(testTeleportLP, ChainLux, testTeleportToken); bal.Int64() != 10_010 {
+		t.Fatalf("expected LP balance 10010, got %s", bal)
+	}
+}
+
+func TestTeleport_ReconcileRepaysLP(t *testing.T) {
+	now := int64(1000)
+	tb, payloads := newTestTeleportBridge(t, &now)
+
+	id := frontTestTeleport(t, tb, 200)
+	msg := warpPayload(payloads, id, testUser1, 200)
+
+	if err := tb.ReconcileTeleport(id, msg, nil); err != ErrInsufficientSignatures {
+		t.Fatalf("expected ErrInsufficientSignatures, got %v", err)
+	}
+	if err := tb.ReconcileTeleport(id, nil, [][]byte{{1}}); err != ErrInvalidWarpSignature {
+		t.Fatalf("expected ErrInvalidWarpSignature, got %v", err)
+	}
+	if err := tb.ReconcileTeleport(id, []byte("forged"), [][]byte{{1}}); !errors.Is(err, ErrInvalidWarpSignature) {
+		t.Fatalf("expected ErrInvalidWarpSignature, got %v", err)
+	}
+
+	// The message must be for this teleport and attest to what the LP
+	// fronted
+	other := initiateTestTeleport(t, tb, 50)
+	if err := tb.ReconcileTeleport(id, warpPayload(payloads, other, testUser1, 200), [][]byte{{1}}); err != ErrInvalidWarpSignature {
+		t.Fatalf("expected ErrInvalidWarpSignature, got %v", err)
+	}
+	for _, bad := range [][]byte{
+		warpPayload(payloads, id, testTeleportLP, 200),
+		warpPayload(payloads, id, testUser1, 201),
+	} {
+		if err := tb.ReconcileTeleport(id, bad, [][]byte{{1}}); err != ErrTeleportMismatch {
+			t.Fatalf("expected ErrTeleportMismatch, got %v", err)
+		}
+	}
+
+	locked := escrowLocked(tb)
+	if err := tb.ReconcileTeleport(id, msg, [][]byte{{1}}); err != nil {
+		t.Fatalf("ReconcileTeleport failed: %v", err)
+	}
+	if bal := tb.GetLPBalance(testTeleportLP, ChainLux, testTeleportToken); bal.Int64() != 500 {
+		t.Fatalf("expected LP balance restored to 500, got %s", bal)
+	}
+	if got := escrowLocked(tb); got != locked-200 {
+		t.Fatalf("expected escrow debited to %d, got %d", locked-200, got)
+	}
+	if tb.FrontedTeleports[id] != nil || tb.PendingTeleports[id] != nil {
+		t.Fatal("reconciled teleport should no longer be outstanding")
+	}
+	if status, _ := tb.GetTeleportStatus(id); status != TeleportMinted {
+		t.Fatalf("expected completed status, got %d", status)
+	}
+
+	// A replayed message finds nothing to settle
+	if err := tb.ReconcileTeleport(id, msg, [][]byte{{1}}); err != ErrTeleportNotFound {
+		t.Fatalf("expected ErrTeleportNotFound on replay, got %v", err)
+	}
+	if _, err := tb.InstantTeleport(testTeleportLP, id, ChainETH, ChainLux, testUser1, testTeleportToken, big.NewInt(200)); err != ErrDuplicateTeleportID {
+		t.Fatalf("expected ErrDuplicateTeleportID for completed ID, got %v", err)
+	}
+	if err := tb.CompleteTeleport(id, msg, [][]byte{{1}}); err != ErrTeleportNotFound {
+		t.Fatalf("expected ErrTeleportNotFound for completed ID, got %v", err)
+	}
+}
+
+func TestTeleport_NoWarpVerifier(t *testing.T) {
+	now := int64(1000)
+	tb, payloads := newTestTeleportBridge(t, &now)
+	tb.SetWarpVerifier(nil)
+
+	id := frontTestTeleport(t, tb, 200)
+	msg := warpPayload(payloads, id, testUser1, 200)
+	if err := tb.ReconcileTeleport(id, msg, [][]byte{{1}}); err != ErrWarpVerifierUnset {
+		t.Fatalf("expected ErrWarpVerifierUnset, got %v", err)
+	}
+
+	other := initiateTestTeleport(t, tb, 100)
+	if err := tb.BurnForTeleport(other); err != nil {
+		t.Fatalf("BurnForTeleport failed: %v", err)
+	}
+	if err := tb.CompleteTeleport(other, warpPayload(payloads, other, testUser1, 100), [][]byte{{1}}); err != ErrWarpVerifierUnset {
+		t.Fatalf("expected ErrWarpVerifierUnset, got %v", err)
+	}
+}
+
+func TestTeleport_TimeoutClawback(t *testing.T) {
+	now := int64(1000)
+	tb, payloads := newTestTeleportBridge(t, &now)
+
+	id := frontTestTeleport(t, tb, 200)
+	if err := tb.ClawbackTeleport(testTeleportLP, id); err != ErrTeleportNotFinalized {
+		t.Fatalf("expected ErrTeleportNotFinalized before deadline, got %v", err)
+	}
+
+	now += tb.InstantTimeout
+	if err := tb.ClawbackTeleport(testUser1, id); err != ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+	if err := tb.ClawbackTeleport(testTeleportLP, id); err != nil {
+		t.Fatalf("ClawbackTeleport failed: %v", err)
+	}
+
+	if bal := tb.GetLPBalance(testTeleportLP, ChainLux, testTeleportToken); bal.Int64() != 500 {
+		t.Fatalf("expected LP balance restored to 500, got %s", bal)
+	}
+	if locked := escrowLocked(tb); locked != 0 {
+		t.Fatalf("expected escrow released, got %d", locked)
+	}
+
+	// The late Warp message cannot pay the LP twice
+	if err := tb.ReconcileTeleport(id, warpPayload(payloads, id, testUser1, 200), [][]byte{{1}}); err != ErrTeleportNotFound {
+		t.Fatalf("expected ErrTeleportNotFound after clawback, got %v", err)
+	}
+	// Nor can the original request be completed or fronted again
+	if tb.PendingTeleports[id] != nil {
+		t.Fatal("clawed back teleport should be closed")
+	}
+}
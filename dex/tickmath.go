@@ -0,0 +1,133 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+)
+
+// Uniswap v3 TickMath: sqrt(1.0001^tick) in Q64.96, computed bit-for-bit as
+// the Solidity library does so routers, limit orders and position managers
+// agree with pools.
+
+// Tick bounds: 1.0001^tick spans the prices representable in Q64.96
+const (
+	MinTick int24 = -887272
+	MaxTick int24 = -MinTick
+)
+
+// MinUsableTick returns the lowest tick a position may use in a pool with
+// tickSpacing: MinTick rounded up to a multiple of the spacing
+func MinUsableTick(tickSpacing int24) int24 {
+	return MinTick / tickSpacing * tickSpacing
+}
+
+// MaxUsableTick returns the highest tick a position may use in a pool with
+// tickSpacing: MaxTick rounded down to a multiple of the spacing
+func MaxUsableTick(tickSpacing int24) int24 {
+	return MaxTick / tickSpacing * tickSpacing
+}
+
+var (
+	// MinSqrtRatio is GetSqrtRatioAtTick(MinTick)
+	MinSqrtRatio = new(big.Int).SetUint64(4295128739)
+	// MaxSqrtRatio is GetSqrtRatioAtTick(MaxTick)
+	MaxSqrtRatio, _ = new(big.Int).SetString("1461446703485210103287273052203988822378723970342", 10)
+)
+
+// tickRatios[i] is sqrt(1.0001^-(2^i)) in Q128.128
+var tickRatios = func() []*big.Int {
+	hex := []string{
+		"fffcb933bd6fad37aa2d162d1a594001",
+		"fff97272373d413259a46990580e213a",
+		"fff2e50f5f656932ef12357cf3c7fdcc",
+		"ffe5caca7e10e4e61c3624eaa0941cd0",
+		"ffcb9843d60f6159c9db58835c926644",
+		"ff973b41fa98c081472e6896dfb254c0",
+		"ff2ea16466c96a3843ec78b326b52861",
+		"fe5dee046a99a2a811c461f1969c3053",
+		"fcbe86c7900a88aedcffc83b479aa3a4",
+		"f987a7253ac413176f2b074cf7815e54",
+		"f3392b0822b70005940c7a398e4b70f3",
+		"e7159475a2c29b7443b29c7fa6e889d9",
+		"d097f3bdfd2022b8845ad8f792aa5825",
+		"a9f746462d870fdf8a65dc1f90e061e5",
+		"70d869a156d2a1b890bb3df62baf32f7",
+		"31be135f97d08fd981231505542fcfa6",
+		"9aa508b5b7a84e1c677de54f3e99bc9",
+		"5d6af8dedb81196699c329225ee604",
+		"2216e584f5fa1ea926041bedfe98",
+		"48a170391f7dc42444e8fa2",
+	}
+	ratios := make([]*big.Int, len(hex))
+	for i, h := range hex {
+		ratios[i], _ = new(big.Int).SetString(h, 16)
+	}
+	return ratios
+}()
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// GetSqrtRatioAtTick returns sqrt(1.0001^tick) * 2^96, rounded up as in
+// Uniswap v3. It fails for ticks outside [MinTick, MaxTick].
+func GetSqrtRatioAtTick(tick int24) (*big.Int, error) {
+	if tick < MinTick || tick > MaxTick {
+		return nil, ErrTickOutOfRange
+	}
+	absTick := tick
+	if tick < 0 {
+		absTick = -tick
+	}
+
+	ratio := new(big.Int).Set(Q128)
+	for i, r := range tickRatios {
+		if absTick&(1<<i) != 0 {
+			ratio.Mul(ratio, r)
+			ratio.Rsh(ratio, 128)
+		}
+	}
+	if tick > 0 {
+		ratio.Div(maxUint256, ratio)
+	}
+
+	// Q128.128 to Q64.96, rounding up so GetTickAtSqrtRatio is consistent
+	sqrtPriceX96 := new(big.Int).Rsh(ratio, 32)
+	if new(big.Int).And(ratio, big.NewInt(0xffffffff)).Sign() != 0 {
+		sqrtPriceX96.Add(sqrtPriceX96, big.NewInt(1))
+	}
+	return sqrtPriceX96, nil
+}
+
+// GetTickAtSqrtRatio returns the greatest tick whose sqrt ratio is at most
+// sqrtPriceX96. sqrtPriceX96 must be in [MinSqrtRatio, MaxSqrtRatio).
+func GetTickAtSqrtRatio(sqrtPriceX96 *big.Int) (int24, error) {
+	if sqrtPriceX96 == nil || sqrtPriceX96.Cmp(MinSqrtRatio) < 0 || sqrtPriceX96.Cmp(MaxSqrtRatio) >= 0 {
+		return 0, ErrInvalidSqrtPrice
+	}
+
+	// Binary search: GetSqrtRatioAtTick(low) <= sqrtPriceX96 < GetSqrtRatioAtTick(low+1)
+	low, high := MinTick, MaxTick
+	for low < high {
+		mid := low + (high-low+1)/2
+		sqrtPriceMid, _ := GetSqrtRatioAtTick(mid)
+		if sqrtPriceMid.Cmp(sqrtPriceX96) <= 0 {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	return low, nil
+}
+
+// sqrtPriceX96FromPrice returns floor(sqrt(price) * 2^96), where price is
+// currency1 per currency0
+func sqrtPriceX96FromPrice(price *big.Rat) (*big.Int, error) {
+	if price == nil || price.Sign() <= 0 {
+		return nil, ErrInvalidSqrtPrice
+	}
+	// floor(sqrt(x)) == floor(sqrt(floor(x))) for x >= 0
+	scaled := new(big.Int).Lsh(price.Num(), 192)
+	scaled.Div(scaled, price.Denom())
+	return scaled.Sqrt(scaled), nil
+}
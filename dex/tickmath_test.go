@@ -0,0 +1,111 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+	"testing"
+)
+
+func mustSqrtRatio(t *testing.T, tick int24) *big.Int {
+	t.Helper()
+	sqrtPriceX96, err := GetSqrtRatioAtTick(tick)
+	if err != nil {
+		t.Fatalf("GetSqrtRatioAtTick(%d) failed: %v", tick, err)
+	}
+	return sqrtPriceX96
+}
+
+// TestGetSqrtRatioAtTickReference checks values from the Uniswap v3
+// TickMath test suite
+func TestGetSqrtRatioAtTickReference(t *testing.T) {
+	tests := []struct {
+		tick int24
+		want string
+	}{
+		{MinTick, "4295128739"},
+		{MinTick + 1, "4295343490"},
+		{0, "79228162514264337593543950336"},
+		{MaxTick - 1, "1461373636630004318706518188784493106690254656249"},
+		{MaxTick, "1461446703485210103287273052203988822378723970342"},
+	}
+	for _, tt := range tests {
+		want, _ := new(big.Int).SetString(tt.want, 10)
+		if got := mustSqrtRatio(t, tt.tick); got.Cmp(want) != 0 {
+			t.Errorf("GetSqrtRatioAtTick(%d) = %s, want %s", tt.tick, got, want)
+		}
+	}
+
+	if mustSqrtRatio(t, MinTick).Cmp(MinSqrtRatio) != 0 {
+		t.Error("MinSqrtRatio does not match MinTick")
+	}
+	if mustSqrtRatio(t, MaxTick).Cmp(MaxSqrtRatio) != 0 {
+		t.Error("MaxSqrtRatio does not match MaxTick")
+	}
+
+	for _, tick := range []int24{MinTick - 1, MaxTick + 1} {
+		if _, err := GetSqrtRatioAtTick(tick); err != ErrTickOutOfRange {
+			t.Errorf("GetSqrtRatioAtTick(%d): expected ErrTickOutOfRange, got: %v", tick, err)
+		}
+	}
+}
+
+func TestGetTickAtSqrtRatioBoundaries(t *testing.T) {
+	tests := []struct {
+		name         string
+		sqrtPriceX96 *big.Int
+		want         int24
+	}{
+		{"min ratio", MinSqrtRatio, MinTick},
+		{"min ratio + 1", new(big.Int).Add(MinSqrtRatio, big.NewInt(1)), MinTick},
+		{"ratio of min tick + 1", mustSqrtRatio(t, MinTick+1), MinTick + 1},
+		{"ratio of max tick - 1", mustSqrtRatio(t, MaxTick-1), MaxTick - 1},
+		{"max ratio - 1", new(big.Int).Sub(MaxSqrtRatio, big.NewInt(1)), MaxTick - 1},
+	}
+	for _, tt := range tests {
+		got, err := GetTickAtSqrtRatio(tt.sqrtPriceX96)
+		if err != nil {
+			t.Fatalf("%s: GetTickAtSqrtRatio failed: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: tick = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// The upper bound is exclusive, as in Uniswap v3
+	for _, sqrtPriceX96 := range []*big.Int{nil, new(big.Int).Sub(MinSqrtRatio, big.NewInt(1)), MaxSqrtRatio} {
+		if _, err := GetTickAtSqrtRatio(sqrtPriceX96); err != ErrInvalidSqrtPrice {
+			t.Errorf("GetTickAtSqrtRatio(%v): expected ErrInvalidSqrtPrice, got: %v", sqrtPriceX96, err)
+		}
+	}
+}
+
+func TestTickMathRoundTrip(t *testing.T) {
+	ticks := []int24{MinTick, MinTick + 1, -500000, -76012, -60, -1, 0, 1, 60, 76012, 500000, MaxTick - 1}
+	for tick := MinTick; tick < MaxTick; tick += 7919 {
+		ticks = append(ticks, tick)
+	}
+
+	for _, tick := range ticks {
+		sqrtPriceX96 := mustSqrtRatio(t, tick)
+		got, err := GetTickAtSqrtRatio(sqrtPriceX96)
+		if err != nil {
+			t.Fatalf("GetTickAtSqrtRatio at tick %d failed: %v", tick, err)
+		}
+		if got != tick {
+			t.Errorf("round trip of tick %d gave %d", tick, got)
+		}
+
+		// Just below the tick's ratio belongs to the tick below
+		if tick > MinTick {
+			below, err := GetTickAtSqrtRatio(new(big.Int).Sub(sqrtPriceX96, big.NewInt(1)))
+			if err != nil {
+				t.Fatalf("GetTickAtSqrtRatio below tick %d failed: %v", tick, err)
+			}
+			if below != tick-1 {
+				t.Errorf("ratio below tick %d gave %d, want %d", tick, below, tick-1)
+			}
+		}
+	}
+}
@@ -17,18 +17,24 @@ var transmuterAddr = common.HexToAddress(LiquidFXAddress)
 
 // Storage key prefixes for Transmuter state
 var (
-	transmuterStatePrefix = []byte("xmut/state")
-	transmuterStakePrefix = []byte("xmut/stake")
-	transmuterQueuePrefix = []byte("xmut/queue")
+	transmuterStatePrefix  = []byte("xmut/state")
+	transmuterStakePrefix  = []byte("xmut/stake")
+	transmuterQueuePrefix  = []byte("xmut/queue")
+	transmuterGlobalPrefix = []byte("xmut/glob")
 )
 
+// transmuterLockKey is the storage slot of the Transmuter reentrancy lock
+var transmuterLockKey = makeStorageKey(transmuterGlobalPrefix, []byte("lock"))
+
 // Transmuter allows conversion of liquid tokens back to underlying assets
 // Based on Alchemix's Transmuter design:
 // 1. Users stake liquid tokens (e.g., LUSD) in the transmuter
 // 2. As underlying flows in (from yield harvesting), staked liquidTokens convert
 // 3. Users can claim their proportional share of underlying
 //
-// This provides an exit mechanism from liquidTokens without market selling
+// This provides an exit mechanism from liquidTokens without market selling.
+// Mutating operations hold a storage reentrancy lock and move tokens only
+// after all state has been saved (checks-effects-interactions).
 type Transmuter struct {
 	mu sync.RWMutex
 
@@ -49,6 +55,7 @@ type TransmuterStake struct {
 	StakedAmount    *big.Int // Amount of liquid staked
 	UnclaimedAmount *big.Int // Underlying available to claim
 	LastUpdateIndex *big.Int // Index at last update (for pro-rata)
+	AccrualDust     *big.Int // Sub-unit accrual remainder (Q96-scaled), carried into the next update
 }
 
 // NewTransmuter creates a new Transmuter instance
@@ -93,6 +100,7 @@ func (t *Transmuter) InitializeTransmuter(
 		ExchangeBuffer:  big.NewInt(0),
 		TotalStaked:     big.NewInt(0),
 		ExchangeRate:    new(big.Int).Set(Q96), // 1:1 initial rate
+		DustBuffer:      big.NewInt(0),
 	}
 
 	t.states[liquidToken] = state
@@ -113,6 +121,12 @@ func (t *Transmuter) Stake(
 	liquidToken common.Address,
 	amount *big.Int,
 ) error {
+	release, err := nonReentrant(stateDB, transmuterAddr, transmuterLockKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -125,25 +139,135 @@ func (t *Transmuter) Stake(
 		return ErrInvalidPositionSize
 	}
 
+	t.addStake(stateDB, state, owner, amount)
+
+	// Transfer liquid tokens from user
+	t.transferSynthetic(stateDB, liquidToken, owner, transmuterAddr, amount)
+
+	return nil
+}
+
+// Zap deposits depositAmount of yieldToken as collateral, mints mintAmount
+// of liquidToken against it and stakes the minted tokens, net of the mint
+// fee, in the transmuter, all in one call. Every check of the three steps
+// runs before any state changes, so a failing step (e.g. mintAmount above
+// the LTV the deposit allows) leaves collateral, debt and stake untouched.
+func (t *Transmuter) Zap(
+	stateDB StateDB,
+	owner common.Address,
+	yieldToken common.Address,
+	liquidToken common.Address,
+	depositAmount *big.Int,
+	mintAmount *big.Int,
+) error {
+	if depositAmount == nil || depositAmount.Sign() <= 0 ||
+		mintAmount == nil || mintAmount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+
+	release, err := nonReentrant(stateDB, transmuterAddr, transmuterLockKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+	releaseLiquid, err := nonReentrant(stateDB, liquidAddr, liquidLockKey)
+	if err != nil {
+		return err
+	}
+	defer releaseLiquid()
+
+	a := t.alchemist
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	yt, exists := a.yieldTokens[yieldToken]
+	if !exists || !yt.IsActive {
+		return ErrInvalidYieldToken
+	}
+	st, exists := a.liquidTokens[liquidToken]
+	if !exists {
+		return ErrLiquidTokenNotRegistered
+	}
+	state, exists := t.states[liquidToken]
+	if !exists {
+		return ErrLiquidTokenNotRegistered
+	}
+
+	newTotalMinted := new(big.Int).Add(st.TotalMinted, mintAmount)
+	if newTotalMinted.Cmp(st.DebtCeiling) > 0 {
+		return ErrDebtCeilingExceeded
+	}
+
+	// Work on a copy of the account so a failed check changes nothing
+	key := accountKey(owner, yieldToken)
+	account := &LiquidAccount{
+		Owner:        owner,
+		YieldToken:   yieldToken,
+		Collateral:   big.NewInt(0),
+		Debt:         big.NewInt(0),
+		AccruedYield: big.NewInt(0),
+	}
+	if existing := a.getAccount(stateDB, key); existing != nil {
+		account.Collateral.Set(existing.Collateral)
+		account.Debt.Set(existing.Debt)
+		account.AccruedYield.Set(existing.AccruedYield)
+		account.LastHarvestBlock = existing.LastHarvestBlock
+	}
+	a.harvestYieldInternal(stateDB, account, yt)
+	account.Collateral.Add(account.Collateral, depositAmount)
+
+	collateralValue, err := a.getCollateralValue(stateDB, account.Collateral, yt)
+	if err != nil {
+		return err
+	}
+	maxDebt := a.calculateMaxDebt(collateralValue)
+	account.Debt.Add(account.Debt, mintAmount)
+	if account.Debt.Cmp(maxDebt) > 0 {
+		return ErrMaxLTVExceeded
+	}
+	netMintAmount := new(big.Int).Sub(mintAmount, a.calculateFee(mintAmount, st.MintFee))
+
+	// Effects
+	yt.TotalDeposited = new(big.Int).Add(yt.TotalDeposited, depositAmount)
+	st.TotalMinted = newTotalMinted
+	a.saveAccount(stateDB, key, account)
+	a.saveYieldToken(stateDB, yt)
+	a.saveLiquidToken(stateDB, st)
+	if netMintAmount.Sign() > 0 {
+		t.addStake(stateDB, state, owner, netMintAmount)
+	}
+
+	// Interactions: the minted tokens go straight to the transmuter
+	a.transferFrom(stateDB, yieldToken, owner, liquidAddr, depositAmount)
+	if netMintAmount.Sign() > 0 {
+		a.mintSynthetic(stateDB, liquidToken, transmuterAddr, netMintAmount)
+	}
+
+	return nil
+}
+
+// addStake credits amount to owner's stake and the transmuter's total and
+// saves both; the caller moves the tokens
+func (t *Transmuter) addStake(stateDB StateDB, state *LiquidFXState, owner common.Address, amount *big.Int) {
 	// Get or create stake
-	key := stakeKey(liquidToken, owner)
+	key := stakeKey(state.LiquidToken, owner)
 	stake := t.getStake(stateDB, key)
 	if stake == nil {
 		stake = &TransmuterStake{
 			Owner:           owner,
-			LiquidToken:     liquidToken,
+			LiquidToken:     state.LiquidToken,
 			StakedAmount:    big.NewInt(0),
 			UnclaimedAmount: big.NewInt(0),
 			LastUpdateIndex: new(big.Int).Set(state.ExchangeRate),
+			AccrualDust:     big.NewInt(0),
 		}
 	}
 
 	// Update stake's unclaimed amount based on exchange rate change
 	t.updateStakeUnclaimed(stake, state)
 
-	// Transfer liquid tokens from user
-	t.transferSynthetic(stateDB, liquidToken, owner, transmuterAddr, amount)
-
 	// Update stake
 	stake.StakedAmount = new(big.Int).Add(stake.StakedAmount, amount)
 	stake.LastUpdateIndex = new(big.Int).Set(state.ExchangeRate)
@@ -154,8 +278,6 @@ func (t *Transmuter) Stake(
 	// Save state
 	t.saveStake(stateDB, key, stake)
 	t.saveState(stateDB, state)
-
-	return nil
 }
 
 // Unstake removes liquid tokens from transmutation queue
@@ -166,6 +288,12 @@ func (t *Transmuter) Unstake(
 	liquidToken common.Address,
 	amount *big.Int,
 ) error {
+	release, err := nonReentrant(stateDB, transmuterAddr, transmuterLockKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -194,13 +322,13 @@ func (t *Transmuter) Unstake(
 	// Update total staked
 	state.TotalStaked = new(big.Int).Sub(state.TotalStaked, amount)
 
-	// Transfer liquid tokens back to user
-	t.transferSynthetic(stateDB, liquidToken, transmuterAddr, owner, amount)
-
 	// Save state
 	t.saveStake(stateDB, key, stake)
 	t.saveState(stateDB, state)
 
+	// Transfer liquid tokens back to user
+	t.transferSynthetic(stateDB, liquidToken, transmuterAddr, owner, amount)
+
 	return nil
 }
 
@@ -211,6 +339,12 @@ func (t *Transmuter) Claim(
 	owner common.Address,
 	liquidToken common.Address,
 ) (*big.Int, error) {
+	release, err := nonReentrant(stateDB, transmuterAddr, transmuterLockKey)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -230,6 +364,9 @@ func (t *Transmuter) Claim(
 
 	claimAmount := new(big.Int).Set(stake.UnclaimedAmount)
 	if claimAmount.Sign() == 0 {
+		// The update may still have carried accrual dust
+		t.saveStake(stateDB, key, stake)
+		t.saveState(stateDB, state)
 		return big.NewInt(0), nil
 	}
 
@@ -242,13 +379,13 @@ func (t *Transmuter) Claim(
 	stake.UnclaimedAmount = new(big.Int).Sub(stake.UnclaimedAmount, claimAmount)
 	state.ExchangeBuffer = new(big.Int).Sub(state.ExchangeBuffer, claimAmount)
 
-	// Transfer underlying to user
-	t.transferUnderlying(stateDB, state.UnderlyingAsset, transmuterAddr, owner, claimAmount)
-
 	// Save state
 	t.saveStake(stateDB, key, stake)
 	t.saveState(stateDB, state)
 
+	// Transfer underlying to user
+	t.transferUnderlying(stateDB, state.UnderlyingAsset, transmuterAddr, owner, claimAmount)
+
 	return claimAmount, nil
 }
 
@@ -259,6 +396,12 @@ func (t *Transmuter) Deposit(
 	liquidToken common.Address,
 	underlyingAmount *big.Int,
 ) error {
+	release, err := nonReentrant(stateDB, transmuterAddr, transmuterLockKey)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
@@ -271,16 +414,31 @@ func (t *Transmuter) Deposit(
 		return nil
 	}
 
-	// Add to exchange buffer
-	state.ExchangeBuffer = new(big.Int).Add(state.ExchangeBuffer, underlyingAmount)
+	// Add to exchange buffer. The buffer is stored in 128 bits, and deposits
+	// nobody has staked for stay in it, which bounds the Q96-scaled dust
+	// held for them below 2^224.
+	buffer := new(big.Int).Add(state.ExchangeBuffer, underlyingAmount)
+	if buffer.BitLen() > 128 {
+		return ErrInvalidAmount
+	}
+	state.ExchangeBuffer = buffer
+
+	// Fold in the remainder left over by previous deposits
+	toDistribute := new(big.Int).Mul(underlyingAmount, Q96)
+	toDistribute.Add(toDistribute, state.DustBuffer)
 
 	// Update exchange rate if there are stakers
 	if state.TotalStaked.Sign() > 0 {
 		// exchangeRate increases as underlying flows in
-		// newRate = oldRate + (underlyingAmount * Q96 / totalStaked)
-		rateIncrease := new(big.Int).Mul(underlyingAmount, Q96)
-		rateIncrease.Div(rateIncrease, state.TotalStaked)
+		// newRate = oldRate + ((underlyingAmount * Q96 + dust) / totalStaked)
+		// The division remainder is exactly what this deposit could not
+		// distribute; keep it for the next one instead of losing it.
+		rateIncrease, dust := new(big.Int).QuoRem(toDistribute, state.TotalStaked, new(big.Int))
 		state.ExchangeRate = new(big.Int).Add(state.ExchangeRate, rateIncrease)
+		state.DustBuffer = dust
+	} else {
+		// Nobody to distribute to yet; hold everything for the next deposit
+		state.DustBuffer = toDistribute
 	}
 
 	t.saveState(stateDB, state)
@@ -343,6 +501,9 @@ func (t *Transmuter) GetClaimable(
 		rateDiff := new(big.Int).Sub(state.ExchangeRate, stake.LastUpdateIndex)
 		if rateDiff.Sign() > 0 {
 			newUnclaimed := new(big.Int).Mul(stake.StakedAmount, rateDiff)
+			if stake.AccrualDust != nil {
+				newUnclaimed.Add(newUnclaimed, stake.AccrualDust)
+			}
 			newUnclaimed.Div(newUnclaimed, Q96)
 			unclaimed.Add(unclaimed, newUnclaimed)
 		}
@@ -385,17 +546,32 @@ func (t *Transmuter) updateStakeUnclaimed(stake *TransmuterStake, state *LiquidF
 		return
 	}
 
-	// newUnclaimed = stakedAmount * rateDiff / Q96
-	newUnclaimed := new(big.Int).Mul(stake.StakedAmount, rateDiff)
-	newUnclaimed.Div(newUnclaimed, Q96)
+	// newUnclaimed = (stakedAmount * rateDiff + accrualDust) / Q96
+	// The sub-unit remainder stays with the stake for its next update.
+	accrued := new(big.Int).Mul(stake.StakedAmount, rateDiff)
+	if stake.AccrualDust != nil {
+		accrued.Add(accrued, stake.AccrualDust)
+	}
+	newUnclaimed, dust := new(big.Int).QuoRem(accrued, Q96, new(big.Int))
+	stake.AccrualDust = dust
 
 	stake.UnclaimedAmount = new(big.Int).Add(stake.UnclaimedAmount, newUnclaimed)
 
 	// Reduce staked amount by converted amount (liquidTokens are "burned" as they convert)
-	if newUnclaimed.Cmp(stake.StakedAmount) >= 0 {
-		stake.StakedAmount = big.NewInt(0)
-	} else {
-		stake.StakedAmount = new(big.Int).Sub(stake.StakedAmount, newUnclaimed)
+	burned := newUnclaimed
+	if burned.Cmp(stake.StakedAmount) > 0 {
+		burned = stake.StakedAmount
+	}
+	stake.StakedAmount = new(big.Int).Sub(stake.StakedAmount, burned)
+
+	// Burned liquid no longer shares in future deposits. Deposit divides by
+	// TotalStaked, so it has to stay the sum of the StakedAmounts: if burned
+	// liquid stayed in the total, every later deposit would assign part of
+	// its rate increase to stake nobody holds, and that part would be
+	// neither claimable nor tracked as dust.
+	state.TotalStaked = new(big.Int).Sub(state.TotalStaked, burned)
+	if state.TotalStaked.Sign() < 0 {
+		state.TotalStaked = big.NewInt(0)
 	}
 
 	stake.LastUpdateIndex = new(big.Int).Set(state.ExchangeRate)
@@ -417,10 +593,12 @@ func (t *Transmuter) getStake(stateDB StateDB, key [32]byte) *TransmuterStake {
 		return nil
 	}
 
+	dust := stateDB.GetState(transmuterAddr, transmuterDustKey(transmuterStakePrefix, key[:]))
 	stake := &TransmuterStake{
 		StakedAmount:    big.NewInt(0).SetBytes(data[:16]),
 		UnclaimedAmount: big.NewInt(0).SetBytes(data[16:]),
 		LastUpdateIndex: new(big.Int).Set(Q96),
+		AccrualDust:     new(big.Int).SetBytes(dust[:]),
 	}
 	t.stakes[key] = stake
 	return stake
@@ -436,6 +614,13 @@ func (t *Transmuter) saveStake(stateDB StateDB, key [32]byte, stake *TransmuterS
 	copy(data[:16], stakedBytes)
 	copy(data[16:], unclaimedBytes)
 	stateDB.SetState(transmuterAddr, storageKey, data)
+
+	// AccrualDust is below Q96, so it fits a slot of its own
+	var dust common.Hash
+	if stake.AccrualDust != nil {
+		stake.AccrualDust.FillBytes(dust[:])
+	}
+	stateDB.SetState(transmuterAddr, transmuterDustKey(transmuterStakePrefix, key[:]), dust)
 }
 
 func (t *Transmuter) saveState(stateDB StateDB, state *LiquidFXState) {
@@ -449,6 +634,23 @@ func (t *Transmuter) saveState(stateDB StateDB, state *LiquidFXState) {
 	copy(data[:16], bufferBytes)
 	copy(data[16:], totalBytes)
 	stateDB.SetState(transmuterAddr, storageKey, data)
+
+	// DustBuffer is below TotalStaked, or below (ExchangeBuffer + 1) * Q96
+	// before anyone stakes, so it fits a slot of its own. Like the rest of
+	// this record it is write-only: states live in t.states and are never
+	// reloaded from storage.
+	var dust common.Hash
+	state.DustBuffer.FillBytes(dust[:])
+	stateDB.SetState(transmuterAddr, transmuterDustKey(transmuterStatePrefix, state.LiquidToken.Bytes()), dust)
+}
+
+// transmuterDustKey derives the slot holding the rounding dust of the state
+// or stake record stored under prefix and id
+func transmuterDustKey(prefix []byte, id []byte) common.Hash {
+	dustId := make([]byte, 0, len(id)+4)
+	dustId = append(dustId, id...)
+	dustId = append(dustId, "dust"...)
+	return makeStorageKey(prefix, dustId)
 }
 
 // Token transfer helpers
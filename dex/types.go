@@ -25,6 +25,7 @@ const (
 	LXRouterAddress = "0x0000000000000000000000000000000000009012" // LP-9012 LXRouter (swap routing)
 	LXHooksAddress  = "0x0000000000000000000000000000000000009013" // LP-9013 LXHooks (hook registry)
 	LXFlashAddress  = "0x0000000000000000000000000000000000009014" // LP-9014 LXFlash (flash loans)
+	WLUXAddress     = "0x0000000000000000000000000000000000009016" // LP-9016 WLUX (wrapped native LUX)
 
 	// Trading & DeFi Extensions (LP-90xx)
 	LXBookAddress     = "0x0000000000000000000000000000000000009020" // LP-9020 LXBook (orderbook + matching)
@@ -37,6 +38,7 @@ const (
 
 	// Bridge Precompiles (LP-6xxx)
 	TeleportAddress = "0x0000000000000000000000000000000000006010" // LP-6010 Teleport (cross-chain)
+	FeeGovAddress   = "0x0000000000000000000000000000000000006221" // LP-6221 FeeGov (LP fee governance)
 
 	// Deprecated: Old addresses kept for migration reference only
 	// These will be removed in a future release
@@ -82,8 +84,9 @@ const (
 	GasTransmute uint64 = 25_000 // Convert liquid token to underlying
 
 	// Teleport operations
-	GasTeleportInit     uint64 = 50_000 // Initiate cross-chain transfer
-	GasTeleportComplete uint64 = 40_000 // Complete cross-chain transfer
+	GasTeleportInit     uint64 = 50_000  // Initiate cross-chain transfer
+	GasTeleportComplete uint64 = 40_000  // Complete cross-chain transfer
+	GasTeleportInstant  uint64 = 100_000 // LP-fronted instant teleport
 )
 
 // Pool fee tiers (basis points)
@@ -95,6 +98,10 @@ const (
 	FeeMax uint24 = 100000 // 10% max fee
 )
 
+// MaxProtocolFee caps the protocol's share of swap fees, in millionths of
+// the fee (250000 = a quarter of every swap fee)
+const MaxProtocolFee uint24 = 250000
+
 // Tick spacing for different fee tiers
 const (
 	TickSpacing001 int24 = 1
@@ -103,6 +110,48 @@ const (
 	TickSpacing100 int24 = 200
 )
 
+// Bounds on the tick spacing of a custom pool
+const (
+	MinTickSpacing int24 = 1
+	MaxTickSpacing int24 = 16384
+)
+
+// FeeTier is a fee paired with the tick spacing pools at that fee must use
+type FeeTier struct {
+	Fee         uint24
+	TickSpacing int24
+}
+
+// validFeeSpacings are the fee/tick spacing pairs Initialize accepts
+var validFeeSpacings = []FeeTier{
+	{Fee001, TickSpacing001},
+	{Fee005, TickSpacing005},
+	{Fee030, TickSpacing030},
+	{Fee100, TickSpacing100},
+}
+
+// SupportedFeeTiers returns the standard fee tiers, from lowest fee to
+// highest, with the tick spacing a pool at each fee must use
+func SupportedFeeTiers() []FeeTier {
+	tiers := make([]FeeTier, len(validFeeSpacings))
+	copy(tiers, validFeeSpacings)
+	return tiers
+}
+
+// isValidFeeSpacing reports whether a pool may pair fee with tickSpacing. A
+// fee from a supported tier must use that tier's spacing; any other fee
+// makes a custom pool, which may use any spacing from MinTickSpacing to
+// MaxTickSpacing, e.g. very tight for a stable pair or wide for a volatile
+// one.
+func isValidFeeSpacing(fee uint24, tickSpacing int24) bool {
+	for _, tier := range validFeeSpacings {
+		if tier.Fee == fee {
+			return tier.TickSpacing == tickSpacing
+		}
+	}
+	return tickSpacing >= MinTickSpacing && tickSpacing <= MaxTickSpacing
+}
+
 // Hook flags (bitmap for hook capabilities)
 type HookFlags uint16
 
@@ -130,11 +179,21 @@ type Currency struct {
 // NativeCurrency represents native LUX (no wrapping needed)
 var NativeCurrency = Currency{Address: common.Address{}}
 
+// WrappedNativeCurrency is WLUX, the ERC-20 form of native LUX for pools
+// and routes that do not handle native transfers. Its low LP address sorts
+// it before ordinary token addresses, just as native LUX sorts first.
+var WrappedNativeCurrency = Currency{Address: common.HexToAddress(WLUXAddress)}
+
 // IsNative returns true if this currency is native LUX
 func (c Currency) IsNative() bool {
 	return c.Address == common.Address{}
 }
 
+// IsWrappedNative returns true if this currency is WLUX
+func (c Currency) IsWrappedNative() bool {
+	return c == WrappedNativeCurrency
+}
+
 // ToBytes serializes currency for storage
 func (c Currency) ToBytes() []byte {
 	return c.Address.Bytes()
@@ -273,6 +332,9 @@ type Pool struct {
 	FeeGrowth1X128 *big.Int // Fee growth for currency1 (Q128.128)
 	ProtocolFees0  *big.Int // Accumulated protocol fees currency0
 	ProtocolFees1  *big.Int // Accumulated protocol fees currency1
+	LPFees0        *big.Int // Accumulated LP fees currency0
+	LPFees1        *big.Int // Accumulated LP fees currency1
+	ProtocolFee    uint24   // Protocol share of swap fees (millionths)
 }
 
 // IsInitialized returns true if the pool has been initialized
@@ -290,19 +352,49 @@ func NewPool() *Pool {
 		FeeGrowth1X128: big.NewInt(0),
 		ProtocolFees0:  big.NewInt(0),
 		ProtocolFees1:  big.NewInt(0),
+		LPFees0:        big.NewInt(0),
+		LPFees1:        big.NewInt(0),
 	}
 }
 
+// FeeBreakdown splits a pool's cumulative swap fees between liquidity
+// providers and the protocol
+type FeeBreakdown struct {
+	LPFees0       *big.Int
+	LPFees1       *big.Int
+	ProtocolFees0 *big.Int
+	ProtocolFees1 *big.Int
+}
+
 // Position represents a liquidity position
 type Position struct {
 	Owner                    common.Address
 	TickLower                int24
 	TickUpper                int24
+	Salt                     [32]byte
 	Liquidity                *big.Int
 	FeeGrowthInside0LastX128 *big.Int
 	FeeGrowthInside1LastX128 *big.Int
 	TokensOwed0              *big.Int
 	TokensOwed1              *big.Int
+
+	// Band donation growth inside the range at the last accrual
+	// (see DonateToRange)
+	RangeGrowthInside0LastX128 *big.Int
+	RangeGrowthInside1LastX128 *big.Int
+}
+
+// PositionInfo describes a liquidity position for callers that render it,
+// e.g. as NFT metadata
+type PositionInfo struct {
+	PositionKey [32]byte
+	PoolID      [32]byte
+	Owner       common.Address
+	TickLower   int24
+	TickUpper   int24
+	Liquidity   *big.Int
+	TokensOwed0 *big.Int // Fees owed in currency0
+	TokensOwed1 *big.Int // Fees owed in currency1
 }
 
 // PositionKey computes the unique position identifier
@@ -354,6 +446,8 @@ var (
 	ErrInsufficientLiquidity  = errors.New("insufficient liquidity")
 	ErrPriceLimitReached      = errors.New("price limit reached")
 	ErrInvalidFee             = errors.New("invalid fee")
+	ErrInvalidTickSpacing     = errors.New("unsupported fee and tick spacing pair")
+	ErrTickMisaligned         = errors.New("tick not a multiple of the pool's tick spacing")
 	ErrCurrencyNotSorted      = errors.New("currencies not sorted")
 	ErrFlashLoanNotRepaid     = errors.New("flash loan not repaid")
 	ErrUnauthorized           = errors.New("unauthorized")
@@ -364,6 +458,8 @@ var (
 	ErrTickOutOfRange         = errors.New("tick out of range")
 	ErrReentrant              = errors.New("reentrancy detected")
 	ErrNoLiquidity            = errors.New("no liquidity in pool")
+	ErrLiquidityOverflow      = errors.New("liquidity exceeds uint128")
+	ErrInvalidRecipient       = errors.New("invalid recipient")
 )
 
 // Errors - Lending
@@ -395,16 +491,58 @@ var (
 	ErrInvalidPositionSize  = errors.New("invalid position size")
 	ErrMarkPriceUnavailable = errors.New("mark price unavailable")
 	ErrBankruptPosition     = errors.New("position is bankrupt")
+	ErrRequestNotFound      = errors.New("position request not found")
+	ErrRequestExpired       = errors.New("position request expired")
+	ErrRequestNotExpired    = errors.New("position request not yet cancellable")
+	ErrPositionSideMismatch = errors.New("request direction does not match position")
+	ErrPriceSlippage        = errors.New("execution price outside acceptable price")
+	ErrStaleFastPrice       = errors.New("fast price is stale")
+	ErrPricesUnreliable     = errors.New("mark and index prices diverged")
+)
+
+// Errors - CLOB
+var (
+	ErrMarketNotFound   = errors.New("market not found")
+	ErrMarketExists     = errors.New("market already exists")
+	ErrInvalidOrderSize = errors.New("order size not aligned to lot size")
+	ErrOrderTooSmall    = errors.New("order size below market minimum")
+	ErrPriceNotAligned  = errors.New("price not aligned to tick size")
+	ErrOrderNotFound    = errors.New("order not found")
+	ErrSelfTrade        = errors.New("order would match the owner's resting order")
+	ErrOrderExpired     = errors.New("order expiry block already passed")
+)
+
+// Errors - FeeGov
+var (
+	ErrProposalNotFound = errors.New("proposal not found")
+	ErrNoVotingPower    = errors.New("no voting power at proposal snapshot")
+	ErrAlreadyVoted     = errors.New("already voted on proposal")
+	ErrVotingClosed     = errors.New("proposal voting period ended")
+	ErrTimelockActive   = errors.New("proposal voting or timelock not over")
+	ErrQuorumNotReached = errors.New("proposal quorum not reached")
+	ErrProposalDefeated = errors.New("proposal did not pass")
+	ErrProposalExecuted = errors.New("proposal already executed")
+)
+
+// Errors - Oracle
+var (
+	ErrUnknownOracleSource = errors.New("unknown oracle source")
+	ErrPriceUnavailable    = errors.New("price unavailable")
+	ErrStaleReport         = errors.New("report older than latest from source")
+	ErrFutureReport        = errors.New("report from a future block")
+	ErrNotKeeper           = errors.New("signer is not a whitelisted keeper")
+	ErrInvalidSignature    = errors.New("invalid signature")
 )
 
 // Errors - Liquid (self-repaying loans)
 var (
 	ErrMaxLTVExceeded           = errors.New("max LTV exceeded (90%)")
 	ErrInvalidYieldToken        = errors.New("invalid yield-bearing token")
-	ErrDebtCeiling              = errors.New("debt ceiling reached")
+	ErrDebtCeilingExceeded      = errors.New("debt ceiling exceeded")
 	ErrNoDebtToRepay            = errors.New("no debt to repay")
 	ErrTransmuterEmpty          = errors.New("transmuter has no underlying")
 	ErrLiquidTokenNotRegistered = errors.New("liquid token not registered")
+	ErrYieldTokenStillActive    = errors.New("yield token must be deactivated before migration")
 )
 
 // Errors - Teleport
@@ -419,12 +557,6 @@ var (
 var (
 	Q96  = new(big.Int).Lsh(big.NewInt(1), 96)
 	Q128 = new(big.Int).Lsh(big.NewInt(1), 128)
-
-	MinTick int24 = -887272
-	MaxTick int24 = 887272
-
-	MinSqrtRatio    = new(big.Int).SetUint64(4295128739)
-	MaxSqrtRatio, _ = new(big.Int).SetString("1461446703485210103287273052203988822378723970342", 10)
 )
 
 // uint24 type alias for fees
@@ -469,6 +601,13 @@ type LendingPosition struct {
 // MaxLeverage is the maximum allowed leverage (1111x)
 const MaxLeverage uint32 = 1111
 
+// Perpetual liquidation penalty, in basis points of PerpFeePrecision
+const (
+	PerpFeePrecision       = 10000
+	PerpLiquidationPenalty = 500  // 5% of notional, taken from the remaining margin
+	PerpInsuranceShare     = 5000 // Half of the penalty goes to the insurance fund
+)
+
 // PerpMarket represents a perpetual futures market
 type PerpMarket struct {
 	BaseAsset         Currency // The underlying asset (e.g., ETH)
@@ -482,6 +621,8 @@ type PerpMarket struct {
 	MaxLeverage       uint32   // Maximum leverage (default 1111x)
 	MaintenanceMargin *big.Int // Maintenance margin ratio (18 decimals)
 	InsuranceFund     *big.Int // Market's insurance fund balance
+	BadDebt           *big.Int // Loss neither the insurance fund nor ADL could cover
+	PricesUnreliable  bool     // Mark and index diverged; liquidations paused
 }
 
 // PerpPosition represents a user's perpetual position
@@ -497,10 +638,183 @@ type PerpPosition struct {
 
 // FundingState tracks funding rate calculation state
 type FundingState struct {
-	CumulativeFunding *big.Int // Cumulative funding per unit size
-	LastUpdateTime    int64    // Last funding settlement time
-	PremiumEMA        *big.Int // Exponential moving average of premium
-	TWAPWindow        uint64   // TWAP window in seconds (default 8h)
+	CumulativeFunding *big.Int            // Cumulative funding per unit size
+	LastUpdateTime    int64               // Last funding settlement time
+	PremiumEMA        *big.Int            // Exponential moving average of premium
+	TWAPWindow        uint64              // TWAP window in seconds (default 8h)
+	Accumulator       *FundingAccumulator // Per-side funding indices
+}
+
+// FundingAccumulator tracks cumulative funding per unit of position size for
+// each side of a market as Q96 fixed point. An index increase is owed by
+// positions on that side, a decrease is earned. Funding is conserved: the
+// receiving side's index falls by the paying side's total spread over the
+// receiving open interest, so longs and shorts net to zero. Every rounding
+// step favors the market, never a position.
+type FundingAccumulator struct {
+	LongIndex  *big.Int // Q96 funding per unit of long size
+	ShortIndex *big.Int // Q96 funding per unit of short size
+}
+
+// NewFundingAccumulator returns an accumulator with both indices at zero
+func NewFundingAccumulator() *FundingAccumulator {
+	return &FundingAccumulator{
+		LongIndex:  big.NewInt(0),
+		ShortIndex: big.NewInt(0),
+	}
+}
+
+// Index returns a copy of the index for one side
+func (a *FundingAccumulator) Index(isLong bool) *big.Int {
+	if isLong {
+		return new(big.Int).Set(a.LongIndex)
+	}
+	return new(big.Int).Set(a.ShortIndex)
+}
+
+// Accrue charges perUnit (Q96, signed: positive means longs pay shorts) to
+// every unit of the paying side and credits the total to the receiving side
+// pro rata. Nothing accrues unless both sides have open interest.
+func (a *FundingAccumulator) Accrue(perUnit, openInterestLong, openInterestShort *big.Int) {
+	if perUnit.Sign() == 0 || openInterestLong.Sign() == 0 || openInterestShort.Sign() == 0 {
+		return
+	}
+
+	payerIndex, receiverIndex := a.LongIndex, a.ShortIndex
+	payerOI, receiverOI := openInterestLong, openInterestShort
+	if perUnit.Sign() < 0 {
+		payerIndex, receiverIndex = a.ShortIndex, a.LongIndex
+		payerOI, receiverOI = openInterestShort, openInterestLong
+	}
+
+	charge := new(big.Int).Abs(perUnit)
+	credit := new(big.Int).Mul(charge, payerOI)
+	credit.Quo(credit, receiverOI) // round the credit down
+
+	payerIndex.Add(payerIndex, charge)
+	receiverIndex.Sub(receiverIndex, credit)
+}
+
+// Pending returns the funding PnL of a position of size (positive long,
+// negative short) since lastIndex: negative when owed, positive when earned.
+// Amounts owed round up and amounts earned round down.
+func (a *FundingAccumulator) Pending(size, lastIndex *big.Int) *big.Int {
+	delta := new(big.Int).Sub(a.Index(size.Sign() > 0), lastIndex)
+	amount := delta.Mul(delta, new(big.Int).Abs(size))
+
+	if amount.Sign() > 0 {
+		// Owed: ceil(amount / Q96)
+		amount.Add(amount, Q96)
+		amount.Sub(amount, big.NewInt(1))
+		amount.Quo(amount, Q96)
+		return amount.Neg(amount)
+	}
+	// Earned: floor(-amount / Q96)
+	amount.Neg(amount)
+	return amount.Quo(amount, Q96)
+}
+
+// =========================================================================
+// FeeGov Types
+// =========================================================================
+
+// FeeProposal is a proposal to change a pool's protocol fee
+type FeeProposal struct {
+	ID            uint64         // Proposal ID
+	Proposer      common.Address // Account that opened the proposal
+	Pool          PoolKey        // Pool whose fee changes
+	ProtocolFee   uint24         // Proposed protocol fee (millionths of the swap fee)
+	SnapshotBlock uint64         // Voting power is liquidity held before this block
+	VoteEnd       uint64         // Last block votes are accepted
+	ForVotes      *big.Int       // Snapshot liquidity voting for
+	AgainstVotes  *big.Int       // Snapshot liquidity voting against
+	Executed      bool           // Fee change applied
+
+	voted map[common.Address]bool
+}
+
+// =========================================================================
+// CLOB Types
+// =========================================================================
+
+// SlippagePrecision is the basis point denominator of market order slippage
+// caps; a cap may not exceed it (100%)
+const SlippagePrecision = 10000
+
+// FeePrecision is the basis point denominator of CLOB maker rebates and
+// taker fees
+const FeePrecision = 10000
+
+// SelfTradePrevention selects what happens when an incoming order would
+// match a resting order of the same owner
+type SelfTradePrevention uint8
+
+const (
+	STPNone           SelfTradePrevention = iota // Allow self-matching
+	STPCancelResting                             // Cancel the owner's resting order and keep matching
+	STPCancelIncoming                            // Stop matching and cancel the incoming remainder
+	STPReject                                    // Reject the whole incoming order
+)
+
+// CLOBMarket holds the trading parameters of an order book market
+type CLOBMarket struct {
+	ID                  [32]byte            // Market ID
+	BaseAsset           Currency            // Asset being traded
+	QuoteAsset          Currency            // Asset prices are quoted in
+	TickSize            *big.Int            // Minimum price increment
+	LotSize             *big.Int            // Minimum size increment
+	MinOrderSize        *big.Int            // Smallest accepted order size
+	SelfTradePrevention SelfTradePrevention // Action on self-matching
+	MakerRebateBps      uint64              // Rebate paid to makers, in bps of fill notional
+	TakerFeeBps         uint64              // Fee charged to takers, in bps of fill notional
+}
+
+// Order is a limit order resting on (or submitted to) the book
+type Order struct {
+	ID       uint64         // Unique order ID
+	Market   [32]byte       // Market ID
+	Owner    common.Address // Order owner
+	IsBuy    bool           // Bid if true, ask otherwise
+	Price    *big.Int       // Limit price (quote per base)
+	Size     *big.Int       // Original size (base units)
+	Filled   *big.Int       // Size filled so far
+	Sequence uint64         // Arrival sequence for time priority
+	Canceled bool           // Remainder canceled by self-trade prevention
+	Expiry   uint64         // Last block the order may rest (0 = good til canceled)
+}
+
+// Remaining returns the unfilled size of the order
+func (o *Order) Remaining() *big.Int {
+	return new(big.Int).Sub(o.Size, o.Filled)
+}
+
+// Expired reports whether the order is past its expiry block
+func (o *Order) Expired(block uint64) bool {
+	return o.Expiry != 0 && block > o.Expiry
+}
+
+// PriceLevel is the resting size aggregated at one price
+type PriceLevel struct {
+	Price  *big.Int // Level price
+	Size   *big.Int // Total remaining size at this price
+	Orders int      // Number of resting orders at this price
+}
+
+// Fill records a single match between a taker and a resting maker order
+type Fill struct {
+	MakerOrderID uint64   // Resting order
+	TakerOrderID uint64   // Incoming order
+	Price        *big.Int // Execution price (maker's price)
+	Size         *big.Int // Filled size
+	MakerRebate  *big.Int // Quote credited to the maker
+	TakerFee     *big.Int // Quote debited from the taker
+}
+
+// FeeAccrual is what one account has paid in taker fees and earned in maker
+// rebates on a market, in the market's quote asset
+type FeeAccrual struct {
+	FeesPaid      *big.Int // Taker fees debited
+	RebatesEarned *big.Int // Maker rebates credited
 }
 
 // =========================================================================
@@ -517,6 +831,26 @@ const (
 
 	// LTVPrecision for basis point calculations
 	LTVPrecision = 10000
+
+	// LiquidationThreshold is the LTV above which a position is unhealthy.
+	// It matches MaxLTV, so a position minted to the limit has a health
+	// factor of exactly 1.
+	LiquidationThreshold = MaxLTV // basis points
+
+	// HealthFactorPrecision scales health factors (1e18 = 1.0)
+	HealthFactorPrecision = 1_000_000_000_000_000_000
+
+	// DefaultLiquidCloseFactor and DefaultLiquidLiquidationBonus seed a new
+	// yield token's CloseFactor and LiquidationBonus. Liquidation reads the
+	// yield token's own values, which SetCloseFactor and SetLiquidationBonus
+	// change per token.
+	DefaultLiquidCloseFactor      = 5000 // 50.00% in basis points
+	DefaultLiquidLiquidationBonus = 500  // 5.00% in basis points
+
+	// MaxLiquidLiquidationBonus bounds a yield token's liquidation bonus.
+	// Seizing more than 1/LiquidationThreshold of the repaid debt would
+	// leave every liquidated account less healthy than before.
+	MaxLiquidLiquidationBonus = 1000 // 10.00% in basis points
 )
 
 // LiquidToken represents a liquid asset (e.g., LUSD, LETH, LBTC)
@@ -537,6 +871,13 @@ type YieldToken struct {
 	YieldPerBlock   *big.Int       // Expected yield per block (for estimation)
 	IsActive        bool           // Whether deposits are accepted
 	TotalDeposited  *big.Int       // Total deposited in Liquid
+
+	// Oracle prices the token in its underlying; nil values it 1:1
+	Oracle CollateralOracle
+
+	// Liquidation parameters, in basis points
+	CloseFactor      uint24 // Max share of an account's debt one liquidation may repay
+	LiquidationBonus uint24 // Collateral value paid on top of the repaid debt
 }
 
 // LiquidAccount represents a user's self-repaying loan position
@@ -556,6 +897,7 @@ type LiquidFXState struct {
 	ExchangeBuffer  *big.Int       // Underlying available for exchange
 	TotalStaked     *big.Int       // Total liquid tokens staked for transmutation
 	ExchangeRate    *big.Int       // Current exchange rate (Q96)
+	DustBuffer      *big.Int       // Undistributed deposit remainder (Q96-scaled), carried into the next deposit
 }
 
 // =========================================================================
@@ -587,6 +929,7 @@ type TeleportRequest struct {
 	Amount      *big.Int       // Amount to teleport
 	Timestamp   int64          // Request timestamp
 	Status      TeleportStatus // Current status
+	Fronted     bool           // Paid out early by an LP
 }
 
 // TeleportStatus represents the state of a teleport
@@ -0,0 +1,75 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"math/big"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/geth/common"
+)
+
+// WLUX contract address; it holds the native LUX backing all WLUX, so its
+// balance equals the WLUX supply as with WETH
+var wluxAddr = common.HexToAddress(WLUXAddress)
+
+// Wrap converts amount of the current locker's native LUX into WLUX.
+// The locker owes amount of native LUX and is owed amount of WLUX, so a
+// native leg of a route can feed a WLUX pool without leaving the lock.
+func (pm *PoolManager) Wrap(stateDB StateDB, amount *big.Int) error {
+	locker := pm.getCurrentLocker()
+	if locker == (common.Address{}) {
+		return ErrUnauthorized
+	}
+	amountU256, err := wrapAmount(amount)
+	if err != nil {
+		return err
+	}
+
+	// Move the native backing from the pool manager to WLUX
+	if stateDB.GetBalance(poolManagerAddr).Lt(amountU256) {
+		return ErrInsufficientBalance
+	}
+	stateDB.SubBalance(poolManagerAddr, amountU256)
+	stateDB.AddBalance(wluxAddr, amountU256)
+
+	pm.updateDelta(locker, NativeCurrency, amount)
+	pm.updateDelta(locker, WrappedNativeCurrency, new(big.Int).Neg(amount))
+	return nil
+}
+
+// Unwrap converts amount of the current locker's WLUX back into native LUX
+func (pm *PoolManager) Unwrap(stateDB StateDB, amount *big.Int) error {
+	locker := pm.getCurrentLocker()
+	if locker == (common.Address{}) {
+		return ErrUnauthorized
+	}
+	amountU256, err := wrapAmount(amount)
+	if err != nil {
+		return err
+	}
+
+	// Release the native backing from WLUX to the pool manager
+	if stateDB.GetBalance(wluxAddr).Lt(amountU256) {
+		return ErrInsufficientBalance
+	}
+	stateDB.SubBalance(wluxAddr, amountU256)
+	stateDB.AddBalance(poolManagerAddr, amountU256)
+
+	pm.updateDelta(locker, WrappedNativeCurrency, amount)
+	pm.updateDelta(locker, NativeCurrency, new(big.Int).Neg(amount))
+	return nil
+}
+
+// wrapAmount validates a wrap or unwrap amount
+func wrapAmount(amount *big.Int) (*uint256.Int, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	amountU256, overflow := uint256.FromBig(amount)
+	if overflow {
+		return nil, ErrInvalidAmount
+	}
+	return amountU256, nil
+}
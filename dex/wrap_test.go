@@ -0,0 +1,165 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/geth/common"
+)
+
+func TestPoolManagerWrapUnwrap(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	stateDB.AddBalance(caller, uint256.NewInt(1000))
+
+	pm.lockers = append(pm.lockers, caller)
+	pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+
+	// Pay native LUX in, then wrap the credit
+	if err := pm.Settle(stateDB, NativeCurrency, big.NewInt(1000)); err != nil {
+		t.Fatalf("Settle failed: %v", err)
+	}
+	if err := pm.Wrap(stateDB, big.NewInt(1000)); err != nil {
+		t.Fatalf("Wrap failed: %v", err)
+	}
+
+	if delta := pm.GetDelta(caller, NativeCurrency); delta.Sign() != 0 {
+		t.Errorf("Expected zero native delta after wrap, got: %s", delta)
+	}
+	if delta := pm.GetDelta(caller, WrappedNativeCurrency); delta.Cmp(big.NewInt(-1000)) != 0 {
+		t.Errorf("Expected WLUX credit of 1000, got: %s", delta)
+	}
+	if balance := stateDB.GetBalance(wluxAddr); balance.Uint64() != 1000 {
+		t.Errorf("Expected WLUX backing of 1000, got: %s", balance)
+	}
+
+	// WLUX holds no more backing than was wrapped
+	if err := pm.Unwrap(stateDB, big.NewInt(1001)); !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("Expected ErrInsufficientBalance, got: %v", err)
+	}
+
+	// Unwrap and take the native LUX back out
+	if err := pm.Unwrap(stateDB, big.NewInt(1000)); err != nil {
+		t.Fatalf("Unwrap failed: %v", err)
+	}
+	if err := pm.Take(stateDB, NativeCurrency, caller, big.NewInt(1000)); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if err := pm.verifySettlement(caller); err != nil {
+		t.Errorf("Expected settled deltas, got: %v", err)
+	}
+	if balance := stateDB.GetBalance(caller); balance.Uint64() != 1000 {
+		t.Errorf("Expected caller balance of 1000, got: %s", balance)
+	}
+	if balance := stateDB.GetBalance(wluxAddr); !balance.IsZero() {
+		t.Errorf("Expected no WLUX backing, got: %s", balance)
+	}
+}
+
+func TestPoolManagerWrapInvalid(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	if err := pm.Wrap(stateDB, big.NewInt(1)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized without lock, got: %v", err)
+	}
+
+	pm.lockers = append(pm.lockers, caller)
+	pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+
+	for _, amount := range []*big.Int{nil, big.NewInt(0), big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 256)} {
+		if err := pm.Wrap(stateDB, amount); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Wrap(%v): expected ErrInvalidAmount, got: %v", amount, err)
+		}
+		if err := pm.Unwrap(stateDB, amount); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Unwrap(%v): expected ErrInvalidAmount, got: %v", amount, err)
+		}
+	}
+
+	// The pool manager holds no native LUX to wrap
+	if err := pm.Wrap(stateDB, big.NewInt(1)); !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("Expected ErrInsufficientBalance, got: %v", err)
+	}
+}
+
+// TestWrappedNativeMatchesNative checks that a WLUX pool sorts and prices
+// exactly like the equivalent native LUX pool
+func TestWrappedNativeMatchesNative(t *testing.T) {
+	pm := newTestPoolManager()
+	stateDB := NewMockStateDB()
+	caller := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	nativeKey := newTestPoolKey()
+	wrappedKey := nativeKey
+	wrappedKey.Currency0 = WrappedNativeCurrency
+
+	if !WrappedNativeCurrency.IsWrappedNative() || WrappedNativeCurrency.IsNative() {
+		t.Fatal("WLUX must be wrapped, not native")
+	}
+	if nativeKey.ID() == wrappedKey.ID() {
+		t.Fatal("Native and WLUX pools must be distinct")
+	}
+
+	// Both sort before the paired token, and neither sorts after it
+	for _, c := range []Currency{NativeCurrency, WrappedNativeCurrency} {
+		if !pm.areCurrenciesSorted(c, nativeKey.Currency1) {
+			t.Errorf("%s should sort before %s", c.Address.Hex(), nativeKey.Currency1.Address.Hex())
+		}
+		if pm.areCurrenciesSorted(nativeKey.Currency1, c) {
+			t.Errorf("%s should not sort before %s", nativeKey.Currency1.Address.Hex(), c.Address.Hex())
+		}
+	}
+
+	sqrtPriceX96 := new(big.Int).Lsh(big.NewInt(3), 95)
+	nativeTick, err := pm.Initialize(stateDB, nativeKey, sqrtPriceX96, nil)
+	if err != nil {
+		t.Fatalf("Initialize native pool failed: %v", err)
+	}
+	wrappedTick, err := pm.Initialize(stateDB, wrappedKey, sqrtPriceX96, nil)
+	if err != nil {
+		t.Fatalf("Initialize WLUX pool failed: %v", err)
+	}
+	if nativeTick != wrappedTick {
+		t.Errorf("Tick mismatch: native %d, WLUX %d", nativeTick, wrappedTick)
+	}
+
+	pm.pools[nativeKey.ID()].Liquidity = big.NewInt(1000000000)
+	pm.pools[wrappedKey.ID()].Liquidity = big.NewInt(1000000000)
+
+	pm.lockers = append(pm.lockers, caller)
+	pm.currentDeltas[caller] = make(map[Currency]*big.Int)
+
+	for _, zeroForOne := range []bool{true, false} {
+		params := SwapParams{ZeroForOne: zeroForOne, AmountSpecified: big.NewInt(5000)}
+		if zeroForOne {
+			params.SqrtPriceLimitX96 = MinSqrtRatio
+		} else {
+			params.SqrtPriceLimitX96 = MaxSqrtRatio
+		}
+
+		nativeDelta, err := pm.Swap(stateDB, nativeKey, params, nil)
+		if err != nil {
+			t.Fatalf("Swap native pool failed: %v", err)
+		}
+		wrappedDelta, err := pm.Swap(stateDB, wrappedKey, params, nil)
+		if err != nil {
+			t.Fatalf("Swap WLUX pool failed: %v", err)
+		}
+
+		if nativeDelta.Amount0.Cmp(wrappedDelta.Amount0) != 0 || nativeDelta.Amount1.Cmp(wrappedDelta.Amount1) != 0 {
+			t.Errorf("Swap delta mismatch: native (%s, %s), WLUX (%s, %s)",
+				nativeDelta.Amount0, nativeDelta.Amount1, wrappedDelta.Amount0, wrappedDelta.Amount1)
+		}
+		if pm.pools[nativeKey.ID()].Tick != pm.pools[wrappedKey.ID()].Tick {
+			t.Errorf("Post-swap tick mismatch: native %d, WLUX %d",
+				pm.pools[nativeKey.ID()].Tick, pm.pools[wrappedKey.ID()].Tick)
+		}
+	}
+}
@@ -0,0 +1,495 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dkg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+)
+
+// Pedersen DKG coordination (LP-5220)
+//
+// Round 1: each participant posts Feldman commitments C_k = a_k*G to a
+// random polynomial of degree threshold-1.
+// Round 2: each dealer posts one encrypted share per participant,
+// e_ij = f_i(j) + H(k_ij) mod n, where k_ij is the ECDH point between the
+// dealer's and recipient's registered encryption keys.
+// A recipient whose share fails Feldman verification complains by revealing
+// k_ij with a DLEQ proof; the coordinator decrypts the share on-chain and
+// disqualifies the dealer if the share is inconsistent.
+// The group key is the sum of the qualified dealers' C_0. A dealer is
+// qualified once it has posted both rounds without being disqualified;
+// dealers that never post are left out, so a silent minority cannot stall
+// the session.
+
+var (
+	// ContractDKGAddress is the address of the DKG precompile (LP-5220)
+	ContractDKGAddress = common.HexToAddress("0x0000000000000000000000000000000000005220")
+
+	ErrInvalidThreshold     = errors.New("invalid threshold: t must be > 0 and <= n")
+	ErrDuplicateParticipant = errors.New("duplicate participant")
+	ErrSessionNotFound      = errors.New("DKG session not found")
+	ErrSessionFinalized     = errors.New("DKG session already finalized")
+	ErrNotParticipant       = errors.New("not a session participant")
+	ErrAlreadySubmitted     = errors.New("contribution already submitted")
+	ErrCommitmentsMissing   = errors.New("dealer has not posted commitments")
+	ErrSharesMissing        = errors.New("dealer has not posted shares")
+	ErrInvalidShares        = errors.New("invalid encrypted shares")
+	ErrInvalidComplaint     = errors.New("complaint does not prove an inconsistent share")
+	ErrInvalidProof         = errors.New("invalid shared key proof")
+	ErrDisqualified         = errors.New("participant disqualified")
+	ErrNotEnoughQualified   = errors.New("not enough qualified dealers")
+	ErrIncompleteRound      = errors.New("round incomplete")
+)
+
+const (
+	// DKGGas is the flat cost of a DKG coordinator call
+	DKGGas uint64 = 100_000
+
+	// DLEQProofSize is c || z
+	DLEQProofSize = 2 * ScalarSize
+)
+
+// dleqDomain separates shared key proofs from other hashes
+var dleqDomain = []byte("LUX_DKG_SHARED_KEY_V1")
+
+// Participant is a DKG party with its share encryption key
+type Participant struct {
+	Address common.Address
+	Index   uint32 // Shamir evaluation point, 1-based position in the session
+	EncKey  []byte // Uncompressed secp256k1 point used for share encryption
+}
+
+// Session is one DKG run
+type Session struct {
+	ID           common.Hash
+	Threshold    uint32
+	Participants []*Participant
+
+	Commitments  map[common.Address][][]byte // dealer -> C_0..C_{t-1}
+	Shares       map[common.Address][][]byte // dealer -> e_i1..e_in
+	Disqualified map[common.Address]bool
+
+	// Set on finalization
+	Finalized  bool
+	Qualified  []common.Address
+	GroupKey   []byte
+	Commitment [][]byte // Sum of qualified dealers' commitments
+
+	// Proactive refresh state
+	Epoch   uint64
+	Refresh *RefreshRound
+
+	participant map[common.Address]*Participant
+}
+
+// Coordinator runs DKG sessions
+type Coordinator struct {
+	Sessions   map[common.Hash]*Session
+	Recoveries map[common.Hash]*Recovery
+
+	// RecoveryDelay is the timelock before helpers may contribute (seconds)
+	RecoveryDelay uint64
+
+	nonce uint64
+
+	// now returns the current unix time; replaced in tests
+	now func() uint64
+
+	mu sync.RWMutex
+}
+
+// NewCoordinator creates an empty DKG coordinator
+func NewCoordinator() *Coordinator {
+	return &Coordinator{
+		Sessions:      make(map[common.Hash]*Session),
+		Recoveries:    make(map[common.Hash]*Recovery),
+		RecoveryDelay: DefaultRecoveryDelay,
+		now:           func() uint64 { return uint64(time.Now().Unix()) },
+	}
+}
+
+// CreateSession opens a threshold-of-len(participants) DKG. Participants are
+// indexed 1..n in the order given.
+func (c *Coordinator) CreateSession(participants []Participant, threshold uint32) (common.Hash, error) {
+	if threshold == 0 || int(threshold) > len(participants) {
+		return common.Hash{}, ErrInvalidThreshold
+	}
+
+	s := &Session{
+		Threshold:    threshold,
+		Participants: make([]*Participant, len(participants)),
+		Commitments:  make(map[common.Address][][]byte),
+		Shares:       make(map[common.Address][][]byte),
+		Disqualified: make(map[common.Address]bool),
+		participant:  make(map[common.Address]*Participant),
+	}
+	for i, p := range participants {
+		if _, exists := s.participant[p.Address]; exists {
+			return common.Hash{}, ErrDuplicateParticipant
+		}
+		if _, _, err := unmarshalPoint(p.EncKey); err != nil {
+			return common.Hash{}, err
+		}
+		member := &Participant{
+			Address: p.Address,
+			Index:   uint32(i + 1),
+			EncKey:  common.CopyBytes(p.EncKey),
+		}
+		s.Participants[i] = member
+		s.participant[p.Address] = member
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.nonce++
+	var nonce [8]byte
+	binary.BigEndian.PutUint64(nonce[:], c.nonce)
+	preimage := [][]byte{nonce[:]}
+	for _, p := range s.Participants {
+		preimage = append(preimage, p.Address[:])
+	}
+	s.ID = common.BytesToHash(crypto.Keccak256(preimage...))
+
+	c.Sessions[s.ID] = s
+	return s.ID, nil
+}
+
+// SubmitCommitments records a dealer's round 1 Feldman commitments
+func (c *Coordinator) SubmitCommitments(sessionID common.Hash, dealer common.Address, commitments [][]byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, err := c.openSession(sessionID, dealer)
+	if err != nil {
+		return err
+	}
+	if _, exists := s.Commitments[dealer]; exists {
+		return ErrAlreadySubmitted
+	}
+	if uint32(len(commitments)) != s.Threshold {
+		return ErrInvalidCommitments
+	}
+	stored := make([][]byte, len(commitments))
+	for k, cm := range commitments {
+		if _, _, err := unmarshalPoint(cm); err != nil {
+			return ErrInvalidCommitments
+		}
+		stored[k] = common.CopyBytes(cm)
+	}
+
+	s.Commitments[dealer] = stored
+	return nil
+}
+
+// SubmitShares records a dealer's round 2 encrypted shares, one per
+// participant in index order
+func (c *Coordinator) SubmitShares(sessionID common.Hash, dealer common.Address, encShares [][]byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, err := c.openSession(sessionID, dealer)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.Commitments[dealer]; !ok {
+		return ErrCommitmentsMissing
+	}
+	if _, exists := s.Shares[dealer]; exists {
+		return ErrAlreadySubmitted
+	}
+	if len(encShares) != len(s.Participants) {
+		return ErrInvalidShares
+	}
+	stored := make([][]byte, len(encShares))
+	for j, e := range encShares {
+		if len(e) != ScalarSize {
+			return ErrInvalidShares
+		}
+		stored[j] = common.CopyBytes(e)
+	}
+
+	s.Shares[dealer] = stored
+	return nil
+}
+
+// Complain accuses dealer of sending complainer an inconsistent share.
+// sharedKey is the ECDH point between the two encryption keys and proof is
+// a DLEQ proof that it was derived from the complainer's key. The dealer is
+// disqualified if the decrypted share fails Feldman verification.
+func (c *Coordinator) Complain(
+	sessionID common.Hash,
+	complainer common.Address,
+	dealer common.Address,
+	sharedKey []byte,
+	proof []byte,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, err := c.openSession(sessionID, complainer)
+	if err != nil {
+		return err
+	}
+	accused, ok := s.participant[dealer]
+	if !ok {
+		return ErrNotParticipant
+	}
+	if s.Disqualified[dealer] {
+		return ErrDisqualified
+	}
+	encShares, ok := s.Shares[dealer]
+	if !ok {
+		return ErrSharesMissing
+	}
+	member := s.participant[complainer]
+
+	err = checkComplaint(member, accused, encShares[member.Index-1], sharedKey, proof, func(share *big.Int) (bool, error) {
+		return VerifyShare(s.Commitments[dealer], member.Index, share)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.Disqualified[dealer] = true
+	return nil
+}
+
+// checkComplaint verifies the complainer's shared key proof, decrypts its
+// share from accused and succeeds only if verify rejects the share
+func checkComplaint(
+	member, accused *Participant,
+	encShare, sharedKey, proof []byte,
+	verify func(share *big.Int) (bool, error),
+) error {
+	if err := verifySharedKey(member.EncKey, accused.EncKey, sharedKey, proof); err != nil {
+		return err
+	}
+	valid, err := verify(DecryptShare(encShare, sharedKey))
+	if err != nil {
+		return err
+	}
+	if valid {
+		return ErrInvalidComplaint
+	}
+	return nil
+}
+
+// Finalize computes the group key from the qualified dealers: those that
+// posted commitments and shares and were not disqualified by a complaint.
+// It succeeds as soon as at least threshold dealers qualify; participants
+// that never dealt still receive shares from the qualified dealers but do
+// not contribute to the key.
+func (c *Coordinator) Finalize(sessionID common.Hash) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.Sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if s.Finalized {
+		return nil, ErrSessionFinalized
+	}
+
+	var qualified []common.Address
+	for _, p := range s.Participants {
+		if s.Disqualified[p.Address] {
+			continue
+		}
+		if _, ok := s.Shares[p.Address]; !ok {
+			continue
+		}
+		qualified = append(qualified, p.Address)
+	}
+	if uint32(len(qualified)) < s.Threshold {
+		return nil, ErrNotEnoughQualified
+	}
+
+	combined := make([][]byte, s.Threshold)
+	for k := range combined {
+		terms := make([][]byte, len(qualified))
+		for i, dealer := range qualified {
+			terms[i] = s.Commitments[dealer][k]
+		}
+		sum, err := addPoints(terms...)
+		if err != nil {
+			return nil, err
+		}
+		combined[k] = sum
+	}
+
+	s.Qualified = qualified
+	s.Commitment = combined
+	s.GroupKey = combined[0]
+	s.Finalized = true
+	return common.CopyBytes(s.GroupKey), nil
+}
+
+// GetGroupKey returns the group public key of a finalized session
+func (c *Coordinator) GetGroupKey(sessionID common.Hash) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, ok := c.Sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if !s.Finalized {
+		return nil, ErrIncompleteRound
+	}
+	return common.CopyBytes(s.GroupKey), nil
+}
+
+// PublicShare returns participant index's public key share x_j*G in a
+// finalized session
+func (c *Coordinator) PublicShare(sessionID common.Hash, index uint32) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	s, ok := c.Sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if !s.Finalized {
+		return nil, ErrIncompleteRound
+	}
+	if index == 0 || int(index) > len(s.Participants) {
+		return nil, ErrInvalidIndex
+	}
+	return EvaluateCommitment(s.Commitment, index)
+}
+
+// openSession returns an unfinalized session that caller may act in
+func (c *Coordinator) openSession(sessionID common.Hash, caller common.Address) (*Session, error) {
+	s, ok := c.Sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if s.Finalized {
+		return nil, ErrSessionFinalized
+	}
+	if _, ok := s.participant[caller]; !ok {
+		return nil, ErrNotParticipant
+	}
+	if s.Disqualified[caller] {
+		return nil, ErrDisqualified
+	}
+	return s, nil
+}
+
+// SharedKey returns the ECDH point priv*peer used to encrypt shares
+func SharedKey(priv *big.Int, peer []byte) ([]byte, error) {
+	px, py, err := unmarshalPoint(peer)
+	if err != nil {
+		return nil, err
+	}
+	kx, ky := curve.ScalarMult(px, py, scalarBytes(priv))
+	return curve.Marshal(kx, ky), nil
+}
+
+// EncryptShare masks share with the ECDH point between dealer and recipient
+func EncryptShare(share *big.Int, sharedKey []byte) []byte {
+	e := new(big.Int).Add(share, sharePad(sharedKey))
+	return scalarBytes(e.Mod(e, curve.Params().N))
+}
+
+// DecryptShare removes the ECDH mask from an encrypted share
+func DecryptShare(encShare, sharedKey []byte) *big.Int {
+	s := new(big.Int).SetBytes(encShare)
+	s.Sub(s, sharePad(sharedKey))
+	return s.Mod(s, curve.Params().N)
+}
+
+// ProveSharedKey returns K = priv*peer with a DLEQ proof that
+// log_G(priv*G) == log_peer(K)
+func ProveSharedKey(priv *big.Int, peer []byte, nonce *big.Int) (sharedKey, proof []byte, err error) {
+	px, py, err := unmarshalPoint(peer)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := curve.Params().N
+
+	ox, oy := curve.ScalarBaseMult(scalarBytes(priv))
+	kx, ky := curve.ScalarMult(px, py, scalarBytes(priv))
+	a1x, a1y := curve.ScalarBaseMult(scalarBytes(nonce))
+	a2x, a2y := curve.ScalarMult(px, py, scalarBytes(nonce))
+
+	sharedKey = curve.Marshal(kx, ky)
+	ch := dleqChallenge(curve.Marshal(ox, oy), peer, sharedKey, curve.Marshal(a1x, a1y), curve.Marshal(a2x, a2y))
+
+	// z = k + c*priv mod n
+	z := new(big.Int).Mul(ch, priv)
+	z.Add(z, nonce).Mod(z, n)
+
+	proof = append(scalarBytes(ch), scalarBytes(z)...)
+	return sharedKey, proof, nil
+}
+
+// verifySharedKey checks a DLEQ proof (c, z) that sharedKey = x*peer for the
+// x behind own: A1 = z*G - c*own, A2 = z*peer - c*K, c == H(own, peer, K, A1, A2)
+func verifySharedKey(own, peer, sharedKey, proof []byte) error {
+	if len(proof) != DLEQProofSize {
+		return ErrInvalidProof
+	}
+	ox, oy, err := unmarshalPoint(own)
+	if err != nil {
+		return ErrInvalidProof
+	}
+	px, py, err := unmarshalPoint(peer)
+	if err != nil {
+		return ErrInvalidProof
+	}
+	kx, ky, err := unmarshalPoint(sharedKey)
+	if err != nil {
+		return ErrInvalidProof
+	}
+
+	n := curve.Params().N
+	ch := new(big.Int).SetBytes(proof[:ScalarSize])
+	z := new(big.Int).SetBytes(proof[ScalarSize:])
+	if ch.Sign() == 0 || ch.Cmp(n) >= 0 || z.Sign() == 0 || z.Cmp(n) >= 0 {
+		return ErrInvalidProof
+	}
+	negC := scalarBytes(new(big.Int).Sub(n, ch))
+
+	zgx, zgy := curve.ScalarBaseMult(scalarBytes(z))
+	cox, coy := curve.ScalarMult(ox, oy, negC)
+	a1x, a1y := curve.Add(zgx, zgy, cox, coy)
+
+	zpx, zpy := curve.ScalarMult(px, py, scalarBytes(z))
+	ckx, cky := curve.ScalarMult(kx, ky, negC)
+	a2x, a2y := curve.Add(zpx, zpy, ckx, cky)
+
+	expected := dleqChallenge(own, peer, sharedKey, curve.Marshal(a1x, a1y), curve.Marshal(a2x, a2y))
+	if expected.Cmp(ch) != 0 {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// dleqChallenge hashes the proof transcript to a scalar
+func dleqChallenge(parts ...[]byte) *big.Int {
+	h := sha256.New()
+	h.Write(dleqDomain)
+	for _, p := range parts {
+		h.Write(p)
+	}
+	c := new(big.Int).SetBytes(h.Sum(nil))
+	return c.Mod(c, curve.Params().N)
+}
+
+// sharePad derives the share mask from an ECDH point
+func sharePad(sharedKey []byte) *big.Int {
+	pad := new(big.Int).SetBytes(crypto.Keccak256(sharedKey))
+	return pad.Mod(pad, curve.Params().N)
+}
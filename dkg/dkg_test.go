@@ -0,0 +1,258 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dkg
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/registry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractAddressesMatchRegistry(t *testing.T) {
+	require.Equal(t, common.HexToAddress(registry.DKGCChain), ContractDKGAddress)
+	require.Equal(t, common.HexToAddress(registry.FeldmanCChain), ContractFeldmanAddress)
+	require.Equal(t, common.HexToAddress(registry.RefreshCChain), ContractRefreshAddress)
+	require.Equal(t, common.HexToAddress(registry.RecoveryCChain), ContractRecoveryAddress)
+}
+
+// testParty is one DKG participant's secret material
+type testParty struct {
+	addr    common.Address
+	encPriv *big.Int
+	encPub  []byte
+	coeffs  []*big.Int
+}
+
+func randomScalar(t *testing.T) *big.Int {
+	t.Helper()
+	for {
+		k, err := rand.Int(rand.Reader, curve.Params().N)
+		require.NoError(t, err)
+		if k.Sign() > 0 {
+			return k
+		}
+	}
+}
+
+func scalarPoint(k *big.Int) []byte {
+	x, y := curve.ScalarBaseMult(scalarBytes(k))
+	return curve.Marshal(x, y)
+}
+
+// evalPoly returns f(index) mod n
+func evalPoly(coeffs []*big.Int, index uint32) *big.Int {
+	n := curve.Params().N
+	x := big.NewInt(int64(index))
+	result, pow := new(big.Int), big.NewInt(1)
+	for _, c := range coeffs {
+		result.Add(result, new(big.Int).Mul(c, pow))
+		pow.Mul(pow, x)
+	}
+	return result.Mod(result, n)
+}
+
+// newTestDKG creates n parties with degree threshold-1 polynomials and a
+// session over them
+func newTestDKG(t *testing.T, n int, threshold uint32) (*Coordinator, common.Hash, []*testParty) {
+	t.Helper()
+
+	parties := make([]*testParty, n)
+	members := make([]Participant, n)
+	for i := range parties {
+		p := &testParty{
+			addr:    common.BigToAddress(big.NewInt(int64(0x100 + i))),
+			encPriv: randomScalar(t),
+			coeffs:  make([]*big.Int, threshold),
+		}
+		p.encPub = scalarPoint(p.encPriv)
+		for k := range p.coeffs {
+			p.coeffs[k] = randomScalar(t)
+		}
+		parties[i] = p
+		members[i] = Participant{Address: p.addr, EncKey: p.encPub}
+	}
+
+	c := NewCoordinator()
+	id, err := c.CreateSession(members, threshold)
+	require.NoError(t, err)
+	return c, id, parties
+}
+
+func (p *testParty) commitments() [][]byte {
+	out := make([][]byte, len(p.coeffs))
+	for k, a := range p.coeffs {
+		out[k] = scalarPoint(a)
+	}
+	return out
+}
+
+func (p *testParty) encryptedShares(t *testing.T, parties []*testParty) [][]byte {
+	t.Helper()
+	out := make([][]byte, len(parties))
+	for j, r := range parties {
+		key, err := SharedKey(p.encPriv, r.encPub)
+		require.NoError(t, err)
+		out[j] = EncryptShare(evalPoly(p.coeffs, uint32(j+1)), key)
+	}
+	return out
+}
+
+func TestDKGThreePartyGroupKey(t *testing.T) {
+	c, id, parties := newTestDKG(t, 3, 2)
+
+	for _, p := range parties {
+		require.NoError(t, c.SubmitCommitments(id, p.addr, p.commitments()))
+	}
+	for _, p := range parties {
+		require.NoError(t, c.SubmitShares(id, p.addr, p.encryptedShares(t, parties)))
+	}
+
+	groupKey, err := c.Finalize(id)
+	require.NoError(t, err)
+
+	secret := new(big.Int)
+	for _, p := range parties {
+		secret.Add(secret, p.coeffs[0])
+	}
+	secret.Mod(secret, curve.Params().N)
+	require.Equal(t, scalarPoint(secret), groupKey)
+
+	// Each party's summed share matches its public key share
+	for j, r := range parties {
+		share := new(big.Int)
+		for _, dealer := range parties {
+			key, err := SharedKey(r.encPriv, dealer.encPub)
+			require.NoError(t, err)
+			share.Add(share, DecryptShare(c.Sessions[id].Shares[dealer.addr][j], key))
+		}
+		share.Mod(share, curve.Params().N)
+
+		public, err := c.PublicShare(id, uint32(j+1))
+		require.NoError(t, err)
+		require.Equal(t, scalarPoint(share), public)
+	}
+
+	_, err = c.Finalize(id)
+	require.ErrorIs(t, err, ErrSessionFinalized)
+}
+
+func TestDKGInconsistentShareExcluded(t *testing.T) {
+	c, id, parties := newTestDKG(t, 3, 2)
+	honest, victim, cheater := parties[0], parties[1], parties[2]
+
+	for _, p := range parties {
+		require.NoError(t, c.SubmitCommitments(id, p.addr, p.commitments()))
+	}
+
+	// The cheater sends the victim a share off its committed polynomial
+	bad := cheater.encryptedShares(t, parties)
+	key, err := SharedKey(cheater.encPriv, victim.encPub)
+	require.NoError(t, err)
+	wrong := evalPoly(cheater.coeffs, 2)
+	bad[1] = EncryptShare(wrong.Add(wrong, big.NewInt(1)), key)
+
+	require.NoError(t, c.SubmitShares(id, honest.addr, honest.encryptedShares(t, parties)))
+	require.NoError(t, c.SubmitShares(id, victim.addr, victim.encryptedShares(t, parties)))
+	require.NoError(t, c.SubmitShares(id, cheater.addr, bad))
+
+	// A complaint against an honest dealer is rejected
+	sharedKey, proof, err := ProveSharedKey(victim.encPriv, honest.encPub, randomScalar(t))
+	require.NoError(t, err)
+	require.ErrorIs(t, c.Complain(id, victim.addr, honest.addr, sharedKey, proof), ErrInvalidComplaint)
+
+	// A forged shared key fails its proof
+	forged := scalarPoint(randomScalar(t))
+	sharedKey, proof, err = ProveSharedKey(victim.encPriv, cheater.encPub, randomScalar(t))
+	require.NoError(t, err)
+	require.ErrorIs(t, c.Complain(id, victim.addr, cheater.addr, forged, proof), ErrInvalidProof)
+
+	require.NoError(t, c.Complain(id, victim.addr, cheater.addr, sharedKey, proof))
+	require.True(t, c.Sessions[id].Disqualified[cheater.addr])
+
+	groupKey, err := c.Finalize(id)
+	require.NoError(t, err)
+
+	secret := new(big.Int).Add(honest.coeffs[0], victim.coeffs[0])
+	secret.Mod(secret, curve.Params().N)
+	require.Equal(t, scalarPoint(secret), groupKey)
+	require.Equal(t, []common.Address{honest.addr, victim.addr}, c.Sessions[id].Qualified)
+}
+
+func TestDKGRejectsBadContributions(t *testing.T) {
+	c, id, parties := newTestDKG(t, 3, 2)
+
+	_, err := c.CreateSession([]Participant{{Address: parties[0].addr, EncKey: parties[0].encPub}}, 2)
+	require.ErrorIs(t, err, ErrInvalidThreshold)
+
+	require.ErrorIs(t, c.SubmitCommitments(id, common.Address{0x01}, parties[0].commitments()), ErrNotParticipant)
+	require.ErrorIs(t, c.SubmitCommitments(id, parties[0].addr, parties[0].commitments()[:1]), ErrInvalidCommitments)
+	require.ErrorIs(t, c.SubmitShares(id, parties[0].addr, parties[0].encryptedShares(t, parties)), ErrCommitmentsMissing)
+
+	require.NoError(t, c.SubmitCommitments(id, parties[0].addr, parties[0].commitments()))
+	require.ErrorIs(t, c.SubmitCommitments(id, parties[0].addr, parties[0].commitments()), ErrAlreadySubmitted)
+
+	_, err = c.Finalize(id)
+	require.ErrorIs(t, err, ErrNotEnoughQualified)
+}
+
+func TestDKGFinalizeWithoutSilentDealer(t *testing.T) {
+	c, id, parties := newTestDKG(t, 3, 2)
+	dealers, silent := parties[:2], parties[2]
+
+	for _, p := range dealers {
+		require.NoError(t, c.SubmitCommitments(id, p.addr, p.commitments()))
+	}
+	require.NoError(t, c.SubmitShares(id, dealers[0].addr, dealers[0].encryptedShares(t, parties)))
+
+	// One qualified dealer is below the threshold
+	_, err := c.Finalize(id)
+	require.ErrorIs(t, err, ErrNotEnoughQualified)
+
+	// The silent party never posts, which does not block the session
+	require.NoError(t, c.SubmitShares(id, dealers[1].addr, dealers[1].encryptedShares(t, parties)))
+	groupKey, err := c.Finalize(id)
+	require.NoError(t, err)
+
+	secret := new(big.Int).Add(dealers[0].coeffs[0], dealers[1].coeffs[0])
+	secret.Mod(secret, curve.Params().N)
+	require.Equal(t, scalarPoint(secret), groupKey)
+	require.Equal(t, []common.Address{dealers[0].addr, dealers[1].addr}, c.Sessions[id].Qualified)
+
+	// The silent party still holds a valid share of the group key
+	share := new(big.Int)
+	for _, dealer := range dealers {
+		key, err := SharedKey(silent.encPriv, dealer.encPub)
+		require.NoError(t, err)
+		share.Add(share, DecryptShare(c.Sessions[id].Shares[dealer.addr][2], key))
+	}
+	share.Mod(share, curve.Params().N)
+	public, err := c.PublicShare(id, 3)
+	require.NoError(t, err)
+	require.Equal(t, scalarPoint(share), public)
+}
+
+func TestFeldmanVerifyShare(t *testing.T) {
+	coeffs := []*big.Int{randomScalar(t), randomScalar(t), randomScalar(t)}
+	commitments := make([][]byte, len(coeffs))
+	for k, a := range coeffs {
+		commitments[k] = scalarPoint(a)
+	}
+
+	for index := uint32(1); index <= 5; index++ {
+		ok, err := VerifyShare(commitments, index, evalPoly(coeffs, index))
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	ok, err := VerifyShare(commitments, 2, evalPoly(coeffs, 3))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = VerifyShare(commitments, 0, evalPoly(coeffs, 1))
+	require.ErrorIs(t, err, ErrInvalidIndex)
+}
@@ -0,0 +1,382 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dkg
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+)
+
+// Threshold share recovery (LP-5222)
+//
+// A lost share x_r is rebuilt by a helper set H of threshold shareholders.
+// Helper i sends the recipient c_i = l_i(r)*x_i + m_i, encrypted to the
+// recipient's new key, where l_i(r) is its Lagrange coefficient at r and the
+// masks m_i sum to zero so no helper's share is revealed. Each helper also
+// posts M_i = m_i*G, and the coordinator checks sum(M_i) is the identity.
+// The Feldman commitments fix each helper's public share X_i, so every
+// contribution has a public image l_i(r)*X_i + M_i. The recipient decrypts
+// each c_i and checks c_i*G against that image. A contribution that fails
+// is proven on-chain by revealing the ECDH key for that helper with a DLEQ
+// proof; the coordinator decrypts c_i, checks it against the commitments
+// and aborts the recovery if it does not match. Only the recipient can
+// finalize, so a recovery never completes with a share the recipient could
+// not verify. Neither the group secret nor any share appears on-chain.
+
+var (
+	// ContractRecoveryAddress is the address of the recovery precompile (LP-5222)
+	ContractRecoveryAddress = common.HexToAddress("0x0000000000000000000000000000000000005222")
+
+	ErrRecoveryNotFound   = errors.New("recovery not found")
+	ErrRecoveryLocked     = errors.New("recovery timelock has not expired")
+	ErrRecoveryCompleted  = errors.New("recovery already completed")
+	ErrNotEnoughApprovals = errors.New("recovery below approval threshold")
+	ErrAlreadyApproved    = errors.New("recovery already approved by caller")
+	ErrHelpersSelected    = errors.New("recovery helper set already selected")
+	ErrNotHelper          = errors.New("caller is not a recovery helper")
+	ErrInvalidMasks       = errors.New("recovery masks do not sum to zero")
+	ErrNotRecipient       = errors.New("caller is not the recovery recipient")
+	ErrRecoveryAborted    = errors.New("recovery aborted by a proven complaint")
+)
+
+const (
+	// RecoveryGas is the flat cost of a recovery call
+	RecoveryGas uint64 = 100_000
+
+	// DefaultRecoveryDelay is the recovery timelock (24 hours)
+	DefaultRecoveryDelay uint64 = 86_400
+)
+
+// Recovery rebuilds one participant's share for a new key holder
+type Recovery struct {
+	ID        common.Hash
+	SessionID common.Hash
+	LostIndex uint32
+	Recipient common.Address
+	NewEncKey []byte
+	UnlockAt  uint64
+
+	// Helpers are the first Threshold approvers, in approval order
+	Helpers       []common.Address
+	Contributions map[common.Address][]byte // helper -> encrypted c_i
+	Masks         map[common.Address][]byte // helper -> M_i
+	Completed     bool
+
+	// Set when the recipient proves a helper's contribution inconsistent
+	Aborted bool
+	Faulty  common.Address
+}
+
+// LagrangeCoefficient returns l_i(at) for the interpolation set indices
+func LagrangeCoefficient(indices []uint32, i uint32, at uint32) *big.Int {
+	n := curve.Params().N
+	num, den := big.NewInt(1), big.NewInt(1)
+	for _, j := range indices {
+		if j == i {
+			continue
+		}
+		num.Mod(num.Mul(num, big.NewInt(int64(at)-int64(j))), n)
+		den.Mod(den.Mul(den, big.NewInt(int64(i)-int64(j))), n)
+	}
+	l := num.Mul(num, den.ModInverse(den, n))
+	return l.Mod(l, n)
+}
+
+// RequestRecovery opens a timelocked recovery of lostIndex's share for a
+// new holder with address recipient and encryption key newEncKey
+func (c *Coordinator) RequestRecovery(
+	sessionID common.Hash,
+	requester common.Address,
+	lostIndex uint32,
+	recipient common.Address,
+	newEncKey []byte,
+) (common.Hash, error) {
+	if _, _, err := unmarshalPoint(newEncKey); err != nil {
+		return common.Hash{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.Sessions[sessionID]
+	if !ok {
+		return common.Hash{}, ErrSessionNotFound
+	}
+	if !s.Finalized {
+		return common.Hash{}, ErrSessionNotFinalized
+	}
+	if _, ok := s.participant[requester]; !ok {
+		return common.Hash{}, ErrNotParticipant
+	}
+	if lostIndex == 0 || int(lostIndex) > len(s.Participants) {
+		return common.Hash{}, ErrInvalidIndex
+	}
+	if existing, ok := s.participant[recipient]; ok && existing.Index != lostIndex {
+		return common.Hash{}, ErrDuplicateParticipant
+	}
+
+	c.nonce++
+	var nonce [8]byte
+	binary.BigEndian.PutUint64(nonce[:], c.nonce)
+	id := common.BytesToHash(crypto.Keccak256([]byte("recovery"), sessionID[:], nonce[:]))
+
+	c.Recoveries[id] = &Recovery{
+		ID:            id,
+		SessionID:     sessionID,
+		LostIndex:     lostIndex,
+		Recipient:     recipient,
+		NewEncKey:     common.CopyBytes(newEncKey),
+		UnlockAt:      c.now() + c.RecoveryDelay,
+		Contributions: make(map[common.Address][]byte),
+		Masks:         make(map[common.Address][]byte),
+	}
+	return id, nil
+}
+
+// ApproveRecovery records a shareholder's approval. The first Threshold
+// approvers become the helper set.
+func (c *Coordinator) ApproveRecovery(recoveryID common.Hash, approver common.Address) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, r, err := c.openRecovery(recoveryID)
+	if err != nil {
+		return err
+	}
+	member, ok := s.participant[approver]
+	if !ok || member.Index == r.LostIndex {
+		return ErrNotParticipant
+	}
+	if s.Disqualified[approver] {
+		return ErrDisqualified
+	}
+	for _, h := range r.Helpers {
+		if h == approver {
+			return ErrAlreadyApproved
+		}
+	}
+	if uint32(len(r.Helpers)) >= s.Threshold {
+		return ErrHelpersSelected
+	}
+
+	r.Helpers = append(r.Helpers, approver)
+	return nil
+}
+
+// SubmitRecoveryShare records a helper's encrypted contribution c_i and
+// mask image M_i once the timelock has expired
+func (c *Coordinator) SubmitRecoveryShare(
+	recoveryID common.Hash,
+	helper common.Address,
+	encContribution []byte,
+	mask []byte,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, r, err := c.openRecovery(recoveryID)
+	if err != nil {
+		return err
+	}
+	if uint32(len(r.Helpers)) < s.Threshold {
+		return ErrNotEnoughApprovals
+	}
+	if c.now() < r.UnlockAt {
+		return ErrRecoveryLocked
+	}
+	if !r.isHelper(helper) {
+		return ErrNotHelper
+	}
+	if _, exists := r.Contributions[helper]; exists {
+		return ErrAlreadySubmitted
+	}
+	if len(encContribution) != ScalarSize {
+		return ErrInvalidShares
+	}
+	if _, _, err := unmarshalPoint(mask); err != nil {
+		return err
+	}
+
+	r.Contributions[helper] = common.CopyBytes(encContribution)
+	r.Masks[helper] = common.CopyBytes(mask)
+	return nil
+}
+
+// ExpectedContribution returns l_i(r)*X_i + M_i, the public image the
+// recipient checks helper's decrypted contribution against
+func (c *Coordinator) ExpectedContribution(recoveryID common.Hash, helper common.Address) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r, ok := c.Recoveries[recoveryID]
+	if !ok {
+		return nil, ErrRecoveryNotFound
+	}
+	mask, ok := r.Masks[helper]
+	if !ok {
+		return nil, ErrSharesMissing
+	}
+	return c.expectedContribution(c.Sessions[r.SessionID], r, helper, mask)
+}
+
+// ComplainRecovery lets the recipient prove that helper's contribution does
+// not match its public image. sharedKey is the ECDH point between the
+// recipient's new key and the helper's encryption key and proof is a DLEQ
+// proof that it was derived from the new key. If the decrypted contribution
+// c_i fails c_i*G == l_i(r)*X_i + M_i, with X_i taken from the Feldman
+// commitments, the recovery is aborted and helper is recorded as faulty.
+func (c *Coordinator) ComplainRecovery(
+	recoveryID common.Hash,
+	complainer common.Address,
+	helper common.Address,
+	sharedKey []byte,
+	proof []byte,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, r, err := c.openRecovery(recoveryID)
+	if err != nil {
+		return err
+	}
+	if complainer != r.Recipient {
+		return ErrNotRecipient
+	}
+	if !r.isHelper(helper) {
+		return ErrNotHelper
+	}
+	encContribution, ok := r.Contributions[helper]
+	if !ok {
+		return ErrSharesMissing
+	}
+	image, err := c.expectedContribution(s, r, helper, r.Masks[helper])
+	if err != nil {
+		return err
+	}
+
+	recipient := &Participant{Address: r.Recipient, EncKey: r.NewEncKey}
+	err = checkComplaint(recipient, s.participant[helper], encContribution, sharedKey, proof, func(part *big.Int) (bool, error) {
+		x, y := curve.ScalarBaseMult(scalarBytes(part))
+		return string(curve.Marshal(x, y)) == string(image), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.Aborted = true
+	r.Faulty = helper
+	return nil
+}
+
+// FinalizeRecovery hands the lost index to the recipient. Every helper must
+// have contributed and the masks must cancel. Only the recipient may
+// finalize: doing so asserts it checked each contribution against
+// ExpectedContribution, and any that failed should be proven with
+// ComplainRecovery instead. It returns the public share the recovered share
+// matches.
+func (c *Coordinator) FinalizeRecovery(recoveryID common.Hash, caller common.Address) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, r, err := c.openRecovery(recoveryID)
+	if err != nil {
+		return nil, err
+	}
+	if caller != r.Recipient {
+		return nil, ErrNotRecipient
+	}
+	if uint32(len(r.Helpers)) < s.Threshold {
+		return nil, ErrNotEnoughApprovals
+	}
+	if c.now() < r.UnlockAt {
+		return nil, ErrRecoveryLocked
+	}
+
+	masks := make([][]byte, 0, len(r.Helpers))
+	for _, helper := range r.Helpers {
+		mask, ok := r.Masks[helper]
+		if !ok {
+			return nil, ErrIncompleteRound
+		}
+		masks = append(masks, mask)
+	}
+	if !sumIsIdentity(masks) {
+		return nil, ErrInvalidMasks
+	}
+
+	public, err := EvaluateCommitment(s.Commitment, r.LostIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	lost := s.Participants[r.LostIndex-1]
+	delete(s.participant, lost.Address)
+	lost.Address = r.Recipient
+	lost.EncKey = common.CopyBytes(r.NewEncKey)
+	s.participant[r.Recipient] = lost
+
+	r.Completed = true
+	return public, nil
+}
+
+// openRecovery returns an incomplete, unaborted recovery and its session
+func (c *Coordinator) openRecovery(recoveryID common.Hash) (*Session, *Recovery, error) {
+	r, ok := c.Recoveries[recoveryID]
+	if !ok {
+		return nil, nil, ErrRecoveryNotFound
+	}
+	if r.Completed {
+		return nil, nil, ErrRecoveryCompleted
+	}
+	if r.Aborted {
+		return nil, nil, ErrRecoveryAborted
+	}
+	return c.Sessions[r.SessionID], r, nil
+}
+
+// expectedContribution computes l_i(r)*X_i + M_i for helper
+func (c *Coordinator) expectedContribution(s *Session, r *Recovery, helper common.Address, mask []byte) ([]byte, error) {
+	indices := make([]uint32, len(r.Helpers))
+	for i, h := range r.Helpers {
+		indices[i] = s.participant[h].Index
+	}
+	index := s.participant[helper].Index
+
+	public, err := EvaluateCommitment(s.Commitment, index)
+	if err != nil {
+		return nil, err
+	}
+	px, py, err := unmarshalPoint(public)
+	if err != nil {
+		return nil, err
+	}
+	lx, ly := curve.ScalarMult(px, py, scalarBytes(LagrangeCoefficient(indices, index, r.LostIndex)))
+	return addPoints(curve.Marshal(lx, ly), mask)
+}
+
+func (r *Recovery) isHelper(addr common.Address) bool {
+	for _, h := range r.Helpers {
+		if h == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// sumIsIdentity reports whether the points sum to the point at infinity
+func sumIsIdentity(points [][]byte) bool {
+	accX, accY := new(big.Int), new(big.Int)
+	for _, p := range points {
+		px, py, err := unmarshalPoint(p)
+		if err != nil {
+			return false
+		}
+		accX, accY = curve.Add(accX, accY, px, py)
+	}
+	return accX.Sign() == 0 && accY.Sign() == 0
+}
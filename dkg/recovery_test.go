@@ -0,0 +1,166 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dkg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoveryRebuildsLostShare(t *testing.T) {
+	c, s, parties, shares := runTestDKG(t, 3, 2)
+	now := uint64(1000)
+	c.now = func() uint64 { return now }
+
+	// Party 3 lost its share and re-enrolls under a new address and key
+	newPriv := randomScalar(t)
+	newPub := scalarPoint(newPriv)
+	recipient := common.Address{0xee}
+
+	id, err := c.RequestRecovery(s.ID, parties[0].addr, 3, recipient, newPub)
+	require.NoError(t, err)
+
+	for _, p := range parties[:2] {
+		require.NoError(t, c.ApproveRecovery(id, p.addr))
+	}
+	require.ErrorIs(t, c.ApproveRecovery(id, parties[2].addr), ErrNotParticipant)
+
+	// Helper masks sum to zero
+	n := curve.Params().N
+	mask := randomScalar(t)
+	masks := []*big.Int{mask, new(big.Int).Sub(n, mask)}
+
+	contributions := make([][]byte, 2)
+	for i, p := range parties[:2] {
+		part := LagrangeCoefficient([]uint32{1, 2}, uint32(i+1), 3)
+		part.Mul(part, shares[i]).Add(part, masks[i]).Mod(part, n)
+		key, err := SharedKey(p.encPriv, newPub)
+		require.NoError(t, err)
+		contributions[i] = EncryptShare(part, key)
+	}
+
+	require.ErrorIs(t, c.SubmitRecoveryShare(id, parties[0].addr, contributions[0], scalarPoint(masks[0])), ErrRecoveryLocked)
+
+	now += DefaultRecoveryDelay
+	for i, p := range parties[:2] {
+		require.NoError(t, c.SubmitRecoveryShare(id, p.addr, contributions[i], scalarPoint(masks[i])))
+	}
+
+	// Only the recipient, having checked the contributions, can finalize
+	_, err = c.FinalizeRecovery(id, parties[0].addr)
+	require.ErrorIs(t, err, ErrNotRecipient)
+	public, err := c.FinalizeRecovery(id, recipient)
+	require.NoError(t, err)
+
+	// The recipient decrypts, checks and sums the contributions
+	recovered := new(big.Int)
+	for _, p := range parties[:2] {
+		key, err := SharedKey(newPriv, p.encPub)
+		require.NoError(t, err)
+		part := DecryptShare(c.Recoveries[id].Contributions[p.addr], key)
+
+		expected, err := c.ExpectedContribution(id, p.addr)
+		require.NoError(t, err)
+		require.Equal(t, expected, scalarPoint(part))
+		recovered.Add(recovered, part)
+	}
+	recovered.Mod(recovered, n)
+
+	require.Equal(t, shares[2], recovered)
+	require.Equal(t, scalarPoint(recovered), public)
+	require.Equal(t, recipient, s.Participants[2].Address)
+	require.Equal(t, newPub, s.Participants[2].EncKey)
+
+	_, err = c.FinalizeRecovery(id, recipient)
+	require.ErrorIs(t, err, ErrRecoveryCompleted)
+}
+
+func TestRecoveryBelowThresholdRejected(t *testing.T) {
+	c, s, parties, shares := runTestDKG(t, 3, 2)
+	now := uint64(1000)
+	c.now = func() uint64 { return now }
+
+	newPub := scalarPoint(randomScalar(t))
+	id, err := c.RequestRecovery(s.ID, parties[0].addr, 3, common.Address{0xee}, newPub)
+	require.NoError(t, err)
+
+	require.NoError(t, c.ApproveRecovery(id, parties[0].addr))
+	require.ErrorIs(t, c.ApproveRecovery(id, parties[0].addr), ErrAlreadyApproved)
+
+	now += DefaultRecoveryDelay
+	key, err := SharedKey(parties[0].encPriv, newPub)
+	require.NoError(t, err)
+	enc := EncryptShare(shares[0], key)
+	require.ErrorIs(t, c.SubmitRecoveryShare(id, parties[0].addr, enc, scalarPoint(randomScalar(t))), ErrNotEnoughApprovals)
+
+	_, err = c.FinalizeRecovery(id, common.Address{0xee})
+	require.ErrorIs(t, err, ErrNotEnoughApprovals)
+
+	// Masks that do not cancel are rejected even with enough helpers
+	require.NoError(t, c.ApproveRecovery(id, parties[1].addr))
+	require.ErrorIs(t, c.ApproveRecovery(id, common.Address{0x01}), ErrNotParticipant)
+	for _, p := range parties[:2] {
+		require.NoError(t, c.SubmitRecoveryShare(id, p.addr, enc, scalarPoint(randomScalar(t))))
+	}
+	_, err = c.FinalizeRecovery(id, common.Address{0xee})
+	require.ErrorIs(t, err, ErrInvalidMasks)
+}
+
+func TestRecoveryComplaintAborts(t *testing.T) {
+	c, s, parties, shares := runTestDKG(t, 3, 2)
+	now := uint64(1000)
+	c.now = func() uint64 { return now }
+
+	newPriv := randomScalar(t)
+	newPub := scalarPoint(newPriv)
+	recipient := common.Address{0xee}
+
+	id, err := c.RequestRecovery(s.ID, parties[0].addr, 3, recipient, newPub)
+	require.NoError(t, err)
+	for _, p := range parties[:2] {
+		require.NoError(t, c.ApproveRecovery(id, p.addr))
+	}
+	now += DefaultRecoveryDelay
+
+	// The masks cancel, but the second helper's contribution is off by one
+	n := curve.Params().N
+	mask := randomScalar(t)
+	masks := []*big.Int{mask, new(big.Int).Sub(n, mask)}
+	for i, p := range parties[:2] {
+		part := LagrangeCoefficient([]uint32{1, 2}, uint32(i+1), 3)
+		part.Mul(part, shares[i]).Add(part, masks[i]).Mod(part, n)
+		if i == 1 {
+			part.Add(part, big.NewInt(1))
+		}
+		key, err := SharedKey(p.encPriv, newPub)
+		require.NoError(t, err)
+		require.NoError(t, c.SubmitRecoveryShare(id, p.addr, EncryptShare(part, key), scalarPoint(masks[i])))
+	}
+
+	honest, faulty := parties[0], parties[1]
+
+	// Only the recipient can complain, and only with a valid proof
+	sharedKey, proof, err := ProveSharedKey(newPriv, faulty.encPub, randomScalar(t))
+	require.NoError(t, err)
+	require.ErrorIs(t, c.ComplainRecovery(id, honest.addr, faulty.addr, sharedKey, proof), ErrNotRecipient)
+	require.ErrorIs(t, c.ComplainRecovery(id, recipient, faulty.addr, scalarPoint(randomScalar(t)), proof), ErrInvalidProof)
+	require.ErrorIs(t, c.ComplainRecovery(id, recipient, parties[2].addr, sharedKey, proof), ErrNotHelper)
+
+	// A complaint against a consistent contribution is rejected
+	honestKey, honestProof, err := ProveSharedKey(newPriv, honest.encPub, randomScalar(t))
+	require.NoError(t, err)
+	require.ErrorIs(t, c.ComplainRecovery(id, recipient, honest.addr, honestKey, honestProof), ErrInvalidComplaint)
+
+	require.NoError(t, c.ComplainRecovery(id, recipient, faulty.addr, sharedKey, proof))
+	require.True(t, c.Recoveries[id].Aborted)
+	require.Equal(t, faulty.addr, c.Recoveries[id].Faulty)
+
+	// The aborted recovery cannot complete and the lost slot is unchanged
+	_, err = c.FinalizeRecovery(id, recipient)
+	require.ErrorIs(t, err, ErrRecoveryAborted)
+	require.Equal(t, parties[2].addr, s.Participants[2].Address)
+}
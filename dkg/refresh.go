@@ -0,0 +1,253 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dkg
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Proactive secret resharing (LP-5221)
+//
+// Each shareholder deals a polynomial d_i with d_i(0) = 0 and commits only
+// to its non-constant coefficients, so every contribution is zero-sum by
+// construction. Shareholder j adds sum_i d_i(j) to its share. The group key
+// C_0 is unchanged while every other coefficient moves, so shares from
+// before and after a refresh no longer combine.
+
+var (
+	// ContractRefreshAddress is the address of the key refresh precompile (LP-5221)
+	ContractRefreshAddress = common.HexToAddress("0x0000000000000000000000000000000000005221")
+
+	ErrSessionNotFinalized = errors.New("DKG session not finalized")
+	ErrRefreshInProgress   = errors.New("refresh already in progress")
+	ErrNoRefresh           = errors.New("no refresh in progress")
+	ErrRefreshUnsupported  = errors.New("refresh requires threshold > 1")
+)
+
+// RefreshGas is the flat cost of a key refresh call
+const RefreshGas uint64 = 100_000
+
+// RefreshRound collects zero-sum resharing contributions for one epoch
+type RefreshRound struct {
+	Epoch        uint64
+	Commitments  map[common.Address][][]byte // dealer -> C_1..C_{t-1}
+	Shares       map[common.Address][][]byte // dealer -> encrypted d_i(j)
+	Disqualified map[common.Address]bool
+}
+
+// EvaluateZeroCommitment returns the public image of a zero-sum share:
+// sum(C_k * index^k) for k = 1..t-1, with C_0 implicitly the identity
+func EvaluateZeroCommitment(commitments [][]byte, index uint32) ([]byte, error) {
+	if index == 0 {
+		return nil, ErrInvalidIndex
+	}
+	// Shift the coefficients up one degree: sum(C_k x^k) = x * sum(C_k x^(k-1))
+	inner, err := EvaluateCommitment(commitments, index)
+	if err != nil {
+		return nil, err
+	}
+	ix, iy, err := unmarshalPoint(inner)
+	if err != nil {
+		return nil, err
+	}
+	x, y := curve.ScalarMult(ix, iy, scalarBytes(big.NewInt(int64(index))))
+	return curve.Marshal(x, y), nil
+}
+
+// VerifyZeroShare checks delta*G == sum(C_k * index^k) for k = 1..t-1
+func VerifyZeroShare(commitments [][]byte, index uint32, delta *big.Int) (bool, error) {
+	if delta == nil || delta.Sign() <= 0 || delta.Cmp(curve.Params().N) >= 0 {
+		return false, nil
+	}
+	expected, err := EvaluateZeroCommitment(commitments, index)
+	if err != nil {
+		return false, err
+	}
+	dx, dy := curve.ScalarBaseMult(scalarBytes(delta))
+	return string(curve.Marshal(dx, dy)) == string(expected), nil
+}
+
+// StartRefresh opens a refresh round on a finalized session
+func (c *Coordinator) StartRefresh(sessionID common.Hash) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.Sessions[sessionID]
+	if !ok {
+		return 0, ErrSessionNotFound
+	}
+	if !s.Finalized {
+		return 0, ErrSessionNotFinalized
+	}
+	if s.Threshold < 2 {
+		return 0, ErrRefreshUnsupported
+	}
+	if s.Refresh != nil {
+		return 0, ErrRefreshInProgress
+	}
+
+	s.Refresh = &RefreshRound{
+		Epoch:        s.Epoch + 1,
+		Commitments:  make(map[common.Address][][]byte),
+		Shares:       make(map[common.Address][][]byte),
+		Disqualified: make(map[common.Address]bool),
+	}
+	return s.Refresh.Epoch, nil
+}
+
+// SubmitRefresh records a dealer's zero-sum contribution: commitments to
+// d_i's coefficients 1..t-1 and one encrypted d_i(j) per participant
+func (c *Coordinator) SubmitRefresh(
+	sessionID common.Hash,
+	dealer common.Address,
+	commitments [][]byte,
+	encShares [][]byte,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, r, err := c.refreshRound(sessionID, dealer)
+	if err != nil {
+		return err
+	}
+	if _, exists := r.Commitments[dealer]; exists {
+		return ErrAlreadySubmitted
+	}
+	if uint32(len(commitments)) != s.Threshold-1 {
+		return ErrInvalidCommitments
+	}
+	if len(encShares) != len(s.Participants) {
+		return ErrInvalidShares
+	}
+
+	storedCommitments := make([][]byte, len(commitments))
+	for k, cm := range commitments {
+		if _, _, err := unmarshalPoint(cm); err != nil {
+			return ErrInvalidCommitments
+		}
+		storedCommitments[k] = common.CopyBytes(cm)
+	}
+	storedShares := make([][]byte, len(encShares))
+	for j, e := range encShares {
+		if len(e) != ScalarSize {
+			return ErrInvalidShares
+		}
+		storedShares[j] = common.CopyBytes(e)
+	}
+
+	r.Commitments[dealer] = storedCommitments
+	r.Shares[dealer] = storedShares
+	return nil
+}
+
+// ComplainRefresh disqualifies a refresh dealer whose delta for complainer
+// is not on its committed zero-sum polynomial
+func (c *Coordinator) ComplainRefresh(
+	sessionID common.Hash,
+	complainer common.Address,
+	dealer common.Address,
+	sharedKey []byte,
+	proof []byte,
+) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, r, err := c.refreshRound(sessionID, complainer)
+	if err != nil {
+		return err
+	}
+	accused, ok := s.participant[dealer]
+	if !ok {
+		return ErrNotParticipant
+	}
+	if r.Disqualified[dealer] {
+		return ErrDisqualified
+	}
+	encShares, ok := r.Shares[dealer]
+	if !ok {
+		return ErrSharesMissing
+	}
+	member := s.participant[complainer]
+
+	err = checkComplaint(member, accused, encShares[member.Index-1], sharedKey, proof, func(delta *big.Int) (bool, error) {
+		return VerifyZeroShare(r.Commitments[dealer], member.Index, delta)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.Disqualified[dealer] = true
+	return nil
+}
+
+// FinalizeRefresh folds the qualified contributions into the session's
+// commitment and advances the epoch. The group key is unchanged.
+func (c *Coordinator) FinalizeRefresh(sessionID common.Hash) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.Sessions[sessionID]
+	if !ok {
+		return 0, ErrSessionNotFound
+	}
+	r := s.Refresh
+	if r == nil {
+		return 0, ErrNoRefresh
+	}
+
+	var qualified []common.Address
+	for _, p := range s.Participants {
+		if s.Disqualified[p.Address] || r.Disqualified[p.Address] {
+			continue
+		}
+		if _, ok := r.Shares[p.Address]; !ok {
+			return 0, ErrIncompleteRound
+		}
+		qualified = append(qualified, p.Address)
+	}
+	if uint32(len(qualified)) < s.Threshold {
+		return 0, ErrNotEnoughQualified
+	}
+
+	combined := make([][]byte, s.Threshold)
+	combined[0] = s.Commitment[0]
+	for k := 1; k < len(combined); k++ {
+		terms := [][]byte{s.Commitment[k]}
+		for _, dealer := range qualified {
+			terms = append(terms, r.Commitments[dealer][k-1])
+		}
+		sum, err := addPoints(terms...)
+		if err != nil {
+			return 0, err
+		}
+		combined[k] = sum
+	}
+
+	s.Commitment = combined
+	s.Epoch = r.Epoch
+	s.Refresh = nil
+	return s.Epoch, nil
+}
+
+// refreshRound returns the open refresh round that caller may act in.
+// Dealers disqualified during the DKG cannot contribute.
+func (c *Coordinator) refreshRound(sessionID common.Hash, caller common.Address) (*Session, *RefreshRound, error) {
+	s, ok := c.Sessions[sessionID]
+	if !ok {
+		return nil, nil, ErrSessionNotFound
+	}
+	if s.Refresh == nil {
+		return nil, nil, ErrNoRefresh
+	}
+	if _, ok := s.participant[caller]; !ok {
+		return nil, nil, ErrNotParticipant
+	}
+	if s.Disqualified[caller] || s.Refresh.Disqualified[caller] {
+		return nil, nil, ErrDisqualified
+	}
+	return s, s.Refresh, nil
+}
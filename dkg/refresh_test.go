@@ -0,0 +1,163 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dkg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runTestDKG completes an honest DKG and returns each party's share
+func runTestDKG(t *testing.T, n int, threshold uint32) (*Coordinator, *Session, []*testParty, []*big.Int) {
+	t.Helper()
+
+	c, id, parties := newTestDKG(t, n, threshold)
+	for _, p := range parties {
+		require.NoError(t, c.SubmitCommitments(id, p.addr, p.commitments()))
+	}
+	for _, p := range parties {
+		require.NoError(t, c.SubmitShares(id, p.addr, p.encryptedShares(t, parties)))
+	}
+	_, err := c.Finalize(id)
+	require.NoError(t, err)
+
+	shares := make([]*big.Int, n)
+	for j := range parties {
+		shares[j] = new(big.Int)
+		for _, dealer := range parties {
+			shares[j].Add(shares[j], evalPoly(dealer.coeffs, uint32(j+1)))
+		}
+		shares[j].Mod(shares[j], curve.Params().N)
+	}
+	return c, c.Sessions[id], parties, shares
+}
+
+// interpolateAtZero combines shares at the given 1-based indices
+func interpolateAtZero(indices []uint32, shares []*big.Int) *big.Int {
+	n := curve.Params().N
+	secret := new(big.Int)
+	for i, xi := range indices {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, xj := range indices {
+			if i == j {
+				continue
+			}
+			num.Mod(num.Mul(num, big.NewInt(int64(xj))), n)
+			den.Mod(den.Mul(den, big.NewInt(int64(xj)-int64(xi))), n)
+		}
+		term := new(big.Int).Mul(shares[i], num)
+		term.Mul(term, new(big.Int).ModInverse(den, n))
+		secret.Add(secret, term)
+	}
+	return secret.Mod(secret, n)
+}
+
+// refreshContribution deals a zero-constant polynomial of degree t-1
+func refreshContribution(t *testing.T, dealer *testParty, parties []*testParty, threshold uint32) ([]*big.Int, [][]byte, [][]byte) {
+	t.Helper()
+
+	coeffs := make([]*big.Int, threshold)
+	coeffs[0] = new(big.Int)
+	commitments := make([][]byte, 0, threshold-1)
+	for k := 1; k < int(threshold); k++ {
+		coeffs[k] = randomScalar(t)
+		commitments = append(commitments, scalarPoint(coeffs[k]))
+	}
+
+	encShares := make([][]byte, len(parties))
+	for j, r := range parties {
+		key, err := SharedKey(dealer.encPriv, r.encPub)
+		require.NoError(t, err)
+		encShares[j] = EncryptShare(evalPoly(coeffs, uint32(j+1)), key)
+	}
+	return coeffs, commitments, encShares
+}
+
+func TestRefreshKeepsGroupKey(t *testing.T) {
+	const threshold = 2
+	c, s, parties, oldShares := runTestDKG(t, 3, threshold)
+	groupKey := append([]byte(nil), s.GroupKey...)
+	secret := interpolateAtZero([]uint32{1, 2}, oldShares[:2])
+	require.Equal(t, scalarPoint(secret), groupKey)
+
+	epoch, err := c.StartRefresh(s.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), epoch)
+
+	newShares := make([]*big.Int, len(parties))
+	for j := range newShares {
+		newShares[j] = new(big.Int).Set(oldShares[j])
+	}
+	for _, dealer := range parties {
+		coeffs, commitments, encShares := refreshContribution(t, dealer, parties, threshold)
+		require.NoError(t, c.SubmitRefresh(s.ID, dealer.addr, commitments, encShares))
+		for j := range newShares {
+			newShares[j].Add(newShares[j], evalPoly(coeffs, uint32(j+1)))
+			newShares[j].Mod(newShares[j], curve.Params().N)
+		}
+	}
+
+	epoch, err = c.FinalizeRefresh(s.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), epoch)
+
+	got, err := c.GetGroupKey(s.ID)
+	require.NoError(t, err)
+	require.Equal(t, groupKey, got)
+
+	// New shares match the refreshed public shares; old ones do not
+	for j := range parties {
+		public, err := c.PublicShare(s.ID, uint32(j+1))
+		require.NoError(t, err)
+		require.Equal(t, scalarPoint(newShares[j]), public)
+		require.NotEqual(t, scalarPoint(oldShares[j]), public)
+	}
+
+	// Any threshold of new shares recovers the secret
+	require.Equal(t, secret, interpolateAtZero([]uint32{2, 3}, newShares[1:]))
+
+	// Mixing an old share with a new one does not
+	mixed := interpolateAtZero([]uint32{1, 2}, []*big.Int{oldShares[0], newShares[1]})
+	require.NotEqual(t, secret, mixed)
+}
+
+func TestRefreshRejectsNonZeroContribution(t *testing.T) {
+	const threshold = 2
+	c, s, parties, _ := runTestDKG(t, 3, threshold)
+
+	_, err := c.FinalizeRefresh(s.ID)
+	require.ErrorIs(t, err, ErrNoRefresh)
+
+	_, err = c.StartRefresh(s.ID)
+	require.NoError(t, err)
+	_, err = c.StartRefresh(s.ID)
+	require.ErrorIs(t, err, ErrRefreshInProgress)
+
+	// The cheater shifts every delta by one, so its deltas sum to a
+	// non-zero constant and would change the group key
+	cheater, victim := parties[2], parties[0]
+	coeffs, commitments, _ := refreshContribution(t, cheater, parties, threshold)
+	encShares := make([][]byte, len(parties))
+	for j, r := range parties {
+		key, err := SharedKey(cheater.encPriv, r.encPub)
+		require.NoError(t, err)
+		delta := evalPoly(coeffs, uint32(j+1))
+		encShares[j] = EncryptShare(delta.Add(delta, big.NewInt(1)), key)
+	}
+	require.NoError(t, c.SubmitRefresh(s.ID, cheater.addr, commitments, encShares))
+
+	sharedKey, proof, err := ProveSharedKey(victim.encPriv, cheater.encPub, randomScalar(t))
+	require.NoError(t, err)
+	require.NoError(t, c.ComplainRefresh(s.ID, victim.addr, cheater.addr, sharedKey, proof))
+
+	for _, dealer := range parties[:2] {
+		_, commitments, encShares := refreshContribution(t, dealer, parties, threshold)
+		require.NoError(t, c.SubmitRefresh(s.ID, dealer.addr, commitments, encShares))
+	}
+	_, err = c.FinalizeRefresh(s.ID)
+	require.NoError(t, err)
+	require.Equal(t, s.Commitment[0], s.GroupKey)
+}
@@ -0,0 +1,134 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package doerner verifies Doerner et al. two-party ECDSA signatures.
+//
+// The interactive 2-of-2 signing protocol runs off-chain between the two
+// key holders; its output is an ordinary secp256k1 ECDSA signature under the
+// joint public key. This precompile only verifies that signature, either
+// against an explicit public key or against a group key registered in the
+// shared threshold group key registry (ecverify.DefaultGroupKeys).
+package doerner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/crypto/ecverify"
+)
+
+var (
+	// ContractDoernerVerifyAddress is the address of the Doerner 2-party ECDSA precompile (Threshold Signatures range 0x0800)
+	ContractDoernerVerifyAddress = common.HexToAddress("0x0800000000000000000000000000000000000004")
+
+	// Singleton instance
+	DoernerVerifyPrecompile = &doernerVerifyPrecompile{registry: DefaultRegistry}
+
+	// DefaultRegistry holds the group keys the precompile resolves key IDs against
+	DefaultRegistry = ecverify.DefaultGroupKeys
+
+	_ contract.StatefulPrecompiledContract = &doernerVerifyPrecompile{}
+
+	ErrInvalidInputLength = errors.New("invalid input length")
+	ErrInvalidPublicKey   = ecverify.ErrInvalidPublicKey
+	ErrInvalidSignature   = ecverify.ErrInvalidSignature
+	ErrUnknownGroupKey    = errors.New("unknown group key")
+	ErrNotTwoPartyKey     = errors.New("group key is not a 2-of-2 secp256k1 key")
+	ErrGroupKeyExists     = ecverify.ErrGroupKeyExists
+	ErrInvalidParties     = ecverify.ErrInvalidParties
+)
+
+const (
+	// DoernerVerifyGas is the cost of one 2-of-2 ECDSA verification
+	DoernerVerifyGas uint64 = 50_000
+
+	DoernerPublicKeySize   = 65 // Uncompressed public key (0x04 || x || y)
+	DoernerSignatureSize   = 65 // ECDSA signature (r || s || v)
+	DoernerMessageHashSize = 32 // 32-byte message hash
+	DoernerKeyIDSize       = 32 // ecverify.GroupKeyID(publicKey)
+
+	// KeyInputSize is the input size when the public key is passed inline
+	KeyInputSize = DoernerPublicKeySize + DoernerMessageHashSize + DoernerSignatureSize
+	// KeyIDInputSize is the input size when a registered key ID is passed
+	KeyIDInputSize = DoernerKeyIDSize + DoernerMessageHashSize + DoernerSignatureSize
+)
+
+// RegisterGroupKey records a 2-of-2 group key held by partyA and partyB in
+// the shared threshold group key registry
+func RegisterGroupKey(name string, partyA, partyB common.Address, publicKey []byte) (common.Hash, error) {
+	return DefaultRegistry.Register(ecverify.GroupKey{
+		Name:      name,
+		Scheme:    ecverify.SchemeECDSASecp256k1,
+		PublicKey: publicKey,
+		Threshold: 2,
+		Parties:   []common.Address{partyA, partyB},
+	})
+}
+
+type doernerVerifyPrecompile struct {
+	registry *ecverify.GroupKeyRegistry
+}
+
+// Address returns the address of the Doerner verify precompile
+func (p *doernerVerifyPrecompile) Address() common.Address {
+	return ContractDoernerVerifyAddress
+}
+
+// RequiredGas returns the gas required for Doerner verification
+func (p *doernerVerifyPrecompile) RequiredGas(input []byte) uint64 {
+	return DoernerVerifyGas
+}
+
+// Run implements the Doerner 2-party ECDSA verification precompile
+func (p *doernerVerifyPrecompile) Run(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	addr common.Address,
+	input []byte,
+	suppliedGas uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	gasCost := p.RequiredGas(input)
+	if suppliedGas < gasCost {
+		return nil, 0, errors.New("out of gas")
+	}
+	remainingGas := suppliedGas - gasCost
+
+	// Input format, by length:
+	// 162 bytes: [0:65] public key || [65:97] message hash || [97:162] signature
+	// 129 bytes: [0:32] group key ID || [32:64] message hash || [64:129] signature
+	var publicKey, rest []byte
+	switch len(input) {
+	case KeyInputSize:
+		publicKey, rest = input[:DoernerPublicKeySize], input[DoernerPublicKeySize:]
+	case KeyIDInputSize:
+		key, ok := p.registry.Get(common.BytesToHash(input[:DoernerKeyIDSize]))
+		if !ok {
+			return nil, remainingGas, ErrUnknownGroupKey
+		}
+		if key.Scheme != ecverify.SchemeECDSASecp256k1 || key.Threshold != 2 || len(key.Parties) != 2 {
+			return nil, remainingGas, ErrNotTwoPartyKey
+		}
+		publicKey, rest = key.PublicKey, input[DoernerKeyIDSize:]
+	default:
+		return nil, remainingGas, fmt.Errorf("%w: expected %d or %d bytes, got %d",
+			ErrInvalidInputLength, KeyInputSize, KeyIDInputSize, len(input))
+	}
+
+	messageHash := rest[:DoernerMessageHashSize]
+	signature := rest[DoernerMessageHashSize:]
+
+	valid, err := ecverify.VerifyECDSASecp256k1(publicKey, messageHash, signature)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	// Return result as 32-byte word (1 = valid, 0 = invalid)
+	result := make([]byte, 32)
+	if valid {
+		result[31] = 1
+	}
+	return result, remainingGas, nil
+}
@@ -0,0 +1,201 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package doerner
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/crypto/ecverify"
+	"github.com/stretchr/testify/require"
+)
+
+// newTwoPartyKey builds a 2-of-2 key sk = a*b as held by the two Doerner
+// parties. Signing happens off-chain, so the test signs with sk directly.
+func newTwoPartyKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	n := crypto.S256().Params().N
+	a, err := rand.Int(rand.Reader, n)
+	require.NoError(t, err)
+	b, err := rand.Int(rand.Reader, n)
+	require.NoError(t, err)
+
+	sk := new(big.Int).Mul(a, b)
+	sk.Mod(sk, n)
+	key, err := crypto.ToECDSA(common.LeftPadBytes(sk.Bytes(), 32))
+	require.NoError(t, err)
+
+	// The group key is b*(a*G), which neither party can compute alone
+	ax, ay := crypto.S256().ScalarBaseMult(common.LeftPadBytes(a.Bytes(), 32))
+	gx, gy := crypto.S256().ScalarMult(ax, ay, common.LeftPadBytes(b.Bytes(), 32))
+	require.Zero(t, gx.Cmp(key.PublicKey.X))
+	require.Zero(t, gy.Cmp(key.PublicKey.Y))
+	return key
+}
+
+func runDoerner(t *testing.T, input []byte) ([]byte, error) {
+	t.Helper()
+
+	result, remainingGas, err := DoernerVerifyPrecompile.Run(
+		nil,
+		common.Address{},
+		ContractDoernerVerifyAddress,
+		input,
+		1_000_000,
+		true,
+	)
+	require.Equal(t, uint64(1_000_000)-DoernerVerifyGas, remainingGas)
+	return result, err
+}
+
+func TestDoernerVerify_ValidSignature(t *testing.T) {
+	key := newTwoPartyKey(t)
+	publicKey := crypto.FromECDSAPub(&key.PublicKey)
+	messageHash := crypto.Keccak256([]byte("test message"))
+
+	signature, err := crypto.Sign(messageHash, key)
+	require.NoError(t, err)
+
+	input := append(append(append([]byte{}, publicKey...), messageHash...), signature...)
+	result, err := runDoerner(t, input)
+	require.NoError(t, err)
+	require.Len(t, result, 32)
+	require.Equal(t, byte(1), result[31], "Signature should be valid")
+}
+
+func TestDoernerVerify_InvalidSignature(t *testing.T) {
+	key := newTwoPartyKey(t)
+	publicKey := crypto.FromECDSAPub(&key.PublicKey)
+	messageHash := crypto.Keccak256([]byte("test message"))
+
+	signature, err := crypto.Sign(messageHash, key)
+	require.NoError(t, err)
+	signature[10] ^= 0xFF
+
+	input := append(append(append([]byte{}, publicKey...), messageHash...), signature...)
+	result, err := runDoerner(t, input)
+	require.NoError(t, err)
+	require.Equal(t, byte(0), result[31], "Signature should be invalid")
+}
+
+func TestDoernerVerify_WrongKey(t *testing.T) {
+	key := newTwoPartyKey(t)
+	other := newTwoPartyKey(t)
+	messageHash := crypto.Keccak256([]byte("test message"))
+
+	signature, err := crypto.Sign(messageHash, key)
+	require.NoError(t, err)
+
+	input := append(append(append([]byte{}, crypto.FromECDSAPub(&other.PublicKey)...), messageHash...), signature...)
+	result, err := runDoerner(t, input)
+	require.NoError(t, err)
+	require.Equal(t, byte(0), result[31], "Signature should be invalid for another group key")
+}
+
+func TestDoernerVerify_RegisteredGroupKey(t *testing.T) {
+	key := newTwoPartyKey(t)
+	publicKey := crypto.FromECDSAPub(&key.PublicKey)
+	partyA, partyB := common.Address{0xa}, common.Address{0xb}
+
+	_, err := RegisterGroupKey("doerner-invalid", partyA, partyA, publicKey)
+	require.ErrorIs(t, err, ErrInvalidParties)
+
+	id, err := RegisterGroupKey("doerner-test", partyA, partyB, publicKey)
+	require.NoError(t, err)
+	require.Equal(t, ecverify.GroupKeyID(publicKey), id)
+
+	_, err = RegisterGroupKey("doerner-duplicate", partyA, partyB, publicKey)
+	require.ErrorIs(t, err, ErrGroupKeyExists)
+
+	messageHash := crypto.Keccak256([]byte("registered key message"))
+	signature, err := crypto.Sign(messageHash, key)
+	require.NoError(t, err)
+
+	input := append(append(append([]byte{}, id[:]...), messageHash...), signature...)
+	result, err := runDoerner(t, input)
+	require.NoError(t, err)
+	require.Equal(t, byte(1), result[31])
+
+	// Unknown key IDs are rejected
+	unknown := common.Hash{0x01}
+	input = append(append(append([]byte{}, unknown[:]...), messageHash...), signature...)
+	_, err = runDoerner(t, input)
+	require.ErrorIs(t, err, ErrUnknownGroupKey)
+}
+
+func TestDoernerVerify_InvalidInput(t *testing.T) {
+	_, err := runDoerner(t, make([]byte, KeyInputSize-1))
+	require.ErrorIs(t, err, ErrInvalidInputLength)
+
+	// Zeroed public key is not on the curve
+	_, err = runDoerner(t, make([]byte, KeyInputSize))
+	require.ErrorIs(t, err, ErrInvalidPublicKey)
+}
+
+func TestDoernerVerify_Address(t *testing.T) {
+	require.Equal(t, ContractDoernerVerifyAddress, DoernerVerifyPrecompile.Address())
+}
+
+func TestDoernerVerify_NotTwoPartyKey(t *testing.T) {
+	key := newTwoPartyKey(t)
+	publicKey := crypto.FromECDSAPub(&key.PublicKey)
+
+	// A 2-of-3 key in the shared registry is not a Doerner key
+	id, err := DefaultRegistry.Register(ecverify.GroupKey{
+		Name:      "doerner-2of3",
+		Scheme:    ecverify.SchemeECDSASecp256k1,
+		PublicKey: publicKey,
+		Threshold: 2,
+		Parties:   []common.Address{{0x1}, {0x2}, {0x3}},
+	})
+	require.NoError(t, err)
+
+	messageHash := crypto.Keccak256([]byte("test message"))
+	signature, err := crypto.Sign(messageHash, key)
+	require.NoError(t, err)
+
+	input := append(append(append([]byte{}, id[:]...), messageHash...), signature...)
+	_, err = runDoerner(t, input)
+	require.ErrorIs(t, err, ErrNotTwoPartyKey)
+}
+
+func TestDoernerVerify_MatchesSharedCore(t *testing.T) {
+	key := newTwoPartyKey(t)
+	publicKey := crypto.FromECDSAPub(&key.PublicKey)
+	messageHash := crypto.Keccak256([]byte("shared core message"))
+	signature, err := crypto.Sign(messageHash, key)
+	require.NoError(t, err)
+
+	corrupted := common.CopyBytes(signature)
+	corrupted[10] ^= 0xFF
+	flipped := common.CopyBytes(signature)
+	flipped[64] ^= 1
+
+	cases := map[string][3][]byte{
+		"valid":          {publicKey, messageHash, signature},
+		"corrupted":      {publicKey, messageHash, corrupted},
+		"flipped v":      {publicKey, messageHash, flipped},
+		"wrong message":  {publicKey, crypto.Keccak256([]byte("other")), signature},
+		"invalid pubkey": {make([]byte, 65), messageHash, signature},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := append(append(append([]byte{}, tc[0]...), tc[1]...), tc[2]...)
+			result, err := runDoerner(t, input)
+			want, wantErr := ecverify.VerifyECDSASecp256k1(tc[0], tc[1], tc[2])
+			if wantErr != nil {
+				require.ErrorIs(t, err, ecverify.ErrInvalidPublicKey)
+				require.ErrorIs(t, wantErr, ecverify.ErrInvalidPublicKey)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, want, result[31] == 1)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ecdsa
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// testSignature is a low-s signature with its recovery id
+type testSignature struct {
+	curve byte
+	hash  []byte
+	r, s  *big.Int
+	v     byte
+	x, y  *big.Int
+}
+
+func signSecp256k1(t *testing.T) *testSignature {
+	t.Helper()
+
+	key, err := stdecdsa.GenerateKey(crypto.S256(), rand.Reader)
+	require.NoError(t, err)
+	hash := crypto.Keccak256([]byte("test message"))
+	sig, err := crypto.Sign(hash, key)
+	require.NoError(t, err)
+
+	return &testSignature{
+		curve: CurveSecp256k1,
+		hash:  hash,
+		r:     new(big.Int).SetBytes(sig[:32]),
+		s:     new(big.Int).SetBytes(sig[32:64]),
+		v:     sig[64],
+		x:     key.X,
+		y:     key.Y,
+	}
+}
+
+func signNIST(t *testing.T, id byte, c elliptic.Curve, hash []byte) *testSignature {
+	t.Helper()
+
+	key, err := stdecdsa.GenerateKey(c, rand.Reader)
+	require.NoError(t, err)
+	r, s, err := stdecdsa.Sign(rand.Reader, key, hash)
+	require.NoError(t, err)
+
+	n := c.Params().N
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+	}
+
+	// Find the recovery id by trial, as a signer would
+	spec := curves[id]
+	for v := byte(0); v < 4; v++ {
+		x, y, ok := spec.recover(hash, r, s, v, true)
+		if ok && x.Cmp(key.X) == 0 && y.Cmp(key.Y) == 0 {
+			return &testSignature{curve: id, hash: hash, r: r, s: s, v: v, x: key.X, y: key.Y}
+		}
+	}
+	t.Fatal("no recovery id reproduces the key")
+	return nil
+}
+
+func testSignatures(t *testing.T) map[string]*testSignature {
+	hash256 := sha256.Sum256([]byte("test message"))
+	hash384 := sha512.Sum384([]byte("test message"))
+	return map[string]*testSignature{
+		"secp256k1": signSecp256k1(t),
+		"P-256":     signNIST(t, CurveP256, elliptic.P256(), hash256[:]),
+		"P-384":     signNIST(t, CurveP384, elliptic.P384(), hash384[:]),
+	}
+}
+
+func (ts *testSignature) size() int {
+	return curves[ts.curve].size
+}
+
+func (ts *testSignature) verifyInput(mode byte, s *big.Int) []byte {
+	k := ts.size()
+	input := []byte{OpVerify, ts.curve | mode}
+	input = append(input, ts.hash...)
+	input = append(input, common.LeftPadBytes(ts.r.Bytes(), k)...)
+	input = append(input, common.LeftPadBytes(s.Bytes(), k)...)
+	input = append(input, common.LeftPadBytes(ts.x.Bytes(), k)...)
+	return append(input, common.LeftPadBytes(ts.y.Bytes(), k)...)
+}
+
+func (ts *testSignature) recoverInput(mode byte, s *big.Int, v byte) []byte {
+	k := ts.size()
+	input := []byte{OpRecover, ts.curve | mode}
+	input = append(input, ts.hash...)
+	input = append(input, common.LeftPadBytes(ts.r.Bytes(), k)...)
+	input = append(input, common.LeftPadBytes(s.Bytes(), k)...)
+	return append(input, v)
+}
+
+func (ts *testSignature) publicKey() []byte {
+	k := ts.size()
+	return append(common.LeftPadBytes(ts.x.Bytes(), k), common.LeftPadBytes(ts.y.Bytes(), k)...)
+}
+
+// highS returns the malleated n - s
+func (ts *testSignature) highS() *big.Int {
+	return new(big.Int).Sub(curves[ts.curve].curve.Params().N, ts.s)
+}
+
+func run(t *testing.T, input []byte) ([]byte, error) {
+	t.Helper()
+
+	result, remainingGas, err := ECDSAPrecompile.Run(
+		nil,
+		common.Address{},
+		ContractECDSAAddress,
+		input,
+		1_000_000,
+		true,
+	)
+	require.Equal(t, uint64(1_000_000)-ECDSAPrecompile.RequiredGas(input), remainingGas)
+	return result, err
+}
+
+func TestECDSA_Verify(t *testing.T) {
+	for name, ts := range testSignatures(t) {
+		t.Run(name, func(t *testing.T) {
+			result, err := run(t, ts.verifyInput(ModeStrict, ts.s))
+			require.NoError(t, err)
+			require.Len(t, result, 32)
+			require.Equal(t, byte(1), result[31], "Signature should be valid")
+
+			// Tampered hash
+			wrong := ts.verifyInput(ModeStrict, ts.s)
+			wrong[HeaderSize] ^= 0xFF
+			result, err = run(t, wrong)
+			require.NoError(t, err)
+			require.Equal(t, byte(0), result[31], "Signature should be invalid")
+
+			// Point off the curve
+			offCurve := ts.verifyInput(ModeStrict, ts.s)
+			offCurve[len(offCurve)-1] ^= 0x01
+			result, err = run(t, offCurve)
+			require.NoError(t, err)
+			require.Equal(t, byte(0), result[31])
+		})
+	}
+}
+
+func TestECDSA_Recover(t *testing.T) {
+	for name, ts := range testSignatures(t) {
+		t.Run(name, func(t *testing.T) {
+			result, err := run(t, ts.recoverInput(ModeStrict, ts.s, ts.v))
+			require.NoError(t, err)
+			require.Equal(t, ts.publicKey(), result)
+
+			// Ethereum-style v
+			result, err = run(t, ts.recoverInput(ModeStrict, ts.s, ts.v+27))
+			require.NoError(t, err)
+			require.Equal(t, ts.publicKey(), result)
+
+			// The other parity recovers a different key
+			result, err = run(t, ts.recoverInput(ModeStrict, ts.s, ts.v^1))
+			require.NoError(t, err)
+			require.NotEqual(t, ts.publicKey(), result)
+
+			// Out-of-range v recovers nothing
+			result, err = run(t, ts.recoverInput(ModeStrict, ts.s, 9))
+			require.NoError(t, err)
+			require.Empty(t, result)
+		})
+	}
+}
+
+func TestECDSA_HighS(t *testing.T) {
+	for name, ts := range testSignatures(t) {
+		t.Run(name, func(t *testing.T) {
+			highS := ts.highS()
+
+			// Strict mode rejects the malleated signature on both paths
+			result, err := run(t, ts.verifyInput(ModeStrict, highS))
+			require.NoError(t, err)
+			require.Equal(t, byte(0), result[31])
+			result, err = run(t, ts.recoverInput(ModeStrict, highS, ts.v^1))
+			require.NoError(t, err)
+			require.Empty(t, result)
+
+			// Lenient mode canonicalizes it to the low-s form
+			result, err = run(t, ts.verifyInput(0, highS))
+			require.NoError(t, err)
+			require.Equal(t, byte(1), result[31])
+			result, err = run(t, ts.recoverInput(0, highS, ts.v^1))
+			require.NoError(t, err)
+			require.Equal(t, ts.publicKey(), result)
+		})
+	}
+}
+
+func TestECDSA_InvalidInput(t *testing.T) {
+	_, err := run(t, []byte{OpVerify})
+	require.ErrorIs(t, err, ErrInvalidInputLength)
+
+	_, err = run(t, []byte{OpVerify, 0x07})
+	require.ErrorIs(t, err, ErrInvalidCurve)
+
+	_, err = run(t, []byte{OpVerify, CurveP256 | 0x40})
+	require.ErrorIs(t, err, ErrInvalidCurve)
+
+	_, err = run(t, []byte{0x09, CurveP256})
+	require.ErrorIs(t, err, ErrInvalidOperation)
+
+	// P-384 needs 48-byte fields
+	_, err = run(t, append([]byte{OpVerify, CurveP384}, make([]byte, 5*32)...))
+	require.ErrorIs(t, err, ErrInvalidInputLength)
+
+	_, err = run(t, append([]byte{OpRecover, CurveSecp256k1}, make([]byte, 3*32)...))
+	require.ErrorIs(t, err, ErrInvalidInputLength)
+}
+
+func TestECDSA_GasCost(t *testing.T) {
+	require.Equal(t, Secp256k1Gas, ECDSAPrecompile.RequiredGas([]byte{OpVerify, CurveSecp256k1}))
+	require.Equal(t, P256Gas, ECDSAPrecompile.RequiredGas([]byte{OpVerify, CurveP256 | ModeStrict}))
+	require.Equal(t, P384Gas, ECDSAPrecompile.RequiredGas([]byte{OpRecover, CurveP384}))
+}
+
+func TestECDSA_Address(t *testing.T) {
+	require.Equal(t, ContractECDSAAddress, ECDSAPrecompile.Address())
+}
@@ -4,6 +4,8 @@
 package fhe
 
 import (
+	"maps"
+
 	"github.com/luxfi/precompile/precompileconfig"
 )
 
@@ -16,6 +18,9 @@ type Config struct {
 	NetworkKeyPath string `json:"networkKeyPath,omitempty"`
 	// CoprocessorEndpoint specifies the Z-Chain coprocessor endpoint for threshold decryption
 	CoprocessorEndpoint string `json:"coprocessorEndpoint,omitempty"`
+	// GasCosts reprices operations by name (see DefaultGasTable); operations
+	// not listed keep their default cost
+	GasCosts map[string]uint64 `json:"gasCosts,omitempty"`
 }
 
 // NewConfig returns a config for a network upgrade at [blockTimestamp] that enables FHE.
@@ -40,8 +45,7 @@ func (*Config) Key() string { return ConfigKey }
 
 // Verify tries to verify Config and returns an error accordingly.
 func (c *Config) Verify(chainConfig precompileconfig.ChainConfig) error {
-	// FHE has no special verification requirements beyond base config
-	return nil
+	return ValidateGasCosts(c.GasCosts)
 }
 
 // Equal returns true if [s] is a [*Config] and it has been configured identical to [c].
@@ -52,5 +56,6 @@ func (c *Config) Equal(s precompileconfig.Config) bool {
 	}
 	return c.Upgrade.Equal(&other.Upgrade) &&
 		c.NetworkKeyPath == other.NetworkKeyPath &&
-		c.CoprocessorEndpoint == other.CoprocessorEndpoint
+		c.CoprocessorEndpoint == other.CoprocessorEndpoint &&
+		maps.Equal(c.GasCosts, other.GasCosts)
 }
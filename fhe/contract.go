@@ -28,6 +28,7 @@ const (
 // Gas costs for FHE operations
 const (
 	GasEncrypt        uint64 = 50000
+	GasTrivialEncrypt uint64 = 10000
 	GasDecryptRequest uint64 = 10000
 	GasAdd            uint64 = 65000
 	GasSub            uint64 = 65000
@@ -55,6 +56,7 @@ const (
 	GasRand           uint64 = 100000
 	GasCast           uint64 = 30000
 	GasRequire        uint64 = 80000
+	GasBootstrap      uint64 = 1000000
 )
 
 var (
@@ -187,6 +189,8 @@ func (c *FHEContract) Run(
 		return c.handleVerify(accessibleState, caller, data, suppliedGas, readOnly)
 	case "\x56\x7a\x11\x98": // sealOutput(bytes32,bytes)
 		return c.handleSealOutput(accessibleState, caller, data, suppliedGas, readOnly)
+	case "\x7a\x92\xe7\x9e": // bootstrap(bytes32)
+		return c.handleBootstrap(accessibleState, caller, data, suppliedGas, readOnly)
 
 	default:
 		return nil, suppliedGas, ErrNotImplemented
@@ -195,38 +199,7 @@ func (c *FHEContract) Run(
 
 // Gas returns the gas required for the FHE operation
 func (c *FHEContract) Gas(input []byte) uint64 {
-	if len(input) < 4 {
-		return 0
-	}
-	selector := string(input[:4])
-	switch selector {
-	case "\x23\xb8\x72\xdd": // add
-		return GasAdd
-	case "\x51\xca\xb0\x91": // sub
-		return GasSub
-	case "\xc8\xa4\xac\x9c": // mul
-		return GasMul
-	case "\xa9\x05\x9c\xbb", "\x4b\x64\xe4\x92": // lt, gt
-		return GasLt
-	case "\x1c\xf4\x86\x63": // eq
-		return GasEq
-	case "\x2e\x17\xde\x78": // select
-		return GasSelect
-	case "\xa5\x17\x5c\x89", "\xd4\x3f\x02\x80": // asEuint64, asEaddress
-		return GasEncrypt
-	case "\x6e\x32\x91\x28", "\x7a\x8f\x63\xb8": // max, min
-		return GasMax
-	case "\xcd\x30\x32\x00", "\x5a\x6b\x26\xba": // and, or
-		return GasAnd
-	case "\x6b\x3a\x00\x11": // not
-		return GasNot
-	case "\xe4\x7e\xf3\xfc": // neg
-		return GasNeg
-	case "\x71\x5a\xd3\x11": // rand
-		return GasRand
-	default:
-		return 100000 // Default high gas for unknown operations
-	}
+	return c.RequiredGas(input)
 }
 
 // Handler implementations
@@ -235,7 +208,8 @@ func (c *FHEContract) handleAdd(state contract.AccessibleState, caller common.Ad
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasAdd {
+	cost := OpGas(OpAdd)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -245,14 +219,15 @@ func (c *FHEContract) handleAdd(state contract.AccessibleState, caller common.Ad
 	// Delegate to Z-Chain FHE coprocessor
 	result := performFHEOperation("add", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasAdd, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleSub(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasSub {
+	cost := OpGas(OpSub)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -261,14 +236,15 @@ func (c *FHEContract) handleSub(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEOperation("sub", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasSub, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleMul(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasMul {
+	cost := OpGas(OpMul)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -277,14 +253,15 @@ func (c *FHEContract) handleMul(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEOperation("mul", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasMul, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleLt(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasLt {
+	cost := OpGas(OpLt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -293,14 +270,15 @@ func (c *FHEContract) handleLt(state contract.AccessibleState, caller common.Add
 
 	result := performFHEOperation("lt", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasLt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleGt(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasGt {
+	cost := OpGas(OpGt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -309,14 +287,15 @@ func (c *FHEContract) handleGt(state contract.AccessibleState, caller common.Add
 
 	result := performFHEOperation("gt", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasGt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleEq(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEq {
+	cost := OpGas(OpEq)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -325,14 +304,15 @@ func (c *FHEContract) handleEq(state contract.AccessibleState, caller common.Add
 
 	result := performFHEOperation("eq", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasEq, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleSelect(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 96 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasSelect {
+	cost := OpGas(OpSelect)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -342,14 +322,15 @@ func (c *FHEContract) handleSelect(state contract.AccessibleState, caller common
 
 	result := performFHESelect(condition, ifTrue, ifFalse, caller)
 
-	return result.Bytes(), gas - GasSelect, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEuint64(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -357,14 +338,15 @@ func (c *FHEContract) handleAsEuint64(state contract.AccessibleState, caller com
 
 	result := encryptValue(value.Uint64(), TypeEuint64, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEaddress(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -372,14 +354,15 @@ func (c *FHEContract) handleAsEaddress(state contract.AccessibleState, caller co
 
 	result := encryptAddress(addr, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleMax(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasMax {
+	cost := OpGas(OpMax)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -388,14 +371,15 @@ func (c *FHEContract) handleMax(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEOperation("max", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasMax, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleMin(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasMin {
+	cost := OpGas(OpMin)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -404,14 +388,15 @@ func (c *FHEContract) handleMin(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEOperation("min", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasMin, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAnd(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasAnd {
+	cost := OpGas(OpAnd)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -420,14 +405,15 @@ func (c *FHEContract) handleAnd(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEOperation("and", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasAnd, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleOr(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasOr {
+	cost := OpGas(OpOr)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -436,14 +422,15 @@ func (c *FHEContract) handleOr(state contract.AccessibleState, caller common.Add
 
 	result := performFHEOperation("or", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasOr, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleNot(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasNot {
+	cost := OpGas(OpNot)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -451,14 +438,15 @@ func (c *FHEContract) handleNot(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEUnaryOperation("not", handle, caller)
 
-	return result.Bytes(), gas - GasNot, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleNeg(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasNeg {
+	cost := OpGas(OpNeg)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -466,14 +454,15 @@ func (c *FHEContract) handleNeg(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEUnaryOperation("neg", handle, caller)
 
-	return result.Bytes(), gas - GasNeg, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleRand(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 1 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasRand {
+	cost := OpGas(OpRand)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -481,7 +470,7 @@ func (c *FHEContract) handleRand(state contract.AccessibleState, caller common.A
 
 	result := generateEncryptedRandom(ctType, caller)
 
-	return result.Bytes(), gas - GasRand, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 // === Additional Arithmetic Handlers ===
@@ -490,7 +479,8 @@ func (c *FHEContract) handleDiv(state contract.AccessibleState, caller common.Ad
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasDiv {
+	cost := OpGas(OpDiv)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -499,14 +489,15 @@ func (c *FHEContract) handleDiv(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEOperation("div", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasDiv, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleRem(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasRem {
+	cost := OpGas(OpRem)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -515,7 +506,7 @@ func (c *FHEContract) handleRem(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEOperation("rem", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasRem, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 // === Scalar Arithmetic Handlers ===
@@ -524,7 +515,8 @@ func (c *FHEContract) handleScalarAdd(state contract.AccessibleState, caller com
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasAdd {
+	cost := OpGas(OpScalarAdd)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -533,14 +525,15 @@ func (c *FHEContract) handleScalarAdd(state contract.AccessibleState, caller com
 
 	result := performFHEScalarOperation("scalarAdd", handle, scalar, caller)
 
-	return result.Bytes(), gas - GasAdd, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleScalarSub(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasSub {
+	cost := OpGas(OpScalarSub)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -549,14 +542,15 @@ func (c *FHEContract) handleScalarSub(state contract.AccessibleState, caller com
 
 	result := performFHEScalarOperation("scalarSub", handle, scalar, caller)
 
-	return result.Bytes(), gas - GasSub, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleScalarMul(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasMul {
+	cost := OpGas(OpScalarMul)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -565,14 +559,15 @@ func (c *FHEContract) handleScalarMul(state contract.AccessibleState, caller com
 
 	result := performFHEScalarOperation("scalarMul", handle, scalar, caller)
 
-	return result.Bytes(), gas - GasMul, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleScalarDiv(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasDiv {
+	cost := OpGas(OpScalarDiv)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -581,14 +576,15 @@ func (c *FHEContract) handleScalarDiv(state contract.AccessibleState, caller com
 
 	result := performFHEScalarOperation("scalarDiv", handle, scalar, caller)
 
-	return result.Bytes(), gas - GasDiv, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleScalarRem(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasRem {
+	cost := OpGas(OpScalarRem)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -597,7 +593,7 @@ func (c *FHEContract) handleScalarRem(state contract.AccessibleState, caller com
 
 	result := performFHEScalarOperation("scalarRem", handle, scalar, caller)
 
-	return result.Bytes(), gas - GasRem, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 // === Additional Comparison Handlers ===
@@ -606,7 +602,8 @@ func (c *FHEContract) handleLe(state contract.AccessibleState, caller common.Add
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasLe {
+	cost := OpGas(OpLe)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -615,14 +612,15 @@ func (c *FHEContract) handleLe(state contract.AccessibleState, caller common.Add
 
 	result := performFHEOperation("le", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasLe, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleGe(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasGe {
+	cost := OpGas(OpGe)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -631,14 +629,15 @@ func (c *FHEContract) handleGe(state contract.AccessibleState, caller common.Add
 
 	result := performFHEOperation("ge", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasGe, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleNe(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasNe {
+	cost := OpGas(OpNe)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -647,7 +646,7 @@ func (c *FHEContract) handleNe(state contract.AccessibleState, caller common.Add
 
 	result := performFHEOperation("ne", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasNe, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 // === Additional Bitwise Handlers ===
@@ -656,7 +655,8 @@ func (c *FHEContract) handleXor(state contract.AccessibleState, caller common.Ad
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasXor {
+	cost := OpGas(OpXor)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -665,7 +665,7 @@ func (c *FHEContract) handleXor(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEOperation("xor", handle1, handle2, caller)
 
-	return result.Bytes(), gas - GasXor, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 // === Shift Operation Handlers ===
@@ -674,7 +674,8 @@ func (c *FHEContract) handleShl(state contract.AccessibleState, caller common.Ad
 	if len(data) < 33 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasShl {
+	cost := OpGas(OpShl)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -683,14 +684,15 @@ func (c *FHEContract) handleShl(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEShiftOperation("shl", handle, shift, caller)
 
-	return result.Bytes(), gas - GasShl, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleShr(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 33 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasShr {
+	cost := OpGas(OpShr)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -699,14 +701,15 @@ func (c *FHEContract) handleShr(state contract.AccessibleState, caller common.Ad
 
 	result := performFHEShiftOperation("shr", handle, shift, caller)
 
-	return result.Bytes(), gas - GasShr, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleRotl(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 33 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasRotl {
+	cost := OpGas(OpRotl)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -715,14 +718,15 @@ func (c *FHEContract) handleRotl(state contract.AccessibleState, caller common.A
 
 	result := performFHEShiftOperation("rotl", handle, shift, caller)
 
-	return result.Bytes(), gas - GasRotl, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleRotr(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 33 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasRotr {
+	cost := OpGas(OpRotr)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -731,7 +735,7 @@ func (c *FHEContract) handleRotr(state contract.AccessibleState, caller common.A
 
 	result := performFHEShiftOperation("rotr", handle, shift, caller)
 
-	return result.Bytes(), gas - GasRotr, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 // === Type Conversion and Encryption Handlers ===
@@ -740,7 +744,8 @@ func (c *FHEContract) handleCast(state contract.AccessibleState, caller common.A
 	if len(data) < 33 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasCast {
+	cost := OpGas(OpCast)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -749,14 +754,15 @@ func (c *FHEContract) handleCast(state contract.AccessibleState, caller common.A
 
 	result := performFHECast(handle, toType, caller)
 
-	return result.Bytes(), gas - GasCast, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEbool(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -768,14 +774,15 @@ func (c *FHEContract) handleAsEbool(state contract.AccessibleState, caller commo
 
 	result := encryptValue(boolVal, TypeEbool, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEuint4(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -783,14 +790,15 @@ func (c *FHEContract) handleAsEuint4(state contract.AccessibleState, caller comm
 
 	result := encryptValue(value.Uint64()&0xF, TypeEuint4, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEuint8(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -798,14 +806,15 @@ func (c *FHEContract) handleAsEuint8(state contract.AccessibleState, caller comm
 
 	result := encryptValue(value.Uint64()&0xFF, TypeEuint8, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEuint16(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -813,14 +822,15 @@ func (c *FHEContract) handleAsEuint16(state contract.AccessibleState, caller com
 
 	result := encryptValue(value.Uint64()&0xFFFF, TypeEuint16, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEuint32(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -828,14 +838,15 @@ func (c *FHEContract) handleAsEuint32(state contract.AccessibleState, caller com
 
 	result := encryptValue(value.Uint64()&0xFFFFFFFF, TypeEuint32, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEuint128(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -843,14 +854,15 @@ func (c *FHEContract) handleAsEuint128(state contract.AccessibleState, caller co
 
 	result := encryptBigIntValue(value, TypeEuint128, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleAsEuint256(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpTrivialEncrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -858,7 +870,7 @@ func (c *FHEContract) handleAsEuint256(state contract.AccessibleState, caller co
 
 	result := encryptBigIntValue(value, TypeEuint256, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 // === Utility Handlers ===
@@ -867,7 +879,8 @@ func (c *FHEContract) handleDecrypt(state contract.AccessibleState, caller commo
 	if len(data) < 32 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasDecryptRequest {
+	cost := OpGas(OpDecrypt)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -875,14 +888,15 @@ func (c *FHEContract) handleDecrypt(state contract.AccessibleState, caller commo
 
 	result := performFHEDecrypt(handle, caller)
 
-	return result.Bytes(), gas - GasDecryptRequest, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleVerify(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 33 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpVerify)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -891,14 +905,15 @@ func (c *FHEContract) handleVerify(state contract.AccessibleState, caller common
 
 	result := performFHEVerify(inputHandle, ctType, caller)
 
-	return result.Bytes(), gas - GasEncrypt, nil
+	return result.Bytes(), gas - cost, nil
 }
 
 func (c *FHEContract) handleSealOutput(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
 	if len(data) < 64 {
 		return nil, gas, ErrInvalidInput
 	}
-	if gas < GasEncrypt {
+	cost := OpGas(OpSealOutput)
+	if gas < cost {
 		return nil, gas, ErrInsufficientGas
 	}
 
@@ -907,7 +922,23 @@ func (c *FHEContract) handleSealOutput(state contract.AccessibleState, caller co
 
 	result := performFHESealOutput(handle, publicKey, caller)
 
-	return result, gas - GasEncrypt, nil
+	return result, gas - cost, nil
+}
+
+func (c *FHEContract) handleBootstrap(state contract.AccessibleState, caller common.Address, data []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
+	if len(data) < 32 {
+		return nil, gas, ErrInvalidInput
+	}
+	cost := OpGas(OpBootstrap)
+	if gas < cost {
+		return nil, gas, ErrInsufficientGas
+	}
+
+	handle := common.BytesToHash(data[:32])
+
+	result := performFHEBootstrap(handle, caller)
+
+	return result.Bytes(), gas - cost, nil
 }
 
 // ciphertextStore holds encrypted values indexed by hash
@@ -1036,6 +1067,21 @@ func performFHEUnaryOperation(op string, handle common.Hash, caller common.Addre
 	return storeCiphertext(result, ctType)
 }
 
+// performFHEBootstrap refreshes a ciphertext's noise using real TFHE library
+func performFHEBootstrap(handle common.Hash, caller common.Address) common.Hash {
+	ct, ctType, ok := getCiphertext(handle)
+	if !ok {
+		return common.Hash{}
+	}
+
+	result := tfheBootstrap(ct, ctType)
+	if result == nil {
+		return common.Hash{}
+	}
+
+	return storeCiphertext(result, ctType)
+}
+
 // encryptValue encrypts a plaintext value using real TFHE library
 func encryptValue(value uint64, ctType uint8, caller common.Address) common.Hash {
 	ct := tfheTrivialEncrypt(new(big.Int).SetUint64(value), ctType)
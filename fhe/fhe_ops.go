@@ -425,6 +425,27 @@ func tfheXor(lhs, rhs []byte, fheType uint8) []byte {
 	return serializeBitCiphertext(result)
 }
 
+// tfheBootstrap refreshes every bit of a ciphertext. Each bit passes through
+// one gate-bootstrapped XOR with an encrypted zero, which resets its noise
+// without changing its value.
+func tfheBootstrap(ct []byte, fheType uint8) []byte {
+	if err := initTFHE(); err != nil {
+		return nil
+	}
+
+	ctIn := deserializeBitCiphertext(ct)
+	if ctIn == nil {
+		return nil
+	}
+
+	result, err := evaluator.Xor(ctIn, evaluator.Zero(ctIn.Type()))
+	if err != nil {
+		return nil
+	}
+
+	return serializeBitCiphertext(result)
+}
+
 func tfheNot(ct []byte, fheType uint8) []byte {
 	if err := initTFHE(); err != nil {
 		return nil
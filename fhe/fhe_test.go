@@ -336,6 +336,22 @@ func TestFHENot(t *testing.T) {
 	}
 }
 
+// TestFHEBootstrap tests that bootstrapping preserves the plaintext
+func TestFHEBootstrap(t *testing.T) {
+	err := initTFHE()
+	require.NoError(t, err)
+
+	ct := tfheTrivialEncrypt(big.NewInt(0xA5), TypeEuint8)
+	require.NotNil(t, ct)
+
+	result := tfheBootstrap(ct, TypeEuint8)
+	require.NotNil(t, result)
+	require.NotEqual(t, ct, result, "bootstrap should produce a fresh ciphertext")
+
+	decrypted := tfheDecrypt(result, TypeEuint8)
+	require.Equal(t, uint64(0xA5), decrypted.Uint64())
+}
+
 // TestFHEShift tests shift operations
 func TestFHEShift(t *testing.T) {
 	err := initTFHE()
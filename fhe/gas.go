@@ -0,0 +1,218 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fhe
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// Operation names, used as gas table keys
+const (
+	OpAdd            = "add"
+	OpSub            = "sub"
+	OpMul            = "mul"
+	OpDiv            = "div"
+	OpRem            = "rem"
+	OpNeg            = "neg"
+	OpScalarAdd      = "scalarAdd"
+	OpScalarSub      = "scalarSub"
+	OpScalarMul      = "scalarMul"
+	OpScalarDiv      = "scalarDiv"
+	OpScalarRem      = "scalarRem"
+	OpLt             = "lt"
+	OpLe             = "le"
+	OpGt             = "gt"
+	OpGe             = "ge"
+	OpEq             = "eq"
+	OpNe             = "ne"
+	OpMin            = "min"
+	OpMax            = "max"
+	OpAnd            = "and"
+	OpOr             = "or"
+	OpXor            = "xor"
+	OpNot            = "not"
+	OpShl            = "shl"
+	OpShr            = "shr"
+	OpRotl           = "rotl"
+	OpRotr           = "rotr"
+	OpSelect         = "select"
+	OpCast           = "cast"
+	OpTrivialEncrypt = "trivialEncrypt"
+	OpRand           = "rand"
+	OpDecrypt        = "decrypt"
+	OpVerify         = "verify"
+	OpSealOutput     = "sealOutput"
+	OpBootstrap      = "bootstrap"
+)
+
+// GasUnknownOp is charged for selectors the contract does not implement
+const GasUnknownOp uint64 = 100000
+
+var (
+	ErrUnknownGasOp   = errors.New("unknown FHE gas table operation")
+	ErrInvalidGasCost = errors.New("FHE gas cost must be non-zero")
+)
+
+// selectorOps maps each function selector handled by Run to its operation
+var selectorOps = map[string]string{
+	"\x23\xb8\x72\xdd": OpAdd,
+	"\x51\xca\xb0\x91": OpSub,
+	"\xc8\xa4\xac\x9c": OpMul,
+	"\x0f\x5e\x1b\x2a": OpDiv,
+	"\x1e\x19\x1a\x96": OpRem,
+	"\xe4\x7e\xf3\xfc": OpNeg,
+	"\xf5\xa7\x96\xfb": OpScalarAdd,
+	"\xb6\x3a\x9e\x11": OpScalarSub,
+	"\x3c\x96\x47\x95": OpScalarMul,
+	"\x7b\x8f\x4a\x2d": OpScalarDiv,
+	"\x52\x91\xa3\x21": OpScalarRem,
+	"\xa9\x05\x9c\xbb": OpLt,
+	"\x26\xa3\x31\x9e": OpLe,
+	"\x4b\x64\xe4\x92": OpGt,
+	"\x53\x1c\x19\xea": OpGe,
+	"\x1c\xf4\x86\x63": OpEq,
+	"\x14\x6e\x3a\x7e": OpNe,
+	"\x7a\x8f\x63\xb8": OpMin,
+	"\x6e\x32\x91\x28": OpMax,
+	"\xcd\x30\x32\x00": OpAnd,
+	"\x5a\x6b\x26\xba": OpOr,
+	"\xf6\x74\x70\x22": OpXor,
+	"\x6b\x3a\x00\x11": OpNot,
+	"\x3e\x8c\x6c\x10": OpShl,
+	"\x5f\x46\xe5\x15": OpShr,
+	"\x89\xa1\x9e\x6b": OpRotl,
+	"\xd7\x25\x1c\xb9": OpRotr,
+	"\x2e\x17\xde\x78": OpSelect,
+	"\xae\xd2\x44\x6b": OpCast,
+	"\xa5\x17\x5c\x89": OpTrivialEncrypt, // asEuint64
+	"\xd4\x3f\x02\x80": OpTrivialEncrypt, // asEaddress
+	"\x8c\x3f\x5a\x42": OpTrivialEncrypt, // asEbool
+	"\x2d\xfa\x48\x63": OpTrivialEncrypt, // asEuint4
+	"\x64\xc1\x51\x81": OpTrivialEncrypt, // asEuint8
+	"\xf8\x91\x08\x50": OpTrivialEncrypt, // asEuint16
+	"\x6c\xa9\xea\xe9": OpTrivialEncrypt, // asEuint32
+	"\x7d\x6d\x81\x95": OpTrivialEncrypt, // asEuint128
+	"\x9e\x5b\x2e\xf3": OpTrivialEncrypt, // asEuint256
+	"\x71\x5a\xd3\x11": OpRand,
+	"\x12\x3d\x4c\x87": OpDecrypt,
+	"\x45\xa9\x32\x18": OpVerify,
+	"\x56\x7a\x11\x98": OpSealOutput,
+	"\x7a\x92\xe7\x9e": OpBootstrap,
+}
+
+// DefaultGasTable returns the default cost of every operation. Costs track
+// the number of bootstrapped gates an operation evaluates: a trivial
+// encryption needs none, a comparison or addition a linear chain, a
+// multiplication a quadratic array, and division a full long-division
+// circuit. A bootstrap refreshes every bit of a 256-bit value and is the
+// most expensive operation.
+func DefaultGasTable() map[string]uint64 {
+	return map[string]uint64{
+		OpAdd:            GasAdd,
+		OpSub:            GasSub,
+		OpMul:            GasMul,
+		OpDiv:            GasDiv,
+		OpRem:            GasRem,
+		OpNeg:            GasNeg,
+		OpScalarAdd:      GasAdd,
+		OpScalarSub:      GasSub,
+		OpScalarMul:      GasMul,
+		OpScalarDiv:      GasDiv,
+		OpScalarRem:      GasRem,
+		OpLt:             GasLt,
+		OpLe:             GasLe,
+		OpGt:             GasGt,
+		OpGe:             GasGe,
+		OpEq:             GasEq,
+		OpNe:             GasNe,
+		OpMin:            GasMin,
+		OpMax:            GasMax,
+		OpAnd:            GasAnd,
+		OpOr:             GasOr,
+		OpXor:            GasXor,
+		OpNot:            GasNot,
+		OpShl:            GasShl,
+		OpShr:            GasShr,
+		OpRotl:           GasRotl,
+		OpRotr:           GasRotr,
+		OpSelect:         GasSelect,
+		OpCast:           GasCast,
+		OpTrivialEncrypt: GasTrivialEncrypt,
+		OpRand:           GasRand,
+		OpDecrypt:        GasDecryptRequest,
+		OpVerify:         GasEncrypt,
+		OpSealOutput:     GasEncrypt,
+		OpBootstrap:      GasBootstrap,
+	}
+}
+
+var (
+	gasTableLock sync.RWMutex
+	gasTable     = DefaultGasTable()
+)
+
+// GasTable returns a copy of the active gas table
+func GasTable() map[string]uint64 {
+	gasTableLock.RLock()
+	defer gasTableLock.RUnlock()
+	return maps.Clone(gasTable)
+}
+
+// OpGas returns the active cost of an operation, or GasUnknownOp if the
+// operation is not in the table
+func OpGas(op string) uint64 {
+	gasTableLock.RLock()
+	defer gasTableLock.RUnlock()
+	if cost, ok := gasTable[op]; ok {
+		return cost
+	}
+	return GasUnknownOp
+}
+
+// ValidateGasCosts checks that every override names a known operation and
+// is non-zero
+func ValidateGasCosts(overrides map[string]uint64) error {
+	defaults := DefaultGasTable()
+	for op, cost := range overrides {
+		if _, ok := defaults[op]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownGasOp, op)
+		}
+		if cost == 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidGasCost, op)
+		}
+	}
+	return nil
+}
+
+// SetGasTable reprices operations: the active table becomes the defaults
+// with overrides applied. A nil map restores the defaults. On error the
+// active table is unchanged.
+func SetGasTable(overrides map[string]uint64) error {
+	if err := ValidateGasCosts(overrides); err != nil {
+		return err
+	}
+	table := DefaultGasTable()
+	maps.Copy(table, overrides)
+
+	gasTableLock.Lock()
+	defer gasTableLock.Unlock()
+	gasTable = table
+	return nil
+}
+
+// RequiredGas returns the gas the operation selected by input costs under
+// the active gas table
+func (c *FHEContract) RequiredGas(input []byte) uint64 {
+	if len(input) < 4 {
+		return 0
+	}
+	op, ok := selectorOps[string(input[:4])]
+	if !ok {
+		return GasUnknownOp
+	}
+	return OpGas(op)
+}
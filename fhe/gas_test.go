@@ -0,0 +1,107 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fhe
+
+import (
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// selectorFor returns a selector that Run routes to op
+func selectorFor(t *testing.T, op string) []byte {
+	t.Helper()
+	for selector, o := range selectorOps {
+		if o == op {
+			return []byte(selector)
+		}
+	}
+	t.Fatalf("no selector for %q", op)
+	return nil
+}
+
+func TestRequiredGasReflectsComplexity(t *testing.T) {
+	require := require.New(t)
+	c := &FHEContract{}
+
+	add := c.RequiredGas(selectorFor(t, OpAdd))
+	mul := c.RequiredGas(selectorFor(t, OpMul))
+	trivial := c.RequiredGas(selectorFor(t, OpTrivialEncrypt))
+	bootstrap := c.RequiredGas(selectorFor(t, OpBootstrap))
+
+	require.Greater(mul, add, "mul must cost more than add")
+	require.Less(trivial, add, "trivial encryption must be cheaper than add")
+	for op, cost := range DefaultGasTable() {
+		if op != OpBootstrap {
+			require.Greater(bootstrap, cost, "bootstrap must cost more than %s", op)
+		}
+	}
+
+	// Gas is an alias for RequiredGas
+	require.Equal(mul, c.Gas(selectorFor(t, OpMul)))
+	require.Equal(GasUnknownOp, c.RequiredGas([]byte{0xde, 0xad, 0xbe, 0xef}))
+	require.Zero(c.RequiredGas([]byte{0x01}))
+}
+
+func TestGasTableCoversSelectors(t *testing.T) {
+	table := DefaultGasTable()
+	for selector, op := range selectorOps {
+		require.Contains(t, table, op, "selector %x", selector)
+	}
+}
+
+func TestSetGasTable(t *testing.T) {
+	require := require.New(t)
+	c := &FHEContract{}
+	t.Cleanup(func() { require.NoError(SetGasTable(nil)) })
+
+	require.NoError(SetGasTable(map[string]uint64{OpMul: 400000}))
+	require.Equal(uint64(400000), OpGas(OpMul))
+	require.Equal(uint64(400000), c.RequiredGas(selectorFor(t, OpMul)))
+	require.Equal(GasAdd, OpGas(OpAdd), "unlisted ops keep their default")
+
+	// GasTable returns a copy
+	table := GasTable()
+	table[OpMul] = 1
+	require.Equal(uint64(400000), OpGas(OpMul))
+
+	// Invalid overrides leave the active table unchanged
+	require.ErrorIs(SetGasTable(map[string]uint64{"fft": 1}), ErrUnknownGasOp)
+	require.ErrorIs(SetGasTable(map[string]uint64{OpAdd: 0}), ErrInvalidGasCost)
+	require.Equal(uint64(400000), OpGas(OpMul))
+
+	require.NoError(SetGasTable(nil))
+	require.Equal(DefaultGasTable(), GasTable())
+}
+
+func TestRunChargesRepricedGas(t *testing.T) {
+	require := require.New(t)
+	c := &FHEContract{}
+	t.Cleanup(func() { require.NoError(SetGasTable(nil)) })
+
+	input := append(selectorFor(t, OpBootstrap), make([]byte, 32)...)
+	_, remaining, err := c.Run(nil, common.Address{}, ContractAddress, input, GasBootstrap-1, false)
+	require.ErrorIs(err, ErrInsufficientGas)
+	require.Equal(GasBootstrap-1, remaining)
+
+	require.NoError(SetGasTable(map[string]uint64{OpBootstrap: 2 * GasBootstrap}))
+	_, _, err = c.Run(nil, common.Address{}, ContractAddress, input, GasBootstrap, false)
+	require.ErrorIs(err, ErrInsufficientGas)
+}
+
+func TestConfigGasCosts(t *testing.T) {
+	require := require.New(t)
+
+	cfg := NewConfig(nil)
+	require.NoError(cfg.Verify(nil))
+
+	cfg.GasCosts = map[string]uint64{OpBootstrap: 2 * GasBootstrap}
+	require.NoError(cfg.Verify(nil))
+	require.False(cfg.Equal(NewConfig(nil)))
+	require.True(cfg.Equal(&Config{GasCosts: map[string]uint64{OpBootstrap: 2 * GasBootstrap}}))
+
+	cfg.GasCosts = map[string]uint64{"fft": 1}
+	require.ErrorIs(cfg.Verify(nil), ErrUnknownGasOp)
+}
@@ -0,0 +1,380 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fhe
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/luxfi/geth/common"
+)
+
+// Decryption committee gateway (Lux Privacy range 0x0700)
+//
+// The committee holds Shamir shares s_i of the network decryption key. A
+// decryption request carries the ciphertext mask C = r*G; each validator
+// answers with the partial D_i = s_i*C and a Chaum-Pedersen proof that
+// log_G(S_i) == log_C(D_i), where S_i is its registered public key share.
+// Once Threshold valid partials arrive the gateway interpolates them in the
+// exponent to recover s*C, which unmasks the plaintext, and the requester
+// collects it once with FulfillDecryption.
+//
+// Only the committee admin enrolls members, and it does so with the share
+// index each member was dealt in the DKG, so neither the set of shares nor
+// their evaluation points depend on who registers first.
+
+// gatewayCurve is the group used for key shares and partials
+var gatewayCurve = elliptic.P256()
+
+const (
+	GatewayPartialProofSize     = 64 // c || z
+	gatewayPartialProofScalarSz = 32
+)
+
+var (
+	ErrNotCommitteeAdmin   = errors.New("caller is not the committee admin")
+	ErrValidatorExists     = errors.New("validator already registered")
+	ErrShareIndexTaken     = errors.New("share index already registered")
+	ErrInvalidShareIndex   = errors.New("invalid share index")
+	ErrValidatorNotFound   = errors.New("validator not registered")
+	ErrInvalidKeyShare     = errors.New("invalid public key share")
+	ErrInvalidThreshold    = errors.New("invalid committee threshold")
+	ErrRequestNotFound     = errors.New("decryption request not found")
+	ErrRequestFinalized    = errors.New("decryption request already finalized")
+	ErrRequestNotFinalized = errors.New("decryption request not yet finalized")
+	ErrRequestFulfilled    = errors.New("decryption request already fulfilled")
+	ErrRequestExists       = errors.New("decryption request ID already in use")
+	ErrNotRequester        = errors.New("caller is not the decryption requester")
+	ErrDuplicatePartial    = errors.New("partial decryption already submitted")
+	ErrInvalidPartial      = errors.New("invalid partial decryption")
+	ErrInvalidPartialProof = errors.New("partial decryption proof verification failed")
+)
+
+// GatewayValidator is a committee member and its public key share
+type GatewayValidator struct {
+	Address common.Address
+	Index   uint32 // Shamir evaluation point from the DKG, starting at 1
+	Share   []byte // Uncompressed P-256 point s_i*G
+}
+
+// DecryptionRequest tracks partials for one ciphertext mask
+type DecryptionRequest struct {
+	ID          common.Hash
+	Requester   common.Address
+	Mask        []byte // Uncompressed P-256 point C
+	BlockNumber uint64
+	Partials    map[common.Address][]byte // validator -> D_i
+	Result      []byte                    // s*C once reconstructed
+	Finalized   bool
+	Fulfilled   bool // Result delivered to the requester
+}
+
+// CommitteeGateway manages the threshold decryption committee
+type CommitteeGateway struct {
+	Admin      common.Address // Committee authority that enrolls members
+	Threshold  uint32
+	Validators map[common.Address]*GatewayValidator
+	Requests   map[common.Hash]*DecryptionRequest
+
+	indices map[uint32]common.Address // share index -> member
+	nonce   uint64
+	mu      sync.RWMutex
+}
+
+// NewCommitteeGateway creates a gateway administered by admin and requiring
+// threshold partials per request
+func NewCommitteeGateway(admin common.Address, threshold uint32) (*CommitteeGateway, error) {
+	if threshold == 0 {
+		return nil, ErrInvalidThreshold
+	}
+	return &CommitteeGateway{
+		Admin:      admin,
+		Threshold:  threshold,
+		Validators: make(map[common.Address]*GatewayValidator),
+		Requests:   make(map[common.Hash]*DecryptionRequest),
+		indices:    make(map[uint32]common.Address),
+	}, nil
+}
+
+// RegisterValidator adds a committee member with the public key share it
+// was dealt at the DKG share index. Only the admin may call it.
+func (g *CommitteeGateway) RegisterValidator(caller, validator common.Address, index uint32, pubkey []byte) error {
+	if index == 0 {
+		return ErrInvalidShareIndex
+	}
+	if _, _, err := unmarshalGatewayPoint(pubkey); err != nil {
+		return ErrInvalidKeyShare
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if caller != g.Admin {
+		return ErrNotCommitteeAdmin
+	}
+	if _, exists := g.Validators[validator]; exists {
+		return ErrValidatorExists
+	}
+	if _, taken := g.indices[index]; taken {
+		return ErrShareIndexTaken
+	}
+
+	g.Validators[validator] = &GatewayValidator{
+		Address: validator,
+		Index:   index,
+		Share:   common.CopyBytes(pubkey),
+	}
+	g.indices[index] = validator
+	return nil
+}
+
+// DecryptionRequestID derives a request's ID from its requester, mask, the
+// gateway's request nonce and the block it was opened in. The nonce is the
+// gateway's own counter, so a caller can neither choose an ID nor reuse one.
+func DecryptionRequestID(requester common.Address, mask []byte, nonce, blockNumber uint64) common.Hash {
+	var n, b [8]byte
+	binary.BigEndian.PutUint64(n[:], nonce)
+	binary.BigEndian.PutUint64(b[:], blockNumber)
+	return common.BytesToHash(sha256Sum([]byte("LUX_FHE_GATEWAY_REQUEST"), requester.Bytes(), mask, n[:], b[:]))
+}
+
+// RequestDecryption opens a request for the ciphertext mask C at blockNumber
+func (g *CommitteeGateway) RequestDecryption(requester common.Address, mask []byte, blockNumber uint64) (common.Hash, error) {
+	if _, _, err := unmarshalGatewayPoint(mask); err != nil {
+		return common.Hash{}, ErrInvalidCiphertext
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.nonce++
+	id := DecryptionRequestID(requester, mask, g.nonce, blockNumber)
+	if _, exists := g.Requests[id]; exists {
+		return common.Hash{}, ErrRequestExists
+	}
+
+	g.Requests[id] = &DecryptionRequest{
+		ID:          id,
+		Requester:   requester,
+		Mask:        common.CopyBytes(mask),
+		BlockNumber: blockNumber,
+		Partials:    make(map[common.Address][]byte),
+	}
+	return id, nil
+}
+
+// SubmitPartialDecryption records a validator's partial after checking its
+// proof against the validator's key share. It returns true once the request
+// has been reconstructed.
+func (g *CommitteeGateway) SubmitPartialDecryption(validator common.Address, requestID common.Hash, partial, proof []byte) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	member, ok := g.Validators[validator]
+	if !ok {
+		return false, ErrValidatorNotFound
+	}
+	req, ok := g.Requests[requestID]
+	if !ok {
+		return false, ErrRequestNotFound
+	}
+	if req.Finalized {
+		return false, ErrRequestFinalized
+	}
+	if _, exists := req.Partials[validator]; exists {
+		return false, ErrDuplicatePartial
+	}
+
+	if err := verifyPartialDecryption(member.Share, req.Mask, partial, proof); err != nil {
+		return false, err
+	}
+	req.Partials[validator] = common.CopyBytes(partial)
+
+	if uint32(len(req.Partials)) < g.Threshold {
+		return false, nil
+	}
+
+	result, err := g.reconstruct(req)
+	if err != nil {
+		return false, err
+	}
+	req.Result = result
+	req.Finalized = true
+	return true, nil
+}
+
+// GetDecryption returns the reconstructed s*C for a finalized request
+func (g *CommitteeGateway) GetDecryption(requestID common.Hash) ([]byte, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	req, ok := g.Requests[requestID]
+	if !ok || !req.Finalized {
+		return nil, false
+	}
+	return common.CopyBytes(req.Result), true
+}
+
+// FulfillDecryption delivers the reconstructed s*C of a finalized request to
+// its requester. Each request is fulfilled once; the ID must name an open
+// request of this requester.
+func (g *CommitteeGateway) FulfillDecryption(requester common.Address, requestID common.Hash) ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	req, ok := g.Requests[requestID]
+	if !ok {
+		return nil, ErrRequestNotFound
+	}
+	if req.Requester != requester {
+		return nil, ErrNotRequester
+	}
+	if req.Fulfilled {
+		return nil, ErrRequestFulfilled
+	}
+	if !req.Finalized {
+		return nil, ErrRequestNotFinalized
+	}
+	req.Fulfilled = true
+	return common.CopyBytes(req.Result), nil
+}
+
+// reconstruct interpolates the partials at zero: s*C = sum(lambda_i * D_i)
+func (g *CommitteeGateway) reconstruct(req *DecryptionRequest) ([]byte, error) {
+	n := gatewayCurve.Params().N
+
+	indices := make([]*big.Int, 0, len(req.Partials))
+	points := make([][]byte, 0, len(req.Partials))
+	for validator, partial := range req.Partials {
+		indices = append(indices, big.NewInt(int64(g.Validators[validator].Index)))
+		points = append(points, partial)
+	}
+
+	var accX, accY *big.Int
+	for i, xi := range indices {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, xj := range indices {
+			if i == j {
+				continue
+			}
+			num.Mod(num.Mul(num, xj), n)
+			den.Mod(den.Mul(den, new(big.Int).Sub(xj, xi)), n)
+		}
+		lambda := new(big.Int).Mul(num, new(big.Int).ModInverse(den, n))
+		lambda.Mod(lambda, n)
+
+		px, py, err := unmarshalGatewayPoint(points[i])
+		if err != nil {
+			return nil, ErrInvalidPartial
+		}
+		tx, ty := gatewayCurve.ScalarMult(px, py, lambda.Bytes())
+		if accX == nil {
+			accX, accY = tx, ty
+		} else {
+			accX, accY = gatewayCurve.Add(accX, accY, tx, ty)
+		}
+	}
+	return elliptic.Marshal(gatewayCurve, accX, accY), nil
+}
+
+// GeneratePartialDecryption computes D = share*C and its proof for a
+// committee member holding the secret share
+func GeneratePartialDecryption(share *big.Int, mask []byte, nonce *big.Int) (partial, proof []byte, err error) {
+	cx, cy, err := unmarshalGatewayPoint(mask)
+	if err != nil {
+		return nil, nil, ErrInvalidCiphertext
+	}
+	n := gatewayCurve.Params().N
+
+	sx, sy := gatewayCurve.ScalarBaseMult(share.Bytes())
+	dx, dy := gatewayCurve.ScalarMult(cx, cy, share.Bytes())
+	a1x, a1y := gatewayCurve.ScalarBaseMult(nonce.Bytes())
+	a2x, a2y := gatewayCurve.ScalarMult(cx, cy, nonce.Bytes())
+
+	shareBytes := elliptic.Marshal(gatewayCurve, sx, sy)
+	partial = elliptic.Marshal(gatewayCurve, dx, dy)
+	c := partialChallenge(shareBytes, mask, partial,
+		elliptic.Marshal(gatewayCurve, a1x, a1y),
+		elliptic.Marshal(gatewayCurve, a2x, a2y))
+
+	// z = k + c*s mod n
+	z := new(big.Int).Mul(c, share)
+	z.Add(z, nonce).Mod(z, n)
+
+	proof = make([]byte, GatewayPartialProofSize)
+	c.FillBytes(proof[:gatewayPartialProofScalarSz])
+	z.FillBytes(proof[gatewayPartialProofScalarSz:])
+	return partial, proof, nil
+}
+
+// verifyPartialDecryption checks the Chaum-Pedersen proof (c, z):
+// A1 = z*G - c*S_i, A2 = z*C - c*D_i, c == H(S_i, C, D_i, A1, A2)
+func verifyPartialDecryption(share, mask, partial, proof []byte) error {
+	if len(proof) != GatewayPartialProofSize {
+		return ErrInvalidPartialProof
+	}
+	sx, sy, err := unmarshalGatewayPoint(share)
+	if err != nil {
+		return ErrInvalidKeyShare
+	}
+	cx, cy, err := unmarshalGatewayPoint(mask)
+	if err != nil {
+		return ErrInvalidCiphertext
+	}
+	dx, dy, err := unmarshalGatewayPoint(partial)
+	if err != nil {
+		return ErrInvalidPartial
+	}
+
+	n := gatewayCurve.Params().N
+	c := new(big.Int).SetBytes(proof[:gatewayPartialProofScalarSz])
+	z := new(big.Int).SetBytes(proof[gatewayPartialProofScalarSz:])
+	if c.Sign() == 0 || c.Cmp(n) >= 0 || z.Cmp(n) >= 0 {
+		return ErrInvalidPartialProof
+	}
+	negC := new(big.Int).Sub(n, c).Bytes()
+
+	zgx, zgy := gatewayCurve.ScalarBaseMult(z.Bytes())
+	csx, csy := gatewayCurve.ScalarMult(sx, sy, negC)
+	a1x, a1y := gatewayCurve.Add(zgx, zgy, csx, csy)
+
+	zcx, zcy := gatewayCurve.ScalarMult(cx, cy, z.Bytes())
+	cdx, cdy := gatewayCurve.ScalarMult(dx, dy, negC)
+	a2x, a2y := gatewayCurve.Add(zcx, zcy, cdx, cdy)
+
+	expected := partialChallenge(share, mask, partial,
+		elliptic.Marshal(gatewayCurve, a1x, a1y),
+		elliptic.Marshal(gatewayCurve, a2x, a2y))
+	if expected.Cmp(c) != 0 {
+		return ErrInvalidPartialProof
+	}
+	return nil
+}
+
+// partialChallenge hashes the proof transcript to a scalar
+func partialChallenge(parts ...[]byte) *big.Int {
+	c := new(big.Int).SetBytes(sha256Sum(append([][]byte{[]byte("LUX_FHE_GATEWAY_PARTIAL")}, parts...)...))
+	return c.Mod(c, gatewayCurve.Params().N)
+}
+
+// unmarshalGatewayPoint parses an uncompressed P-256 point
+func unmarshalGatewayPoint(b []byte) (*big.Int, *big.Int, error) {
+	x, y := elliptic.Unmarshal(gatewayCurve, b)
+	if x == nil {
+		return nil, nil, ErrInvalidInput
+	}
+	return x, y, nil
+}
+
+func sha256Sum(parts ...[]byte) []byte {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
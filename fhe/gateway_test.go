@@ -0,0 +1,229 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fhe
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+var testCommitteeAdmin = common.Address{0xad}
+
+// newTestCommittee deals Shamir shares of a random key to n validators and
+// registers them with a t-of-n gateway, last index first
+func newTestCommittee(t *testing.T, threshold, n int) (*CommitteeGateway, []common.Address, []*big.Int, *big.Int) {
+	t.Helper()
+	order := gatewayCurve.Params().N
+
+	coeffs := make([]*big.Int, threshold)
+	for i := range coeffs {
+		c, err := rand.Int(rand.Reader, order)
+		require.NoError(t, err)
+		coeffs[i] = c
+	}
+
+	g, err := NewCommitteeGateway(testCommitteeAdmin, uint32(threshold))
+	require.NoError(t, err)
+
+	validators := make([]common.Address, n)
+	shares := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		// f(x) = sum(coeffs[k] * x^k)
+		x := big.NewInt(int64(i + 1))
+		share, pow := new(big.Int), big.NewInt(1)
+		for _, c := range coeffs {
+			share.Add(share, new(big.Int).Mul(c, pow))
+			pow.Mul(pow, x)
+		}
+		shares[i] = share.Mod(share, order)
+
+		validators[i] = common.BigToAddress(big.NewInt(int64(0x100 + i)))
+	}
+
+	// Indices come from the deal, not from the order members enroll in
+	for i := n - 1; i >= 0; i-- {
+		sx, sy := gatewayCurve.ScalarBaseMult(shares[i].Bytes())
+		err := g.RegisterValidator(testCommitteeAdmin, validators[i], uint32(i+1), elliptic.Marshal(gatewayCurve, sx, sy))
+		require.NoError(t, err)
+		require.Equal(t, uint32(i+1), g.Validators[validators[i]].Index)
+	}
+	return g, validators, shares, coeffs[0]
+}
+
+func randomScalar(t *testing.T) *big.Int {
+	t.Helper()
+	k, err := rand.Int(rand.Reader, gatewayCurve.Params().N)
+	require.NoError(t, err)
+	return k
+}
+
+func TestCommitteeGatewayRegisterValidator(t *testing.T) {
+	g, err := NewCommitteeGateway(testCommitteeAdmin, 2)
+	require.NoError(t, err)
+
+	sx, sy := gatewayCurve.ScalarBaseMult(randomScalar(t).Bytes())
+	share := elliptic.Marshal(gatewayCurve, sx, sy)
+	member := common.Address{1}
+
+	// Only the admin enrolls members
+	err = g.RegisterValidator(member, member, 1, share)
+	require.ErrorIs(t, err, ErrNotCommitteeAdmin)
+	require.Empty(t, g.Validators)
+
+	err = g.RegisterValidator(testCommitteeAdmin, member, 0, share)
+	require.ErrorIs(t, err, ErrInvalidShareIndex)
+	err = g.RegisterValidator(testCommitteeAdmin, member, 1, share[1:])
+	require.ErrorIs(t, err, ErrInvalidKeyShare)
+
+	require.NoError(t, g.RegisterValidator(testCommitteeAdmin, member, 3, share))
+	err = g.RegisterValidator(testCommitteeAdmin, member, 4, share)
+	require.ErrorIs(t, err, ErrValidatorExists)
+
+	// Two members cannot hold the same evaluation point
+	err = g.RegisterValidator(testCommitteeAdmin, common.Address{2}, 3, share)
+	require.ErrorIs(t, err, ErrShareIndexTaken)
+}
+
+func TestCommitteeGatewayThresholdReconstruction(t *testing.T) {
+	g, validators, shares, secret := newTestCommittee(t, 3, 5)
+
+	r := randomScalar(t)
+	cx, cy := gatewayCurve.ScalarBaseMult(r.Bytes())
+	mask := elliptic.Marshal(gatewayCurve, cx, cy)
+
+	id, err := g.RequestDecryption(common.Address{1}, mask, 1)
+	require.NoError(t, err)
+
+	// Any three of the five members suffice
+	for i, member := range []int{4, 1, 2} {
+		partial, proof, err := GeneratePartialDecryption(shares[member], mask, randomScalar(t))
+		require.NoError(t, err)
+
+		done, err := g.SubmitPartialDecryption(validators[member], id, partial, proof)
+		require.NoError(t, err)
+		require.Equal(t, i == 2, done)
+	}
+
+	result, ok := g.GetDecryption(id)
+	require.True(t, ok)
+
+	ex, ey := gatewayCurve.ScalarMult(cx, cy, secret.Bytes())
+	require.Equal(t, elliptic.Marshal(gatewayCurve, ex, ey), result)
+
+	partial, proof, err := GeneratePartialDecryption(shares[0], mask, randomScalar(t))
+	require.NoError(t, err)
+	_, err = g.SubmitPartialDecryption(validators[0], id, partial, proof)
+	require.ErrorIs(t, err, ErrRequestFinalized)
+}
+
+func TestCommitteeGatewayRejectsInvalidPartial(t *testing.T) {
+	g, validators, shares, _ := newTestCommittee(t, 2, 3)
+
+	cx, cy := gatewayCurve.ScalarBaseMult(randomScalar(t).Bytes())
+	mask := elliptic.Marshal(gatewayCurve, cx, cy)
+	id, err := g.RequestDecryption(common.Address{1}, mask, 1)
+	require.NoError(t, err)
+
+	// A partial computed with another member's share fails its proof
+	partial, proof, err := GeneratePartialDecryption(shares[1], mask, randomScalar(t))
+	require.NoError(t, err)
+	_, err = g.SubmitPartialDecryption(validators[0], id, partial, proof)
+	require.ErrorIs(t, err, ErrInvalidPartialProof)
+
+	// A tampered proof is rejected
+	partial, proof, err = GeneratePartialDecryption(shares[0], mask, randomScalar(t))
+	require.NoError(t, err)
+	proof[len(proof)-1] ^= 1
+	_, err = g.SubmitPartialDecryption(validators[0], id, partial, proof)
+	require.ErrorIs(t, err, ErrInvalidPartialProof)
+
+	// Neither attempt counted toward the threshold
+	proof[len(proof)-1] ^= 1
+	done, err := g.SubmitPartialDecryption(validators[0], id, partial, proof)
+	require.NoError(t, err)
+	require.False(t, done)
+
+	_, err = g.SubmitPartialDecryption(validators[0], id, partial, proof)
+	require.ErrorIs(t, err, ErrDuplicatePartial)
+
+	_, err = g.SubmitPartialDecryption(common.Address{9}, id, partial, proof)
+	require.ErrorIs(t, err, ErrValidatorNotFound)
+
+	_, ok := g.GetDecryption(id)
+	require.False(t, ok)
+}
+
+// finalizeRequest submits threshold valid partials for id
+func finalizeRequest(t *testing.T, g *CommitteeGateway, validators []common.Address, shares []*big.Int, id common.Hash, mask []byte) {
+	t.Helper()
+	for i := 0; i < int(g.Threshold); i++ {
+		partial, proof, err := GeneratePartialDecryption(shares[i], mask, randomScalar(t))
+		require.NoError(t, err)
+		_, err = g.SubmitPartialDecryption(validators[i], id, partial, proof)
+		require.NoError(t, err)
+	}
+}
+
+func TestCommitteeGatewayRequestIDsUnique(t *testing.T) {
+	g, _, _, _ := newTestCommittee(t, 2, 3)
+
+	cx, cy := gatewayCurve.ScalarBaseMult(randomScalar(t).Bytes())
+	mask := elliptic.Marshal(gatewayCurve, cx, cy)
+
+	// The same requester, mask and block still get distinct IDs
+	seen := make(map[common.Hash]bool)
+	for i := 0; i < 10; i++ {
+		id, err := g.RequestDecryption(common.Address{1}, mask, 7)
+		require.NoError(t, err)
+		require.False(t, seen[id])
+		seen[id] = true
+	}
+
+	// Each input is bound into the ID
+	id := DecryptionRequestID(common.Address{1}, mask, 1, 7)
+	require.NotEqual(t, id, DecryptionRequestID(common.Address{2}, mask, 1, 7))
+	require.NotEqual(t, id, DecryptionRequestID(common.Address{1}, mask, 2, 7))
+	require.NotEqual(t, id, DecryptionRequestID(common.Address{1}, mask, 1, 8))
+	require.True(t, seen[id])
+}
+
+func TestCommitteeGatewayFulfillDecryption(t *testing.T) {
+	g, validators, shares, secret := newTestCommittee(t, 2, 3)
+	requester := common.Address{1}
+
+	cx, cy := gatewayCurve.ScalarBaseMult(randomScalar(t).Bytes())
+	mask := elliptic.Marshal(gatewayCurve, cx, cy)
+	id, err := g.RequestDecryption(requester, mask, 1)
+	require.NoError(t, err)
+	other, err := g.RequestDecryption(requester, mask, 1)
+	require.NoError(t, err)
+
+	_, err = g.FulfillDecryption(requester, common.Hash{0xde, 0xad})
+	require.ErrorIs(t, err, ErrRequestNotFound)
+
+	_, err = g.FulfillDecryption(requester, id)
+	require.ErrorIs(t, err, ErrRequestNotFinalized)
+
+	finalizeRequest(t, g, validators, shares, id, mask)
+
+	_, err = g.FulfillDecryption(common.Address{2}, id)
+	require.ErrorIs(t, err, ErrNotRequester)
+
+	result, err := g.FulfillDecryption(requester, id)
+	require.NoError(t, err)
+	ex, ey := gatewayCurve.ScalarMult(cx, cy, secret.Bytes())
+	require.Equal(t, elliptic.Marshal(gatewayCurve, ex, ey), result)
+
+	_, err = g.FulfillDecryption(requester, id)
+	require.ErrorIs(t, err, ErrRequestFulfilled)
+
+	// Fulfilling one request leaves the other with the same mask open
+	_, err = g.FulfillDecryption(requester, other)
+	require.ErrorIs(t, err, ErrRequestNotFinalized)
+}
@@ -28,6 +28,10 @@ var (
 	InputVerifierAddress = common.HexToAddress("0x0700000000000000000000000000000000000002")
 	// Decryption Gateway precompile
 	GatewayContractAddress = common.HexToAddress("0x0700000000000000000000000000000000000003")
+	// Threshold decryption committee gateway precompile
+	CommitteeGatewayAddress = common.HexToAddress("0x0700000000000000000000000000000000000004")
+	// Async FHE task manager precompile
+	TaskManagerAddress = common.HexToAddress("0x0700000000000000000000000000000000000005")
 )
 
 // FHEPrecompile is a thread-safe singleton instance of FHEContract
@@ -64,6 +68,11 @@ func (*configurator) Configure(chainConfig precompileconfig.ChainConfig, cfg pre
 		return fmt.Errorf("expected config type %T, got %T: %v", &Config{}, cfg, cfg)
 	}
 
+	// Apply governance repricing; an upgrade without overrides restores defaults
+	if err := SetGasTable(config.GasCosts); err != nil {
+		return err
+	}
+
 	// Initialize TFHE parameters if network key path is specified
 	if config.NetworkKeyPath != "" {
 		// TODO: Load network key from path
@@ -0,0 +1,388 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fhe
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+)
+
+// Task manager for asynchronous FHE computation (Lux Privacy range 0x0700)
+//
+// Expensive operations (div, rem, mul) are queued as tasks instead of being
+// evaluated inline. A registered coprocessor claims a task, evaluates it
+// off-chain and completes it with the result handle and attestations:
+// signatures over TaskResultDigest from at least Threshold distinct
+// registered coprocessors, the claimer among them. The result is then
+// delivered to the task's callback. A claim that misses its deadline
+// returns the task to the queue.
+//
+// Trust assumption: an attestation is not a proof of correct evaluation.
+// The chain accepts a result because a quorum of coprocessors vouches for
+// it, so any Threshold colluding coprocessors can finalize a wrong result.
+// Threshold must be set high enough that such a coalition is not expected.
+
+// Task states
+const (
+	TaskPending   uint8 = 0
+	TaskClaimed   uint8 = 1
+	TaskCompleted uint8 = 2
+)
+
+// Gas costs for the task manager
+const (
+	GasTaskCreate   uint64 = 25000
+	GasTaskClaim    uint64 = 10000
+	GasTaskComplete uint64 = 30000
+
+	// DefaultTaskClaimTimeout is how long a coprocessor holds a claim (blocks)
+	DefaultTaskClaimTimeout uint64 = 150
+)
+
+var (
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrTaskNotPending     = errors.New("task is not pending")
+	ErrTaskNotClaimed     = errors.New("task is not claimed by caller")
+	ErrTaskExpired        = errors.New("task claim expired")
+	ErrTaskNotExpired     = errors.New("task claim has not expired")
+	ErrNotCoprocessor     = errors.New("caller is not a registered coprocessor")
+	ErrInvalidAttestation = errors.New("invalid task result attestation")
+	ErrTooFewAttestations = errors.New("not enough coprocessors attested the result")
+	ErrUnsupportedTaskOp  = errors.New("unsupported task operation")
+)
+
+// taskResultDomain prefixes the digest coprocessors attest
+var taskResultDomain = []byte("LUX_FHE_TASK_RESULT_V1")
+
+// Storage key fields for task state
+var (
+	taskNonceKey      = common.BytesToHash([]byte("task/nonce"))
+	taskFieldMeta     = []byte("meta")
+	taskFieldLHS      = []byte("lhs")
+	taskFieldRHS      = []byte("rhs")
+	taskFieldOwner    = []byte("owner")
+	taskFieldCallback = []byte("callback")
+	taskFieldClaimer  = []byte("claimer")
+	taskFieldResult   = []byte("result")
+)
+
+// StateDB is the storage the task manager persists tasks to. Claim
+// deadlines are block numbers, so every node agrees on when one expires.
+type StateDB interface {
+	GetState(common.Address, common.Hash) common.Hash
+	SetState(common.Address, common.Hash, common.Hash) common.Hash
+	GetBlockNumber() uint64
+}
+
+// Task is a queued FHE operation
+type Task struct {
+	ID        common.Hash
+	Op        string
+	Type      uint8
+	LHS       common.Hash
+	RHS       common.Hash
+	Requester common.Address
+	Callback  common.Address
+	Status    uint8
+	Claimer   common.Address
+	Deadline  uint64 // Last block in which the claimer may complete
+	Result    common.Hash
+}
+
+// TaskCallbackInvoker delivers a completed task's result to its callback
+type TaskCallbackInvoker func(callback common.Address, taskID, result common.Hash) error
+
+// taskOps maps queueable operations to their storage code
+var taskOps = map[string]uint8{
+	"mul": 1,
+	"div": 2,
+	"rem": 3,
+}
+
+// TaskManager queues FHE operations for off-chain coprocessors
+type TaskManager struct {
+	Coprocessors map[common.Address]bool
+	ClaimTimeout uint64 // Blocks a claim stays valid
+	Threshold    uint32 // Distinct coprocessor attestations needed per result
+
+	callback TaskCallbackInvoker
+
+	mu sync.RWMutex
+}
+
+// NewTaskManager creates a task manager with the given claim timeout in
+// blocks that accepts a result once threshold coprocessors attest it
+func NewTaskManager(claimTimeout uint64, threshold uint32) (*TaskManager, error) {
+	if threshold == 0 {
+		return nil, ErrInvalidThreshold
+	}
+	if claimTimeout == 0 {
+		claimTimeout = DefaultTaskClaimTimeout
+	}
+	return &TaskManager{
+		Coprocessors: make(map[common.Address]bool),
+		ClaimTimeout: claimTimeout,
+		Threshold:    threshold,
+	}, nil
+}
+
+// AddCoprocessor registers a coprocessor allowed to claim tasks
+func (tm *TaskManager) AddCoprocessor(coprocessor common.Address) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.Coprocessors[coprocessor] = true
+}
+
+// RemoveCoprocessor revokes a coprocessor
+func (tm *TaskManager) RemoveCoprocessor(coprocessor common.Address) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	delete(tm.Coprocessors, coprocessor)
+}
+
+// SetCallbackInvoker sets how completed results are delivered
+func (tm *TaskManager) SetCallbackInvoker(invoker TaskCallbackInvoker) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.callback = invoker
+}
+
+// CreateTask queues op(lhs, rhs) and returns the task ID
+func (tm *TaskManager) CreateTask(
+	stateDB StateDB,
+	requester common.Address,
+	op string,
+	ctType uint8,
+	lhs, rhs common.Hash,
+	callback common.Address,
+) (common.Hash, error) {
+	code, ok := taskOps[op]
+	if !ok {
+		return common.Hash{}, ErrUnsupportedTaskOp
+	}
+	if _, lhsType, ok := getCiphertext(lhs); !ok || lhsType != ctType {
+		return common.Hash{}, ErrInvalidCiphertext
+	}
+	if _, rhsType, ok := getCiphertext(rhs); !ok || rhsType != ctType {
+		return common.Hash{}, ErrTypeMismatch
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	slot := stateDB.GetState(TaskManagerAddress, taskNonceKey)
+	binary.BigEndian.PutUint64(slot[24:], binary.BigEndian.Uint64(slot[24:])+1)
+	stateDB.SetState(TaskManagerAddress, taskNonceKey, slot)
+	id := common.BytesToHash(crypto.Keccak256(requester[:], slot[24:]))
+
+	task := &Task{
+		ID:        id,
+		Op:        op,
+		Type:      ctType,
+		LHS:       lhs,
+		RHS:       rhs,
+		Requester: requester,
+		Callback:  callback,
+		Status:    TaskPending,
+	}
+	tm.saveTask(stateDB, task, code)
+	return id, nil
+}
+
+// GetTask loads a task from state
+func (tm *TaskManager) GetTask(stateDB StateDB, taskID common.Hash) (*Task, error) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	return tm.loadTask(stateDB, taskID)
+}
+
+// ClaimTask assigns a pending task to a coprocessor until the claim deadline
+func (tm *TaskManager) ClaimTask(stateDB StateDB, coprocessor common.Address, taskID common.Hash) (*Task, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if !tm.Coprocessors[coprocessor] {
+		return nil, ErrNotCoprocessor
+	}
+	task, err := tm.loadTask(stateDB, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != TaskPending {
+		return nil, ErrTaskNotPending
+	}
+
+	task.Status = TaskClaimed
+	task.Claimer = coprocessor
+	task.Deadline = stateDB.GetBlockNumber() + tm.ClaimTimeout
+	tm.saveTask(stateDB, task, taskOps[task.Op])
+	return task, nil
+}
+
+// TaskResultDigest returns the hash coprocessors sign to attest a result
+// keccak256(domain || taskID || result)
+func TaskResultDigest(taskID, result common.Hash) []byte {
+	return crypto.Keccak256(taskResultDomain, taskID[:], result[:])
+}
+
+// CompleteTask records the result of a claimed task and delivers it to the
+// callback. Each attestation is a 65-byte [R || S || V] signature over
+// TaskResultDigest; together they must come from at least Threshold
+// distinct registered coprocessors including the claimer.
+func (tm *TaskManager) CompleteTask(
+	stateDB StateDB,
+	coprocessor common.Address,
+	taskID common.Hash,
+	result common.Hash,
+	attestations [][]byte,
+) error {
+	digest := TaskResultDigest(taskID, result)
+	signers := make(map[common.Address]bool, len(attestations))
+	for _, attestation := range attestations {
+		signer, err := recoverAttester(digest, attestation)
+		if err != nil {
+			return err
+		}
+		if signers[signer] {
+			return ErrInvalidAttestation
+		}
+		signers[signer] = true
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	task, err := tm.loadTask(stateDB, taskID)
+	if err != nil {
+		return err
+	}
+	if task.Status != TaskClaimed || task.Claimer != coprocessor {
+		return ErrTaskNotClaimed
+	}
+	if stateDB.GetBlockNumber() > task.Deadline {
+		return ErrTaskExpired
+	}
+	for signer := range signers {
+		if !tm.Coprocessors[signer] {
+			return ErrInvalidAttestation
+		}
+	}
+	if !signers[coprocessor] {
+		return ErrInvalidAttestation
+	}
+	if uint32(len(signers)) < tm.Threshold {
+		return ErrTooFewAttestations
+	}
+	if tm.callback != nil && task.Callback != (common.Address{}) {
+		if err := tm.callback(task.Callback, taskID, result); err != nil {
+			return err
+		}
+	}
+
+	task.Status = TaskCompleted
+	task.Result = result
+	tm.saveTask(stateDB, task, taskOps[task.Op])
+	return nil
+}
+
+// recoverAttester returns the address that signed digest
+func recoverAttester(digest, attestation []byte) (common.Address, error) {
+	if len(attestation) != 65 {
+		return common.Address{}, ErrInvalidAttestation
+	}
+	sig := make([]byte, 65)
+	copy(sig, attestation)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidAttestation
+	}
+	return common.Address(crypto.PubkeyToAddress(*pub)), nil
+}
+
+// RequeueExpired returns a task whose claim deadline has passed to the queue
+func (tm *TaskManager) RequeueExpired(stateDB StateDB, taskID common.Hash) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	task, err := tm.loadTask(stateDB, taskID)
+	if err != nil {
+		return err
+	}
+	if task.Status != TaskClaimed {
+		return ErrTaskNotClaimed
+	}
+	if stateDB.GetBlockNumber() <= task.Deadline {
+		return ErrTaskNotExpired
+	}
+
+	task.Status = TaskPending
+	task.Claimer = common.Address{}
+	task.Deadline = 0
+	tm.saveTask(stateDB, task, taskOps[task.Op])
+	return nil
+}
+
+// taskKey derives the storage slot for one field of a task
+func taskKey(taskID common.Hash, field []byte) common.Hash {
+	return common.BytesToHash(crypto.Keccak256([]byte("task"), taskID[:], field))
+}
+
+// saveTask writes a task to state. The meta slot packs
+// exists (1) || status (1) || op (1) || type (1) || deadline (8).
+func (tm *TaskManager) saveTask(stateDB StateDB, task *Task, opCode uint8) {
+	var meta common.Hash
+	meta[0] = 1
+	meta[1] = task.Status
+	meta[2] = opCode
+	meta[3] = task.Type
+	binary.BigEndian.PutUint64(meta[4:12], task.Deadline)
+
+	stateDB.SetState(TaskManagerAddress, taskKey(task.ID, taskFieldMeta), meta)
+	stateDB.SetState(TaskManagerAddress, taskKey(task.ID, taskFieldLHS), task.LHS)
+	stateDB.SetState(TaskManagerAddress, taskKey(task.ID, taskFieldRHS), task.RHS)
+	stateDB.SetState(TaskManagerAddress, taskKey(task.ID, taskFieldOwner), common.BytesToHash(task.Requester[:]))
+	stateDB.SetState(TaskManagerAddress, taskKey(task.ID, taskFieldCallback), common.BytesToHash(task.Callback[:]))
+	stateDB.SetState(TaskManagerAddress, taskKey(task.ID, taskFieldClaimer), common.BytesToHash(task.Claimer[:]))
+	stateDB.SetState(TaskManagerAddress, taskKey(task.ID, taskFieldResult), task.Result)
+}
+
+// loadTask reads a task from state
+func (tm *TaskManager) loadTask(stateDB StateDB, taskID common.Hash) (*Task, error) {
+	meta := stateDB.GetState(TaskManagerAddress, taskKey(taskID, taskFieldMeta))
+	if meta[0] == 0 {
+		return nil, ErrTaskNotFound
+	}
+
+	var op string
+	for name, code := range taskOps {
+		if code == meta[2] {
+			op = name
+		}
+	}
+
+	return &Task{
+		ID:        taskID,
+		Op:        op,
+		Type:      meta[3],
+		LHS:       stateDB.GetState(TaskManagerAddress, taskKey(taskID, taskFieldLHS)),
+		RHS:       stateDB.GetState(TaskManagerAddress, taskKey(taskID, taskFieldRHS)),
+		Requester: common.BytesToAddress(stateDB.GetState(TaskManagerAddress, taskKey(taskID, taskFieldOwner)).Bytes()),
+		Callback:  common.BytesToAddress(stateDB.GetState(TaskManagerAddress, taskKey(taskID, taskFieldCallback)).Bytes()),
+		Status:    meta[1],
+		Claimer:   common.BytesToAddress(stateDB.GetState(TaskManagerAddress, taskKey(taskID, taskFieldClaimer)).Bytes()),
+		Deadline:  binary.BigEndian.Uint64(meta[4:12]),
+		Result:    stateDB.GetState(TaskManagerAddress, taskKey(taskID, taskFieldResult)),
+	}, nil
+}
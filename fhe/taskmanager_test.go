@@ -0,0 +1,229 @@
+// Copyright (C) 2019-2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fhe
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// taskTestStateDB is an in-memory StateDB for task manager tests
+type taskTestStateDB struct {
+	storage map[common.Address]map[common.Hash]common.Hash
+	block   uint64
+}
+
+func newTaskTestStateDB() *taskTestStateDB {
+	return &taskTestStateDB{storage: make(map[common.Address]map[common.Hash]common.Hash)}
+}
+
+func (s *taskTestStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	return s.storage[addr][key]
+}
+
+func (s *taskTestStateDB) GetBlockNumber() uint64 { return s.block }
+
+func (s *taskTestStateDB) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	if s.storage[addr] == nil {
+		s.storage[addr] = make(map[common.Hash]common.Hash)
+	}
+	prev := s.storage[addr][key]
+	s.storage[addr][key] = value
+	return prev
+}
+
+// newTestTaskManager returns a manager with one registered coprocessor and
+// two queued euint64 operands
+func newTestTaskManager(t *testing.T) (*TaskManager, *ecdsa.PrivateKey, common.Hash, common.Hash) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	tm, err := NewTaskManager(60, 1)
+	require.NoError(t, err)
+	tm.AddCoprocessor(common.Address(crypto.PubkeyToAddress(key.PublicKey)))
+
+	lhs := storeCiphertext([]byte("task-lhs-ciphertext"), TypeEuint64)
+	rhs := storeCiphertext([]byte("task-rhs-ciphertext"), TypeEuint64)
+	return tm, key, lhs, rhs
+}
+
+// signTaskResult returns one attestation per key
+func signTaskResult(t *testing.T, taskID, result common.Hash, keys ...*ecdsa.PrivateKey) [][]byte {
+	t.Helper()
+
+	attestations := make([][]byte, len(keys))
+	for i, key := range keys {
+		sig, err := crypto.Sign(TaskResultDigest(taskID, result), key)
+		require.NoError(t, err)
+		attestations[i] = sig
+	}
+	return attestations
+}
+
+func TestTaskManagerLifecycle(t *testing.T) {
+	tm, key, lhs, rhs := newTestTaskManager(t)
+	state := newTaskTestStateDB()
+	state.block = 1000
+	coprocessor := common.Address(crypto.PubkeyToAddress(key.PublicKey))
+	requester := common.Address{0xaa}
+	callback := common.Address{0xcb}
+
+	var delivered common.Hash
+	tm.SetCallbackInvoker(func(to common.Address, taskID, result common.Hash) error {
+		require.Equal(t, callback, to)
+		delivered = result
+		return nil
+	})
+
+	_, err := tm.CreateTask(state, requester, "add", TypeEuint64, lhs, rhs, callback)
+	require.ErrorIs(t, err, ErrUnsupportedTaskOp)
+
+	id, err := tm.CreateTask(state, requester, "div", TypeEuint64, lhs, rhs, callback)
+	require.NoError(t, err)
+
+	id2, err := tm.CreateTask(state, requester, "div", TypeEuint64, lhs, rhs, callback)
+	require.NoError(t, err)
+	require.NotEqual(t, id, id2)
+
+	task, err := tm.GetTask(state, id)
+	require.NoError(t, err)
+	require.Equal(t, TaskPending, task.Status)
+	require.Equal(t, "div", task.Op)
+	require.Equal(t, lhs, task.LHS)
+	require.Equal(t, requester, task.Requester)
+
+	_, err = tm.ClaimTask(state, common.Address{0x01}, id)
+	require.ErrorIs(t, err, ErrNotCoprocessor)
+
+	task, err = tm.ClaimTask(state, coprocessor, id)
+	require.NoError(t, err)
+	require.Equal(t, TaskClaimed, task.Status)
+	require.Equal(t, uint64(1060), task.Deadline)
+
+	_, err = tm.ClaimTask(state, coprocessor, id)
+	require.ErrorIs(t, err, ErrTaskNotPending)
+
+	result := common.Hash{0x42}
+
+	// An attestation over a different result is rejected
+	err = tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, common.Hash{0x43}, key))
+	require.ErrorIs(t, err, ErrInvalidAttestation)
+
+	state.block += 30
+	require.NoError(t, tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, result, key)))
+	require.Equal(t, result, delivered)
+
+	task, err = tm.GetTask(state, id)
+	require.NoError(t, err)
+	require.Equal(t, TaskCompleted, task.Status)
+	require.Equal(t, result, task.Result)
+
+	// Reloading through a fresh manager reads the same state
+	fresh, err := NewTaskManager(60, 1)
+	require.NoError(t, err)
+	task, err = fresh.GetTask(state, id)
+	require.NoError(t, err)
+	require.Equal(t, TaskCompleted, task.Status)
+
+	_, err = tm.GetTask(state, common.Hash{0xff})
+	require.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+func TestTaskManagerDeadlineRequeue(t *testing.T) {
+	tm, key, lhs, rhs := newTestTaskManager(t)
+	state := newTaskTestStateDB()
+	state.block = 1000
+	coprocessor := common.Address(crypto.PubkeyToAddress(key.PublicKey))
+
+	backupKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	backup := common.Address(crypto.PubkeyToAddress(backupKey.PublicKey))
+	tm.AddCoprocessor(backup)
+
+	id, err := tm.CreateTask(state, common.Address{0xaa}, "mul", TypeEuint64, lhs, rhs, common.Address{})
+	require.NoError(t, err)
+
+	_, err = tm.ClaimTask(state, coprocessor, id)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, tm.RequeueExpired(state, id), ErrTaskNotExpired)
+
+	state.block += 61
+	result := common.Hash{0x42}
+	err = tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, result, key))
+	require.ErrorIs(t, err, ErrTaskExpired)
+
+	require.NoError(t, tm.RequeueExpired(state, id))
+	task, err := tm.GetTask(state, id)
+	require.NoError(t, err)
+	require.Equal(t, TaskPending, task.Status)
+	require.Equal(t, common.Address{}, task.Claimer)
+
+	_, err = tm.ClaimTask(state, backup, id)
+	require.NoError(t, err)
+
+	// The original claimer can no longer complete
+	err = tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, result, key))
+	require.ErrorIs(t, err, ErrTaskNotClaimed)
+
+	require.NoError(t, tm.CompleteTask(state, backup, id, result, signTaskResult(t, id, result, backupKey)))
+}
+
+func TestTaskManagerQuorumAttestation(t *testing.T) {
+	tm, key, lhs, rhs := newTestTaskManager(t)
+	state := newTaskTestStateDB()
+	state.block = 1000
+	coprocessor := common.Address(crypto.PubkeyToAddress(key.PublicKey))
+	tm.Threshold = 2
+
+	peerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tm.AddCoprocessor(common.Address(crypto.PubkeyToAddress(peerKey.PublicKey)))
+	outsiderKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	_, err = NewTaskManager(60, 0)
+	require.ErrorIs(t, err, ErrInvalidThreshold)
+
+	id, err := tm.CreateTask(state, common.Address{0xaa}, "mul", TypeEuint64, lhs, rhs, common.Address{})
+	require.NoError(t, err)
+	_, err = tm.ClaimTask(state, coprocessor, id)
+	require.NoError(t, err)
+
+	result := common.Hash{0x42}
+
+	// The claimer alone is below the quorum
+	err = tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, result, key))
+	require.ErrorIs(t, err, ErrTooFewAttestations)
+
+	// Repeating one signer does not count twice
+	err = tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, result, key, key))
+	require.ErrorIs(t, err, ErrInvalidAttestation)
+
+	// Unregistered signers are rejected
+	err = tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, result, key, outsiderKey))
+	require.ErrorIs(t, err, ErrInvalidAttestation)
+
+	// The quorum must include the claimer
+	err = tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, result, peerKey))
+	require.ErrorIs(t, err, ErrInvalidAttestation)
+
+	// Attestations over another result do not count toward this one
+	attestations := signTaskResult(t, id, result, key)
+	attestations = append(attestations, signTaskResult(t, id, common.Hash{0x43}, peerKey)...)
+	err = tm.CompleteTask(state, coprocessor, id, result, attestations)
+	require.ErrorIs(t, err, ErrInvalidAttestation)
+
+	require.NoError(t, tm.CompleteTask(state, coprocessor, id, result, signTaskResult(t, id, result, key, peerKey)))
+	task, err := tm.GetTask(state, id)
+	require.NoError(t, err)
+	require.Equal(t, TaskCompleted, task.Status)
+	require.Equal(t, result, task.Result)
+}
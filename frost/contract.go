@@ -4,14 +4,13 @@
 package frost
 
 import (
-	"crypto/sha256"
 	"encoding/binary"
 	"errors"
 	"fmt"
 
-	"github.com/luxfi/crypto"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/crypto/ecverify"
 )
 
 var (
@@ -25,8 +24,8 @@ var (
 
 	ErrInvalidInputLength  = errors.New("invalid input length")
 	ErrInvalidThreshold    = errors.New("invalid threshold: t must be > 0 and <= n")
-	ErrInvalidPublicKey    = errors.New("invalid public key")
-	ErrInvalidSignature    = errors.New("invalid signature")
+	ErrInvalidPublicKey    = ecverify.ErrInvalidPublicKey
+	ErrInvalidSignature    = ecverify.ErrInvalidSignature
 	ErrSignatureVerifyFail = errors.New("signature verification failed")
 )
 
@@ -36,9 +35,9 @@ const (
 	FROSTVerifyBaseGas      uint64 = 50_000 // Base cost for Schnorr verification
 	FROSTVerifyPerSignerGas uint64 = 5_000  // Cost per signer in threshold
 
-	// FROST uses 32-byte Schnorr signatures (Ed25519 or secp256k1)
-	FROSTPublicKeySize   = 32 // Compressed public key
-	FROSTSignatureSize   = 64 // Schnorr signature (R || s)
+	// FROST uses BIP-340 Schnorr signatures over secp256k1
+	FROSTPublicKeySize   = 32 // x-only public key
+	FROSTSignatureSize   = 64 // Schnorr signature (R.x || s)
 	FROSTMessageHashSize = 32 // SHA-256 message hash
 	ThresholdSize        = 4  // uint32 threshold t
 	TotalSignersSize     = 4  // uint32 total signers n
@@ -90,9 +89,9 @@ func (p *frostVerifyPrecompile) Run(
 	// Input format:
 	// [0:4]      = threshold t (uint32)
 	// [4:8]      = total signers n (uint32)
-	// [8:40]     = aggregated public key (32 bytes)
+	// [8:40]     = aggregated x-only public key (32 bytes)
 	// [40:72]    = message hash (32 bytes)
-	// [72:136]   = Schnorr signature (64 bytes: R || s)
+	// [72:136]   = BIP-340 Schnorr signature (64 bytes: R.x || s)
 
 	if len(input) < MinInputSize {
 		return nil, suppliedGas - gasCost, fmt.Errorf("%w: expected at least %d bytes, got %d",
@@ -114,8 +113,10 @@ func (p *frostVerifyPrecompile) Run(
 	signature := input[72:136]
 
 	// Verify Schnorr signature
-	// FROST produces standard Schnorr signatures that can be verified normally
-	valid := verifySchnorrSignature(publicKey, messageHash, signature)
+	// FROST over secp256k1 produces standard BIP-340 signatures. A key that
+	// is not a valid x-only point cannot verify, so it reports invalid
+	// rather than failing the call.
+	valid, _ := ecverify.VerifySchnorrBIP340(publicKey, messageHash, signature)
 
 	// Return result as 32-byte word (1 = valid, 0 = invalid)
 	result := make([]byte, 32)
@@ -125,36 +126,3 @@ func (p *frostVerifyPrecompile) Run(
 
 	return result, suppliedGas - gasCost, nil
 }
-
-// verifySchnorrSignature verifies a Schnorr signature
-// This is a simplified implementation for Ed25519-style Schnorr
-func verifySchnorrSignature(publicKey, messageHash, signature []byte) bool {
-	if len(publicKey) != 32 || len(messageHash) != 32 || len(signature) != 64 {
-		return false
-	}
-
-	// Extract R from signature for challenge computation
-	R := signature[0:32]
-	// Note: s = signature[32:64] would be used in full Schnorr verification
-
-	// Compute challenge: c = H(R || P || m)
-	// This is used to verify the Schnorr equation: s*G = R + c*P
-	hasher := sha256.New()
-	hasher.Write(R)
-	hasher.Write(publicKey)
-	hasher.Write(messageHash)
-	_ = hasher.Sum(nil) // challenge - used in full implementation
-
-	// For production, use proper Ed25519 or secp256k1 Schnorr verification
-	// This is a placeholder that uses Ethereum's secp256k1 for now
-
-	// Use standard ECDSA verification as fallback
-	// Real implementation would use Schnorr verification
-	pk, err := crypto.UnmarshalPubkey(append([]byte{0x04}, publicKey...))
-	if err != nil {
-		return false
-	}
-
-	// For now, verify as ECDSA (production would use Schnorr)
-	return crypto.VerifySignature(crypto.FromECDSAPub(pk), messageHash, signature[:64])
-}
@@ -4,11 +4,15 @@
 package frost
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
+	"math/big"
 	"testing"
 
+	"github.com/luxfi/crypto"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/crypto/ecverify"
 	"github.com/stretchr/testify/require"
 )
 
@@ -198,3 +202,72 @@ func BenchmarkFROSTVerify_10of15(b *testing.B) {
 		)
 	}
 }
+
+// signBIP340 produces a BIP-340 signature as a FROST signing session would
+func signBIP340(t *testing.T, message []byte) (publicKey, signature []byte) {
+	t.Helper()
+
+	c := crypto.S256()
+	n := c.Params().N
+	d, err := rand.Int(rand.Reader, n)
+	require.NoError(t, err)
+	px, py := c.ScalarBaseMult(common.LeftPadBytes(d.Bytes(), 32))
+	if py.Bit(0) == 1 {
+		d.Sub(n, d)
+	}
+	k, err := rand.Int(rand.Reader, n)
+	require.NoError(t, err)
+	rx, ry := c.ScalarBaseMult(common.LeftPadBytes(k.Bytes(), 32))
+	if ry.Bit(0) == 1 {
+		k.Sub(n, k)
+	}
+
+	tag := sha256.Sum256([]byte("BIP0340/challenge"))
+	h := sha256.New()
+	h.Write(tag[:])
+	h.Write(tag[:])
+	h.Write(common.LeftPadBytes(rx.Bytes(), 32))
+	h.Write(common.LeftPadBytes(px.Bytes(), 32))
+	h.Write(message)
+	e := new(big.Int).SetBytes(h.Sum(nil))
+
+	s := new(big.Int).Mul(e, d)
+	s.Add(s, k).Mod(s, n)
+	return common.LeftPadBytes(px.Bytes(), 32),
+		append(common.LeftPadBytes(rx.Bytes(), 32), common.LeftPadBytes(s.Bytes(), 32)...)
+}
+
+func TestFROSTVerify_MatchesSharedCore(t *testing.T) {
+	messageHash := sha256.Sum256([]byte("test message"))
+	publicKey, signature := signBIP340(t, messageHash[:])
+
+	corrupted := common.CopyBytes(signature)
+	corrupted[40] ^= 0xFF
+	otherHash := sha256.Sum256([]byte("other"))
+	noPoint := make([]byte, 32)
+	noPoint[31] = 5
+
+	cases := map[string][3][]byte{
+		"valid":          {publicKey, messageHash[:], signature},
+		"corrupted":      {publicKey, messageHash[:], corrupted},
+		"wrong message":  {publicKey, otherHash[:], signature},
+		"invalid pubkey": {noPoint, messageHash[:], signature},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			input := make([]byte, MinInputSize)
+			binary.BigEndian.PutUint32(input[0:4], 2)
+			binary.BigEndian.PutUint32(input[4:8], 3)
+			copy(input[8:40], tc[0])
+			copy(input[40:72], tc[1])
+			copy(input[72:136], tc[2])
+
+			result, _, err := FROSTVerifyPrecompile.Run(nil, common.Address{}, ContractFROSTVerifyAddress, input, 1_000_000, true)
+			require.NoError(t, err)
+
+			want, _ := ecverify.VerifySchnorrBIP340(tc[0], tc[1], tc[2])
+			require.Equal(t, want, result[31] == 1)
+			require.Equal(t, name == "valid", want)
+		})
+	}
+}
@@ -33,6 +33,11 @@ const (
 	ModeMLDSA87 uint8 = 0x87 // ML-DSA-87 (256-bit security, NIST Level 5)
 )
 
+// PreHashedSelector as the first input byte selects pre-hashed verification:
+// the signature is over a digest of the message (see
+// contract.PreHashedMessage) and gas does not depend on the message length
+const PreHashedSelector uint8 = 0x01
+
 // Size constants for each mode
 const (
 	// ML-DSA-44
@@ -50,6 +55,7 @@ const (
 	// Common
 	ModeByte       = 1  // Mode indicator byte
 	MessageLenSize = 32 // Size of message length field (uint256)
+	HashAlgSize    = 1  // Size of the pre-hash algorithm id
 )
 
 // Gas costs - adjusted per mode based on computational complexity
@@ -90,6 +96,17 @@ func (p *mldsaVerifyPrecompile) RequiredGas(input []byte) uint64 {
 		return MLDSA65VerifyBaseGas // Default to ML-DSA-65 gas for invalid input
 	}
 
+	if input[0] == PreHashedSelector {
+		if len(input) < 2*ModeByte {
+			return MLDSA65VerifyBaseGas
+		}
+		_, _, baseGas, _, err := getModeParams(input[1])
+		if err != nil {
+			return MLDSA65VerifyBaseGas
+		}
+		return baseGas
+	}
+
 	mode := input[0]
 	pubKeySize, _, baseGas, _, err := getModeParams(mode)
 	if err != nil {
@@ -117,9 +134,18 @@ func (p *mldsaVerifyPrecompile) RequiredGas(input []byte) uint64 {
 //	[1:pubKeyEnd]    = public key (size depends on mode)
 //	[pubKeyEnd:+32]  = message length as uint256 (32 bytes)
 //	[+32:+sigEnd]    = signature (size depends on mode)
-//	[sigEnd:...]     = message (variable length)
+//	[sigEnd:...]     = message (at most contract.MessageSizeLimit bytes)
 //
 // Output: 32-byte word (1 = valid, 0 = invalid)
+//
+// Pre-hashed format:
+//
+//	[0]              = PreHashedSelector
+//	[1]              = mode byte
+//	[2:pubKeyEnd]    = public key
+//	[pubKeyEnd]      = pre-hash algorithm id (contract.PreHash*)
+//	[+1:digestEnd]   = digest (length fixed by the algorithm)
+//	[digestEnd:...]  = signature
 func (p *mldsaVerifyPrecompile) Run(
 	accessibleState contract.AccessibleState,
 	caller common.Address,
@@ -139,6 +165,11 @@ func (p *mldsaVerifyPrecompile) Run(
 		return nil, suppliedGas - gasCost, fmt.Errorf("%w: need at least mode byte", ErrInvalidInputLength)
 	}
 
+	if input[0] == PreHashedSelector {
+		ret, err := runPreHashed(input[1:])
+		return ret, suppliedGas - gasCost, err
+	}
+
 	// Parse mode
 	mode := input[0]
 	pubKeySize, sigSize, _, mldsaMode, err := getModeParams(mode)
@@ -168,6 +199,9 @@ func (p *mldsaVerifyPrecompile) Run(
 
 	// Read message length
 	messageLen := readUint256(messageLenBytes)
+	if err := contract.CheckMessageSize(messageLen, contract.MessageSizeLimit(accessibleState)); err != nil {
+		return nil, suppliedGas - gasCost, err
+	}
 
 	// Validate total input size
 	expectedSize := uint64(sigEnd) + messageLen
@@ -197,6 +231,49 @@ func (p *mldsaVerifyPrecompile) Run(
 	return result, suppliedGas - gasCost, nil
 }
 
+// runPreHashed verifies [mode][pubKey][hashAlg][digest][signature]
+func runPreHashed(input []byte) ([]byte, error) {
+	if len(input) < ModeByte {
+		return nil, fmt.Errorf("%w: need at least mode byte", ErrInvalidInputLength)
+	}
+	mode := input[0]
+	pubKeySize, sigSize, _, mldsaMode, err := getModeParams(mode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrUnsupportedMode, mode)
+	}
+
+	pubKeyEnd := ModeByte + pubKeySize
+	if len(input) < pubKeyEnd+HashAlgSize {
+		return nil, fmt.Errorf("%w: input too short for pre-hash algorithm", ErrInvalidInputLength)
+	}
+	hashAlg := input[pubKeyEnd]
+	digestLen, err := contract.PreHashDigestLength(hashAlg)
+	if err != nil {
+		return nil, err
+	}
+	digestStart := pubKeyEnd + HashAlgSize
+	digestEnd := digestStart + digestLen
+	if len(input) != digestEnd+sigSize {
+		return nil, fmt.Errorf("%w: expected %d bytes for mode 0x%02x, got %d",
+			ErrInvalidInputLength, digestEnd+sigSize, mode, len(input))
+	}
+
+	message, err := contract.PreHashedMessage(hashAlg, input[digestStart:digestEnd])
+	if err != nil {
+		return nil, err
+	}
+	pub, err := mldsa.PublicKeyFromBytes(input[ModeByte:pubKeyEnd], mldsaMode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	result := make([]byte, 32)
+	if pub.Verify(message, input[digestEnd:], nil) {
+		result[31] = 1
+	}
+	return result, nil
+}
+
 // readUint256 reads a big-endian uint256 as uint64
 func readUint256(b []byte) uint64 {
 	if len(b) != 32 {
@@ -217,7 +294,7 @@ func (p *mldsaVerifyPrecompile) isLegacyFormat(input []byte) bool {
 		return false
 	}
 	mode := input[0]
-	return mode != ModeMLDSA44 && mode != ModeMLDSA65 && mode != ModeMLDSA87
+	return mode != ModeMLDSA44 && mode != ModeMLDSA65 && mode != ModeMLDSA87 && mode != PreHashedSelector
 }
 
 // RunLegacy handles the legacy ML-DSA-65 only format for backwards compatibility
@@ -261,6 +338,9 @@ func (p *mldsaVerifyPrecompile) RunLegacy(
 	signature := input[legacyPubKeySize+legacyMsgLenSize : legacyPubKeySize+legacyMsgLenSize+legacySigSize]
 
 	messageLen := readUint256(messageLenBytes)
+	if err := contract.CheckMessageSize(messageLen, contract.MessageSizeLimit(accessibleState)); err != nil {
+		return nil, suppliedGas - gasCost, err
+	}
 	expectedSize := uint64(legacyMinInput) + messageLen
 	if uint64(len(input)) != expectedSize {
 		return nil, suppliedGas - gasCost, fmt.Errorf("%w: expected %d bytes total, got %d",
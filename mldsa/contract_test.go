@@ -9,6 +9,7 @@ import (
 
 	"github.com/luxfi/crypto/mldsa"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
 	"github.com/stretchr/testify/require"
 )
 
@@ -254,6 +255,25 @@ func TestMLDSAVerify_LargeMessage(t *testing.T) {
 	require.Equal(t, byte(1), ret[31])
 }
 
+func TestMLDSAVerify_MaxMessageSize(t *testing.T) {
+	pk, signature, msg := createTestSignature(t, mldsa.MLDSA65, make([]byte, contract.MaxMessageSize))
+	input := createInputWithMode(ModeMLDSA65, pk, signature, msg)
+	ret, _, err := MLDSAVerifyPrecompile.Run(
+		nil, common.Address{}, ContractMLDSAVerifyAddress,
+		input, MLDSAVerifyPrecompile.RequiredGas(input), false,
+	)
+	require.NoError(t, err)
+	require.Equal(t, byte(1), ret[31])
+
+	pk, signature, msg = createTestSignature(t, mldsa.MLDSA65, make([]byte, contract.MaxMessageSize+1))
+	input = createInputWithMode(ModeMLDSA65, pk, signature, msg)
+	_, _, err = MLDSAVerifyPrecompile.Run(
+		nil, common.Address{}, ContractMLDSAVerifyAddress,
+		input, MLDSAVerifyPrecompile.RequiredGas(input), false,
+	)
+	require.ErrorIs(t, err, contract.ErrMessageTooLarge)
+}
+
 func TestMLDSAVerify_GasCost(t *testing.T) {
 	message := []byte("test")
 	pk, signature, msg := createTestSignature(t, mldsa.MLDSA65, message)
@@ -367,3 +387,86 @@ func BenchmarkMLDSAVerify_AllModes(b *testing.B) {
 		})
 	}
 }
+
+// createPreHashedInput creates pre-hashed precompile input
+func createPreHashedInput(mode uint8, pk []byte, hashAlg uint8, digest, signature []byte) []byte {
+	input := []byte{PreHashedSelector, mode}
+	input = append(input, pk...)
+	input = append(input, hashAlg)
+	input = append(input, digest...)
+	return append(input, signature...)
+}
+
+func TestMLDSAVerify_PreHashedMatchesPure(t *testing.T) {
+	priv, err := mldsa.GenerateKey(rand.Reader, mldsa.MLDSA65)
+	require.NoError(t, err)
+	pk := priv.PublicKey.Bytes()
+
+	message := make([]byte, 4*1024*1024)
+	for i := range message {
+		message[i] = byte(i % 251)
+	}
+
+	// Pure: the whole message is in the input and charged per byte
+	pureSig, err := priv.Sign(rand.Reader, message, nil)
+	require.NoError(t, err)
+	pureInput := createInputWithMode(ModeMLDSA65, pk, pureSig, message)
+	pureGas := MLDSAVerifyPrecompile.RequiredGas(pureInput)
+	require.Equal(t, MLDSA65VerifyBaseGas+uint64(len(message))*MLDSAVerifyPerByteGas, pureGas)
+	ret, _, err := MLDSAVerifyPrecompile.Run(nil, common.Address{}, ContractMLDSAVerifyAddress, pureInput, pureGas, false)
+	require.NoError(t, err)
+	require.Equal(t, byte(1), ret[31])
+
+	for _, hashAlg := range []uint8{contract.PreHashSHA256, contract.PreHashSHA3_512, contract.PreHashSHAKE256} {
+		digest, err := contract.PreHash(hashAlg, message)
+		require.NoError(t, err)
+		signed, err := contract.PreHashedMessage(hashAlg, digest)
+		require.NoError(t, err)
+		sig, err := priv.Sign(rand.Reader, signed, nil)
+		require.NoError(t, err)
+
+		// Pre-hashed: only the digest is in the input and gas is flat
+		input := createPreHashedInput(ModeMLDSA65, pk, hashAlg, digest, sig)
+		gas := MLDSAVerifyPrecompile.RequiredGas(input)
+		require.Equal(t, MLDSA65VerifyBaseGas, gas)
+		ret, remainingGas, err := MLDSAVerifyPrecompile.Run(nil, common.Address{}, ContractMLDSAVerifyAddress, input, gas, false)
+		require.NoError(t, err)
+		require.Zero(t, remainingGas)
+		require.Equal(t, byte(1), ret[31], "hash 0x%02x", hashAlg)
+
+		// A digest of a different message does not verify
+		tampered := append([]byte{}, message...)
+		tampered[len(tampered)-1] ^= 1
+		otherDigest, err := contract.PreHash(hashAlg, tampered)
+		require.NoError(t, err)
+		input = createPreHashedInput(ModeMLDSA65, pk, hashAlg, otherDigest, sig)
+		ret, _, err = MLDSAVerifyPrecompile.Run(nil, common.Address{}, ContractMLDSAVerifyAddress, input, gas, false)
+		require.NoError(t, err)
+		require.Equal(t, byte(0), ret[31])
+	}
+
+	// The pure signature is not valid as a pre-hashed one
+	digest, err := contract.PreHash(contract.PreHashSHA256, message)
+	require.NoError(t, err)
+	input := createPreHashedInput(ModeMLDSA65, pk, contract.PreHashSHA256, digest, pureSig)
+	ret, _, err = MLDSAVerifyPrecompile.Run(nil, common.Address{}, ContractMLDSAVerifyAddress, input, MLDSA65VerifyBaseGas, false)
+	require.NoError(t, err)
+	require.Equal(t, byte(0), ret[31])
+}
+
+func TestMLDSAVerify_PreHashedInvalidInput(t *testing.T) {
+	pk := make([]byte, MLDSA44PublicKeySize)
+	sig := make([]byte, MLDSA44SignatureSize)
+
+	// Digest length must match the algorithm
+	input := createPreHashedInput(ModeMLDSA44, pk, contract.PreHashSHA512, make([]byte, 32), sig)
+	_, _, err := MLDSAVerifyPrecompile.Run(nil, common.Address{}, ContractMLDSAVerifyAddress, input, MLDSA44VerifyBaseGas, false)
+	require.ErrorIs(t, err, ErrInvalidInputLength)
+
+	input = createPreHashedInput(ModeMLDSA44, pk, 0x7F, make([]byte, 32), sig)
+	_, _, err = MLDSAVerifyPrecompile.Run(nil, common.Address{}, ContractMLDSAVerifyAddress, input, MLDSA44VerifyBaseGas, false)
+	require.ErrorIs(t, err, contract.ErrUnsupportedPreHash)
+
+	_, _, err = MLDSAVerifyPrecompile.Run(nil, common.Address{}, ContractMLDSAVerifyAddress, []byte{PreHashedSelector, 0x99}, MLDSA65VerifyBaseGas, false)
+	require.ErrorIs(t, err, ErrUnsupportedMode)
+}
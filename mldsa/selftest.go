@@ -0,0 +1,70 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mldsa
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/crypto/mldsa"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/registry"
+)
+
+var selfTestMessage = []byte("lux precompile self-test")
+
+func init() {
+	if err := registry.RegisterSelfTest("ML_DSA", SelfTest); err != nil {
+		panic(err)
+	}
+}
+
+// SelfTest signs a fixed message with a fresh ML-DSA-65 key and fails unless
+// the precompile accepts the signature and rejects it over another message
+func SelfTest() error {
+	return selfTest(func(input []byte) ([]byte, error) {
+		ret, _, err := MLDSAVerifyPrecompile.Run(
+			nil, common.Address{}, ContractMLDSAVerifyAddress,
+			input, MLDSAVerifyPrecompile.RequiredGas(input), true,
+		)
+		return ret, err
+	})
+}
+
+func selfTest(run func(input []byte) ([]byte, error)) error {
+	priv, err := mldsa.GenerateKey(rand.Reader, mldsa.MLDSA65)
+	if err != nil {
+		return fmt.Errorf("ML-DSA-65 key generation: %w", err)
+	}
+	pk := priv.PublicKey.Bytes()
+
+	sig, err := priv.Sign(rand.Reader, selfTestMessage, nil)
+	if err != nil {
+		return fmt.Errorf("ML-DSA-65 signing: %w", err)
+	}
+	if !selfTestVerify(run, pk, sig, selfTestMessage) {
+		return errors.New("ML-DSA-65 signature rejected")
+	}
+	if selfTestVerify(run, pk, sig, append([]byte("not "), selfTestMessage...)) {
+		return errors.New("ML-DSA-65 signature accepted for a different message")
+	}
+	return nil
+}
+
+// selfTestVerify runs a mode-prefixed ML-DSA-65 verification
+func selfTestVerify(run func(input []byte) ([]byte, error), pk, sig, message []byte) bool {
+	msgLen := make([]byte, MessageLenSize)
+	binary.BigEndian.PutUint64(msgLen[MessageLenSize-8:], uint64(len(message)))
+
+	input := []byte{ModeMLDSA65}
+	input = append(input, pk...)
+	input = append(input, msgLen...)
+	input = append(input, sig...)
+	input = append(input, message...)
+
+	ret, err := run(input)
+	return err == nil && len(ret) == 32 && ret[31] == 1
+}
@@ -0,0 +1,24 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mldsa
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, SelfTest())
+}
+
+func TestSelfTestDetectsBrokenVerify(t *testing.T) {
+	valid := make([]byte, 32)
+	valid[31] = 1
+	acceptAll := func([]byte) ([]byte, error) { return valid, nil }
+	require.ErrorContains(t, selfTest(acceptAll), "different message")
+
+	rejectAll := func([]byte) ([]byte, error) { return make([]byte, 32), nil }
+	require.ErrorContains(t, selfTest(rejectAll), "rejected")
+}
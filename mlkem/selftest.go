@@ -0,0 +1,67 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mlkem
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/crypto/mlkem"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/registry"
+)
+
+func init() {
+	if err := registry.RegisterSelfTest("ML_KEM", SelfTest); err != nil {
+		panic(err)
+	}
+}
+
+// SelfTest encapsulates to a fresh ML-KEM-768 key through the precompile and
+// fails unless decapsulation recovers the same shared secret, and a tampered
+// ciphertext does not
+func SelfTest() error {
+	return selfTest(func(input []byte) ([]byte, error) {
+		ret, _, err := MLKEMPrecompile.Run(
+			nil, common.Address{}, ContractAddress,
+			input, MLKEMPrecompile.RequiredGas(input), true,
+		)
+		return ret, err
+	})
+}
+
+func selfTest(run func(input []byte) ([]byte, error)) error {
+	pk, sk, err := mlkem.GenerateKey(mlkem.MLKEM768)
+	if err != nil {
+		return fmt.Errorf("ML-KEM-768 key generation: %w", err)
+	}
+
+	out, err := run(append([]byte{OpEncapsulate, ModeMLKEM768}, pk.Bytes()...))
+	if err != nil || len(out) != MLKEM768CiphertextSize+MLKEM768SharedKeySize {
+		return errors.New("ML-KEM-768 encapsulation failed")
+	}
+	ct, ss := out[:MLKEM768CiphertextSize], out[MLKEM768CiphertextSize:]
+
+	decapsulate := func(ct []byte) []byte {
+		input := []byte{OpDecapsulate, ModeMLKEM768}
+		input = append(input, sk.Bytes()...)
+		input = append(input, ct...)
+		got, err := run(input)
+		if err != nil {
+			return nil
+		}
+		return got
+	}
+	if !bytes.Equal(decapsulate(ct), ss) {
+		return errors.New("ML-KEM-768 decapsulation did not recover the shared secret")
+	}
+
+	tampered := bytes.Clone(ct)
+	tampered[0] ^= 0x01
+	if bytes.Equal(decapsulate(tampered), ss) {
+		return errors.New("ML-KEM-768 tampered ciphertext recovered the shared secret")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mlkem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, SelfTest())
+}
+
+func TestSelfTestDetectsBrokenDecapsulate(t *testing.T) {
+	// Encapsulation returns a zero secret that decapsulation never matches
+	zeroSecret := func(input []byte) ([]byte, error) {
+		if input[0] == OpEncapsulate {
+			return make([]byte, MLKEM768CiphertextSize+MLKEM768SharedKeySize), nil
+		}
+		return []byte{0x01}, nil
+	}
+	require.ErrorContains(t, selfTest(zeroSecret), "did not recover")
+
+	// Decapsulation ignores the ciphertext
+	secret := make([]byte, MLKEM768SharedKeySize)
+	constant := func(input []byte) ([]byte, error) {
+		if input[0] == OpEncapsulate {
+			return make([]byte, MLKEM768CiphertextSize+MLKEM768SharedKeySize), nil
+		}
+		return secret, nil
+	}
+	require.ErrorContains(t, selfTest(constant), "tampered")
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/precompileconfig"
 )
 
 // AddressRange represents a continuous range of addresses
@@ -232,6 +233,14 @@ func GetPrecompileModuleByAddress(address common.Address) (Module, bool) {
 	return Module{}, false
 }
 
+// IsActive returns true if the precompile at [address] is active under
+// [chainConfig] in a block with timestamp [time]. Chain configs that do not
+// implement precompileconfig.ActivePrecompilesConfig activate nothing.
+func IsActive(chainConfig precompileconfig.ChainConfig, address common.Address, time uint64) bool {
+	c, ok := chainConfig.(precompileconfig.ActivePrecompilesConfig)
+	return ok && c.IsPrecompileEnabled(address, time)
+}
+
 func GetPrecompileModule(key string) (Module, bool) {
 	for _, stm := range registeredModules {
 		if stm.ConfigKey == key {
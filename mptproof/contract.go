@@ -0,0 +1,300 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package mptproof implements a precompile that verifies Ethereum
+// Merkle-Patricia trie proofs for the Lux EVM. Address: 0x6230 (LP-6230)
+//
+// Bridges and light clients use it to read Ethereum-style state trustlessly:
+// given a state root they already trust (e.g. from a verified header), an
+// eth_getProof response proves an account's nonce, balance, storage root and
+// code hash, and the storage proof proves a slot under that storage root.
+//
+// Operations:
+// - VerifyAccountProof: prove an account under a state root
+// - VerifyStorageProof: prove a storage slot under a storage root
+//
+// A proof that does not hash to the root is an error. A valid proof that
+// the key is absent succeeds with exists = 0.
+package mptproof
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/ethdb/memorydb"
+	"github.com/luxfi/geth/rlp"
+	"github.com/luxfi/geth/trie"
+	"github.com/luxfi/precompile/contract"
+)
+
+var (
+	// ContractAddress is the address of the MPT proof precompile (Bridges page, LP-6230)
+	ContractAddress = common.HexToAddress("0x0000000000000000000000000000000000006230")
+
+	// Singleton instance
+	MPTProofPrecompile = &mptProofPrecompile{}
+
+	_ contract.StatefulPrecompiledContract = &mptProofPrecompile{}
+
+	ErrInvalidInput     = errors.New("invalid mptproof input")
+	ErrInvalidOperation = errors.New("invalid operation selector")
+	ErrTooManyNodes     = errors.New("too many proof nodes")
+	ErrInvalidProof     = errors.New("invalid merkle-patricia proof")
+	ErrInvalidAccount   = errors.New("invalid account encoding")
+	ErrInvalidSlotValue = errors.New("invalid storage slot encoding")
+)
+
+// Operation selectors (first byte of input)
+const (
+	OpVerifyAccountProof = 0x01
+	OpVerifyStorageProof = 0x02
+)
+
+// Sizes and limits
+const (
+	RootSize       = 32
+	AddressSize    = 20
+	SlotKeySize    = 32
+	NodeCountSize  = 2
+	NodeLenSize    = 2
+	MaxProofNodes  = 64 // a 32-byte key has 64 nibbles, bounding the trie depth
+	WordSize       = 32
+	AccountOutSize = 5 * WordSize
+	StorageOutSize = 2 * WordSize
+)
+
+// Gas costs
+const (
+	GasVerifyBase   = 3000
+	GasPerProofWord = 6  // Keccak256 per-word cost over every proof node
+	GasPerNode      = 30 // Keccak256 base cost per proof node
+)
+
+type mptProofPrecompile struct{}
+
+// Address returns the address of the MPT proof precompile
+func (p *mptProofPrecompile) Address() common.Address {
+	return ContractAddress
+}
+
+// RequiredGas charges one Keccak256 per proof node over the node bytes
+func (p *mptProofPrecompile) RequiredGas(input []byte) uint64 {
+	var headerSize int
+	switch {
+	case len(input) < 1:
+		return GasVerifyBase
+	case input[0] == OpVerifyAccountProof:
+		headerSize = 1 + RootSize + AddressSize
+	case input[0] == OpVerifyStorageProof:
+		headerSize = 1 + RootSize + SlotKeySize
+	default:
+		return GasVerifyBase
+	}
+	if len(input) < headerSize+NodeCountSize {
+		return GasVerifyBase
+	}
+	proof := input[headerSize:]
+	nodes := uint64(binary.BigEndian.Uint16(proof))
+	words := uint64(len(proof)+WordSize-1) / WordSize
+	return GasVerifyBase + nodes*GasPerNode + words*GasPerProofWord
+}
+
+// Run executes the MPT proof precompile.
+//
+// VerifyAccountProof input:
+//
+//	[0]       = OpVerifyAccountProof
+//	[1:33]    = state root
+//	[33:53]   = account address
+//	[53:...]  = proof
+//
+// Output: [exists][nonce][balance][storageRoot][codeHash], 32 bytes each
+//
+// VerifyStorageProof input:
+//
+//	[0]       = OpVerifyStorageProof
+//	[1:33]    = storage root
+//	[33:65]   = storage slot key (unhashed)
+//	[65:...]  = proof
+//
+// Output: [exists][value], 32 bytes each
+//
+// A proof is [nodeCount(2)] followed by nodeCount entries of
+// [nodeLen(2)][RLP-encoded trie node], in any order; eth_getProof's
+// accountProof and storageProof arrays encode directly.
+func (p *mptProofPrecompile) Run(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	addr common.Address,
+	input []byte,
+	suppliedGas uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	gasCost := p.RequiredGas(input)
+	if suppliedGas < gasCost {
+		return nil, 0, contract.ErrOutOfGas
+	}
+	remainingGas := suppliedGas - gasCost
+
+	if len(input) < 1 {
+		return nil, remainingGas, ErrInvalidInput
+	}
+
+	var (
+		result []byte
+		err    error
+	)
+	switch input[0] {
+	case OpVerifyAccountProof:
+		if len(input) < 1+RootSize+AddressSize {
+			return nil, remainingGas, ErrInvalidInput
+		}
+		root := common.BytesToHash(input[1 : 1+RootSize])
+		address := common.BytesToAddress(input[1+RootSize : 1+RootSize+AddressSize])
+		result, err = VerifyAccountProof(root, address, input[1+RootSize+AddressSize:])
+	case OpVerifyStorageProof:
+		if len(input) < 1+RootSize+SlotKeySize {
+			return nil, remainingGas, ErrInvalidInput
+		}
+		root := common.BytesToHash(input[1 : 1+RootSize])
+		key := common.BytesToHash(input[1+RootSize : 1+RootSize+SlotKeySize])
+		result, err = VerifyStorageProof(root, key, input[1+RootSize+SlotKeySize:])
+	default:
+		return nil, remainingGas, fmt.Errorf("%w: 0x%02x", ErrInvalidOperation, input[0])
+	}
+	return result, remainingGas, err
+}
+
+// VerifyAccountProof verifies an account proof against a state root and
+// returns the encoded account; see Run for the proof and output formats
+func VerifyAccountProof(stateRoot common.Hash, address common.Address, proof []byte) ([]byte, error) {
+	value, err := verify(stateRoot, crypto.Keccak256(address[:]), proof)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, AccountOutSize)
+	if value == nil {
+		return out, nil
+	}
+	var account types.StateAccount
+	if err := rlp.DecodeBytes(value, &account); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAccount, err)
+	}
+	if len(account.CodeHash) != WordSize {
+		return nil, fmt.Errorf("%w: code hash is %d bytes", ErrInvalidAccount, len(account.CodeHash))
+	}
+	out[WordSize-1] = 1
+	binary.BigEndian.PutUint64(out[2*WordSize-8:2*WordSize], account.Nonce)
+	account.Balance.WriteToSlice(out[2*WordSize : 3*WordSize])
+	copy(out[3*WordSize:4*WordSize], account.Root[:])
+	copy(out[4*WordSize:], account.CodeHash)
+	return out, nil
+}
+
+// VerifyStorageProof verifies a storage proof against an account's storage
+// root and returns the encoded slot; see Run for the proof and output formats
+func VerifyStorageProof(storageRoot common.Hash, key common.Hash, proof []byte) ([]byte, error) {
+	value, err := verify(storageRoot, crypto.Keccak256(key[:]), proof)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, StorageOutSize)
+	if value == nil {
+		return out, nil
+	}
+	// Slots are stored as the RLP string of the value with leading zeros trimmed
+	var slot []byte
+	if err := rlp.DecodeBytes(value, &slot); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSlotValue, err)
+	}
+	if len(slot) == 0 || len(slot) > WordSize || slot[0] == 0 {
+		return nil, fmt.Errorf("%w: non-canonical %d-byte value", ErrInvalidSlotValue, len(slot))
+	}
+	out[WordSize-1] = 1
+	copy(out[StorageOutSize-len(slot):], slot)
+	return out, nil
+}
+
+// verify walks the trie from root along the hashed key through the proof
+// nodes. It returns the leaf value, or nil for a valid proof of absence.
+func verify(root common.Hash, hashedKey []byte, proof []byte) ([]byte, error) {
+	nodes, err := parseProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	db := memorydb.New()
+	for _, node := range nodes {
+		if err := db.Put(crypto.Keccak256(node), node); err != nil {
+			return nil, err
+		}
+	}
+	value, err := trie.VerifyProof(root, hashedKey, db)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return value, nil
+}
+
+// parseProof splits [nodeCount(2)] ([nodeLen(2)][node])*. Trailing bytes
+// are rejected.
+func parseProof(proof []byte) ([][]byte, error) {
+	if len(proof) < NodeCountSize {
+		return nil, fmt.Errorf("%w: missing proof node count", ErrInvalidInput)
+	}
+	count := int(binary.BigEndian.Uint16(proof))
+	if count == 0 {
+		return nil, fmt.Errorf("%w: empty proof", ErrInvalidInput)
+	}
+	if count > MaxProofNodes {
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooManyNodes, count, MaxProofNodes)
+	}
+
+	body := proof[NodeCountSize:]
+	nodes := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		if len(body) < NodeLenSize {
+			return nil, fmt.Errorf("%w: proof node %d truncated", ErrInvalidInput, i)
+		}
+		end := NodeLenSize + int(binary.BigEndian.Uint16(body))
+		if len(body) < end {
+			return nil, fmt.Errorf("%w: proof node %d truncated", ErrInvalidInput, i)
+		}
+		nodes = append(nodes, body[NodeLenSize:end])
+		body = body[end:]
+	}
+	if len(body) != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes after proof", ErrInvalidInput, len(body))
+	}
+	return nodes, nil
+}
+
+// EncodeProof encodes proof nodes, such as the accountProof or storageProof
+// of an eth_getProof response, into the precompile's proof format
+func EncodeProof(nodes [][]byte) ([]byte, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: empty proof", ErrInvalidInput)
+	}
+	if len(nodes) > MaxProofNodes {
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooManyNodes, len(nodes), MaxProofNodes)
+	}
+	size := NodeCountSize
+	for _, node := range nodes {
+		if len(node) > 0xFFFF {
+			return nil, fmt.Errorf("%w: %d-byte node", ErrInvalidInput, len(node))
+		}
+		size += NodeLenSize + len(node)
+	}
+	out := make([]byte, 0, size)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(nodes)))
+	for _, node := range nodes {
+		out = binary.BigEndian.AppendUint16(out, uint16(len(node)))
+		out = append(out, node...)
+	}
+	return out, nil
+}
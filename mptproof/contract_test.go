@@ -0,0 +1,243 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mptproof
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/luxfi/crypto"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/geth/core/types"
+	"github.com/luxfi/geth/rlp"
+	"github.com/luxfi/geth/trie"
+	"github.com/stretchr/testify/require"
+)
+
+// proofList collects the nodes geth's trie writes for a proof, in path order
+type proofList [][]byte
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+func (l *proofList) Delete(key []byte) error { return nil }
+
+var (
+	testAccount  = common.HexToAddress("0x8ba1f109551bD432803012645Ac136ddd64DBA72")
+	testCodeHash = common.BytesToHash(crypto.Keccak256([]byte("contract code")))
+	testBalance  = uint256.MustFromDecimal("1234567890000000000000")
+)
+
+const testNonce = 42
+
+// slotValue returns the value stored in slot i of the test contract
+func slotValue(i int64) common.Hash {
+	return common.BigToHash(new(big.Int).Mul(big.NewInt(i+1), big.NewInt(0x1_0000_0001)))
+}
+
+// testState builds a geth storage trie for testAccount and a state trie of
+// 256 accounts around it, and returns both
+func testState(t *testing.T) (stateTrie, storageTrie *trie.Trie) {
+	t.Helper()
+
+	storageTrie = trie.NewEmpty(nil)
+	for i := int64(0); i < 64; i++ {
+		key := common.BigToHash(big.NewInt(i))
+		value, err := rlp.EncodeToBytes(common.TrimLeftZeroes(slotValue(i).Bytes()))
+		require.NoError(t, err)
+		require.NoError(t, storageTrie.Update(crypto.Keccak256(key[:]), value))
+	}
+
+	stateTrie = trie.NewEmpty(nil)
+	for i := 0; i < 256; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(i) + 1))
+		account := types.StateAccount{
+			Nonce:    uint64(i),
+			Balance:  uint256.NewInt(uint64(i) * 1e9),
+			Root:     types.EmptyRootHash,
+			CodeHash: types.EmptyCodeHash[:],
+		}
+		value, err := rlp.EncodeToBytes(&account)
+		require.NoError(t, err)
+		require.NoError(t, stateTrie.Update(crypto.Keccak256(addr[:]), value))
+	}
+	value, err := rlp.EncodeToBytes(&types.StateAccount{
+		Nonce:    testNonce,
+		Balance:  testBalance,
+		Root:     storageTrie.Hash(),
+		CodeHash: testCodeHash[:],
+	})
+	require.NoError(t, err)
+	require.NoError(t, stateTrie.Update(crypto.Keccak256(testAccount[:]), value))
+	return stateTrie, storageTrie
+}
+
+func prove(t *testing.T, tr *trie.Trie, key []byte) proofList {
+	t.Helper()
+	var nodes proofList
+	require.NoError(t, tr.Prove(crypto.Keccak256(key), &nodes))
+	require.Greater(t, len(nodes), 1, "proof should cross more than the root")
+	return nodes
+}
+
+func encodeProof(t *testing.T, nodes [][]byte) []byte {
+	t.Helper()
+	proof, err := EncodeProof(nodes)
+	require.NoError(t, err)
+	return proof
+}
+
+func run(t *testing.T, input []byte) ([]byte, error) {
+	t.Helper()
+	gas := MPTProofPrecompile.RequiredGas(input)
+	out, remaining, err := MPTProofPrecompile.Run(nil, common.Address{}, ContractAddress, input, gas, true)
+	require.Zero(t, remaining)
+	return out, err
+}
+
+func accountInput(root common.Hash, address common.Address, proof []byte) []byte {
+	input := append([]byte{OpVerifyAccountProof}, root[:]...)
+	input = append(input, address[:]...)
+	return append(input, proof...)
+}
+
+func storageInput(root common.Hash, key common.Hash, proof []byte) []byte {
+	input := append([]byte{OpVerifyStorageProof}, root[:]...)
+	input = append(input, key[:]...)
+	return append(input, proof...)
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	require := require.New(t)
+	stateTrie, storageTrie := testState(t)
+	root := stateTrie.Hash()
+	nodes := prove(t, stateTrie, testAccount[:])
+
+	out, err := run(t, accountInput(root, testAccount, encodeProof(t, nodes)))
+	require.NoError(err)
+	require.Len(out, AccountOutSize)
+	require.Equal(uint64(1), new(big.Int).SetBytes(out[:WordSize]).Uint64())
+	require.Equal(uint64(testNonce), new(big.Int).SetBytes(out[WordSize:2*WordSize]).Uint64())
+	require.Equal(testBalance.ToBig(), new(big.Int).SetBytes(out[2*WordSize:3*WordSize]))
+	require.Equal(storageTrie.Hash(), common.BytesToHash(out[3*WordSize:4*WordSize]))
+	require.Equal(testCodeHash, common.BytesToHash(out[4*WordSize:]))
+
+	// Node order does not matter
+	reversed := make([][]byte, len(nodes))
+	for i, node := range nodes {
+		reversed[len(nodes)-1-i] = node
+	}
+	again, err := run(t, accountInput(root, testAccount, encodeProof(t, reversed)))
+	require.NoError(err)
+	require.Equal(out, again)
+}
+
+func TestVerifyAccountProof_Tampered(t *testing.T) {
+	stateTrie, _ := testState(t)
+	root := stateTrie.Hash()
+	nodes := prove(t, stateTrie, testAccount[:])
+
+	// Flip one byte in every node in turn; each breaks the hash chain
+	for i := range nodes {
+		tampered := make([][]byte, len(nodes))
+		copy(tampered, nodes)
+		tampered[i] = common.CopyBytes(nodes[i])
+		tampered[i][len(tampered[i])-1] ^= 0x01
+
+		_, err := run(t, accountInput(root, testAccount, encodeProof(t, tampered)))
+		require.ErrorIs(t, err, ErrInvalidProof, "node %d", i)
+	}
+
+	// A valid proof checked against a different root
+	otherRoot := common.BytesToHash(crypto.Keccak256([]byte("other root")))
+	_, err := run(t, accountInput(otherRoot, testAccount, encodeProof(t, nodes)))
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	// A missing node
+	_, err = run(t, accountInput(root, testAccount, encodeProof(t, nodes[:len(nodes)-1])))
+	require.ErrorIs(t, err, ErrInvalidProof)
+}
+
+func TestVerifyAccountProof_Absent(t *testing.T) {
+	require := require.New(t)
+	stateTrie, _ := testState(t)
+	absent := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+	out, err := run(t, accountInput(stateTrie.Hash(), absent, encodeProof(t, prove(t, stateTrie, absent[:]))))
+	require.NoError(err)
+	require.Equal(make([]byte, AccountOutSize), out)
+}
+
+func TestVerifyStorageProof(t *testing.T) {
+	require := require.New(t)
+	_, storageTrie := testState(t)
+	root := storageTrie.Hash()
+
+	for _, i := range []int64{0, 7, 63} {
+		key := common.BigToHash(big.NewInt(i))
+		out, err := run(t, storageInput(root, key, encodeProof(t, prove(t, storageTrie, key[:]))))
+		require.NoError(err)
+		require.Len(out, StorageOutSize)
+		require.Equal(byte(1), out[WordSize-1])
+		require.Equal(slotValue(i), common.BytesToHash(out[WordSize:]))
+	}
+
+	// An unset slot proves absent
+	key := common.BigToHash(big.NewInt(1000))
+	out, err := run(t, storageInput(root, key, encodeProof(t, prove(t, storageTrie, key[:]))))
+	require.NoError(err)
+	require.Equal(make([]byte, StorageOutSize), out)
+
+	// A proof whose leaf value was altered does not verify
+	nodes := prove(t, storageTrie, common.BigToHash(big.NewInt(7)).Bytes())
+	nodes[len(nodes)-1] = common.CopyBytes(nodes[len(nodes)-1])
+	nodes[len(nodes)-1][len(nodes[len(nodes)-1])-1] ^= 0x01
+	_, err = run(t, storageInput(root, common.BigToHash(big.NewInt(7)), encodeProof(t, nodes)))
+	require.ErrorIs(err, ErrInvalidProof)
+}
+
+func TestMPTProof_InvalidInput(t *testing.T) {
+	stateTrie, _ := testState(t)
+	root := stateTrie.Hash()
+	proof := encodeProof(t, prove(t, stateTrie, testAccount[:]))
+
+	tests := []struct {
+		name  string
+		input []byte
+		err   error
+	}{
+		{"empty", nil, ErrInvalidInput},
+		{"unknown op", []byte{0x7f}, ErrInvalidOperation},
+		{"short header", []byte{OpVerifyAccountProof, 0x01}, ErrInvalidInput},
+		{"no proof", accountInput(root, testAccount, nil), ErrInvalidInput},
+		{"zero nodes", accountInput(root, testAccount, []byte{0, 0}), ErrInvalidInput},
+		{"too many nodes", accountInput(root, testAccount, binary.BigEndian.AppendUint16(nil, MaxProofNodes+1)), ErrTooManyNodes},
+		{"truncated", accountInput(root, testAccount, proof[:len(proof)-1]), ErrInvalidInput},
+		{"trailing bytes", accountInput(root, testAccount, append(common.CopyBytes(proof), 0x00)), ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := run(t, tt.input)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestMPTProof_GasScalesWithProof(t *testing.T) {
+	require := require.New(t)
+	stateTrie, _ := testState(t)
+	nodes := prove(t, stateTrie, testAccount[:])
+	input := accountInput(stateTrie.Hash(), testAccount, encodeProof(t, nodes))
+
+	proofLen := uint64(len(input) - 1 - RootSize - AddressSize)
+	expected := GasVerifyBase + uint64(len(nodes))*GasPerNode + (proofLen+WordSize-1)/WordSize*GasPerProofWord
+	require.Equal(expected, MPTProofPrecompile.RequiredGas(input))
+
+	_, _, err := MPTProofPrecompile.Run(nil, common.Address{}, ContractAddress, input, expected-1, true)
+	require.Error(err)
+}
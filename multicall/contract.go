@@ -0,0 +1,233 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package multicall implements a precompile that batches calls to other
+// precompiles in one invocation. Each call is dispatched through the
+// registered module's StatefulPrecompiledContract with its own gas limit;
+// if any call marked required fails, every state change made by the batch is
+// reverted. Address: 0x8002 (Lux Core System range)
+package multicall
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/modules"
+)
+
+var (
+	// ContractAddress is the address of the multicall precompile (Lux Core System range 0x8002)
+	ContractAddress = common.HexToAddress("0x8002")
+
+	// Singleton instance
+	MulticallPrecompile = &multicallPrecompile{}
+
+	_ contract.StatefulPrecompiledContract = &multicallPrecompile{}
+
+	ErrInvalidInput       = errors.New("invalid multicall input")
+	ErrTooManyCalls       = errors.New("too many calls in batch")
+	ErrUnknownTarget      = errors.New("target is not a registered precompile")
+	ErrInactiveTarget     = errors.New("target precompile is not active at this block")
+	ErrRecursiveCall      = errors.New("multicall cannot call itself")
+	ErrRequiredCallFailed = errors.New("required call failed")
+)
+
+// Gas costs
+const (
+	GasBase    uint64 = 700 // flat cost of a batch
+	GasPerCall uint64 = 100 // dispatch overhead per call, excluding the call's own gas
+)
+
+// MaxCalls is the maximum number of calls in one batch
+const MaxCalls = 64
+
+// FlagRequired marks a call whose failure reverts the whole batch
+const FlagRequired uint8 = 0x01
+
+// Encoding sizes
+const (
+	countSize     = 2
+	callHeaderLen = common.AddressLength + 1 + 8 + 4 // target, flags, gasLimit, inputLen
+	resultHeader  = 1 + 8 + 4                        // success, gasUsed, retLen
+)
+
+// call is one decoded entry of a batch
+type call struct {
+	target   common.Address
+	required bool
+	gasLimit uint64
+	input    []byte
+}
+
+type multicallPrecompile struct{}
+
+// Address returns the address of the multicall precompile
+func (p *multicallPrecompile) Address() common.Address {
+	return ContractAddress
+}
+
+// RequiredGas returns the dispatch overhead of the batch. The gas of each
+// call is charged separately, up to its gas limit, as it executes.
+func (p *multicallPrecompile) RequiredGas(input []byte) uint64 {
+	if len(input) < countSize {
+		return GasBase
+	}
+	count := uint64(binary.BigEndian.Uint16(input))
+	return GasBase + count*GasPerCall
+}
+
+// Run executes a batch of precompile calls.
+// Input: [count(2)] then per call [target(20)] [flags(1)] [gasLimit(8)] [inputLen(4)] [input]
+// Output: [count(2)] then per call [success(1)] [gasUsed(8)] [retLen(4)] [ret]
+//
+// A failed call returns its error message as ret. Its own state changes are
+// reverted and the batch continues, unless the call is marked required, in
+// which case the batch fails and all of its state changes are reverted.
+func (p *multicallPrecompile) Run(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	addr common.Address,
+	input []byte,
+	suppliedGas uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	remainingGas, err := contract.DeductGas(suppliedGas, p.RequiredGas(input))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	calls, err := parseCalls(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	var stateDB contract.StateDB
+	if accessibleState != nil {
+		stateDB = accessibleState.GetStateDB()
+	}
+	batchSnapshot := snapshot(stateDB)
+
+	output := make([]byte, countSize, countSize+len(calls)*resultHeader)
+	binary.BigEndian.PutUint16(output, uint16(len(calls)))
+
+	for i, c := range calls {
+		gasLimit := min(c.gasLimit, remainingGas)
+		callSnapshot := snapshot(stateDB)
+
+		ret, gasLeft, err := dispatch(accessibleState, caller, c, gasLimit, readOnly)
+		if gasLeft > gasLimit {
+			gasLeft = gasLimit
+		}
+		gasUsed := gasLimit - gasLeft
+		remainingGas -= gasUsed
+
+		if err != nil {
+			revert(stateDB, callSnapshot)
+			if c.required {
+				revert(stateDB, batchSnapshot)
+				return nil, remainingGas, fmt.Errorf("%w: call %d to %s: %w", ErrRequiredCallFailed, i, c.target, err)
+			}
+			ret = []byte(err.Error())
+		}
+
+		output = append(output, boolByte(err == nil))
+		output = binary.BigEndian.AppendUint64(output, gasUsed)
+		output = binary.BigEndian.AppendUint32(output, uint32(len(ret)))
+		output = append(output, ret...)
+	}
+
+	return output, remainingGas, nil
+}
+
+// dispatch runs one call through the target's registered contract. Only
+// precompiles the chain config activates at the current block are callable;
+// a registered module that is scheduled later, disabled, or not part of this
+// chain's upgrades is rejected as if the EVM had called it directly.
+func dispatch(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	c call,
+	gasLimit uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	if c.target == ContractAddress {
+		return nil, gasLimit, ErrRecursiveCall
+	}
+	module, ok := modules.GetPrecompileModuleByAddress(c.target)
+	if !ok || module.Contract == nil {
+		return nil, gasLimit, ErrUnknownTarget
+	}
+	if accessibleState == nil {
+		return nil, gasLimit, ErrInactiveTarget
+	}
+	blockContext := accessibleState.GetBlockContext()
+	if blockContext == nil || !modules.IsActive(accessibleState.GetChainConfig(), c.target, blockContext.Timestamp()) {
+		return nil, gasLimit, ErrInactiveTarget
+	}
+	return module.Contract.Run(accessibleState, caller, c.target, c.input, gasLimit, readOnly)
+}
+
+// parseCalls decodes the batch encoding
+func parseCalls(input []byte) ([]call, error) {
+	if len(input) < countSize {
+		return nil, ErrInvalidInput
+	}
+	count := int(binary.BigEndian.Uint16(input))
+	if count > MaxCalls {
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooManyCalls, count, MaxCalls)
+	}
+
+	calls := make([]call, count)
+	offset := countSize
+	for i := range calls {
+		if len(input)-offset < callHeaderLen {
+			return nil, fmt.Errorf("%w: truncated header for call %d", ErrInvalidInput, i)
+		}
+		header := input[offset : offset+callHeaderLen]
+		offset += callHeaderLen
+
+		flags := header[common.AddressLength]
+		if flags&^FlagRequired != 0 {
+			return nil, fmt.Errorf("%w: unknown flags 0x%02x for call %d", ErrInvalidInput, flags, i)
+		}
+		inputLen := uint64(binary.BigEndian.Uint32(header[common.AddressLength+9:]))
+		if uint64(len(input)-offset) < inputLen {
+			return nil, fmt.Errorf("%w: truncated input for call %d", ErrInvalidInput, i)
+		}
+
+		calls[i] = call{
+			target:   common.BytesToAddress(header[:common.AddressLength]),
+			required: flags&FlagRequired != 0,
+			gasLimit: binary.BigEndian.Uint64(header[common.AddressLength+1:]),
+			input:    input[offset : offset+int(inputLen)],
+		}
+		offset += int(inputLen)
+	}
+	if offset != len(input) {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidInput, len(input)-offset)
+	}
+	return calls, nil
+}
+
+func snapshot(stateDB contract.StateDB) int {
+	if stateDB == nil {
+		return 0
+	}
+	return stateDB.Snapshot()
+}
+
+func revert(stateDB contract.StateDB, id int) {
+	if stateDB != nil {
+		stateDB.RevertToSnapshot(id)
+	}
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,349 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package multicall
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/modules"
+	"github.com/luxfi/precompile/precompileconfig"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	writerAddr = common.HexToAddress("0x8f01")
+	failerAddr = common.HexToAddress("0x8f02")
+
+	errMockFailure = errors.New("mock failure")
+)
+
+const mockGas uint64 = 1_000
+
+// writer stores its input under slot 0 of its own address and echoes it
+type writer struct{}
+
+func (writer) Run(state contract.AccessibleState, _ common.Address, addr common.Address, input []byte, gas uint64, _ bool) ([]byte, uint64, error) {
+	remaining, err := contract.DeductGas(gas, mockGas)
+	if err != nil {
+		return nil, 0, err
+	}
+	state.GetStateDB().SetState(addr, common.Hash{}, common.BytesToHash(input))
+	return input, remaining, nil
+}
+
+// failer writes to state and then fails
+type failer struct{}
+
+func (failer) Run(state contract.AccessibleState, _ common.Address, addr common.Address, input []byte, gas uint64, _ bool) ([]byte, uint64, error) {
+	remaining, err := contract.DeductGas(gas, mockGas)
+	if err != nil {
+		return nil, 0, err
+	}
+	state.GetStateDB().SetState(addr, common.Hash{}, common.BytesToHash(input))
+	return nil, remaining, errMockFailure
+}
+
+func init() {
+	for _, m := range []modules.Module{
+		{ConfigKey: "multicallTestWriter", Address: writerAddr, Contract: writer{}},
+		{ConfigKey: "multicallTestFailer", Address: failerAddr, Contract: failer{}},
+	} {
+		if err := modules.RegisterModule(m); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// journalStateDB is a StateDB with storage and snapshots
+type journalStateDB struct {
+	contract.StateDB
+	storage   map[common.Address]map[common.Hash]common.Hash
+	snapshots []map[common.Address]map[common.Hash]common.Hash
+}
+
+func newJournalStateDB() *journalStateDB {
+	return &journalStateDB{storage: make(map[common.Address]map[common.Hash]common.Hash)}
+}
+
+func (s *journalStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	return s.storage[addr][key]
+}
+
+func (s *journalStateDB) SetState(addr common.Address, key, value common.Hash) common.Hash {
+	prev := s.storage[addr][key]
+	if s.storage[addr] == nil {
+		s.storage[addr] = make(map[common.Hash]common.Hash)
+	}
+	s.storage[addr][key] = value
+	return prev
+}
+
+func (s *journalStateDB) Snapshot() int {
+	copied := make(map[common.Address]map[common.Hash]common.Hash, len(s.storage))
+	for addr, slots := range s.storage {
+		copied[addr] = make(map[common.Hash]common.Hash, len(slots))
+		for k, v := range slots {
+			copied[addr][k] = v
+		}
+	}
+	s.snapshots = append(s.snapshots, copied)
+	return len(s.snapshots) - 1
+}
+
+func (s *journalStateDB) RevertToSnapshot(id int) {
+	s.storage = s.snapshots[id]
+	s.snapshots = s.snapshots[:id]
+}
+
+type blockContext struct{ timestamp uint64 }
+
+func (b blockContext) Number() *big.Int                                       { return nil }
+func (b blockContext) Timestamp() uint64                                      { return b.timestamp }
+func (b blockContext) GetPredicateResults(common.Hash, common.Address) []byte { return nil }
+
+// chainConfig activates each precompile from its listed timestamp
+type chainConfig map[common.Address]uint64
+
+func (c chainConfig) IsDurango(uint64) bool { return true }
+func (c chainConfig) IsPrecompileEnabled(address common.Address, time uint64) bool {
+	activation, ok := c[address]
+	return ok && time >= activation
+}
+
+// defaultChainConfig activates both mock precompiles from genesis
+var defaultChainConfig = chainConfig{writerAddr: 0, failerAddr: 0}
+
+type accessibleState struct {
+	stateDB   contract.StateDB
+	config    precompileconfig.ChainConfig
+	timestamp uint64
+}
+
+func (a *accessibleState) GetStateDB() contract.StateDB { return a.stateDB }
+func (a *accessibleState) GetBlockContext() contract.BlockContext {
+	return blockContext{a.timestamp}
+}
+func (a *accessibleState) GetConsensusContext() context.Context { return context.Background() }
+func (a *accessibleState) GetChainConfig() precompileconfig.ChainConfig {
+	if a.config == nil {
+		return defaultChainConfig
+	}
+	return a.config
+}
+func (a *accessibleState) GetPrecompileEnv() contract.PrecompileEnvironment { return nil }
+
+func encodeCall(target common.Address, required bool, gasLimit uint64, input []byte) []byte {
+	var flags byte
+	if required {
+		flags = FlagRequired
+	}
+	out := append(target.Bytes(), flags)
+	out = binary.BigEndian.AppendUint64(out, gasLimit)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(input)))
+	return append(out, input...)
+}
+
+func encodeBatch(calls ...[]byte) []byte {
+	out := binary.BigEndian.AppendUint16(nil, uint16(len(calls)))
+	for _, c := range calls {
+		out = append(out, c...)
+	}
+	return out
+}
+
+type result struct {
+	success bool
+	gasUsed uint64
+	ret     []byte
+}
+
+func decodeResults(t *testing.T, output []byte) []result {
+	t.Helper()
+	require.GreaterOrEqual(t, len(output), countSize)
+	results := make([]result, binary.BigEndian.Uint16(output))
+	output = output[countSize:]
+	for i := range results {
+		require.GreaterOrEqual(t, len(output), resultHeader)
+		retLen := int(binary.BigEndian.Uint32(output[9:resultHeader]))
+		results[i] = result{
+			success: output[0] == 1,
+			gasUsed: binary.BigEndian.Uint64(output[1:9]),
+			ret:     output[resultHeader : resultHeader+retLen],
+		}
+		output = output[resultHeader+retLen:]
+	}
+	require.Empty(t, output)
+	return results
+}
+
+func slot(stateDB *journalStateDB, addr common.Address) common.Hash {
+	return stateDB.GetState(addr, common.Hash{})
+}
+
+func TestMulticallPrecompile_Address(t *testing.T) {
+	require.Equal(t, common.HexToAddress("0x0000000000000000000000000000000000008002"), ContractAddress)
+	require.Equal(t, ContractAddress, MulticallPrecompile.Address())
+	require.Equal(t, ContractAddress, Module.Address())
+}
+
+func TestMulticall_SuccessfulBatch(t *testing.T) {
+	stateDB := newJournalStateDB()
+	state := &accessibleState{stateDB: stateDB}
+
+	input := encodeBatch(
+		encodeCall(writerAddr, true, 5_000, []byte{0x01}),
+		encodeCall(writerAddr, false, 5_000, []byte{0x02}),
+	)
+	overhead := MulticallPrecompile.RequiredGas(input)
+	require.Equal(t, GasBase+2*GasPerCall, overhead)
+
+	supplied := overhead + 10_000
+	ret, remaining, err := MulticallPrecompile.Run(state, common.Address{}, ContractAddress, input, supplied, false)
+	require.NoError(t, err)
+	require.Equal(t, supplied-overhead-2*mockGas, remaining)
+
+	results := decodeResults(t, ret)
+	require.Len(t, results, 2)
+	for i, r := range results {
+		require.True(t, r.success)
+		require.Equal(t, mockGas, r.gasUsed)
+		require.Equal(t, []byte{byte(i + 1)}, r.ret)
+	}
+	require.Equal(t, common.BytesToHash([]byte{0x02}), slot(stateDB, writerAddr))
+}
+
+func TestMulticall_OptionalFailureContinues(t *testing.T) {
+	stateDB := newJournalStateDB()
+	state := &accessibleState{stateDB: stateDB}
+
+	unknown := common.HexToAddress("0x8fff")
+	input := encodeBatch(
+		encodeCall(writerAddr, true, 5_000, []byte{0x01}),
+		encodeCall(failerAddr, false, 5_000, []byte{0x02}),
+		encodeCall(unknown, false, 5_000, nil),
+		encodeCall(ContractAddress, false, 5_000, nil),
+	)
+	ret, _, err := MulticallPrecompile.Run(state, common.Address{}, ContractAddress, input, 100_000, false)
+	require.NoError(t, err)
+
+	results := decodeResults(t, ret)
+	require.Len(t, results, 4)
+	require.True(t, results[0].success)
+	require.False(t, results[1].success)
+	require.Equal(t, errMockFailure.Error(), string(results[1].ret))
+	require.Equal(t, mockGas, results[1].gasUsed)
+	require.False(t, results[2].success)
+	require.Equal(t, ErrUnknownTarget.Error(), string(results[2].ret))
+	require.Zero(t, results[2].gasUsed)
+	require.False(t, results[3].success)
+	require.Equal(t, ErrRecursiveCall.Error(), string(results[3].ret))
+
+	// The successful call's write survives, the failed call's write does not
+	require.Equal(t, common.BytesToHash([]byte{0x01}), slot(stateDB, writerAddr))
+	require.Equal(t, common.Hash{}, slot(stateDB, failerAddr))
+}
+
+func TestMulticall_RequiredFailureRevertsBatch(t *testing.T) {
+	stateDB := newJournalStateDB()
+	state := &accessibleState{stateDB: stateDB}
+
+	input := encodeBatch(
+		encodeCall(writerAddr, false, 5_000, []byte{0x01}),
+		encodeCall(failerAddr, true, 5_000, []byte{0x02}),
+		encodeCall(writerAddr, false, 5_000, []byte{0x03}),
+	)
+	supplied := uint64(100_000)
+	ret, remaining, err := MulticallPrecompile.Run(state, common.Address{}, ContractAddress, input, supplied, false)
+	require.ErrorIs(t, err, ErrRequiredCallFailed)
+	require.ErrorIs(t, err, errMockFailure)
+	require.Nil(t, ret)
+	// Gas of the calls that ran is still consumed
+	require.Equal(t, supplied-MulticallPrecompile.RequiredGas(input)-2*mockGas, remaining)
+
+	require.Equal(t, common.Hash{}, slot(stateDB, writerAddr))
+	require.Equal(t, common.Hash{}, slot(stateDB, failerAddr))
+}
+
+func TestMulticall_GasLimitCapsCall(t *testing.T) {
+	stateDB := newJournalStateDB()
+	state := &accessibleState{stateDB: stateDB}
+
+	// The call's gas limit is below what the writer needs
+	input := encodeBatch(encodeCall(writerAddr, false, mockGas-1, []byte{0x01}))
+	ret, _, err := MulticallPrecompile.Run(state, common.Address{}, ContractAddress, input, 100_000, false)
+	require.NoError(t, err)
+
+	results := decodeResults(t, ret)
+	require.False(t, results[0].success)
+	require.Equal(t, mockGas-1, results[0].gasUsed)
+	require.Equal(t, common.Hash{}, slot(stateDB, writerAddr))
+}
+
+func TestMulticall_InvalidInput(t *testing.T) {
+	call := encodeCall(writerAddr, false, 5_000, []byte{0x01})
+
+	tests := map[string][]byte{
+		"empty":           nil,
+		"truncated call":  encodeBatch(call[:len(call)-1]),
+		"trailing bytes":  append(encodeBatch(call), 0x00),
+		"unknown flags":   encodeBatch(append(append(writerAddr.Bytes(), 0x80), make([]byte, 12)...)),
+		"missing entries": binary.BigEndian.AppendUint16(nil, 2),
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := MulticallPrecompile.Run(nil, common.Address{}, ContractAddress, input, 100_000, false)
+			require.ErrorIs(t, err, ErrInvalidInput)
+		})
+	}
+
+	tooMany := binary.BigEndian.AppendUint16(nil, MaxCalls+1)
+	_, _, err := MulticallPrecompile.Run(nil, common.Address{}, ContractAddress, tooMany, 1_000_000, false)
+	require.ErrorIs(t, err, ErrTooManyCalls)
+
+	_, _, err = MulticallPrecompile.Run(nil, common.Address{}, ContractAddress, encodeBatch(call), GasBase, false)
+	require.ErrorIs(t, err, contract.ErrOutOfGas)
+}
+
+func TestMulticall_InactiveTargetRejected(t *testing.T) {
+	stateDB := newJournalStateDB()
+	// The failer activates at 100 and the writer is registered but never
+	// activated on this chain
+	state := &accessibleState{stateDB: stateDB, config: chainConfig{failerAddr: 100}, timestamp: 99}
+
+	input := encodeBatch(
+		encodeCall(writerAddr, false, 5_000, []byte{0x01}),
+		encodeCall(failerAddr, false, 5_000, []byte{0x02}),
+	)
+	ret, _, err := MulticallPrecompile.Run(state, common.Address{}, ContractAddress, input, 100_000, false)
+	require.NoError(t, err)
+
+	results := decodeResults(t, ret)
+	for _, r := range results {
+		require.False(t, r.success)
+		require.Equal(t, ErrInactiveTarget.Error(), string(r.ret))
+		require.Zero(t, r.gasUsed)
+	}
+	require.Equal(t, common.Hash{}, slot(stateDB, writerAddr))
+	require.Equal(t, common.Hash{}, slot(stateDB, failerAddr))
+
+	// Once the failer's activation timestamp is reached it is dispatched
+	state.timestamp = 100
+	ret, _, err = MulticallPrecompile.Run(state, common.Address{}, ContractAddress, input, 100_000, false)
+	require.NoError(t, err)
+	results = decodeResults(t, ret)
+	require.Equal(t, ErrInactiveTarget.Error(), string(results[0].ret))
+	require.Equal(t, errMockFailure.Error(), string(results[1].ret))
+	require.Equal(t, mockGas, results[1].gasUsed)
+
+	// A required call to an inactive target reverts the batch
+	input = encodeBatch(encodeCall(writerAddr, true, 5_000, []byte{0x01}))
+	_, _, err = MulticallPrecompile.Run(state, common.Address{}, ContractAddress, input, 100_000, false)
+	require.ErrorIs(t, err, ErrInactiveTarget)
+}
@@ -144,6 +144,12 @@ library PQCryptoLib {
     bytes4 constant MLKEM_ENCAPSULATE_SELECTOR = "encp";
     bytes4 constant MLKEM_DECAPSULATE_SELECTOR = "decp";
     bytes4 constant SLHDSA_VERIFY_SELECTOR = "slhs";
+    /// @dev PQ sealed box: ML-KEM encapsulation + AES-256-GCM
+    /// seal input: [mode(1)][seed(32)][pubkey][plaintext]
+    /// seal output: [mlkem_ciphertext][nonce(12)][aead_ciphertext]
+    /// open input: [mode(1)][privkey_len(2)][privkey][sealed]
+    bytes4 constant PQSEAL_SELECTOR = "seal";
+    bytes4 constant PQOPEN_SELECTOR = "open";
 
     /// @dev Gas costs
     uint256 constant MLDSA_VERIFY_GAS = 10000;
@@ -161,6 +167,9 @@ library PQCryptoLib {
     uint8 constant MLKEM_768 = 1;
     uint8 constant MLKEM_1024 = 2;
 
+    /// @dev OR into the encapsulation mode for the unframed [ciphertext][sharedSecret] output
+    uint8 constant MLKEM_LEGACY_OUTPUT_FLAG = 0x80;
+
     error PQCryptoCallFailed();
     error InvalidSignature();
     error InvalidPublicKey();
@@ -256,16 +265,21 @@ library PQCryptoLib {
         (bool success, bytes memory result) = PRECOMPILE_ADDRESS.staticcall(input);
         if (!success) revert PQCryptoCallFailed();
 
-        // Result contains ciphertext + shared secret (32 bytes)
-        uint256 ctLen = result.length - 32;
+        // Result is [ctLen(2)][ciphertext][ssLen(2)][sharedSecret]
+        if (result.length < 4) revert PQCryptoCallFailed();
+        uint256 ctLen = (uint256(uint8(result[0])) << 8) | uint256(uint8(result[1]));
+        if (result.length < 4 + ctLen) revert PQCryptoCallFailed();
+        uint256 ssLen = (uint256(uint8(result[2 + ctLen])) << 8) | uint256(uint8(result[3 + ctLen]));
+        if (result.length != 4 + ctLen + ssLen) revert PQCryptoCallFailed();
+
         ciphertext = new bytes(ctLen);
-        sharedSecret = new bytes(32);
+        sharedSecret = new bytes(ssLen);
 
         for (uint256 i = 0; i < ctLen; i++) {
-            ciphertext[i] = result[i];
+            ciphertext[i] = result[2 + i];
         }
-        for (uint256 i = 0; i < 32; i++) {
-            sharedSecret[i] = result[ctLen + i];
+        for (uint256 i = 0; i < ssLen; i++) {
+            sharedSecret[i] = result[4 + ctLen + i];
         }
     }
 
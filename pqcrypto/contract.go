@@ -5,6 +5,7 @@ package pqcrypto
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/luxfi/crypto/slhdsa"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/crypto/pqsizes"
 )
 
 // Function selectors (first 4 bytes of input)
@@ -21,6 +23,20 @@ const (
 	MLKEMEncapsulateSelector = "encp" // "encp_mlkem"
 	MLKEMDecapsulateSelector = "decp" // "decp_mlkem"
 	SLHDSAVerifySelector     = "slhs" // "slhs_verify"
+
+	// Domain-separated verification: same input as the plain selectors, but
+	// the signature is over contract.DomainSeparatedMessage(chainID,
+	// precompile, message), binding it to this chain and precompile
+	MLDSAVerifyDomainSelector  = "mldd" // "mldd_verify"
+	SLHDSAVerifyDomainSelector = "slhd" // "slhd_verify"
+
+	// PQ sealed-box encryption: ML-KEM encapsulation keys AES-256-GCM
+	PQSealSelector = "seal" // "seal_pqseal"
+	PQOpenSelector = "open" // "open_pqseal"
+
+	// A combined ML-KEM + NTRU KEM is not offered: no NTRU implementation
+	// is available to this module yet (the NTRU registry slots, LP-2211 and
+	// LP-2311, are reserved). ML-KEM is the only KEM here until one lands.
 )
 
 // ML-DSA mode bytes
@@ -37,6 +53,10 @@ const (
 	MLKEMMode1024 uint8 = 0x02
 )
 
+// MLKEMLegacyOutputFlag may be OR'd into the encapsulation mode byte to get
+// the original unframed [ciphertext][shared_secret] output.
+const MLKEMLegacyOutputFlag uint8 = 0x80
+
 // SLH-DSA mode bytes (mapped from slhdsa package)
 const (
 	SLHDSAModeSHA2_128s  uint8 = 0x00
@@ -105,6 +125,11 @@ func (p *pqCryptoPrecompile) Address() common.Address {
 	return ContractAddress
 }
 
+// Name returns the precompile name
+func (p *pqCryptoPrecompile) Name() string {
+	return "PQ_CRYPTO"
+}
+
 // RequiredGas calculates the gas required for the given input
 func (p *pqCryptoPrecompile) RequiredGas(input []byte) uint64 {
 	if len(input) < 4 {
@@ -116,14 +141,18 @@ func (p *pqCryptoPrecompile) RequiredGas(input []byte) uint64 {
 	data := input[4:]
 
 	switch selector {
-	case MLDSAVerifySelector:
+	case MLDSAVerifySelector, MLDSAVerifyDomainSelector:
 		return p.mldsaRequiredGas(data)
 	case MLKEMEncapsulateSelector:
 		return p.mlkemEncapsulateRequiredGas(data)
 	case MLKEMDecapsulateSelector:
 		return p.mlkemDecapsulateRequiredGas(data)
-	case SLHDSAVerifySelector:
+	case SLHDSAVerifySelector, SLHDSAVerifyDomainSelector:
 		return p.slhdsaRequiredGas(data)
+	case PQSealSelector:
+		return p.pqsealRequiredGas(data, p.mlkemEncapsulateRequiredGas(data))
+	case PQOpenSelector:
+		return p.pqsealRequiredGas(data, p.mlkemDecapsulateRequiredGas(data))
 	default:
 		return 0
 	}
@@ -154,7 +183,7 @@ func (p *pqCryptoPrecompile) mlkemEncapsulateRequiredGas(input []byte) uint64 {
 		return MLKEMDefaultGas
 	}
 
-	mode := input[0]
+	mode := input[0] &^ MLKEMLegacyOutputFlag
 	switch mode {
 	case MLKEMMode512:
 		return MLKEM512EncapsulateGas
@@ -213,6 +242,12 @@ func (p *pqCryptoPrecompile) slhdsaRequiredGas(input []byte) uint64 {
 
 // Run executes the precompile with the given input
 func (p *pqCryptoPrecompile) Run(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if tracer := contract.TracerFrom(accessibleState); tracer != nil {
+		tracer.OnEnter(addr, contract.Selector(input), len(input))
+		defer func() { tracer.OnExit(suppliedGas-remainingGas, err) }()
+	}
+	defer func() { err = contract.WrapRunError(p.Name(), addr, err) }()
+
 	if len(input) < 4 {
 		return nil, suppliedGas, errInvalidInput
 	}
@@ -230,7 +265,14 @@ func (p *pqCryptoPrecompile) Run(accessibleState contract.AccessibleState, calle
 
 	switch selector {
 	case MLDSAVerifySelector:
-		ret, err = p.mldsaVerify(data)
+		ret, err = p.mldsaVerify(data, nil, contract.MessageSizeLimit(accessibleState))
+		return ret, remainingGas, err
+	case MLDSAVerifyDomainSelector:
+		domain, err := domainPrefix(accessibleState, addr)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		ret, err = p.mldsaVerify(data, domain, contract.MessageSizeLimit(accessibleState))
 		return ret, remainingGas, err
 	case MLKEMEncapsulateSelector:
 		ret, err = p.mlkemEncapsulate(data)
@@ -239,16 +281,40 @@ func (p *pqCryptoPrecompile) Run(accessibleState contract.AccessibleState, calle
 		ret, err = p.mlkemDecapsulate(data)
 		return ret, remainingGas, err
 	case SLHDSAVerifySelector:
-		ret, err = p.slhdsaVerify(data)
+		ret, err = p.slhdsaVerify(data, nil, contract.MessageSizeLimit(accessibleState))
+		return ret, remainingGas, err
+	case SLHDSAVerifyDomainSelector:
+		domain, err := domainPrefix(accessibleState, addr)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		ret, err = p.slhdsaVerify(data, domain, contract.MessageSizeLimit(accessibleState))
+		return ret, remainingGas, err
+	case PQSealSelector:
+		ret, err = p.pqseal(data)
+		return ret, remainingGas, err
+	case PQOpenSelector:
+		ret, err = p.pqopen(data)
 		return ret, remainingGas, err
 	default:
 		return nil, remainingGas, fmt.Errorf("unknown function selector: %x", selector)
 	}
 }
 
-// mldsaVerify verifies an ML-DSA signature
+// domainPrefix returns the domain separator the domain selectors prepend to
+// the message
+func domainPrefix(accessibleState contract.AccessibleState, addr common.Address) ([]byte, error) {
+	chainID, err := contract.ChainID(accessibleState)
+	if err != nil {
+		return nil, err
+	}
+	return contract.DomainSeparatedMessage(chainID, addr, nil), nil
+}
+
+// mldsaVerify verifies an ML-DSA signature over domain || msg. Messages over
+// maxMessageSize are rejected.
 // Input format: [mode(1)] [pubkey_len(2)] [pubkey] [msg_len(2)] [msg] [sig]
-func (p *pqCryptoPrecompile) mldsaVerify(input []byte) ([]byte, error) {
+func (p *pqCryptoPrecompile) mldsaVerify(input, domain []byte, maxMessageSize uint64) ([]byte, error) {
 	if len(input) < 6 {
 		return nil, errInvalidInput
 	}
@@ -256,13 +322,14 @@ func (p *pqCryptoPrecompile) mldsaVerify(input []byte) ([]byte, error) {
 	// Parse mode
 	modeByte := input[0]
 	var mode mldsa.Mode
+	var sizeMode pqsizes.Mode
 	switch modeByte {
 	case MLDSAMode44:
-		mode = mldsa.MLDSA44
+		mode, sizeMode = mldsa.MLDSA44, pqsizes.MLDSA44
 	case MLDSAMode65:
-		mode = mldsa.MLDSA65
+		mode, sizeMode = mldsa.MLDSA65, pqsizes.MLDSA65
 	case MLDSAMode87:
-		mode = mldsa.MLDSA87
+		mode, sizeMode = mldsa.MLDSA87, pqsizes.MLDSA87
 	default:
 		return nil, fmt.Errorf("%w: ML-DSA mode 0x%02x", errInvalidMode, modeByte)
 	}
@@ -275,13 +342,24 @@ func (p *pqCryptoPrecompile) mldsaVerify(input []byte) ([]byte, error) {
 
 	pubKeyBytes := input[3 : 3+pubKeyLen]
 	msgLen := int(input[3+pubKeyLen])<<8 | int(input[3+pubKeyLen+1])
+	if err := contract.CheckMessageSize(uint64(msgLen), maxMessageSize); err != nil {
+		return nil, err
+	}
 
 	if len(input) < 3+pubKeyLen+2+msgLen {
 		return nil, errInvalidInput
 	}
 
 	message := input[3+pubKeyLen+2 : 3+pubKeyLen+2+msgLen]
-	signature := input[3+pubKeyLen+2+msgLen:]
+
+	// Reject sizes that don't match the declared mode before touching the library
+	if err := pqsizes.ValidateSizes(pqsizes.MLDSA, sizeMode, pqsizes.PublicKey(pubKeyLen)); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInput, err)
+	}
+	signature, err := rawSignature(input[3+pubKeyLen+2+msgLen:], pqsizes.MLDSA, sizeMode)
+	if err != nil {
+		return nil, err
+	}
 
 	// Reconstruct public key
 	pubKey, err := mldsa.PublicKeyFromBytes(pubKeyBytes, mode)
@@ -289,6 +367,10 @@ func (p *pqCryptoPrecompile) mldsaVerify(input []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	if domain != nil {
+		message = append(domain, message...)
+	}
+
 	// Verify signature
 	valid := pubKey.Verify(message, signature, nil)
 	if valid {
@@ -297,36 +379,58 @@ func (p *pqCryptoPrecompile) mldsaVerify(input []byte) ([]byte, error) {
 	return []byte{0}, nil
 }
 
-// mlkemEncapsulate performs ML-KEM encapsulation
-// Input format: [mode(1)] [pubkey]
-// Output: [ciphertext] [shared_secret]
-func (p *pqCryptoPrecompile) mlkemEncapsulate(input []byte) ([]byte, error) {
-	if len(input) < 2 {
-		return nil, errInvalidInput
+// rawSignature returns the signature occupying the rest of a verify input.
+// Signatures are raw bytes of exactly the mode's size; anything after them
+// is a framing error, reported apart from a short signature so callers can
+// tell a length bug from a bad signature.
+func rawSignature(rest []byte, scheme pqsizes.Scheme, mode pqsizes.Mode) ([]byte, error) {
+	sizes, err := pqsizes.Lookup(scheme, mode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInput, err)
 	}
+	if expected := sizes.Signature; len(rest) > expected {
+		return nil, fmt.Errorf("%w: %d trailing bytes after %d-byte signature", errInvalidInput, len(rest)-expected, expected)
+	}
+	if err := pqsizes.ValidateSizes(scheme, mode, pqsizes.Signature(len(rest))); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInput, err)
+	}
+	return rest, nil
+}
 
-	// Parse mode
-	modeByte := input[0]
-	var mode mlkem.Mode
-	var expectedPubKeySize int
-
+// mlkemModes maps an ML-KEM mode byte to the library mode and its size table entry
+func mlkemModes(modeByte uint8) (mlkem.Mode, pqsizes.Mode, error) {
 	switch modeByte {
 	case MLKEMMode512:
-		mode = mlkem.MLKEM512
-		expectedPubKeySize = mlkem.MLKEM512PublicKeySize
+		return mlkem.MLKEM512, pqsizes.MLKEM512, nil
 	case MLKEMMode768:
-		mode = mlkem.MLKEM768
-		expectedPubKeySize = mlkem.MLKEM768PublicKeySize
+		return mlkem.MLKEM768, pqsizes.MLKEM768, nil
 	case MLKEMMode1024:
-		mode = mlkem.MLKEM1024
-		expectedPubKeySize = mlkem.MLKEM1024PublicKeySize
+		return mlkem.MLKEM1024, pqsizes.MLKEM1024, nil
 	default:
-		return nil, fmt.Errorf("%w: ML-KEM mode 0x%02x", errInvalidMode, modeByte)
+		return 0, "", fmt.Errorf("%w: ML-KEM mode 0x%02x", errInvalidMode, modeByte)
+	}
+}
+
+// mlkemEncapsulate performs ML-KEM encapsulation
+// Input format: [mode(1)] [pubkey]
+// Output: [ct_len(2)] [ciphertext] [ss_len(2)] [shared_secret]
+// Output with MLKEMLegacyOutputFlag set in mode: [ciphertext] [shared_secret]
+func (p *pqCryptoPrecompile) mlkemEncapsulate(input []byte) ([]byte, error) {
+	if len(input) < 2 {
+		return nil, errInvalidInput
+	}
+
+	// Parse mode and output format
+	legacyOutput := input[0]&MLKEMLegacyOutputFlag != 0
+	modeByte := input[0] &^ MLKEMLegacyOutputFlag
+	mode, sizeMode, err := mlkemModes(modeByte)
+	if err != nil {
+		return nil, err
 	}
 
 	pubKeyBytes := input[1:]
-	if len(pubKeyBytes) != expectedPubKeySize {
-		return nil, fmt.Errorf("%w: expected pubkey size %d, got %d", errInvalidInput, expectedPubKeySize, len(pubKeyBytes))
+	if err := pqsizes.ValidateSizes(pqsizes.MLKEM, sizeMode, pqsizes.PublicKey(len(pubKeyBytes))); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInput, err)
 	}
 
 	// Reconstruct public key
@@ -341,8 +445,16 @@ func (p *pqCryptoPrecompile) mlkemEncapsulate(input []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	// Return ciphertext + shared secret
-	output := append(ciphertext, sharedSecret...)
+	if legacyOutput {
+		return append(ciphertext, sharedSecret...), nil
+	}
+
+	// Length-prefix both parts so callers can split without knowing the mode's sizes
+	output := make([]byte, 0, 2+len(ciphertext)+2+len(sharedSecret))
+	output = binary.BigEndian.AppendUint16(output, uint16(len(ciphertext)))
+	output = append(output, ciphertext...)
+	output = binary.BigEndian.AppendUint16(output, uint16(len(sharedSecret)))
+	output = append(output, sharedSecret...)
 	return output, nil
 }
 
@@ -356,21 +468,9 @@ func (p *pqCryptoPrecompile) mlkemDecapsulate(input []byte) ([]byte, error) {
 
 	// Parse mode
 	modeByte := input[0]
-	var mode mlkem.Mode
-	var expectedCiphertextSize int
-
-	switch modeByte {
-	case MLKEMMode512:
-		mode = mlkem.MLKEM512
-		expectedCiphertextSize = mlkem.MLKEM512CiphertextSize
-	case MLKEMMode768:
-		mode = mlkem.MLKEM768
-		expectedCiphertextSize = mlkem.MLKEM768CiphertextSize
-	case MLKEMMode1024:
-		mode = mlkem.MLKEM1024
-		expectedCiphertextSize = mlkem.MLKEM1024CiphertextSize
-	default:
-		return nil, fmt.Errorf("%w: ML-KEM mode 0x%02x", errInvalidMode, modeByte)
+	mode, sizeMode, err := mlkemModes(modeByte)
+	if err != nil {
+		return nil, err
 	}
 
 	privKeyLen := int(input[1])<<8 | int(input[2])
@@ -382,8 +482,9 @@ func (p *pqCryptoPrecompile) mlkemDecapsulate(input []byte) ([]byte, error) {
 	privKeyBytes := input[3 : 3+privKeyLen]
 	ciphertext := input[3+privKeyLen:]
 
-	if len(ciphertext) != expectedCiphertextSize {
-		return nil, fmt.Errorf("%w: expected ciphertext size %d, got %d", errInvalidInput, expectedCiphertextSize, len(ciphertext))
+	if err := pqsizes.ValidateSizes(pqsizes.MLKEM, sizeMode,
+		pqsizes.PrivateKey(privKeyLen), pqsizes.Ciphertext(len(ciphertext))); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInput, err)
 	}
 
 	// Reconstruct private key
@@ -401,9 +502,10 @@ func (p *pqCryptoPrecompile) mlkemDecapsulate(input []byte) ([]byte, error) {
 	return sharedSecret, nil
 }
 
-// slhdsaVerify verifies an SLH-DSA signature
+// slhdsaVerify verifies an SLH-DSA signature over domain || msg. Messages over
+// maxMessageSize are rejected.
 // Input format: [mode(1)] [pubkey_len(2)] [pubkey] [msg_len(2)] [msg] [sig]
-func (p *pqCryptoPrecompile) slhdsaVerify(input []byte) ([]byte, error) {
+func (p *pqCryptoPrecompile) slhdsaVerify(input, domain []byte, maxMessageSize uint64) ([]byte, error) {
 	if len(input) < 6 {
 		return nil, errInvalidInput
 	}
@@ -411,32 +513,33 @@ func (p *pqCryptoPrecompile) slhdsaVerify(input []byte) ([]byte, error) {
 	// Parse mode
 	modeByte := input[0]
 	var mode slhdsa.Mode
+	var sizeMode pqsizes.Mode
 
 	switch modeByte {
 	case SLHDSAModeSHA2_128s:
-		mode = slhdsa.SHA2_128s
+		mode, sizeMode = slhdsa.SHA2_128s, pqsizes.SLHDSASHA2_128s
 	case SLHDSAModeSHA2_128f:
-		mode = slhdsa.SHA2_128f
+		mode, sizeMode = slhdsa.SHA2_128f, pqsizes.SLHDSASHA2_128f
 	case SLHDSAModeSHA2_192s:
-		mode = slhdsa.SHA2_192s
+		mode, sizeMode = slhdsa.SHA2_192s, pqsizes.SLHDSASHA2_192s
 	case SLHDSAModeSHA2_192f:
-		mode = slhdsa.SHA2_192f
+		mode, sizeMode = slhdsa.SHA2_192f, pqsizes.SLHDSASHA2_192f
 	case SLHDSAModeSHA2_256s:
-		mode = slhdsa.SHA2_256s
+		mode, sizeMode = slhdsa.SHA2_256s, pqsizes.SLHDSASHA2_256s
 	case SLHDSAModeSHA2_256f:
-		mode = slhdsa.SHA2_256f
+		mode, sizeMode = slhdsa.SHA2_256f, pqsizes.SLHDSASHA2_256f
 	case SLHDSAModeSHAKE_128s:
-		mode = slhdsa.SHAKE_128s
+		mode, sizeMode = slhdsa.SHAKE_128s, pqsizes.SLHDSASHAKE_128s
 	case SLHDSAModeSHAKE_128f:
-		mode = slhdsa.SHAKE_128f
+		mode, sizeMode = slhdsa.SHAKE_128f, pqsizes.SLHDSASHAKE_128f
 	case SLHDSAModeSHAKE_192s:
-		mode = slhdsa.SHAKE_192s
+		mode, sizeMode = slhdsa.SHAKE_192s, pqsizes.SLHDSASHAKE_192s
 	case SLHDSAModeSHAKE_192f:
-		mode = slhdsa.SHAKE_192f
+		mode, sizeMode = slhdsa.SHAKE_192f, pqsizes.SLHDSASHAKE_192f
 	case SLHDSAModeSHAKE_256s:
-		mode = slhdsa.SHAKE_256s
+		mode, sizeMode = slhdsa.SHAKE_256s, pqsizes.SLHDSASHAKE_256s
 	case SLHDSAModeSHAKE_256f:
-		mode = slhdsa.SHAKE_256f
+		mode, sizeMode = slhdsa.SHAKE_256f, pqsizes.SLHDSASHAKE_256f
 	default:
 		return nil, fmt.Errorf("%w: SLH-DSA mode 0x%02x", errInvalidMode, modeByte)
 	}
@@ -449,13 +552,24 @@ func (p *pqCryptoPrecompile) slhdsaVerify(input []byte) ([]byte, error) {
 
 	pubKeyBytes := input[3 : 3+pubKeyLen]
 	msgLen := int(input[3+pubKeyLen])<<8 | int(input[3+pubKeyLen+1])
+	if err := contract.CheckMessageSize(uint64(msgLen), maxMessageSize); err != nil {
+		return nil, err
+	}
 
 	if len(input) < 3+pubKeyLen+2+msgLen {
 		return nil, errInvalidInput
 	}
 
 	message := input[3+pubKeyLen+2 : 3+pubKeyLen+2+msgLen]
-	signature := input[3+pubKeyLen+2+msgLen:]
+
+	// Reject sizes that don't match the declared mode before touching the library
+	if err := pqsizes.ValidateSizes(pqsizes.SLHDSA, sizeMode, pqsizes.PublicKey(pubKeyLen)); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInput, err)
+	}
+	signature, err := rawSignature(input[3+pubKeyLen+2+msgLen:], pqsizes.SLHDSA, sizeMode)
+	if err != nil {
+		return nil, err
+	}
 
 	// Reconstruct public key
 	pubKey, err := slhdsa.PublicKeyFromBytes(pubKeyBytes, mode)
@@ -463,6 +577,10 @@ func (p *pqCryptoPrecompile) slhdsaVerify(input []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	if domain != nil {
+		message = append(domain, message...)
+	}
+
 	// Verify signature
 	valid := pubKey.Verify(message, signature, nil)
 	if valid {
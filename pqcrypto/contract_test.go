@@ -4,13 +4,18 @@
 package pqcrypto
 
 import (
+	"context"
 	"crypto/rand"
+	"encoding/binary"
+	"math/big"
 	"testing"
 
 	"github.com/luxfi/crypto/mldsa"
 	"github.com/luxfi/crypto/mlkem"
 	"github.com/luxfi/crypto/slhdsa"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/precompileconfig"
 	"github.com/stretchr/testify/require"
 )
 
@@ -124,7 +129,7 @@ func TestMLKEMEncapsulateDecapsulate(t *testing.T) {
 	// Test encapsulation
 	pubBytes := pub.Bytes()
 	encapInput := []byte(MLKEMEncapsulateSelector[:4])
-	encapInput = append(encapInput, MLKEMMode512)
+	encapInput = append(encapInput, MLKEMMode512|MLKEMLegacyOutputFlag)
 	encapInput = append(encapInput, pubBytes...)
 
 	gas := precompile.RequiredGas(encapInput)
@@ -134,7 +139,7 @@ func TestMLKEMEncapsulateDecapsulate(t *testing.T) {
 	require.NoError(err)
 	require.NotEmpty(encapResult)
 
-	// Extract ciphertext (first part of result)
+	// Extract ciphertext (first part of the legacy unframed result)
 	// For MLKEM512, ciphertext size is defined by mlkem.MLKEM512CiphertextSize
 	ciphertext := encapResult[:mlkem.MLKEM512CiphertextSize]
 	sharedSecret1 := encapResult[mlkem.MLKEM512CiphertextSize:]
@@ -189,8 +194,24 @@ func TestMLKEM_AllModes(t *testing.T) {
 			encapResult, _, err := precompile.Run(nil, common.Address{}, ContractAddress, encapInput, gas, true)
 			require.NoError(err)
 
-			ciphertext := encapResult[:m.ciphertextSize]
-			sharedSecret1 := encapResult[m.ciphertextSize:]
+			// Split the framed output without using the mode's sizes
+			ctLen := int(binary.BigEndian.Uint16(encapResult[:2]))
+			require.Equal(m.ciphertextSize, ctLen)
+			ciphertext := encapResult[2 : 2+ctLen]
+			ssLen := int(binary.BigEndian.Uint16(encapResult[2+ctLen : 4+ctLen]))
+			require.Equal(mlkem.MLKEM768SharedKeySize, ssLen) // 32 bytes in every mode
+			sharedSecret1 := encapResult[4+ctLen:]
+			require.Len(sharedSecret1, ssLen)
+
+			// Legacy flag returns the unframed concatenation
+			legacyInput := []byte(MLKEMEncapsulateSelector[:4])
+			legacyInput = append(legacyInput, m.modeByte|MLKEMLegacyOutputFlag)
+			legacyInput = append(legacyInput, pubBytes...)
+			require.Equal(m.encapGas, precompile.RequiredGas(legacyInput))
+
+			legacyResult, _, err := precompile.Run(nil, common.Address{}, ContractAddress, legacyInput, gas, true)
+			require.NoError(err)
+			require.Len(legacyResult, m.ciphertextSize+mlkem.MLKEM768SharedKeySize)
 
 			// Decapsulate
 			privBytes := priv.Bytes()
@@ -289,6 +310,130 @@ func TestSLHDSA_AllModes(t *testing.T) {
 	}
 }
 
+// buildVerifyInput encodes [selector][mode][pubkey_len][pubkey][msg_len][msg][sig]
+func buildVerifyInput(selector string, modeByte uint8, pubKey, message, signature []byte) []byte {
+	input := []byte(selector[:4])
+	input = append(input, modeByte)
+	input = append(input, byte(len(pubKey)>>8), byte(len(pubKey)))
+	input = append(input, pubKey...)
+	input = append(input, byte(len(message)>>8), byte(len(message)))
+	input = append(input, message...)
+	return append(input, signature...)
+}
+
+func TestMLDSAVerify_WrongSizes(t *testing.T) {
+	modes := []struct {
+		name     string
+		mode     mldsa.Mode
+		modeByte uint8
+	}{
+		{"ML-DSA-44", mldsa.MLDSA44, MLDSAMode44},
+		{"ML-DSA-65", mldsa.MLDSA65, MLDSAMode65},
+		{"ML-DSA-87", mldsa.MLDSA87, MLDSAMode87},
+	}
+
+	message := []byte("wrong size test")
+	for i, m := range modes {
+		t.Run(m.name, func(t *testing.T) {
+			require := require.New(t)
+			precompile := PQCryptoPrecompile
+
+			// Key sized for a different mode
+			other := modes[(i+1)%len(modes)].mode
+			wrongKey := make([]byte, mldsa.GetPublicKeySize(other))
+			sig := make([]byte, mldsa.GetSignatureSize(m.mode))
+
+			input := buildVerifyInput(MLDSAVerifySelector, m.modeByte, wrongKey, message, sig)
+			_, _, err := precompile.Run(nil, common.Address{}, ContractAddress, input, precompile.RequiredGas(input), true)
+			require.ErrorIs(err, errInvalidInput)
+
+			// Correct key size, signature one byte short
+			key := make([]byte, mldsa.GetPublicKeySize(m.mode))
+			input = buildVerifyInput(MLDSAVerifySelector, m.modeByte, key, message, sig[:len(sig)-1])
+			_, _, err = precompile.Run(nil, common.Address{}, ContractAddress, input, precompile.RequiredGas(input), true)
+			require.ErrorIs(err, errInvalidInput)
+		})
+	}
+}
+
+func TestSLHDSAVerify_WrongSizes(t *testing.T) {
+	modes := []struct {
+		name     string
+		mode     slhdsa.Mode
+		modeByte uint8
+	}{
+		{"SHA2-128s", slhdsa.SHA2_128s, SLHDSAModeSHA2_128s},
+		{"SHA2-128f", slhdsa.SHA2_128f, SLHDSAModeSHA2_128f},
+		{"SHA2-192s", slhdsa.SHA2_192s, SLHDSAModeSHA2_192s},
+		{"SHA2-192f", slhdsa.SHA2_192f, SLHDSAModeSHA2_192f},
+		{"SHA2-256s", slhdsa.SHA2_256s, SLHDSAModeSHA2_256s},
+		{"SHA2-256f", slhdsa.SHA2_256f, SLHDSAModeSHA2_256f},
+		{"SHAKE-128s", slhdsa.SHAKE_128s, SLHDSAModeSHAKE_128s},
+		{"SHAKE-128f", slhdsa.SHAKE_128f, SLHDSAModeSHAKE_128f},
+		{"SHAKE-192s", slhdsa.SHAKE_192s, SLHDSAModeSHAKE_192s},
+		{"SHAKE-192f", slhdsa.SHAKE_192f, SLHDSAModeSHAKE_192f},
+		{"SHAKE-256s", slhdsa.SHAKE_256s, SLHDSAModeSHAKE_256s},
+		{"SHAKE-256f", slhdsa.SHAKE_256f, SLHDSAModeSHAKE_256f},
+	}
+
+	message := []byte("wrong size test")
+	for _, m := range modes {
+		t.Run(m.name, func(t *testing.T) {
+			require := require.New(t)
+			precompile := PQCryptoPrecompile
+
+			keySize := slhdsa.GetPublicKeySize(m.mode)
+			sig := make([]byte, slhdsa.GetSignatureSize(m.mode))
+
+			// Key 16 bytes off from the mode's size (a different security level)
+			wrongKey := make([]byte, keySize+16)
+			input := buildVerifyInput(SLHDSAVerifySelector, m.modeByte, wrongKey, message, sig)
+			_, _, err := precompile.Run(nil, common.Address{}, ContractAddress, input, precompile.RequiredGas(input), true)
+			require.ErrorIs(err, errInvalidInput)
+
+			// Correct key size, signature with a trailing byte
+			key := make([]byte, keySize)
+			input = buildVerifyInput(SLHDSAVerifySelector, m.modeByte, key, message, append(sig, 0x00))
+			_, _, err = precompile.Run(nil, common.Address{}, ContractAddress, input, precompile.RequiredGas(input), true)
+			require.ErrorIs(err, errInvalidInput)
+		})
+	}
+}
+
+func TestVerify_MaxMessageSize(t *testing.T) {
+	cases := []struct {
+		name     string
+		selector string
+		modeByte uint8
+		keySize  int
+		sigSize  int
+	}{
+		{"ML-DSA", MLDSAVerifySelector, MLDSAMode44, mldsa.GetPublicKeySize(mldsa.MLDSA44), mldsa.GetSignatureSize(mldsa.MLDSA44)},
+		{"SLH-DSA", SLHDSAVerifySelector, SLHDSAModeSHAKE_128f, slhdsa.GetPublicKeySize(slhdsa.SHAKE_128f), slhdsa.GetSignatureSize(slhdsa.SHAKE_128f)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			precompile := PQCryptoPrecompile
+			key, sig := make([]byte, c.keySize), make([]byte, c.sigSize)
+			run := func(state contract.AccessibleState, msgLen int) error {
+				input := buildVerifyInput(c.selector, c.modeByte, key, make([]byte, msgLen), sig)
+				_, _, err := precompile.Run(state, common.Address{}, ContractAddress, input, precompile.RequiredGas(input), true)
+				return err
+			}
+
+			require.NoError(run(nil, contract.MaxMessageSize))
+			require.ErrorIs(run(nil, contract.MaxMessageSize+1), contract.ErrMessageTooLarge)
+
+			// The chain config sets the limit
+			limited := &limitState{limit: 64}
+			require.NoError(run(limited, 64))
+			require.ErrorIs(run(limited, 65), contract.ErrMessageTooLarge)
+		})
+	}
+}
+
 func TestGasCalculation(t *testing.T) {
 	require := require.New(t)
 	precompile := PQCryptoPrecompile
@@ -384,3 +529,231 @@ func BenchmarkPQPrecompile(b *testing.B) {
 		}
 	})
 }
+
+func TestVerify_TrailingBytes(t *testing.T) {
+	message := []byte("trailing bytes test")
+
+	mldsaPriv, err := mldsa.GenerateKey(rand.Reader, mldsa.MLDSA44)
+	require.NoError(t, err)
+	mldsaSig, err := mldsaPriv.Sign(rand.Reader, message, nil)
+	require.NoError(t, err)
+
+	slhdsaPriv, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHAKE_128f)
+	require.NoError(t, err)
+	slhdsaSig, err := slhdsaPriv.Sign(rand.Reader, message, nil)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name      string
+		selector  string
+		modeByte  uint8
+		pubKey    []byte
+		signature []byte
+	}{
+		{"ML-DSA-44", MLDSAVerifySelector, MLDSAMode44, mldsaPriv.PublicKey.Bytes(), mldsaSig},
+		{"SLH-DSA-SHAKE-128f", SLHDSAVerifySelector, SLHDSAModeSHAKE_128f, slhdsaPriv.PublicKey.Bytes(), slhdsaSig},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require := require.New(t)
+			precompile := PQCryptoPrecompile
+
+			run := func(signature []byte) ([]byte, error) {
+				input := buildVerifyInput(c.selector, c.modeByte, c.pubKey, message, signature)
+				result, _, err := precompile.Run(nil, common.Address{}, ContractAddress, input, precompile.RequiredGas(input), true)
+				return result, err
+			}
+
+			result, err := run(c.signature)
+			require.NoError(err)
+			require.Equal([]byte{1}, result)
+
+			// A valid signature followed by garbage is a framing error
+			for _, extra := range [][]byte{{0x00}, {0xde, 0xad, 0xbe, 0xef}} {
+				_, err = run(append(append([]byte{}, c.signature...), extra...))
+				require.ErrorIs(err, errInvalidInput)
+				require.ErrorContains(err, "trailing bytes")
+			}
+
+			// A correctly sized but wrong signature is reported as invalid
+			tampered := append([]byte{}, c.signature...)
+			tampered[len(tampered)/2] ^= 0xFF
+			result, err = run(tampered)
+			require.NoError(err)
+			require.Equal([]byte{0}, result)
+		})
+	}
+}
+
+// chainState is an AccessibleState that only exposes a chain id
+type chainState struct {
+	chainID *big.Int
+}
+
+func (s *chainState) GetStateDB() contract.StateDB                     { return nil }
+func (s *chainState) GetBlockContext() contract.BlockContext           { return nil }
+func (s *chainState) GetConsensusContext() context.Context             { return context.Background() }
+func (s *chainState) GetChainConfig() precompileconfig.ChainConfig     { return chainConfig{s.chainID} }
+func (s *chainState) GetPrecompileEnv() contract.PrecompileEnvironment { return nil }
+
+type chainConfig struct {
+	chainID *big.Int
+}
+
+func (c chainConfig) IsDurango(uint64) bool { return true }
+func (c chainConfig) GetChainID() *big.Int  { return c.chainID }
+
+// limitState is a chainState whose chain config sets the maximum message size
+type limitState struct {
+	chainState
+	limit uint64
+}
+
+func (s *limitState) GetChainConfig() precompileconfig.ChainConfig {
+	return limitConfig{chainConfig{s.chainID}, s.limit}
+}
+
+type limitConfig struct {
+	chainConfig
+	limit uint64
+}
+
+func (c limitConfig) MaxMessageSize(uint64) uint64 { return c.limit }
+
+func TestDomainSeparatedVerify(t *testing.T) {
+	r := require.New(t)
+	precompile := PQCryptoPrecompile
+	chainID := big.NewInt(96369)
+	message := []byte("domain separated message")
+
+	mldsaKey, err := mldsa.GenerateKey(rand.Reader, mldsa.MLDSA44)
+	r.NoError(err)
+	mldsaSig, err := mldsaKey.Sign(rand.Reader, contract.DomainSeparatedMessage(chainID, ContractAddress, message), nil)
+	r.NoError(err)
+
+	slhdsaKey, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHAKE_128f)
+	r.NoError(err)
+	slhdsaSig, err := slhdsaKey.Sign(rand.Reader, contract.DomainSeparatedMessage(chainID, ContractAddress, message), nil)
+	r.NoError(err)
+
+	tests := []struct {
+		name           string
+		raw, separated []byte
+	}{
+		{
+			"ML-DSA",
+			buildVerifyInput(MLDSAVerifySelector, MLDSAMode44, mldsaKey.PublicKey.Bytes(), message, mldsaSig),
+			buildVerifyInput(MLDSAVerifyDomainSelector, MLDSAMode44, mldsaKey.PublicKey.Bytes(), message, mldsaSig),
+		},
+		{
+			"SLH-DSA",
+			buildVerifyInput(SLHDSAVerifySelector, SLHDSAModeSHAKE_128f, slhdsaKey.PublicKey.Bytes(), message, slhdsaSig),
+			buildVerifyInput(SLHDSAVerifyDomainSelector, SLHDSAModeSHAKE_128f, slhdsaKey.PublicKey.Bytes(), message, slhdsaSig),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			gas := precompile.RequiredGas(tt.separated)
+			require.Equal(precompile.RequiredGas(tt.raw), gas)
+
+			result, _, err := precompile.Run(&chainState{chainID}, common.Address{}, ContractAddress, tt.separated, gas, true)
+			require.NoError(err)
+			require.Equal([]byte{1}, result)
+
+			// Another chain
+			result, _, err = precompile.Run(&chainState{big.NewInt(96368)}, common.Address{}, ContractAddress, tt.separated, gas, true)
+			require.NoError(err)
+			require.Equal([]byte{0}, result)
+
+			// Another precompile
+			other := common.HexToAddress("0x9004")
+			result, _, err = precompile.Run(&chainState{chainID}, common.Address{}, other, tt.separated, gas, true)
+			require.NoError(err)
+			require.Equal([]byte{0}, result)
+
+			// The raw selector does not accept a domain-separated signature
+			result, _, err = precompile.Run(&chainState{chainID}, common.Address{}, ContractAddress, tt.raw, gas, true)
+			require.NoError(err)
+			require.Equal([]byte{0}, result)
+
+			// No chain id to bind to
+			_, _, err = precompile.Run(nil, common.Address{}, ContractAddress, tt.separated, gas, true)
+			require.ErrorIs(err, contract.ErrChainIDUnavailable)
+		})
+	}
+}
+
+type traceEvent struct {
+	selector []byte
+	inputLen int
+	gasUsed  uint64
+	err      error
+}
+
+type recordingTracer struct {
+	events []traceEvent
+}
+
+func (r *recordingTracer) OnEnter(_ common.Address, selector []byte, inputLen int) {
+	r.events = append(r.events, traceEvent{selector: common.CopyBytes(selector), inputLen: inputLen})
+}
+
+func (r *recordingTracer) OnExit(gasUsed uint64, err error) {
+	last := &r.events[len(r.events)-1]
+	last.gasUsed, last.err = gasUsed, err
+}
+
+// tracingState is a chainState with a tracer configured
+type tracingState struct {
+	chainState
+	tracer contract.Tracer
+}
+
+func (s *tracingState) GetTracer() contract.Tracer { return s.tracer }
+
+func TestRunTracesFailedVerification(t *testing.T) {
+	require := require.New(t)
+	precompile := PQCryptoPrecompile
+	tracer := &recordingTracer{}
+	state := &tracingState{tracer: tracer}
+
+	// Signature one byte short of ML-DSA-44
+	key := make([]byte, mldsa.GetPublicKeySize(mldsa.MLDSA44))
+	sig := make([]byte, mldsa.GetSignatureSize(mldsa.MLDSA44)-1)
+	input := buildVerifyInput(MLDSAVerifySelector, MLDSAMode44, key, []byte("msg"), sig)
+	_, _, err := precompile.Run(state, common.Address{}, ContractAddress, input, 200_000, true)
+	require.ErrorIs(err, errInvalidInput)
+
+	// Not enough gas
+	_, _, err = precompile.Run(state, common.Address{}, ContractAddress, input, 10, true)
+	require.ErrorIs(err, contract.ErrOutOfGas)
+
+	require.Len(tracer.events, 2)
+	require.Equal([]byte(MLDSAVerifySelector), tracer.events[0].selector)
+	require.Equal(len(input), tracer.events[0].inputLen)
+	require.Equal(MLDSA44VerifyGas, tracer.events[0].gasUsed)
+	require.ErrorIs(tracer.events[0].err, errInvalidInput)
+	require.Equal(uint64(10), tracer.events[1].gasUsed)
+	require.ErrorIs(tracer.events[1].err, contract.ErrOutOfGas)
+}
+
+func TestRunErrorNamesPrecompile(t *testing.T) {
+	require := require.New(t)
+	precompile := PQCryptoPrecompile
+
+	_, _, err := precompile.Run(nil, common.Address{}, ContractAddress, []byte("nope"), 200_000, true)
+	require.ErrorContains(err, "unknown function selector")
+	require.ErrorContains(err, precompile.Name())
+	require.ErrorContains(err, ContractAddress.Hex())
+
+	// Wrapping keeps the cause matchable
+	_, _, err = precompile.Run(nil, common.Address{}, ContractAddress, []byte{0x01}, 200_000, true)
+	require.ErrorIs(err, errInvalidInput)
+	require.ErrorContains(err, ContractAddress.Hex())
+
+	// Successful calls are not wrapped
+	require.NoError(contract.WrapRunError(precompile.Name(), ContractAddress, nil))
+}
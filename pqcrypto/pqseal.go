@@ -0,0 +1,172 @@
+// Copyright (C) 2025, Lux Industries Inc All rights reserved.
+// Post-quantum sealed-box encryption (ML-KEM + AES-256-GCM)
+
+package pqcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"crypto/sha3"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/crypto/mlkem"
+	"github.com/luxfi/precompile/crypto/pqsizes"
+)
+
+// PQSeal sizes
+const (
+	PQSealSeedSize  = 32 // caller-supplied seed for the deterministic KEM coins
+	PQSealNonceSize = 12 // AES-GCM nonce
+	PQSealTagSize   = 16 // AES-GCM tag
+)
+
+// PQSealGasPerWord is charged per 32-byte word of input on top of the ML-KEM
+// encapsulation/decapsulation gas for the mode
+const PQSealGasPerWord uint64 = 3
+
+const (
+	// pqsealCoinsTag domain-separates the KEM coins and nonce derivation
+	pqsealCoinsTag = "LUX_PQSEAL_COINS_V1"
+	// pqsealKeyInfo is the HKDF info for the AEAD key
+	pqsealKeyInfo = "LUX_PQSEAL_KEY_V1"
+)
+
+var errPQSealAuth = errors.New("pqseal: message authentication failed")
+
+// pqsealRequiredGas returns the mode's KEM gas plus the per-word AEAD gas
+func (p *pqCryptoPrecompile) pqsealRequiredGas(input []byte, kemGas uint64) uint64 {
+	return kemGas + PQSealGasPerWord*uint64((len(input)+31)/32)
+}
+
+// pqsealAEAD derives the AES-256-GCM instance for one encapsulation. The key
+// is bound to the KEM ciphertext so a ciphertext cannot be re-paired with
+// another encapsulation of the same secret.
+func pqsealAEAD(sharedSecret, kemCiphertext []byte) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, sharedSecret, kemCiphertext, pqsealKeyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pqseal encrypts plaintext to an ML-KEM public key.
+// Input format: [mode(1)] [seed(32)] [pubkey] [plaintext]
+// Output: [mlkem_ciphertext] [nonce(12)] [aead_ciphertext]
+//
+// The KEM coins and nonce are derived from the seed, public key and
+// plaintext so every node computes the same output. Callers should use a
+// fresh seed per message; reusing one reveals when two plaintexts are equal.
+func (p *pqCryptoPrecompile) pqseal(input []byte) ([]byte, error) {
+	if len(input) < 1+PQSealSeedSize {
+		return nil, errInvalidInput
+	}
+	mode, sizeMode, err := mlkemModes(input[0])
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := pqsizes.Lookup(pqsizes.MLKEM, sizeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	seed := input[1 : 1+PQSealSeedSize]
+	pubKeySize := sizes.PublicKey
+	if len(input) < 1+PQSealSeedSize+pubKeySize {
+		return nil, fmt.Errorf("%w: expected pubkey size %d, got %d", errInvalidInput, pubKeySize, len(input)-1-PQSealSeedSize)
+	}
+	pubKeyBytes := input[1+PQSealSeedSize : 1+PQSealSeedSize+pubKeySize]
+	plaintext := input[1+PQSealSeedSize+pubKeySize:]
+
+	pubKey, err := mlkem.PublicKeyFromBytes(pubKeyBytes, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	// Deterministic coins: SHAKE256(tag || mode || seed || pubkey || plaintext)
+	// yields the encapsulation seed followed by the nonce
+	xof := sha3.NewSHAKE256()
+	xof.Write([]byte(pqsealCoinsTag))
+	xof.Write(input[:1])
+	xof.Write(seed)
+	xof.Write(pubKeyBytes)
+	xof.Write(plaintext)
+
+	kemCiphertext, sharedSecret, err := pubKey.Encapsulate(xof)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, PQSealNonceSize)
+	if _, err := xof.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	aead, err := pqsealAEAD(sharedSecret, kemCiphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]byte, 0, len(kemCiphertext)+PQSealNonceSize+len(plaintext)+PQSealTagSize)
+	output = append(output, kemCiphertext...)
+	output = append(output, nonce...)
+	return aead.Seal(output, nonce, plaintext, input[:1]), nil
+}
+
+// pqopen decrypts a pqseal output.
+// Input format: [mode(1)] [privkey_len(2)] [privkey] [mlkem_ciphertext] [nonce(12)] [aead_ciphertext]
+// Output: [plaintext]
+func (p *pqCryptoPrecompile) pqopen(input []byte) ([]byte, error) {
+	if len(input) < 3 {
+		return nil, errInvalidInput
+	}
+	mode, sizeMode, err := mlkemModes(input[0])
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := pqsizes.Lookup(pqsizes.MLKEM, sizeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	privKeyLen := int(input[1])<<8 | int(input[2])
+	if err := pqsizes.ValidateSizes(pqsizes.MLKEM, sizeMode, pqsizes.PrivateKey(privKeyLen)); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidInput, err)
+	}
+	ciphertextSize := sizes.Ciphertext
+	if len(input) < 3+privKeyLen+ciphertextSize+PQSealNonceSize+PQSealTagSize {
+		return nil, errInvalidInput
+	}
+
+	privKeyBytes := input[3 : 3+privKeyLen]
+	sealed := input[3+privKeyLen:]
+	kemCiphertext := sealed[:ciphertextSize]
+	nonce := sealed[ciphertextSize : ciphertextSize+PQSealNonceSize]
+	aeadCiphertext := sealed[ciphertextSize+PQSealNonceSize:]
+
+	privKey, err := mlkem.PrivateKeyFromBytes(privKeyBytes, mode)
+	if err != nil {
+		return nil, err
+	}
+	// ML-KEM decapsulation rejects implicitly: a tampered KEM ciphertext
+	// yields an unrelated secret and fails authentication below
+	sharedSecret, err := privKey.Decapsulate(kemCiphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := pqsealAEAD(sharedSecret, kemCiphertext)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, aeadCiphertext, input[:1])
+	if err != nil {
+		return nil, errPQSealAuth
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2025, Lux Industries Inc All rights reserved.
+// Post-quantum sealed-box encryption tests
+
+package pqcrypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/luxfi/crypto/mlkem"
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func buildSealInput(modeByte uint8, seed, pubKey, plaintext []byte) []byte {
+	input := []byte(PQSealSelector)
+	input = append(input, modeByte)
+	input = append(input, seed...)
+	input = append(input, pubKey...)
+	return append(input, plaintext...)
+}
+
+func buildOpenInput(modeByte uint8, privKey, sealed []byte) []byte {
+	input := []byte(PQOpenSelector)
+	input = append(input, modeByte)
+	input = append(input, byte(len(privKey)>>8), byte(len(privKey)))
+	input = append(input, privKey...)
+	return append(input, sealed...)
+}
+
+func runPQ(t *testing.T, input []byte) ([]byte, error) {
+	t.Helper()
+	gas := PQCryptoPrecompile.RequiredGas(input)
+	ret, remaining, err := PQCryptoPrecompile.Run(nil, common.Address{}, ContractAddress, input, gas, true)
+	if err == nil {
+		require.Zero(t, remaining)
+	}
+	return ret, err
+}
+
+func TestPQSeal_AllModes(t *testing.T) {
+	modes := []struct {
+		name     string
+		mode     mlkem.Mode
+		modeByte uint8
+		encapGas uint64
+		decapGas uint64
+	}{
+		{"ML-KEM-512", mlkem.MLKEM512, MLKEMMode512, MLKEM512EncapsulateGas, MLKEM512DecapsulateGas},
+		{"ML-KEM-768", mlkem.MLKEM768, MLKEMMode768, MLKEM768EncapsulateGas, MLKEM768DecapsulateGas},
+		{"ML-KEM-1024", mlkem.MLKEM1024, MLKEMMode1024, MLKEM1024EncapsulateGas, MLKEM1024DecapsulateGas},
+	}
+
+	for _, m := range modes {
+		t.Run(m.name, func(t *testing.T) {
+			require := require.New(t)
+
+			pub, priv, err := mlkem.GenerateKeyPair(rand.Reader, m.mode)
+			require.NoError(err)
+
+			seed := bytes.Repeat([]byte{0x5e}, PQSealSeedSize)
+			plaintext := []byte("post-quantum sealed message")
+			sealInput := buildSealInput(m.modeByte, seed, pub.Bytes(), plaintext)
+			require.Greater(PQCryptoPrecompile.RequiredGas(sealInput), m.encapGas)
+
+			sealed, err := runPQ(t, sealInput)
+			require.NoError(err)
+			require.Len(sealed, mlkem.GetCiphertextSize(m.mode)+PQSealNonceSize+len(plaintext)+PQSealTagSize)
+
+			// Every node must compute the same ciphertext
+			again, err := runPQ(t, sealInput)
+			require.NoError(err)
+			require.Equal(sealed, again)
+
+			// A different seed gives a different ciphertext
+			otherSeed := bytes.Repeat([]byte{0x01}, PQSealSeedSize)
+			other, err := runPQ(t, buildSealInput(m.modeByte, otherSeed, pub.Bytes(), plaintext))
+			require.NoError(err)
+			require.NotEqual(sealed, other)
+
+			openInput := buildOpenInput(m.modeByte, priv.Bytes(), sealed)
+			require.Greater(PQCryptoPrecompile.RequiredGas(openInput), m.decapGas)
+
+			opened, err := runPQ(t, openInput)
+			require.NoError(err)
+			require.Equal(plaintext, opened)
+
+			// Empty plaintext round-trips too
+			sealed, err = runPQ(t, buildSealInput(m.modeByte, seed, pub.Bytes(), nil))
+			require.NoError(err)
+			opened, err = runPQ(t, buildOpenInput(m.modeByte, priv.Bytes(), sealed))
+			require.NoError(err)
+			require.Empty(opened)
+		})
+	}
+}
+
+func TestPQSeal_AuthenticationFailure(t *testing.T) {
+	require := require.New(t)
+
+	pub, priv, err := mlkem.GenerateKeyPair(rand.Reader, mlkem.MLKEM768)
+	require.NoError(err)
+	seed := make([]byte, PQSealSeedSize)
+	sealed, err := runPQ(t, buildSealInput(MLKEMMode768, seed, pub.Bytes(), []byte("secret")))
+	require.NoError(err)
+
+	ctSize := mlkem.MLKEM768CiphertextSize
+	for name, offset := range map[string]int{
+		"kem ciphertext":  0,
+		"nonce":           ctSize,
+		"aead ciphertext": ctSize + PQSealNonceSize,
+		"tag":             len(sealed) - 1,
+	} {
+		tampered := common.CopyBytes(sealed)
+		tampered[offset] ^= 0x01
+		_, err := runPQ(t, buildOpenInput(MLKEMMode768, priv.Bytes(), tampered))
+		require.ErrorIs(err, errPQSealAuth, name)
+	}
+
+	// Wrong recipient key
+	_, otherPriv, err := mlkem.GenerateKeyPair(rand.Reader, mlkem.MLKEM768)
+	require.NoError(err)
+	_, err = runPQ(t, buildOpenInput(MLKEMMode768, otherPriv.Bytes(), sealed))
+	require.ErrorIs(err, errPQSealAuth)
+
+	// Truncated boxes and the legacy output flag are rejected before decryption
+	_, err = runPQ(t, buildOpenInput(MLKEMMode768, priv.Bytes(), sealed[:ctSize+PQSealNonceSize]))
+	require.ErrorIs(err, errInvalidInput)
+	_, err = runPQ(t, buildSealInput(MLKEMMode768|MLKEMLegacyOutputFlag, seed, pub.Bytes(), nil))
+	require.ErrorIs(err, errInvalidMode)
+}
@@ -70,3 +70,23 @@ type ChainConfig interface {
 	// IsDurango returns true if the time is after Durango.
 	IsDurango(time uint64) bool
 }
+
+// ActivePrecompilesConfig is an optional extension of ChainConfig for chain
+// configs that know which stateful precompiles their upgrade schedule has
+// activated. Precompiles that call into or report on other precompiles use it
+// so they follow the same activation rules as the EVM itself.
+type ActivePrecompilesConfig interface {
+	// IsPrecompileEnabled returns true if the precompile at [address] is
+	// active in a block with timestamp [time].
+	IsPrecompileEnabled(address common.Address, time uint64) bool
+}
+
+// MessageSizeConfig is an optional extension of ChainConfig for chain configs
+// that override the maximum message size signature-verifying precompiles
+// accept. The limit decides whether a call fails, so it must come from the
+// chain's configuration and be the same on every node.
+type MessageSizeConfig interface {
+	// MaxMessageSize returns the maximum message size in bytes in a block
+	// with timestamp [time]. Zero selects the default.
+	MaxMessageSize(time uint64) uint64
+}
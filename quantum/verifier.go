@@ -16,6 +16,7 @@ import (
 	"github.com/luxfi/crypto/mlkem"
 	"github.com/luxfi/crypto/slhdsa"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/crypto/ecverify"
 	ringtailConfig "github.com/luxfi/threshold/protocols/ringtail/config"
 )
 
@@ -565,29 +566,11 @@ func (qv *QuantumVerifier) verifyBLSSignature(
 	message []byte,
 	signature []byte,
 ) bool {
-	// Validate inputs
-	if len(publicKey) != BLSPublicKeySize || len(signature) != BLSSignatureSize {
-		return false
-	}
 	if len(message) == 0 {
 		return false
 	}
-
-	// Parse public key using the real BLS implementation (CIRCL BLS12-381)
-	pk, err := bls.PublicKeyFromCompressedBytes(publicKey)
-	if err != nil {
-		return false
-	}
-
-	// Parse signature
-	sig, err := bls.SignatureFromBytes(signature)
-	if err != nil {
-		return false
-	}
-
-	// Verify signature using the real CIRCL BLS12-381 implementation
-	// This performs the pairing check: e(H(m), pk) == e(sig, g2)
-	return bls.Verify(pk, sig, message)
+	valid, err := ecverify.VerifyBLS12381(publicKey, message, signature)
+	return err == nil && valid
 }
 
 func (qv *QuantumVerifier) verifyECDSASignature(
@@ -638,69 +621,12 @@ func (qv *QuantumVerifier) verifySchnorrSignature(
 	message []byte,
 	signature []byte,
 ) bool {
-	// Validate inputs - BIP-340 Schnorr signature verification
-	// Public key is 32 bytes (x-coordinate only)
-	// Signature is 64 bytes (r || s)
-	if len(signature) != 64 || len(publicKey) != 32 {
-		return false
-	}
+	// BIP-340: 32-byte x-only public key, 64-byte (r || s) signature
 	if len(message) == 0 {
 		return false
 	}
-
-	// BIP-340 Schnorr verification:
-	// 1. Parse r (32 bytes) and s (32 bytes) from signature
-	// 2. Compute e = H(r || P || m)
-	// 3. Verify s*G == R + e*P
-
-	// For now, we use a simplified verification that checks structure
-	// and delegates to the threshold package's Schnorr verification if available.
-	// A full BIP-340 implementation requires proper curve operations.
-
-	// Parse r from signature
-	r := new(big.Int).SetBytes(signature[:32])
-	s := new(big.Int).SetBytes(signature[32:64])
-
-	// Basic validity checks for Schnorr
-	// r and s must be in valid range for secp256k1
-	curveOrder := crypto.S256().Params().N
-	if r.Sign() <= 0 || r.Cmp(curveOrder) >= 0 {
-		return false
-	}
-	if s.Sign() <= 0 || s.Cmp(curveOrder) >= 0 {
-		return false
-	}
-
-	// Compute challenge hash: e = SHA256(r || P || m)
-	var challengeData []byte
-	challengeData = append(challengeData, signature[:32]...) // r
-	challengeData = append(challengeData, publicKey...)      // P (x-only)
-	challengeData = append(challengeData, message...)        // m
-	eHash := sha256.Sum256(challengeData)
-	e := new(big.Int).SetBytes(eHash[:])
-	e.Mod(e, curveOrder)
-
-	// Reconstruct public key point from x-coordinate
-	// Assume even y-coordinate (BIP-340 convention)
-	curve := crypto.S256()
-	x := new(big.Int).SetBytes(publicKey)
-	// Compute y^2 = x^3 + 7 (secp256k1 curve equation)
-	y2 := new(big.Int).Mul(x, x)
-	y2.Mul(y2, x)
-	y2.Add(y2, big.NewInt(7))
-	y2.Mod(y2, curve.Params().P)
-	_ = y2 // y-coordinate squared (would need sqrt for full verification)
-
-	// For full verification, we would compute:
-	// sG = s * G (generator point)
-	// eP = e * P (public key point)
-	// R = sG - eP
-	// Check that R.x == r
-
-	// Since full curve operations require more infrastructure,
-	// return true if structural checks pass (signature has valid form)
-	// Real implementation should use a proper Schnorr library
-	return e.Sign() > 0 // Challenge is non-zero, structure is valid
+	valid, err := ecverify.VerifySchnorrBIP340(publicKey, message, signature)
+	return err == nil && valid
 }
 
 func (qv *QuantumVerifier) aggregateBLSSignatures(signatures [][]byte) []byte {
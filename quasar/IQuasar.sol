@@ -58,9 +58,9 @@ interface IVerkleVerify {
 interface IBLSVerify {
     /**
      * @notice Verify a BLS signature
-     * @param publicKey The 48-byte compressed BLS public key
+     * @param publicKey The BLS public key: 48-byte compressed or 96-byte uncompressed
      * @param messageHash The 32-byte message hash
-     * @param signature The 96-byte BLS signature
+     * @param signature The BLS signature: 96-byte compressed or 192-byte uncompressed
      * @return valid True if the signature is valid
      */
     function verify(
@@ -11,6 +11,7 @@ import (
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/geth/core/vm"
 	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/crypto/ecverify"
 )
 
 const (
@@ -101,27 +102,29 @@ func (b *blsPrecompile) Run(accessibleState contract.AccessibleState, caller com
 	}
 	remainingGas = suppliedGas - BLSVerifyGas
 
-	// Input format: [pubkey(48)] [message(32)] [signature(96)]
-	if len(input) < 176 {
+	// Input format: [pubkey(48|96)] [message(32)] [signature(96|192)]
+	// Each point is compressed or uncompressed, as given by its flag bit
+	pubKeyLen := ecverify.BLSUncompressedPublicKeySize
+	if ecverify.IsBLSCompressed(input) {
+		pubKeyLen = ecverify.BLSPublicKeySize
+	}
+	if len(input) < pubKeyLen+32+ecverify.BLSSignatureSize {
 		return nil, remainingGas, ErrInvalidInput
 	}
-
-	pubKeyBytes := input[:48]
-	message := input[48:80]
-	sigBytes := input[80:176]
-
-	// Verify BLS signature
-	pubKey, err := bls.PublicKeyFromCompressedBytes(pubKeyBytes)
-	if err != nil {
-		return []byte{0}, remainingGas, nil
+	sigLen := ecverify.BLSUncompressedSignatureSize
+	if ecverify.IsBLSCompressed(input[pubKeyLen+32:]) {
+		sigLen = ecverify.BLSSignatureSize
 	}
-
-	sig, err := bls.SignatureFromBytes(sigBytes)
-	if err != nil {
-		return []byte{0}, remainingGas, nil
+	if len(input) < pubKeyLen+32+sigLen {
+		return nil, remainingGas, ErrInvalidInput
 	}
 
-	if bls.Verify(pubKey, sig, message) {
+	pubKeyBytes := input[:pubKeyLen]
+	message := input[pubKeyLen : pubKeyLen+32]
+	sigBytes := input[pubKeyLen+32 : pubKeyLen+32+sigLen]
+
+	// Verify BLS signature
+	if valid, err := ecverify.VerifyBLS12381(pubKeyBytes, message, sigBytes); err == nil && valid {
 		return []byte{1}, remainingGas, nil
 	}
 
@@ -261,17 +264,7 @@ func (h *hybridPrecompile) Run(accessibleState contract.AccessibleState, caller
 	ringtailPubKey := input[98+ringtailSigLen+32+48:]
 
 	// Verify BLS signature
-	blsPK, err := bls.PublicKeyFromCompressedBytes(blsPubKey)
-	if err != nil {
-		return []byte{0}, remainingGas, nil
-	}
-
-	blsS, err := bls.SignatureFromBytes(blsSig)
-	if err != nil {
-		return []byte{0}, remainingGas, nil
-	}
-
-	if !bls.Verify(blsPK, blsS, message) {
+	if valid, err := ecverify.VerifyBLS12381(blsPubKey, message, blsSig); err != nil || !valid {
 		return []byte{0}, remainingGas, nil
 	}
 
@@ -0,0 +1,96 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownPrecompile = errors.New("unknown precompile")
+	ErrNoABI             = errors.New("precompile has no ABI descriptor")
+)
+
+// precompileABIs holds the function declarations of each precompile with a
+// published Solidity interface, keyed by PrecompileInfo.Name. Declarations
+// follow the interface files next to each implementation.
+var precompileABIs = map[string][]string{
+	"P256_VERIFY": {
+		"verify(bytes32 hash, bytes32 r, bytes32 s, bytes32 x, bytes32 y) external view returns (bool valid)",
+	},
+	"ML_DSA": {
+		"verify(bytes calldata publicKey, bytes calldata message, bytes calldata signature) external view returns (bool valid)",
+	},
+	"ML_KEM": {
+		"encapsulate(uint8 mode, bytes calldata publicKey) external view returns (bytes memory ciphertext, bytes32 sharedSecret)",
+		"decapsulate(uint8 mode, bytes calldata privateKey, bytes calldata ciphertext) external view returns (bytes32 sharedSecret)",
+	},
+	"SLH_DSA": {
+		"verify(bytes calldata publicKey, bytes calldata message, bytes calldata signature) external view returns (bool valid)",
+	},
+	"BLAKE3": {
+		"hash256(bytes calldata data) external view returns (bytes32 digest)",
+		"hash512(bytes calldata data) external view returns (bytes memory digest)",
+		"hashXOF(bytes calldata data, uint32 outputLength) external view returns (bytes memory digest)",
+		"hashWithDomain(string calldata domain, bytes calldata data) external view returns (bytes32 digest)",
+		"merkleRoot(bytes32[] calldata leaves) external view returns (bytes32 root)",
+		"deriveKey(string calldata context, bytes32 keyMaterial) external view returns (bytes32 derivedKey)",
+	},
+}
+
+// ABI returns the Solidity declarations of the precompile's functions,
+// e.g. "verify(bytes32 hash, ...) external view returns (bool valid)", or
+// nil for a precompile without a published interface
+func (p PrecompileInfo) ABI() []string {
+	return precompileABIs[p.Name]
+}
+
+// InterfaceName returns the Solidity interface name of a precompile:
+// "I" followed by its name in PascalCase, e.g. P256_VERIFY → IP256Verify
+func InterfaceName(name string) string {
+	var b strings.Builder
+	b.WriteString("I")
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(strings.ToLower(part[1:]))
+	}
+	return b.String()
+}
+
+// GenerateSolidityInterface emits a Solidity interface for the precompile
+// named name, declaring every function in its ABI descriptor
+func GenerateSolidityInterface(name string) (string, error) {
+	var info *PrecompileInfo
+	for i := range AllPrecompiles {
+		if AllPrecompiles[i].Name == name {
+			info = &AllPrecompiles[i]
+			break
+		}
+	}
+	if info == nil {
+		return "", fmt.Errorf("%w: %s", ErrUnknownPrecompile, name)
+	}
+	if len(info.ABI()) == 0 {
+		return "", fmt.Errorf("%w: %s", ErrNoABI, name)
+	}
+
+	iface := InterfaceName(name)
+	var b strings.Builder
+	b.WriteString("// SPDX-License-Identifier: MIT\n")
+	b.WriteString("pragma solidity ^0.8.0;\n\n")
+	fmt.Fprintf(&b, "/// @title %s\n", iface)
+	fmt.Fprintf(&b, "/// @notice %s\n", info.Description)
+	fmt.Fprintf(&b, "/// @dev Precompile address: %s\n", info.Address)
+	fmt.Fprintf(&b, "interface %s {\n", iface)
+	for _, fn := range info.ABI() {
+		fmt.Fprintf(&b, "    function %s;\n", fn)
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
@@ -4,9 +4,14 @@
 package registry
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/precompileconfig"
 )
 
 // ============================================================================
@@ -107,178 +112,182 @@ const (
 	// =========================================================================
 
 	// Hashing (II = 0x00-0x0F)
-	Poseidon2CChain    = "0x3200000000000000000000000000000000000000" // C-Chain Poseidon2
-	Poseidon2ZChain    = "0x3600000000000000000000000000000000000000" // Z-Chain Poseidon2
-	Poseidon2SpongeCCh = "0x3201000000000000000000000000000000000000" // C-Chain Poseidon2Sponge
-	Blake3CChain       = "0x3202000000000000000000000000000000000000" // C-Chain Blake3
-	Blake3ZChain       = "0x3602000000000000000000000000000000000000" // Z-Chain Blake3
-	PedersenCChain     = "0x3203000000000000000000000000000000000000" // C-Chain Pedersen
-	PedersenZChain     = "0x3603000000000000000000000000000000000000" // Z-Chain Pedersen
-	MiMCCChain         = "0x3204000000000000000000000000000000000000" // C-Chain MiMC
-	RescueCChain       = "0x3205000000000000000000000000000000000000" // C-Chain Rescue
+	Poseidon2CChain    = "0x0000000000000000000000000000000000003200" // C-Chain Poseidon2
+	Poseidon2ZChain    = "0x0000000000000000000000000000000000003600" // Z-Chain Poseidon2
+	Poseidon2SpongeCCh = "0x0000000000000000000000000000000000003201" // C-Chain Poseidon2Sponge
+	Blake3CChain       = "0x0000000000000000000000000000000000003202" // C-Chain Blake3
+	Blake3ZChain       = "0x0000000000000000000000000000000000003602" // Z-Chain Blake3
+	PedersenCChain     = "0x0000000000000000000000000000000000003203" // C-Chain Pedersen
+	PedersenZChain     = "0x0000000000000000000000000000000000003603" // Z-Chain Pedersen
+	MiMCCChain         = "0x0000000000000000000000000000000000003204" // C-Chain MiMC
+	RescueCChain       = "0x0000000000000000000000000000000000003205" // C-Chain Rescue
 
 	// Classical Signatures (II = 0x10-0x1F)
-	ECDSACChain   = "0x3210000000000000000000000000000000000000" // Extended ECDSA
-	Ed25519CChain = "0x3211000000000000000000000000000000000000" // Ed25519
-	BLS381CChain  = "0x3212000000000000000000000000000000000000" // BLS12-381
-	SchnorrCChain = "0x3213000000000000000000000000000000000000" // Schnorr (BIP-340)
+	ECDSACChain   = "0x0000000000000000000000000000000000003210" // Extended ECDSA
+	Ed25519CChain = "0x0000000000000000000000000000000000003211" // Ed25519
+	BLS381CChain  = "0x0000000000000000000000000000000000003212" // BLS12-381
+	SchnorrCChain = "0x0000000000000000000000000000000000003213" // Schnorr (BIP-340)
 
 	// Encryption (II = 0x20-0x2F)
-	AESGCMCChain   = "0x3220000000000000000000000000000000000000" // AES-GCM
-	ChaCha20CChain = "0x3221000000000000000000000000000000000000" // ChaCha20-Poly1305
-	HPKECChain     = "0x3222000000000000000000000000000000000000" // HPKE
-	ECIESCChain    = "0x3223000000000000000000000000000000000000" // ECIES
+	AESGCMCChain   = "0x0000000000000000000000000000000000003220" // AES-GCM
+	ChaCha20CChain = "0x0000000000000000000000000000000000003221" // ChaCha20-Poly1305
+	HPKECChain     = "0x0000000000000000000000000000000000003222" // HPKE
+	ECIESCChain    = "0x0000000000000000000000000000000000003223" // ECIES
 
 	// =========================================================================
 	// PAGE 4: PRIVACY/ZK (0x4CII) → LP-4xxx
 	// =========================================================================
 
 	// SNARKs (II = 0x00-0x0F)
-	Groth16CChain = "0x4200000000000000000000000000000000000000" // C-Chain Groth16
-	Groth16ZChain = "0x4600000000000000000000000000000000000000" // Z-Chain Groth16
-	PLONKCChain   = "0x4201000000000000000000000000000000000000" // C-Chain PLONK
-	PLONKZChain   = "0x4601000000000000000000000000000000000000" // Z-Chain PLONK
-	fflonkCChain  = "0x4202000000000000000000000000000000000000" // C-Chain fflonk
-	fflonkZChain  = "0x4602000000000000000000000000000000000000" // Z-Chain fflonk
-	Halo2CChain   = "0x4203000000000000000000000000000000000000" // C-Chain Halo2
-	Halo2ZChain   = "0x4603000000000000000000000000000000000000" // Z-Chain Halo2
-	NovaCChain    = "0x4204000000000000000000000000000000000000" // C-Chain Nova
-	NovaZChain    = "0x4604000000000000000000000000000000000000" // Z-Chain Nova
+	Groth16CChain = "0x0000000000000000000000000000000000004200" // C-Chain Groth16
+	Groth16ZChain = "0x0000000000000000000000000000000000004600" // Z-Chain Groth16
+	PLONKCChain   = "0x0000000000000000000000000000000000004201" // C-Chain PLONK
+	PLONKZChain   = "0x0000000000000000000000000000000000004601" // Z-Chain PLONK
+	fflonkCChain  = "0x0000000000000000000000000000000000004202" // C-Chain fflonk
+	fflonkZChain  = "0x0000000000000000000000000000000000004602" // Z-Chain fflonk
+	Halo2CChain   = "0x0000000000000000000000000000000000004203" // C-Chain Halo2
+	Halo2ZChain   = "0x0000000000000000000000000000000000004603" // Z-Chain Halo2
+	NovaCChain    = "0x0000000000000000000000000000000000004204" // C-Chain Nova
+	NovaZChain    = "0x0000000000000000000000000000000000004604" // Z-Chain Nova
 
 	// STARKs (II = 0x10-0x1F)
-	STARKCChain       = "0x4210000000000000000000000000000000000000" // C-Chain STARK
-	STARKZChain       = "0x4610000000000000000000000000000000000000" // Z-Chain STARK
-	STARKRecursiveCCh = "0x4211000000000000000000000000000000000000" // C-Chain STARKRecursive
-	STARKRecursiveZCh = "0x4611000000000000000000000000000000000000" // Z-Chain STARKRecursive
-	STARKBatchCChain  = "0x4212000000000000000000000000000000000000" // C-Chain STARKBatch
-	STARKBatchZChain  = "0x4612000000000000000000000000000000000000" // Z-Chain STARKBatch
-	STARKReceiptsCCh  = "0x421F000000000000000000000000000000000000" // C-Chain STARKReceipts
-	STARKReceiptsZCh  = "0x461F000000000000000000000000000000000000" // Z-Chain STARKReceipts
+	STARKCChain       = "0x0000000000000000000000000000000000004210" // C-Chain STARK
+	STARKZChain       = "0x0000000000000000000000000000000000004610" // Z-Chain STARK
+	STARKRecursiveCCh = "0x0000000000000000000000000000000000004211" // C-Chain STARKRecursive
+	STARKRecursiveZCh = "0x0000000000000000000000000000000000004611" // Z-Chain STARKRecursive
+	STARKBatchCChain  = "0x0000000000000000000000000000000000004212" // C-Chain STARKBatch
+	STARKBatchZChain  = "0x0000000000000000000000000000000000004612" // Z-Chain STARKBatch
+	STARKReceiptsCCh  = "0x000000000000000000000000000000000000421F" // C-Chain STARKReceipts
+	STARKReceiptsZCh  = "0x000000000000000000000000000000000000461F" // Z-Chain STARKReceipts
 
 	// Commitments (II = 0x20-0x2F)
-	KZGCChain = "0x4220000000000000000000000000000000000000" // C-Chain KZG
-	KZGZChain = "0x4620000000000000000000000000000000000000" // Z-Chain KZG
-	IPACChain = "0x4221000000000000000000000000000000000000" // C-Chain IPA
-	IPAZChain = "0x4621000000000000000000000000000000000000" // Z-Chain IPA
-	FRICChain = "0x4222000000000000000000000000000000000000" // C-Chain FRI
-	FRIZChain = "0x4622000000000000000000000000000000000000" // Z-Chain FRI
+	KZGCChain = "0x0000000000000000000000000000000000004220" // C-Chain KZG
+	KZGZChain = "0x0000000000000000000000000000000000004620" // Z-Chain KZG
+	IPACChain = "0x0000000000000000000000000000000000004221" // C-Chain IPA
+	IPAZChain = "0x0000000000000000000000000000000000004621" // Z-Chain IPA
+	FRICChain = "0x0000000000000000000000000000000000004222" // C-Chain FRI
+	FRIZChain = "0x0000000000000000000000000000000000004622" // Z-Chain FRI
 
 	// Privacy Primitives (II = 0x30-0x3F)
-	RangeProofCChain  = "0x4230000000000000000000000000000000000000" // C-Chain Bulletproofs
-	RangeProofZChain  = "0x4630000000000000000000000000000000000000" // Z-Chain Bulletproofs
-	NullifierCChain   = "0x4231000000000000000000000000000000000000" // C-Chain Nullifier
-	NullifierZChain   = "0x4631000000000000000000000000000000000000" // Z-Chain Nullifier
-	CommitmentCChain  = "0x4232000000000000000000000000000000000000" // C-Chain Commitment
-	CommitmentZChain  = "0x4632000000000000000000000000000000000000" // Z-Chain Commitment
-	MerkleProofCChain = "0x4233000000000000000000000000000000000000" // C-Chain MerkleProof
-	MerkleProofZChain = "0x4633000000000000000000000000000000000000" // Z-Chain MerkleProof
+	RangeProofCChain  = "0x0000000000000000000000000000000000004230" // C-Chain Bulletproofs
+	RangeProofZChain  = "0x0000000000000000000000000000000000004630" // Z-Chain Bulletproofs
+	NullifierCChain   = "0x0000000000000000000000000000000000004231" // C-Chain Nullifier
+	NullifierZChain   = "0x0000000000000000000000000000000000004631" // Z-Chain Nullifier
+	CommitmentCChain  = "0x0000000000000000000000000000000000004232" // C-Chain Commitment
+	CommitmentZChain  = "0x0000000000000000000000000000000000004632" // Z-Chain Commitment
+	MerkleProofCChain = "0x0000000000000000000000000000000000004233" // C-Chain MerkleProof
+	MerkleProofZChain = "0x0000000000000000000000000000000000004633" // Z-Chain MerkleProof
 
 	// FHE (II = 0x40-0x4F)
-	FHECChain         = "0x4240000000000000000000000000000000000000" // C-Chain FHE
-	FHEZChain         = "0x4640000000000000000000000000000000000000" // Z-Chain FHE
-	TFHECChain        = "0x4241000000000000000000000000000000000000" // C-Chain TFHE
-	TFHEZChain        = "0x4641000000000000000000000000000000000000" // Z-Chain TFHE
-	CKKSCChain        = "0x4242000000000000000000000000000000000000" // C-Chain CKKS
-	CKKSZChain        = "0x4642000000000000000000000000000000000000" // Z-Chain CKKS
-	BGVCChain         = "0x4243000000000000000000000000000000000000" // C-Chain BGV
-	BGVZChain         = "0x4643000000000000000000000000000000000000" // Z-Chain BGV
-	GatewayCChain     = "0x4244000000000000000000000000000000000000" // C-Chain Gateway
-	GatewayZChain     = "0x4644000000000000000000000000000000000000" // Z-Chain Gateway
-	TaskManagerCChain = "0x4245000000000000000000000000000000000000" // C-Chain TaskManager
-	TaskManagerZChain = "0x4645000000000000000000000000000000000000" // Z-Chain TaskManager
+	FHECChain         = "0x0000000000000000000000000000000000004240" // C-Chain FHE
+	FHEZChain         = "0x0000000000000000000000000000000000004640" // Z-Chain FHE
+	TFHECChain        = "0x0000000000000000000000000000000000004241" // C-Chain TFHE
+	TFHEZChain        = "0x0000000000000000000000000000000000004641" // Z-Chain TFHE
+	CKKSCChain        = "0x0000000000000000000000000000000000004242" // C-Chain CKKS
+	CKKSZChain        = "0x0000000000000000000000000000000000004642" // Z-Chain CKKS
+	BGVCChain         = "0x0000000000000000000000000000000000004243" // C-Chain BGV
+	BGVZChain         = "0x0000000000000000000000000000000000004643" // Z-Chain BGV
+	GatewayCChain     = "0x0000000000000000000000000000000000004244" // C-Chain Gateway
+	GatewayZChain     = "0x0000000000000000000000000000000000004644" // Z-Chain Gateway
+	TaskManagerCChain = "0x0000000000000000000000000000000000004245" // C-Chain TaskManager
+	TaskManagerZChain = "0x0000000000000000000000000000000000004645" // Z-Chain TaskManager
 
 	// =========================================================================
 	// PAGE 5: THRESHOLD/MPC (0x5CII) → LP-5xxx
 	// =========================================================================
 
 	// Threshold Signatures (II = 0x00-0x0F)
-	FROSTCChain     = "0x5200000000000000000000000000000000000000" // C-Chain FROST
-	FROSTQChain     = "0x5300000000000000000000000000000000000000" // Q-Chain FROST
-	CGGMP21CChain   = "0x5201000000000000000000000000000000000000" // C-Chain CGGMP21
-	CGGMP21QChain   = "0x5301000000000000000000000000000000000000" // Q-Chain CGGMP21
-	RingtailCChain  = "0x5202000000000000000000000000000000000000" // C-Chain Ringtail
-	RingtailQChain  = "0x5302000000000000000000000000000000000000" // Q-Chain Ringtail
-	DoernerCChain   = "0x5203000000000000000000000000000000000000" // C-Chain Doerner
-	DoernerQChain   = "0x5303000000000000000000000000000000000000" // Q-Chain Doerner
-	BLSThreshCChain = "0x5204000000000000000000000000000000000000" // C-Chain BLS Threshold
-	BLSThreshQChain = "0x5304000000000000000000000000000000000000" // Q-Chain BLS Threshold
+	FROSTCChain     = "0x0000000000000000000000000000000000005200" // C-Chain FROST
+	FROSTQChain     = "0x0000000000000000000000000000000000005300" // Q-Chain FROST
+	CGGMP21CChain   = "0x0000000000000000000000000000000000005201" // C-Chain CGGMP21
+	CGGMP21QChain   = "0x0000000000000000000000000000000000005301" // Q-Chain CGGMP21
+	RingtailCChain  = "0x0000000000000000000000000000000000005202" // C-Chain Ringtail
+	RingtailQChain  = "0x0000000000000000000000000000000000005302" // Q-Chain Ringtail
+	DoernerCChain   = "0x0000000000000000000000000000000000005203" // C-Chain Doerner
+	DoernerQChain   = "0x0000000000000000000000000000000000005303" // Q-Chain Doerner
+	BLSThreshCChain = "0x0000000000000000000000000000000000005204" // C-Chain BLS Threshold
+	BLSThreshQChain = "0x0000000000000000000000000000000000005304" // Q-Chain BLS Threshold
 
 	// Secret Sharing (II = 0x10-0x1F)
-	LSSCChain     = "0x5210000000000000000000000000000000000000" // C-Chain LSS
-	LSSQChain     = "0x5310000000000000000000000000000000000000" // Q-Chain LSS
-	ShamirCChain  = "0x5211000000000000000000000000000000000000" // C-Chain Shamir
-	ShamirQChain  = "0x5311000000000000000000000000000000000000" // Q-Chain Shamir
-	FeldmanCChain = "0x5212000000000000000000000000000000000000" // C-Chain Feldman
-	FeldmanQChain = "0x5312000000000000000000000000000000000000" // Q-Chain Feldman
+	LSSCChain     = "0x0000000000000000000000000000000000005210" // C-Chain LSS
+	LSSQChain     = "0x0000000000000000000000000000000000005310" // Q-Chain LSS
+	ShamirCChain  = "0x0000000000000000000000000000000000005211" // C-Chain Shamir
+	ShamirQChain  = "0x0000000000000000000000000000000000005311" // Q-Chain Shamir
+	FeldmanCChain = "0x0000000000000000000000000000000000005212" // C-Chain Feldman
+	FeldmanQChain = "0x0000000000000000000000000000000000005312" // Q-Chain Feldman
 
 	// DKG/Custody (II = 0x20-0x2F)
-	DKGCChain      = "0x5220000000000000000000000000000000000000" // C-Chain DKG
-	DKGQChain      = "0x5320000000000000000000000000000000000000" // Q-Chain DKG
-	RefreshCChain  = "0x5221000000000000000000000000000000000000" // C-Chain Key Refresh
-	RefreshQChain  = "0x5321000000000000000000000000000000000000" // Q-Chain Key Refresh
-	RecoveryCChain = "0x5222000000000000000000000000000000000000" // C-Chain Recovery
-	RecoveryQChain = "0x5322000000000000000000000000000000000000" // Q-Chain Recovery
+	DKGCChain      = "0x0000000000000000000000000000000000005220" // C-Chain DKG
+	DKGQChain      = "0x0000000000000000000000000000000000005320" // Q-Chain DKG
+	RefreshCChain  = "0x0000000000000000000000000000000000005221" // C-Chain Key Refresh
+	RefreshQChain  = "0x0000000000000000000000000000000000005321" // Q-Chain Key Refresh
+	RecoveryCChain = "0x0000000000000000000000000000000000005222" // C-Chain Recovery
+	RecoveryQChain = "0x0000000000000000000000000000000000005322" // Q-Chain Recovery
 
 	// =========================================================================
 	// PAGE 6: BRIDGES (0x6CII) → LP-6xxx
 	// =========================================================================
 
 	// Warp Messaging (II = 0x00-0x0F)
-	WarpSendCChain     = "0x6200000000000000000000000000000000000000" // C-Chain WarpSend
-	WarpSendBChain     = "0x6500000000000000000000000000000000000000" // B-Chain WarpSend
-	WarpReceiveCChain  = "0x6201000000000000000000000000000000000000" // C-Chain WarpReceive
-	WarpReceiveBChain  = "0x6501000000000000000000000000000000000000" // B-Chain WarpReceive
-	WarpReceiptsCChain = "0x6202000000000000000000000000000000000000" // C-Chain WarpReceipts
-	WarpReceiptsBChain = "0x6502000000000000000000000000000000000000" // B-Chain WarpReceipts
+	WarpSendCChain     = "0x0000000000000000000000000000000000006200" // C-Chain WarpSend
+	WarpSendBChain     = "0x0000000000000000000000000000000000006500" // B-Chain WarpSend
+	WarpReceiveCChain  = "0x0000000000000000000000000000000000006201" // C-Chain WarpReceive
+	WarpReceiveBChain  = "0x0000000000000000000000000000000000006501" // B-Chain WarpReceive
+	WarpReceiptsCChain = "0x0000000000000000000000000000000000006202" // C-Chain WarpReceipts
+	WarpReceiptsBChain = "0x0000000000000000000000000000000000006502" // B-Chain WarpReceipts
 
 	// Token Bridges (II = 0x10-0x1F)
-	BridgeCChain       = "0x6210000000000000000000000000000000000000" // C-Chain Bridge
-	BridgeBChain       = "0x6510000000000000000000000000000000000000" // B-Chain Bridge
-	TeleportCChain     = "0x6211000000000000000000000000000000000000" // C-Chain Teleport
-	TeleportBChain     = "0x6511000000000000000000000000000000000000" // B-Chain Teleport
-	BridgeRouterCChain = "0x6212000000000000000000000000000000000000" // C-Chain BridgeRouter
-	BridgeRouterBChain = "0x6512000000000000000000000000000000000000" // B-Chain BridgeRouter
+	BridgeCChain       = "0x0000000000000000000000000000000000006210" // C-Chain Bridge
+	BridgeBChain       = "0x0000000000000000000000000000000000006510" // B-Chain Bridge
+	TeleportCChain     = "0x0000000000000000000000000000000000006211" // C-Chain Teleport
+	TeleportBChain     = "0x0000000000000000000000000000000000006511" // B-Chain Teleport
+	BridgeRouterCChain = "0x0000000000000000000000000000000000006212" // C-Chain BridgeRouter
+	BridgeRouterBChain = "0x0000000000000000000000000000000000006512" // B-Chain BridgeRouter
 
 	// Fee Collection (II = 0x20-0x2F)
-	FeeCollectCChain = "0x6220000000000000000000000000000000000000" // C-Chain FeeCollect
-	FeeCollectBChain = "0x6520000000000000000000000000000000000000" // B-Chain FeeCollect
-	FeeGovCChain     = "0x6221000000000000000000000000000000000000" // C-Chain FeeGov
-	FeeGovBChain     = "0x6521000000000000000000000000000000000000" // B-Chain FeeGov
+	FeeCollectCChain = "0x0000000000000000000000000000000000006220" // C-Chain FeeCollect
+	FeeCollectBChain = "0x0000000000000000000000000000000000006520" // B-Chain FeeCollect
+	FeeGovCChain     = "0x0000000000000000000000000000000000006221" // C-Chain FeeGov
+	FeeGovBChain     = "0x0000000000000000000000000000000000006521" // B-Chain FeeGov
+
+	// State Proofs (II = 0x30-0x3F)
+	MPTProofCChain = "0x0000000000000000000000000000000000006230" // C-Chain Ethereum MPT proof
+	MPTProofBChain = "0x0000000000000000000000000000000000006530" // B-Chain Ethereum MPT proof
 
 	// =========================================================================
 	// PAGE 7: AI (0x7CII) → LP-7xxx
 	// =========================================================================
 
 	// Attestation (II = 0x00-0x0F)
-	GPUAttestCChain = "0x7200000000000000000000000000000000000000" // C-Chain GPU Attestation
-	GPUAttestAChain = "0x7400000000000000000000000000000000000000" // A-Chain GPU Attestation
-	GPUAttestHanzo  = "0x7900000000000000000000000000000000000000" // Hanzo GPU Attestation
-	TEEVerifyCChain = "0x7201000000000000000000000000000000000000" // C-Chain TEE Verify
-	TEEVerifyAChain = "0x7401000000000000000000000000000000000000" // A-Chain TEE Verify
-	NVTrustCChain   = "0x7202000000000000000000000000000000000000" // C-Chain NVTrust
-	NVTrustAChain   = "0x7402000000000000000000000000000000000000" // A-Chain NVTrust
-	SGXAttestCChain = "0x7203000000000000000000000000000000000000" // C-Chain SGX Attestation
-	SGXAttestAChain = "0x7403000000000000000000000000000000000000" // A-Chain SGX Attestation
-	TDXAttestCChain = "0x7204000000000000000000000000000000000000" // C-Chain TDX Attestation
-	TDXAttestAChain = "0x7404000000000000000000000000000000000000" // A-Chain TDX Attestation
+	GPUAttestCChain = "0x0000000000000000000000000000000000007200" // C-Chain GPU Attestation
+	GPUAttestAChain = "0x0000000000000000000000000000000000007400" // A-Chain GPU Attestation
+	GPUAttestHanzo  = "0x0000000000000000000000000000000000007900" // Hanzo GPU Attestation
+	TEEVerifyCChain = "0x0000000000000000000000000000000000007201" // C-Chain TEE Verify
+	TEEVerifyAChain = "0x0000000000000000000000000000000000007401" // A-Chain TEE Verify
+	NVTrustCChain   = "0x0000000000000000000000000000000000007202" // C-Chain NVTrust
+	NVTrustAChain   = "0x0000000000000000000000000000000000007402" // A-Chain NVTrust
+	SGXAttestCChain = "0x0000000000000000000000000000000000007203" // C-Chain SGX Attestation
+	SGXAttestAChain = "0x0000000000000000000000000000000000007403" // A-Chain SGX Attestation
+	TDXAttestCChain = "0x0000000000000000000000000000000000007204" // C-Chain TDX Attestation
+	TDXAttestAChain = "0x0000000000000000000000000000000000007404" // A-Chain TDX Attestation
 
 	// Inference (II = 0x10-0x1F)
-	InferenceCChain  = "0x7210000000000000000000000000000000000000" // C-Chain Inference
-	InferenceAChain  = "0x7410000000000000000000000000000000000000" // A-Chain Inference
-	InferenceHanzo   = "0x7910000000000000000000000000000000000000" // Hanzo Inference
-	ProvenanceCChain = "0x7211000000000000000000000000000000000000" // C-Chain Provenance
-	ProvenanceAChain = "0x7411000000000000000000000000000000000000" // A-Chain Provenance
-	ModelHashCChain  = "0x7212000000000000000000000000000000000000" // C-Chain ModelHash
-	ModelHashAChain  = "0x7412000000000000000000000000000000000000" // A-Chain ModelHash
+	InferenceCChain  = "0x0000000000000000000000000000000000007210" // C-Chain Inference
+	InferenceAChain  = "0x0000000000000000000000000000000000007410" // A-Chain Inference
+	InferenceHanzo   = "0x0000000000000000000000000000000000007910" // Hanzo Inference
+	ProvenanceCChain = "0x0000000000000000000000000000000000007211" // C-Chain Provenance
+	ProvenanceAChain = "0x0000000000000000000000000000000000007411" // A-Chain Provenance
+	ModelHashCChain  = "0x0000000000000000000000000000000000007212" // C-Chain ModelHash
+	ModelHashAChain  = "0x0000000000000000000000000000000000007412" // A-Chain ModelHash
 
 	// Mining (II = 0x20-0x2F)
-	SessionCChain   = "0x7220000000000000000000000000000000000000" // C-Chain Session
-	SessionAChain   = "0x7420000000000000000000000000000000000000" // A-Chain Session
-	SessionHanzo    = "0x7920000000000000000000000000000000000000" // Hanzo Session
-	HeartbeatCChain = "0x7221000000000000000000000000000000000000" // C-Chain Heartbeat
-	HeartbeatAChain = "0x7421000000000000000000000000000000000000" // A-Chain Heartbeat
-	RewardCChain    = "0x7222000000000000000000000000000000000000" // C-Chain Reward
-	RewardAChain    = "0x7422000000000000000000000000000000000000" // A-Chain Reward
+	SessionCChain   = "0x0000000000000000000000000000000000007220" // C-Chain Session
+	SessionAChain   = "0x0000000000000000000000000000000000007420" // A-Chain Session
+	SessionHanzo    = "0x0000000000000000000000000000000000007920" // Hanzo Session
+	HeartbeatCChain = "0x0000000000000000000000000000000000007221" // C-Chain Heartbeat
+	HeartbeatAChain = "0x0000000000000000000000000000000000007421" // A-Chain Heartbeat
+	RewardCChain    = "0x0000000000000000000000000000000000007222" // C-Chain Reward
+	RewardAChain    = "0x0000000000000000000000000000000000007422" // A-Chain Reward
 
 	// =========================================================================
 	// PAGE 9: DEX/MARKETS → LP-9xxx (addresses match LP numbers directly)
@@ -392,7 +401,7 @@ var ChainPrecompiles = map[string][]string{
 		// Threshold (P=5)
 		FROSTCChain, CGGMP21CChain, RingtailCChain, LSSCChain, DKGCChain,
 		// Bridges (P=6)
-		WarpSendCChain, WarpReceiveCChain, BridgeCChain, TeleportCChain,
+		WarpSendCChain, WarpReceiveCChain, BridgeCChain, TeleportCChain, MPTProofCChain,
 		// AI (P=7)
 		GPUAttestCChain, TEEVerifyCChain, InferenceCChain, SessionCChain,
 		// DEX (LP-9xxx)
@@ -422,7 +431,7 @@ var ChainPrecompiles = map[string][]string{
 		// Bridges (P=6)
 		WarpSendBChain, WarpReceiveBChain, WarpReceiptsBChain,
 		BridgeBChain, TeleportBChain, BridgeRouterBChain,
-		FeeCollectBChain, FeeGovBChain,
+		FeeCollectBChain, FeeGovBChain, MPTProofBChain,
 	},
 
 	// Z-Chain (Privacy) - ZK/Privacy focused
@@ -479,6 +488,10 @@ var AllPrecompiles = []PrecompileInfo{
 	// BLS12-381 (standard EVM)
 	{BLS12381G1AddAddress, "BLS12381_G1ADD", "BLS12-381 G1 point addition", 500, []string{"C"}, "EIP-2537"},
 	{BLS12381G1MulAddress, "BLS12381_G1MUL", "BLS12-381 G1 scalar multiplication", 12000, []string{"C"}, "EIP-2537"},
+	{BLS12381G1MSMAddress, "BLS12381_G1MSM", "BLS12-381 G1 multi-scalar multiplication", 14400, []string{"C"}, "EIP-2537"},
+	{BLS12381G2AddAddress, "BLS12381_G2ADD", "BLS12-381 G2 point addition", 800, []string{"C"}, "EIP-2537"},
+	{BLS12381G2MulAddress, "BLS12381_G2MUL", "BLS12-381 G2 scalar multiplication", 45000, []string{"C"}, "EIP-2537"},
+	{BLS12381G2MSMAddress, "BLS12381_G2MSM", "BLS12-381 G2 multi-scalar multiplication", 54000, []string{"C"}, "EIP-2537"},
 	{BLS12381PairingAddress, "BLS12381_PAIRING", "BLS12-381 pairing check", 115000, []string{"C"}, "EIP-2537"},
 
 	// P-256
@@ -517,6 +530,7 @@ var AllPrecompiles = []PrecompileInfo{
 	{WarpReceiveCChain, "WARP_RECEIVE", "Cross-chain message receive", 50000, []string{"C", "B", "A", "Zoo", "Hanzo", "P", "X"}, "LP-6xxx"},
 	{BridgeCChain, "BRIDGE", "Token bridge operations", 75000, []string{"C", "B"}, "LP-6xxx"},
 	{TeleportCChain, "TELEPORT", "Instant token teleport", 100000, []string{"C", "B"}, "LP-6xxx"},
+	{MPTProofCChain, "MPT_PROOF", "Ethereum Merkle-Patricia state proof", 3000, []string{"C", "B"}, "LP-6xxx"},
 
 	// AI (P=7) → LP-7xxx
 	{GPUAttestCChain, "GPU_ATTEST", "GPU compute attestation", 100000, []string{"C", "A", "Hanzo"}, "LP-7xxx"},
@@ -550,7 +564,31 @@ func GetPrecompileAddress(name string) common.Address {
 	return common.Address{}
 }
 
-// GetChainPrecompiles returns all precompile addresses for a chain
+var (
+	infoByAddressOnce sync.Once
+	infoByAddress     map[common.Address]PrecompileInfo
+)
+
+// GetPrecompileInfoByAddress returns the metadata of the precompile at addr,
+// for example one seen in a trace. Entry addresses are normalized through
+// common.HexToAddress, so short and full-length hex forms match alike. The
+// index is built from AllPrecompiles on first use.
+func GetPrecompileInfoByAddress(addr common.Address) (PrecompileInfo, bool) {
+	infoByAddressOnce.Do(func() {
+		infoByAddress = make(map[common.Address]PrecompileInfo, len(AllPrecompiles))
+		for _, p := range AllPrecompiles {
+			a := common.HexToAddress(p.Address)
+			if _, exists := infoByAddress[a]; !exists {
+				infoByAddress[a] = p
+			}
+		}
+	})
+	info, ok := infoByAddress[addr]
+	return info, ok
+}
+
+// GetChainPrecompiles returns all precompile addresses for a chain, sorted
+// by address
 func GetChainPrecompiles(chainLetter string) []common.Address {
 	addrs, ok := ChainPrecompiles[chainLetter]
 	if !ok {
@@ -561,9 +599,30 @@ func GetChainPrecompiles(chainLetter string) []common.Address {
 	for i, addr := range addrs {
 		result[i] = common.HexToAddress(addr)
 	}
+	sortAddresses(result)
+	return result
+}
+
+// GetChainPrecompilesPage returns the window [offset, offset+limit) of
+// GetChainPrecompiles(chainLetter); see Paginate
+func GetChainPrecompilesPage(chainLetter string, offset, limit int) []common.Address {
+	return Paginate(GetChainPrecompiles(chainLetter), offset, limit)
+}
+
+// ListPrecompiles returns AllPrecompiles sorted by address
+func ListPrecompiles() []PrecompileInfo {
+	result := make([]PrecompileInfo, len(AllPrecompiles))
+	copy(result, AllPrecompiles)
+	sortByAddress(result)
 	return result
 }
 
+// ListPrecompilesPage returns the window [offset, offset+limit) of
+// ListPrecompiles(); see Paginate
+func ListPrecompilesPage(offset, limit int) []PrecompileInfo {
+	return Paginate(ListPrecompiles(), offset, limit)
+}
+
 // IsPrecompileEnabled checks if a precompile is enabled for a chain
 func IsPrecompileEnabled(chainLetter string, precompileAddr common.Address) bool {
 	addrs := ChainPrecompiles[chainLetter]
@@ -576,7 +635,54 @@ func IsPrecompileEnabled(chainLetter string, precompileAddr common.Address) bool
 	return false
 }
 
-// GetPrecompilesByFamily returns all precompiles for a family page
+// EnabledPrecompilesAt returns the precompiles enabled for a chain at the
+// given block timestamp, sorted by address. configs maps a precompile name (as in AllPrecompiles)
+// to its most recent network upgrade:
+//   - an enabling upgrade activates the precompile from its timestamp on
+//   - a disabling upgrade deactivates it from its timestamp on
+//   - an upgrade with a nil timestamp means the precompile is never enabled
+//
+// Precompiles without a config keep the static ChainPrecompiles enablement.
+func EnabledPrecompilesAt(chain string, timestamp uint64, configs map[string]precompileconfig.Config) []common.Address {
+	addrs, ok := ChainPrecompiles[chain]
+	if !ok {
+		return nil
+	}
+
+	names := make(map[common.Address]string, len(AllPrecompiles))
+	for _, p := range AllPrecompiles {
+		names[common.HexToAddress(p.Address)] = p.Name
+	}
+
+	result := make([]common.Address, 0, len(addrs))
+	for _, addr := range addrs {
+		address := common.HexToAddress(addr)
+		if cfg, ok := configs[names[address]]; ok && !isActiveAt(cfg, timestamp) {
+			continue
+		}
+		result = append(result, address)
+	}
+	sortAddresses(result)
+	return result
+}
+
+// isActiveAt reports whether cfg leaves its precompile enabled at timestamp
+func isActiveAt(cfg precompileconfig.Config, timestamp uint64) bool {
+	if cfg == nil {
+		return true
+	}
+	activation := cfg.Timestamp()
+	if activation == nil {
+		return false
+	}
+	if cfg.IsDisabled() {
+		return timestamp < *activation
+	}
+	return timestamp >= *activation
+}
+
+// GetPrecompilesByFamily returns all precompiles for a family page, sorted
+// by address
 func GetPrecompilesByFamily(family string) []PrecompileInfo {
 	page := FamilyPage(family)
 	if page == 0xFF {
@@ -590,5 +696,343 @@ func GetPrecompilesByFamily(family string) []PrecompileInfo {
 			result = append(result, p)
 		}
 	}
+	sortByAddress(result)
 	return result
 }
+
+// GetPrecompilesByFamilyPage returns the window [offset, offset+limit) of
+// GetPrecompilesByFamily(family); see Paginate
+func GetPrecompilesByFamilyPage(family string, offset, limit int) []PrecompileInfo {
+	return Paginate(GetPrecompilesByFamily(family), offset, limit)
+}
+
+// Paginate returns the items from offset up to offset+limit, clipped to the
+// slice. A negative offset is treated as zero and a limit of zero or less
+// means no limit; an offset past the end returns an empty slice. The result
+// shares items' backing array.
+func Paginate[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return items[:0:0]
+	}
+	end := len(items)
+	if limit > 0 && limit < end-offset {
+		end = offset + limit
+	}
+	return items[offset:end:end]
+}
+
+// sortAddresses sorts addrs by address value
+func sortAddresses(addrs []common.Address) {
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+}
+
+// sortByAddress sorts infos by address value, keeping AllPrecompiles order
+// between entries that share an address
+func sortByAddress(infos []PrecompileInfo) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		a, b := common.HexToAddress(infos[i].Address), common.HexToAddress(infos[j].Address)
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+}
+
+// Gas tiers for fee estimation UIs, by PrecompileInfo.GasBase
+const (
+	GasTierCheap     = "cheap"     // below GasTierModerateMin
+	GasTierModerate  = "moderate"  // GasTierModerateMin up to GasTierExpensiveMin
+	GasTierExpensive = "expensive" // above GasTierExpensiveMin
+
+	GasTierModerateMin  uint64 = 10_000
+	GasTierExpensiveMin uint64 = 100_000
+)
+
+// GasTier returns the gas tier of a base gas cost
+func GasTier(gasBase uint64) string {
+	switch {
+	case gasBase < GasTierModerateMin:
+		return GasTierCheap
+	case gasBase <= GasTierExpensiveMin:
+		return GasTierModerate
+	default:
+		return GasTierExpensive
+	}
+}
+
+// PrecompilesByGasTier buckets AllPrecompiles by GasTier of their GasBase.
+// Each bucket is sorted by address.
+func PrecompilesByGasTier() map[string][]PrecompileInfo {
+	tiers := make(map[string][]PrecompileInfo, 3)
+	for _, p := range ListPrecompiles() {
+		tier := GasTier(p.GasBase)
+		tiers[tier] = append(tiers[tier], p)
+	}
+	return tiers
+}
+
+// namedConstant records the documented (P, C, II) decomposition of a named
+// address constant. Standard EVM addresses (BLS12-381, P-256) are not part of
+// the PCII scheme and are not listed.
+type namedConstant struct {
+	Name    string
+	Address string
+	Family  string
+	Chain   string
+	Item    uint8
+}
+
+// namedConstants lists every Lux-native address constant with its
+// decomposition. DEX addresses are LP-9xxx numbers, so their chain slot is 0.
+var namedConstants = []namedConstant{
+	{"MLDSACChain", MLDSACChain, "PQ", "C", 0x00},
+	{"MLDSAQChain", MLDSAQChain, "PQ", "Q", 0x00},
+	{"MLKEMCChain", MLKEMCChain, "PQ", "C", 0x01},
+	{"MLKEMQChain", MLKEMQChain, "PQ", "Q", 0x01},
+	{"SLHDSACChain", SLHDSACChain, "PQ", "C", 0x02},
+	{"SLHDSAQChain", SLHDSAQChain, "PQ", "Q", 0x02},
+	{"FalconCChain", FalconCChain, "PQ", "C", 0x03},
+	{"FalconQChain", FalconQChain, "PQ", "Q", 0x03},
+	{"KyberCChain", KyberCChain, "PQ", "C", 0x10},
+	{"KyberQChain", KyberQChain, "PQ", "Q", 0x10},
+	{"NTRUCChain", NTRUCChain, "PQ", "C", 0x11},
+	{"NTRUQChain", NTRUQChain, "PQ", "Q", 0x11},
+	{"HybridSignCChain", HybridSignCChain, "PQ", "C", 0x20},
+	{"HybridSignQChain", HybridSignQChain, "PQ", "Q", 0x20},
+	{"HybridKEMCChain", HybridKEMCChain, "PQ", "C", 0x21},
+	{"HybridKEMQChain", HybridKEMQChain, "PQ", "Q", 0x21},
+	{"Poseidon2CChain", Poseidon2CChain, "Crypto", "C", 0x00},
+	{"Poseidon2ZChain", Poseidon2ZChain, "Crypto", "Z", 0x00},
+	{"Poseidon2SpongeCCh", Poseidon2SpongeCCh, "Crypto", "C", 0x01},
+	{"Blake3CChain", Blake3CChain, "Crypto", "C", 0x02},
+	{"Blake3ZChain", Blake3ZChain, "Crypto", "Z", 0x02},
+	{"PedersenCChain", PedersenCChain, "Crypto", "C", 0x03},
+	{"PedersenZChain", PedersenZChain, "Crypto", "Z", 0x03},
+	{"MiMCCChain", MiMCCChain, "Crypto", "C", 0x04},
+	{"RescueCChain", RescueCChain, "Crypto", "C", 0x05},
+	{"ECDSACChain", ECDSACChain, "Crypto", "C", 0x10},
+	{"Ed25519CChain", Ed25519CChain, "Crypto", "C", 0x11},
+	{"BLS381CChain", BLS381CChain, "Crypto", "C", 0x12},
+	{"SchnorrCChain", SchnorrCChain, "Crypto", "C", 0x13},
+	{"AESGCMCChain", AESGCMCChain, "Crypto", "C", 0x20},
+	{"ChaCha20CChain", ChaCha20CChain, "Crypto", "C", 0x21},
+	{"HPKECChain", HPKECChain, "Crypto", "C", 0x22},
+	{"ECIESCChain", ECIESCChain, "Crypto", "C", 0x23},
+	{"Groth16CChain", Groth16CChain, "ZK", "C", 0x00},
+	{"Groth16ZChain", Groth16ZChain, "ZK", "Z", 0x00},
+	{"PLONKCChain", PLONKCChain, "ZK", "C", 0x01},
+	{"PLONKZChain", PLONKZChain, "ZK", "Z", 0x01},
+	{"fflonkCChain", fflonkCChain, "ZK", "C", 0x02},
+	{"fflonkZChain", fflonkZChain, "ZK", "Z", 0x02},
+	{"Halo2CChain", Halo2CChain, "ZK", "C", 0x03},
+	{"Halo2ZChain", Halo2ZChain, "ZK", "Z", 0x03},
+	{"NovaCChain", NovaCChain, "ZK", "C", 0x04},
+	{"NovaZChain", NovaZChain, "ZK", "Z", 0x04},
+	{"STARKCChain", STARKCChain, "ZK", "C", 0x10},
+	{"STARKZChain", STARKZChain, "ZK", "Z", 0x10},
+	{"STARKRecursiveCCh", STARKRecursiveCCh, "ZK", "C", 0x11},
+	{"STARKRecursiveZCh", STARKRecursiveZCh, "ZK", "Z", 0x11},
+	{"STARKBatchCChain", STARKBatchCChain, "ZK", "C", 0x12},
+	{"STARKBatchZChain", STARKBatchZChain, "ZK", "Z", 0x12},
+	{"STARKReceiptsCCh", STARKReceiptsCCh, "ZK", "C", 0x1F},
+	{"STARKReceiptsZCh", STARKReceiptsZCh, "ZK", "Z", 0x1F},
+	{"KZGCChain", KZGCChain, "ZK", "C", 0x20},
+	{"KZGZChain", KZGZChain, "ZK", "Z", 0x20},
+	{"IPACChain", IPACChain, "ZK", "C", 0x21},
+	{"IPAZChain", IPAZChain, "ZK", "Z", 0x21},
+	{"FRICChain", FRICChain, "ZK", "C", 0x22},
+	{"FRIZChain", FRIZChain, "ZK", "Z", 0x22},
+	{"RangeProofCChain", RangeProofCChain, "ZK", "C", 0x30},
+	{"RangeProofZChain", RangeProofZChain, "ZK", "Z", 0x30},
+	{"NullifierCChain", NullifierCChain, "ZK", "C", 0x31},
+	{"NullifierZChain", NullifierZChain, "ZK", "Z", 0x31},
+	{"CommitmentCChain", CommitmentCChain, "ZK", "C", 0x32},
+	{"CommitmentZChain", CommitmentZChain, "ZK", "Z", 0x32},
+	{"MerkleProofCChain", MerkleProofCChain, "ZK", "C", 0x33},
+	{"MerkleProofZChain", MerkleProofZChain, "ZK", "Z", 0x33},
+	{"FHECChain", FHECChain, "ZK", "C", 0x40},
+	{"FHEZChain", FHEZChain, "ZK", "Z", 0x40},
+	{"TFHECChain", TFHECChain, "ZK", "C", 0x41},
+	{"TFHEZChain", TFHEZChain, "ZK", "Z", 0x41},
+	{"CKKSCChain", CKKSCChain, "ZK", "C", 0x42},
+	{"CKKSZChain", CKKSZChain, "ZK", "Z", 0x42},
+	{"BGVCChain", BGVCChain, "ZK", "C", 0x43},
+	{"BGVZChain", BGVZChain, "ZK", "Z", 0x43},
+	{"GatewayCChain", GatewayCChain, "ZK", "C", 0x44},
+	{"GatewayZChain", GatewayZChain, "ZK", "Z", 0x44},
+	{"TaskManagerCChain", TaskManagerCChain, "ZK", "C", 0x45},
+	{"TaskManagerZChain", TaskManagerZChain, "ZK", "Z", 0x45},
+	{"FROSTCChain", FROSTCChain, "Threshold", "C", 0x00},
+	{"FROSTQChain", FROSTQChain, "Threshold", "Q", 0x00},
+	{"CGGMP21CChain", CGGMP21CChain, "Threshold", "C", 0x01},
+	{"CGGMP21QChain", CGGMP21QChain, "Threshold", "Q", 0x01},
+	{"RingtailCChain", RingtailCChain, "Threshold", "C", 0x02},
+	{"RingtailQChain", RingtailQChain, "Threshold", "Q", 0x02},
+	{"DoernerCChain", DoernerCChain, "Threshold", "C", 0x03},
+	{"DoernerQChain", DoernerQChain, "Threshold", "Q", 0x03},
+	{"BLSThreshCChain", BLSThreshCChain, "Threshold", "C", 0x04},
+	{"BLSThreshQChain", BLSThreshQChain, "Threshold", "Q", 0x04},
+	{"LSSCChain", LSSCChain, "Threshold", "C", 0x10},
+	{"LSSQChain", LSSQChain, "Threshold", "Q", 0x10},
+	{"ShamirCChain", ShamirCChain, "Threshold", "C", 0x11},
+	{"ShamirQChain", ShamirQChain, "Threshold", "Q", 0x11},
+	{"FeldmanCChain", FeldmanCChain, "Threshold", "C", 0x12},
+	{"FeldmanQChain", FeldmanQChain, "Threshold", "Q", 0x12},
+	{"DKGCChain", DKGCChain, "Threshold", "C", 0x20},
+	{"DKGQChain", DKGQChain, "Threshold", "Q", 0x20},
+	{"RefreshCChain", RefreshCChain, "Threshold", "C", 0x21},
+	{"RefreshQChain", RefreshQChain, "Threshold", "Q", 0x21},
+	{"RecoveryCChain", RecoveryCChain, "Threshold", "C", 0x22},
+	{"RecoveryQChain", RecoveryQChain, "Threshold", "Q", 0x22},
+	{"WarpSendCChain", WarpSendCChain, "Bridge", "C", 0x00},
+	{"WarpSendBChain", WarpSendBChain, "Bridge", "B", 0x00},
+	{"WarpReceiveCChain", WarpReceiveCChain, "Bridge", "C", 0x01},
+	{"WarpReceiveBChain", WarpReceiveBChain, "Bridge", "B", 0x01},
+	{"WarpReceiptsCChain", WarpReceiptsCChain, "Bridge", "C", 0x02},
+	{"WarpReceiptsBChain", WarpReceiptsBChain, "Bridge", "B", 0x02},
+	{"BridgeCChain", BridgeCChain, "Bridge", "C", 0x10},
+	{"BridgeBChain", BridgeBChain, "Bridge", "B", 0x10},
+	{"TeleportCChain", TeleportCChain, "Bridge", "C", 0x11},
+	{"TeleportBChain", TeleportBChain, "Bridge", "B", 0x11},
+	{"BridgeRouterCChain", BridgeRouterCChain, "Bridge", "C", 0x12},
+	{"BridgeRouterBChain", BridgeRouterBChain, "Bridge", "B", 0x12},
+	{"FeeCollectCChain", FeeCollectCChain, "Bridge", "C", 0x20},
+	{"FeeCollectBChain", FeeCollectBChain, "Bridge", "B", 0x20},
+	{"FeeGovCChain", FeeGovCChain, "Bridge", "C", 0x21},
+	{"FeeGovBChain", FeeGovBChain, "Bridge", "B", 0x21},
+	{"MPTProofCChain", MPTProofCChain, "Bridge", "C", 0x30},
+	{"MPTProofBChain", MPTProofBChain, "Bridge", "B", 0x30},
+	{"GPUAttestCChain", GPUAttestCChain, "AI", "C", 0x00},
+	{"GPUAttestAChain", GPUAttestAChain, "AI", "A", 0x00},
+	{"GPUAttestHanzo", GPUAttestHanzo, "AI", "Hanzo", 0x00},
+	{"TEEVerifyCChain", TEEVerifyCChain, "AI", "C", 0x01},
+	{"TEEVerifyAChain", TEEVerifyAChain, "AI", "A", 0x01},
+	{"NVTrustCChain", NVTrustCChain, "AI", "C", 0x02},
+	{"NVTrustAChain", NVTrustAChain, "AI", "A", 0x02},
+	{"SGXAttestCChain", SGXAttestCChain, "AI", "C", 0x03},
+	{"SGXAttestAChain", SGXAttestAChain, "AI", "A", 0x03},
+	{"TDXAttestCChain", TDXAttestCChain, "AI", "C", 0x04},
+	{"TDXAttestAChain", TDXAttestAChain, "AI", "A", 0x04},
+	{"InferenceCChain", InferenceCChain, "AI", "C", 0x10},
+	{"InferenceAChain", InferenceAChain, "AI", "A", 0x10},
+	{"InferenceHanzo", InferenceHanzo, "AI", "Hanzo", 0x10},
+	{"ProvenanceCChain", ProvenanceCChain, "AI", "C", 0x11},
+	{"ProvenanceAChain", ProvenanceAChain, "AI", "A", 0x11},
+	{"ModelHashCChain", ModelHashCChain, "AI", "C", 0x12},
+	{"ModelHashAChain", ModelHashAChain, "AI", "A", 0x12},
+	{"SessionCChain", SessionCChain, "AI", "C", 0x20},
+	{"SessionAChain", SessionAChain, "AI", "A", 0x20},
+	{"SessionHanzo", SessionHanzo, "AI", "Hanzo", 0x20},
+	{"HeartbeatCChain", HeartbeatCChain, "AI", "C", 0x21},
+	{"HeartbeatAChain", HeartbeatAChain, "AI", "A", 0x21},
+	{"RewardCChain", RewardCChain, "AI", "C", 0x22},
+	{"RewardAChain", RewardAChain, "AI", "A", 0x22},
+	{"LXPool", LXPool, "DEX", "P", 0x10},
+	{"LXOracle", LXOracle, "DEX", "P", 0x11},
+	{"LXRouter", LXRouter, "DEX", "P", 0x12},
+	{"LXHooks", LXHooks, "DEX", "P", 0x13},
+	{"LXFlash", LXFlash, "DEX", "P", 0x14},
+	{"LXBook", LXBook, "DEX", "P", 0x20},
+	{"LXVault", LXVault, "DEX", "P", 0x30},
+	{"LXFeed", LXFeed, "DEX", "P", 0x40},
+	{"LXLend", LXLend, "DEX", "P", 0x50},
+	{"LXLiquid", LXLiquid, "DEX", "P", 0x60},
+	{"Liquidator", Liquidator, "DEX", "P", 0x70},
+	{"LiquidFX", LiquidFX, "DEX", "P", 0x80},
+}
+
+// VerifyConstants recomputes each named address constant from its documented
+// (P, C, II) decomposition and returns an error listing every constant whose
+// literal disagrees. It catches typos that are still unique addresses.
+func VerifyConstants() error {
+	return verifyConstants(namedConstants)
+}
+
+func verifyConstants(constants []namedConstant) error {
+	var errs []error
+	for _, nc := range constants {
+		p, c := FamilyPage(nc.Family), ChainSlot(nc.Chain)
+		if p == 0xFF || c == 0xFF {
+			errs = append(errs, fmt.Errorf("%s: unknown family %q or chain %q", nc.Name, nc.Family, nc.Chain))
+			continue
+		}
+		want := PrecompileAddress(p, c, nc.Item)
+		if !common.IsHexAddress(nc.Address) || common.HexToAddress(nc.Address) != want {
+			errs = append(errs, fmt.Errorf("%s = %s, want %s (P=%x C=%x II=%02x)", nc.Name, nc.Address, want.Hex(), p, c, nc.Item))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	ErrAddressCollision   = errors.New("registry entries share an address")
+	ErrUnlistedPrecompile = errors.New("chain enables an unlisted precompile")
+)
+
+func init() {
+	if err := ValidateRegistry(); err != nil {
+		panic(err)
+	}
+}
+
+// ValidateRegistry checks the hand-maintained address tables for mistakes
+// that are still well-formed hex. It returns an error naming every pair of
+// AllPrecompiles entries, or of named constants, that map to the same
+// address, every address a chain in ChainPrecompiles lists twice, and every
+// ChainPrecompiles address that is neither in AllPrecompiles nor a named
+// constant. Per-chain deployments of a precompile (e.g. MLDSAQChain) are
+// named constants, not AllPrecompiles entries. The package panics at init
+// if it fails, so a bad edit cannot ship.
+func ValidateRegistry() error {
+	return validateRegistry(AllPrecompiles, ChainPrecompiles, namedConstants)
+}
+
+func validateRegistry(all []PrecompileInfo, chains map[string][]string, constants []namedConstant) error {
+	var errs []error
+	known := make(map[common.Address]bool, len(all)+len(constants))
+
+	byAddress := make(map[common.Address]string, len(all))
+	for _, p := range all {
+		addr := common.HexToAddress(p.Address)
+		if prev, ok := byAddress[addr]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s and %s at %s", ErrAddressCollision, prev, p.Name, addr.Hex()))
+			continue
+		}
+		byAddress[addr] = p.Name
+		known[addr] = true
+	}
+
+	byAddress = make(map[common.Address]string, len(constants))
+	for _, nc := range constants {
+		addr := common.HexToAddress(nc.Address)
+		if prev, ok := byAddress[addr]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s and %s at %s", ErrAddressCollision, prev, nc.Name, addr.Hex()))
+			continue
+		}
+		byAddress[addr] = nc.Name
+		known[addr] = true
+	}
+
+	names := make([]string, 0, len(chains))
+	for chain := range chains {
+		names = append(names, chain)
+	}
+	sort.Strings(names)
+	for _, chain := range names {
+		seen := make(map[common.Address]int, len(chains[chain]))
+		for i, entry := range chains[chain] {
+			addr := common.HexToAddress(entry)
+			if prev, ok := seen[addr]; ok {
+				errs = append(errs, fmt.Errorf("%w: %s[%d] and %s[%d] at %s", ErrAddressCollision, chain, prev, chain, i, addr.Hex()))
+				continue
+			}
+			seen[addr] = i
+			if !known[addr] {
+				errs = append(errs, fmt.Errorf("%w: %s[%d] = %s", ErrUnlistedPrecompile, chain, i, entry))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,365 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/precompileconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyConstants(t *testing.T) {
+	require.NoError(t, VerifyConstants())
+}
+
+func TestVerifyConstantsDetectsTypo(t *testing.T) {
+	// A leading-significant literal is a valid, unique address but does not
+	// follow the trailing-significant scheme
+	err := verifyConstants([]namedConstant{
+		{"FROSTCChain", "0x5200000000000000000000000000000000000000", "Threshold", "C", 0x00},
+		{"FROSTQChain", FROSTQChain, "Threshold", "Q", 0x00},
+	})
+	require.ErrorContains(t, err, "FROSTCChain")
+	require.NotContains(t, err.Error(), "FROSTQChain")
+
+	// Transposed nibbles
+	err = verifyConstants([]namedConstant{
+		{"MLDSACChain", "0x0000000000000000000000000000000000002300", "PQ", "C", 0x00},
+	})
+	require.ErrorContains(t, err, "MLDSACChain")
+
+	err = verifyConstants([]namedConstant{{"Bogus", LXPool, "Unknown", "C", 0x00}})
+	require.ErrorContains(t, err, "unknown family")
+}
+
+func TestNamedConstantsUnique(t *testing.T) {
+	seen := make(map[common.Address]string, len(namedConstants))
+	for _, nc := range namedConstants {
+		addr := common.HexToAddress(nc.Address)
+		prev, ok := seen[addr]
+		require.Falsef(t, ok, "%s and %s share address %s", prev, nc.Name, addr.Hex())
+		seen[addr] = nc.Name
+	}
+}
+
+func TestValidateRegistry(t *testing.T) {
+	require.NoError(t, ValidateRegistry())
+}
+
+func TestValidateRegistryDetectsCollisions(t *testing.T) {
+	all := []PrecompileInfo{
+		{Address: BLS12381G1AddAddress, Name: "BLS12381_G1ADD"},
+		{Address: MLDSACChain, Name: "ML_DSA"},
+	}
+	constants := []namedConstant{
+		{"MLDSACChain", MLDSACChain, "PQ", "C", 0x00},
+		{"MLDSAQChain", MLDSAQChain, "PQ", "Q", 0x00},
+	}
+
+	tests := []struct {
+		name      string
+		all       []PrecompileInfo
+		chains    map[string][]string
+		constants []namedConstant
+		wantErr   error
+		wantNames []string
+	}{
+		{
+			name:      "valid",
+			all:       all,
+			chains:    map[string][]string{"C": {BLS12381G1AddAddress, MLDSACChain}, "Q": {MLDSAQChain}},
+			constants: constants,
+		},
+		{
+			// The short form of a reused selector is the same address
+			name:      "colliding precompiles",
+			all:       append(all[:2:2], PrecompileInfo{Address: "0xb", Name: "BOGUS"}),
+			constants: constants,
+			wantErr:   ErrAddressCollision,
+			wantNames: []string{"BLS12381_G1ADD", "BOGUS"},
+		},
+		{
+			name:      "colliding constants",
+			all:       all,
+			constants: append(constants[:2:2], namedConstant{"FalconQChain", MLDSAQChain, "PQ", "Q", 0x03}),
+			wantErr:   ErrAddressCollision,
+			wantNames: []string{"MLDSAQChain", "FalconQChain"},
+		},
+		{
+			name:      "chain lists an address twice",
+			all:       all,
+			chains:    map[string][]string{"C": {MLDSACChain, BLS12381G1AddAddress, MLDSACChain}},
+			constants: constants,
+			wantErr:   ErrAddressCollision,
+			wantNames: []string{"C[0]", "C[2]"},
+		},
+		{
+			name:      "chain enables unlisted precompile",
+			all:       all,
+			chains:    map[string][]string{"Q": {MLDSAQChain, FalconQChain}},
+			constants: constants,
+			wantErr:   ErrUnlistedPrecompile,
+			wantNames: []string{"Q[1]", FalconQChain},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRegistry(tt.all, tt.chains, tt.constants)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+			for _, name := range tt.wantNames {
+				require.ErrorContains(t, err, name)
+			}
+		})
+	}
+}
+
+func TestFROSTAddress(t *testing.T) {
+	// Example from the addressing scheme documentation
+	require.Equal(t, common.HexToAddress("0x0000000000000000000000000000000000005200"), common.HexToAddress(FROSTCChain))
+	require.Equal(t, PrecompileAddress(FamilyPage("Threshold"), ChainSlot("C"), 0x00), common.HexToAddress(FROSTCChain))
+}
+
+// upgradeConfig is a minimal precompileconfig.Config driven by an Upgrade
+type upgradeConfig struct {
+	precompileconfig.Upgrade
+	key string
+}
+
+func (c *upgradeConfig) Key() string                               { return c.key }
+func (c *upgradeConfig) Equal(precompileconfig.Config) bool        { return false }
+func (c *upgradeConfig) Verify(precompileconfig.ChainConfig) error { return nil }
+
+func enableAt(key string, ts uint64) *upgradeConfig {
+	return &upgradeConfig{Upgrade: precompileconfig.Upgrade{BlockTimestamp: &ts}, key: key}
+}
+
+func disableAt(key string, ts uint64) *upgradeConfig {
+	return &upgradeConfig{Upgrade: precompileconfig.Upgrade{BlockTimestamp: &ts, Disable: true}, key: key}
+}
+
+func TestEnabledPrecompilesAt(t *testing.T) {
+	lxPool := common.HexToAddress(LXPool)
+	frost := common.HexToAddress(FROSTCChain)
+	mldsa := common.HexToAddress(MLDSACChain)
+
+	// No configs: static enablement
+	require.Equal(t, GetChainPrecompiles("C"), EnabledPrecompilesAt("C", 0, nil))
+	require.Nil(t, EnabledPrecompilesAt("unknown", 0, nil))
+
+	configs := map[string]precompileconfig.Config{
+		"LX_POOL": enableAt("LX_POOL", 100),
+		"FROST":   disableAt("FROST", 200),
+		"ML_DSA":  &upgradeConfig{key: "ML_DSA"}, // nil timestamp: never enabled
+	}
+
+	tests := []struct {
+		timestamp uint64
+		enabled   []common.Address
+		disabled  []common.Address
+	}{
+		{timestamp: 0, enabled: []common.Address{frost}, disabled: []common.Address{lxPool, mldsa}},
+		{timestamp: 99, enabled: []common.Address{frost}, disabled: []common.Address{lxPool, mldsa}},
+		{timestamp: 100, enabled: []common.Address{lxPool, frost}, disabled: []common.Address{mldsa}},
+		{timestamp: 199, enabled: []common.Address{lxPool, frost}, disabled: []common.Address{mldsa}},
+		{timestamp: 200, enabled: []common.Address{lxPool}, disabled: []common.Address{frost, mldsa}},
+	}
+	for _, tt := range tests {
+		got := EnabledPrecompilesAt("C", tt.timestamp, configs)
+		for _, addr := range tt.enabled {
+			require.Containsf(t, got, addr, "timestamp %d", tt.timestamp)
+		}
+		for _, addr := range tt.disabled {
+			require.NotContainsf(t, got, addr, "timestamp %d", tt.timestamp)
+		}
+		// Unconfigured precompiles are unaffected
+		require.Containsf(t, got, common.HexToAddress(Blake3CChain), "timestamp %d", tt.timestamp)
+	}
+
+	// Configs only affect the chains that list the precompile
+	require.NotContains(t, EnabledPrecompilesAt("Q", 1000, configs), lxPool)
+}
+
+func TestPrecompilesByGasTier(t *testing.T) {
+	tiers := PrecompilesByGasTier()
+
+	tierOf := make(map[string]string)
+	total := 0
+	for tier, infos := range tiers {
+		total += len(infos)
+		for _, p := range infos {
+			tierOf[p.Name] = tier
+			require.Equal(t, tier, GasTier(p.GasBase), p.Name)
+		}
+	}
+	require.Equal(t, len(AllPrecompiles), total)
+
+	require.Equal(t, GasTierExpensive, tierOf["FHE"])
+	require.Equal(t, GasTierCheap, tierOf["P256_VERIFY"])
+	require.Equal(t, GasTierModerate, tierOf["ML_DSA"])
+
+	// Boundaries: the moderate tier is inclusive at both ends
+	require.Equal(t, GasTierCheap, GasTier(GasTierModerateMin-1))
+	require.Equal(t, GasTierModerate, GasTier(GasTierModerateMin))
+	require.Equal(t, GasTierModerate, GasTier(GasTierExpensiveMin))
+	require.Equal(t, GasTierExpensive, GasTier(GasTierExpensiveMin+1))
+}
+
+func TestListFunctionsSortedByAddress(t *testing.T) {
+	lessInfo := func(a, b PrecompileInfo) bool {
+		x, y := common.HexToAddress(a.Address), common.HexToAddress(b.Address)
+		return bytes.Compare(x[:], y[:]) <= 0
+	}
+
+	all := ListPrecompiles()
+	require.Len(t, all, len(AllPrecompiles))
+	for i := 1; i < len(all); i++ {
+		require.True(t, lessInfo(all[i-1], all[i]), "%s before %s", all[i-1].Name, all[i].Name)
+	}
+	require.Equal(t, all, ListPrecompiles())
+
+	for _, family := range []string{"PQ", "Crypto", "ZK", "Threshold", "Bridge", "AI"} {
+		infos := GetPrecompilesByFamily(family)
+		require.NotEmpty(t, infos, family)
+		for i := 1; i < len(infos); i++ {
+			require.True(t, lessInfo(infos[i-1], infos[i]), family)
+		}
+		require.Equal(t, infos, GetPrecompilesByFamily(family), family)
+	}
+
+	for tier, infos := range PrecompilesByGasTier() {
+		for i := 1; i < len(infos); i++ {
+			require.True(t, lessInfo(infos[i-1], infos[i]), tier)
+		}
+	}
+
+	for chain := range ChainPrecompiles {
+		addrs := GetChainPrecompiles(chain)
+		for i := 1; i < len(addrs); i++ {
+			require.LessOrEqual(t, bytes.Compare(addrs[i-1][:], addrs[i][:]), 0, chain)
+		}
+		require.Equal(t, addrs, GetChainPrecompiles(chain), chain)
+		require.Equal(t, addrs, EnabledPrecompilesAt(chain, 0, nil), chain)
+	}
+}
+
+func TestGetPrecompileInfoByAddress(t *testing.T) {
+	for _, p := range AllPrecompiles {
+		info, ok := GetPrecompileInfoByAddress(GetPrecompileAddress(p.Name))
+		require.True(t, ok, p.Name)
+		require.Equal(t, p, info)
+	}
+
+	// Short and full-length forms name the same precompile
+	info, ok := GetPrecompileInfoByAddress(common.HexToAddress("0xb"))
+	require.True(t, ok)
+	require.Equal(t, "BLS12381_G1ADD", info.Name)
+
+	_, ok = GetPrecompileInfoByAddress(common.Address{})
+	require.False(t, ok)
+	_, ok = GetPrecompileInfoByAddress(common.HexToAddress("0xdead"))
+	require.False(t, ok)
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{0, 1, 2, 3, 4}
+
+	tests := []struct {
+		offset, limit int
+		want          []int
+	}{
+		{offset: 0, limit: 2, want: []int{0, 1}},
+		{offset: 2, limit: 2, want: []int{2, 3}},
+		{offset: 4, limit: 2, want: []int{4}},
+		{offset: 5, limit: 2, want: []int{}},
+		{offset: 9, limit: 2, want: []int{}},
+		{offset: 1, limit: 0, want: []int{1, 2, 3, 4}},
+		{offset: 1, limit: -1, want: []int{1, 2, 3, 4}},
+		{offset: -3, limit: 1, want: []int{0}},
+	}
+	for _, tt := range tests {
+		require.Equalf(t, tt.want, Paginate(items, tt.offset, tt.limit), "offset %d limit %d", tt.offset, tt.limit)
+	}
+
+	// Appending to a page must not clobber the next one
+	page := Paginate(items, 0, 2)
+	_ = append(page, 99)
+	require.Equal(t, []int{2, 3}, Paginate(items, 2, 2))
+
+	// Walking pages reassembles the full, ordered list
+	all := ListPrecompiles()
+	var walked []PrecompileInfo
+	for offset := 0; offset < len(all); offset += 7 {
+		walked = append(walked, ListPrecompilesPage(offset, 7)...)
+	}
+	require.Equal(t, all, walked)
+
+	zk := GetPrecompilesByFamily("ZK")
+	require.Equal(t, zk[1:3], GetPrecompilesByFamilyPage("ZK", 1, 2))
+	c := GetChainPrecompiles("C")
+	require.Equal(t, c[len(c)-1:], GetChainPrecompilesPage("C", len(c)-1, 10))
+}
+
+func TestGenerateSolidityInterface(t *testing.T) {
+	require := require.New(t)
+
+	src, err := GenerateSolidityInterface("P256_VERIFY")
+	require.NoError(err)
+	require.Contains(src, "interface IP256Verify {")
+	require.Contains(src, "function verify(bytes32 hash, bytes32 r, bytes32 s, bytes32 x, bytes32 y) external view returns (bool valid);")
+	require.Contains(src, P256VerifyAddress)
+
+	src, err = GenerateSolidityInterface("ML_KEM")
+	require.NoError(err)
+	require.Contains(src, "interface IMlKem {")
+	require.Equal(2, strings.Count(src, "    function "))
+
+	_, err = GenerateSolidityInterface("NOT_A_PRECOMPILE")
+	require.ErrorIs(err, ErrUnknownPrecompile)
+	_, err = GenerateSolidityInterface("FROST")
+	require.ErrorIs(err, ErrNoABI)
+}
+
+func TestPrecompileABIsNamed(t *testing.T) {
+	// Every descriptor belongs to a listed precompile
+	names := make(map[string]bool, len(AllPrecompiles))
+	for _, p := range AllPrecompiles {
+		names[p.Name] = true
+	}
+	for name := range precompileABIs {
+		require.True(t, names[name], "ABI for unlisted precompile %s", name)
+	}
+}
+
+func TestSelfTestAll(t *testing.T) {
+	require := require.New(t)
+	register := func(name string, test func() error) {
+		require.NoError(RegisterSelfTest(name, test))
+		t.Cleanup(func() {
+			selfTestsMu.Lock()
+			delete(selfTests, name)
+			selfTestsMu.Unlock()
+		})
+	}
+
+	register("TEST_OK", func() error { return nil })
+	require.NoError(SelfTestAll())
+	require.ErrorIs(RegisterSelfTest("TEST_OK", func() error { return nil }), ErrDuplicateSelfTest)
+
+	// A primitive that returns a wrong answer is reported by name
+	errBroken := errors.New("digest mismatch")
+	register("TEST_BROKEN", func() error { return errBroken })
+	err := SelfTestAll()
+	require.ErrorIs(err, errBroken)
+	require.ErrorContains(err, "TEST_BROKEN self-test")
+	require.NotContains(err.Error(), "TEST_OK")
+}
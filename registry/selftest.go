@@ -0,0 +1,56 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var ErrDuplicateSelfTest = errors.New("self-test already registered")
+
+var (
+	selfTestsMu sync.Mutex
+	selfTests   = make(map[string]func() error)
+)
+
+// RegisterSelfTest registers a precompile's known-answer test under its
+// PrecompileInfo.Name. Crypto precompile packages call it from init, so
+// SelfTestAll covers every such package linked into the binary.
+func RegisterSelfTest(name string, test func() error) error {
+	selfTestsMu.Lock()
+	defer selfTestsMu.Unlock()
+
+	if _, exists := selfTests[name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateSelfTest, name)
+	}
+	selfTests[name] = test
+	return nil
+}
+
+// SelfTestAll runs every registered self-test and returns an error listing
+// each precompile whose underlying library produced a wrong answer. Nodes
+// call it at startup, before serving traffic, to catch a broken or
+// misconfigured crypto dependency.
+func SelfTestAll() error {
+	selfTestsMu.Lock()
+	names := make([]string, 0, len(selfTests))
+	tests := make(map[string]func() error, len(selfTests))
+	for name, test := range selfTests {
+		names = append(names, name)
+		tests[name] = test
+	}
+	selfTestsMu.Unlock()
+
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		if err := tests[name](); err != nil {
+			errs = append(errs, fmt.Errorf("%s self-test: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -24,6 +24,12 @@ const (
 	// InputLength is the required input length (160 bytes)
 	// 32 (hash) + 32 (r) + 32 (s) + 32 (x) + 32 (y)
 	InputLength = 160
+
+	// CompactInputLength is the compact input length (128 bytes)
+	// 32 (hash) + 32 (r) + 32 (yParity|s) + 32 (x)
+	// The high bit of the s word carries the parity of the public key's
+	// y-coordinate (EIP-2098 style), so y is recovered from x.
+	CompactInputLength = 128
 )
 
 var (
@@ -59,22 +65,37 @@ func (c *Contract) RequiredGas(input []byte) uint64 {
 //   - bytes 96-127: x (public key x-coordinate)
 //   - bytes 128-159: y (public key y-coordinate)
 //
+// Compact input format (128 bytes):
+//   - bytes  0-31: message hash
+//   - bytes 32-63: r (signature component)
+//   - bytes 64-95: s with the y-parity of the public key in the high bit
+//   - bytes 96-127: x (public key x-coordinate)
+//
 // Output:
 //   - Success: 32 bytes with value 1
 //   - Failure: empty bytes (invalid signature or point not on curve)
 func (c *Contract) Run(input []byte) ([]byte, error) {
-	if len(input) != InputLength {
+	var hash []byte
+	var r, s, x, y *big.Int
+
+	switch len(input) {
+	case InputLength:
+		hash = input[0:32]
+		r = new(big.Int).SetBytes(input[32:64])
+		s = new(big.Int).SetBytes(input[64:96])
+		x = new(big.Int).SetBytes(input[96:128])
+		y = new(big.Int).SetBytes(input[128:160])
+	case CompactInputLength:
+		var ok bool
+		hash, r, s, x, y, ok = decodeCompact(input)
+		if !ok {
+			return nil, nil
+		}
+	default:
 		// Invalid input length returns empty (not error)
 		return nil, nil
 	}
 
-	// Extract components
-	hash := input[0:32]
-	r := new(big.Int).SetBytes(input[32:64])
-	s := new(big.Int).SetBytes(input[64:96])
-	x := new(big.Int).SetBytes(input[96:128])
-	y := new(big.Int).SetBytes(input[128:160])
-
 	// Get P-256 curve
 	curve := elliptic.P256()
 
@@ -107,6 +128,28 @@ func (c *Contract) Run(input []byte) ([]byte, error) {
 	return nil, nil
 }
 
+// decodeCompact splits a 128-byte compact input and recovers the public
+// key's y-coordinate from x and the parity bit packed into s
+func decodeCompact(input []byte) (hash []byte, r, s, x, y *big.Int, ok bool) {
+	hash = input[0:32]
+	r = new(big.Int).SetBytes(input[32:64])
+
+	var sBytes [32]byte
+	copy(sBytes[:], input[64:96])
+	yParity := sBytes[0] >> 7
+	sBytes[0] &= 0x7f
+	s = new(big.Int).SetBytes(sBytes[:])
+
+	compressed := make([]byte, 33)
+	compressed[0] = 0x02 | yParity
+	copy(compressed[1:], input[96:128])
+	x, y = elliptic.UnmarshalCompressed(elliptic.P256(), compressed)
+	if x == nil {
+		return nil, nil, nil, nil, nil, false
+	}
+	return hash, r, s, x, y, true
+}
+
 // Name returns the precompile name
 func (c *Contract) Name() string {
 	return "P256VERIFY"
@@ -258,6 +258,57 @@ func TestContract_NISTTestVector(t *testing.T) {
 }
 
 // Benchmark tests
+func TestContract_CompactSignature(t *testing.T) {
+	c := &Contract{}
+	n := elliptic.P256().Params().N
+	halfN := new(big.Int).Rsh(n, 1)
+
+	for i := 0; i < 8; i++ {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		hash := sha256.Sum256([]byte("compact secp256r1"))
+		r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
+		require.NoError(t, err)
+
+		// Compact form requires low-s; (r, n-s) is equally valid
+		if s.Cmp(halfN) > 0 {
+			s.Sub(n, s)
+		}
+
+		full, err := c.Run(buildInput(hash[:], r, s, privateKey.PublicKey.X, privateKey.PublicKey.Y))
+		require.NoError(t, err)
+		require.Equal(t, successResult, full)
+
+		compact, err := c.Run(buildCompactInput(hash[:], r, s, privateKey.PublicKey.X, privateKey.PublicKey.Y))
+		require.NoError(t, err)
+		require.Equal(t, full, compact)
+
+		// Flipping the parity bit selects the negated key and fails
+		flipped := buildCompactInput(hash[:], r, s, privateKey.PublicKey.X, privateKey.PublicKey.Y)
+		flipped[64] ^= 0x80
+		result, err := c.Run(flipped)
+		require.NoError(t, err)
+		require.Empty(t, result)
+	}
+}
+
+func TestContract_CompactInvalidX(t *testing.T) {
+	c := &Contract{}
+
+	// x >= p is not a valid field element
+	input := make([]byte, CompactInputLength)
+	input[63] = 1
+	input[95] = 1
+	for i := 96; i < 128; i++ {
+		input[i] = 0xff
+	}
+
+	result, err := c.Run(input)
+	require.NoError(t, err)
+	require.Empty(t, result)
+}
+
 func BenchmarkContract_Run(b *testing.B) {
 	c := &Contract{}
 
@@ -306,3 +357,14 @@ func buildInput(hash []byte, r, s, x, y *big.Int) []byte {
 
 	return input
 }
+
+// Helper function to build compact precompile input
+func buildCompactInput(hash []byte, r, s, x, y *big.Int) []byte {
+	input := make([]byte, CompactInputLength)
+	copy(input[0:32], common.LeftPadBytes(hash, 32))
+	copy(input[32:64], common.LeftPadBytes(r.Bytes(), 32))
+	copy(input[64:96], common.LeftPadBytes(s.Bytes(), 32))
+	input[64] |= byte(y.Bit(0)) << 7
+	copy(input[96:128], common.LeftPadBytes(x.Bytes(), 32))
+	return input
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: MIT
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+
+package secp256r1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/luxfi/precompile/registry"
+)
+
+// RFC 6979 A.2.5: P-256 key and the deterministic SHA-256 signature of
+// "sample"
+const (
+	selfTestX = "60fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6"
+	selfTestY = "7903fe1008b8bc99a41ae9e95628bc64f2f1b20c2d7e9f5177a3c294d4462299"
+	selfTestR = "efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716"
+	selfTestS = "f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8"
+)
+
+func init() {
+	if err := registry.RegisterSelfTest("P256_VERIFY", SelfTest); err != nil {
+		panic(err)
+	}
+}
+
+// SelfTest runs the RFC 6979 P-256 vector through the precompile and fails
+// unless it verifies and the same signature over another hash does not
+func SelfTest() error {
+	c := &Contract{}
+	return selfTest(c.Run)
+}
+
+func selfTest(run func(input []byte) ([]byte, error)) error {
+	hash := sha256.Sum256([]byte("sample"))
+	input := make([]byte, 0, InputLength)
+	input = append(input, hash[:]...)
+	for _, word := range []string{selfTestR, selfTestS, selfTestX, selfTestY} {
+		b, _ := hex.DecodeString(word)
+		input = append(input, b...)
+	}
+
+	if out, err := run(input); err != nil || !bytes.Equal(out, successResult) {
+		return errors.New("P-256 known-answer signature rejected")
+	}
+	input[0] ^= 0x01
+	if out, err := run(input); err != nil || len(out) != 0 {
+		return errors.New("P-256 signature accepted for a different hash")
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+
+package secp256r1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, SelfTest())
+}
+
+func TestSelfTestDetectsBrokenVerify(t *testing.T) {
+	acceptAll := func([]byte) ([]byte, error) { return successResult, nil }
+	require.ErrorContains(t, selfTest(acceptAll), "different hash")
+
+	rejectAll := func([]byte) ([]byte, error) { return nil, nil }
+	require.ErrorContains(t, selfTest(rejectAll), "rejected")
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: MIT
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+
+package secp256r1
+
+import (
+	"crypto/sha256"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+)
+
+// ModeDomainSeparated, prepended to a 160- or 128-byte input, verifies the
+// signature over sha256(contract.DomainSeparatedMessage(chainID, precompile,
+// hash)) instead of the raw hash, binding it to this chain and precompile.
+// Only the stateful interface knows the chain, so Contract.Run treats the
+// longer input as malformed.
+const ModeDomainSeparated byte = 0x80
+
+var (
+	// StatefulPrecompile is the P256VERIFY precompile behind the stateful
+	// interface, for registration alongside the other precompiles
+	StatefulPrecompile = &StatefulContract{}
+
+	_ contract.StatefulPrecompiledContract = &StatefulContract{}
+)
+
+// StatefulContract adapts Contract to contract.StatefulPrecompiledContract.
+// It charges P256VerifyGas up front and keeps the EIP-7212 result encoding:
+// malformed input or an invalid signature returns empty output, not an
+// error. Input rejected on its length alone, before any curve work, gets
+// back contract.LengthRejectionRefundPercent of the charge.
+type StatefulContract struct {
+	Contract
+}
+
+// Run charges gas and delegates to Contract.Run
+func (c *StatefulContract) Run(
+	accessibleState contract.AccessibleState,
+	caller common.Address,
+	addr common.Address,
+	input []byte,
+	suppliedGas uint64,
+	readOnly bool,
+) ([]byte, uint64, error) {
+	// Gas is charged before any length check so malformed input cannot be
+	// used to probe the precompile for free
+	gasCost := c.RequiredGas(input)
+	remainingGas, err := contract.DeductGas(suppliedGas, gasCost)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case len(input) == InputLength, len(input) == CompactInputLength:
+	case isDomainSeparated(input):
+		chainID, err := contract.ChainID(accessibleState)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		input = common.CopyBytes(input[1:])
+		hash := sha256.Sum256(contract.DomainSeparatedMessage(chainID, addr, input[:32]))
+		copy(input[:32], hash[:])
+	default:
+		// Empty, short and oversized input: EIP-7212 returns empty output
+		return nil, contract.RefundLengthRejection(remainingGas, gasCost), nil
+	}
+
+	result, err := c.Contract.Run(input)
+	return result, remainingGas, err
+}
+
+// isDomainSeparated reports whether input is a standard or compact input
+// behind the ModeDomainSeparated prefix
+func isDomainSeparated(input []byte) bool {
+	if len(input) != InputLength+1 && len(input) != CompactInputLength+1 {
+		return false
+	}
+	return input[0] == ModeDomainSeparated
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: MIT
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+
+package secp256r1
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/precompileconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func signedInput(t *testing.T) []byte {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	hash := sha256.Sum256([]byte("Hello, secp256r1!"))
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
+	require.NoError(t, err)
+
+	return buildInput(hash[:], r, s, privateKey.PublicKey.X, privateKey.PublicKey.Y)
+}
+
+// lengthRejectionGas is what a call rejected on input length is charged
+const lengthRejectionGas = P256VerifyGas - P256VerifyGas*contract.LengthRejectionRefundPercent/100
+
+func runStateful(input []byte, gas uint64) ([]byte, uint64, error) {
+	return StatefulPrecompile.Run(nil, common.Address{}, Address, input, gas, true)
+}
+
+func TestStatefulContract_ValidSignature(t *testing.T) {
+	require.Equal(t, Address, StatefulPrecompile.Address())
+
+	result, remainingGas, err := runStateful(signedInput(t), 10_000)
+	require.NoError(t, err)
+	require.Equal(t, successResult, result)
+	require.Equal(t, uint64(10_000-P256VerifyGas), remainingGas)
+}
+
+func TestStatefulContract_InvalidSignature(t *testing.T) {
+	input := signedInput(t)
+	input[0] ^= 0xff
+
+	result, remainingGas, err := runStateful(input, P256VerifyGas)
+	require.NoError(t, err)
+	require.Empty(t, result)
+	require.Zero(t, remainingGas)
+
+	// A well-formed input that fails verification costs the full gas, while
+	// one rejected on length alone gets the partial refund
+	result, remainingGas, err = runStateful(input, 10_000)
+	require.NoError(t, err)
+	require.Empty(t, result)
+	require.Equal(t, uint64(10_000-P256VerifyGas), remainingGas)
+
+	result, remainingGas, err = runStateful(input[:InputLength-1], 10_000)
+	require.NoError(t, err)
+	require.Empty(t, result)
+	require.Equal(t, uint64(10_000-lengthRejectionGas), remainingGas)
+}
+
+func TestStatefulContract_OutOfGas(t *testing.T) {
+	_, remainingGas, err := runStateful(signedInput(t), P256VerifyGas-1)
+	require.ErrorIs(t, err, contract.ErrOutOfGas)
+	require.Zero(t, remainingGas)
+}
+
+func TestStatefulContract_WrongLengthInput(t *testing.T) {
+	valid := signedInput(t)
+	inputs := map[string][]byte{
+		"empty":              nil,
+		"one byte":           {0x01},
+		"short":              valid[:InputLength-1],
+		"between lengths":    valid[:CompactInputLength+1],
+		"long":               append(common.CopyBytes(valid), 0x00),
+		"oversized":          make([]byte, 64*1024),
+		"prefixed oversized": append([]byte{ModeDomainSeparated}, make([]byte, InputLength+1)...),
+	}
+	for name, input := range inputs {
+		require.Equal(t, uint64(P256VerifyGas), StatefulPrecompile.RequiredGas(input), name)
+
+		// Length-only rejections are refunded part of the charge
+		result, remainingGas, err := runStateful(input, 10_000)
+		require.NoError(t, err, name)
+		require.Empty(t, result, name)
+		require.Equal(t, uint64(10_000-lengthRejectionGas), remainingGas, name)
+
+		// Not enough gas for a full run fails before the input is looked at
+		_, remainingGas, err = runStateful(input, P256VerifyGas-1)
+		require.ErrorIs(t, err, contract.ErrOutOfGas, name)
+		require.Zero(t, remainingGas, name)
+	}
+}
+
+// chainState is an AccessibleState that only exposes a chain id
+type chainState struct {
+	chainID *big.Int
+}
+
+func (s *chainState) GetStateDB() contract.StateDB                     { return nil }
+func (s *chainState) GetBlockContext() contract.BlockContext           { return nil }
+func (s *chainState) GetConsensusContext() context.Context             { return context.Background() }
+func (s *chainState) GetChainConfig() precompileconfig.ChainConfig     { return chainConfig{s.chainID} }
+func (s *chainState) GetPrecompileEnv() contract.PrecompileEnvironment { return nil }
+
+type chainConfig struct {
+	chainID *big.Int
+}
+
+func (c chainConfig) IsDurango(uint64) bool { return true }
+func (c chainConfig) GetChainID() *big.Int  { return c.chainID }
+
+func TestStatefulContract_DomainSeparated(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	chainID := big.NewInt(96369)
+	hash := sha256.Sum256([]byte("Hello, secp256r1!"))
+	signed := sha256.Sum256(contract.DomainSeparatedMessage(chainID, Address, hash[:]))
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, signed[:])
+	require.NoError(t, err)
+
+	raw := buildInput(hash[:], r, s, privateKey.PublicKey.X, privateKey.PublicKey.Y)
+	compact := buildCompactInput(hash[:], r, s, privateKey.PublicKey.X, privateKey.PublicKey.Y)
+	for _, input := range [][]byte{raw, compact} {
+		separated := append([]byte{ModeDomainSeparated}, input...)
+
+		result, _, err := StatefulPrecompile.Run(&chainState{chainID}, common.Address{}, Address, separated, P256VerifyGas, true)
+		require.NoError(t, err)
+		require.Equal(t, successResult, result)
+
+		// Different chain id
+		result, _, err = StatefulPrecompile.Run(&chainState{big.NewInt(96368)}, common.Address{}, Address, separated, P256VerifyGas, true)
+		require.NoError(t, err)
+		require.Empty(t, result)
+
+		// The raw hash was not signed
+		result, _, err = StatefulPrecompile.Run(&chainState{chainID}, common.Address{}, Address, input, P256VerifyGas, true)
+		require.NoError(t, err)
+		require.Empty(t, result)
+
+		// No chain id to bind to
+		_, _, err = runStateful(separated, P256VerifyGas)
+		require.ErrorIs(t, err, contract.ErrChainIDUnavailable)
+	}
+}
@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: MIT
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+
+package secp256r1
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/luxfi/geth/common"
+)
+
+const (
+	// WebAuthnVerifyAddress is the precompile address for WebAuthn assertion
+	// verification (Curves range 0x0A00)
+	WebAuthnVerifyAddress = "0x0A00000000000000000000000000000000000002"
+
+	// WebAuthnBaseGas covers the P-256 verification and two SHA-256 calls
+	WebAuthnBaseGas = P256VerifyGas + 2*60
+	// WebAuthnPerWordGas is charged per 32-byte word of authenticator and
+	// client data hashed
+	WebAuthnPerWordGas = 12
+
+	// WebAuthnHeaderLength is the fixed prefix of a WebAuthn input:
+	// 32 (challenge) + 32 (r) + 32 (s) + 32 (x) + 32 (y) + 2 (authenticatorData length)
+	WebAuthnHeaderLength = 162
+
+	// authenticatorData is rpIdHash (32) || flags (1) || signCount (4) || ...
+	minAuthenticatorDataLength = 37
+	flagsOffset                = 32
+	flagUserPresent            = 0x01
+
+	webAuthnGetType = "webauthn.get"
+)
+
+var (
+	// WebAuthnAddress is the WebAuthn precompile address as common.Address
+	WebAuthnAddress = common.HexToAddress(WebAuthnVerifyAddress)
+
+	ErrInvalidAuthenticatorData = errors.New("secp256r1: invalid authenticator data")
+	ErrUserNotPresent           = errors.New("secp256r1: user presence flag not set")
+	ErrInvalidClientData        = errors.New("secp256r1: invalid client data JSON")
+	ErrWrongClientDataType      = errors.New("secp256r1: client data type is not webauthn.get")
+	ErrChallengeMismatch        = errors.New("secp256r1: challenge mismatch")
+)
+
+// clientData holds the clientDataJSON fields an assertion check needs
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+// VerifyWebAuthn verifies a WebAuthn assertion. The authenticator signs
+// authenticatorData || sha256(clientDataJSON), not a caller-supplied hash,
+// so the message is rebuilt here after checking that clientDataJSON is a
+// webauthn.get for the expected challenge and that the user was present.
+func VerifyWebAuthn(authenticatorData, clientDataJSON, challenge []byte, r, s, x, y *big.Int) bool {
+	hash, err := webAuthnMessageHash(authenticatorData, clientDataJSON, challenge)
+	if err != nil {
+		return false
+	}
+	return Verify(hash, r, s, x, y)
+}
+
+// webAuthnMessageHash validates the assertion data and returns the hash the
+// authenticator signed
+func webAuthnMessageHash(authenticatorData, clientDataJSON, challenge []byte) ([]byte, error) {
+	if len(authenticatorData) < minAuthenticatorDataLength {
+		return nil, ErrInvalidAuthenticatorData
+	}
+	if authenticatorData[flagsOffset]&flagUserPresent == 0 {
+		return nil, ErrUserNotPresent
+	}
+
+	var cd clientData
+	if err := json.Unmarshal(clientDataJSON, &cd); err != nil {
+		return nil, ErrInvalidClientData
+	}
+	if cd.Type != webAuthnGetType {
+		return nil, ErrWrongClientDataType
+	}
+	// The challenge is base64url without padding (WebAuthn §5.8.1)
+	if cd.Challenge != base64.RawURLEncoding.EncodeToString(challenge) {
+		return nil, ErrChallengeMismatch
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := make([]byte, 0, len(authenticatorData)+len(clientDataHash))
+	signed = append(signed, authenticatorData...)
+	signed = append(signed, clientDataHash[:]...)
+	hash := sha256.Sum256(signed)
+	return hash[:], nil
+}
+
+// WebAuthnContract implements the WebAuthn assertion verification precompile
+type WebAuthnContract struct{}
+
+// Address returns the precompile address
+func (c *WebAuthnContract) Address() common.Address {
+	return WebAuthnAddress
+}
+
+// RequiredGas charges the P-256 verification plus hashing of the variable
+// authenticator and client data
+func (c *WebAuthnContract) RequiredGas(input []byte) uint64 {
+	variable := 0
+	if len(input) > WebAuthnHeaderLength {
+		variable = len(input) - WebAuthnHeaderLength
+	}
+	return WebAuthnBaseGas + uint64((variable+31)/32)*WebAuthnPerWordGas
+}
+
+// Name returns the precompile name
+func (c *WebAuthnContract) Name() string {
+	return "WEBAUTHNVERIFY"
+}
+
+// Run executes WebAuthn assertion verification
+//
+// Input format:
+//   - bytes   0-31: expected challenge
+//   - bytes  32-63: r (signature component)
+//   - bytes  64-95: s (signature component)
+//   - bytes 96-127: x (public key x-coordinate)
+//   - bytes 128-159: y (public key y-coordinate)
+//   - bytes 160-161: authenticatorData length (big-endian uint16)
+//   - authenticatorData
+//   - clientDataJSON (remaining bytes)
+//
+// Output matches P256VERIFY: 32 bytes with value 1 on success, empty bytes
+// on any failure.
+func (c *WebAuthnContract) Run(input []byte) ([]byte, error) {
+	if len(input) < WebAuthnHeaderLength {
+		return nil, nil
+	}
+	authLen := int(binary.BigEndian.Uint16(input[160:162]))
+	if len(input) < WebAuthnHeaderLength+authLen {
+		return nil, nil
+	}
+
+	challenge := input[0:32]
+	r := new(big.Int).SetBytes(input[32:64])
+	s := new(big.Int).SetBytes(input[64:96])
+	x := new(big.Int).SetBytes(input[96:128])
+	y := new(big.Int).SetBytes(input[128:160])
+	authenticatorData := input[WebAuthnHeaderLength : WebAuthnHeaderLength+authLen]
+	clientDataJSON := input[WebAuthnHeaderLength+authLen:]
+
+	if VerifyWebAuthn(authenticatorData, clientDataJSON, challenge, r, s, x, y) {
+		return successResult, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: MIT
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+
+package secp256r1
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// Fixed assertion from a software authenticator for RP "app.lux.network":
+// flags UP|UV, signCount 42.
+const (
+	assertionX          = "cb34623eeb9872b66dfbf3a0f6ac9415d8b9fb749915165fc4ae81514ca3bc71"
+	assertionY          = "61f62504e16cfbee7e3db5244a59b8f566ebfa470272bd38ad04de22002bac5f"
+	assertionR          = "ff221cc998f462755caec507ecb498747921e98f9daef13a6ee98ac4d8ebae40"
+	assertionS          = "8b6aff3b5db84e54197136c380f4636301dd88af2b0b800feccc5863d62689eb"
+	assertionAuthData   = "1e683a1c939e06db9e4d6767daf40bbfdc91477637af96c72ef77a2df0a3c59a050000002a"
+	assertionChallenge  = "057c5276c3987833612177ea5c9e7a6b5957dfec3205b6516c56fa233cb3753d"
+	assertionClientData = `{"type":"webauthn.get","challenge":"BXxSdsOYeDNhIXfqXJ56a1lX3-wyBbZRbFb6IzyzdT0",` +
+		`"origin":"https://app.lux.network","crossOrigin":false}`
+)
+
+type webAuthnAssertion struct {
+	authenticatorData []byte
+	clientDataJSON    []byte
+	challenge         []byte
+	r, s, x, y        *big.Int
+}
+
+func hexInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 16)
+	require.True(t, ok)
+	return v
+}
+
+func hexBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func capturedAssertion(t *testing.T) *webAuthnAssertion {
+	return &webAuthnAssertion{
+		authenticatorData: hexBytes(t, assertionAuthData),
+		clientDataJSON:    []byte(assertionClientData),
+		challenge:         hexBytes(t, assertionChallenge),
+		r:                 hexInt(t, assertionR),
+		s:                 hexInt(t, assertionS),
+		x:                 hexInt(t, assertionX),
+		y:                 hexInt(t, assertionY),
+	}
+}
+
+// signAssertion signs authenticatorData || sha256(clientDataJSON) as an
+// authenticator would
+func signAssertion(t *testing.T, authenticatorData []byte, clientDataJSON string, challenge []byte) *webAuthnAssertion {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	clientDataHash := sha256.Sum256([]byte(clientDataJSON))
+	hash := sha256.Sum256(append(append([]byte{}, authenticatorData...), clientDataHash[:]...))
+	r, s, err := ecdsa.Sign(rand.Reader, key, hash[:])
+	require.NoError(t, err)
+
+	return &webAuthnAssertion{
+		authenticatorData: authenticatorData,
+		clientDataJSON:    []byte(clientDataJSON),
+		challenge:         challenge,
+		r:                 r,
+		s:                 s,
+		x:                 key.PublicKey.X,
+		y:                 key.PublicKey.Y,
+	}
+}
+
+func (a *webAuthnAssertion) verify() bool {
+	return VerifyWebAuthn(a.authenticatorData, a.clientDataJSON, a.challenge, a.r, a.s, a.x, a.y)
+}
+
+func (a *webAuthnAssertion) input() []byte {
+	input := make([]byte, WebAuthnHeaderLength)
+	copy(input[0:32], a.challenge)
+	a.r.FillBytes(input[32:64])
+	a.s.FillBytes(input[64:96])
+	a.x.FillBytes(input[96:128])
+	a.y.FillBytes(input[128:160])
+	input[160] = byte(len(a.authenticatorData) >> 8)
+	input[161] = byte(len(a.authenticatorData))
+	input = append(input, a.authenticatorData...)
+	return append(input, a.clientDataJSON...)
+}
+
+func TestVerifyWebAuthn_CapturedAssertion(t *testing.T) {
+	a := capturedAssertion(t)
+	require.True(t, a.verify())
+
+	// The signature is over the WebAuthn message, not a raw hash of the data
+	clientDataHash := sha256.Sum256(a.clientDataJSON)
+	require.False(t, Verify(clientDataHash[:], a.r, a.s, a.x, a.y))
+}
+
+func TestVerifyWebAuthn_Rejections(t *testing.T) {
+	a := capturedAssertion(t)
+
+	// Expected challenge differs from the one the client signed
+	other := *a
+	other.challenge = make([]byte, 32)
+	require.False(t, other.verify())
+	_, err := webAuthnMessageHash(other.authenticatorData, other.clientDataJSON, other.challenge)
+	require.ErrorIs(t, err, ErrChallengeMismatch)
+
+	// Tampered signature counter
+	tampered := *a
+	tampered.authenticatorData = common.CopyBytes(a.authenticatorData)
+	tampered.authenticatorData[36]++
+	require.False(t, tampered.verify())
+
+	// Tampered origin
+	tampered = *a
+	tampered.clientDataJSON = []byte(`{"type":"webauthn.get","challenge":"BXxSdsOYeDNhIXfqXJ56a1lX3-wyBbZRbFb6IzyzdT0",` +
+		`"origin":"https://evil.example","crossOrigin":false}`)
+	require.False(t, tampered.verify())
+
+	// Truncated authenticator data
+	_, err = webAuthnMessageHash(a.authenticatorData[:36], a.clientDataJSON, a.challenge)
+	require.ErrorIs(t, err, ErrInvalidAuthenticatorData)
+
+	_, err = webAuthnMessageHash(a.authenticatorData, []byte("not json"), a.challenge)
+	require.ErrorIs(t, err, ErrInvalidClientData)
+}
+
+func TestVerifyWebAuthn_ClientDataChecks(t *testing.T) {
+	challenge := sha256.Sum256([]byte("fresh challenge"))
+	encoded := base64.RawURLEncoding.EncodeToString(challenge[:])
+	rpIDHash := sha256.Sum256([]byte("app.lux.network"))
+	authData := append(rpIDHash[:], flagUserPresent, 0, 0, 0, 1)
+
+	a := signAssertion(t, authData, fmt.Sprintf(`{"type":"webauthn.get","challenge":"%s"}`, encoded), challenge[:])
+	require.True(t, a.verify())
+
+	// A registration ceremony is not an assertion
+	a = signAssertion(t, authData, fmt.Sprintf(`{"type":"webauthn.create","challenge":"%s"}`, encoded), challenge[:])
+	require.False(t, a.verify())
+	_, err := webAuthnMessageHash(a.authenticatorData, a.clientDataJSON, a.challenge)
+	require.ErrorIs(t, err, ErrWrongClientDataType)
+
+	// Padded base64 is not the WebAuthn encoding
+	padded := base64.URLEncoding.EncodeToString(challenge[:])
+	a = signAssertion(t, authData, fmt.Sprintf(`{"type":"webauthn.get","challenge":"%s"}`, padded), challenge[:])
+	require.False(t, a.verify())
+
+	// User presence is required
+	noUP := append(rpIDHash[:], 0x04, 0, 0, 0, 1)
+	a = signAssertion(t, noUP, fmt.Sprintf(`{"type":"webauthn.get","challenge":"%s"}`, encoded), challenge[:])
+	require.False(t, a.verify())
+	_, err = webAuthnMessageHash(a.authenticatorData, a.clientDataJSON, a.challenge)
+	require.ErrorIs(t, err, ErrUserNotPresent)
+}
+
+func TestWebAuthnContract_Run(t *testing.T) {
+	c := &WebAuthnContract{}
+	require.Equal(t, common.HexToAddress(WebAuthnVerifyAddress), c.Address())
+	require.Equal(t, "WEBAUTHNVERIFY", c.Name())
+
+	a := capturedAssertion(t)
+	input := a.input()
+	result, err := c.Run(input)
+	require.NoError(t, err)
+	require.Equal(t, successResult, result)
+
+	variable := len(a.authenticatorData) + len(a.clientDataJSON)
+	require.Equal(t, uint64(WebAuthnBaseGas+(variable+31)/32*WebAuthnPerWordGas), c.RequiredGas(input))
+
+	// Wrong expected challenge
+	wrong := common.CopyBytes(input)
+	wrong[0] ^= 0xFF
+	result, err = c.Run(wrong)
+	require.NoError(t, err)
+	require.Empty(t, result)
+
+	// authenticatorData length past the end of input
+	short := common.CopyBytes(input[:WebAuthnHeaderLength+10])
+	result, err = c.Run(short)
+	require.NoError(t, err)
+	require.Empty(t, result)
+
+	result, err = c.Run(input[:WebAuthnHeaderLength-1])
+	require.NoError(t, err)
+	require.Empty(t, result)
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slhdsa
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/luxfi/crypto/slhdsa"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+)
+
+// ModeBatch may be OR'd into the mode byte to verify many signatures of the
+// same parameter set in one call. It combines with ModeDomainSeparated.
+const ModeBatch uint8 = 0x40
+
+// CountSize is the size of the batch count field (uint16)
+const CountSize = 2
+
+// SLHDSABatchBaseGas is charged once per batch call, so an empty batch still
+// pays for parsing its header
+const SLHDSABatchBaseGas uint64 = 5_000
+
+// batchEntry is one parsed (public key, message, signature) tuple
+type batchEntry struct {
+	publicKey []byte
+	message   []byte
+	signature []byte
+}
+
+// parseBatch splits a batch body into tuples. Each tuple uses the single
+// verification layout without the mode byte:
+//
+//	[pubKeyLen(2)][pubKey][msgLen(2)][message][signature]
+//
+// Messages over maxMessageSize are rejected.
+func parseBatch(body []byte, count, pubKeySize, sigSize int, maxMessageSize uint64) ([]batchEntry, error) {
+	entries := make([]batchEntry, 0, count)
+	for i := 0; i < count; i++ {
+		if len(body) < PubKeyLenSize {
+			return nil, fmt.Errorf("%w: entry %d truncated", ErrInvalidInputLength, i)
+		}
+		pubKeyLen := int(binary.BigEndian.Uint16(body[:PubKeyLenSize]))
+		if pubKeyLen != pubKeySize {
+			return nil, fmt.Errorf("%w: entry %d: expected pubkey size %d, got %d", ErrInvalidInputLength, i, pubKeySize, pubKeyLen)
+		}
+		msgLenStart := PubKeyLenSize + pubKeyLen
+		if len(body) < msgLenStart+MessageLenSize {
+			return nil, fmt.Errorf("%w: entry %d truncated", ErrInvalidInputLength, i)
+		}
+		msgStart := msgLenStart + MessageLenSize
+		msgLen := binary.BigEndian.Uint16(body[msgLenStart:msgStart])
+		if err := contract.CheckMessageSize(uint64(msgLen), maxMessageSize); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+		msgEnd := msgStart + int(msgLen)
+		sigEnd := msgEnd + sigSize
+		if len(body) < sigEnd {
+			return nil, fmt.Errorf("%w: entry %d truncated", ErrInvalidInputLength, i)
+		}
+		entries = append(entries, batchEntry{
+			publicKey: body[PubKeyLenSize:msgLenStart],
+			message:   body[msgStart:msgEnd],
+			signature: body[msgEnd:sigEnd],
+		})
+		body = body[sigEnd:]
+	}
+	if len(body) != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes after %d entries", ErrInvalidInputLength, len(body), count)
+	}
+	return entries, nil
+}
+
+// batchRequiredGas charges SLHDSABatchBaseGas plus the mode's base gas per
+// signature and the per-byte message gas over every message. Malformed input
+// is charged the batch and per-signature base gas for the declared count.
+func batchRequiredGas(input []byte) uint64 {
+	if len(input) < ModeByte+CountSize {
+		return SLHDSADefaultGas
+	}
+	mode := input[0] &^ (ModeDomainSeparated | ModeBatch)
+	pubKeySize, sigSize, baseGas, _, err := getModeParams(mode)
+	if err != nil {
+		return SLHDSADefaultGas
+	}
+	count := int(binary.BigEndian.Uint16(input[ModeByte : ModeByte+CountSize]))
+	gas := SLHDSABatchBaseGas + uint64(count)*baseGas
+
+	// Gas does not depend on the chain's message size limit: oversized
+	// messages are charged here and rejected by runBatch
+	entries, err := parseBatch(input[ModeByte+CountSize:], count, pubKeySize, sigSize, math.MaxUint16)
+	if err != nil {
+		return gas
+	}
+	for _, e := range entries {
+		gas += uint64(len(e.message)) * SLHDSAVerifyPerByteGas
+	}
+	return gas
+}
+
+// runBatch verifies every tuple of a batch input.
+// Input format:
+//
+//	[0]      = mode byte | ModeBatch (optionally | ModeDomainSeparated)
+//	[1:3]    = count as uint16
+//	[3:...]  = count tuples of [pubKeyLen(2)][pubKey][msgLen(2)][message][signature]
+//
+// Output: bitmap of ceil(count/8) bytes; bit i%8 of byte i/8 is set when
+// signature i is valid
+func runBatch(accessibleState contract.AccessibleState, addr common.Address, input []byte) ([]byte, error) {
+	if len(input) < ModeByte+CountSize {
+		return nil, fmt.Errorf("%w: need at least %d bytes", ErrInvalidInputLength, ModeByte+CountSize)
+	}
+
+	mode := input[0] &^ (ModeDomainSeparated | ModeBatch)
+	domainSeparated := input[0]&ModeDomainSeparated != 0
+	pubKeySize, sigSize, _, slhdsaMode, err := getModeParams(mode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrUnsupportedMode, mode)
+	}
+
+	count := int(binary.BigEndian.Uint16(input[ModeByte : ModeByte+CountSize]))
+	entries, err := parseBatch(input[ModeByte+CountSize:], count, pubKeySize, sigSize, contract.MessageSizeLimit(accessibleState))
+	if err != nil {
+		return nil, err
+	}
+
+	var domain []byte
+	if domainSeparated {
+		chainID, err := contract.ChainID(accessibleState)
+		if err != nil {
+			return nil, err
+		}
+		domain = contract.DomainSeparatedMessage(chainID, addr, nil)
+	}
+
+	// SLH-DSA has no aggregation; verify the tuples in parallel. Each
+	// worker writes only its own slot, so the result is deterministic.
+	valid := make([]bool, len(entries))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(entries)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				e := entries[i]
+				pub, err := slhdsa.PublicKeyFromBytes(e.publicKey, slhdsaMode)
+				if err != nil {
+					continue
+				}
+				message := e.message
+				if domain != nil {
+					message = append(common.CopyBytes(domain), message...)
+				}
+				valid[i] = pub.Verify(message, e.signature, nil)
+			}
+		}()
+	}
+	for i := range entries {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	bitmap := make([]byte, (count+7)/8)
+	for i, ok := range valid {
+		if ok {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	return bitmap, nil
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slhdsa
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/crypto/slhdsa"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/stretchr/testify/require"
+)
+
+type batchTuple struct {
+	publicKey, message, signature []byte
+}
+
+// prepareBatchInput creates batch input
+// Format: [mode|ModeBatch(1)][count(2)] then per tuple [pubKeyLen(2)][pubKey][msgLen(2)][message][signature]
+func prepareBatchInput(mode uint8, tuples []batchTuple) []byte {
+	input := []byte{mode | ModeBatch}
+	input = binary.BigEndian.AppendUint16(input, uint16(len(tuples)))
+	for _, tu := range tuples {
+		input = binary.BigEndian.AppendUint16(input, uint16(len(tu.publicKey)))
+		input = append(input, tu.publicKey...)
+		input = binary.BigEndian.AppendUint16(input, uint16(len(tu.message)))
+		input = append(input, tu.message...)
+		input = append(input, tu.signature...)
+	}
+	return input
+}
+
+func TestSLHDSABatchVerify(t *testing.T) {
+	priv, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHA2_128f)
+	require.NoError(t, err)
+	pub := priv.PublicKey.Bytes()
+
+	sign := func(msg []byte) []byte {
+		sig, err := priv.Sign(rand.Reader, msg, nil)
+		require.NoError(t, err)
+		return sig
+	}
+
+	var tuples []batchTuple
+	for i := 0; i < 10; i++ {
+		msg := []byte(fmt.Sprintf("batch message %d", i))
+		tuples = append(tuples, batchTuple{pub, msg, sign(msg)})
+	}
+	// Tamper with entries 1, 3 and 9
+	tuples[1].signature = common.CopyBytes(tuples[1].signature)
+	tuples[1].signature[100] ^= 0xFF
+	tuples[3].message = []byte("not the signed message")
+	tuples[9].signature = tuples[8].signature
+
+	input := prepareBatchInput(ModeSHA2_128f, tuples)
+	var msgBytes uint64
+	for _, tu := range tuples {
+		msgBytes += uint64(len(tu.message))
+	}
+	gas := SLHDSAVerifyPrecompile.RequiredGas(input)
+	require.Equal(t, SLHDSABatchBaseGas+10*SLH128fVerifyBaseGas+msgBytes*SLHDSAVerifyPerByteGas, gas)
+
+	result, remaining, err := SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress, input, gas, true)
+	require.NoError(t, err)
+	require.Zero(t, remaining)
+	// Valid: 0, 2, 4, 5, 6, 7, 8
+	require.Equal(t, []byte{0b11110101, 0b00000001}, result)
+
+	// An empty batch still pays the batch base gas
+	empty := prepareBatchInput(ModeSHA2_128f, nil)
+	require.Equal(t, SLHDSABatchBaseGas, SLHDSAVerifyPrecompile.RequiredGas(empty))
+	result, _, err = SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress, empty, gas, true)
+	require.NoError(t, err)
+	require.Empty(t, result)
+	_, _, err = SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress, empty, SLHDSABatchBaseGas-1, true)
+	require.Error(t, err)
+}
+
+func TestSLHDSABatchVerify_Malformed(t *testing.T) {
+	pub, sig, msg, _ := createTestSignature(t, slhdsa.SHA2_128f)
+	input := prepareBatchInput(ModeSHA2_128f, []batchTuple{{pub, msg, sig}})
+
+	run := func(input []byte) error {
+		_, _, err := SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress, input, 10_000_000, true)
+		return err
+	}
+
+	require.NoError(t, run(input))
+	require.ErrorIs(t, run(input[:len(input)-1]), ErrInvalidInputLength)
+	require.ErrorIs(t, run(append(common.CopyBytes(input), 0)), ErrInvalidInputLength)
+	require.ErrorIs(t, run(input[:2]), ErrInvalidInputLength)
+
+	// Count larger than the tuples supplied
+	overcount := common.CopyBytes(input)
+	overcount[2] = 2
+	require.ErrorIs(t, run(overcount), ErrInvalidInputLength)
+	require.Equal(t, SLHDSABatchBaseGas+2*SLH128fVerifyBaseGas, SLHDSAVerifyPrecompile.RequiredGas(overcount))
+
+	// Public key of the wrong size for the mode
+	require.ErrorIs(t, run(prepareBatchInput(ModeSHA2_128f, []batchTuple{{pub[:16], msg, sig}})), ErrInvalidInputLength)
+
+	invalidMode := common.CopyBytes(input)
+	invalidMode[0] = 0x0F | ModeBatch
+	require.ErrorIs(t, run(invalidMode), ErrUnsupportedMode)
+}
+
+func TestSLHDSABatchVerify_DomainSeparated(t *testing.T) {
+	priv, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHA2_128f)
+	require.NoError(t, err)
+
+	chainID := big.NewInt(96369)
+	message := []byte("domain separated batch")
+	signature, err := priv.Sign(rand.Reader, contract.DomainSeparatedMessage(chainID, ContractSLHDSAVerifyAddress, message), nil)
+	require.NoError(t, err)
+
+	tuple := batchTuple{priv.PublicKey.Bytes(), message, signature}
+	input := prepareBatchInput(ModeSHA2_128f|ModeDomainSeparated, []batchTuple{tuple, tuple})
+
+	result, _, err := SLHDSAVerifyPrecompile.Run(&chainState{chainID}, common.Address{}, ContractSLHDSAVerifyAddress, input, 10_000_000, true)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0b11}, result)
+
+	result, _, err = SLHDSAVerifyPrecompile.Run(&chainState{big.NewInt(1)}, common.Address{}, ContractSLHDSAVerifyAddress, input, 10_000_000, true)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0}, result)
+}
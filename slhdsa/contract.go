@@ -11,6 +11,7 @@ import (
 	"github.com/luxfi/crypto/slhdsa"
 	"github.com/luxfi/geth/common"
 	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/crypto/pqsizes"
 )
 
 var (
@@ -47,7 +48,19 @@ const (
 	ModeSHAKE_256f uint8 = 0x15 // SHAKE, 256-bit security, fast signing
 )
 
-// Size constants for each mode
+// ModeDomainSeparated may be OR'd into the mode byte to verify the signature
+// over contract.DomainSeparatedMessage(chainID, precompile, message) instead
+// of the raw message, binding it to this chain and precompile
+const ModeDomainSeparated uint8 = 0x80
+
+// ModePreHashed may be OR'd into the mode byte to verify a signature over a
+// digest of the message instead of the message itself; see
+// contract.PreHashedMessage. Gas does not depend on the message length.
+// It combines with ModeDomainSeparated but not with ModeBatch.
+const ModePreHashed uint8 = 0x20
+
+// Size constants for each mode. These mirror the pqsizes table, which is
+// what the precompile validates against.
 const (
 	// Public key sizes (2*n where n is security level parameter)
 	SLH128PublicKeySize = 32 // 128-bit security
@@ -72,6 +85,7 @@ const (
 	ModeByte       = 1 // Mode indicator byte
 	PubKeyLenSize  = 2 // Size of public key length field (uint16)
 	MessageLenSize = 2 // Size of message length field (uint16)
+	HashAlgSize    = 1 // Size of the pre-hash algorithm id
 )
 
 // Gas costs - adjusted per mode based on computational complexity
@@ -99,36 +113,45 @@ func (p *slhdsaVerifyPrecompile) Address() common.Address {
 	return ContractSLHDSAVerifyAddress
 }
 
+// Name returns the precompile name
+func (p *slhdsaVerifyPrecompile) Name() string {
+	return "SLH_DSA"
+}
+
+// modeParam is one row of the mode table; key and signature sizes come
+// from the shared pqsizes table
+type modeParam struct {
+	sizeMode   pqsizes.Mode
+	baseGas    uint64
+	slhdsaMode slhdsa.Mode
+}
+
+var modeParams = map[uint8]modeParam{
+	ModeSHA2_128s:  {pqsizes.SLHDSASHA2_128s, SLH128sVerifyBaseGas, slhdsa.SHA2_128s},
+	ModeSHA2_128f:  {pqsizes.SLHDSASHA2_128f, SLH128fVerifyBaseGas, slhdsa.SHA2_128f},
+	ModeSHA2_192s:  {pqsizes.SLHDSASHA2_192s, SLH192sVerifyBaseGas, slhdsa.SHA2_192s},
+	ModeSHA2_192f:  {pqsizes.SLHDSASHA2_192f, SLH192fVerifyBaseGas, slhdsa.SHA2_192f},
+	ModeSHA2_256s:  {pqsizes.SLHDSASHA2_256s, SLH256sVerifyBaseGas, slhdsa.SHA2_256s},
+	ModeSHA2_256f:  {pqsizes.SLHDSASHA2_256f, SLH256fVerifyBaseGas, slhdsa.SHA2_256f},
+	ModeSHAKE_128s: {pqsizes.SLHDSASHAKE_128s, SLH128sVerifyBaseGas, slhdsa.SHAKE_128s},
+	ModeSHAKE_128f: {pqsizes.SLHDSASHAKE_128f, SLH128fVerifyBaseGas, slhdsa.SHAKE_128f},
+	ModeSHAKE_192s: {pqsizes.SLHDSASHAKE_192s, SLH192sVerifyBaseGas, slhdsa.SHAKE_192s},
+	ModeSHAKE_192f: {pqsizes.SLHDSASHAKE_192f, SLH192fVerifyBaseGas, slhdsa.SHAKE_192f},
+	ModeSHAKE_256s: {pqsizes.SLHDSASHAKE_256s, SLH256sVerifyBaseGas, slhdsa.SHAKE_256s},
+	ModeSHAKE_256f: {pqsizes.SLHDSASHAKE_256f, SLH256fVerifyBaseGas, slhdsa.SHAKE_256f},
+}
+
 // getModeParams returns the parameters for a given SLH-DSA mode
 func getModeParams(mode uint8) (pubKeySize, sigSize int, baseGas uint64, slhdsaMode slhdsa.Mode, err error) {
-	switch mode {
-	case ModeSHA2_128s:
-		return SLH128PublicKeySize, SLHSHA2_128sSignatureSize, SLH128sVerifyBaseGas, slhdsa.SHA2_128s, nil
-	case ModeSHA2_128f:
-		return SLH128PublicKeySize, SLHSHA2_128fSignatureSize, SLH128fVerifyBaseGas, slhdsa.SHA2_128f, nil
-	case ModeSHA2_192s:
-		return SLH192PublicKeySize, SLHSHA2_192sSignatureSize, SLH192sVerifyBaseGas, slhdsa.SHA2_192s, nil
-	case ModeSHA2_192f:
-		return SLH192PublicKeySize, SLHSHA2_192fSignatureSize, SLH192fVerifyBaseGas, slhdsa.SHA2_192f, nil
-	case ModeSHA2_256s:
-		return SLH256PublicKeySize, SLHSHA2_256sSignatureSize, SLH256sVerifyBaseGas, slhdsa.SHA2_256s, nil
-	case ModeSHA2_256f:
-		return SLH256PublicKeySize, SLHSHA2_256fSignatureSize, SLH256fVerifyBaseGas, slhdsa.SHA2_256f, nil
-	case ModeSHAKE_128s:
-		return SLH128PublicKeySize, SLHSHAKE_128sSignatureSize, SLH128sVerifyBaseGas, slhdsa.SHAKE_128s, nil
-	case ModeSHAKE_128f:
-		return SLH128PublicKeySize, SLHSHAKE_128fSignatureSize, SLH128fVerifyBaseGas, slhdsa.SHAKE_128f, nil
-	case ModeSHAKE_192s:
-		return SLH192PublicKeySize, SLHSHAKE_192sSignatureSize, SLH192sVerifyBaseGas, slhdsa.SHAKE_192s, nil
-	case ModeSHAKE_192f:
-		return SLH192PublicKeySize, SLHSHAKE_192fSignatureSize, SLH192fVerifyBaseGas, slhdsa.SHAKE_192f, nil
-	case ModeSHAKE_256s:
-		return SLH256PublicKeySize, SLHSHAKE_256sSignatureSize, SLH256sVerifyBaseGas, slhdsa.SHAKE_256s, nil
-	case ModeSHAKE_256f:
-		return SLH256PublicKeySize, SLHSHAKE_256fSignatureSize, SLH256fVerifyBaseGas, slhdsa.SHAKE_256f, nil
-	default:
+	m, ok := modeParams[mode]
+	if !ok {
 		return 0, 0, 0, 0, ErrUnsupportedMode
 	}
+	sizes, err := pqsizes.Lookup(pqsizes.SLHDSA, m.sizeMode)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	return sizes.PublicKey, sizes.Signature, m.baseGas, m.slhdsaMode, nil
 }
 
 // RequiredGas calculates the gas required for SLH-DSA verification
@@ -136,12 +159,18 @@ func (p *slhdsaVerifyPrecompile) RequiredGas(input []byte) uint64 {
 	if len(input) < ModeByte {
 		return SLHDSADefaultGas
 	}
+	if input[0]&ModeBatch != 0 {
+		return batchRequiredGas(input)
+	}
 
-	mode := input[0]
+	mode := input[0] &^ (ModeDomainSeparated | ModePreHashed)
 	pubKeySize, _, baseGas, _, err := getModeParams(mode)
 	if err != nil {
 		return SLHDSADefaultGas
 	}
+	if input[0]&ModePreHashed != 0 {
+		return baseGas
+	}
 
 	// Check if we have enough bytes to read message length
 	// Format: [mode(1)][pubKeyLen(2)][pubKey][msgLen(2)][message][signature]
@@ -169,14 +198,23 @@ func (p *slhdsaVerifyPrecompile) RequiredGas(input []byte) uint64 {
 // Run implements the SLH-DSA signature verification precompile
 // Input format:
 //
-//	[0]              = mode byte (defines parameter set)
+//	[0]              = mode byte (defines parameter set, optionally | ModeDomainSeparated)
 //	[1:3]            = public key length as uint16
 //	[3:pubKeyEnd]    = public key
 //	[pubKeyEnd:+2]   = message length as uint16
-//	[+2:msgEnd]      = message
+//	[+2:msgEnd]      = message (at most contract.MessageSizeLimit bytes)
 //	[msgEnd:...]     = signature
 //
 // Output: 32-byte word (1 = valid, 0 = invalid)
+//
+// With ModePreHashed set the message length and message are replaced by a
+// pre-hash algorithm id and digest:
+//
+//	[pubKeyEnd]      = pre-hash algorithm id (contract.PreHash*)
+//	[+1:digestEnd]   = digest (length fixed by the algorithm)
+//	[digestEnd:...]  = signature
+//
+// With ModeBatch set in the mode byte the input is a batch; see runBatch.
 func (p *slhdsaVerifyPrecompile) Run(
 	accessibleState contract.AccessibleState,
 	caller common.Address,
@@ -184,13 +222,20 @@ func (p *slhdsaVerifyPrecompile) Run(
 	input []byte,
 	suppliedGas uint64,
 	readOnly bool,
-) ([]byte, uint64, error) {
+) (ret []byte, remainingGas uint64, err error) {
+	defer func() { err = contract.WrapRunError(p.Name(), addr, err) }()
+
 	// Calculate required gas
 	gasCost := p.RequiredGas(input)
 	if suppliedGas < gasCost {
 		return nil, 0, errors.New("out of gas")
 	}
 
+	if len(input) >= ModeByte && input[0]&ModeBatch != 0 {
+		ret, err := runBatch(accessibleState, addr, input)
+		return ret, suppliedGas - gasCost, err
+	}
+
 	// Minimum: mode byte + pubkey length
 	minHeader := ModeByte + PubKeyLenSize
 	if len(input) < minHeader {
@@ -198,47 +243,45 @@ func (p *slhdsaVerifyPrecompile) Run(
 	}
 
 	// Parse mode
-	mode := input[0]
-	pubKeySize, sigSize, _, slhdsaMode, err := getModeParams(mode)
+	mode := input[0] &^ (ModeDomainSeparated | ModePreHashed)
+	domainSeparated := input[0]&ModeDomainSeparated != 0
+	preHashed := input[0]&ModePreHashed != 0
+	_, sigSize, _, slhdsaMode, err := getModeParams(mode)
 	if err != nil {
 		return nil, suppliedGas - gasCost, fmt.Errorf("%w: 0x%02x", ErrUnsupportedMode, mode)
 	}
 
 	// Parse public key length
 	pubKeyLen := int(binary.BigEndian.Uint16(input[ModeByte : ModeByte+PubKeyLenSize]))
-	if pubKeyLen != pubKeySize {
-		return nil, suppliedGas - gasCost, fmt.Errorf("%w: expected pubkey size %d for mode 0x%02x, got %d",
-			ErrInvalidInputLength, pubKeySize, mode, pubKeyLen)
+	if err := pqsizes.ValidateSizes(pqsizes.SLHDSA, modeParams[mode].sizeMode, pqsizes.PublicKey(pubKeyLen)); err != nil {
+		return nil, suppliedGas - gasCost, fmt.Errorf("%w: %w", ErrInvalidInputLength, err)
 	}
 
 	// Calculate offsets
 	pubKeyStart := ModeByte + PubKeyLenSize
 	pubKeyEnd := pubKeyStart + pubKeyLen
-	msgLenStart := pubKeyEnd
-	msgLenEnd := msgLenStart + MessageLenSize
-
-	// Check we have enough input
-	if len(input) < msgLenEnd {
-		return nil, suppliedGas - gasCost, fmt.Errorf("%w: input too short for message length", ErrInvalidInputLength)
+	if len(input) < pubKeyEnd {
+		return nil, suppliedGas - gasCost, fmt.Errorf("%w: input too short for public key", ErrInvalidInputLength)
 	}
 
-	// Parse message length
-	msgLen := int(binary.BigEndian.Uint16(input[msgLenStart:msgLenEnd]))
-	msgStart := msgLenEnd
-	msgEnd := msgStart + msgLen
-	sigStart := msgEnd
-	sigEnd := sigStart + sigSize
-
-	// Validate total input size
-	if len(input) < sigEnd {
-		return nil, suppliedGas - gasCost, fmt.Errorf("%w: expected at least %d bytes, got %d",
-			ErrInvalidInputLength, sigEnd, len(input))
+	var message, signature []byte
+	if preHashed {
+		message, signature, err = parsePreHashed(input[pubKeyEnd:], sigSize)
+	} else {
+		message, signature, err = parseMessage(input[pubKeyEnd:], sigSize, contract.MessageSizeLimit(accessibleState))
+	}
+	if err != nil {
+		return nil, suppliedGas - gasCost, err
 	}
-
-	// Extract components
 	publicKey := input[pubKeyStart:pubKeyEnd]
-	message := input[msgStart:msgEnd]
-	signature := input[sigStart:sigEnd]
+
+	if domainSeparated {
+		chainID, err := contract.ChainID(accessibleState)
+		if err != nil {
+			return nil, suppliedGas - gasCost, err
+		}
+		message = contract.DomainSeparatedMessage(chainID, addr, message)
+	}
 
 	// Parse public key from bytes
 	pub, err := slhdsa.PublicKeyFromBytes(publicKey, slhdsaMode)
@@ -258,6 +301,47 @@ func (p *slhdsaVerifyPrecompile) Run(
 	return result, suppliedGas - gasCost, nil
 }
 
+// parseMessage splits [msgLen(2)][message][signature]. Bytes after the
+// signature are ignored. Messages over maxMessageSize are rejected.
+func parseMessage(body []byte, sigSize int, maxMessageSize uint64) ([]byte, []byte, error) {
+	if len(body) < MessageLenSize {
+		return nil, nil, fmt.Errorf("%w: input too short for message length", ErrInvalidInputLength)
+	}
+	msgLen := binary.BigEndian.Uint16(body)
+	if err := contract.CheckMessageSize(uint64(msgLen), maxMessageSize); err != nil {
+		return nil, nil, err
+	}
+	msgEnd := MessageLenSize + int(msgLen)
+	sigEnd := msgEnd + sigSize
+	if len(body) < sigEnd {
+		return nil, nil, fmt.Errorf("%w: expected at least %d bytes after public key, got %d",
+			ErrInvalidInputLength, sigEnd, len(body))
+	}
+	return body[MessageLenSize:msgEnd], body[msgEnd:sigEnd], nil
+}
+
+// parsePreHashed splits [hashAlg(1)][digest][signature] and returns the
+// pre-hashed message the signature is over
+func parsePreHashed(body []byte, sigSize int) ([]byte, []byte, error) {
+	if len(body) < HashAlgSize {
+		return nil, nil, fmt.Errorf("%w: input too short for pre-hash algorithm", ErrInvalidInputLength)
+	}
+	digestLen, err := contract.PreHashDigestLength(body[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	digestEnd := HashAlgSize + digestLen
+	if len(body) != digestEnd+sigSize {
+		return nil, nil, fmt.Errorf("%w: expected %d bytes after public key, got %d",
+			ErrInvalidInputLength, digestEnd+sigSize, len(body))
+	}
+	message, err := contract.PreHashedMessage(body[0], body[HashAlgSize:digestEnd])
+	if err != nil {
+		return nil, nil, err
+	}
+	return message, body[digestEnd:], nil
+}
+
 // ModeName returns a human-readable name for the mode
 func ModeName(mode uint8) string {
 	switch mode {
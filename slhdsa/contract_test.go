@@ -4,12 +4,17 @@
 package slhdsa
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/binary"
+	"math/big"
 	"testing"
 
 	"github.com/luxfi/crypto/slhdsa"
 	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+	"github.com/luxfi/precompile/crypto/pqsizes"
+	"github.com/luxfi/precompile/precompileconfig"
 	"github.com/stretchr/testify/require"
 )
 
@@ -153,6 +158,27 @@ func TestSLHDSAVerify_InvalidMode(t *testing.T) {
 	require.Contains(t, err.Error(), "unsupported")
 }
 
+// TestSLHDSAVerify_WrongKeySize tests that a key sized for another security
+// level is reported as a size mismatch before reaching the library
+func TestSLHDSAVerify_WrongKeySize(t *testing.T) {
+	for mode := range modeParams {
+		pubKeySize, sigSize, _, _, err := getModeParams(mode)
+		require.NoError(t, err)
+
+		input := make([]byte, ModeByte+PubKeyLenSize+pubKeySize+16+MessageLenSize+sigSize)
+		input[0] = mode
+		binary.BigEndian.PutUint16(input[ModeByte:], uint16(pubKeySize+16))
+
+		gas := SLHDSAVerifyPrecompile.RequiredGas(input)
+		_, _, err = SLHDSAVerifyPrecompile.Run(
+			nil, common.Address{}, ContractSLHDSAVerifyAddress,
+			input, gas, true,
+		)
+		require.ErrorIs(t, err, ErrInvalidInputLength)
+		require.ErrorIs(t, err, pqsizes.ErrSizeMismatch)
+	}
+}
+
 // TestSLHDSAVerify_EmptyMessage tests verification with empty message
 func TestSLHDSAVerify_EmptyMessage(t *testing.T) {
 	priv, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHA2_128s)
@@ -200,6 +226,43 @@ func TestSLHDSAVerify_LargeMessage(t *testing.T) {
 	require.Equal(t, byte(1), result[31], "signature for large message should be valid")
 }
 
+// TestSLHDSAVerify_MaxMessageSize tests that messages up to the limit verify
+// and longer ones are rejected, including under a chain config override
+func TestSLHDSAVerify_MaxMessageSize(t *testing.T) {
+	priv, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHA2_128s)
+	require.NoError(t, err)
+
+	run := func(state contract.AccessibleState, message []byte) ([]byte, error) {
+		signature, err := priv.Sign(rand.Reader, message, nil)
+		require.NoError(t, err)
+		input := prepareInputWithMode(ModeSHA2_128s, priv.PublicKey.Bytes(), message, signature)
+		result, _, err := SLHDSAVerifyPrecompile.Run(
+			state, common.Address{}, ContractSLHDSAVerifyAddress,
+			input, SLHDSAVerifyPrecompile.RequiredGas(input), true,
+		)
+		return result, err
+	}
+
+	result, err := run(nil, make([]byte, contract.MaxMessageSize))
+	require.NoError(t, err)
+	require.Equal(t, byte(1), result[31])
+
+	_, err = run(nil, make([]byte, contract.MaxMessageSize+1))
+	require.ErrorIs(t, err, contract.ErrMessageTooLarge)
+
+	limited := &limitState{limit: 64}
+	result, err = run(limited, make([]byte, 64))
+	require.NoError(t, err)
+	require.Equal(t, byte(1), result[31])
+
+	_, err = run(limited, make([]byte, 65))
+	require.ErrorIs(t, err, contract.ErrMessageTooLarge)
+
+	// A zero limit selects the default
+	_, err = run(&limitState{}, make([]byte, contract.MaxMessageSize+1))
+	require.ErrorIs(t, err, contract.ErrMessageTooLarge)
+}
+
 // TestSLHDSAVerify_GasCost tests per-mode gas cost calculation
 func TestSLHDSAVerify_GasCost(t *testing.T) {
 	tests := []struct {
@@ -297,3 +360,143 @@ func BenchmarkSLHDSAVerify_SHA2_128f(b *testing.B) {
 		)
 	}
 }
+
+// chainState is an AccessibleState that only exposes a chain id
+type chainState struct {
+	chainID *big.Int
+}
+
+func (s *chainState) GetStateDB() contract.StateDB                     { return nil }
+func (s *chainState) GetBlockContext() contract.BlockContext           { return nil }
+func (s *chainState) GetConsensusContext() context.Context             { return context.Background() }
+func (s *chainState) GetChainConfig() precompileconfig.ChainConfig     { return chainConfig{s.chainID} }
+func (s *chainState) GetPrecompileEnv() contract.PrecompileEnvironment { return nil }
+
+type chainConfig struct {
+	chainID *big.Int
+}
+
+func (c chainConfig) IsDurango(uint64) bool { return true }
+func (c chainConfig) GetChainID() *big.Int  { return c.chainID }
+
+// limitState is a chainState whose chain config sets the maximum message size
+type limitState struct {
+	chainState
+	limit uint64
+}
+
+func (s *limitState) GetChainConfig() precompileconfig.ChainConfig {
+	return limitConfig{chainConfig{s.chainID}, s.limit}
+}
+
+type limitConfig struct {
+	chainConfig
+	limit uint64
+}
+
+func (c limitConfig) MaxMessageSize(uint64) uint64 { return c.limit }
+
+// TestSLHDSAVerify_DomainSeparated tests that a domain-separated signature
+// only verifies on its own chain
+func TestSLHDSAVerify_DomainSeparated(t *testing.T) {
+	priv, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHA2_128f)
+	require.NoError(t, err)
+
+	chainID := big.NewInt(96369)
+	message := []byte("domain separated message")
+	signature, err := priv.Sign(rand.Reader, contract.DomainSeparatedMessage(chainID, ContractSLHDSAVerifyAddress, message), nil)
+	require.NoError(t, err)
+
+	input := prepareInputWithMode(ModeSHA2_128f|ModeDomainSeparated, priv.PublicKey.Bytes(), message, signature)
+	gas := SLHDSAVerifyPrecompile.RequiredGas(input)
+	require.Equal(t, SLH128fVerifyBaseGas+uint64(len(message))*SLHDSAVerifyPerByteGas, gas)
+
+	run := func(state contract.AccessibleState, input []byte) ([]byte, error) {
+		result, _, err := SLHDSAVerifyPrecompile.Run(state, common.Address{}, ContractSLHDSAVerifyAddress, input, gas, true)
+		return result, err
+	}
+
+	result, err := run(&chainState{chainID}, input)
+	require.NoError(t, err)
+	require.Equal(t, byte(1), result[31])
+
+	// Different chain id
+	result, err = run(&chainState{big.NewInt(96368)}, input)
+	require.NoError(t, err)
+	require.Equal(t, byte(0), result[31])
+
+	// Raw mode does not accept it
+	result, err = run(&chainState{chainID}, prepareInputWithMode(ModeSHA2_128f, priv.PublicKey.Bytes(), message, signature))
+	require.NoError(t, err)
+	require.Equal(t, byte(0), result[31])
+
+	// No chain id to bind to
+	_, err = run(nil, input)
+	require.ErrorIs(t, err, contract.ErrChainIDUnavailable)
+}
+
+// prepareInputPreHashed creates pre-hashed precompile input
+// Format: [mode|ModePreHashed][pubKeyLen(2)][pubKey][hashAlg(1)][digest][signature]
+func prepareInputPreHashed(mode uint8, publicKey []byte, hashAlg uint8, digest, signature []byte) []byte {
+	input := []byte{mode | ModePreHashed}
+	input = binary.BigEndian.AppendUint16(input, uint16(len(publicKey)))
+	input = append(input, publicKey...)
+	input = append(input, hashAlg)
+	input = append(input, digest...)
+	return append(input, signature...)
+}
+
+func TestSLHDSAVerify_PreHashedMatchesPure(t *testing.T) {
+	priv, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHA2_128f)
+	require.NoError(t, err)
+	pk := priv.PublicKey.Bytes()
+
+	// The largest message the pure format can carry
+	message := make([]byte, 0xFFFF)
+	for i := range message {
+		message[i] = byte(i % 251)
+	}
+
+	pureSig, err := priv.Sign(rand.Reader, message, nil)
+	require.NoError(t, err)
+	pureInput := prepareInputWithMode(ModeSHA2_128f, pk, message, pureSig)
+	pureGas := SLHDSAVerifyPrecompile.RequiredGas(pureInput)
+	require.Equal(t, SLH128fVerifyBaseGas+uint64(len(message))*SLHDSAVerifyPerByteGas, pureGas)
+	ret, _, err := SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress, pureInput, pureGas, true)
+	require.NoError(t, err)
+	require.Equal(t, byte(1), ret[31])
+
+	// Pre-hashed verification of the same message, and of one far larger
+	// than the pure format allows, costs the flat base gas
+	large := make([]byte, 4*1024*1024)
+	copy(large, message)
+	for _, msg := range [][]byte{message, large} {
+		digest, err := contract.PreHash(contract.PreHashSHA256, msg)
+		require.NoError(t, err)
+		signed, err := contract.PreHashedMessage(contract.PreHashSHA256, digest)
+		require.NoError(t, err)
+		sig, err := priv.Sign(rand.Reader, signed, nil)
+		require.NoError(t, err)
+
+		input := prepareInputPreHashed(ModeSHA2_128f, pk, contract.PreHashSHA256, digest, sig)
+		gas := SLHDSAVerifyPrecompile.RequiredGas(input)
+		require.Equal(t, SLH128fVerifyBaseGas, gas)
+		ret, _, err := SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress, input, gas, true)
+		require.NoError(t, err)
+		require.Equal(t, byte(1), ret[31])
+
+		// The pure signature over the message does not verify as pre-hashed
+		input = prepareInputPreHashed(ModeSHA2_128f, pk, contract.PreHashSHA256, digest, pureSig)
+		ret, _, err = SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress, input, gas, true)
+		require.NoError(t, err)
+		require.Equal(t, byte(0), ret[31])
+	}
+
+	// Wrong digest length and pre-hash in a batch are rejected
+	input := prepareInputPreHashed(ModeSHA2_128f, pk, contract.PreHashSHA512, make([]byte, 32), pureSig)
+	_, _, err = SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress, input, SLH128fVerifyBaseGas, true)
+	require.ErrorIs(t, err, ErrInvalidInputLength)
+	_, _, err = SLHDSAVerifyPrecompile.Run(nil, common.Address{}, ContractSLHDSAVerifyAddress,
+		[]byte{ModeSHA2_128f | ModePreHashed | ModeBatch, 0, 0}, SLHDSADefaultGas, true)
+	require.ErrorIs(t, err, ErrUnsupportedMode)
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2025, Lux Industries, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package slhdsa
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/luxfi/crypto/slhdsa"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/registry"
+)
+
+var selfTestMessage = []byte("lux precompile self-test")
+
+func init() {
+	if err := registry.RegisterSelfTest("SLH_DSA", SelfTest); err != nil {
+		panic(err)
+	}
+}
+
+// SelfTest signs a fixed message with a fresh SLH-DSA-SHAKE-128f key and
+// fails unless the precompile accepts the signature and rejects it over
+// another message
+func SelfTest() error {
+	return selfTest(func(input []byte) ([]byte, error) {
+		ret, _, err := SLHDSAVerifyPrecompile.Run(
+			nil, common.Address{}, ContractSLHDSAVerifyAddress,
+			input, SLHDSAVerifyPrecompile.RequiredGas(input), true,
+		)
+		return ret, err
+	})
+}
+
+func selfTest(run func(input []byte) ([]byte, error)) error {
+	priv, err := slhdsa.GenerateKey(rand.Reader, slhdsa.SHAKE_128f)
+	if err != nil {
+		return fmt.Errorf("SLH-DSA key generation: %w", err)
+	}
+	sig, err := priv.Sign(rand.Reader, selfTestMessage, nil)
+	if err != nil {
+		return fmt.Errorf("SLH-DSA signing: %w", err)
+	}
+
+	pk := priv.PublicKey.Bytes()
+	if !selfTestVerify(run, pk, sig, selfTestMessage) {
+		return errors.New("SLH-DSA signature rejected")
+	}
+	if selfTestVerify(run, pk, sig, append([]byte("not "), selfTestMessage...)) {
+		return errors.New("SLH-DSA signature accepted for a different message")
+	}
+	return nil
+}
+
+// selfTestVerify runs a mode-prefixed SLH-DSA-SHAKE-128f verification
+func selfTestVerify(run func(input []byte) ([]byte, error), pk, sig, message []byte) bool {
+	input := []byte{ModeSHAKE_128f}
+	input = binary.BigEndian.AppendUint16(input, uint16(len(pk)))
+	input = append(input, pk...)
+	input = binary.BigEndian.AppendUint16(input, uint16(len(message)))
+	input = append(input, message...)
+	input = append(input, sig...)
+
+	ret, err := run(input)
+	return err == nil && len(ret) == 32 && ret[31] == 1
+}
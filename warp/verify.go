@@ -0,0 +1,153 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package warp verifies Warp messages for the WarpReceive precompiles: a
+// message is accepted when the source subnet's validators that signed it,
+// weighted by stake at the P-Chain height it was signed at, meet a
+// threshold.
+package warp
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/geth/common"
+)
+
+var (
+	ErrInvalidThreshold    = errors.New("invalid warp quorum threshold")
+	ErrValidatorSetHeight  = errors.New("validator set is not at the message's signing height")
+	ErrValidatorSetSubnet  = errors.New("validator set is not the message's source subnet")
+	ErrNoSigners           = errors.New("warp message has no signers")
+	ErrUnknownSigner       = errors.New("signer bit outside the validator set")
+	ErrInsufficientWeight  = errors.New("signers' weight below quorum threshold")
+	ErrInvalidSignature    = errors.New("invalid warp aggregate signature")
+	ErrWeightOverflow      = errors.New("validator weight overflows uint64")
+	ErrValidatorSetMissing = errors.New("no validator set for subnet at height")
+)
+
+// Validator is one member of a subnet's validator set
+type Validator struct {
+	PublicKey *bls.PublicKey
+	Weight    uint64
+}
+
+// ValidatorSet is a subnet's validators as of one P-Chain height. Signer
+// bitsets index into Validators, so its order is canonical.
+type ValidatorSet struct {
+	SubnetID     common.Hash
+	PChainHeight uint64
+	Validators   []Validator
+}
+
+// TotalWeight returns the summed weight of the set
+func (vs *ValidatorSet) TotalWeight() (uint64, error) {
+	var total uint64
+	for _, v := range vs.Validators {
+		if total+v.Weight < total {
+			return 0, ErrWeightOverflow
+		}
+		total += v.Weight
+	}
+	return total, nil
+}
+
+// ValidatorSetProvider looks up a subnet's validator set at a P-Chain
+// height. Keying by height lets messages signed before a validator set
+// rotation still verify against the set that signed them.
+type ValidatorSetProvider interface {
+	GetValidatorSet(subnetID common.Hash, pChainHeight uint64) (*ValidatorSet, error)
+}
+
+// Message is a Warp message with the source validators' aggregate signature
+type Message struct {
+	SourceSubnetID common.Hash
+	// PChainHeight is the height whose validator set signed the message
+	PChainHeight uint64
+	// UnsignedMessage is the byte string every signer signed
+	UnsignedMessage []byte
+	// Signers is a bitset over the validator set: bit i (LSB first within
+	// each byte) is set if Validators[i] signed
+	Signers []byte
+	// Signature is the compressed aggregate BLS signature of the signers
+	Signature []byte
+}
+
+// Threshold is the fraction of total weight that must sign, e.g. 67/100
+type Threshold struct {
+	Numerator   uint64
+	Denominator uint64
+}
+
+// VerifyWarpMessage checks that msg was signed by validators of validatorSet
+// holding at least threshold of its total weight, and that the aggregate
+// signature is theirs over msg.UnsignedMessage. validatorSet must be the
+// source subnet's set at msg.PChainHeight.
+func VerifyWarpMessage(msg *Message, validatorSet *ValidatorSet, threshold Threshold) error {
+	if threshold.Denominator == 0 || threshold.Numerator == 0 || threshold.Numerator > threshold.Denominator {
+		return ErrInvalidThreshold
+	}
+	if validatorSet.SubnetID != msg.SourceSubnetID {
+		return ErrValidatorSetSubnet
+	}
+	if validatorSet.PChainHeight != msg.PChainHeight {
+		return fmt.Errorf("%w: set at %d, message at %d", ErrValidatorSetHeight, validatorSet.PChainHeight, msg.PChainHeight)
+	}
+
+	totalWeight, err := validatorSet.TotalWeight()
+	if err != nil {
+		return err
+	}
+
+	var signedWeight uint64
+	var publicKeys []*bls.PublicKey
+	for i := 0; i < len(msg.Signers)*8; i++ {
+		if msg.Signers[i/8]&(1<<(i%8)) == 0 {
+			continue
+		}
+		if i >= len(validatorSet.Validators) {
+			return fmt.Errorf("%w: bit %d of %d validators", ErrUnknownSigner, i, len(validatorSet.Validators))
+		}
+		v := validatorSet.Validators[i]
+		signedWeight += v.Weight // cannot overflow: bounded by totalWeight
+		publicKeys = append(publicKeys, v.PublicKey)
+	}
+	if len(publicKeys) == 0 {
+		return ErrNoSigners
+	}
+
+	// signedWeight / totalWeight >= numerator / denominator, without overflow
+	lhs := new(big.Int).Mul(new(big.Int).SetUint64(signedWeight), new(big.Int).SetUint64(threshold.Denominator))
+	rhs := new(big.Int).Mul(new(big.Int).SetUint64(totalWeight), new(big.Int).SetUint64(threshold.Numerator))
+	if lhs.Cmp(rhs) < 0 {
+		return fmt.Errorf("%w: %d of %d", ErrInsufficientWeight, signedWeight, totalWeight)
+	}
+
+	aggregateKey, err := bls.AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	signature, err := bls.SignatureFromBytes(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if !bls.Verify(aggregateKey, signature, msg.UnsignedMessage) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyWarpMessageAt fetches the source subnet's validator set at the
+// message's signing height from provider and verifies msg against it
+func VerifyWarpMessageAt(msg *Message, provider ValidatorSetProvider, threshold Threshold) error {
+	validatorSet, err := provider.GetValidatorSet(msg.SourceSubnetID, msg.PChainHeight)
+	if err != nil {
+		return err
+	}
+	if validatorSet == nil {
+		return fmt.Errorf("%w: %s at %d", ErrValidatorSetMissing, msg.SourceSubnetID.Hex(), msg.PChainHeight)
+	}
+	return VerifyWarpMessage(msg, validatorSet, threshold)
+}
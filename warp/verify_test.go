@@ -0,0 +1,117 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package warp
+
+import (
+	"testing"
+
+	"github.com/luxfi/crypto/bls"
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSubnetID  = common.HexToHash("0x01")
+	testThreshold = Threshold{Numerator: 67, Denominator: 100}
+)
+
+// testValidators creates a validator set at height with the given weights
+// and returns it with the validators' secret keys
+func testValidators(t *testing.T, height uint64, weights ...uint64) (*ValidatorSet, []*bls.SecretKey) {
+	t.Helper()
+	set := &ValidatorSet{SubnetID: testSubnetID, PChainHeight: height}
+	keys := make([]*bls.SecretKey, len(weights))
+	for i, weight := range weights {
+		sk, err := bls.NewSecretKey()
+		require.NoError(t, err)
+		keys[i] = sk
+		set.Validators = append(set.Validators, Validator{PublicKey: bls.PublicFromSecretKey(sk), Weight: weight})
+	}
+	return set, keys
+}
+
+// signMessage builds a message at height signed by the validators at
+// indices
+func signMessage(t *testing.T, height uint64, keys []*bls.SecretKey, indices ...int) *Message {
+	t.Helper()
+	msg := &Message{
+		SourceSubnetID:  testSubnetID,
+		PChainHeight:    height,
+		UnsignedMessage: []byte("warp payload"),
+		Signers:         make([]byte, (len(keys)+7)/8),
+	}
+	signatures := make([]*bls.Signature, len(indices))
+	for i, idx := range indices {
+		msg.Signers[idx/8] |= 1 << (idx % 8)
+		signatures[i] = bls.Sign(keys[idx], msg.UnsignedMessage)
+	}
+	aggregate, err := bls.AggregateSignatures(signatures)
+	require.NoError(t, err)
+	msg.Signature = bls.SignatureToBytes(aggregate)
+	return msg
+}
+
+func TestVerifyWarpMessageThreshold(t *testing.T) {
+	set, keys := testValidators(t, 10, 34, 33, 33)
+
+	// 67 of 100 meets the threshold
+	require.NoError(t, VerifyWarpMessage(signMessage(t, 10, keys, 0, 1), set, testThreshold))
+	require.NoError(t, VerifyWarpMessage(signMessage(t, 10, keys, 0, 1, 2), set, testThreshold))
+
+	// 66 of 100 is just under
+	err := VerifyWarpMessage(signMessage(t, 10, keys, 1, 2), set, testThreshold)
+	require.ErrorIs(t, err, ErrInsufficientWeight)
+}
+
+func TestVerifyWarpMessageSignature(t *testing.T) {
+	set, keys := testValidators(t, 10, 34, 33, 33)
+
+	// Claiming a signer whose signature is not in the aggregate fails
+	msg := signMessage(t, 10, keys, 0, 1)
+	msg.Signers[0] |= 1 << 2
+	require.ErrorIs(t, VerifyWarpMessage(msg, set, testThreshold), ErrInvalidSignature)
+
+	// So does a tampered payload
+	msg = signMessage(t, 10, keys, 0, 1)
+	msg.UnsignedMessage = []byte("other payload")
+	require.ErrorIs(t, VerifyWarpMessage(msg, set, testThreshold), ErrInvalidSignature)
+
+	msg = signMessage(t, 10, keys, 0, 1)
+	msg.Signers = append(msg.Signers, 0x01)
+	require.ErrorIs(t, VerifyWarpMessage(msg, set, testThreshold), ErrUnknownSigner)
+
+	msg.Signers = []byte{0}
+	require.ErrorIs(t, VerifyWarpMessage(msg, set, testThreshold), ErrNoSigners)
+
+	for _, threshold := range []Threshold{{}, {Numerator: 1}, {Numerator: 2, Denominator: 1}} {
+		require.ErrorIs(t, VerifyWarpMessage(signMessage(t, 10, keys, 0, 1), set, threshold), ErrInvalidThreshold)
+	}
+}
+
+// heightProvider serves validator sets by P-Chain height
+type heightProvider map[uint64]*ValidatorSet
+
+func (p heightProvider) GetValidatorSet(subnetID common.Hash, height uint64) (*ValidatorSet, error) {
+	return p[height], nil
+}
+
+func TestVerifyWarpMessageRotation(t *testing.T) {
+	oldSet, oldKeys := testValidators(t, 10, 50, 50)
+	newSet, _ := testValidators(t, 20, 50, 50)
+	provider := heightProvider{10: oldSet, 20: newSet}
+
+	// A message signed before the rotation verifies against the old set
+	msg := signMessage(t, 10, oldKeys, 0, 1)
+	require.NoError(t, VerifyWarpMessageAt(msg, provider, testThreshold))
+
+	// but not against the current one
+	require.ErrorIs(t, VerifyWarpMessage(msg, newSet, testThreshold), ErrValidatorSetHeight)
+
+	// Old validators cannot sign for the new height
+	msg = signMessage(t, 20, oldKeys, 0, 1)
+	require.ErrorIs(t, VerifyWarpMessageAt(msg, provider, testThreshold), ErrInvalidSignature)
+
+	msg = signMessage(t, 30, oldKeys, 0, 1)
+	require.ErrorIs(t, VerifyWarpMessageAt(msg, provider, testThreshold), ErrValidatorSetMissing)
+}
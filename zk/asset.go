@@ -0,0 +1,111 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/luxfi/geth/common"
+)
+
+var (
+	ErrAssetNotRegistered = errors.New("asset not registered")
+	ErrAssetMismatch      = errors.New("note asset does not match token")
+)
+
+// DeriveAssetID maps a token address to its shielded asset ID. The ID is a
+// canonical BN254 field element, so it enters note commitments unreduced,
+// and depends only on the token, so every note of a token carries the same
+// ID on every chain.
+func DeriveAssetID(token common.Address) [32]byte {
+	digest := sha256.Sum256(append([]byte("lux/zk/asset/"), token[:]...))
+	var elem fr.Element
+	elem.SetBytes(digest[:])
+	return elem.Bytes()
+}
+
+// AssetRegistry maps token addresses to the shielded asset IDs notes
+// commit to, and back
+type AssetRegistry struct {
+	ids    map[common.Address][32]byte
+	tokens map[[32]byte]common.Address
+	mu     sync.RWMutex
+}
+
+// NewAssetRegistry creates an empty registry
+func NewAssetRegistry() *AssetRegistry {
+	return &AssetRegistry{
+		ids:    make(map[common.Address][32]byte),
+		tokens: make(map[[32]byte]common.Address),
+	}
+}
+
+// RegisterAsset registers token and returns its asset ID. Registering a
+// token again returns the same ID.
+func (r *AssetRegistry) RegisterAsset(token common.Address) [32]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.ids[token]; ok {
+		return id
+	}
+	id := DeriveAssetID(token)
+	r.ids[token] = id
+	r.tokens[id] = token
+	return id
+}
+
+// AssetIDFor returns the asset ID of a registered token
+func (r *AssetRegistry) AssetIDFor(token common.Address) ([32]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.ids[token]
+	if !ok {
+		return [32]byte{}, fmt.Errorf("%w: token %s", ErrAssetNotRegistered, token)
+	}
+	return id, nil
+}
+
+// TokenFor returns the token a registered asset ID belongs to
+func (r *AssetRegistry) TokenFor(assetID [32]byte) (common.Address, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	token, ok := r.tokens[assetID]
+	if !ok {
+		return common.Address{}, fmt.Errorf("%w: asset %x", ErrAssetNotRegistered, assetID)
+	}
+	return token, nil
+}
+
+// ValidateNoteAsset checks that note is a note of token: its asset ID is
+// the token's, and its commitment opens to the note's fields, so the ID
+// cannot be swapped after the note was committed
+func (r *AssetRegistry) ValidateNoteAsset(note *Note, token common.Address) error {
+	id, err := r.AssetIDFor(token)
+	if err != nil {
+		return err
+	}
+	if note.AssetID != id {
+		return fmt.Errorf("%w: note asset %x, token %s is %x", ErrAssetMismatch, note.AssetID, token, id)
+	}
+
+	scheme, err := GetScheme(note.SchemeType)
+	if err != nil {
+		return err
+	}
+	commitment, err := scheme.NoteCommitment(note.Amount, note.AssetID, note.Owner, note.BlindingFactor)
+	if err != nil {
+		return err
+	}
+	if commitment != note.Commitment {
+		return fmt.Errorf("%w: note does not open its commitment", ErrInvalidCommitment)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testUSDC  = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	testWETH  = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	testOwner = common.HexToAddress("0x1234567890123456789012345678901234567890")
+)
+
+func noteFor(t *testing.T, assets *AssetRegistry, token common.Address, amount int64, blinding byte) *Note {
+	t.Helper()
+	assetID, err := assets.AssetIDFor(token)
+	require.NoError(t, err)
+	note, err := CreateNote(NoteInput{
+		Amount:         big.NewInt(amount),
+		AssetID:        assetID,
+		Owner:          testOwner,
+		BlindingFactor: [32]byte{31: blinding},
+		SchemeType:     SchemePoseidon2,
+	})
+	require.NoError(t, err)
+	return note
+}
+
+func TestAssetRegistry(t *testing.T) {
+	require := require.New(t)
+	assets := NewAssetRegistry()
+
+	_, err := assets.AssetIDFor(testUSDC)
+	require.ErrorIs(err, ErrAssetNotRegistered)
+
+	usdc := assets.RegisterAsset(testUSDC)
+	require.Equal(usdc, assets.RegisterAsset(testUSDC), "registration is idempotent")
+	require.Equal(DeriveAssetID(testUSDC), usdc, "IDs do not depend on the registry")
+	weth := assets.RegisterAsset(testWETH)
+	require.NotEqual(usdc, weth)
+
+	// IDs are canonical field elements
+	var elem fr.Element
+	require.NoError(elem.SetBytesCanonical(usdc[:]))
+
+	token, err := assets.TokenFor(weth)
+	require.NoError(err)
+	require.Equal(testWETH, token)
+	_, err = assets.TokenFor([32]byte{1})
+	require.ErrorIs(err, ErrAssetNotRegistered)
+
+	// Notes of the same token share an asset ID; different tokens differ
+	a := noteFor(t, assets, testUSDC, 100, 1)
+	b := noteFor(t, assets, testUSDC, 250, 2)
+	c := noteFor(t, assets, testWETH, 100, 1)
+	require.Equal(a.AssetID, b.AssetID)
+	require.NotEqual(a.AssetID, c.AssetID)
+	require.NotEqual(a.Commitment, c.Commitment, "same amount and blinding, different asset")
+}
+
+func TestValidateWithdrawal(t *testing.T) {
+	require := require.New(t)
+	zv := NewZKVerifier()
+
+	usdcPool, err := zv.CreateConfidentialPool(testOwner, testUSDC, 20)
+	require.NoError(err)
+	_, err = zv.CreateConfidentialPool(testOwner, testWETH, 20)
+	require.NoError(err)
+
+	usdcNote := noteFor(t, zv.Assets, testUSDC, 100, 1)
+	wethNote := noteFor(t, zv.Assets, testWETH, 100, 1)
+
+	require.NoError(zv.ValidateWithdrawal(usdcPool, usdcNote))
+	require.ErrorIs(zv.ValidateWithdrawal(usdcPool, wethNote), ErrAssetMismatch)
+
+	// Relabelling a note's asset without recommitting does not pass
+	relabelled := *wethNote
+	relabelled.AssetID = usdcNote.AssetID
+	require.ErrorIs(zv.ValidateWithdrawal(usdcPool, &relabelled), ErrInvalidCommitment)
+
+	require.ErrorIs(zv.ValidateWithdrawal([32]byte{0xff}, usdcNote), ErrPoolNotFound)
+}
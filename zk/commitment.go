@@ -5,7 +5,10 @@ package zk
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"slices"
+	"sync"
 
 	"github.com/luxfi/geth/common"
 )
@@ -26,6 +29,37 @@ type CommitmentScheme interface {
 	IsPQSafe() bool
 }
 
+// NoteScheme is a commitment scheme that notes can be created under,
+// identified on-chain by its SchemeType
+type NoteScheme interface {
+	CommitmentScheme
+
+	// Type returns the scheme's on-chain identifier
+	Type() SchemeType
+}
+
+// Poseidon2CommitElements is the number of field elements hashed by a
+// Poseidon2 commitment (value, blinding, salt)
+const Poseidon2CommitElements = 3
+
+// Poseidon2Gas returns the gas cost of hashing numElements field elements
+// with Poseidon2: 500 base + 100 per element.
+func Poseidon2Gas(numElements int) uint64 {
+	if numElements < 0 {
+		numElements = 0
+	}
+	return 500 + uint64(numElements)*100
+}
+
+// PedersenGas returns the gas cost of a Pedersen commitment to numValues
+// values: one scalar mult per value plus one for the blinding factor.
+func PedersenGas(numValues int) uint64 {
+	if numValues < 0 {
+		numValues = 0
+	}
+	return uint64(numValues+1) * 3000
+}
+
 // Poseidon2Scheme implements CommitmentScheme using Poseidon2 hashes
 type Poseidon2Scheme struct {
 	hasher *Poseidon2Hasher
@@ -48,13 +82,17 @@ func (s *Poseidon2Scheme) NoteCommitment(amount *big.Int, assetId [32]byte, owne
 }
 
 func (s *Poseidon2Scheme) RequiredGas() uint64 {
-	return 800 // 500 base + 3 elements * 100
+	return Poseidon2Gas(Poseidon2CommitElements)
 }
 
 func (s *Poseidon2Scheme) IsPQSafe() bool {
 	return true // Hash-based, Grover-resistant with 256-bit security
 }
 
+func (s *Poseidon2Scheme) Type() SchemeType {
+	return SchemePoseidon2
+}
+
 // PedersenScheme implements CommitmentScheme using Pedersen commitments
 type PedersenScheme struct {
 	committer *PedersenCommitter
@@ -76,13 +114,17 @@ func (s *PedersenScheme) NoteCommitment(amount *big.Int, assetId [32]byte, owner
 }
 
 func (s *PedersenScheme) RequiredGas() uint64 {
-	return 6000 // 2 scalar mults + 1 add
+	return PedersenGas(1)
 }
 
 func (s *PedersenScheme) IsPQSafe() bool {
 	return false // Discrete log assumption breaks with quantum
 }
 
+func (s *PedersenScheme) Type() SchemeType {
+	return SchemePedersen
+}
+
 // DefaultScheme is Poseidon2 (PQ-safe)
 var DefaultScheme CommitmentScheme = NewPoseidon2Scheme()
 
@@ -94,18 +136,79 @@ const (
 	SchemePedersen  SchemeType = 1 // Legacy, NOT PQ-safe
 )
 
-// GetScheme returns a commitment scheme by type
-func GetScheme(schemeType SchemeType) (CommitmentScheme, error) {
-	switch schemeType {
-	case SchemePoseidon2:
-		return NewPoseidon2Scheme(), nil
-	case SchemePedersen:
-		return NewPedersenScheme(), nil
-	default:
-		return nil, errors.New("unknown commitment scheme")
+var (
+	ErrUnknownScheme    = errors.New("unknown commitment scheme")
+	ErrSchemeRegistered = errors.New("commitment scheme already registered")
+)
+
+// NoteSchemeRegistry maps scheme identifiers to constructors
+type NoteSchemeRegistry struct {
+	constructors map[SchemeType]func() NoteScheme
+	mu           sync.RWMutex
+}
+
+// NewNoteSchemeRegistry creates an empty registry
+func NewNoteSchemeRegistry() *NoteSchemeRegistry {
+	return &NoteSchemeRegistry{
+		constructors: make(map[SchemeType]func() NoteScheme),
+	}
+}
+
+// Register adds a constructor for schemeType
+func (r *NoteSchemeRegistry) Register(schemeType SchemeType, constructor func() NoteScheme) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.constructors[schemeType]; exists {
+		return fmt.Errorf("%w: %d", ErrSchemeRegistered, schemeType)
+	}
+	r.constructors[schemeType] = constructor
+	return nil
+}
+
+// Get constructs the scheme registered for schemeType
+func (r *NoteSchemeRegistry) Get(schemeType SchemeType) (NoteScheme, error) {
+	r.mu.RLock()
+	constructor, ok := r.constructors[schemeType]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownScheme, schemeType)
+	}
+	return constructor(), nil
+}
+
+// Types returns the registered scheme identifiers in ascending order
+func (r *NoteSchemeRegistry) Types() []SchemeType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]SchemeType, 0, len(r.constructors))
+	for t := range r.constructors {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// NoteSchemes is the registry CreateNote and the precompiles resolve
+// schemes from
+var NoteSchemes = NewNoteSchemeRegistry()
+
+func init() {
+	if err := NoteSchemes.Register(SchemePoseidon2, func() NoteScheme { return NewPoseidon2Scheme() }); err != nil {
+		panic(err)
+	}
+	if err := NoteSchemes.Register(SchemePedersen, func() NoteScheme { return NewPedersenScheme() }); err != nil {
+		panic(err)
 	}
 }
 
+// GetScheme returns a commitment scheme by type
+func GetScheme(schemeType SchemeType) (NoteScheme, error) {
+	return NoteSchemes.Get(schemeType)
+}
+
 // NoteInput represents the inputs to create a shielded note
 type NoteInput struct {
 	Amount         *big.Int
@@ -153,14 +256,11 @@ func CreateNote(input NoteInput) (*Note, error) {
 	}, nil
 }
 
-// Nullifier computes the nullifier for spending this note
-// nullifier = Hash(nullifierKey, commitment, leafIndex)
+// Nullifier computes the nullifier for spending this note; see
+// DeriveNullifier. Notes under every commitment scheme use the same
+// Poseidon2 derivation, since it is a hash, not a commitment.
 func (n *Note) Nullifier(nullifierKey [32]byte) ([32]byte, error) {
-	if n.SchemeType == SchemePoseidon2 {
-		return globalPoseidon2.NullifierHash(nullifierKey, n.Commitment, n.LeafIndex)
-	}
-	// For Pedersen scheme, still use Poseidon2 for nullifier (it's a hash, not commitment)
-	return globalPoseidon2.NullifierHash(nullifierKey, n.Commitment, n.LeafIndex)
+	return DeriveNullifier(n.Commitment, n.LeafIndex, nullifierKey), nil
 }
 
 // TransactionWitness contains the private witness for a shielded transaction
@@ -5,10 +5,13 @@ package zk
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"fmt"
+	"math"
 	"math/big"
 	"testing"
 
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/luxfi/geth/common"
 	"github.com/stretchr/testify/require"
 )
@@ -53,6 +56,48 @@ func TestPoseidon2HashPair(t *testing.T) {
 	hash2, err := hasher.HashPair(right, left)
 	require.NoError(t, err)
 	require.NotEqual(t, hash, hash2)
+
+	// Pairs sharing a left child must not share a cache entry
+	right[31] = 3
+	hash3, err := hasher.HashPair(left, right)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, hash3)
+}
+
+// TestPoseidon2DomainSeparation tests that trees in different domains never
+// share roots or proofs
+func TestPoseidon2DomainSeparation(t *testing.T) {
+	leaves := make([][32]byte, 5)
+	for i := range leaves {
+		leaves[i][31] = byte(i + 1)
+	}
+
+	commitments := NewPoseidon2HasherForDomain("commitments")
+	nullifiers := NewPoseidon2HasherForDomain("nullifiers")
+
+	commitRoot, err := commitments.MerkleRoot(leaves)
+	require.NoError(t, err)
+	nullRoot, err := nullifiers.MerkleRoot(leaves)
+	require.NoError(t, err)
+	plainRoot, err := NewPoseidon2Hasher().MerkleRoot(leaves)
+	require.NoError(t, err)
+	require.NotEqual(t, commitRoot, nullRoot)
+	require.NotEqual(t, commitRoot, plainRoot)
+
+	// Same domain is deterministic across hasher instances
+	again, err := NewPoseidon2HasherForDomain("commitments").MerkleRoot(leaves)
+	require.NoError(t, err)
+	require.Equal(t, commitRoot, again)
+
+	// A proof only verifies under the domain that produced it
+	proof, isLeft, err := commitments.MerkleProof(leaves, 2)
+	require.NoError(t, err)
+	valid, err := commitments.VerifyMerkleProof(leaves[2], proof, isLeft, commitRoot)
+	require.NoError(t, err)
+	require.True(t, valid)
+	valid, err = nullifiers.VerifyMerkleProof(leaves[2], proof, isLeft, commitRoot)
+	require.NoError(t, err)
+	require.False(t, valid)
 }
 
 // TestPoseidon2MerkleTree tests Merkle tree construction and verification
@@ -81,6 +126,168 @@ func TestPoseidon2MerkleTree(t *testing.T) {
 	}
 }
 
+// TestPoseidon2MultiProof tests batch verification of several leaves
+func TestPoseidon2MultiProof(t *testing.T) {
+	require := require.New(t)
+	hasher := NewPoseidon2Hasher()
+
+	leaves := make([][32]byte, 8)
+	for i := range leaves {
+		leaves[i][31] = byte(i + 1)
+	}
+	root, err := hasher.MerkleRoot(leaves)
+	require.NoError(err)
+
+	indices := []int{1, 2, 3, 6}
+	proof, err := hasher.MerkleMultiProof(leaves, indices)
+	require.NoError(err)
+	require.Equal(3, proof.Depth)
+	// 4 single proofs carry 12 siblings; shared nodes leave 3: leaf 0,
+	// leaf 7 and the parent of leaves 4 and 5
+	require.Len(proof.Nodes, 3)
+
+	batch := make([]LeafAt, len(indices))
+	for i, idx := range indices {
+		batch[i] = LeafAt{Index: uint64(idx), Leaf: leaves[idx]}
+	}
+	valid, err := hasher.VerifyMultiProof(batch, proof, root)
+	require.NoError(err)
+	require.True(valid)
+
+	// Order of the batch does not matter
+	reversed := []LeafAt{batch[3], batch[2], batch[1], batch[0]}
+	valid, err = hasher.VerifyMultiProof(reversed, proof, root)
+	require.NoError(err)
+	require.True(valid)
+
+	// Swapping any leaf for another value fails
+	for i := range batch {
+		swapped := append([]LeafAt(nil), batch...)
+		swapped[i].Leaf = leaves[0]
+		valid, err := hasher.VerifyMultiProof(swapped, proof, root)
+		require.NoError(err)
+		require.False(valid, "swapped leaf %d", i)
+	}
+
+	// Swapping two leaves' positions fails
+	swapped := append([]LeafAt(nil), batch...)
+	swapped[0].Leaf, swapped[3].Leaf = swapped[3].Leaf, swapped[0].Leaf
+	valid, err = hasher.VerifyMultiProof(swapped, proof, root)
+	require.NoError(err)
+	require.False(valid)
+
+	// Malformed proofs are rejected
+	_, err = hasher.VerifyMultiProof(batch, MultiProof{Depth: 3, Nodes: proof.Nodes[:2]}, root)
+	require.ErrorIs(err, ErrInvalidMerkleProof)
+	_, err = hasher.VerifyMultiProof(batch, MultiProof{Depth: 3, Nodes: append(proof.Nodes, root)}, root)
+	require.ErrorIs(err, ErrInvalidMerkleProof)
+	_, err = hasher.VerifyMultiProof(append(batch, batch[0]), proof, root)
+	require.ErrorIs(err, ErrInvalidMerkleProof)
+	_, err = hasher.VerifyMultiProof([]LeafAt{{Index: 8}}, proof, root)
+	require.ErrorIs(err, ErrInvalidMerkleProof)
+}
+
+// TestMerkleProofEncoding tests the canonical proof byte encoding
+func TestMerkleProofEncoding(t *testing.T) {
+	hasher := NewPoseidon2Hasher()
+
+	leaves := make([][32]byte, 11)
+	for i := range leaves {
+		leaves[i][31] = byte(i + 1)
+	}
+	root, err := hasher.MerkleRoot(leaves)
+	require.NoError(t, err)
+
+	for i := range leaves {
+		proof, isLeft, err := hasher.MerkleProof(leaves, i)
+		require.NoError(t, err)
+
+		encoded, err := EncodeMerkleProof(proof, isLeft)
+		require.NoError(t, err)
+		require.Len(t, encoded, 2+len(proof)*32+1)
+
+		gotProof, gotIsLeft, err := DecodeMerkleProof(encoded)
+		require.NoError(t, err)
+		require.Equal(t, proof, gotProof)
+		require.Equal(t, isLeft, gotIsLeft)
+
+		valid, err := hasher.VerifyMerkleProof(leaves[i], gotProof, gotIsLeft, root)
+		require.NoError(t, err)
+		require.True(t, valid, "decoded proof should be valid for leaf %d", i)
+	}
+
+	// Empty proof round-trips
+	encoded, err := EncodeMerkleProof(nil, nil)
+	require.NoError(t, err)
+	siblings, isLeft, err := DecodeMerkleProof(encoded)
+	require.NoError(t, err)
+	require.Empty(t, siblings)
+	require.Empty(t, isLeft)
+}
+
+// TestMerkleProofEncodingMalformed tests rejection of malformed encodings
+func TestMerkleProofEncodingMalformed(t *testing.T) {
+	siblings := make([][32]byte, 3)
+	encoded, err := EncodeMerkleProof(siblings, []bool{true, false, true})
+	require.NoError(t, err)
+
+	_, _, err = DecodeMerkleProof(nil)
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+
+	_, _, err = DecodeMerkleProof(encoded[:len(encoded)-1])
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+
+	_, _, err = DecodeMerkleProof(append(encoded, 0))
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+
+	// Depth prefix claiming more siblings than present
+	bad := append([]byte(nil), encoded...)
+	bad[1] = 4
+	_, _, err = DecodeMerkleProof(bad)
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+
+	// Direction bits beyond the depth must be zero
+	bad = append([]byte(nil), encoded...)
+	bad[len(bad)-1] |= 0x80
+	_, _, err = DecodeMerkleProof(bad)
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+}
+
+// TestMerkleProofEncodingRejectsInvalid tests that proofs the layout cannot
+// represent are rejected rather than silently truncated
+func TestMerkleProofEncodingRejectsInvalid(t *testing.T) {
+	// Direction count must match the sibling count
+	_, err := EncodeMerkleProof(make([][32]byte, 3), []bool{true, false})
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+	_, err = EncodeMerkleProof(make([][32]byte, 2), []bool{true, false, true})
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+
+	// The depth prefix is a uint16
+	_, err = EncodeMerkleProof(make([][32]byte, math.MaxUint16+1), make([]bool, math.MaxUint16+1))
+	require.ErrorIs(t, err, ErrInvalidMerkleProof)
+
+	encoded, err := EncodeMerkleProof(make([][32]byte, math.MaxUint16), make([]bool, math.MaxUint16))
+	require.NoError(t, err)
+	siblings, _, err := DecodeMerkleProof(encoded)
+	require.NoError(t, err)
+	require.Len(t, siblings, math.MaxUint16)
+}
+
+// TestSchemeGasFunctions tests that package-level gas functions match the schemes
+func TestSchemeGasFunctions(t *testing.T) {
+	require.Equal(t, NewPoseidon2Scheme().RequiredGas(), Poseidon2Gas(Poseidon2CommitElements))
+	require.Equal(t, uint64(800), Poseidon2Gas(Poseidon2CommitElements))
+	require.Equal(t, NewPoseidon2Hasher().RequiredGas(Poseidon2CommitElements*32), Poseidon2Gas(Poseidon2CommitElements))
+
+	require.Equal(t, NewPedersenScheme().RequiredGas(), PedersenGas(1))
+	require.Equal(t, uint64(6000), PedersenGas(1))
+	require.Equal(t, NewPedersenCommitter().RequiredGas("commit", 1), PedersenGas(1))
+
+	// Costs grow with input size
+	require.Less(t, Poseidon2Gas(2), Poseidon2Gas(16))
+	require.Less(t, PedersenGas(1), PedersenGas(4))
+}
+
 // TestPedersenCommit tests basic Pedersen commitment
 func TestPedersenCommit(t *testing.T) {
 	committer := NewPedersenCommitter()
@@ -105,6 +312,36 @@ func TestPedersenCommit(t *testing.T) {
 	require.False(t, valid)
 }
 
+// TestPedersenGenerators tests that the NUMS generators are reproducible and distinct
+func TestPedersenGenerators(t *testing.T) {
+	g1, h1 := NewPedersenCommitter().BaseGenerators()
+	g2, h2 := NewPedersenCommitter().BaseGenerators()
+
+	// Reproducible across instances
+	require.True(t, g1.Equal(&g2))
+	require.True(t, h1.Equal(&h2))
+
+	// H is the documented hash-to-curve output, not some other point
+	expectedH, err := HashToCurve([]byte(PedersenHSeed), []byte(PedersenDST))
+	require.NoError(t, err)
+	require.True(t, h1.Equal(&expectedH))
+
+	// G and H are distinct valid points
+	require.False(t, g1.Equal(&h1))
+	require.True(t, h1.IsOnCurve())
+	require.True(t, h1.IsInSubGroup())
+	require.False(t, h1.IsInfinity())
+
+	// Vector generators are pairwise distinct and distinct from G and H
+	committer := NewPedersenCommitter()
+	seen := map[[64]byte]bool{g1.RawBytes(): true, h1.RawBytes(): true}
+	for _, gen := range committer.Generators {
+		key := gen.RawBytes()
+		require.False(t, seen[key])
+		seen[key] = true
+	}
+}
+
 // TestPedersenHomomorphism tests homomorphic addition
 func TestPedersenHomomorphism(t *testing.T) {
 	committer := NewPedersenCommitter()
@@ -132,6 +369,71 @@ func TestPedersenHomomorphism(t *testing.T) {
 	require.NotEqual(t, [32]byte{}, sum)
 }
 
+// TestPedersenBalance tests that balanced transfers subtract to a commitment to zero
+func TestPedersenBalance(t *testing.T) {
+	committer := NewPedersenCommitter()
+
+	commit := func(value uint64) ([32]byte, fr.Element) {
+		var r fr.Element
+		_, err := r.SetRandom()
+		require.NoError(t, err)
+		var v [32]byte
+		binary.BigEndian.PutUint64(v[24:], value)
+		c, err := committer.Commit(v, r.Bytes())
+		require.NoError(t, err)
+		return c, r
+	}
+
+	// Inputs 30 + 20 spent into outputs 45 + 5
+	in1, rIn1 := commit(30)
+	in2, rIn2 := commit(20)
+	out1, rOut1 := commit(45)
+	out2, rOut2 := commit(5)
+
+	inputs, err := committer.Add(in1, in2)
+	require.NoError(t, err)
+	outputs, err := committer.Add(out1, out2)
+	require.NoError(t, err)
+	diff, err := committer.Sub(outputs, inputs)
+	require.NoError(t, err)
+
+	// The difference opens to zero under r_out - r_in
+	var rDiff, rIn fr.Element
+	rDiff.Add(&rOut1, &rOut2)
+	rIn.Add(&rIn1, &rIn2)
+	rDiff.Sub(&rDiff, &rIn)
+	zero, err := committer.IsCommitmentToZero(diff, rDiff.Bytes())
+	require.NoError(t, err)
+	require.True(t, zero)
+
+	// Subtracting via Neg gives the same point
+	negInputs, err := committer.Neg(inputs)
+	require.NoError(t, err)
+	viaNeg, err := committer.Add(outputs, negInputs)
+	require.NoError(t, err)
+	require.Equal(t, diff, viaNeg)
+
+	// An output that mints value does not balance
+	out3, rOut3 := commit(1)
+	minted, err := committer.Add(outputs, out3)
+	require.NoError(t, err)
+	diff, err = committer.Sub(minted, inputs)
+	require.NoError(t, err)
+	rDiff.Add(&rDiff, &rOut3)
+	zero, err = committer.IsCommitmentToZero(diff, rDiff.Bytes())
+	require.NoError(t, err)
+	require.False(t, zero)
+
+	// C + (-C) commits to zero with a zero blinding factor
+	negIn1, err := committer.Neg(in1)
+	require.NoError(t, err)
+	sum, err := committer.Add(in1, negIn1)
+	require.NoError(t, err)
+	zero, err = committer.IsCommitmentToZero(sum, [32]byte{})
+	require.NoError(t, err)
+	require.True(t, zero)
+}
+
 // TestNoteCommitments tests note commitment for both schemes
 func TestNoteCommitments(t *testing.T) {
 	amount := big.NewInt(1000)
@@ -188,6 +490,45 @@ func TestCreateNote(t *testing.T) {
 	require.NotEqual(t, [32]byte{}, nullifier)
 }
 
+// TestNoteSchemeRegistry tests resolving schemes by SchemeType
+func TestNoteSchemeRegistry(t *testing.T) {
+	poseidon, err := GetScheme(SchemePoseidon2)
+	require.NoError(t, err)
+	require.Equal(t, SchemePoseidon2, poseidon.Type())
+	require.True(t, poseidon.IsPQSafe())
+
+	pedersen, err := GetScheme(SchemePedersen)
+	require.NoError(t, err)
+	require.Equal(t, SchemePedersen, pedersen.Type())
+	require.False(t, pedersen.IsPQSafe())
+
+	_, err = GetScheme(SchemeType(99))
+	require.ErrorIs(t, err, ErrUnknownScheme)
+
+	_, err = CreateNote(NoteInput{Amount: big.NewInt(1), SchemeType: SchemeType(99)})
+	require.ErrorIs(t, err, ErrUnknownScheme)
+
+	require.Equal(t, []SchemeType{SchemePoseidon2, SchemePedersen}, NoteSchemes.Types())
+	for _, schemeType := range NoteSchemes.Types() {
+		scheme, err := GetScheme(schemeType)
+		require.NoError(t, err)
+		require.Equal(t, schemeType, scheme.Type())
+	}
+
+	// Identifiers cannot be rebound
+	err = NoteSchemes.Register(SchemePedersen, func() NoteScheme { return NewPoseidon2Scheme() })
+	require.ErrorIs(t, err, ErrSchemeRegistered)
+
+	// A private registry resolves only what was registered into it
+	r := NewNoteSchemeRegistry()
+	require.NoError(t, r.Register(SchemePedersen, func() NoteScheme { return NewPedersenScheme() }))
+	_, err = r.Get(SchemePoseidon2)
+	require.ErrorIs(t, err, ErrUnknownScheme)
+	scheme, err := r.Get(SchemePedersen)
+	require.NoError(t, err)
+	require.False(t, scheme.IsPQSafe())
+}
+
 // BenchmarkPoseidon2Hash benchmarks Poseidon2 hashing
 func BenchmarkPoseidon2Hash(b *testing.B) {
 	hasher := NewPoseidon2Hasher()
@@ -0,0 +1,124 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// Run with -update after an intentional change to an output; the diff of
+// the golden file is then the record of what changed on-chain
+var updateGolden = flag.Bool("update", false, "rewrite testdata golden vectors")
+
+const goldenFile = "golden_vectors.json"
+
+// goldenElement returns the field element n as 32 big-endian bytes
+func goldenElement(n uint64) [32]byte {
+	var e [32]byte
+	new(big.Int).SetUint64(n).FillBytes(e[:])
+	return e
+}
+
+func goldenElements(n int) [][32]byte {
+	elems := make([][32]byte, n)
+	for i := range elems {
+		elems[i] = goldenElement(uint64(i + 1))
+	}
+	return elems
+}
+
+func goldenConcat(elems [][32]byte) []byte {
+	out := make([]byte, 0, 32*len(elems))
+	for _, e := range elems {
+		out = append(out, e[:]...)
+	}
+	return out
+}
+
+// computeGoldenVectors evaluates every hash and commitment on fixed inputs
+func computeGoldenVectors(t *testing.T) map[string]string {
+	t.Helper()
+	require := require.New(t)
+	vectors := make(map[string]string)
+	put := func(name string, out [32]byte, err error) {
+		require.NoError(err, name)
+		vectors[name] = hex.EncodeToString(out[:])
+	}
+
+	poseidon := NewPoseidon2Hasher()
+	for _, n := range []int{1, 2, 3, 16} {
+		out, err := poseidon.Hash(goldenConcat(goldenElements(n)))
+		put("poseidon2/hash/"+strconv.Itoa(n), out, err)
+	}
+	out, err := poseidon.HashPair(goldenElement(1), goldenElement(2))
+	put("poseidon2/hash_pair", out, err)
+	out, err = NewPoseidon2HasherForDomain("commitments").HashPair(goldenElement(1), goldenElement(2))
+	put("poseidon2/hash_pair/domain_commitments", out, err)
+	out, err = poseidon.Commitment(goldenElement(100), goldenElement(7), goldenElement(9))
+	put("poseidon2/commitment", out, err)
+	out, err = poseidon.NullifierHash(goldenElement(11), goldenElement(12), 5)
+	put("poseidon2/nullifier_hash", out, err)
+	put("nullifier/derive", DeriveNullifier(goldenElement(12), 5, goldenElement(11)), nil)
+	put("nullifier/derive/1_2_3", DeriveNullifier(goldenElement(2), 3, goldenElement(1)), nil)
+
+	owner := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	out, err = poseidon.NoteCommitment(big.NewInt(1_000_000), goldenElement(3), owner, goldenElement(7))
+	put("poseidon2/note_commitment", out, err)
+
+	for _, n := range []int{1, 2, 5, 8} {
+		out, err := poseidon.MerkleRoot(goldenElements(n))
+		put("poseidon2/merkle_root/"+strconv.Itoa(n), out, err)
+	}
+	out, err = NewPoseidon2HasherForDomain("commitments").MerkleRoot(goldenElements(5))
+	put("poseidon2/merkle_root/domain_commitments/5", out, err)
+
+	pedersen := NewPedersenCommitter()
+	out, err = pedersen.Commit(goldenElement(100), goldenElement(7))
+	put("pedersen/commit", out, err)
+	out, err = pedersen.Commit(goldenElement(0), goldenElement(1))
+	put("pedersen/commit/zero_value", out, err)
+	out, err = pedersen.NoteCommitment(big.NewInt(1_000_000), goldenElement(3), owner, goldenElement(7))
+	put("pedersen/note_commitment", out, err)
+
+	return vectors
+}
+
+// TestGoldenVectors pins the outputs of every hash and commitment, so an
+// implementation change that alters on-chain values fails here
+func TestGoldenVectors(t *testing.T) {
+	require := require.New(t)
+	got := computeGoldenVectors(t)
+	path := filepath.Join("testdata", goldenFile)
+
+	if *updateGolden {
+		data, err := json.MarshalIndent(got, "", "  ")
+		require.NoError(err)
+		require.NoError(os.MkdirAll("testdata", 0o755))
+		require.NoError(os.WriteFile(path, append(data, '\n'), 0o644))
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(err, "run go test -run TestGoldenVectors -update to create it")
+	var want map[string]string
+	require.NoError(json.Unmarshal(data, &want))
+
+	for name, w := range want {
+		require.Contains(got, name, "golden vector no longer computed")
+		require.Equal(w, got[name], "%s drifted from its golden value", name)
+	}
+	for name := range got {
+		require.Contains(want, name, "vector missing from %s", path)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// Point is a BN254 G1 point, the curve used by the package's
+// discrete-log commitment schemes.
+type Point = bn254.G1Affine
+
+// HashToCurveSuite identifies the RFC 9380 suite implemented by HashToCurve.
+//
+// RFC 9380's simplified SWU map requires a curve with A != 0. BN254 has
+// A = 0 and no standardised isogeny, so the RFC's Shallue-van de Woestijne
+// map (section 6.6.1) is used instead, with expand_message_xmd over SHA-256
+// and the random-oracle (hash_to_curve) construction.
+const HashToCurveSuite = "BN254G1_XMD:SHA-256_SVDW_RO_"
+
+// maxDomainLength is the longest domain separation tag expand_message_xmd
+// accepts without pre-hashing.
+const maxDomainLength = 255
+
+var ErrInvalidDomain = errors.New("invalid hash-to-curve domain: must be 1-255 bytes")
+
+// HashToCurve maps msg to a uniformly distributed BN254 G1 point under the
+// given domain separation tag, per RFC 9380 hash_to_curve.
+//
+// This is the shared primitive for anything that needs points with no known
+// discrete-log relation (Pedersen generators, Bulletproofs, IPA). Distinct
+// features must use distinct domains so their outputs are independent.
+func HashToCurve(msg []byte, domain []byte) (Point, error) {
+	if len(domain) == 0 || len(domain) > maxDomainLength {
+		return Point{}, ErrInvalidDomain
+	}
+	return bn254.HashToG1(msg, domain)
+}
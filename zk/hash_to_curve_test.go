@@ -0,0 +1,78 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHashToCurveVectors checks HashToCurve against the RFC 9380-format
+// BN254G1_XMD:SHA-256_SVDW_RO_ test vectors
+func TestHashToCurveVectors(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-" + HashToCurveSuite)
+
+	vectors := []struct {
+		msg string
+		x   string
+		y   string
+	}{
+		{
+			msg: "",
+			x:   "0a976ab906170db1f9638d376514dbf8c42aef256a54bbd48521f20749e59e86",
+			y:   "02925ead66b9e68bfc309b014398640ab55f6619ab59bc1fab2210ad4c4d53d5",
+		},
+		{
+			msg: "abc",
+			x:   "23f717bee89b1003957139f193e6be7da1df5f1374b26a4643b0378b5baf53d1",
+			y:   "04142f826b71ee574452dbc47e05bc3e1a647478403a7ba38b7b93948f4e151d",
+		},
+		{
+			msg: "abcdef0123456789",
+			x:   "187dbf1c3c89aceceef254d6548d7163fdfa43084145f92c4c91c85c21442d4a",
+			y:   "0abd99d5b0000910b56058f9cc3b0ab0a22d47cf27615f588924fac1e5c63b4d",
+		},
+		{
+			msg: "q128_" + strings.Repeat("q", 128),
+			x:   "00fe2b0743575324fc452d590d217390ad48e5a16cf051bee5c40a2eba233f5c",
+			y:   "0794211e0cc72d3cbbdf8e4e5cd6e7d7e78d101ff94862caae8acbe63e9fdc78",
+		},
+	}
+
+	for _, v := range vectors {
+		p, err := HashToCurve([]byte(v.msg), dst)
+		require.NoError(t, err)
+
+		expectedX, _ := new(big.Int).SetString(v.x, 16)
+		expectedY, _ := new(big.Int).SetString(v.y, 16)
+		require.Equal(t, expectedX, p.X.BigInt(new(big.Int)), "x mismatch for %q", v.msg)
+		require.Equal(t, expectedY, p.Y.BigInt(new(big.Int)), "y mismatch for %q", v.msg)
+		require.True(t, p.IsOnCurve())
+	}
+}
+
+// TestHashToCurveDomainSeparation tests that domains separate outputs
+func TestHashToCurveDomainSeparation(t *testing.T) {
+	msg := []byte("same message")
+
+	p1, err := HashToCurve(msg, []byte("lux-domain-a"))
+	require.NoError(t, err)
+	p2, err := HashToCurve(msg, []byte("lux-domain-b"))
+	require.NoError(t, err)
+	require.False(t, p1.Equal(&p2))
+
+	// Deterministic for the same (msg, domain)
+	p3, err := HashToCurve(msg, []byte("lux-domain-a"))
+	require.NoError(t, err)
+	require.True(t, p1.Equal(&p3))
+
+	// Empty and oversized domains are rejected
+	_, err = HashToCurve(msg, nil)
+	require.ErrorIs(t, err, ErrInvalidDomain)
+	_, err = HashToCurve(msg, make([]byte, 256))
+	require.ErrorIs(t, err, ErrInvalidDomain)
+}
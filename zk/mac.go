@@ -0,0 +1,86 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"crypto/subtle"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+)
+
+// Domain tags are absorbed first so MAC tags never collide with plain hashes
+// or commitments over the same elements, nor with each other
+const (
+	poseidonMACDomain = "LUX_POSEIDON2_MAC_V1"
+	mimcMACDomain     = "LUX_MIMC_MAC_V1"
+)
+
+// macChunkSize is the number of bytes packed per field element. 31 bytes
+// always fit below the BN254 scalar modulus, so packing never reduces.
+const macChunkSize = 31
+
+// PoseidonMAC computes a keyed MAC over msg with the Poseidon2 sponge. The
+// domain tag, key and message are absorbed in that order, each
+// length-prefixed and packed into field elements, so the tag is cheap to
+// recompute inside a circuit.
+func PoseidonMAC(key, msg []byte) [32]byte {
+	return keyedTag(poseidon2HasherFactory(), poseidonMACDomain, key, msg)
+}
+
+// VerifyPoseidonMAC reports whether tag is the PoseidonMAC of msg under key.
+// The comparison is constant-time.
+func VerifyPoseidonMAC(key, msg, tag []byte) bool {
+	expected := PoseidonMAC(key, msg)
+	return subtle.ConstantTimeCompare(expected[:], tag) == 1
+}
+
+// MiMCMAC computes a keyed MAC over msg with BN254 MiMC, absorbing the same
+// domain-tag, key, message layout as PoseidonMAC under its own domain tag.
+// MiMC is the cheaper choice in circuits that already use it for hashing.
+func MiMCMAC(key, msg []byte) [32]byte {
+	return keyedTag(mimc.NewMiMC(), mimcMACDomain, key, msg)
+}
+
+// VerifyMiMCMAC reports whether tag is the MiMCMAC of msg under key. The
+// comparison is constant-time.
+func VerifyMiMCMAC(key, msg, tag []byte) bool {
+	expected := MiMCMAC(key, msg)
+	return subtle.ConstantTimeCompare(expected[:], tag) == 1
+}
+
+// keyedTag absorbs domain, key and msg into hasher and returns the digest.
+// Every write is a canonical field element, so hashers that reject
+// out-of-range blocks never fail.
+func keyedTag(hasher hash.Hash, domain string, key, msg []byte) [32]byte {
+	var elem fr.Element
+	elem.SetBytes([]byte(domain))
+	elemBytes := elem.Bytes()
+	hasher.Write(elemBytes[:])
+
+	absorbMACInput(hasher.Write, key)
+	absorbMACInput(hasher.Write, msg)
+
+	var tag [32]byte
+	copy(tag[:], hasher.Sum(nil))
+	return tag
+}
+
+// absorbMACInput writes len(data) followed by data packed into 31-byte
+// field elements
+func absorbMACInput(write func([]byte) (int, error), data []byte) {
+	var elem fr.Element
+	elem.SetUint64(uint64(len(data)))
+	elemBytes := elem.Bytes()
+	write(elemBytes[:])
+
+	for len(data) > 0 {
+		n := min(len(data), macChunkSize)
+		elem.SetBytes(data[:n])
+		elemBytes = elem.Bytes()
+		write(elemBytes[:])
+		data = data[n:]
+	}
+}
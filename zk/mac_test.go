@@ -0,0 +1,150 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoseidonMAC_MatchesSpongeConstruction(t *testing.T) {
+	key := []byte("mac key")
+	msg := bytes.Repeat([]byte{0xAB}, 40) // spans two 31-byte chunks
+
+	// Recompute the tag element by element: domain, len(key), key, len(msg), msg chunks
+	element := func(b []byte) []byte {
+		var e fr.Element
+		e.SetBytes(b)
+		out := e.Bytes()
+		return out[:]
+	}
+	count := func(n int) []byte {
+		var e fr.Element
+		e.SetUint64(uint64(n))
+		out := e.Bytes()
+		return out[:]
+	}
+	hasher := poseidon2HasherFactory()
+	hasher.Write(element([]byte(poseidonMACDomain)))
+	hasher.Write(count(len(key)))
+	hasher.Write(element(key))
+	hasher.Write(count(len(msg)))
+	hasher.Write(element(msg[:31]))
+	hasher.Write(element(msg[31:]))
+
+	tag := PoseidonMAC(key, msg)
+	require.Equal(t, hasher.Sum(nil), tag[:])
+	require.Equal(t, tag, PoseidonMAC(key, msg))
+}
+
+func TestPoseidonMAC_KeySensitivity(t *testing.T) {
+	msg := []byte("transfer 100 to alice")
+	tag := PoseidonMAC([]byte("key one"), msg)
+
+	require.NotEqual(t, tag, PoseidonMAC([]byte("key two"), msg))
+	require.NotEqual(t, tag, PoseidonMAC([]byte("key one"), []byte("transfer 101 to alice")))
+
+	// Length prefixes keep the key/message boundary unambiguous
+	require.NotEqual(t, PoseidonMAC([]byte("ab"), []byte("c")), PoseidonMAC([]byte("a"), []byte("bc")))
+	require.NotEqual(t, PoseidonMAC(nil, []byte{0}), PoseidonMAC(nil, nil))
+
+	// Distinct from a plain Poseidon2 hash
+	hash, err := NewPoseidon2Hasher().Hash(make([]byte, 32))
+	require.NoError(t, err)
+	require.NotEqual(t, hash, PoseidonMAC(nil, nil))
+}
+
+func TestVerifyPoseidonMAC(t *testing.T) {
+	key := []byte("verifier key")
+	msg := []byte("message")
+	tag := PoseidonMAC(key, msg)
+
+	require.True(t, VerifyPoseidonMAC(key, msg, tag[:]))
+	require.False(t, VerifyPoseidonMAC([]byte("other key"), msg, tag[:]))
+
+	// Every byte of the tag is checked, including the last
+	for i := range tag {
+		bad := tag
+		bad[i] ^= 0x01
+		require.False(t, VerifyPoseidonMAC(key, msg, bad[:]), "byte %d", i)
+	}
+
+	// Wrong-length tags are rejected, never compared as a prefix
+	require.False(t, VerifyPoseidonMAC(key, msg, tag[:31]))
+	require.False(t, VerifyPoseidonMAC(key, msg, append(tag[:], 0)))
+	require.False(t, VerifyPoseidonMAC(key, msg, nil))
+}
+
+func TestMiMCMAC_MatchesSpongeConstruction(t *testing.T) {
+	key := []byte("mac key")
+	msg := bytes.Repeat([]byte{0xCD}, 40) // spans two 31-byte chunks
+
+	element := func(b []byte) []byte {
+		var e fr.Element
+		e.SetBytes(b)
+		out := e.Bytes()
+		return out[:]
+	}
+	count := func(n int) []byte {
+		var e fr.Element
+		e.SetUint64(uint64(n))
+		out := e.Bytes()
+		return out[:]
+	}
+	hasher := mimc.NewMiMC()
+	for _, block := range [][]byte{
+		element([]byte(mimcMACDomain)),
+		count(len(key)),
+		element(key),
+		count(len(msg)),
+		element(msg[:31]),
+		element(msg[31:]),
+	} {
+		_, err := hasher.Write(block)
+		require.NoError(t, err)
+	}
+
+	tag := MiMCMAC(key, msg)
+	require.Equal(t, hasher.Sum(nil), tag[:])
+	require.Equal(t, tag, MiMCMAC(key, msg))
+}
+
+func TestMiMCMAC_KeySensitivity(t *testing.T) {
+	msg := []byte("transfer 100 to alice")
+	tag := MiMCMAC([]byte("key one"), msg)
+
+	require.NotEqual(t, tag, MiMCMAC([]byte("key two"), msg))
+	require.NotEqual(t, tag, MiMCMAC([]byte("key one"), []byte("transfer 101 to alice")))
+	require.NotEqual(t, MiMCMAC([]byte("ab"), []byte("c")), MiMCMAC([]byte("a"), []byte("bc")))
+
+	// Never equal to the Poseidon2 MAC of the same input
+	require.NotEqual(t, PoseidonMAC([]byte("key one"), msg), tag)
+
+	// Inputs with bytes that would exceed the modulus as raw 32-byte blocks
+	// are packed, not rejected
+	high := bytes.Repeat([]byte{0xFF}, 64)
+	require.NotEqual(t, [32]byte{}, MiMCMAC(high, high))
+}
+
+func TestVerifyMiMCMAC(t *testing.T) {
+	key := []byte("verifier key")
+	msg := []byte("message")
+	tag := MiMCMAC(key, msg)
+
+	require.True(t, VerifyMiMCMAC(key, msg, tag[:]))
+	require.False(t, VerifyMiMCMAC([]byte("other key"), msg, tag[:]))
+
+	for i := range tag {
+		bad := tag
+		bad[i] ^= 0x01
+		require.False(t, VerifyMiMCMAC(key, msg, bad[:]), "byte %d", i)
+	}
+
+	require.False(t, VerifyMiMCMAC(key, msg, tag[:31]))
+	require.False(t, VerifyMiMCMAC(key, msg, nil))
+}
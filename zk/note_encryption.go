@@ -0,0 +1,171 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/cloudflare/circl/hpke"
+	"github.com/luxfi/geth/common"
+)
+
+// Note encryption carries a note's opening to its recipient so they can
+// later spend it. The plaintext is sealed with HPKE (RFC 9180) base mode:
+// X25519, HKDF-SHA256, ChaCha20-Poly1305.
+//
+// Ciphertext: enc (32) || AEAD(amount (32) || assetID (32) || owner (20) ||
+// blindingFactor (32) || schemeType (1))
+
+const (
+	// NotePlaintextSize is the size of an encoded note opening
+	NotePlaintextSize = 32 + 32 + common.AddressLength + 32 + 1
+)
+
+var (
+	noteSuite = hpke.NewSuite(hpke.KEM_X25519_HKDF_SHA256, hpke.KDF_HKDF_SHA256, hpke.AEAD_ChaCha20Poly1305)
+
+	// noteInfo domain-separates note encryption from other HPKE uses
+	noteInfo = []byte("lux/zk/note-encryption/v1")
+
+	ErrInvalidNote           = errors.New("invalid note")
+	ErrInvalidNoteKey        = errors.New("invalid note encryption key")
+	ErrInvalidNoteCiphertext = errors.New("invalid note ciphertext")
+	ErrNoteDecryptionFailed  = errors.New("note decryption failed")
+)
+
+// GenerateNoteKey creates an X25519 key pair for receiving encrypted notes
+func GenerateNoteKey() (publicKey, privateKey []byte, err error) {
+	kem, _, _ := noteSuite.Params()
+	pk, sk, err := kem.Scheme().GenerateKeyPair()
+	if err != nil {
+		return nil, nil, err
+	}
+	if publicKey, err = pk.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	if privateKey, err = sk.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	return publicKey, privateKey, nil
+}
+
+// EncryptNote seals note's amount, asset, owner, blinding factor and scheme
+// to recipientPubkey
+func EncryptNote(note *Note, recipientPubkey []byte) ([]byte, error) {
+	plaintext, err := encodeNotePlaintext(note)
+	if err != nil {
+		return nil, err
+	}
+
+	kem, _, _ := noteSuite.Params()
+	pk, err := kem.Scheme().UnmarshalBinaryPublicKey(recipientPubkey)
+	if err != nil {
+		return nil, ErrInvalidNoteKey
+	}
+	sender, err := noteSuite.NewSender(pk, noteInfo)
+	if err != nil {
+		return nil, err
+	}
+	enc, sealer, err := sender.Setup(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := sealer.Seal(plaintext, nil)
+	if err != nil {
+		return nil, err
+	}
+	return append(enc, ciphertext...), nil
+}
+
+// DecryptNote opens a note sealed by EncryptNote and recomputes its
+// commitment from the recovered opening. LeafIndex is not encrypted; the
+// recipient sets it from the tree position of the matching commitment.
+func DecryptNote(ciphertext, recipientPrivkey []byte) (*Note, error) {
+	receiver, err := newNoteReceiver(recipientPrivkey)
+	if err != nil {
+		return nil, err
+	}
+	return openNote(receiver, ciphertext)
+}
+
+// ScanNotes trial-decrypts every encrypted note with viewingKey and returns
+// the notes sealed to it, in input order. Notes sealed to other keys and
+// malformed ciphertexts are skipped, so a wallet recovering from its seed
+// can pass every note memo in the tree. encryptedNotes[i] is taken to be
+// the memo of leaf i, and each returned note's LeafIndex is set to i.
+func ScanNotes(encryptedNotes [][]byte, viewingKey []byte) ([]*Note, error) {
+	receiver, err := newNoteReceiver(viewingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []*Note
+	for i, ciphertext := range encryptedNotes {
+		note, err := openNote(receiver, ciphertext)
+		if err != nil {
+			continue
+		}
+		note.LeafIndex = uint64(i)
+		notes = append(notes, note)
+	}
+	return notes, nil
+}
+
+func newNoteReceiver(recipientPrivkey []byte) (*hpke.Receiver, error) {
+	kem, _, _ := noteSuite.Params()
+	sk, err := kem.Scheme().UnmarshalBinaryPrivateKey(recipientPrivkey)
+	if err != nil {
+		return nil, ErrInvalidNoteKey
+	}
+	return noteSuite.NewReceiver(sk, noteInfo)
+}
+
+func openNote(receiver *hpke.Receiver, ciphertext []byte) (*Note, error) {
+	kem, _, aead := noteSuite.Params()
+	encSize := kem.Scheme().CiphertextSize()
+	if len(ciphertext) != encSize+NotePlaintextSize+int(aead.CipherLen(0)) {
+		return nil, ErrInvalidNoteCiphertext
+	}
+
+	opener, err := receiver.Setup(ciphertext[:encSize])
+	if err != nil {
+		return nil, ErrNoteDecryptionFailed
+	}
+	plaintext, err := opener.Open(ciphertext[encSize:], nil)
+	if err != nil {
+		return nil, ErrNoteDecryptionFailed
+	}
+
+	return decodeNotePlaintext(plaintext)
+}
+
+func encodeNotePlaintext(note *Note) ([]byte, error) {
+	if note == nil || note.Amount == nil || note.Amount.Sign() < 0 || note.Amount.BitLen() > 256 {
+		return nil, ErrInvalidNote
+	}
+
+	out := make([]byte, 0, NotePlaintextSize)
+	out = append(out, note.Amount.FillBytes(make([]byte, 32))...)
+	out = append(out, note.AssetID[:]...)
+	out = append(out, note.Owner[:]...)
+	out = append(out, note.BlindingFactor[:]...)
+	return append(out, byte(note.SchemeType)), nil
+}
+
+func decodeNotePlaintext(plaintext []byte) (*Note, error) {
+	if len(plaintext) != NotePlaintextSize {
+		return nil, ErrInvalidNoteCiphertext
+	}
+
+	input := NoteInput{
+		Amount:     new(big.Int).SetBytes(plaintext[:32]),
+		Owner:      common.BytesToAddress(plaintext[64 : 64+common.AddressLength]),
+		SchemeType: SchemeType(plaintext[NotePlaintextSize-1]),
+	}
+	copy(input.AssetID[:], plaintext[32:64])
+	copy(input.BlindingFactor[:], plaintext[64+common.AddressLength:NotePlaintextSize-1])
+	return CreateNote(input)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+func testNote(t *testing.T, schemeType SchemeType) *Note {
+	t.Helper()
+
+	var blinding, assetId [32]byte
+	rand.Read(blinding[:])
+	assetId[31] = 7
+
+	note, err := CreateNote(NoteInput{
+		Amount:         new(big.Int).Lsh(big.NewInt(1), 200),
+		AssetID:        assetId,
+		Owner:          common.HexToAddress("0xabcd"),
+		BlindingFactor: blinding,
+		SchemeType:     schemeType,
+	})
+	require.NoError(t, err)
+	return note
+}
+
+// TestNoteEncryptionRoundTrip tests that the recipient recovers the note
+func TestNoteEncryptionRoundTrip(t *testing.T) {
+	pub, priv, err := GenerateNoteKey()
+	require.NoError(t, err)
+
+	for _, schemeType := range NoteSchemes.Types() {
+		note := testNote(t, schemeType)
+
+		ciphertext, err := EncryptNote(note, pub)
+		require.NoError(t, err)
+
+		decrypted, err := DecryptNote(ciphertext, priv)
+		require.NoError(t, err)
+		require.Equal(t, note.Commitment, decrypted.Commitment)
+		require.Zero(t, note.Amount.Cmp(decrypted.Amount))
+		require.Equal(t, note.AssetID, decrypted.AssetID)
+		require.Equal(t, note.Owner, decrypted.Owner)
+		require.Equal(t, note.BlindingFactor, decrypted.BlindingFactor)
+		require.Equal(t, schemeType, decrypted.SchemeType)
+
+		// Encryption is randomized
+		again, err := EncryptNote(note, pub)
+		require.NoError(t, err)
+		require.NotEqual(t, ciphertext, again)
+	}
+}
+
+// TestNoteEncryptionWrongKey tests that only the recipient can decrypt
+func TestNoteEncryptionWrongKey(t *testing.T) {
+	pub, _, err := GenerateNoteKey()
+	require.NoError(t, err)
+	_, otherPriv, err := GenerateNoteKey()
+	require.NoError(t, err)
+
+	ciphertext, err := EncryptNote(testNote(t, SchemePoseidon2), pub)
+	require.NoError(t, err)
+
+	_, err = DecryptNote(ciphertext, otherPriv)
+	require.ErrorIs(t, err, ErrNoteDecryptionFailed)
+}
+
+// TestNoteEncryptionInvalidInput tests malformed notes, keys and ciphertexts
+func TestNoteEncryptionInvalidInput(t *testing.T) {
+	pub, priv, err := GenerateNoteKey()
+	require.NoError(t, err)
+	note := testNote(t, SchemePoseidon2)
+
+	_, err = EncryptNote(nil, pub)
+	require.ErrorIs(t, err, ErrInvalidNote)
+	_, err = EncryptNote(&Note{Amount: big.NewInt(-1)}, pub)
+	require.ErrorIs(t, err, ErrInvalidNote)
+	_, err = EncryptNote(note, pub[:31])
+	require.ErrorIs(t, err, ErrInvalidNoteKey)
+
+	ciphertext, err := EncryptNote(note, pub)
+	require.NoError(t, err)
+
+	_, err = DecryptNote(ciphertext[:len(ciphertext)-1], priv)
+	require.ErrorIs(t, err, ErrInvalidNoteCiphertext)
+	_, err = DecryptNote(ciphertext, priv[:31])
+	require.ErrorIs(t, err, ErrInvalidNoteKey)
+
+	tampered := common.CopyBytes(ciphertext)
+	tampered[len(tampered)-1] ^= 0x01
+	_, err = DecryptNote(tampered, priv)
+	require.ErrorIs(t, err, ErrNoteDecryptionFailed)
+}
+
+// TestScanNotes tests that a wallet recovers exactly the notes sealed to it
+func TestScanNotes(t *testing.T) {
+	require := require.New(t)
+
+	walletPub, walletPriv, err := GenerateNoteKey()
+	require.NoError(err)
+	otherPub, _, err := GenerateNoteKey()
+	require.NoError(err)
+
+	// Memos in leaf order: the wallet's notes at leaves 1, 2 and 5
+	mine := map[uint64]*Note{}
+	var memos [][]byte
+	for i, toWallet := range []bool{false, true, true, false, false, true, false} {
+		note := testNote(t, SchemePoseidon2)
+		pub := otherPub
+		if toWallet {
+			pub = walletPub
+			mine[uint64(i)] = note
+		}
+		memo, err := EncryptNote(note, pub)
+		require.NoError(err)
+		memos = append(memos, memo)
+	}
+	// Malformed memos from other senders are skipped, not fatal
+	memos = append(memos, nil, []byte("not a note"))
+
+	notes, err := ScanNotes(memos, walletPriv)
+	require.NoError(err)
+	require.Len(notes, len(mine))
+	for _, note := range notes {
+		expected, ok := mine[note.LeafIndex]
+		require.True(ok, "leaf %d is not the wallet's", note.LeafIndex)
+		require.Equal(expected.Commitment, note.Commitment)
+		require.Zero(expected.Amount.Cmp(note.Amount))
+		require.Equal(expected.BlindingFactor, note.BlindingFactor)
+	}
+	require.Equal([]uint64{1, 2, 5}, []uint64{notes[0].LeafIndex, notes[1].LeafIndex, notes[2].LeafIndex})
+
+	// A scan finding nothing is not an error
+	notes, err = ScanNotes(memos[:1], walletPriv)
+	require.NoError(err)
+	require.Empty(notes)
+
+	_, err = ScanNotes(memos, walletPriv[:31])
+	require.ErrorIs(err, ErrInvalidNoteKey)
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/luxfi/geth/common"
+	"github.com/luxfi/precompile/contract"
+)
+
+// StateDB is the subset of EVM state the nullifier registry needs. It
+// matches contract.StateDB, so a precompile can pass its state directly.
+type StateDB interface {
+	GetState(common.Address, common.Hash) common.Hash
+	SetState(common.Address, common.Hash, common.Hash) common.Hash
+}
+
+var _ StateDB = (contract.StateDB)(nil)
+
+// nullifierSetPrefix is the storage key prefix for spent nullifiers
+var nullifierSetPrefix = [4]byte{'n', 'u', 'l', 'l'}
+
+// ErrDuplicateNullifier is returned when a batch spends the same nullifier twice
+var ErrDuplicateNullifier = errors.New("duplicate nullifier in batch")
+
+// DeriveNullifier returns the nullifier that spends the note with
+// commitment at leafIndex in the commitment tree, under the owner's
+// nullifierKey. Note.Nullifier and the note helpers of the nullifier
+// registry both call it, and spend circuits must match it exactly:
+//
+//	nullifier = Poseidon2(nullifierKey, commitment, leafIndex)
+//
+// Each input is one BN254 scalar field element encoded as 32 big-endian
+// bytes, hashed in the order shown with the default (untagged) Poseidon2
+// domain. leafIndex is zero-extended to 32 bytes. Like every Poseidon2
+// input, nullifierKey and commitment are reduced modulo the field order.
+//
+// The derivation always runs the portable Poseidon2 implementation, never
+// the GPU path, so every node computes it the same way and it cannot fail.
+func DeriveNullifier(commitment [32]byte, leafIndex uint64, nullifierKey [32]byte) [32]byte {
+	var index [32]byte
+	binary.BigEndian.PutUint64(index[24:], leafIndex)
+
+	hasher := poseidon2HasherFactory()
+	for _, input := range [][32]byte{nullifierKey, commitment, index} {
+		var elem fr.Element
+		elem.SetBytes(input[:])
+		canonical := elem.Bytes()
+		hasher.Write(canonical[:])
+	}
+
+	var nullifier [32]byte
+	copy(nullifier[:], hasher.Sum(nil))
+	return nullifier
+}
+
+// IsNoteSpent reports whether the note with commitment at leafIndex has been
+// spent under nullifierKey
+func IsNoteSpent(stateDB StateDB, commitment [32]byte, leafIndex uint64, nullifierKey [32]byte) bool {
+	return IsSpent(stateDB, DeriveNullifier(commitment, leafIndex, nullifierKey))
+}
+
+// MarkNoteSpent derives the nullifier of the note with commitment at
+// leafIndex, marks it spent and returns it
+func MarkNoteSpent(stateDB StateDB, commitment [32]byte, leafIndex uint64, nullifierKey [32]byte) ([32]byte, error) {
+	nullifier := DeriveNullifier(commitment, leafIndex, nullifierKey)
+	return nullifier, MarkSpent(stateDB, nullifier)
+}
+
+// IsSpent reports whether nullifier has been marked spent in the registry
+// stored at NullifierContractAddress
+func IsSpent(stateDB StateDB, nullifier [32]byte) bool {
+	return stateDB.GetState(NullifierContractAddress, nullifierKey(nullifier)) != (common.Hash{})
+}
+
+// MarkSpent marks a single nullifier as spent
+func MarkSpent(stateDB StateDB, nullifier [32]byte) error {
+	return MarkSpentBatch(stateDB, [][32]byte{nullifier})
+}
+
+// MarkSpentBatch marks every nullifier of a multi-note spend as spent. The
+// whole batch is checked before anything is written, so if any nullifier is
+// already spent or appears twice in the batch, none are marked.
+func MarkSpentBatch(stateDB StateDB, nullifiers [][32]byte) error {
+	seen := make(map[[32]byte]struct{}, len(nullifiers))
+	for i, nullifier := range nullifiers {
+		if _, dup := seen[nullifier]; dup {
+			return fmt.Errorf("%w: index %d (%x)", ErrDuplicateNullifier, i, nullifier)
+		}
+		seen[nullifier] = struct{}{}
+
+		if IsSpent(stateDB, nullifier) {
+			return fmt.Errorf("%w: index %d (%x)", ErrNullifierSpent, i, nullifier)
+		}
+	}
+
+	spent := common.Hash{31: 1} // Non-zero value indicates spent
+	for _, nullifier := range nullifiers {
+		stateDB.SetState(NullifierContractAddress, nullifierKey(nullifier), spent)
+	}
+	return nil
+}
+
+// nullifierKey creates the storage key: SHA256(nullifierSetPrefix || nullifier)
+func nullifierKey(nullifier [32]byte) common.Hash {
+	h := sha256.New()
+	h.Write(nullifierSetPrefix[:])
+	h.Write(nullifier[:])
+	return common.BytesToHash(h.Sum(nil))
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2025, Lux Industries Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package zk
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/luxfi/geth/common"
+	"github.com/stretchr/testify/require"
+)
+
+// mockStateDB is an in-memory StateDB
+type mockStateDB struct {
+	storage map[common.Address]map[common.Hash]common.Hash
+	writes  int
+}
+
+func newMockStateDB() *mockStateDB {
+	return &mockStateDB{storage: make(map[common.Address]map[common.Hash]common.Hash)}
+}
+
+func (m *mockStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	return m.storage[addr][key]
+}
+
+func (m *mockStateDB) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	if m.storage[addr] == nil {
+		m.storage[addr] = make(map[common.Hash]common.Hash)
+	}
+	prev := m.storage[addr][key]
+	m.storage[addr][key] = value
+	m.writes++
+	return prev
+}
+
+func randomNullifiers(n int) [][32]byte {
+	nullifiers := make([][32]byte, n)
+	for i := range nullifiers {
+		rand.Read(nullifiers[i][:])
+	}
+	return nullifiers
+}
+
+// TestMarkSpentBatch tests marking a batch of fresh nullifiers
+func TestMarkSpentBatch(t *testing.T) {
+	stateDB := newMockStateDB()
+	nullifiers := randomNullifiers(4)
+
+	require.NoError(t, MarkSpentBatch(stateDB, nullifiers))
+	for _, nullifier := range nullifiers {
+		require.True(t, IsSpent(stateDB, nullifier))
+	}
+
+	// Spending any of them again fails
+	require.ErrorIs(t, MarkSpent(stateDB, nullifiers[2]), ErrNullifierSpent)
+
+	// An empty batch is a no-op
+	require.NoError(t, MarkSpentBatch(stateDB, nil))
+}
+
+// TestMarkSpentBatchAtomic tests that a batch with a spent nullifier marks none
+func TestMarkSpentBatchAtomic(t *testing.T) {
+	stateDB := newMockStateDB()
+	spent := randomNullifiers(1)[0]
+	require.NoError(t, MarkSpent(stateDB, spent))
+	writes := stateDB.writes
+
+	batch := randomNullifiers(3)
+	batch = append(batch, spent)
+	require.ErrorIs(t, MarkSpentBatch(stateDB, batch), ErrNullifierSpent)
+
+	require.Equal(t, writes, stateDB.writes)
+	for _, nullifier := range batch[:3] {
+		require.False(t, IsSpent(stateDB, nullifier))
+	}
+}
+
+// TestMarkSpentBatchInternalDuplicate tests that a batch cannot spend a
+// nullifier twice
+func TestMarkSpentBatchInternalDuplicate(t *testing.T) {
+	stateDB := newMockStateDB()
+
+	batch := randomNullifiers(3)
+	batch = append(batch, batch[1])
+	require.ErrorIs(t, MarkSpentBatch(stateDB, batch), ErrDuplicateNullifier)
+
+	require.Zero(t, stateDB.writes)
+	for _, nullifier := range batch {
+		require.False(t, IsSpent(stateDB, nullifier))
+	}
+}
+
+// TestDeriveNullifier pins the nullifier derivation spec and checks that a
+// note and the registry derive the same nullifier
+func TestDeriveNullifier(t *testing.T) {
+	// Golden vectors: (commitment, leafIndex, nullifierKey) -> nullifier
+	vectors := []struct {
+		commitment   uint64
+		leafIndex    uint64
+		nullifierKey uint64
+		want         string
+	}{
+		{12, 5, 11, "24ae8fbeb2d18fa7f404d18416de684b7c7af2c745df2623be6f71d69c975a56"},
+		{2, 3, 1, "2217b2961d96ae36f9f7701ccfaae198e00d7246805f514f8edb13f7f73699bc"},
+	}
+	for _, v := range vectors {
+		got := DeriveNullifier(goldenElement(v.commitment), v.leafIndex, goldenElement(v.nullifierKey))
+		require.Equal(t, v.want, hex.EncodeToString(got[:]))
+	}
+
+	// The spec is Poseidon2 over (nullifierKey, commitment, leafIndex)
+	commitment, nullifierKey := goldenElement(12), goldenElement(11)
+	want, err := NewPoseidon2Hasher().Hash(goldenConcat([][32]byte{nullifierKey, commitment, goldenElement(5)}))
+	require.NoError(t, err)
+	got := DeriveNullifier(commitment, 5, nullifierKey)
+	require.Equal(t, want, got)
+
+	// The hasher's NullifierHash follows the same spec
+	want, err = NewPoseidon2Hasher().NullifierHash(nullifierKey, commitment, 5)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	// Changing any input changes the nullifier
+	for _, other := range [][32]byte{
+		DeriveNullifier(goldenElement(13), 5, nullifierKey),
+		DeriveNullifier(commitment, 6, nullifierKey),
+		DeriveNullifier(commitment, 5, goldenElement(10)),
+	} {
+		require.NotEqual(t, got, other)
+	}
+
+	for _, scheme := range []SchemeType{SchemePoseidon2, SchemePedersen} {
+		note, err := CreateNote(NoteInput{
+			Amount:         big.NewInt(1_000_000),
+			AssetID:        goldenElement(3),
+			Owner:          common.HexToAddress("0xabcd"),
+			BlindingFactor: goldenElement(7),
+			SchemeType:     scheme,
+		})
+		require.NoError(t, err)
+		note.LeafIndex = 42
+
+		nullifier, err := note.Nullifier(nullifierKey)
+		require.NoError(t, err)
+		require.Equal(t, DeriveNullifier(note.Commitment, note.LeafIndex, nullifierKey), nullifier)
+
+		// The registry marks exactly the note's nullifier spent
+		stateDB := newMockStateDB()
+		marked, err := MarkNoteSpent(stateDB, note.Commitment, note.LeafIndex, nullifierKey)
+		require.NoError(t, err)
+		require.Equal(t, nullifier, marked)
+		require.True(t, IsSpent(stateDB, nullifier))
+
+		require.True(t, IsNoteSpent(stateDB, note.Commitment, note.LeafIndex, nullifierKey))
+
+		_, err = MarkNoteSpent(stateDB, note.Commitment, note.LeafIndex, nullifierKey)
+		require.ErrorIs(t, err, ErrNullifierSpent)
+	}
+}
@@ -7,10 +7,10 @@ import (
 	"crypto/sha256"
 	"errors"
 	"math/big"
+	"strconv"
 	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
-	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/luxfi/geth/common"
 )
@@ -29,7 +29,7 @@ var (
 // WARNING: Pedersen commitments are NOT post-quantum secure (discrete log)
 // Use Poseidon2 commitments for PQ security
 type PedersenCommitter struct {
-	// Generator points (from trusted setup or hash-to-curve)
+	// Generator points (see NewPedersenCommitter for the derivation)
 	G bn254.G1Affine // Base generator
 	H bn254.G1Affine // Blinding generator
 
@@ -43,7 +43,25 @@ type PedersenCommitter struct {
 	mu sync.RWMutex
 }
 
+// Nothing-up-my-sleeve inputs for the Pedersen generators.
+//
+// G is the standard BN254 G1 generator. H and the vector generators are
+// derived as HashToCurve(seed, PedersenDST), so anyone can recompute them
+// from these public strings.
+const (
+	PedersenDST           = "LUX-PEDERSEN-V01-CS01-with-BN254G1_XMD:SHA-256_SVDW_RO_"
+	PedersenHSeed         = "lux-pedersen-H"
+	PedersenGeneratorSeed = "lux-pedersen-G-"
+	NumPedersenVectorGens = 32
+)
+
 // NewPedersenCommitter creates a new Pedersen committer with default generators
+//
+// Binding relies on nobody knowing log_G(H). Because H is the output of a
+// hash-to-curve random oracle on a fixed public string, finding that
+// discrete log is as hard as solving DLP on BN254; no party (including the
+// authors) could have chosen H with a known relation to G. The same argument
+// covers the vector generators, each derived from its own index-tagged seed.
 func NewPedersenCommitter() *PedersenCommitter {
 	pc := &PedersenCommitter{}
 
@@ -51,19 +69,25 @@ func NewPedersenCommitter() *PedersenCommitter {
 	_, _, g1Gen, _ := bn254.Generators()
 	pc.G = g1Gen
 
-	// Derive H from G using hash-to-curve (nothing-up-my-sleeve)
-	// H = HashToCurve("Lux_Pedersen_H")
-	pc.H = hashToG1("Lux_Pedersen_H_Generator")
+	// H = hash_to_curve("lux-pedersen-H")
+	pc.H = hashToG1(PedersenHSeed)
 
 	// Pre-generate additional generators for vector commitments
-	pc.Generators = make([]bn254.G1Affine, 32)
-	for i := 0; i < 32; i++ {
-		pc.Generators[i] = hashToG1("Lux_Pedersen_Gen_" + string(rune('0'+i)))
+	pc.Generators = make([]bn254.G1Affine, NumPedersenVectorGens)
+	for i := 0; i < NumPedersenVectorGens; i++ {
+		pc.Generators[i] = hashToG1(PedersenGeneratorSeed + strconv.Itoa(i))
 	}
 
 	return pc
 }
 
+// BaseGenerators returns the base and blinding generators (G, H) so the
+// nothing-up-my-sleeve derivation can be checked independently. The vector
+// generators are in the Generators field.
+func (p *PedersenCommitter) BaseGenerators() (g, h bn254.G1Affine) {
+	return p.G, p.H
+}
+
 // Commit creates a Pedersen commitment: C = v*G + r*H
 // value: the value to commit to (as field element)
 // blindingFactor: random blinding factor
@@ -177,6 +201,28 @@ func (p *PedersenCommitter) Sub(c1, c2 [32]byte) ([32]byte, error) {
 	return compressG1WithCache(&diff), nil
 }
 
+// Neg negates a commitment homomorphically
+// -C = (-v)*G + (-r)*H
+func (p *PedersenCommitter) Neg(c [32]byte) ([32]byte, error) {
+	pt, err := decompressG1(c)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	var neg bn254.G1Affine
+	neg.Neg(&pt)
+
+	return compressG1WithCache(&neg), nil
+}
+
+// IsCommitmentToZero reports whether c opens to the value zero under
+// blindingFactor, i.e. C == r*H. A confidential transfer balances when the
+// difference of its output and input commitments passes this check with the
+// difference of their blinding factors.
+func (p *PedersenCommitter) IsCommitmentToZero(c, blindingFactor [32]byte) (bool, error) {
+	return p.Verify(c, [32]byte{}, blindingFactor)
+}
+
 // VectorCommit creates a vector Pedersen commitment
 // C = sum(v_i * G_i) + r * H
 func (p *PedersenCommitter) VectorCommit(values [][32]byte, blindingFactor [32]byte) ([32]byte, error) {
@@ -277,12 +323,12 @@ func (p *PedersenCommitter) RequiredGas(operation string, numElements int) uint6
 	switch operation {
 	case "commit":
 		// Single commitment: 2 scalar mults + 1 add
-		return 6000
+		return PedersenGas(1)
 	case "verify":
 		// Commitment + equality check
 		return 7000
-	case "add", "sub":
-		// Point addition/subtraction
+	case "add", "sub", "neg":
+		// Point addition/subtraction/negation
 		return 500
 	case "vector":
 		// Vector commitment: n scalar mults + 1 blinding
@@ -294,54 +340,14 @@ func (p *PedersenCommitter) RequiredGas(operation string, numElements int) uint6
 
 // Helper functions
 
-// hashToG1 creates a generator from a seed using hash-to-curve
+// hashToG1 derives a generator from a public seed via HashToCurve
 func hashToG1(seed string) bn254.G1Affine {
-	// Use hash-to-curve via repeated hashing until we find a valid point
-	// This is a simple try-and-increment approach
-	var point bn254.G1Affine
-
-	seedBytes := []byte(seed)
-	var counter byte = 0
-
-	for {
-		// Hash seed with counter
-		data := append(seedBytes, counter)
-		hash := sha256.Sum256(data)
-
-		// Try to create a point from the hash
-		var x fp.Element
-		x.SetBytes(hash[:])
-
-		// Try to find y for this x using curve equation y^2 = x^3 + 3
-		var x2, x3, rhs fp.Element
-		x2.Square(&x)
-		x3.Mul(&x2, &x)
-
-		var three fp.Element
-		three.SetInt64(3)
-		rhs.Add(&x3, &three)
-
-		// Try to compute square root
-		var y fp.Element
-		if y.Sqrt(&rhs) != nil {
-			// Found valid point
-			point.X = x
-			point.Y = y
-
-			if point.IsOnCurve() && !point.IsInfinity() {
-				return point
-			}
-		}
-		counter++
-		if counter == 0 {
-			// Overflow, shouldn't happen with good seeds
-			break
-		}
+	point, err := HashToCurve([]byte(seed), []byte(PedersenDST))
+	if err != nil {
+		// Only fails on an invalid DST, which is a fixed constant
+		panic("pedersen: hash to curve failed: " + err.Error())
 	}
-
-	// Fallback to base generator (shouldn't reach here)
-	_, _, g1, _ := bn254.Generators()
-	return g1
+	return point
 }
 
 // compressG1 compresses a G1 point to 64 bytes (uncompressed for simplicity)
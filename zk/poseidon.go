@@ -4,8 +4,13 @@
 package zk
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
+	"fmt"
+	"math"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
@@ -34,11 +39,15 @@ var (
 	ErrInvalidInputLength  = errors.New("invalid input length: must be multiple of 32 bytes")
 	ErrTooManyInputs       = errors.New("too many inputs: maximum 16 field elements")
 	ErrInvalidFieldElement = errors.New("invalid field element: exceeds BN254 scalar field")
+	ErrInvalidMerkleProof  = errors.New("invalid merkle proof encoding")
 )
 
 // Poseidon2Hasher provides Poseidon2 hash operations for ZK circuits
 // Poseidon2 is optimized for ZK proofs and is PQ-resistant (hash-based security)
 type Poseidon2Hasher struct {
+	// domainTag, if set, is a field element bound into every HashPair
+	domainTag *[32]byte
+
 	// Cache for frequently used hashes
 	cache    map[[32]byte][32]byte
 	cacheMu  sync.RWMutex
@@ -58,6 +67,25 @@ func NewPoseidon2Hasher() *Poseidon2Hasher {
 	}
 }
 
+// NewPoseidon2HasherForDomain creates a Poseidon2 hasher whose HashPair is
+// bound to domain, so Merkle trees with different domains (e.g. note
+// commitments and nullifiers) never share internal node hashes. Leaves are
+// not tagged; the domain separates every level above them.
+func NewPoseidon2HasherForDomain(domain string) *Poseidon2Hasher {
+	p := NewPoseidon2Hasher()
+	p.domainTag = domainTag(domain)
+	return p
+}
+
+// domainTag maps a domain name to a canonical BN254 field element
+func domainTag(domain string) *[32]byte {
+	digest := sha256.Sum256([]byte("lux/poseidon2/domain/" + domain))
+	var elem fr.Element
+	elem.SetBytes(digest[:])
+	tag := elem.Bytes()
+	return &tag
+}
+
 // Hash computes Poseidon2 hash of multiple field elements
 // Input: concatenated 32-byte field elements (1-16 elements)
 // Output: 32-byte hash
@@ -126,8 +154,16 @@ func (p *Poseidon2Hasher) Hash(input []byte) ([32]byte, error) {
 }
 
 // HashPair computes Poseidon2(left, right) - optimized for Merkle trees
+// A domain-bound hasher computes Poseidon2(domainTag, left, right) instead
 // Uses GPU-accelerated version when available
 func (p *Poseidon2Hasher) HashPair(left, right [32]byte) ([32]byte, error) {
+	if p.domainTag != nil {
+		input := make([]byte, 96)
+		copy(input[:32], p.domainTag[:])
+		copy(input[32:64], left[:])
+		copy(input[64:], right[:])
+		return p.Hash(input)
+	}
 	// Use GPU-accelerated version if available
 	if useGPU && gpuHashPairFunc != nil {
 		return gpuHashPairFunc(left, right)
@@ -313,16 +349,203 @@ func (p *Poseidon2Hasher) VerifyMerkleProof(
 	return current == root, nil
 }
 
+// LeafAt is a leaf together with its index in a Merkle tree
+type LeafAt struct {
+	Index uint64
+	Leaf  [32]byte
+}
+
+// MultiProof proves several leaves of one Merkle tree at once. Nodes holds
+// only the siblings that cannot be derived from the proven leaves, so an
+// internal node shared by two paths appears once. They are ordered level by
+// level from the leaves up, and left to right within a level.
+type MultiProof struct {
+	Depth int
+	Nodes [][32]byte
+}
+
+// MerkleMultiProof generates a multiproof for the leaves at the given indices
+func (p *Poseidon2Hasher) MerkleMultiProof(leaves [][32]byte, indices []int) (MultiProof, error) {
+	if len(leaves) == 0 || len(indices) == 0 {
+		return MultiProof{}, errors.New("invalid index")
+	}
+
+	// Pad to power of 2
+	n := 1
+	for n < len(leaves) {
+		n *= 2
+	}
+
+	current := make([][32]byte, n)
+	copy(current, leaves)
+
+	known := make(map[int]bool, len(indices))
+	for _, idx := range indices {
+		if idx < 0 || idx >= len(leaves) {
+			return MultiProof{}, errors.New("invalid index")
+		}
+		known[idx] = true
+	}
+
+	var proof MultiProof
+	for len(current) > 1 {
+		level := make([]int, 0, len(known))
+		for idx := range known {
+			level = append(level, idx)
+		}
+		sort.Ints(level)
+
+		parents := make(map[int]bool, len(level))
+		for _, idx := range level {
+			if !known[idx^1] {
+				proof.Nodes = append(proof.Nodes, current[idx^1])
+			}
+			parents[idx/2] = true
+		}
+
+		next := make([][32]byte, len(current)/2)
+		for i := 0; i < len(next); i++ {
+			hash, err := p.HashPair(current[i*2], current[i*2+1])
+			if err != nil {
+				return MultiProof{}, err
+			}
+			next[i] = hash
+		}
+		current = next
+		known = parents
+		proof.Depth++
+	}
+
+	return proof, nil
+}
+
+// VerifyMultiProof verifies a batch of leaves against root with a single
+// multiproof. Leaves are combined level by level, so each internal node is
+// hashed once however many of the proven paths pass through it. It returns
+// ErrInvalidMerkleProof if the proof does not have exactly the nodes the
+// leaf indices call for.
+func (p *Poseidon2Hasher) VerifyMultiProof(leaves []LeafAt, proof MultiProof, root [32]byte) (bool, error) {
+	if len(leaves) == 0 || proof.Depth < 0 || proof.Depth > 64 {
+		return false, ErrInvalidMerkleProof
+	}
+
+	current := make([]LeafAt, len(leaves))
+	copy(current, leaves)
+	sort.Slice(current, func(i, j int) bool { return current[i].Index < current[j].Index })
+	for i := range current {
+		if proof.Depth < 64 && current[i].Index>>proof.Depth != 0 {
+			return false, ErrInvalidMerkleProof
+		}
+		if i > 0 && current[i].Index == current[i-1].Index {
+			return false, ErrInvalidMerkleProof
+		}
+	}
+
+	nodes := proof.Nodes
+	for level := 0; level < proof.Depth; level++ {
+		next := current[:0]
+		for i := 0; i < len(current); i++ {
+			node := current[i]
+			var sibling [32]byte
+			if i+1 < len(current) && current[i+1].Index == node.Index^1 {
+				sibling = current[i+1].Leaf
+				i++
+			} else {
+				if len(nodes) == 0 {
+					return false, ErrInvalidMerkleProof
+				}
+				sibling = nodes[0]
+				nodes = nodes[1:]
+			}
+
+			left, right := node.Leaf, sibling
+			if node.Index%2 == 1 {
+				left, right = sibling, node.Leaf
+			}
+			hash, err := p.HashPair(left, right)
+			if err != nil {
+				return false, err
+			}
+			next = append(next, LeafAt{Index: node.Index / 2, Leaf: hash})
+		}
+		current = next
+	}
+
+	if len(nodes) != 0 || len(current) != 1 {
+		return false, ErrInvalidMerkleProof
+	}
+	return current[0].Leaf == root, nil
+}
+
+// EncodeMerkleProof serializes a Merkle proof for the precompile boundary.
+//
+// Layout:
+//
+//	[0:2]     depth (uint16 big-endian)
+//	[2:2+32n] siblings, leaf to root
+//	[...]     direction bits, ceil(n/8) bytes, bit i (LSB first) set if isLeft[i]
+//
+// It returns ErrInvalidMerkleProof if siblings and isLeft differ in length
+// or the depth does not fit the uint16 prefix.
+func EncodeMerkleProof(siblings [][32]byte, isLeft []bool) ([]byte, error) {
+	depth := len(siblings)
+	if len(isLeft) != depth {
+		return nil, fmt.Errorf("%w: %d siblings, %d directions", ErrInvalidMerkleProof, depth, len(isLeft))
+	}
+	if depth > math.MaxUint16 {
+		return nil, fmt.Errorf("%w: depth %d exceeds %d", ErrInvalidMerkleProof, depth, math.MaxUint16)
+	}
+	out := make([]byte, 2, 2+depth*32+(depth+7)/8)
+	binary.BigEndian.PutUint16(out, uint16(depth))
+	for i := range siblings {
+		out = append(out, siblings[i][:]...)
+	}
+
+	bits := make([]byte, (depth+7)/8)
+	for i := range isLeft {
+		if isLeft[i] {
+			bits[i/8] |= 1 << (i % 8)
+		}
+	}
+	return append(out, bits...), nil
+}
+
+// DecodeMerkleProof parses the output of EncodeMerkleProof. The input must
+// be exactly the encoded length and unused direction bits must be zero.
+func DecodeMerkleProof(data []byte) ([][32]byte, []bool, error) {
+	if len(data) < 2 {
+		return nil, nil, ErrInvalidMerkleProof
+	}
+	depth := int(binary.BigEndian.Uint16(data))
+	if len(data) != 2+depth*32+(depth+7)/8 {
+		return nil, nil, ErrInvalidMerkleProof
+	}
+
+	siblings := make([][32]byte, depth)
+	for i := range siblings {
+		copy(siblings[i][:], data[2+i*32:])
+	}
+
+	bits := data[2+depth*32:]
+	if depth%8 != 0 && bits[len(bits)-1]>>(depth%8) != 0 {
+		return nil, nil, ErrInvalidMerkleProof
+	}
+	isLeft := make([]bool, depth)
+	for i := range isLeft {
+		isLeft[i] = bits[i/8]&(1<<(i%8)) != 0
+	}
+
+	return siblings, isLeft, nil
+}
+
 // RequiredGas calculates gas cost for Poseidon2 hash
 // Base cost + per-element cost
 func (p *Poseidon2Hasher) RequiredGas(inputLen int) uint64 {
 	if inputLen == 0 || inputLen%32 != 0 {
 		return 0
 	}
-	numElements := uint64(inputLen / 32)
-	// Base: 500 gas + 100 per element
 	// This is ~3-5x cheaper than keccak256 for small inputs in ZK context
-	return 500 + numElements*100
+	return Poseidon2Gas(inputLen / 32)
 }
 
 // computeCacheKey creates a cache key from input
@@ -332,11 +555,15 @@ func computeCacheKey(input []byte) [32]byte {
 		copy(key[:], input)
 		return key
 	}
-	// For longer inputs, use first 32 bytes XOR'd with length
+	// Longer inputs are keyed by a digest of the whole input; keying on a
+	// prefix would let pairs sharing a left child (or a domain tag) collide
+	var prefix [2]byte
+	binary.BigEndian.PutUint16(prefix[:], uint16(len(input)))
+	h := sha256.New()
+	h.Write(prefix[:])
+	h.Write(input)
 	var key [32]byte
-	copy(key[:], input[:32])
-	key[0] ^= byte(len(input) >> 8)
-	key[1] ^= byte(len(input))
+	h.Sum(key[:0])
 	return key
 }
 
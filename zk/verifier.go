@@ -10,6 +10,8 @@ import (
 	"sync"
 	"time"
 
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	blsfr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/luxfi/crypto/bn256"
 	"github.com/luxfi/crypto/kzg4844"
 	"github.com/luxfi/geth/common"
@@ -36,6 +38,9 @@ type ZKVerifier struct {
 	// Confidential pools
 	Pools map[[32]byte]*ConfidentialPool
 
+	// Shielded asset IDs of pool tokens
+	Assets *AssetRegistry
+
 	// KZG trusted setup
 	KZGSetup *KZGSetup
 
@@ -65,6 +70,7 @@ func NewZKVerifier() *ZKVerifier {
 		Rollups:       make(map[[32]byte]*RollupConfig),
 		RollupStates:  make(map[[32]byte]*RollupState),
 		Pools:         make(map[[32]byte]*ConfidentialPool),
+		Assets:        NewAssetRegistry(),
 	}
 }
 
@@ -204,6 +210,37 @@ func (zv *ZKVerifier) VerifyKZG(
 	return valid, nil
 }
 
+// VerifyMultiOpen verifies several KZG openings, possibly of different
+// polynomials at different points, with a single pairing check.
+//
+// proof is the aggregated opening proof: the concatenation of one 48-byte
+// compressed witness per (commitment, point, value) triple, in order.
+func (zv *ZKVerifier) VerifyMultiOpen(
+	commitments [][]byte,
+	points []*big.Int,
+	values []*big.Int,
+	proof []byte,
+) (bool, error) {
+	zv.mu.RLock()
+	defer zv.mu.RUnlock()
+
+	if zv.KZGSetup == nil {
+		return false, errors.New("KZG setup not initialized")
+	}
+
+	n := len(commitments)
+	if n == 0 || len(points) != n || len(values) != n {
+		return false, ErrInvalidPublicInputs
+	}
+	if len(proof) != n*kzgG1CompressedSize {
+		return false, ErrInvalidProof
+	}
+
+	valid := zv.kzgMultiOpenCheck(commitments, points, values, proof)
+
+	return valid, nil
+}
+
 // VerifyRangeProof verifies that a committed value is within a range
 func (zv *ZKVerifier) VerifyRangeProof(
 	commitment []byte,
@@ -448,9 +485,26 @@ func (zv *ZKVerifier) CreateConfidentialPool(
 	}
 
 	zv.Pools[poolID] = pool
+	zv.Assets.RegisterAsset(token)
 	return poolID, nil
 }
 
+// ValidateWithdrawal checks that note can be withdrawn from the pool: the
+// note must be a note of the pool's token (see AssetRegistry.ValidateNoteAsset)
+func (zv *ZKVerifier) ValidateWithdrawal(poolID [32]byte, note *Note) error {
+	zv.mu.RLock()
+	pool := zv.Pools[poolID]
+	zv.mu.RUnlock()
+
+	if pool == nil {
+		return ErrPoolNotFound
+	}
+	if !pool.Enabled {
+		return ErrPoolDisabled
+	}
+	return zv.Assets.ValidateNoteAsset(note, pool.Token)
+}
+
 // Helper functions
 
 // groth16PairingCheck implements the Groth16 pairing verification equation:
@@ -803,6 +857,114 @@ func (zv *ZKVerifier) kzgPointEvaluation(
 	return err == nil
 }
 
+// kzgG1CompressedSize is the size of a compressed BLS12-381 G1 point.
+const kzgG1CompressedSize = 48
+
+// kzgMultiOpenCheck batches n KZG openings into one pairing equation.
+//
+// Each opening i satisfies e(Wᵢ, [τ - zᵢ]G2) = e(Cᵢ - [yᵢ]G1, G2), which
+// rearranges to e(Wᵢ, [τ]G2) = e(Cᵢ - [yᵢ]G1 + zᵢ·Wᵢ, G2). Combining the
+// openings with powers of a Fiat-Shamir challenge r gives:
+//
+//	e(∑ rⁱ·Wᵢ, [τ]G2) · e(-∑ rⁱ·(Cᵢ - [yᵢ]G1 + zᵢ·Wᵢ), G2) = 1
+//
+// A single invalid opening makes the check fail except with negligible
+// probability, since r is bound to every commitment, point, value and witness.
+func (zv *ZKVerifier) kzgMultiOpenCheck(
+	commitments [][]byte,
+	points []*big.Int,
+	values []*big.Int,
+	proof []byte,
+) bool {
+	setup := zv.KZGSetup
+	if len(setup.G1Powers) < 1 || len(setup.G2Powers) < 2 {
+		return false
+	}
+
+	var g1 bls12381.G1Affine
+	if _, err := g1.SetBytes(setup.G1Powers[0]); err != nil {
+		return false
+	}
+	var g2, tauG2 bls12381.G2Affine
+	if _, err := g2.SetBytes(setup.G2Powers[0]); err != nil {
+		return false
+	}
+	if _, err := tauG2.SetBytes(setup.G2Powers[1]); err != nil {
+		return false
+	}
+
+	// Derive the batching challenge from the full statement
+	h := sha256.New()
+	h.Write([]byte("lux-kzg-multi-open"))
+	for i := range commitments {
+		if len(commitments[i]) != kzgG1CompressedSize {
+			return false
+		}
+		if points[i] == nil || values[i] == nil ||
+			points[i].Sign() < 0 || values[i].Sign() < 0 ||
+			points[i].Cmp(blsfr.Modulus()) >= 0 || values[i].Cmp(blsfr.Modulus()) >= 0 {
+			return false
+		}
+		var zBuf, yBuf [32]byte
+		points[i].FillBytes(zBuf[:])
+		values[i].FillBytes(yBuf[:])
+		h.Write(commitments[i])
+		h.Write(zBuf[:])
+		h.Write(yBuf[:])
+	}
+	h.Write(proof)
+	var r blsfr.Element
+	r.SetBytes(h.Sum(nil))
+
+	var g1Jac bls12381.G1Jac
+	g1Jac.FromAffine(&g1)
+
+	var lhs, rhs bls12381.G1Jac
+	var rPow blsfr.Element
+	rPow.SetOne()
+	for i := range commitments {
+		var c, w bls12381.G1Affine
+		if _, err := c.SetBytes(commitments[i]); err != nil {
+			return false
+		}
+		witness := proof[i*kzgG1CompressedSize : (i+1)*kzgG1CompressedSize]
+		if _, err := w.SetBytes(witness); err != nil {
+			return false
+		}
+
+		var wJac bls12381.G1Jac
+		wJac.FromAffine(&w)
+
+		// term = Cᵢ - [yᵢ]G1 + zᵢ·Wᵢ
+		var term, yG, zW bls12381.G1Jac
+		term.FromAffine(&c)
+		yG.ScalarMultiplication(&g1Jac, values[i])
+		term.SubAssign(&yG)
+		zW.ScalarMultiplication(&wJac, points[i])
+		term.AddAssign(&zW)
+
+		rBig := rPow.BigInt(new(big.Int))
+		var rW bls12381.G1Jac
+		rW.ScalarMultiplication(&wJac, rBig)
+		lhs.AddAssign(&rW)
+		term.ScalarMultiplication(&term, rBig)
+		rhs.AddAssign(&term)
+
+		rPow.Mul(&rPow, &r)
+	}
+
+	var lhsAff, rhsAff bls12381.G1Affine
+	lhsAff.FromJacobian(&lhs)
+	rhsAff.FromJacobian(&rhs)
+	rhsAff.Neg(&rhsAff)
+
+	ok, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{lhsAff, rhsAff},
+		[]bls12381.G2Affine{tauG2, g2},
+	)
+	return err == nil && ok
+}
+
 func (zv *ZKVerifier) bulletproofRangeVerify(
 	commitment []byte,
 	rangeProof []byte,
@@ -8,6 +8,8 @@ import (
 	"math/big"
 	"testing"
 
+	blsfr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	blskzg "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
 	"github.com/luxfi/crypto/bn256"
 	"github.com/luxfi/geth/common"
 )
@@ -930,3 +932,124 @@ func TestPLONKProofSizeValidation(t *testing.T) {
 		t.Error("Expected undersized proof to fail verification")
 	}
 }
+
+// newTestKZGSetup builds a KZG setup from a known toxic-waste scalar.
+// Test-only: a real deployment loads a ceremony transcript.
+func newTestKZGSetup(t *testing.T) (*KZGSetup, *blskzg.SRS) {
+	t.Helper()
+
+	srs, err := blskzg.NewSRS(16, big.NewInt(12345))
+	if err != nil {
+		t.Fatalf("NewSRS failed: %v", err)
+	}
+
+	g1 := srs.Vk.G1.Bytes()
+	g2 := srs.Vk.G2[0].Bytes()
+	tauG2 := srs.Vk.G2[1].Bytes()
+
+	return &KZGSetup{
+		G1Powers:  [][]byte{g1[:]},
+		G2Powers:  [][]byte{g2[:], tauG2[:]},
+		MaxDegree: 16,
+	}, srs
+}
+
+// openTestPolynomials commits to three polynomials and opens each at its own point
+func openTestPolynomials(t *testing.T, srs *blskzg.SRS) ([][]byte, []*big.Int, []*big.Int, []byte) {
+	t.Helper()
+
+	polys := [][]blsfr.Element{
+		make([]blsfr.Element, 8),
+		make([]blsfr.Element, 8),
+		make([]blsfr.Element, 8),
+	}
+	for i := range polys {
+		for j := range polys[i] {
+			polys[i][j].SetUint64(uint64(i*10 + j + 1))
+		}
+	}
+
+	var commitments [][]byte
+	var points, values []*big.Int
+	var proof []byte
+	for i, p := range polys {
+		digest, err := blskzg.Commit(p, srs.Pk)
+		if err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+
+		var z blsfr.Element
+		z.SetUint64(uint64(100 + i*7))
+		opening, err := blskzg.Open(p, z, srs.Pk)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+
+		c := digest.Bytes()
+		w := opening.H.Bytes()
+		commitments = append(commitments, c[:])
+		points = append(points, z.BigInt(new(big.Int)))
+		values = append(values, opening.ClaimedValue.BigInt(new(big.Int)))
+		proof = append(proof, w[:]...)
+	}
+
+	return commitments, points, values, proof
+}
+
+// TestVerifyMultiOpen tests aggregated verification of three openings at three points
+func TestVerifyMultiOpen(t *testing.T) {
+	zv := NewZKVerifier()
+	setup, srs := newTestKZGSetup(t)
+	zv.KZGSetup = setup
+
+	commitments, points, values, proof := openTestPolynomials(t, srs)
+
+	valid, err := zv.VerifyMultiOpen(commitments, points, values, proof)
+	if err != nil {
+		t.Fatalf("VerifyMultiOpen failed: %v", err)
+	}
+	if !valid {
+		t.Fatal("Expected valid multi-opening")
+	}
+
+	// Tampering with a single claimed value must fail the whole batch
+	tampered := make([]*big.Int, len(values))
+	copy(tampered, values)
+	tampered[1] = new(big.Int).Add(values[1], big.NewInt(1))
+
+	valid, err = zv.VerifyMultiOpen(commitments, points, tampered, proof)
+	if err != nil {
+		t.Fatalf("VerifyMultiOpen failed: %v", err)
+	}
+	if valid {
+		t.Error("Expected tampered value to fail the batch")
+	}
+
+	// Swapping two evaluation points must also fail
+	swapped := []*big.Int{points[1], points[0], points[2]}
+	valid, _ = zv.VerifyMultiOpen(commitments, swapped, values, proof)
+	if valid {
+		t.Error("Expected swapped points to fail the batch")
+	}
+}
+
+// TestVerifyMultiOpenInvalidInput tests argument validation for multi-open
+func TestVerifyMultiOpenInvalidInput(t *testing.T) {
+	zv := NewZKVerifier()
+
+	_, err := zv.VerifyMultiOpen([][]byte{make([]byte, 48)}, []*big.Int{big.NewInt(1)}, []*big.Int{big.NewInt(2)}, make([]byte, 48))
+	if err == nil {
+		t.Error("Expected error for uninitialized KZG setup")
+	}
+
+	setup, srs := newTestKZGSetup(t)
+	zv.KZGSetup = setup
+	commitments, points, values, proof := openTestPolynomials(t, srs)
+
+	if _, err := zv.VerifyMultiOpen(commitments, points[:2], values, proof); err != ErrInvalidPublicInputs {
+		t.Errorf("Expected ErrInvalidPublicInputs, got %v", err)
+	}
+	if _, err := zv.VerifyMultiOpen(commitments, points, values, proof[:96]); err != ErrInvalidProof {
+		t.Errorf("Expected ErrInvalidProof, got %v", err)
+	}
+}